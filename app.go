@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"cc-forwarder/config"
+	"cc-forwarder/internal/capture"
 	"cc-forwarder/internal/endpoint"
 	"cc-forwarder/internal/events"
 	"cc-forwarder/internal/logging"
@@ -22,6 +23,7 @@ import (
 	"cc-forwarder/internal/proxy"
 	"cc-forwarder/internal/service"
 	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/tracing"
 	"cc-forwarder/internal/tracking"
 	"cc-forwarder/internal/transport"
 	"cc-forwarder/internal/utils"
@@ -40,20 +42,50 @@ type App struct {
 	configWatcher        *config.ConfigWatcher
 	logger               *slog.Logger
 	endpointManager      *endpoint.Manager
-	eventBus             events.EventBus // 接口类型，不是指针
+	eventBus             events.EventBus   // 接口类型，不是指针
+	eventStream          *events.StreamHub // /events SSE 事件流
 	usageTracker         *tracking.UsageTracker
 	proxyHandler         *proxy.Handler
 	loggingMiddleware    *middleware.LoggingMiddleware
 	monitoringMiddleware *middleware.MonitoringMiddleware
 	authMiddleware       *middleware.AuthMiddleware
+	tracingShutdown      func(context.Context) error // 刷新并关闭链路追踪导出器
 
 	// v5.0+ 端点存储 (SQLite)
 	endpointStore   store.EndpointStore      // 端点数据持久化
 	endpointService *service.EndpointService // 端点业务服务
 
 	// v5.0+ 模型定价存储 (SQLite)
-	modelPricingStore   store.ModelPricingStore      // 模型定价数据持久化
-	modelPricingService *service.ModelPricingService // 模型定价业务服务
+	modelPricingStore   store.ModelPricingStore       // 模型定价数据持久化
+	modelPricingService *service.ModelPricingService  // 模型定价业务服务
+	costRecompute       *service.CostRecomputeService // 定价修正后的后台成本重算
+
+	// 客户端 Key 存储 (SQLite)
+	clientKeyStore   store.ClientKeyStore      // 客户端 Key 数据持久化
+	clientKeyService *service.ClientKeyService // 客户端 Key 业务服务
+
+	// 预算存储 (SQLite)
+	budgetStore   store.BudgetStore      // 预算数据持久化
+	budgetService *service.BudgetService // 预算统计与执行服务
+
+	// 模型路由规则存储 (SQLite)
+	routingRuleStore   store.RoutingRuleStore      // 路由规则数据持久化
+	routingRuleService *service.RoutingRuleService // 路由规则服务（端点选择时匹配）
+
+	// 多 Key 轮换状态存储 (SQLite)
+	keyStateStore store.KeyStateStore // Key 冷却/失效状态持久化
+	keyStateMu    sync.Mutex          // 串行化 Key 状态保存
+
+	// 响应缓存存储 (SQLite)
+	responseCacheStore   store.ResponseCacheStore      // 缓存响应持久化
+	responseCacheService *service.ResponseCacheService // 响应缓存服务（端点选择前查找）
+
+	// Webhook 存储 (SQLite)
+	webhookStore   store.WebhookStore      // Webhook 配置和投递记录持久化
+	webhookService *service.WebhookService // 运维事件外发通知服务
+
+	// 请求抓取（JSON 文件）
+	captureRecorder *capture.Recorder // 记录转发请求和原始响应，用于重放
 
 	// v5.1+ 系统设置存储 (SQLite)
 	settingsStore   store.SettingsStore      // 设置数据持久化
@@ -71,6 +103,9 @@ type App struct {
 	mu        sync.RWMutex
 	isRunning bool
 
+	// 无界面模式（--headless）：不启动 Wails 窗口，也不向前端推送事件
+	headless bool
+
 	// 日志处理器（用于查询和广播）
 	logHandler *logging.BroadcastHandler
 	logEmitter *logging.EventEmitter
@@ -101,6 +136,9 @@ func (a *App) startup(ctx context.Context) {
 		"version", Version,
 		"config_file", a.configPath)
 
+	// 3.5 初始化链路追踪（OTLP 导出）
+	a.setupTracing()
+
 	// 4. 初始化事件总线
 	a.setupEventBus()
 
@@ -137,6 +175,11 @@ func (a *App) startup(ctx context.Context) {
 		a.emitEndpointUpdate()
 	})
 
+	// least_outstanding 策略使用热池中的在途请求数
+	if a.usageTracker != nil {
+		a.endpointManager.SetInFlightCounter(a.usageTracker)
+	}
+
 	// 7. 初始化端点存储 (v5.0+ SQLite, 需要在创建 Manager 之后)
 	// 从数据库同步端点到 Manager
 	if a.config.EndpointsStorage.Type == "sqlite" {
@@ -146,9 +189,27 @@ func (a *App) startup(ctx context.Context) {
 	// 7.5 初始化模型定价存储 (v5.0+ SQLite)
 	a.setupModelPricingStore()
 
-	// 7.6 同步端点倍率到 UsageTracker（用于成本计算）
+	// 7.6 初始化客户端 Key 存储 (SQLite)
+	a.setupClientKeyStore()
+
+	// 7.7 同步端点倍率到 UsageTracker（用于成本计算）
 	a.syncEndpointMultipliersToTracker(ctx)
 
+	// 7.8 初始化预算存储（需要在启动端点管理器之前设置端点过滤）
+	a.setupBudgetStore()
+
+	// 7.85 初始化模型路由规则存储（需要在启动端点管理器之前设置）
+	a.setupRoutingRuleStore()
+
+	// 7.9 恢复多 Key 轮换状态（冷却/失效状态跨重启保留）
+	a.setupKeyStateStore()
+
+	// 7.95 初始化响应缓存存储
+	a.setupResponseCacheStore()
+
+	// 7.97 初始化 Webhook 存储（订阅事件总线的运维事件）
+	a.setupWebhookStore()
+
 	// 8. 启动端点管理器（此时端点已从数据库加载完成）
 	a.endpointManager.Start()
 
@@ -185,7 +246,10 @@ func (a *App) shutdown(ctx context.Context) {
 
 	a.logger.Info("🛑 正在关闭 CC-Forwarder...")
 
-	// 1. 停止接收新请求
+	// 1. 停止接收新请求（先关闭事件流长连接，否则会阻塞服务器关闭）
+	if a.eventStream != nil {
+		a.eventStream.Stop()
+	}
 	if a.proxyServer != nil {
 		shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
@@ -194,6 +258,33 @@ func (a *App) shutdown(ctx context.Context) {
 		}
 	}
 
+	// 1.5 导出剩余的链路追踪 span
+	if a.tracingShutdown != nil {
+		tracingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := a.tracingShutdown(tracingCtx); err != nil {
+			a.logger.Error("链路追踪关闭失败", "error", err)
+		}
+	}
+
+	// 2. 停止预算刷新（依赖使用追踪器的数据库）
+	if a.budgetService != nil {
+		a.budgetService.Stop()
+	}
+
+	// 2. 停止成本重算（更新追踪器数据库中的请求成本）
+	if a.costRecompute != nil {
+		a.costRecompute.Stop()
+	}
+
+	// 2. 停止 Webhook 投递（投递记录写入追踪器的数据库）
+	if a.webhookService != nil {
+		a.webhookService.Stop()
+	}
+
+	// 2. 保存多 Key 轮换状态（使用追踪器的数据库）
+	a.saveKeyStates()
+
 	// 2. 关闭使用追踪 (flush 数据库)
 	if a.usageTracker != nil {
 		if err := a.usageTracker.Close(); err != nil {
@@ -206,7 +297,10 @@ func (a *App) shutdown(ctx context.Context) {
 		a.endpointManager.Stop()
 	}
 
-	// 4. 关闭事件总线
+	// 4. 取消监控订阅并关闭事件总线
+	if a.monitoringMiddleware != nil {
+		a.monitoringMiddleware.UnsubscribeEndpointEvents()
+	}
 	if a.eventBus != nil {
 		if err := a.eventBus.Stop(); err != nil {
 			a.logger.Error("事件总线关闭失败", "error", err)
@@ -255,23 +349,8 @@ func (a *App) loadConfig() {
 			"logs", utils.GetLogDir())
 	}
 
-	// 直接从嵌入的配置加载（不写文件）
-	tempLogger.Info("📝 从嵌入配置加载")
-
-	// 将嵌入的配置写入临时文件进行解析
-	tmpConfigPath := filepath.Join(os.TempDir(), "cc-forwarder-config.yaml")
-
-	// 先修改配置内容，替换路径为用户目录
-	configContent := string(defaultConfigContent)
-	// 注意：不修改配置内容，而是加载后再覆盖路径
-
-	if err := os.WriteFile(tmpConfigPath, []byte(configContent), 0644); err != nil {
-		panic(fmt.Sprintf("无法创建临时配置文件: %v", err))
-	}
-	defer os.Remove(tmpConfigPath)
-
 	// 创建配置监听器（此时会调用 SetDefaults 设置默认路径）
-	configWatcher, err := config.NewConfigWatcher(tmpConfigPath, tempLogger)
+	configWatcher, configPath, err := a.openConfigWatcher(tempLogger)
 	if err != nil {
 		panic(fmt.Sprintf("无法加载配置: %v", err))
 	}
@@ -280,13 +359,7 @@ func (a *App) loadConfig() {
 	cfg := configWatcher.GetConfig()
 
 	// ⚠️ 关键：立即覆盖所有路径为用户目录（在任何组件初始化之前）
-	cfg.Logging.FilePath = filepath.Join(utils.GetLogDir(), "app.log")
-	cfg.UsageTracking.DatabasePath = filepath.Join(utils.GetDataDir(), "usage.db")
-
-	// 同时设置 Database 配置（如果存在）
-	if cfg.UsageTracking.Database != nil {
-		// Database 配置中没有 DatabasePath 字段，UsageTracking.DatabasePath 是统一路径
-	}
+	applyAppDirPaths(cfg)
 
 	a.config = cfg
 
@@ -295,7 +368,42 @@ func (a *App) loadConfig() {
 		"db_path", a.config.UsageTracking.DatabasePath,
 		"appdir", utils.GetAppDataDir())
 
-	a.configPath = tmpConfigPath
+	a.configPath = configPath
+}
+
+// openConfigWatcher 创建配置监听器，返回实际使用的配置文件路径
+// 无界面模式下优先使用 --config 指定的文件（支持文件监听和 SIGHUP 重载），
+// 其余情况从嵌入的默认配置加载
+func (a *App) openConfigWatcher(tempLogger *slog.Logger) (*config.ConfigWatcher, string, error) {
+	if a.headless && a.configPath != "" {
+		if _, err := os.Stat(a.configPath); err == nil {
+			tempLogger.Info("📝 从配置文件加载", "path", a.configPath)
+			configWatcher, err := config.NewConfigWatcher(a.configPath, tempLogger)
+			return configWatcher, a.configPath, err
+		}
+		tempLogger.Warn("⚠️ 配置文件不存在，回退到嵌入配置", "path", a.configPath)
+	}
+
+	// 直接从嵌入的配置加载（不写文件）
+	tempLogger.Info("📝 从嵌入配置加载")
+
+	// 将嵌入的配置写入临时文件进行解析
+	tmpConfigPath := filepath.Join(os.TempDir(), "cc-forwarder-config.yaml")
+
+	// 注意：不修改配置内容，而是加载后再覆盖路径
+	if err := os.WriteFile(tmpConfigPath, defaultConfigContent, 0644); err != nil {
+		panic(fmt.Sprintf("无法创建临时配置文件: %v", err))
+	}
+	defer os.Remove(tmpConfigPath)
+
+	configWatcher, err := config.NewConfigWatcher(tmpConfigPath, tempLogger)
+	return configWatcher, tmpConfigPath, err
+}
+
+// applyAppDirPaths 将日志和数据库路径覆盖为用户应用目录
+func applyAppDirPaths(cfg *config.Config) {
+	cfg.Logging.FilePath = filepath.Join(utils.GetLogDir(), "app.log")
+	cfg.UsageTracking.DatabasePath = filepath.Join(utils.GetDataDir(), "usage.db")
 }
 
 // setupLogger 设置日志
@@ -322,6 +430,10 @@ func (a *App) setupEventBus() {
 	if err := a.eventBus.Start(); err != nil {
 		a.logger.Error("事件总线启动失败", "error", err)
 	}
+
+	// 对外事件流（经 FilterManager 过滤限流，供外部看板订阅）
+	a.eventStream = events.NewStreamHub(a.eventBus, events.NewFilterManager(a.logger), 0)
+	a.eventStream.Start()
 }
 
 // setupEndpointStore 设置端点存储 (v5.0+ SQLite)
@@ -397,12 +509,268 @@ func (a *App) setupModelPricingStore() {
 		a.logger.Warn("⚠️ 加载模型定价缓存失败", "error", err)
 	}
 
+	// 为启用定价历史前的模型补充基线版本
+	if _, err := a.modelPricingService.EnsureBaselineVersions(ctx); err != nil {
+		a.logger.Warn("⚠️ 补充基线定价版本失败", "error", err)
+	}
+
 	// 同步定价到 UsageTracker（用于成本计算）
 	a.syncPricingToTracker(ctx)
 
+	// 修正定价或端点倍率后按时间范围重算历史成本
+	a.costRecompute = service.NewCostRecomputeService(a.usageTracker)
+	a.costRecompute.SetOnProgress(a.emitCostRecompute)
+
 	a.logger.Info("✅ 模型定价存储已启用 (SQLite)", "count", count)
 }
 
+// setupClientKeyStore 设置客户端 Key 存储 (SQLite)
+func (a *App) setupClientKeyStore() {
+	// 使用 usageTracker 的数据库连接
+	if a.usageTracker == nil {
+		a.logger.Debug("客户端 Key 存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (客户端 Key)")
+		return
+	}
+
+	a.clientKeyStore = store.NewSQLiteClientKeyStore(db)
+	a.clientKeyService = service.NewClientKeyService(a.clientKeyStore)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.clientKeyService.LoadCache(ctx); err != nil {
+		a.logger.Warn("⚠️ 加载客户端 Key 缓存失败", "error", err)
+		return
+	}
+
+	a.logger.Info("✅ 客户端 Key 存储已启用 (SQLite)")
+}
+
+// setupBudgetStore 设置预算存储 (SQLite)
+func (a *App) setupBudgetStore() {
+	// 使用 usageTracker 的数据库连接（同时作为消费数据来源）
+	if a.usageTracker == nil {
+		a.logger.Debug("预算存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (预算)")
+		return
+	}
+
+	a.budgetStore = store.NewSQLiteBudgetStore(db)
+	a.budgetService = service.NewBudgetService(a.budgetStore, a.usageTracker)
+	a.budgetService.SetEventBus(a.eventBus)
+	if a.config.Timezone != "" {
+		if loc, err := time.LoadLocation(a.config.Timezone); err == nil {
+			a.budgetService.SetLocation(loc)
+		}
+	}
+	a.budgetService.Configure(a.config.Budget.Enabled, a.config.Budget.RefreshInterval)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.budgetService.LoadCache(ctx); err != nil {
+		a.logger.Warn("⚠️ 加载预算缓存失败", "error", err)
+	}
+	a.budgetService.Refresh(ctx)
+	a.budgetService.Start()
+
+	// 超过硬限额的端点/渠道在选择时跳过
+	if a.endpointManager != nil {
+		a.endpointManager.SetEndpointGate(a.budgetService)
+	}
+
+	a.logger.Info("✅ 预算存储已启用 (SQLite)", "enforce", a.config.Budget.Enabled)
+}
+
+// setupRoutingRuleStore 设置模型路由规则存储 (SQLite)
+func (a *App) setupRoutingRuleStore() {
+	// 使用 usageTracker 的数据库连接
+	if a.usageTracker == nil {
+		a.logger.Debug("路由规则存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (路由规则)")
+		return
+	}
+
+	a.routingRuleStore = store.NewSQLiteRoutingRuleStore(db)
+	a.routingRuleService = service.NewRoutingRuleService(a.routingRuleStore)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.routingRuleService.LoadCache(ctx); err != nil {
+		a.logger.Warn("⚠️ 加载路由规则缓存失败", "error", err)
+	}
+
+	// 命中规则的请求只转发到规则允许的端点
+	if a.endpointManager != nil {
+		a.endpointManager.SetModelRouter(a.routingRuleService)
+	}
+
+	a.logger.Info("✅ 模型路由规则存储已启用 (SQLite)")
+}
+
+// setupResponseCacheStore 设置响应缓存存储 (SQLite)
+func (a *App) setupResponseCacheStore() {
+	// 使用 usageTracker 的数据库连接
+	if a.usageTracker == nil {
+		a.logger.Debug("响应缓存存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (响应缓存)")
+		return
+	}
+
+	a.responseCacheStore = store.NewSQLiteResponseCacheStore(db)
+	a.responseCacheService = service.NewResponseCacheService(a.responseCacheStore)
+	a.responseCacheService.Configure(a.config.ResponseCache.Enabled, a.config.ResponseCache.TTL, a.config.ResponseCache.MaxEntries)
+
+	a.logger.Info("✅ 响应缓存存储已启用 (SQLite)", "enabled", a.config.ResponseCache.Enabled)
+}
+
+// setupWebhookStore 设置 Webhook 存储 (SQLite)
+func (a *App) setupWebhookStore() {
+	// 使用 usageTracker 的数据库连接
+	if a.usageTracker == nil {
+		a.logger.Debug("Webhook 存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (Webhook)")
+		return
+	}
+
+	a.webhookStore = store.NewSQLiteWebhookStore(db)
+	a.webhookService = service.NewWebhookService(a.webhookStore)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.webhookService.LoadCache(ctx); err != nil {
+		a.logger.Warn("⚠️ 加载 Webhook 缓存失败", "error", err)
+	}
+
+	// 订阅端点、组、挂起超时和预算事件
+	a.webhookService.Start(a.eventBus)
+
+	a.logger.Info("✅ Webhook 存储已启用 (SQLite)")
+}
+
+// setupKeyStateStore 设置多 Key 轮换状态存储 (SQLite)
+func (a *App) setupKeyStateStore() {
+	if a.usageTracker == nil || a.endpointManager == nil {
+		a.logger.Debug("Key 状态存储跳过初始化 (usage_tracking 未启用)")
+		return
+	}
+
+	db := a.usageTracker.GetDB()
+	if db == nil {
+		a.logger.Error("❌ 无法获取数据库连接 (Key 状态)")
+		return
+	}
+
+	a.keyStateStore = store.NewSQLiteKeyStateStore(db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	records, err := a.keyStateStore.List(ctx)
+	if err != nil {
+		a.logger.Warn("⚠️ 加载 Key 状态失败", "error", err)
+	} else if len(records) > 0 {
+		snapshots := make([]endpoint.KeyStateSnapshot, 0, len(records))
+		for _, r := range records {
+			snapshot := endpoint.KeyStateSnapshot{
+				EndpointName: r.EndpointName,
+				KeyType:      r.KeyType,
+				KeyHash:      r.KeyHash,
+				Active:       r.IsActive,
+				Status: endpoint.KeyStatus{
+					Invalid:        r.Invalid,
+					LastStatusCode: r.LastStatusCode,
+					LastError:      r.LastError,
+					UsageCount:     r.UsageCount,
+				},
+			}
+			if r.CooldownUntil != nil {
+				snapshot.Status.CooldownUntil = *r.CooldownUntil
+			}
+			if r.LastUsedAt != nil {
+				snapshot.Status.LastUsedAt = *r.LastUsedAt
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		restored := a.endpointManager.RestoreKeyStates(snapshots)
+		a.logger.Info(fmt.Sprintf("🔑 已恢复 %d 个 Key 的轮换状态", restored))
+	}
+
+	// Key 冷却/失效/切换时异步保存
+	a.endpointManager.SetOnKeyStateChanged(func() {
+		go a.saveKeyStates()
+	})
+}
+
+// saveKeyStates 保存当前多 Key 轮换状态
+func (a *App) saveKeyStates() {
+	if a.keyStateStore == nil || a.endpointManager == nil {
+		return
+	}
+
+	a.keyStateMu.Lock()
+	defer a.keyStateMu.Unlock()
+
+	snapshots := a.endpointManager.KeyStateSnapshots()
+	records := make([]*store.KeyStateRecord, 0, len(snapshots))
+	for _, s := range snapshots {
+		record := &store.KeyStateRecord{
+			EndpointName:   s.EndpointName,
+			KeyType:        s.KeyType,
+			KeyHash:        s.KeyHash,
+			IsActive:       s.Active,
+			UsageCount:     s.Status.UsageCount,
+			Invalid:        s.Status.Invalid,
+			LastStatusCode: s.Status.LastStatusCode,
+			LastError:      s.Status.LastError,
+		}
+		if !s.Status.CooldownUntil.IsZero() {
+			cooldownUntil := s.Status.CooldownUntil
+			record.CooldownUntil = &cooldownUntil
+		}
+		if !s.Status.LastUsedAt.IsZero() {
+			lastUsedAt := s.Status.LastUsedAt
+			record.LastUsedAt = &lastUsedAt
+		}
+		records = append(records, record)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.keyStateStore.ReplaceAll(ctx, records); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [Key轮换] 保存 Key 状态失败: %v", err))
+	}
+}
+
 // initDefaultModelPricing 初始化默认模型定价数据
 func (a *App) initDefaultModelPricing(ctx context.Context) {
 	// Claude 官方定价 (2025年最新)
@@ -560,6 +928,14 @@ func (a *App) syncPricingToTracker(ctx context.Context) {
 	// 更新 UsageTracker 的定价缓存
 	a.usageTracker.UpdatePricing(pricing)
 	a.logger.Debug("已同步模型定价到 UsageTracker", "count", len(pricing))
+
+	// 同步定价历史（归档和重算按请求开始时间选择定价版本）
+	history, err := a.modelPricingService.PricingHistory(ctx)
+	if err != nil {
+		a.logger.Warn("⚠️ 获取模型定价历史失败", "error", err)
+		return
+	}
+	a.usageTracker.UpdatePricingHistory(history)
 }
 
 // syncEndpointMultipliersToTracker 同步端点倍率到 UsageTracker
@@ -635,6 +1011,17 @@ func (a *App) setupUsageTracker() {
 	a.logger.Info("📊 使用追踪已启用", "database", a.config.UsageTracking.DatabasePath)
 }
 
+// setupTracing 初始化 OpenTelemetry 链路追踪
+// 初始化失败只记录错误，不影响代理启动（此时使用 no-op Tracer）
+func (a *App) setupTracing() {
+	shutdown, err := tracing.Setup(context.Background(), a.config.Tracing)
+	if err != nil {
+		a.logger.Error("链路追踪初始化失败", "error", err)
+		return
+	}
+	a.tracingShutdown = shutdown
+}
+
 // setupProxyHandler 设置代理处理器
 func (a *App) setupProxyHandler() {
 	// 创建代理处理器
@@ -645,9 +1032,25 @@ func (a *App) setupProxyHandler() {
 	a.loggingMiddleware = middleware.NewLoggingMiddleware(a.logger)
 	a.monitoringMiddleware = middleware.NewMonitoringMiddleware(a.endpointManager)
 	a.authMiddleware = middleware.NewAuthMiddleware(a.config.Auth)
+	if a.clientKeyService != nil {
+		a.authMiddleware.SetClientKeyResolver(a.clientKeyService)
+	}
+	if a.budgetService != nil {
+		a.proxyHandler.SetBudgetGuard(a.budgetService)
+	}
+	if a.responseCacheService != nil {
+		a.proxyHandler.SetResponseCache(a.responseCacheService)
+	}
+	a.captureRecorder = capture.NewRecorder(a.config.Capture)
+	a.proxyHandler.SetCaptureRecorder(a.captureRecorder)
+	if a.config.Capture.Enabled {
+		a.logger.Info("📼 请求抓取已启用", "dir", a.config.Capture.Dir, "max_files", a.config.Capture.MaxFiles)
+	}
 
 	// 连接组件
 	a.monitoringMiddleware.SetEventBus(a.eventBus)
+	a.monitoringMiddleware.SubscribeEndpointEvents()
+	a.monitoringMiddleware.SetUsageTracker(a.usageTracker)
 	a.loggingMiddleware.SetUsageTracker(a.usageTracker)
 	a.loggingMiddleware.SetMonitoringMiddleware(a.monitoringMiddleware)
 	a.proxyHandler.SetMonitoringMiddleware(a.monitoringMiddleware)
@@ -684,6 +1087,12 @@ func (a *App) startProxyServer() {
 		})
 	}
 
+	// 注册管理 API（独立的 admin_token 鉴权）
+	a.registerAdminAPI(mux)
+
+	// 注册事件流（SSE）
+	a.registerEventStream(mux)
+
 	// 注册代理处理器
 	mux.Handle("/", a.loggingMiddleware.Wrap(a.authMiddleware.Wrap(a.proxyHandler)))
 
@@ -765,6 +1174,12 @@ func (a *App) setupConfigReload() {
 		a.mu.Lock()
 		defer a.mu.Unlock()
 
+		// 保持与启动时一致的用户目录路径和实际监听端口（端口变更需重启生效）
+		applyAppDirPaths(newCfg)
+		if a.config != nil {
+			newCfg.Server.Port = a.config.Server.Port
+		}
+
 		// 更新配置引用
 		a.config = newCfg
 
@@ -785,6 +1200,12 @@ func (a *App) setupConfigReload() {
 		a.endpointManager.UpdateConfig(newCfg)
 		a.proxyHandler.UpdateConfig(newCfg)
 		a.authMiddleware.UpdateConfig(newCfg.Auth)
+		if a.captureRecorder != nil {
+			a.captureRecorder.Configure(newCfg.Capture)
+		}
+
+		// SQLite 中的系统设置优先于 YAML 配置
+		a.applySettingsToConfig()
 
 		// v5.0+ 注意：模型定价不再从 config.yaml 热重载
 		// 定价配置通过前端「定价」页面管理，存储在 SQLite model_pricing 表中
@@ -798,21 +1219,84 @@ func (a *App) setupConfigReload() {
 	a.logger.Info("🔄 配置热重载已启用")
 }
 
+// 事件桥接的合并窗口：窗口内同一类前端事件只推送一次
+const eventBridgeFlushInterval = 500 * time.Millisecond
+
+// 系统状态的保活推送间隔：状态只在事件发生时推送，运行时长由前端根据启动时间本地计算，
+// 保活只用于兜底端口监听状态等不会产生 EventBus 事件的变化
+const eventBridgeStatusKeepalive = 30 * time.Second
+
+// isRequestCompletedEvent 请求完成事件：生命周期状态更新为完成，或请求最终结果事件
+func isRequestCompletedEvent(event events.Event) bool {
+	if event.Type == events.EventRequestCompleted {
+		return true
+	}
+	changeType, _ := event.Data["change_type"].(string)
+	return event.Type == events.EventRequestUpdated && changeType == "request_completed"
+}
+
 // setupEventBridges 设置事件桥接
-// 将内部 EventBus 事件转发到 Wails 前端
+// 订阅内部 EventBus 的请求、端点和组事件，合并后推送到 Wails 前端
 func (a *App) setupEventBridges() {
-	// 注意：当前 EventBus 实现不支持订阅回调
-	// 我们使用定时轮询来更新前端状态
+	if a.headless || a.eventBus == nil {
+		return
+	}
+
+	eventCh, unsubscribe := a.eventBus.Subscribe(events.SubscriptionFilter{
+		Name:       "wails_bridge",
+		Categories: []string{"request", "endpoint", "group"},
+	})
+
 	go func() {
-		ticker := time.NewTicker(2 * time.Second)
-		defer ticker.Stop()
+		defer unsubscribe()
 
+		flush := time.NewTimer(eventBridgeFlushInterval)
+		flush.Stop()
+		keepalive := time.NewTicker(eventBridgeStatusKeepalive)
+		defer keepalive.Stop()
+
+		var scheduled, pendingStatus, pendingEndpoint, pendingGroup, pendingUsage bool
 		for {
 			select {
 			case <-a.ctx.Done():
 				return
-			case <-ticker.C:
-				if a.isRunning {
+			case event, ok := <-eventCh:
+				if !ok {
+					return
+				}
+				switch events.EventTypeMapping[event.Type] {
+				case "request":
+					pendingStatus = true
+					if isRequestCompletedEvent(event) {
+						pendingUsage = true
+					}
+				case "endpoint":
+					pendingEndpoint = true
+				case "group":
+					pendingGroup = true
+					pendingStatus = true // 系统状态包含当前活跃组
+				}
+				if !scheduled {
+					flush.Reset(eventBridgeFlushInterval)
+					scheduled = true
+				}
+			case <-flush.C:
+				scheduled = false
+				if pendingStatus && a.isRunning {
+					a.emitSystemStatus()
+				}
+				if pendingEndpoint {
+					a.emitEndpointUpdate()
+				}
+				if pendingGroup {
+					a.emitGroupUpdate()
+				}
+				if pendingUsage {
+					a.emitUsageUpdate()
+				}
+				pendingStatus, pendingEndpoint, pendingGroup, pendingUsage = false, false, false, false
+			case <-keepalive.C:
+				if a.isRunning && !pendingStatus {
 					a.emitSystemStatus()
 				}
 			}
@@ -864,7 +1348,7 @@ func (a *App) startHistoryCollector() {
 
 // emitError 发送错误通知到前端
 func (a *App) emitError(title, message string) {
-	if a.ctx != nil {
+	if a.canEmit() {
 		runtime.EventsEmit(a.ctx, "error", map[string]string{
 			"title":   title,
 			"message": message,
@@ -874,7 +1358,7 @@ func (a *App) emitError(title, message string) {
 
 // emitConfigReloaded 通知前端配置已重载
 func (a *App) emitConfigReloaded() {
-	if a.ctx != nil {
+	if a.canEmit() {
 		runtime.EventsEmit(a.ctx, "config:reloaded", nil)
 	}
 }
@@ -939,12 +1423,20 @@ func (a *App) applySettingsToConfig() {
 	a.config.Strategy.FastTestCacheTTL = a.settingsService.GetDuration(ctx, service.CategoryStrategy, "fast_test_cache_ttl", a.config.Strategy.FastTestCacheTTL)
 	a.config.Strategy.FastTestTimeout = a.settingsService.GetDuration(ctx, service.CategoryStrategy, "fast_test_timeout", a.config.Strategy.FastTestTimeout)
 	a.config.Strategy.FastTestPath = a.getSettingString(ctx, service.CategoryStrategy, "fast_test_path", a.config.Strategy.FastTestPath)
+	a.config.Strategy.RateLimitReserve = a.settingsService.GetFloat(ctx, service.CategoryStrategy, "rate_limit_reserve", a.config.Strategy.RateLimitReserve)
 
 	// 重试配置
 	a.config.Retry.MaxAttempts = a.settingsService.GetInt(ctx, service.CategoryRetry, "max_attempts", a.config.Retry.MaxAttempts)
 	a.config.Retry.BaseDelay = a.settingsService.GetDuration(ctx, service.CategoryRetry, "base_delay", a.config.Retry.BaseDelay)
 	a.config.Retry.MaxDelay = a.settingsService.GetDuration(ctx, service.CategoryRetry, "max_delay", a.config.Retry.MaxDelay)
 	a.config.Retry.Multiplier = a.settingsService.GetFloat(ctx, service.CategoryRetry, "multiplier", a.config.Retry.Multiplier)
+	if policy, err := a.settingsService.GetValue(ctx, service.CategoryRetry, "policy"); err == nil {
+		if rules, err := config.ParseRetryPolicy(policy); err != nil {
+			slog.Warn(fmt.Sprintf("⚠️ [设置] 重试策略无效，保持当前策略: %v", err))
+		} else {
+			a.config.Retry.Policy = rules
+		}
+	}
 
 	// 健康检查配置
 	a.config.Health.CheckInterval = a.settingsService.GetDuration(ctx, service.CategoryHealth, "check_interval", a.config.Health.CheckInterval)
@@ -974,11 +1466,40 @@ func (a *App) applySettingsToConfig() {
 	// 访问控制配置
 	a.config.Auth.Enabled = a.settingsService.GetBool(ctx, service.CategoryAuth, "enabled", a.config.Auth.Enabled)
 	a.config.Auth.Token = a.getSettingString(ctx, service.CategoryAuth, "token", a.config.Auth.Token)
+	a.config.Auth.AdminToken = a.getSettingString(ctx, service.CategoryAuth, "admin_token", a.config.Auth.AdminToken)
 
 	// Token 计数配置
 	a.config.TokenCounting.Enabled = a.settingsService.GetBool(ctx, service.CategoryTokenCounting, "enabled", a.config.TokenCounting.Enabled)
 	a.config.TokenCounting.EstimationRatio = a.settingsService.GetFloat(ctx, service.CategoryTokenCounting, "estimation_ratio", a.config.TokenCounting.EstimationRatio)
 
+	// OpenAI 兼容配置
+	a.config.OpenAICompat.Enabled = a.settingsService.GetBool(ctx, service.CategoryOpenAICompat, "enabled", a.config.OpenAICompat.Enabled)
+	a.config.OpenAICompat.DefaultMaxTokens = a.settingsService.GetInt(ctx, service.CategoryOpenAICompat, "default_max_tokens", a.config.OpenAICompat.DefaultMaxTokens)
+
+	// 预算配置
+	a.config.Budget.Enabled = a.settingsService.GetBool(ctx, service.CategoryBudget, "enabled", a.config.Budget.Enabled)
+	a.config.Budget.RefreshInterval = a.settingsService.GetDuration(ctx, service.CategoryBudget, "refresh_interval", a.config.Budget.RefreshInterval)
+
+	// 多 Key 轮换配置
+	a.config.KeyRotation.Enabled = a.settingsService.GetBool(ctx, service.CategoryKeyRotation, "enabled", a.config.KeyRotation.Enabled)
+	a.config.KeyRotation.Strategy = a.getSettingString(ctx, service.CategoryKeyRotation, "strategy", a.config.KeyRotation.Strategy)
+	a.config.KeyRotation.Cooldown = a.settingsService.GetDuration(ctx, service.CategoryKeyRotation, "cooldown", a.config.KeyRotation.Cooldown)
+
+	// 响应缓存配置
+	a.config.ResponseCache.Enabled = a.settingsService.GetBool(ctx, service.CategoryResponseCache, "enabled", a.config.ResponseCache.Enabled)
+	a.config.ResponseCache.TTL = a.settingsService.GetDuration(ctx, service.CategoryResponseCache, "ttl", a.config.ResponseCache.TTL)
+	a.config.ResponseCache.MaxEntries = a.settingsService.GetInt(ctx, service.CategoryResponseCache, "max_entries", a.config.ResponseCache.MaxEntries)
+
+	// 熔断器配置
+	a.config.CircuitBreaker.Enabled = a.settingsService.GetBool(ctx, service.CategoryCircuit, "enabled", a.config.CircuitBreaker.Enabled)
+	a.config.CircuitBreaker.Window = a.settingsService.GetDuration(ctx, service.CategoryCircuit, "window", a.config.CircuitBreaker.Window)
+	a.config.CircuitBreaker.MinRequests = a.settingsService.GetInt(ctx, service.CategoryCircuit, "min_requests", a.config.CircuitBreaker.MinRequests)
+	a.config.CircuitBreaker.ErrorRate = a.settingsService.GetFloat(ctx, service.CategoryCircuit, "error_rate", a.config.CircuitBreaker.ErrorRate)
+	a.config.CircuitBreaker.SlowCallDuration = a.settingsService.GetDuration(ctx, service.CategoryCircuit, "slow_call_duration", a.config.CircuitBreaker.SlowCallDuration)
+	a.config.CircuitBreaker.SlowCallRate = a.settingsService.GetFloat(ctx, service.CategoryCircuit, "slow_call_rate", a.config.CircuitBreaker.SlowCallRate)
+	a.config.CircuitBreaker.OpenDuration = a.settingsService.GetDuration(ctx, service.CategoryCircuit, "open_duration", a.config.CircuitBreaker.OpenDuration)
+	a.config.CircuitBreaker.HalfOpenRequests = a.settingsService.GetInt(ctx, service.CategoryCircuit, "half_open_requests", a.config.CircuitBreaker.HalfOpenRequests)
+
 	// 数据保留配置
 	a.config.UsageTracking.RetentionDays = a.settingsService.GetInt(ctx, service.CategoryRetention, "retention_days", a.config.UsageTracking.RetentionDays)
 	a.config.UsageTracking.CleanupInterval = a.settingsService.GetDuration(ctx, service.CategoryRetention, "cleanup_interval", a.config.UsageTracking.CleanupInterval)
@@ -995,6 +1516,12 @@ func (a *App) applySettingsToConfig() {
 	if a.authMiddleware != nil {
 		a.authMiddleware.UpdateConfig(a.config.Auth)
 	}
+	if a.budgetService != nil {
+		a.budgetService.Configure(a.config.Budget.Enabled, a.config.Budget.RefreshInterval)
+	}
+	if a.responseCacheService != nil {
+		a.responseCacheService.Configure(a.config.ResponseCache.Enabled, a.config.ResponseCache.TTL, a.config.ResponseCache.MaxEntries)
+	}
 }
 
 // getSettingString 获取字符串设置值（带默认值）
@@ -0,0 +1,896 @@
+// app_admin_api.go - 本地 REST 管理 API
+// 将 Wails 绑定方法以 JSON HTTP 接口形式暴露在 /admin/api/v1 下，便于脚本和 CI 调用
+// 使用独立的管理 Token（auth.admin_token），与代理访问 Token 分离
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/store"
+)
+
+// adminAPIPrefix 管理 API 路径前缀
+const adminAPIPrefix = "/admin/api/v1"
+
+// adminAPIMaxBodySize 管理 API 请求体大小上限
+const adminAPIMaxBodySize = 1 << 20
+
+// adminAPIMaxImportSize 定价导入的价格目录大小上限（LiteLLM 完整目录超过 1MB）
+const adminAPIMaxImportSize = 32 << 20
+
+// AdminAPIResponse 管理 API 统一响应格式
+type AdminAPIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// registerAdminAPI 在代理服务器的 mux 上注册管理 API
+func (a *App) registerAdminAPI(mux *http.ServeMux) {
+	api := http.NewServeMux()
+
+	// 系统状态
+	api.HandleFunc("GET "+adminAPIPrefix+"/status", a.adminGetStatus)
+
+	// 运行时端点（健康状态、优先级、Key 切换）
+	api.HandleFunc("GET "+adminAPIPrefix+"/endpoints", a.adminGetEndpoints)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/endpoints/{name}/priority", a.adminSetEndpointPriority)
+	api.HandleFunc("POST "+adminAPIPrefix+"/endpoints/{name}/health-check", a.adminTriggerHealthCheck)
+	api.HandleFunc("POST "+adminAPIPrefix+"/endpoints/health-check", a.adminBatchHealthCheck)
+	api.HandleFunc("POST "+adminAPIPrefix+"/endpoints/{name}/keys/switch", a.adminSwitchKey)
+	api.HandleFunc("GET "+adminAPIPrefix+"/keys", a.adminGetKeysOverview)
+
+	// 端点存储（SQLite CRUD）
+	api.HandleFunc("GET "+adminAPIPrefix+"/endpoint-records", a.adminGetEndpointRecords)
+	api.HandleFunc("POST "+adminAPIPrefix+"/endpoint-records", a.adminCreateEndpointRecord)
+	api.HandleFunc("GET "+adminAPIPrefix+"/endpoint-records/{name}", a.adminGetEndpointRecord)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/endpoint-records/{name}", a.adminUpdateEndpointRecord)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/endpoint-records/{name}", a.adminDeleteEndpointRecord)
+	api.HandleFunc("POST "+adminAPIPrefix+"/endpoint-records/{name}/toggle", a.adminToggleEndpointRecord)
+	api.HandleFunc("GET "+adminAPIPrefix+"/channels", a.adminGetChannels)
+
+	// 组管理
+	api.HandleFunc("GET "+adminAPIPrefix+"/groups", a.adminGetGroups)
+	api.HandleFunc("POST "+adminAPIPrefix+"/groups/{name}/activate", a.adminGroupAction(a.ActivateGroup))
+	api.HandleFunc("POST "+adminAPIPrefix+"/groups/{name}/pause", a.adminGroupAction(a.PauseGroup))
+	api.HandleFunc("POST "+adminAPIPrefix+"/groups/{name}/resume", a.adminGroupAction(a.ResumeGroup))
+
+	// 使用统计
+	api.HandleFunc("GET "+adminAPIPrefix+"/requests", a.adminGetRequests)
+	api.HandleFunc("GET "+adminAPIPrefix+"/usage/summary", a.adminGetUsageSummary)
+	api.HandleFunc("GET "+adminAPIPrefix+"/usage/stats", a.adminGetUsageStats)
+
+	// 模型定价
+	api.HandleFunc("GET "+adminAPIPrefix+"/model-pricing", a.adminGetModelPricings)
+	api.HandleFunc("POST "+adminAPIPrefix+"/model-pricing", a.adminCreateModelPricing)
+	api.HandleFunc("GET "+adminAPIPrefix+"/model-pricing/{model}", a.adminGetModelPricing)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/model-pricing/{model}", a.adminUpdateModelPricing)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/model-pricing/{model}", a.adminDeleteModelPricing)
+	api.HandleFunc("POST "+adminAPIPrefix+"/model-pricing/{model}/default", a.adminSetDefaultModelPricing)
+	api.HandleFunc("POST "+adminAPIPrefix+"/model-pricing/import/preview", a.adminPreviewModelPricingImport)
+	api.HandleFunc("POST "+adminAPIPrefix+"/model-pricing/import", a.adminApplyModelPricingImport)
+	api.HandleFunc("GET "+adminAPIPrefix+"/model-pricing/import/audits", a.adminGetModelPricingAudits)
+	api.HandleFunc("GET "+adminAPIPrefix+"/model-pricing/{model}/versions", a.adminGetModelPricingVersions)
+	api.HandleFunc("POST "+adminAPIPrefix+"/model-pricing/{model}/versions", a.adminAddModelPricingVersion)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/model-pricing/versions/{id}", a.adminDeleteModelPricingVersion)
+
+	// 成本重算
+	api.HandleFunc("GET "+adminAPIPrefix+"/costs/recompute", a.adminGetCostRecomputeStatus)
+	api.HandleFunc("POST "+adminAPIPrefix+"/costs/recompute", a.adminStartCostRecompute)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/costs/recompute", a.adminCancelCostRecompute)
+
+	// 客户端 Key
+	api.HandleFunc("GET "+adminAPIPrefix+"/client-keys", a.adminGetClientKeys)
+	api.HandleFunc("POST "+adminAPIPrefix+"/client-keys", a.adminCreateClientKey)
+	api.HandleFunc("GET "+adminAPIPrefix+"/client-keys/{name}", a.adminGetClientKey)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/client-keys/{name}", a.adminUpdateClientKey)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/client-keys/{name}", a.adminDeleteClientKey)
+	api.HandleFunc("POST "+adminAPIPrefix+"/client-keys/{name}/toggle", a.adminToggleClientKey)
+
+	// 预算
+	api.HandleFunc("GET "+adminAPIPrefix+"/budgets", a.adminGetBudgets)
+	api.HandleFunc("POST "+adminAPIPrefix+"/budgets", a.adminCreateBudget)
+	api.HandleFunc("GET "+adminAPIPrefix+"/budgets/{name}", a.adminGetBudget)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/budgets/{name}", a.adminUpdateBudget)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/budgets/{name}", a.adminDeleteBudget)
+
+	// 模型路由规则
+	api.HandleFunc("GET "+adminAPIPrefix+"/routing-rules", a.adminGetRoutingRules)
+	api.HandleFunc("POST "+adminAPIPrefix+"/routing-rules", a.adminCreateRoutingRule)
+	api.HandleFunc("GET "+adminAPIPrefix+"/routing-rules/match", a.adminMatchRoutingRule)
+	api.HandleFunc("GET "+adminAPIPrefix+"/routing-rules/{name}", a.adminGetRoutingRule)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/routing-rules/{name}", a.adminUpdateRoutingRule)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/routing-rules/{name}", a.adminDeleteRoutingRule)
+
+	// 响应缓存
+	api.HandleFunc("GET "+adminAPIPrefix+"/response-cache", a.adminGetResponseCacheStats)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/response-cache", a.adminPurgeResponseCache)
+	api.HandleFunc("GET "+adminAPIPrefix+"/response-cache/entries", a.adminGetResponseCacheEntries)
+	api.HandleFunc("GET "+adminAPIPrefix+"/response-cache/entries/{key}", a.adminGetResponseCacheEntry)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/response-cache/entries/{key}", a.adminDeleteResponseCacheEntry)
+
+	// Webhook 通知
+	api.HandleFunc("GET "+adminAPIPrefix+"/webhooks", a.adminGetWebhooks)
+	api.HandleFunc("POST "+adminAPIPrefix+"/webhooks", a.adminCreateWebhook)
+	api.HandleFunc("GET "+adminAPIPrefix+"/webhooks/event-types", a.adminGetWebhookEventTypes)
+	api.HandleFunc("GET "+adminAPIPrefix+"/webhooks/deliveries", a.adminGetWebhookDeliveries)
+	api.HandleFunc("GET "+adminAPIPrefix+"/webhooks/{name}", a.adminGetWebhook)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/webhooks/{name}", a.adminUpdateWebhook)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/webhooks/{name}", a.adminDeleteWebhook)
+	api.HandleFunc("POST "+adminAPIPrefix+"/webhooks/{name}/test", a.adminTestWebhook)
+	api.HandleFunc("GET "+adminAPIPrefix+"/webhooks/{name}/deliveries", a.adminGetWebhookDeliveries)
+
+	// 请求抓取
+	api.HandleFunc("GET "+adminAPIPrefix+"/captures", a.adminGetCaptures)
+	api.HandleFunc("GET "+adminAPIPrefix+"/captures/{id}", a.adminGetCapture)
+	api.HandleFunc("DELETE "+adminAPIPrefix+"/captures/{id}", a.adminDeleteCapture)
+
+	// 系统设置
+	api.HandleFunc("GET "+adminAPIPrefix+"/settings", a.adminGetAllSettings)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/settings", a.adminBatchUpdateSettings)
+	api.HandleFunc("GET "+adminAPIPrefix+"/settings/categories", a.adminGetSettingCategories)
+	api.HandleFunc("GET "+adminAPIPrefix+"/settings/{category}", a.adminGetSettingsByCategory)
+	api.HandleFunc("POST "+adminAPIPrefix+"/settings/{category}/reset", a.adminResetCategorySettings)
+	api.HandleFunc("GET "+adminAPIPrefix+"/settings/{category}/{key}", a.adminGetSetting)
+	api.HandleFunc("PUT "+adminAPIPrefix+"/settings/{category}/{key}", a.adminUpdateSetting)
+
+	mux.Handle(adminAPIPrefix+"/", a.adminAuth(api))
+	a.logger.Info("🛠️ 管理 API 已注册", "prefix", adminAPIPrefix)
+}
+
+// adminAuth 管理 API 鉴权
+// 未配置 admin_token 时管理 API 处于关闭状态
+func (a *App) adminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		adminToken := ""
+		if a.config != nil {
+			adminToken = a.config.Auth.AdminToken
+		}
+		a.mu.RUnlock()
+
+		if adminToken == "" {
+			writeAdminError(w, http.StatusForbidden, "管理 API 未启用（未配置 admin_token）")
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			writeAdminError(w, http.StatusUnauthorized, "Authorization header required. Expected 'Bearer <admin_token>'")
+			return
+		}
+
+		token := strings.TrimPrefix(auth, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, "Invalid admin token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ============================================================
+// 响应辅助函数
+// ============================================================
+
+// writeAdminJSON 写入成功响应
+func writeAdminJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(AdminAPIResponse{Success: true, Data: data})
+}
+
+// writeAdminError 写入错误响应
+func writeAdminError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(AdminAPIResponse{Success: false, Error: message})
+}
+
+// writeAdminServiceError 按错误类型写入错误响应
+func writeAdminServiceError(w http.ResponseWriter, err error) {
+	writeAdminError(w, adminErrorStatus(err), err.Error())
+}
+
+// adminErrorStatus 将业务错误映射为 HTTP 状态码
+// 记录不存在 404，记录已存在 409，数据库或文件系统故障 500，其余（参数校验等）400
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, store.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrAlreadyExists):
+		return http.StatusConflict
+	case store.IsStorageError(err), errors.As(err, new(*fs.PathError)):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// writeAdminResult 根据 error 写入响应（错误状态码见 adminErrorStatus）
+func writeAdminResult(w http.ResponseWriter, data interface{}, err error) {
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, data)
+}
+
+// decodeAdminBody 解析 JSON 请求体，失败时写入 400 响应并返回 false
+func decodeAdminBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, adminAPIMaxBodySize))
+	if err := decoder.Decode(v); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Sprintf("无效的请求体: %v", err))
+		return false
+	}
+	return true
+}
+
+// queryInt 读取整数查询参数
+func queryInt(r *http.Request, key string, defaultVal int) int {
+	if v := r.URL.Query().Get(key); v != "" {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return defaultVal
+}
+
+// ============================================================
+// 系统 / 端点
+// ============================================================
+
+func (a *App) adminGetStatus(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetSystemStatus())
+}
+
+func (a *App) adminGetEndpoints(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetEndpoints())
+}
+
+func (a *App) adminSetEndpointPriority(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Priority int `json:"priority"`
+	}
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.SetEndpointPriority(r.PathValue("name"), input.Priority))
+}
+
+func (a *App) adminTriggerHealthCheck(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.TriggerHealthCheck(r.PathValue("name")))
+}
+
+func (a *App) adminBatchHealthCheck(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.BatchHealthCheckAll())
+}
+
+func (a *App) adminSwitchKey(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		KeyType string `json:"key_type"` // "token" 或 "api_key"
+		Index   int    `json:"index"`
+	}
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	result, err := a.SwitchKey(r.PathValue("name"), input.KeyType, input.Index)
+	writeAdminResult(w, result, err)
+}
+
+func (a *App) adminGetKeysOverview(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetKeysOverview())
+}
+
+// ============================================================
+// 端点存储
+// ============================================================
+
+func (a *App) adminGetEndpointRecords(w http.ResponseWriter, r *http.Request) {
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		records, err := a.GetEndpointsByChannel(channel)
+		writeAdminResult(w, records, err)
+		return
+	}
+	records, err := a.GetEndpointRecords()
+	writeAdminResult(w, records, err)
+}
+
+func (a *App) adminGetEndpointRecord(w http.ResponseWriter, r *http.Request) {
+	record, err := a.GetEndpointRecord(r.PathValue("name"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, record)
+}
+
+func (a *App) adminCreateEndpointRecord(w http.ResponseWriter, r *http.Request) {
+	var input CreateEndpointInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	if err := a.CreateEndpointRecord(input); err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, nil)
+}
+
+func (a *App) adminUpdateEndpointRecord(w http.ResponseWriter, r *http.Request) {
+	var input CreateEndpointInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateEndpointRecord(r.PathValue("name"), input))
+}
+
+func (a *App) adminDeleteEndpointRecord(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteEndpointRecord(r.PathValue("name")))
+}
+
+func (a *App) adminToggleEndpointRecord(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.ToggleEndpointRecord(r.PathValue("name"), input.Enabled))
+}
+
+func (a *App) adminGetChannels(w http.ResponseWriter, r *http.Request) {
+	channels, err := a.GetChannels()
+	writeAdminResult(w, channels, err)
+}
+
+// ============================================================
+// 组管理
+// ============================================================
+
+func (a *App) adminGetGroups(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetGroups())
+}
+
+// adminGroupAction 包装组操作（激活/暂停/恢复）
+func (a *App) adminGroupAction(action func(name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResult(w, nil, action(r.PathValue("name")))
+	}
+}
+
+// ============================================================
+// 使用统计
+// ============================================================
+
+func (a *App) adminGetRequests(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := RequestQueryParams{
+		Page:      queryInt(r, "page", 1),
+		PageSize:  queryInt(r, "page_size", 20),
+		StartDate: q.Get("start_date"),
+		EndDate:   q.Get("end_date"),
+		Status:    q.Get("status"),
+		Model:     q.Get("model"),
+		Channel:   q.Get("channel"),
+		Endpoint:  q.Get("endpoint"),
+		Group:     q.Get("group"),
+		ClientKey: q.Get("client_key"),
+	}
+	result, err := a.GetRequests(params)
+	writeAdminResult(w, result, err)
+}
+
+func (a *App) adminGetUsageSummary(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	summary, err := a.GetUsageSummary(q.Get("start_date"), q.Get("end_date"))
+	writeAdminResult(w, summary, err)
+}
+
+func (a *App) adminGetUsageStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := UsageStatsQueryParams{
+		Period:    q.Get("period"),
+		StartDate: q.Get("start_date"),
+		EndDate:   q.Get("end_date"),
+		Status:    q.Get("status"),
+		Model:     q.Get("model"),
+		Channel:   q.Get("channel"),
+		Endpoint:  q.Get("endpoint"),
+		Group:     q.Get("group"),
+		ClientKey: q.Get("client_key"),
+	}
+	stats, err := a.GetUsageStats(params)
+	writeAdminResult(w, stats, err)
+}
+
+// ============================================================
+// 模型定价
+// ============================================================
+
+func (a *App) adminGetModelPricings(w http.ResponseWriter, r *http.Request) {
+	pricings, err := a.GetModelPricings()
+	writeAdminResult(w, pricings, err)
+}
+
+func (a *App) adminGetModelPricing(w http.ResponseWriter, r *http.Request) {
+	pricing, err := a.GetModelPricing(r.PathValue("model"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, pricing)
+}
+
+func (a *App) adminCreateModelPricing(w http.ResponseWriter, r *http.Request) {
+	var input CreateModelPricingInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	if err := a.CreateModelPricing(input); err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, nil)
+}
+
+func (a *App) adminUpdateModelPricing(w http.ResponseWriter, r *http.Request) {
+	var input CreateModelPricingInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateModelPricing(r.PathValue("model"), input))
+}
+
+func (a *App) adminDeleteModelPricing(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteModelPricing(r.PathValue("model")))
+}
+
+func (a *App) adminSetDefaultModelPricing(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.SetDefaultModelPricing(r.PathValue("model")))
+}
+
+// adminPreviewModelPricingImport 预览定价导入
+// 请求体为价格目录文件（LiteLLM JSON 或 CSV），选项通过查询参数传递：
+// ?format=litellm&providers=anthropic,bedrock&only_existing=true&source=xxx
+func (a *App) adminPreviewModelPricingImport(w http.ResponseWriter, r *http.Request) {
+	input, ok := readPricingImportInput(w, r)
+	if !ok {
+		return
+	}
+	result, err := a.PreviewModelPricingImport(input)
+	writeAdminResult(w, result, err)
+}
+
+// adminApplyModelPricingImport 应用定价导入（?fingerprint= 传入预览返回的摘要）
+func (a *App) adminApplyModelPricingImport(w http.ResponseWriter, r *http.Request) {
+	input, ok := readPricingImportInput(w, r)
+	if !ok {
+		return
+	}
+	result, err := a.ApplyModelPricingImport(input)
+	writeAdminResult(w, result, err)
+}
+
+// adminGetModelPricingAudits 定价导入审计（?limit=50，按时间倒序）
+func (a *App) adminGetModelPricingAudits(w http.ResponseWriter, r *http.Request) {
+	audits, err := a.GetModelPricingAudits(queryInt(r, "limit", 50))
+	writeAdminResult(w, audits, err)
+}
+
+// adminGetModelPricingVersions 获取模型定价版本（按生效时间升序）
+func (a *App) adminGetModelPricingVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := a.GetModelPricingVersions(r.PathValue("model"))
+	writeAdminResult(w, versions, err)
+}
+
+// adminAddModelPricingVersion 添加或修正定价版本（模型名取自路径）
+func (a *App) adminAddModelPricingVersion(w http.ResponseWriter, r *http.Request) {
+	var input ModelPricingVersionInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	input.ModelName = r.PathValue("model")
+	version, err := a.AddModelPricingVersion(input)
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, version)
+}
+
+func (a *App) adminDeleteModelPricingVersion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, "无效的定价版本 ID")
+		return
+	}
+	writeAdminResult(w, nil, a.DeleteModelPricingVersion(id))
+}
+
+// readPricingImportInput 读取价格目录请求体和导入选项，失败时写入 400 响应并返回 false
+func readPricingImportInput(w http.ResponseWriter, r *http.Request) (ModelPricingImportInput, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, adminAPIMaxImportSize))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Sprintf("读取价格目录失败: %v", err))
+		return ModelPricingImportInput{}, false
+	}
+
+	query := r.URL.Query()
+	input := ModelPricingImportInput{
+		Content:      string(body),
+		Format:       query.Get("format"),
+		OnlyExisting: query.Get("only_existing") == "true",
+		Source:       query.Get("source"),
+		Fingerprint:  query.Get("fingerprint"),
+	}
+	for _, provider := range strings.Split(query.Get("providers"), ",") {
+		if provider = strings.TrimSpace(provider); provider != "" {
+			input.Providers = append(input.Providers, provider)
+		}
+	}
+	return input, true
+}
+
+// ============================================================
+// 成本重算
+// ============================================================
+
+// adminGetCostRecomputeStatus 获取当前或最近一次成本重算任务，没有任务时 data 为 null
+func (a *App) adminGetCostRecomputeStatus(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetCostRecomputeStatus())
+}
+
+func (a *App) adminStartCostRecompute(w http.ResponseWriter, r *http.Request) {
+	var input CostRecomputeInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	status, err := a.StartCostRecompute(input)
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusAccepted, status)
+}
+
+func (a *App) adminCancelCostRecompute(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.CancelCostRecompute())
+}
+
+// ============================================================
+// 客户端 Key
+// ============================================================
+
+func (a *App) adminGetClientKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := a.GetClientKeys()
+	writeAdminResult(w, keys, err)
+}
+
+func (a *App) adminGetClientKey(w http.ResponseWriter, r *http.Request) {
+	key, err := a.GetClientKey(r.PathValue("name"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, key)
+}
+
+func (a *App) adminCreateClientKey(w http.ResponseWriter, r *http.Request) {
+	var input ClientKeyInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	key, err := a.CreateClientKey(input)
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, key)
+}
+
+func (a *App) adminUpdateClientKey(w http.ResponseWriter, r *http.Request) {
+	var input ClientKeyInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateClientKey(r.PathValue("name"), input))
+}
+
+func (a *App) adminDeleteClientKey(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteClientKey(r.PathValue("name")))
+}
+
+func (a *App) adminToggleClientKey(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.ToggleClientKey(r.PathValue("name"), input.Enabled))
+}
+
+// ============================================================
+// 预算
+// ============================================================
+
+func (a *App) adminGetBudgets(w http.ResponseWriter, r *http.Request) {
+	budgets, err := a.GetBudgets()
+	writeAdminResult(w, budgets, err)
+}
+
+func (a *App) adminGetBudget(w http.ResponseWriter, r *http.Request) {
+	budget, err := a.GetBudget(r.PathValue("name"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, budget)
+}
+
+func (a *App) adminCreateBudget(w http.ResponseWriter, r *http.Request) {
+	var input BudgetInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	if err := a.CreateBudget(input); err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, nil)
+}
+
+func (a *App) adminUpdateBudget(w http.ResponseWriter, r *http.Request) {
+	var input BudgetInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateBudget(r.PathValue("name"), input))
+}
+
+func (a *App) adminDeleteBudget(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteBudget(r.PathValue("name")))
+}
+
+// ============================================================
+// 模型路由规则
+// ============================================================
+
+func (a *App) adminGetRoutingRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := a.GetRoutingRules()
+	writeAdminResult(w, rules, err)
+}
+
+func (a *App) adminGetRoutingRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := a.GetRoutingRule(r.PathValue("name"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, rule)
+}
+
+func (a *App) adminCreateRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var input RoutingRuleInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	if err := a.CreateRoutingRule(input); err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, nil)
+}
+
+func (a *App) adminUpdateRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var input RoutingRuleInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateRoutingRule(r.PathValue("name"), input))
+}
+
+func (a *App) adminDeleteRoutingRule(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteRoutingRule(r.PathValue("name")))
+}
+
+func (a *App) adminMatchRoutingRule(w http.ResponseWriter, r *http.Request) {
+	model := r.URL.Query().Get("model")
+	if model == "" {
+		writeAdminError(w, http.StatusBadRequest, "缺少 model 参数")
+		return
+	}
+	result, err := a.TestRoutingRuleMatch(model)
+	writeAdminResult(w, result, err)
+}
+
+// ============================================================
+// 响应缓存
+// ============================================================
+
+func (a *App) adminGetResponseCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.GetResponseCacheStats()
+	writeAdminResult(w, stats, err)
+}
+
+func (a *App) adminGetResponseCacheEntries(w http.ResponseWriter, r *http.Request) {
+	entries, err := a.GetResponseCacheEntries()
+	writeAdminResult(w, entries, err)
+}
+
+func (a *App) adminGetResponseCacheEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := a.GetResponseCacheEntry(r.PathValue("key"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, entry)
+}
+
+func (a *App) adminDeleteResponseCacheEntry(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteResponseCacheEntry(r.PathValue("key")))
+}
+
+// adminPurgeResponseCache 清除缓存（?expired_only=true 只清除已过期的条目）
+func (a *App) adminPurgeResponseCache(w http.ResponseWriter, r *http.Request) {
+	expiredOnly := r.URL.Query().Get("expired_only") == "true"
+	result, err := a.PurgeResponseCache(expiredOnly)
+	writeAdminResult(w, result, err)
+}
+
+// ============================================================
+// Webhook 通知
+// ============================================================
+
+func (a *App) adminGetWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := a.GetWebhooks()
+	writeAdminResult(w, hooks, err)
+}
+
+func (a *App) adminGetWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := a.GetWebhook(r.PathValue("name"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, hook)
+}
+
+func (a *App) adminCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input WebhookInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	if err := a.CreateWebhook(input); err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, nil)
+}
+
+func (a *App) adminUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input WebhookInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateWebhook(r.PathValue("name"), input))
+}
+
+func (a *App) adminDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteWebhook(r.PathValue("name")))
+}
+
+// adminTestWebhook 同步发送测试通知，投递失败时仍返回 200 和投递结果
+func (a *App) adminTestWebhook(w http.ResponseWriter, r *http.Request) {
+	result, err := a.TestWebhook(r.PathValue("name"))
+	writeAdminResult(w, result, err)
+}
+
+// adminGetWebhookDeliveries 投递记录（?limit=100，按时间倒序）
+func (a *App) adminGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := a.GetWebhookDeliveries(r.PathValue("name"), queryInt(r, "limit", 100))
+	writeAdminResult(w, deliveries, err)
+}
+
+func (a *App) adminGetWebhookEventTypes(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetWebhookEventTypes())
+}
+
+// ============================================================
+// 请求抓取
+// ============================================================
+
+func (a *App) adminGetCaptures(w http.ResponseWriter, r *http.Request) {
+	captures, err := a.GetCaptures()
+	writeAdminResult(w, captures, err)
+}
+
+// adminGetCapture 返回原始抓取记录（与抓取文件格式相同，可保存后用 replay -file 重放）
+func (a *App) adminGetCapture(w http.ResponseWriter, r *http.Request) {
+	c, err := a.loadCapture(r.PathValue("id"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, c)
+}
+
+func (a *App) adminDeleteCapture(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.DeleteCapture(r.PathValue("id")))
+}
+
+// ============================================================
+// 系统设置
+// ============================================================
+
+func (a *App) adminGetAllSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := a.GetAllSettings()
+	writeAdminResult(w, redactSecretSettings(settings), err)
+}
+
+func (a *App) adminGetSettingCategories(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.GetSettingCategories())
+}
+
+func (a *App) adminGetSettingsByCategory(w http.ResponseWriter, r *http.Request) {
+	settings, err := a.GetSettingsByCategory(r.PathValue("category"))
+	writeAdminResult(w, redactSecretSettings(settings), err)
+}
+
+func (a *App) adminGetSetting(w http.ResponseWriter, r *http.Request) {
+	setting, err := a.GetSetting(r.PathValue("category"), r.PathValue("key"))
+	if err != nil {
+		writeAdminServiceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, redactSecretSettings([]SettingInfo{setting})[0])
+}
+
+func (a *App) adminUpdateSetting(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Value string `json:"value"`
+	}
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.UpdateSetting(UpdateSettingInput{
+		Category: r.PathValue("category"),
+		Key:      r.PathValue("key"),
+		Value:    input.Value,
+	}))
+}
+
+func (a *App) adminBatchUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var input BatchUpdateSettingsInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	writeAdminResult(w, nil, a.BatchUpdateSettings(input))
+}
+
+// redactSecretSettings 隐藏敏感设置（鉴权 Token、管理 Token）的值，只返回是否已设置
+func redactSecretSettings(settings []SettingInfo) []SettingInfo {
+	for i := range settings {
+		if service.IsSecretSetting(settings[i].Category, settings[i].Key) {
+			settings[i].ValueSet = settings[i].Value != ""
+			settings[i].Value = ""
+		}
+	}
+	return settings
+}
+
+func (a *App) adminResetCategorySettings(w http.ResponseWriter, r *http.Request) {
+	writeAdminResult(w, nil, a.ResetCategorySettings(r.PathValue("category")))
+}
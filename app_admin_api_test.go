@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/tracking"
+)
+
+// newAdminTestApp 创建带 SQLite 存储（客户端 Key、设置）的最小 App，并返回注册了管理 API 的 mux
+func newAdminTestApp(t *testing.T, adminToken string) (*App, *http.ServeMux) {
+	t.Helper()
+
+	tracker, err := tracking.NewUsageTracker(&tracking.Config{
+		Enabled:      true,
+		DatabasePath: filepath.Join(t.TempDir(), "usage.db"),
+	})
+	if err != nil {
+		t.Fatalf("创建 UsageTracker 失败: %v", err)
+	}
+	t.Cleanup(func() { tracker.Close() })
+
+	db := tracker.GetDB()
+	app := &App{
+		config:       &config.Config{Auth: config.AuthConfig{AdminToken: adminToken}},
+		logger:       slog.Default(),
+		usageTracker: tracker,
+	}
+	app.clientKeyStore = store.NewSQLiteClientKeyStore(db)
+	app.clientKeyService = service.NewClientKeyService(app.clientKeyStore)
+	app.settingsStore = store.NewSQLiteSettingsStore(db)
+	app.settingsService = service.NewSettingsService(app.settingsStore)
+	if err := app.settingsService.InitDefaults(context.Background()); err != nil {
+		t.Fatalf("初始化默认设置失败: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	app.registerAdminAPI(mux)
+	return app, mux
+}
+
+// adminRequest 发送管理 API 请求，token 为空时不带 Authorization 头
+func adminRequest(t *testing.T, mux *http.ServeMux, method, path, token, body string) (int, AdminAPIResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, adminAPIPrefix+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var resp AdminAPIResponse
+	if strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v (%s)", err, rec.Body.String())
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestAdminAPI_Auth(t *testing.T) {
+	_, disabled := newAdminTestApp(t, "")
+	if code, resp := adminRequest(t, disabled, "GET", "/client-keys", "anything", ""); code != http.StatusForbidden || resp.Success {
+		t.Errorf("未配置 admin_token 时应返回 403, 实际 %d %+v", code, resp)
+	}
+
+	_, mux := newAdminTestApp(t, "adm")
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"缺少 Token", "", http.StatusUnauthorized},
+		{"错误 Token", "wrong", http.StatusUnauthorized},
+		{"正确 Token", "adm", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code, _ := adminRequest(t, mux, "GET", "/client-keys", tt.token, ""); code != tt.want {
+			t.Errorf("%s: 期望 %d, 实际 %d", tt.name, tt.want, code)
+		}
+	}
+
+	// 非 Bearer 格式的 Authorization 头
+	req := httptest.NewRequest("GET", adminAPIPrefix+"/client-keys", nil)
+	req.Header.Set("Authorization", "Basic YWRtOg==")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Basic 鉴权应返回 401, 实际 %d", rec.Code)
+	}
+}
+
+func TestAdminAPI_Routing(t *testing.T) {
+	_, mux := newAdminTestApp(t, "adm")
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"GET", "/client-keys", http.StatusOK},
+		{"GET", "/settings/categories", http.StatusOK},
+		{"GET", "/settings/auth", http.StatusOK},
+		{"GET", "/not-a-route", http.StatusNotFound},
+		{"PATCH", "/client-keys", http.StatusMethodNotAllowed},
+		{"DELETE", "/settings", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if code, _ := adminRequest(t, mux, tt.method, tt.path, "adm", ""); code != tt.want {
+			t.Errorf("%s %s: 期望 %d, 实际 %d", tt.method, tt.path, tt.want, code)
+		}
+	}
+}
+
+func TestAdminAPI_ErrorMapping(t *testing.T) {
+	app, mux := newAdminTestApp(t, "adm")
+
+	create := `{"name":"alice","key":"sk-alice-0123456789"}`
+	if code, resp := adminRequest(t, mux, "POST", "/client-keys", "adm", create); code != http.StatusCreated {
+		t.Fatalf("创建客户端 Key 失败: %d %+v", code, resp)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"重复创建", "POST", "/client-keys", create, http.StatusConflict},
+		{"获取不存在的 Key", "GET", "/client-keys/bob", "", http.StatusNotFound},
+		{"删除不存在的 Key", "DELETE", "/client-keys/bob", "", http.StatusNotFound},
+		{"获取不存在的设置", "GET", "/settings/auth/missing", "", http.StatusNotFound},
+		{"无效请求体", "POST", "/client-keys", "{", http.StatusBadRequest},
+		{"参数校验失败", "POST", "/client-keys", `{"name":"carol","expires_at":"tomorrow"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		code, resp := adminRequest(t, mux, tt.method, tt.path, "adm", tt.body)
+		if code != tt.want || resp.Success || resp.Error == "" {
+			t.Errorf("%s: 期望 %d, 实际 %d %+v", tt.name, tt.want, code, resp)
+		}
+	}
+
+	// 数据库故障返回 500
+	if _, err := app.usageTracker.GetDB().Exec("DROP TABLE settings"); err != nil {
+		t.Fatalf("删除设置表失败: %v", err)
+	}
+	if code, resp := adminRequest(t, mux, "GET", "/settings/auth", "adm", ""); code != http.StatusInternalServerError {
+		t.Errorf("数据库故障应返回 500, 实际 %d %+v", code, resp)
+	}
+}
+
+func TestAdminAPI_RedactSecretSettings(t *testing.T) {
+	_, mux := newAdminTestApp(t, "adm")
+
+	update := `{"value":"sk-proxy-secret"}`
+	if code, resp := adminRequest(t, mux, "PUT", "/settings/auth/token", "adm", update); code != http.StatusOK {
+		t.Fatalf("更新设置失败: %d %+v", code, resp)
+	}
+
+	for _, path := range []string{"/settings", "/settings/auth", "/settings/auth/token", "/settings/auth/admin_token"} {
+		req := httptest.NewRequest("GET", adminAPIPrefix+path, nil)
+		req.Header.Set("Authorization", "Bearer adm")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: 请求失败 %d", path, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "sk-proxy-secret") {
+			t.Errorf("%s: 响应中不应包含 Token 明文", path)
+		}
+	}
+
+	var resp struct {
+		Data []SettingInfo `json:"data"`
+	}
+	req := httptest.NewRequest("GET", adminAPIPrefix+"/settings/auth", nil)
+	req.Header.Set("Authorization", "Bearer adm")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	for _, s := range resp.Data {
+		switch s.Key {
+		case "token":
+			if s.Value != "" || !s.ValueSet {
+				t.Errorf("token 应脱敏并标记已设置: %+v", s)
+			}
+		case "admin_token":
+			if s.Value != "" || s.ValueSet {
+				t.Errorf("未设置的 admin_token 不应标记已设置: %+v", s)
+			}
+		case "enabled":
+			if s.Value == "" {
+				t.Errorf("非敏感设置不应脱敏: %+v", s)
+			}
+		}
+	}
+}
+
+func TestAdminErrorStatus(t *testing.T) {
+	_, statErr := os.Stat(filepath.Join(t.TempDir(), "missing"))
+
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("端点 'a' %w", store.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("获取失败: %w", fmt.Errorf("端点%w: a", store.ErrNotFound)), http.StatusNotFound},
+		{fmt.Errorf("端点 'a' %w", store.ErrAlreadyExists), http.StatusConflict},
+		{fmt.Errorf("读取失败: %w", statErr), http.StatusNotFound},
+		{fmt.Errorf("写入失败: %w", &fs.PathError{Op: "write", Path: "x", Err: errors.New("disk full")}), http.StatusInternalServerError},
+		{fmt.Errorf("查询失败: %w", context.DeadlineExceeded), http.StatusInternalServerError},
+		{errors.New("价格不能为负数"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if got := adminErrorStatus(tt.err); got != tt.want {
+			t.Errorf("%v: 期望 %d, 实际 %d", tt.err, tt.want, got)
+		}
+	}
+}
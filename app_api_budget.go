@@ -0,0 +1,211 @@
+// app_api_budget.go - 预算管理 API (Wails Bindings)
+// 提供预算的增删改查功能，并返回各预算当前周期的消费状态
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/store"
+)
+
+// ============================================================
+// 预算管理 API (SQLite)
+// ============================================================
+
+// BudgetInfo 预算信息（给前端用的结构体，包含当前周期消费状态）
+type BudgetInfo struct {
+	ID              int64   `json:"id"`
+	Name            string  `json:"name"`
+	Scope           string  `json:"scope"`  // global/endpoint/channel/client_key
+	Target          string  `json:"target"` // 端点名/渠道名/客户端 Key 名称
+	Period          string  `json:"period"` // daily/weekly/monthly
+	Description     string  `json:"description"`
+	SoftLimitUSD    float64 `json:"soft_limit_usd"`
+	SoftLimitTokens int64   `json:"soft_limit_tokens"`
+	HardLimitUSD    float64 `json:"hard_limit_usd"`
+	HardLimitTokens int64   `json:"hard_limit_tokens"`
+	Enabled         bool    `json:"enabled"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+
+	// 当前周期状态（未启用或尚未统计时为空）
+	State        string  `json:"state"` // ok/soft_exceeded/hard_exceeded
+	SpentUSD     float64 `json:"spent_usd"`
+	SpentTokens  int64   `json:"spent_tokens"`
+	RequestCount int64   `json:"request_count"`
+	PeriodStart  string  `json:"period_start"`
+	PeriodEnd    string  `json:"period_end"`
+}
+
+// BudgetInput 创建/更新预算的输入参数
+type BudgetInput struct {
+	Name            string  `json:"name"`
+	Scope           string  `json:"scope"`  // 默认 global
+	Target          string  `json:"target"` // scope 非 global 时必填
+	Period          string  `json:"period"` // 默认 monthly
+	Description     string  `json:"description"`
+	SoftLimitUSD    float64 `json:"soft_limit_usd"`
+	SoftLimitTokens int64   `json:"soft_limit_tokens"`
+	HardLimitUSD    float64 `json:"hard_limit_usd"`
+	HardLimitTokens int64   `json:"hard_limit_tokens"`
+	Enabled         *bool   `json:"enabled"` // 未指定时默认启用
+}
+
+// GetBudgets 获取所有预算及其当前状态
+func (a *App) GetBudgets() ([]BudgetInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.budgetService == nil {
+		return nil, fmt.Errorf("预算服务未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.budgetService.ListBudgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BudgetInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, budgetRecordToInfo(r, a.budgetService.GetStatus(r.Name)))
+	}
+
+	return result, nil
+}
+
+// GetBudget 获取单个预算及其当前状态
+func (a *App) GetBudget(name string) (BudgetInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.budgetService == nil {
+		return BudgetInfo{}, fmt.Errorf("预算服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := a.budgetService.GetBudget(ctx, name)
+	if err != nil {
+		return BudgetInfo{}, err
+	}
+	if record == nil {
+		return BudgetInfo{}, fmt.Errorf("预算 '%s' %w", name, store.ErrNotFound)
+	}
+
+	return budgetRecordToInfo(record, a.budgetService.GetStatus(record.Name)), nil
+}
+
+// CreateBudget 创建预算
+func (a *App) CreateBudget(input BudgetInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.budgetService == nil {
+		return fmt.Errorf("预算服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := a.budgetService.CreateBudget(ctx, budgetInputToRecord(input.Name, input))
+	return err
+}
+
+// UpdateBudget 更新预算
+func (a *App) UpdateBudget(name string, input BudgetInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.budgetService == nil {
+		return fmt.Errorf("预算服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.budgetService.UpdateBudget(ctx, budgetInputToRecord(name, input))
+}
+
+// DeleteBudget 删除预算
+func (a *App) DeleteBudget(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.budgetService == nil {
+		return fmt.Errorf("预算服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.budgetService.DeleteBudget(ctx, name)
+}
+
+// budgetInputToRecord 将前端输入转换为数据库记录
+func budgetInputToRecord(name string, input BudgetInput) *store.BudgetRecord {
+	record := &store.BudgetRecord{
+		Name:            strings.TrimSpace(name),
+		Scope:           strings.TrimSpace(input.Scope),
+		Target:          strings.TrimSpace(input.Target),
+		Period:          strings.TrimSpace(input.Period),
+		Description:     input.Description,
+		SoftLimitUSD:    input.SoftLimitUSD,
+		SoftLimitTokens: input.SoftLimitTokens,
+		HardLimitUSD:    input.HardLimitUSD,
+		HardLimitTokens: input.HardLimitTokens,
+		Enabled:         input.Enabled == nil || *input.Enabled,
+	}
+
+	if record.Scope == "" {
+		record.Scope = store.BudgetScopeGlobal
+	}
+	if record.Period == "" {
+		record.Period = store.BudgetPeriodMonthly
+	}
+
+	return record
+}
+
+// budgetRecordToInfo 将数据库记录和当前状态转换为前端 Info 结构
+func budgetRecordToInfo(r *store.BudgetRecord, st *service.BudgetStatus) BudgetInfo {
+	info := BudgetInfo{
+		ID:              r.ID,
+		Name:            r.Name,
+		Scope:           r.Scope,
+		Target:          r.Target,
+		Period:          r.Period,
+		Description:     r.Description,
+		SoftLimitUSD:    r.SoftLimitUSD,
+		SoftLimitTokens: r.SoftLimitTokens,
+		HardLimitUSD:    r.HardLimitUSD,
+		HardLimitTokens: r.HardLimitTokens,
+		Enabled:         r.Enabled,
+	}
+
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	if !r.UpdatedAt.IsZero() {
+		info.UpdatedAt = r.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	if st != nil {
+		info.State = st.State
+		info.SpentUSD = st.SpentUSD
+		info.SpentTokens = st.SpentTokens
+		info.RequestCount = st.RequestCount
+		info.PeriodStart = st.PeriodStart.Format(time.RFC3339)
+		info.PeriodEnd = st.PeriodEnd.Format(time.RFC3339)
+	}
+
+	return info
+}
@@ -0,0 +1,147 @@
+// app_api_capture.go - 请求抓取 API (Wails Bindings)
+// 提供抓取记录的列表、查看和删除功能（重放使用 replay 子命令）
+
+package main
+
+import (
+	"fmt"
+
+	"cc-forwarder/internal/capture"
+)
+
+// ============================================================
+// 请求抓取 API (JSON 文件)
+// ============================================================
+
+// CaptureSummaryInfo 抓取记录摘要（给前端用的结构体）
+type CaptureSummaryInfo struct {
+	RequestID  string `json:"request_id"`
+	CreatedAt  string `json:"created_at"`
+	Attempts   int    `json:"attempts"`
+	Endpoint   string `json:"endpoint"`
+	Path       string `json:"path"`
+	StatusCode int    `json:"status_code"`
+	Stream     bool   `json:"stream"`
+	Error      string `json:"error"`
+	Size       int64  `json:"size"`
+}
+
+// CaptureAttemptInfo 一次端点尝试的请求和响应
+type CaptureAttemptInfo struct {
+	Endpoint        string              `json:"endpoint"`
+	StartedAt       string              `json:"started_at"`
+	DurationMs      int64               `json:"duration_ms"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	RequestBody     string              `json:"request_body"`
+	StatusCode      int                 `json:"status_code"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+	ResponseBody    string              `json:"response_body"` // 原始响应体（压缩响应为二进制内容）
+	ChunkCount      int                 `json:"chunk_count"`
+	Complete        bool                `json:"complete"`
+	Truncated       bool                `json:"truncated"`
+	Error           string              `json:"error"`
+}
+
+// CaptureDetailInfo 抓取记录详情
+type CaptureDetailInfo struct {
+	RequestID string               `json:"request_id"`
+	CreatedAt string               `json:"created_at"`
+	Attempts  []CaptureAttemptInfo `json:"attempts"`
+}
+
+// GetCaptures 获取所有抓取记录摘要（按时间倒序）
+func (a *App) GetCaptures() ([]CaptureSummaryInfo, error) {
+	captureStore, err := a.getCaptureStore()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := captureStore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CaptureSummaryInfo, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, CaptureSummaryInfo{
+			RequestID:  s.RequestID,
+			CreatedAt:  s.CreatedAt.Format("2006-01-02 15:04:05"),
+			Attempts:   s.Attempts,
+			Endpoint:   s.Endpoint,
+			Path:       s.Path,
+			StatusCode: s.StatusCode,
+			Stream:     s.Stream,
+			Error:      s.Error,
+			Size:       s.Size,
+		})
+	}
+	return result, nil
+}
+
+// GetCapture 获取抓取记录详情
+func (a *App) GetCapture(requestID string) (CaptureDetailInfo, error) {
+	c, err := a.loadCapture(requestID)
+	if err != nil {
+		return CaptureDetailInfo{}, err
+	}
+
+	info := CaptureDetailInfo{
+		RequestID: c.RequestID,
+		CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+		Attempts:  make([]CaptureAttemptInfo, 0, len(c.Attempts)),
+	}
+	for _, attempt := range c.Attempts {
+		attemptInfo := CaptureAttemptInfo{
+			Endpoint:       attempt.Endpoint,
+			StartedAt:      attempt.StartedAt.Format("2006-01-02 15:04:05.000"),
+			DurationMs:     attempt.DurationMs,
+			Method:         attempt.Request.Method,
+			URL:            attempt.Request.URL,
+			RequestHeaders: attempt.Request.Header,
+			RequestBody:    string(attempt.Request.Body),
+			Truncated:      attempt.Request.Truncated,
+			Error:          attempt.Error,
+		}
+		if resp := attempt.Response; resp != nil {
+			attemptInfo.StatusCode = resp.StatusCode
+			attemptInfo.ResponseHeaders = resp.Header
+			attemptInfo.ResponseBody = string(resp.Body())
+			attemptInfo.ChunkCount = len(resp.Chunks)
+			attemptInfo.Complete = resp.Complete
+			attemptInfo.Truncated = attemptInfo.Truncated || resp.Truncated
+		}
+		info.Attempts = append(info.Attempts, attemptInfo)
+	}
+	return info, nil
+}
+
+// DeleteCapture 删除抓取记录
+func (a *App) DeleteCapture(requestID string) error {
+	captureStore, err := a.getCaptureStore()
+	if err != nil {
+		return err
+	}
+	return captureStore.Delete(requestID)
+}
+
+// loadCapture 读取原始抓取记录
+func (a *App) loadCapture(requestID string) (*capture.Capture, error) {
+	captureStore, err := a.getCaptureStore()
+	if err != nil {
+		return nil, err
+	}
+	return captureStore.Get(requestID)
+}
+
+// getCaptureStore 获取抓取存储
+func (a *App) getCaptureStore() (*capture.Store, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.captureRecorder == nil {
+		return nil, fmt.Errorf("请求抓取未初始化")
+	}
+	return a.captureRecorder.Store(), nil
+}
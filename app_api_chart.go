@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"sort"
 	"time"
 )
 
@@ -212,3 +213,40 @@ func (a *App) GetEndpointCosts() []EndpointCostItem {
 
 	return result
 }
+
+// ============================================================
+// 端点首 Token 耗时图表 API
+// ============================================================
+
+// EndpointTTFTItem 端点首 Token 耗时分位数（最近 monitor.TTFTSampleSize 个请求）
+type EndpointTTFTItem struct {
+	Name    string `json:"name"`
+	P50Ms   int64  `json:"p50_ms"`
+	P95Ms   int64  `json:"p95_ms"`
+	Samples int    `json:"samples"`
+}
+
+// GetEndpointTTFTChart 获取各端点首 Token 耗时的 p50/p95
+func (a *App) GetEndpointTTFTChart() []EndpointTTFTItem {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.monitoringMiddleware == nil {
+		return []EndpointTTFTItem{}
+	}
+
+	percentiles := a.monitoringMiddleware.GetMetrics().GetEndpointTTFTPercentiles()
+
+	result := make([]EndpointTTFTItem, 0, len(percentiles))
+	for name, p := range percentiles {
+		result = append(result, EndpointTTFTItem{
+			Name:    name,
+			P50Ms:   p.P50.Milliseconds(),
+			P95Ms:   p.P95.Milliseconds(),
+			Samples: p.Samples,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
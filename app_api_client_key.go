@@ -0,0 +1,209 @@
+// app_api_client_key.go - 客户端 API Key 管理 API (Wails Bindings)
+// 提供多客户端 Key 的增删改查功能，请求按 Key 名称统计用量
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// ============================================================
+// 客户端 API Key 管理 API (SQLite)
+// ============================================================
+
+// ClientKeyInfo 客户端 Key 信息（给前端用的结构体）
+type ClientKeyInfo struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	ExpiresAt   string `json:"expires_at"` // 空字符串表示永不过期
+	Expired     bool   `json:"expired"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// ClientKeyInput 创建/更新客户端 Key 的输入参数
+type ClientKeyInput struct {
+	Name        string `json:"name"`
+	Key         string `json:"key"` // 创建时为空则自动生成，更新时为空则保留原值
+	Description string `json:"description"`
+	Enabled     *bool  `json:"enabled"`    // 未指定时默认启用
+	ExpiresAt   string `json:"expires_at"` // RFC3339 或 "2006-01-02 15:04:05"（本地时间），空表示永不过期
+}
+
+// GetClientKeys 获取所有客户端 Key
+func (a *App) GetClientKeys() ([]ClientKeyInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return nil, fmt.Errorf("客户端 Key 服务未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.clientKeyService.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ClientKeyInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, clientKeyRecordToInfo(r))
+	}
+
+	return result, nil
+}
+
+// GetClientKey 获取单个客户端 Key
+func (a *App) GetClientKey(name string) (ClientKeyInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return ClientKeyInfo{}, fmt.Errorf("客户端 Key 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := a.clientKeyService.GetKey(ctx, name)
+	if err != nil {
+		return ClientKeyInfo{}, err
+	}
+	if record == nil {
+		return ClientKeyInfo{}, fmt.Errorf("客户端 Key '%s' %w", name, store.ErrNotFound)
+	}
+
+	return clientKeyRecordToInfo(record), nil
+}
+
+// CreateClientKey 创建客户端 Key，返回创建结果（包含自动生成的 Key 值）
+func (a *App) CreateClientKey(input ClientKeyInput) (ClientKeyInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return ClientKeyInfo{}, fmt.Errorf("客户端 Key 服务未启用")
+	}
+
+	record, err := clientKeyInputToRecord(input.Name, input)
+	if err != nil {
+		return ClientKeyInfo{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	created, err := a.clientKeyService.CreateKey(ctx, record)
+	if err != nil {
+		return ClientKeyInfo{}, err
+	}
+
+	return clientKeyRecordToInfo(created), nil
+}
+
+// UpdateClientKey 更新客户端 Key
+func (a *App) UpdateClientKey(name string, input ClientKeyInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return fmt.Errorf("客户端 Key 服务未启用")
+	}
+
+	record, err := clientKeyInputToRecord(name, input)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.clientKeyService.UpdateKey(ctx, record)
+}
+
+// ToggleClientKey 启用/禁用客户端 Key
+func (a *App) ToggleClientKey(name string, enabled bool) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return fmt.Errorf("客户端 Key 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.clientKeyService.ToggleKey(ctx, name, enabled)
+}
+
+// DeleteClientKey 删除客户端 Key
+func (a *App) DeleteClientKey(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clientKeyService == nil {
+		return fmt.Errorf("客户端 Key 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.clientKeyService.DeleteKey(ctx, name)
+}
+
+// clientKeyInputToRecord 将前端输入转换为数据库记录
+func clientKeyInputToRecord(name string, input ClientKeyInput) (*store.ClientKeyRecord, error) {
+	record := &store.ClientKeyRecord{
+		Name:        strings.TrimSpace(name),
+		Key:         input.Key,
+		Description: input.Description,
+		Enabled:     input.Enabled == nil || *input.Enabled,
+	}
+
+	if expires := strings.TrimSpace(input.ExpiresAt); expires != "" {
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			t, err = time.ParseInLocation("2006-01-02 15:04:05", expires, time.Local)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("过期时间格式无效: %s", expires)
+		}
+		record.ExpiresAt = &t
+	}
+
+	return record, nil
+}
+
+// clientKeyRecordToInfo 将数据库记录转换为前端 Info 结构
+func clientKeyRecordToInfo(r *store.ClientKeyRecord) ClientKeyInfo {
+	info := ClientKeyInfo{
+		ID:          r.ID,
+		Name:        r.Name,
+		Key:         r.Key,
+		Description: r.Description,
+		Enabled:     r.Enabled,
+		Expired:     r.IsExpired(time.Now()),
+	}
+
+	if r.ExpiresAt != nil {
+		info.ExpiresAt = r.ExpiresAt.Format(time.RFC3339)
+	}
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	if !r.UpdatedAt.IsZero() {
+		info.UpdatedAt = r.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return info
+}
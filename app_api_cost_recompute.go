@@ -0,0 +1,152 @@
+// app_api_cost_recompute.go - 成本重算 API (Wails Bindings)
+// 修正模型定价或端点倍率后，在后台按时间范围重算请求成本
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/tracking"
+)
+
+// CostRecomputeInput 成本重算参数
+type CostRecomputeInput struct {
+	StartTime    string `json:"start_time"`    // 请求开始时间下界（包含），不带时区时使用配置时区
+	EndTime      string `json:"end_time"`      // 请求开始时间上界（不包含），为空时为当前时间
+	ModelName    string `json:"model_name"`    // 只重算该模型，为空时不过滤
+	EndpointName string `json:"endpoint_name"` // 只重算该端点，为空时不过滤
+}
+
+// CostRecomputeStatus 成本重算任务状态
+type CostRecomputeStatus struct {
+	ID           int64   `json:"id"`
+	Status       string  `json:"status"` // running / completed / failed / cancelled
+	StartTime    string  `json:"start_time"`
+	EndTime      string  `json:"end_time"`
+	ModelName    string  `json:"model_name"`
+	EndpointName string  `json:"endpoint_name"`
+	Total        int64   `json:"total"`       // 范围内的记录数
+	Processed    int64   `json:"processed"`   // 已处理记录数
+	Updated      int64   `json:"updated"`     // 成本变化并已更新的记录数
+	Percent      float64 `json:"percent"`     // 进度百分比
+	CostBefore   float64 `json:"cost_before"` // 已处理记录重算前的总成本
+	CostAfter    float64 `json:"cost_after"`  // 已处理记录重算后的总成本
+	Error        string  `json:"error"`
+	StartedAt    string  `json:"started_at"`
+	FinishedAt   string  `json:"finished_at"`
+}
+
+// StartCostRecompute 启动后台成本重算，同一时间只允许一个任务
+// 每条记录按其开始时间生效的定价版本和当前端点倍率重新计算 *_cost_usd 列
+func (a *App) StartCostRecompute(input CostRecomputeInput) (CostRecomputeStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.costRecompute == nil {
+		return CostRecomputeStatus{}, fmt.Errorf("成本重算未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	loc := a.timezoneLocation()
+	opts := tracking.CostRecomputeOptions{
+		ModelName:    input.ModelName,
+		EndpointName: input.EndpointName,
+	}
+
+	startTime, err := parseTimeWithLocation(input.StartTime, loc)
+	if err != nil {
+		return CostRecomputeStatus{}, fmt.Errorf("开始时间格式无效: %w", err)
+	}
+	opts.StartTime = startTime
+
+	if input.EndTime != "" {
+		endTime, err := parseTimeWithLocation(input.EndTime, loc)
+		if err != nil {
+			return CostRecomputeStatus{}, fmt.Errorf("结束时间格式无效: %w", err)
+		}
+		opts.EndTime = endTime
+	}
+
+	// 重算使用 UsageTracker 中的定价和倍率，先同步确保与数据库一致（端点修改后的同步是异步的）
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	a.syncPricingToTracker(ctx)
+	a.syncEndpointMultipliersToTracker(ctx)
+
+	job, err := a.costRecompute.Start(opts)
+	if err != nil {
+		return CostRecomputeStatus{}, err
+	}
+
+	return costRecomputeJobToStatus(&job, loc), nil
+}
+
+// GetCostRecomputeStatus 获取当前或最近一次成本重算任务，没有任务时返回 nil
+func (a *App) GetCostRecomputeStatus() *CostRecomputeStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.costRecompute == nil {
+		return nil
+	}
+
+	job := a.costRecompute.Status()
+	if job == nil {
+		return nil
+	}
+
+	status := costRecomputeJobToStatus(job, a.timezoneLocation())
+	return &status
+}
+
+// CancelCostRecompute 取消正在运行的成本重算（已更新的批次不回滚）
+func (a *App) CancelCostRecompute() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.costRecompute == nil {
+		return fmt.Errorf("成本重算未启用")
+	}
+
+	return a.costRecompute.Cancel()
+}
+
+// timezoneLocation 返回配置的时区，未配置或无效时使用本地时区
+func (a *App) timezoneLocation() *time.Location {
+	if a.config != nil && a.config.Timezone != "" {
+		if loc, err := time.LoadLocation(a.config.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// costRecomputeJobToStatus 将重算任务快照转换为前端结构
+func costRecomputeJobToStatus(job *service.CostRecomputeJob, loc *time.Location) CostRecomputeStatus {
+	status := CostRecomputeStatus{
+		ID:           job.ID,
+		Status:       job.Status,
+		StartTime:    job.StartTime.In(loc).Format("2006-01-02 15:04:05"),
+		EndTime:      job.EndTime.In(loc).Format("2006-01-02 15:04:05"),
+		ModelName:    job.ModelName,
+		EndpointName: job.EndpointName,
+		Total:        job.Progress.Total,
+		Processed:    job.Progress.Processed,
+		Updated:      job.Progress.Updated,
+		CostBefore:   job.Progress.CostBefore,
+		CostAfter:    job.Progress.CostAfter,
+		Error:        job.Error,
+		StartedAt:    job.StartedAt.In(loc).Format("2006-01-02 15:04:05"),
+	}
+	if job.Progress.Total > 0 {
+		status.Percent = float64(job.Progress.Processed) / float64(job.Progress.Total) * 100
+	} else if job.Status == service.CostRecomputeCompleted {
+		status.Percent = 100
+	}
+	if job.FinishedAt != nil {
+		status.FinishedAt = job.FinishedAt.In(loc).Format("2006-01-02 15:04:05")
+	}
+	return status
+}
@@ -6,6 +6,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"cc-forwarder/internal/endpoint"
 )
 
 // ============================================================
@@ -25,6 +27,53 @@ type EndpointInfo struct {
 	LastCheck       string  `json:"last_check"`
 	ResponseTimeMs  float64 `json:"response_time_ms"`
 	ConsecutiveFail int     `json:"consecutive_fail"`
+	Weight          int     `json:"weight"`          // 权重（weighted 策略）
+	InFlight        int     `json:"in_flight"`       // 在途请求数（least_outstanding 策略）
+	EWMALatencyMs   float64 `json:"ewma_latency_ms"` // 真实请求延迟 EWMA（ewma 策略）
+	// 熔断器状态（closed/open/half_open）及滚动窗口统计
+	CircuitState        string  `json:"circuit_state"`
+	CircuitReason       string  `json:"circuit_reason"`         // 最近一次熔断的原因
+	CircuitOpenedAt     string  `json:"circuit_opened_at"`      // 最近一次熔断时间
+	CircuitHalfOpenAt   string  `json:"circuit_half_open_at"`   // 熔断中时进入半开的时间
+	CircuitRequests     int     `json:"circuit_requests"`       // 窗口内请求数
+	CircuitErrorRate    float64 `json:"circuit_error_rate"`     // 窗口内错误率
+	CircuitSlowCallRate float64 `json:"circuit_slow_call_rate"` // 窗口内慢请求比例
+	CircuitAvgLatencyMs float64 `json:"circuit_avg_latency_ms"` // 窗口内平均响应头耗时
+	// 上游最近一次返回的限流快照（未返回过限流响应头时为空）
+	RateLimit *RateLimitInfo `json:"rate_limit,omitempty"`
+	// 端点容量上限（max_concurrency、rpm、tpm）及当前用量
+	Capacity *EndpointCapacityInfo `json:"capacity"`
+}
+
+// EndpointCapacityInfo 端点的容量上限和当前用量，上限为 0 表示不限制
+type EndpointCapacityInfo struct {
+	InFlight          int   `json:"in_flight"`           // 转发到该端点、尚未结束的请求数
+	MaxConcurrency    int   `json:"max_concurrency"`     // 最大并发请求数
+	RequestsPerMinute int   `json:"requests_per_minute"` // 最近一分钟的请求数
+	RPM               int   `json:"rpm"`                 // 每分钟请求数上限
+	TokensPerMinute   int64 `json:"tokens_per_minute"`   // 最近一分钟消耗的 Token 数
+	TPM               int   `json:"tpm"`                 // 每分钟 Token 数上限
+	Saturated         bool  `json:"saturated"`           // 已达到任一上限（选择时跳过）
+}
+
+// RateLimitInfo 端点最近一次响应携带的上游限流信息（retry-after、anthropic-ratelimit-*）
+type RateLimitInfo struct {
+	Requests          *RateLimitWindowInfo `json:"requests,omitempty"`
+	Tokens            *RateLimitWindowInfo `json:"tokens,omitempty"`
+	InputTokens       *RateLimitWindowInfo `json:"input_tokens,omitempty"`
+	OutputTokens      *RateLimitWindowInfo `json:"output_tokens,omitempty"`
+	RetryAfterSeconds float64              `json:"retry_after_seconds,omitempty"` // 限流剩余等待时间
+	RetryAt           string               `json:"retry_at,omitempty"`            // 限流解除时间
+	NearLimit         bool                 `json:"near_limit"`                    // 剩余额度接近上限（选择时排到后面）
+	StatusCode        int                  `json:"status_code"`                   // 最近一次记录的响应状态码
+	UpdatedAt         string               `json:"updated_at"`
+}
+
+// RateLimitWindowInfo 一类额度（请求数或 Token 数）的状态
+type RateLimitWindowInfo struct {
+	Limit     int64  `json:"limit"`
+	Remaining int64  `json:"remaining"`
+	Reset     string `json:"reset,omitempty"`
 }
 
 // GetEndpoints 获取所有端点状态
@@ -48,6 +97,8 @@ func (a *App) GetEndpoints() []EndpointInfo {
 		}
 	}
 
+	inFlight := a.endpointManager.GetInFlightCounts()
+
 	for _, ep := range endpoints {
 		info := EndpointInfo{
 			Name:            ep.Config.Name,
@@ -59,6 +110,12 @@ func (a *App) GetEndpoints() []EndpointInfo {
 			Healthy:         ep.Status.Healthy,
 			ConsecutiveFail: ep.Status.ConsecutiveFails,
 			ResponseTimeMs:  float64(ep.Status.ResponseTime.Milliseconds()),
+			Weight:          ep.Config.Weight,
+			InFlight:        inFlight[ep.Config.Name],
+			EWMALatencyMs:   float64(ep.Status.EWMALatency.Milliseconds()),
+		}
+		if info.Weight <= 0 {
+			info.Weight = 1
 		}
 
 		// 获取组是否激活
@@ -70,12 +127,71 @@ func (a *App) GetEndpoints() []EndpointInfo {
 			info.LastCheck = ep.Status.LastCheck.Format(time.RFC3339)
 		}
 
+		circuit := a.endpointManager.GetCircuitStatus(ep.Config.Name)
+		info.CircuitState = string(circuit.State)
+		info.CircuitReason = circuit.Reason
+		info.CircuitRequests = circuit.Requests
+		info.CircuitErrorRate = circuit.ErrorRate
+		info.CircuitSlowCallRate = circuit.SlowCallRate
+		info.CircuitAvgLatencyMs = float64(circuit.AvgLatency.Milliseconds())
+		if !circuit.OpenedAt.IsZero() {
+			info.CircuitOpenedAt = circuit.OpenedAt.Format(time.RFC3339)
+		}
+		if !circuit.HalfOpenAt.IsZero() {
+			info.CircuitHalfOpenAt = circuit.HalfOpenAt.Format(time.RFC3339)
+		}
+
+		if snapshot := a.endpointManager.GetRateLimitSnapshot(ep.Config.Name); !snapshot.IsZero() {
+			info.RateLimit = buildRateLimitInfo(snapshot, a.endpointManager.IsNearRateLimit(ep.Config.Name))
+		}
+
+		usage := a.endpointManager.GetEndpointUsage(ep.Config.Name)
+		saturated, _ := usage.Saturated(ep.Config)
+		info.Capacity = &EndpointCapacityInfo{
+			InFlight:          usage.InFlight,
+			MaxConcurrency:    ep.Config.MaxConcurrency,
+			RequestsPerMinute: usage.RequestsPerMinute,
+			RPM:               ep.Config.RPM,
+			TokensPerMinute:   usage.TokensPerMinute,
+			TPM:               ep.Config.TPM,
+			Saturated:         saturated,
+		}
+
 		result = append(result, info)
 	}
 
 	return result
 }
 
+// buildRateLimitInfo 转换限流快照
+func buildRateLimitInfo(snapshot endpoint.RateLimitSnapshot, nearLimit bool) *RateLimitInfo {
+	window := func(w endpoint.RateLimitWindow) *RateLimitWindowInfo {
+		if w.Limit <= 0 {
+			return nil
+		}
+		info := &RateLimitWindowInfo{Limit: w.Limit, Remaining: w.Remaining}
+		if !w.Reset.IsZero() {
+			info.Reset = w.Reset.Format(time.RFC3339)
+		}
+		return info
+	}
+
+	info := &RateLimitInfo{
+		Requests:     window(snapshot.Requests),
+		Tokens:       window(snapshot.Tokens),
+		InputTokens:  window(snapshot.InputTokens),
+		OutputTokens: window(snapshot.OutputTokens),
+		NearLimit:    nearLimit,
+		StatusCode:   snapshot.StatusCode,
+		UpdatedAt:    snapshot.UpdatedAt.Format(time.RFC3339),
+	}
+	if remaining := time.Until(snapshot.RetryAt); remaining > 0 {
+		info.RetryAfterSeconds = remaining.Seconds()
+		info.RetryAt = snapshot.RetryAt.Format(time.RFC3339)
+	}
+	return info
+}
+
 // SetEndpointPriority 设置端点优先级
 func (a *App) SetEndpointPriority(name string, priority int) error {
 	a.mu.RLock()
@@ -162,6 +278,14 @@ type KeyInfo struct {
 	Name     string `json:"name"`      // Key 名称
 	Value    string `json:"value"`     // 脱敏后的值 (masked)
 	IsActive bool   `json:"is_active"` // 是否为当前使用的 Key
+
+	// 自动轮换状态
+	Status         string `json:"status"`                   // available/cooldown/invalid
+	CooldownUntil  string `json:"cooldown_until,omitempty"` // 冷却结束时间
+	UsageCount     int64  `json:"usage_count"`              // 分配次数
+	LastStatusCode int    `json:"last_status_code,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	LastUsedAt     string `json:"last_used_at,omitempty"`
 }
 
 // EndpointKeysInfo 端点 Key 概览
@@ -171,6 +295,8 @@ type EndpointKeysInfo struct {
 	ApiKeys            []KeyInfo `json:"api_keys"`
 	CurrentTokenIndex  int       `json:"current_token_index"`
 	CurrentApiKeyIndex int       `json:"current_api_key_index"`
+	AutoRotation       bool      `json:"auto_rotation"` // 是否启用自动轮换
+	Strategy           string    `json:"strategy"`      // failover/round_robin/least_used
 }
 
 // KeysOverviewResult Keys 概览结果
@@ -207,6 +333,8 @@ func (a *App) GetKeysOverview() KeysOverviewResult {
 			Tokens:   make([]KeyInfo, 0),
 			ApiKeys:  make([]KeyInfo, 0),
 		}
+		info.AutoRotation, _ = keysInfo["auto_rotation"].(bool)
+		info.Strategy, _ = keysInfo["strategy"].(string)
 
 		// 解析 keysInfo map - 注意类型是 []map[string]interface{} 不是 []interface{}
 		if tokens, ok := keysInfo["tokens"].([]map[string]interface{}); ok {
@@ -221,6 +349,7 @@ func (a *App) GetKeysOverview() KeysOverviewResult {
 				if v, ok := tokenMap["masked"].(string); ok {
 					keyInfo.Value = v
 				}
+				applyKeyStatusInfo(&keyInfo, tokenMap)
 				if active, ok := tokenMap["is_active"].(bool); ok {
 					keyInfo.IsActive = active
 					if active {
@@ -243,6 +372,7 @@ func (a *App) GetKeysOverview() KeysOverviewResult {
 				if v, ok := keyMap["masked"].(string); ok {
 					keyInfo.Value = v
 				}
+				applyKeyStatusInfo(&keyInfo, keyMap)
 				if active, ok := keyMap["is_active"].(bool); ok {
 					keyInfo.IsActive = active
 					if active {
@@ -260,6 +390,19 @@ func (a *App) GetKeysOverview() KeysOverviewResult {
 	return result
 }
 
+// applyKeyStatusInfo 从 GetEndpointKeysInfo 的 Key 条目解析轮换状态
+func applyKeyStatusInfo(keyInfo *KeyInfo, keyMap map[string]interface{}) {
+	keyInfo.Status = "available"
+	if status, ok := keyMap["status"].(string); ok {
+		keyInfo.Status = status
+	}
+	keyInfo.CooldownUntil, _ = keyMap["cooldown_until"].(string)
+	keyInfo.UsageCount, _ = keyMap["usage_count"].(int64)
+	keyInfo.LastStatusCode, _ = keyMap["last_status_code"].(int)
+	keyInfo.LastError, _ = keyMap["last_error"].(string)
+	keyInfo.LastUsedAt, _ = keyMap["last_used_at"].(string)
+}
+
 // SwitchKeyResult 切换 Key 结果
 type SwitchKeyResult struct {
 	Success   bool   `json:"success"`
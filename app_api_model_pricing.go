@@ -6,9 +6,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"cc-forwarder/internal/service"
 	"cc-forwarder/internal/store"
 )
 
@@ -123,7 +125,7 @@ func (a *App) GetModelPricing(modelName string) (ModelPricingInfo, error) {
 		return ModelPricingInfo{}, fmt.Errorf("获取模型定价失败: %w", err)
 	}
 	if record == nil {
-		return ModelPricingInfo{}, fmt.Errorf("模型定价 '%s' 不存在", modelName)
+		return ModelPricingInfo{}, fmt.Errorf("模型定价 '%s' %w", modelName, store.ErrNotFound)
 	}
 
 	return a.pricingRecordToInfo(record), nil
@@ -175,6 +177,9 @@ func (a *App) CreateModelPricing(input CreateModelPricingInput) error {
 		return fmt.Errorf("创建模型定价失败: %w", err)
 	}
 
+	// 同步定价和定价历史到 UsageTracker
+	a.syncPricingToTracker(ctx)
+
 	if a.logger != nil {
 		a.logger.Info("✅ 模型定价已创建", "model", input.ModelName)
 	}
@@ -210,6 +215,9 @@ func (a *App) UpdateModelPricing(modelName string, input CreateModelPricingInput
 		return fmt.Errorf("更新模型定价失败: %w", err)
 	}
 
+	// 同步定价和定价历史到 UsageTracker
+	a.syncPricingToTracker(ctx)
+
 	if a.logger != nil {
 		a.logger.Info("✅ 模型定价已更新", "model", modelName)
 	}
@@ -233,6 +241,9 @@ func (a *App) DeleteModelPricing(modelName string) error {
 		return fmt.Errorf("删除模型定价失败: %w", err)
 	}
 
+	// 同步定价和定价历史到 UsageTracker
+	a.syncPricingToTracker(ctx)
+
 	if a.logger != nil {
 		a.logger.Info("✅ 模型定价已删除", "model", modelName)
 	}
@@ -263,6 +274,322 @@ func (a *App) SetDefaultModelPricing(modelName string) error {
 	return nil
 }
 
+// ModelPricingImportInput 定价导入参数
+type ModelPricingImportInput struct {
+	Content      string   `json:"content"`       // 价格目录内容（LiteLLM JSON 或 CSV）
+	Format       string   `json:"format"`        // litellm / csv，为空时按内容识别
+	Providers    []string `json:"providers"`     // LiteLLM 目录只导入这些 provider，为空时为 anthropic
+	OnlyExisting bool     `json:"only_existing"` // 只更新已有模型，不新增
+	Source       string   `json:"source"`        // 来源说明（文件名或 URL），写入审计记录
+	Fingerprint  string   `json:"fingerprint"`   // 应用时传入预览返回的摘要，预览后定价被修改时拒绝导入
+}
+
+// ModelPricingFieldChangeInfo 定价字段变化
+type ModelPricingFieldChangeInfo struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ModelPricingChangeInfo 单个模型的导入差异
+type ModelPricingChangeInfo struct {
+	ModelName string                        `json:"model_name"`
+	Action    string                        `json:"action"` // new / changed / unchanged
+	Fields    []ModelPricingFieldChangeInfo `json:"fields"`
+}
+
+// ModelPricingImportResult 定价导入预览或应用结果
+type ModelPricingImportResult struct {
+	Format      string                   `json:"format"`
+	Total       int                      `json:"total"`
+	Skipped     int                      `json:"skipped"`
+	New         int                      `json:"new"`
+	Changed     int                      `json:"changed"`
+	Unchanged   int                      `json:"unchanged"`
+	Changes     []ModelPricingChangeInfo `json:"changes"`
+	Fingerprint string                   `json:"fingerprint"`
+	AuditID     int64                    `json:"audit_id"` // 应用后的审计记录 ID（预览或没有变化时为 0）
+}
+
+// ModelPricingAuditInfo 定价导入审计记录
+type ModelPricingAuditInfo struct {
+	ID        int64                    `json:"id"`
+	Source    string                   `json:"source"`
+	Format    string                   `json:"format"`
+	Added     int                      `json:"added"`
+	Changed   int                      `json:"changed"`
+	Unchanged int                      `json:"unchanged"`
+	Changes   []ModelPricingChangeInfo `json:"changes"` // 新增和变化明细
+	CreatedAt string                   `json:"created_at"`
+}
+
+// PreviewModelPricingImport 预览定价导入：列出新增、变化和未变化的模型，不写入数据库
+func (a *App) PreviewModelPricingImport(input ModelPricingImportInput) (ModelPricingImportResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return ModelPricingImportResult{}, fmt.Errorf("模型定价服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	diff, err := a.modelPricingService.PreviewImport(ctx, []byte(input.Content), pricingImportOptions(input))
+	if err != nil {
+		return ModelPricingImportResult{}, err
+	}
+
+	return pricingImportDiffToResult(diff), nil
+}
+
+// ApplyModelPricingImport 应用定价导入：写入新增和变化的模型定价并记录审计
+func (a *App) ApplyModelPricingImport(input ModelPricingImportInput) (ModelPricingImportResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return ModelPricingImportResult{}, fmt.Errorf("模型定价服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	diff, audit, err := a.modelPricingService.ApplyImport(ctx, []byte(input.Content), pricingImportOptions(input), input.Fingerprint)
+	if err != nil {
+		return ModelPricingImportResult{}, err
+	}
+
+	result := pricingImportDiffToResult(diff)
+	if diff.New > 0 || diff.Changed > 0 {
+		// 同步定价到 UsageTracker（用于成本计算）
+		a.syncPricingToTracker(ctx)
+
+		if a.logger != nil {
+			a.logger.Info("✅ 模型定价已导入", "format", diff.Format, "new", diff.New, "changed", diff.Changed)
+		}
+	}
+	if audit != nil {
+		result.AuditID = audit.ID
+	}
+
+	return result, nil
+}
+
+// GetModelPricingAudits 获取定价导入审计记录（按时间倒序）
+func (a *App) GetModelPricingAudits(limit int) ([]ModelPricingAuditInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return nil, fmt.Errorf("模型定价服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.modelPricingService.ListImportAudits(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ModelPricingAuditInfo, 0, len(records))
+	for _, r := range records {
+		info := ModelPricingAuditInfo{
+			ID:        r.ID,
+			Source:    r.Source,
+			Format:    r.Format,
+			Added:     r.Added,
+			Changed:   r.Changed,
+			Unchanged: r.Unchanged,
+		}
+		if err := json.Unmarshal([]byte(r.Changes), &info.Changes); err != nil {
+			info.Changes = []ModelPricingChangeInfo{}
+		}
+		if !r.CreatedAt.IsZero() {
+			info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// ModelPricingVersionInfo 模型定价版本（给前端用的结构体）
+type ModelPricingVersionInfo struct {
+	ID                   int64   `json:"id"`
+	ModelName            string  `json:"model_name"`
+	InputPrice           float64 `json:"input_price"`
+	OutputPrice          float64 `json:"output_price"`
+	CacheCreationPrice5m float64 `json:"cache_creation_price_5m"`
+	CacheCreationPrice1h float64 `json:"cache_creation_price_1h"`
+	CacheReadPrice       float64 `json:"cache_read_price"`
+	EffectiveFrom        string  `json:"effective_from"` // 生效时间（配置时区）
+	Source               string  `json:"source"`         // baseline / create / update / import / manual
+	CreatedAt            string  `json:"created_at"`
+}
+
+// ModelPricingVersionInput 添加或修正定价版本的输入参数
+type ModelPricingVersionInput struct {
+	ModelName            string  `json:"model_name"`
+	InputPrice           float64 `json:"input_price"`
+	OutputPrice          float64 `json:"output_price"`
+	CacheCreationPrice5m float64 `json:"cache_creation_price_5m"` // 为 0 时按输入价格 1.25 倍推算
+	CacheCreationPrice1h float64 `json:"cache_creation_price_1h"` // 为 0 时按输入价格 2 倍推算
+	CacheReadPrice       float64 `json:"cache_read_price"`        // 为 0 时按输入价格 0.1 倍推算
+	EffectiveFrom        string  `json:"effective_from"`          // 生效时间，不带时区时使用配置时区；与已有版本相同时覆盖
+}
+
+// GetModelPricingVersions 获取模型的定价版本（按生效时间升序），modelName 为空时返回全部
+func (a *App) GetModelPricingVersions(modelName string) ([]ModelPricingVersionInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return nil, fmt.Errorf("模型定价服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	versions, err := a.modelPricingService.ListPricingVersions(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := a.timezoneLocation()
+	result := make([]ModelPricingVersionInfo, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, pricingVersionToInfo(v, loc))
+	}
+
+	return result, nil
+}
+
+// AddModelPricingVersion 添加或修正定价版本（生效时间不能晚于当前时间）
+// 修正历史价格后可通过 StartCostRecompute 重算受影响时间范围的成本
+func (a *App) AddModelPricingVersion(input ModelPricingVersionInput) (ModelPricingVersionInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return ModelPricingVersionInfo{}, fmt.Errorf("模型定价服务未启用")
+	}
+
+	effectiveFrom, err := parseTimeWithLocation(input.EffectiveFrom, a.timezoneLocation())
+	if err != nil {
+		return ModelPricingVersionInfo{}, fmt.Errorf("生效时间格式无效: %w", err)
+	}
+
+	version := &store.ModelPricingVersionRecord{
+		ModelName:            input.ModelName,
+		InputPrice:           input.InputPrice,
+		OutputPrice:          input.OutputPrice,
+		CacheCreationPrice5m: input.CacheCreationPrice5m,
+		CacheCreationPrice1h: input.CacheCreationPrice1h,
+		CacheReadPrice:       input.CacheReadPrice,
+		EffectiveFrom:        effectiveFrom,
+		Source:               service.PricingVersionManual,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.modelPricingService.AddPricingVersion(ctx, version); err != nil {
+		return ModelPricingVersionInfo{}, err
+	}
+
+	a.syncPricingToTracker(ctx)
+
+	if a.logger != nil {
+		a.logger.Info("✅ 定价版本已添加", "model", version.ModelName, "effective_from", version.EffectiveFrom)
+	}
+
+	return pricingVersionToInfo(version, a.timezoneLocation()), nil
+}
+
+// DeleteModelPricingVersion 删除定价版本（模型至少保留一个版本）
+func (a *App) DeleteModelPricingVersion(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.modelPricingService == nil {
+		return fmt.Errorf("模型定价服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.modelPricingService.DeletePricingVersion(ctx, id); err != nil {
+		return err
+	}
+
+	a.syncPricingToTracker(ctx)
+
+	if a.logger != nil {
+		a.logger.Info("✅ 定价版本已删除", "id", id)
+	}
+
+	return nil
+}
+
+// pricingVersionToInfo 将定价版本转换为前端结构（时间按配置时区显示）
+func pricingVersionToInfo(v *store.ModelPricingVersionRecord, loc *time.Location) ModelPricingVersionInfo {
+	info := ModelPricingVersionInfo{
+		ID:                   v.ID,
+		ModelName:            v.ModelName,
+		InputPrice:           v.InputPrice,
+		OutputPrice:          v.OutputPrice,
+		CacheCreationPrice5m: v.CacheCreationPrice5m,
+		CacheCreationPrice1h: v.CacheCreationPrice1h,
+		CacheReadPrice:       v.CacheReadPrice,
+		EffectiveFrom:        v.EffectiveFrom.In(loc).Format("2006-01-02 15:04:05"),
+		Source:               v.Source,
+	}
+	if !v.CreatedAt.IsZero() {
+		info.CreatedAt = v.CreatedAt.In(loc).Format("2006-01-02 15:04:05")
+	}
+	return info
+}
+
+// pricingImportOptions 将前端输入转换为导入选项
+func pricingImportOptions(input ModelPricingImportInput) service.PricingImportOptions {
+	return service.PricingImportOptions{
+		Format:       input.Format,
+		Providers:    input.Providers,
+		OnlyExisting: input.OnlyExisting,
+		Source:       input.Source,
+	}
+}
+
+// pricingImportDiffToResult 将导入差异转换为前端结构
+func pricingImportDiffToResult(diff *service.PricingImportDiff) ModelPricingImportResult {
+	result := ModelPricingImportResult{
+		Format:      diff.Format,
+		Total:       diff.Total,
+		Skipped:     diff.Skipped,
+		New:         diff.New,
+		Changed:     diff.Changed,
+		Unchanged:   diff.Unchanged,
+		Changes:     make([]ModelPricingChangeInfo, 0, len(diff.Changes)),
+		Fingerprint: diff.Fingerprint,
+	}
+
+	for _, c := range diff.Changes {
+		change := ModelPricingChangeInfo{
+			ModelName: c.ModelName,
+			Action:    c.Action,
+			Fields:    make([]ModelPricingFieldChangeInfo, 0, len(c.Fields)),
+		}
+		for _, f := range c.Fields {
+			change.Fields = append(change.Fields, ModelPricingFieldChangeInfo{Field: f.Field, Old: f.Old, New: f.New})
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	return result
+}
+
 // pricingRecordToInfo 将数据库记录转换为前端 Info 结构
 func (a *App) pricingRecordToInfo(r *store.ModelPricingRecord) ModelPricingInfo {
 	info := ModelPricingInfo{
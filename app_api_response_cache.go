@@ -0,0 +1,180 @@
+// app_api_response_cache.go - 响应缓存管理 API (Wails Bindings)
+// 提供响应缓存的统计、条目查看和清除功能
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// ============================================================
+// 响应缓存 API (SQLite)
+// ============================================================
+
+// ResponseCacheStatsInfo 响应缓存统计（给前端用的结构体）
+type ResponseCacheStatsInfo struct {
+	Enabled    bool   `json:"enabled"`
+	TTL        string `json:"ttl"`
+	MaxEntries int    `json:"max_entries"`
+	Entries    int    `json:"entries"`
+	TotalHits  int64  `json:"total_hits"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// ResponseCacheEntryInfo 响应缓存条目信息
+type ResponseCacheEntryInfo struct {
+	CacheKey  string              `json:"cache_key"`
+	Model     string              `json:"model"`
+	Path      string              `json:"path"`
+	BodySize  int64               `json:"body_size"`
+	HitCount  int64               `json:"hit_count"`
+	Expired   bool                `json:"expired"`
+	ExpiresAt string              `json:"expires_at"`
+	LastHitAt string              `json:"last_hit_at"`
+	CreatedAt string              `json:"created_at"`
+	Headers   map[string][]string `json:"headers,omitempty"` // 仅单条查询返回
+	Body      string              `json:"body,omitempty"`    // 仅单条查询返回
+}
+
+// ResponseCachePurgeResult 清除缓存结果
+type ResponseCachePurgeResult struct {
+	Purged int64 `json:"purged"`
+}
+
+// GetResponseCacheStats 获取响应缓存配置和统计
+func (a *App) GetResponseCacheStats() (ResponseCacheStatsInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.responseCacheService == nil {
+		return ResponseCacheStatsInfo{}, fmt.Errorf("响应缓存服务未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := a.responseCacheService.GetStats(ctx)
+	if err != nil {
+		return ResponseCacheStatsInfo{}, err
+	}
+
+	return ResponseCacheStatsInfo{
+		Enabled:    stats.Enabled,
+		TTL:        stats.TTL,
+		MaxEntries: stats.MaxEntries,
+		Entries:    stats.Entries,
+		TotalHits:  stats.TotalHits,
+		TotalBytes: stats.TotalBytes,
+	}, nil
+}
+
+// GetResponseCacheEntries 获取所有缓存条目（不含响应体）
+func (a *App) GetResponseCacheEntries() ([]ResponseCacheEntryInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.responseCacheService == nil {
+		return nil, fmt.Errorf("响应缓存服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.responseCacheService.ListEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]ResponseCacheEntryInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, responseCacheRecordToInfo(r, now))
+	}
+
+	return result, nil
+}
+
+// GetResponseCacheEntry 获取单个缓存条目（含响应头和响应体）
+func (a *App) GetResponseCacheEntry(key string) (ResponseCacheEntryInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.responseCacheService == nil {
+		return ResponseCacheEntryInfo{}, fmt.Errorf("响应缓存服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := a.responseCacheService.GetEntry(ctx, key)
+	if err != nil {
+		return ResponseCacheEntryInfo{}, err
+	}
+
+	info := responseCacheRecordToInfo(record, time.Now())
+	info.Headers = record.Headers
+	info.Body = string(record.Body)
+	return info, nil
+}
+
+// DeleteResponseCacheEntry 删除单个缓存条目
+func (a *App) DeleteResponseCacheEntry(key string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.responseCacheService == nil {
+		return fmt.Errorf("响应缓存服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return a.responseCacheService.DeleteEntry(ctx, key)
+}
+
+// PurgeResponseCache 清除缓存，expiredOnly 为 true 时只清除已过期的条目
+func (a *App) PurgeResponseCache(expiredOnly bool) (ResponseCachePurgeResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.responseCacheService == nil {
+		return ResponseCachePurgeResult{}, fmt.Errorf("响应缓存服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	purged, err := a.responseCacheService.Purge(ctx, expiredOnly)
+	if err != nil {
+		return ResponseCachePurgeResult{}, err
+	}
+	return ResponseCachePurgeResult{Purged: purged}, nil
+}
+
+// responseCacheRecordToInfo 将数据库记录转换为前端 Info 结构
+func responseCacheRecordToInfo(r *store.ResponseCacheRecord, now time.Time) ResponseCacheEntryInfo {
+	info := ResponseCacheEntryInfo{
+		CacheKey: r.CacheKey,
+		Model:    r.Model,
+		Path:     r.Path,
+		BodySize: r.BodySize,
+		HitCount: r.HitCount,
+		Expired:  !r.ExpiresAt.After(now),
+	}
+
+	if !r.ExpiresAt.IsZero() {
+		info.ExpiresAt = r.ExpiresAt.Format("2006-01-02 15:04:05")
+	}
+	if r.LastHitAt != nil {
+		info.LastHitAt = r.LastHitAt.Format("2006-01-02 15:04:05")
+	}
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return info
+}
@@ -0,0 +1,207 @@
+// app_api_routing_rule.go - 模型路由规则管理 API (Wails Bindings)
+// 提供模型路由规则的增删改查功能，命中规则的请求只转发到允许的端点/渠道
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// ============================================================
+// 模型路由规则 API (SQLite)
+// ============================================================
+
+// RoutingRuleInfo 路由规则信息（给前端用的结构体）
+type RoutingRuleInfo struct {
+	ID           int64    `json:"id"`
+	Name         string   `json:"name"`
+	ModelPattern string   `json:"model_pattern"` // 模型名称通配符
+	Description  string   `json:"description"`
+	Endpoints    []string `json:"endpoints"` // 允许的端点名称
+	Channels     []string `json:"channels"`  // 允许的渠道名称
+	Priority     int      `json:"priority"`  // 匹配顺序（数字越小越先匹配）
+	Fallback     bool     `json:"fallback"`  // 允许的端点均不可用时回退到常规选择
+	Enabled      bool     `json:"enabled"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+// RoutingRuleInput 创建/更新路由规则的输入参数
+type RoutingRuleInput struct {
+	Name         string   `json:"name"`
+	ModelPattern string   `json:"model_pattern"`
+	Description  string   `json:"description"`
+	Endpoints    []string `json:"endpoints"`
+	Channels     []string `json:"channels"`
+	Priority     int      `json:"priority"`
+	Fallback     bool     `json:"fallback"`
+	Enabled      *bool    `json:"enabled"` // 未指定时默认启用
+}
+
+// RoutingRuleMatchResult 模型匹配测试结果
+type RoutingRuleMatchResult struct {
+	Model     string   `json:"model"`
+	Matched   bool     `json:"matched"`
+	Rule      string   `json:"rule"`
+	Endpoints []string `json:"endpoints"`
+	Channels  []string `json:"channels"`
+	Fallback  bool     `json:"fallback"`
+}
+
+// GetRoutingRules 获取所有路由规则（按匹配顺序）
+func (a *App) GetRoutingRules() ([]RoutingRuleInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return nil, fmt.Errorf("路由规则服务未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.routingRuleService.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RoutingRuleInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, routingRuleRecordToInfo(r))
+	}
+
+	return result, nil
+}
+
+// GetRoutingRule 获取单个路由规则
+func (a *App) GetRoutingRule(name string) (RoutingRuleInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return RoutingRuleInfo{}, fmt.Errorf("路由规则服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := a.routingRuleService.GetRule(ctx, name)
+	if err != nil {
+		return RoutingRuleInfo{}, err
+	}
+	if record == nil {
+		return RoutingRuleInfo{}, fmt.Errorf("路由规则 '%s' %w", name, store.ErrNotFound)
+	}
+
+	return routingRuleRecordToInfo(record), nil
+}
+
+// CreateRoutingRule 创建路由规则
+func (a *App) CreateRoutingRule(input RoutingRuleInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return fmt.Errorf("路由规则服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := a.routingRuleService.CreateRule(ctx, routingRuleInputToRecord(input.Name, input))
+	return err
+}
+
+// UpdateRoutingRule 更新路由规则
+func (a *App) UpdateRoutingRule(name string, input RoutingRuleInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return fmt.Errorf("路由规则服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.routingRuleService.UpdateRule(ctx, routingRuleInputToRecord(name, input))
+}
+
+// DeleteRoutingRule 删除路由规则
+func (a *App) DeleteRoutingRule(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return fmt.Errorf("路由规则服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.routingRuleService.DeleteRule(ctx, name)
+}
+
+// TestRoutingRuleMatch 测试模型会命中哪条路由规则
+func (a *App) TestRoutingRuleMatch(model string) (RoutingRuleMatchResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.routingRuleService == nil {
+		return RoutingRuleMatchResult{}, fmt.Errorf("路由规则服务未启用")
+	}
+
+	result := RoutingRuleMatchResult{Model: model}
+	if route, ok := a.routingRuleService.MatchModel(strings.TrimSpace(model)); ok {
+		result.Matched = true
+		result.Rule = route.Rule
+		result.Endpoints = route.Endpoints
+		result.Channels = route.Channels
+		result.Fallback = route.Fallback
+	}
+
+	return result, nil
+}
+
+// routingRuleInputToRecord 将前端输入转换为数据库记录
+func routingRuleInputToRecord(name string, input RoutingRuleInput) *store.RoutingRuleRecord {
+	return &store.RoutingRuleRecord{
+		Name:         strings.TrimSpace(name),
+		ModelPattern: input.ModelPattern,
+		Description:  input.Description,
+		Endpoints:    input.Endpoints,
+		Channels:     input.Channels,
+		Priority:     input.Priority,
+		Fallback:     input.Fallback,
+		Enabled:      input.Enabled == nil || *input.Enabled,
+	}
+}
+
+// routingRuleRecordToInfo 将数据库记录转换为前端 Info 结构
+func routingRuleRecordToInfo(r *store.RoutingRuleRecord) RoutingRuleInfo {
+	info := RoutingRuleInfo{
+		ID:           r.ID,
+		Name:         r.Name,
+		ModelPattern: r.ModelPattern,
+		Description:  r.Description,
+		Endpoints:    r.Endpoints,
+		Channels:     r.Channels,
+		Priority:     r.Priority,
+		Fallback:     r.Fallback,
+		Enabled:      r.Enabled,
+	}
+
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	if !r.UpdatedAt.IsZero() {
+		info.UpdatedAt = r.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return info
+}
@@ -29,6 +29,7 @@ type SettingInfo struct {
 	Description     string `json:"description"`
 	DisplayOrder    int    `json:"display_order"`
 	RequiresRestart bool   `json:"requires_restart"`
+	ValueSet        bool   `json:"value_set,omitempty"` // 敏感设置脱敏后表示是否已设置值
 	CreatedAt       string `json:"created_at"`
 	UpdatedAt       string `json:"updated_at"`
 }
@@ -195,7 +196,7 @@ func (a *App) GetSetting(category, key string) (SettingInfo, error) {
 		return SettingInfo{}, fmt.Errorf("获取设置失败: %w", err)
 	}
 	if record == nil {
-		return SettingInfo{}, fmt.Errorf("设置 '%s.%s' 不存在", category, key)
+		return SettingInfo{}, fmt.Errorf("设置 '%s.%s' %w", category, key, store.ErrNotFound)
 	}
 
 	return a.settingRecordToInfo(record), nil
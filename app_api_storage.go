@@ -6,9 +6,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"time"
 
 	"cc-forwarder/config"
+	"cc-forwarder/internal/service"
 	"cc-forwarder/internal/store"
 )
 
@@ -20,31 +22,38 @@ import (
 
 // EndpointRecordInfo 端点记录信息（给前端用的结构体）
 type EndpointRecordInfo struct {
-	ID                          int64             `json:"id"`
-	Channel                     string            `json:"channel"`
-	Name                        string            `json:"name"`
-	URL                         string            `json:"url"`
-	Token                       string            `json:"token"`        // v5.0: 本地桌面应用，直接返回原始 Token
-	ApiKey                      string            `json:"api_key"`      // v5.0: 本地桌面应用，直接返回原始 ApiKey
-	TokenMasked                 string            `json:"token_masked"` // 脱敏后的 Token（列表展示用）
-	ApiKeyMasked                string            `json:"api_key_masked"`
-	Headers                     map[string]string `json:"headers"`
-	Priority                    int               `json:"priority"`
-	FailoverEnabled             bool              `json:"failover_enabled"`
-	CooldownSeconds             *int              `json:"cooldown_seconds"`
-	TimeoutSeconds              int               `json:"timeout_seconds"`
-	SupportsCountTokens         bool              `json:"supports_count_tokens"`
-	CostMultiplier              float64           `json:"cost_multiplier"`
-	InputCostMultiplier         float64           `json:"input_cost_multiplier"`
-	OutputCostMultiplier        float64           `json:"output_cost_multiplier"`
-	CacheCreationCostMultiplier float64           `json:"cache_creation_cost_multiplier"`
-	CacheReadCostMultiplier     float64           `json:"cache_read_cost_multiplier"`
-	Enabled                     bool              `json:"enabled"`
-	CreatedAt                   string            `json:"created_at"`
-	UpdatedAt                   string            `json:"updated_at"`
+	ID                          int64              `json:"id"`
+	Channel                     string             `json:"channel"`
+	Name                        string             `json:"name"`
+	URL                         string             `json:"url"`
+	Token                       string             `json:"token"`        // v5.0: 本地桌面应用，直接返回原始 Token
+	ApiKey                      string             `json:"api_key"`      // v5.0: 本地桌面应用，直接返回原始 ApiKey
+	TokenMasked                 string             `json:"token_masked"` // 脱敏后的 Token（列表展示用）
+	ApiKeyMasked                string             `json:"api_key_masked"`
+	Headers                     map[string]string  `json:"headers"`
+	Priority                    int                `json:"priority"`
+	Weight                      int                `json:"weight"` // 权重（weighted 策略下同优先级内按权重分配）
+	FailoverEnabled             bool               `json:"failover_enabled"`
+	CooldownSeconds             *int               `json:"cooldown_seconds"`
+	TimeoutSeconds              int                `json:"timeout_seconds"`
+	MaxConcurrency              int                `json:"max_concurrency"` // 最大并发请求数（0=不限制）
+	RPM                         int                `json:"rpm"`             // 每分钟请求数上限（0=不限制）
+	TPM                         int                `json:"tpm"`             // 每分钟 Token 数上限（0=不限制）
+	SupportsCountTokens         bool               `json:"supports_count_tokens"`
+	ModelMapping                map[string]string  `json:"model_mapping"` // 模型名称映射（客户端模型 -> 上游模型）
+	RetryPolicy                 string             `json:"retry_policy"`  // 端点级重试策略规则（YAML/JSON 文本，空表示使用全局策略）
+	CostMultiplier              float64            `json:"cost_multiplier"`
+	InputCostMultiplier         float64            `json:"input_cost_multiplier"`
+	OutputCostMultiplier        float64            `json:"output_cost_multiplier"`
+	CacheCreationCostMultiplier float64            `json:"cache_creation_cost_multiplier"`
+	CacheReadCostMultiplier     float64            `json:"cache_read_cost_multiplier"`
+	Enabled                     bool               `json:"enabled"`
+	Proxy                       *EndpointProxyInfo `json:"proxy"` // 端点级代理（nil=使用全局代理，不返回密码）
+	CreatedAt                   string             `json:"created_at"`
+	UpdatedAt                   string             `json:"updated_at"`
 	// 运行时健康状态
 	Healthy        bool    `json:"healthy"`
-	LastCheck      string  `json:"last_check"`       // 最后健康检查时间
+	LastCheck      string  `json:"last_check"` // 最后健康检查时间
 	ResponseTimeMs float64 `json:"response_time_ms"`
 	// 冷却状态（请求级故障转移）
 	InCooldown     bool   `json:"in_cooldown"`     // 是否处于冷却中
@@ -54,22 +63,40 @@ type EndpointRecordInfo struct {
 
 // CreateEndpointInput 创建端点的输入参数
 type CreateEndpointInput struct {
-	Channel                     string            `json:"channel"`
-	Name                        string            `json:"name"`
-	URL                         string            `json:"url"`
-	Token                       string            `json:"token"`
-	ApiKey                      string            `json:"api_key"`
-	Headers                     map[string]string `json:"headers"`
-	Priority                    int               `json:"priority"`
-	FailoverEnabled             bool              `json:"failover_enabled"`
-	CooldownSeconds             *int              `json:"cooldown_seconds"`
-	TimeoutSeconds              int               `json:"timeout_seconds"`
-	SupportsCountTokens         bool              `json:"supports_count_tokens"`
-	CostMultiplier              float64           `json:"cost_multiplier"`
-	InputCostMultiplier         float64           `json:"input_cost_multiplier"`
-	OutputCostMultiplier        float64           `json:"output_cost_multiplier"`
-	CacheCreationCostMultiplier float64           `json:"cache_creation_cost_multiplier"`
-	CacheReadCostMultiplier     float64           `json:"cache_read_cost_multiplier"`
+	Channel                     string               `json:"channel"`
+	Name                        string               `json:"name"`
+	URL                         string               `json:"url"`
+	Token                       string               `json:"token"`
+	ApiKey                      string               `json:"api_key"`
+	Headers                     map[string]string    `json:"headers"`
+	Priority                    int                  `json:"priority"`
+	Weight                      int                  `json:"weight"` // 权重（weighted 策略下同优先级内按权重分配）
+	FailoverEnabled             bool                 `json:"failover_enabled"`
+	CooldownSeconds             *int                 `json:"cooldown_seconds"`
+	TimeoutSeconds              int                  `json:"timeout_seconds"`
+	MaxConcurrency              int                  `json:"max_concurrency"` // 最大并发请求数（0=不限制）
+	RPM                         int                  `json:"rpm"`             // 每分钟请求数上限（0=不限制）
+	TPM                         int                  `json:"tpm"`             // 每分钟 Token 数上限（0=不限制）
+	SupportsCountTokens         bool                 `json:"supports_count_tokens"`
+	ModelMapping                map[string]string    `json:"model_mapping"` // 模型名称映射（客户端模型 -> 上游模型）
+	RetryPolicy                 string               `json:"retry_policy"`  // 端点级重试策略规则（YAML/JSON 文本，空表示使用全局策略）
+	CostMultiplier              float64              `json:"cost_multiplier"`
+	InputCostMultiplier         float64              `json:"input_cost_multiplier"`
+	OutputCostMultiplier        float64              `json:"output_cost_multiplier"`
+	CacheCreationCostMultiplier float64              `json:"cache_creation_cost_multiplier"`
+	CacheReadCostMultiplier     float64              `json:"cache_read_cost_multiplier"`
+	Proxy                       *store.EndpointProxy `json:"proxy"` // 端点级代理（nil=使用全局代理，enabled=false 表示直连；密码为空时保留原密码）
+}
+
+// EndpointProxyInfo 端点级代理信息（给前端用，不包含密码）
+type EndpointProxyInfo struct {
+	Enabled     bool   `json:"enabled"`
+	Type        string `json:"type"`
+	URL         string `json:"url,omitempty"` // URL 中内嵌的密码已脱敏
+	Host        string `json:"host,omitempty"`
+	Port        int    `json:"port,omitempty"`
+	Username    string `json:"username,omitempty"`
+	PasswordSet bool   `json:"password_set"` // 是否已配置密码
 }
 
 // EndpointStorageStatus 端点存储状态
@@ -236,6 +263,9 @@ func (a *App) CreateEndpointRecord(input CreateEndpointInput) error {
 	if input.Priority == 0 {
 		input.Priority = 1
 	}
+	if input.Weight <= 0 {
+		input.Weight = 1
+	}
 	if input.TimeoutSeconds == 0 {
 		input.TimeoutSeconds = 300
 	}
@@ -251,15 +281,22 @@ func (a *App) CreateEndpointRecord(input CreateEndpointInput) error {
 		ApiKey:                      input.ApiKey,
 		Headers:                     input.Headers,
 		Priority:                    input.Priority,
+		Weight:                      input.Weight,
 		FailoverEnabled:             input.FailoverEnabled,
 		CooldownSeconds:             input.CooldownSeconds,
 		TimeoutSeconds:              input.TimeoutSeconds,
+		MaxConcurrency:              input.MaxConcurrency,
+		RPM:                         input.RPM,
+		TPM:                         input.TPM,
 		SupportsCountTokens:         input.SupportsCountTokens,
+		ModelMapping:                input.ModelMapping,
+		RetryPolicy:                 input.RetryPolicy,
 		CostMultiplier:              input.CostMultiplier,
 		InputCostMultiplier:         input.InputCostMultiplier,
 		OutputCostMultiplier:        input.OutputCostMultiplier,
 		CacheCreationCostMultiplier: input.CacheCreationCostMultiplier,
 		CacheReadCostMultiplier:     input.CacheReadCostMultiplier,
+		Proxy:                       input.Proxy,
 		Enabled:                     false, // v5.0: 新建端点默认不激活，需手动激活
 	}
 
@@ -314,6 +351,15 @@ func (a *App) UpdateEndpointRecord(name string, input CreateEndpointInput) error
 		apiKey = existingRecord.ApiKey
 	}
 
+	// 处理代理密码: 如果前端传空值或脱敏后的 URL，保留原有密码
+	proxy := mergeEndpointProxySecrets(input.Proxy, existingRecord.Proxy)
+
+	// 处理 Weight: 未指定时保留原有权重
+	weight := input.Weight
+	if weight <= 0 {
+		weight = existingRecord.Weight
+	}
+
 	record := &store.EndpointRecord{
 		Channel:                     input.Channel,
 		Name:                        name, // 使用 URL 参数中的 name
@@ -322,15 +368,22 @@ func (a *App) UpdateEndpointRecord(name string, input CreateEndpointInput) error
 		ApiKey:                      apiKey, // 空值时保留原有值
 		Headers:                     input.Headers,
 		Priority:                    input.Priority,
+		Weight:                      weight,
 		FailoverEnabled:             input.FailoverEnabled,
 		CooldownSeconds:             input.CooldownSeconds,
 		TimeoutSeconds:              input.TimeoutSeconds,
+		MaxConcurrency:              input.MaxConcurrency,
+		RPM:                         input.RPM,
+		TPM:                         input.TPM,
 		SupportsCountTokens:         input.SupportsCountTokens,
+		ModelMapping:                input.ModelMapping,
+		RetryPolicy:                 input.RetryPolicy,
 		CostMultiplier:              input.CostMultiplier,
 		InputCostMultiplier:         input.InputCostMultiplier,
 		OutputCostMultiplier:        input.OutputCostMultiplier,
 		CacheCreationCostMultiplier: input.CacheCreationCostMultiplier,
 		CacheReadCostMultiplier:     input.CacheReadCostMultiplier,
+		Proxy:                       proxy,
 		Enabled:                     existingRecord.Enabled, // 保持原有激活状态
 	}
 
@@ -353,12 +406,19 @@ func (a *App) UpdateEndpointRecord(name string, input CreateEndpointInput) error
 			URL:                 input.URL,
 			Channel:             input.Channel,
 			Priority:            input.Priority,
+			Weight:              weight,
 			FailoverEnabled:     &failoverEnabled,
 			Token:               token,  // 使用处理后的值（空值时保留原有）
 			ApiKey:              apiKey, // 使用处理后的值（空值时保留原有）
 			Timeout:             time.Duration(input.TimeoutSeconds) * time.Second,
+			MaxConcurrency:      input.MaxConcurrency,
+			RPM:                 input.RPM,
+			TPM:                 input.TPM,
 			Headers:             input.Headers,
 			SupportsCountTokens: input.SupportsCountTokens,
+			ModelMapping:        input.ModelMapping,
+			RetryPolicy:         service.EndpointRetryPolicyToConfig(input.RetryPolicy),
+			Proxy:               service.EndpointProxyToConfig(proxy),
 		}
 
 		// 更新内存中的端点配置
@@ -443,12 +503,19 @@ func (a *App) ToggleEndpointRecord(name string, enabled bool) error {
 				URL:                 record.URL,
 				Channel:             record.Channel,
 				Priority:            record.Priority,
+				Weight:              record.Weight,
 				FailoverEnabled:     &failoverEnabled,
 				Token:               record.Token,
 				ApiKey:              record.ApiKey,
 				Timeout:             time.Duration(record.TimeoutSeconds) * time.Second,
+				MaxConcurrency:      record.MaxConcurrency,
+				RPM:                 record.RPM,
+				TPM:                 record.TPM,
 				Headers:             record.Headers,
 				SupportsCountTokens: record.SupportsCountTokens,
+				ModelMapping:        record.ModelMapping,
+				RetryPolicy:         service.EndpointRetryPolicyToConfig(record.RetryPolicy),
+				Proxy:               service.EndpointProxyToConfig(record.Proxy),
 			}
 
 			// 尝试添加端点（如果已存在会更新配置）
@@ -582,16 +649,23 @@ func (a *App) recordToInfo(r *store.EndpointRecord) EndpointRecordInfo {
 		ApiKeyMasked:                maskToken(r.ApiKey),
 		Headers:                     r.Headers,
 		Priority:                    r.Priority,
+		Weight:                      r.Weight,
 		FailoverEnabled:             r.FailoverEnabled,
 		CooldownSeconds:             r.CooldownSeconds,
 		TimeoutSeconds:              r.TimeoutSeconds,
+		MaxConcurrency:              r.MaxConcurrency,
+		RPM:                         r.RPM,
+		TPM:                         r.TPM,
 		SupportsCountTokens:         r.SupportsCountTokens,
+		ModelMapping:                r.ModelMapping,
+		RetryPolicy:                 r.RetryPolicy,
 		CostMultiplier:              r.CostMultiplier,
 		InputCostMultiplier:         r.InputCostMultiplier,
 		OutputCostMultiplier:        r.OutputCostMultiplier,
 		CacheCreationCostMultiplier: r.CacheCreationCostMultiplier,
 		CacheReadCostMultiplier:     r.CacheReadCostMultiplier,
 		Enabled:                     r.Enabled,
+		Proxy:                       endpointProxyToInfo(r.Proxy),
 	}
 
 	if !r.CreatedAt.IsZero() {
@@ -604,6 +678,50 @@ func (a *App) recordToInfo(r *store.EndpointRecord) EndpointRecordInfo {
 	return info
 }
 
+// endpointProxyToInfo 代理配置转换为前端信息，去掉密码
+func endpointProxyToInfo(p *store.EndpointProxy) *EndpointProxyInfo {
+	if p == nil {
+		return nil
+	}
+	return &EndpointProxyInfo{
+		Enabled:     p.Enabled,
+		Type:        p.Type,
+		URL:         redactProxyURL(p.URL),
+		Host:        p.Host,
+		Port:        p.Port,
+		Username:    p.Username,
+		PasswordSet: p.Password != "",
+	}
+}
+
+// redactProxyURL 脱敏代理 URL 中的密码（user:xxxxx@host）
+func redactProxyURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// mergeEndpointProxySecrets 更新代理时保留原有密码
+// 前端拿不到密码，回传空密码或脱敏后的 URL 时沿用数据库中的值
+func mergeEndpointProxySecrets(input, existing *store.EndpointProxy) *store.EndpointProxy {
+	if input == nil || existing == nil {
+		return input
+	}
+	merged := *input
+	if merged.Password == "" {
+		merged.Password = existing.Password
+	}
+	if merged.URL != "" && merged.URL != existing.URL && merged.URL == redactProxyURL(existing.URL) {
+		merged.URL = existing.URL
+	}
+	return &merged
+}
+
 // maskToken Token 脱敏显示
 func maskToken(token string) string {
 	if token == "" {
@@ -196,8 +196,11 @@ type RequestRecord struct {
 	CacheCreation1hTokens  int64   `json:"cache_creation_1h_tokens"`  // v5.0.1: 1小时缓存
 	CacheReadTokens        int64   `json:"cache_read_tokens"`
 	ResponseTime           int64   `json:"response_time"`
+	TTFTMs                 int64   `json:"ttft_ms,omitempty"`               // 首 Token 耗时
+	OutputTokensPerSec     float64 `json:"output_tokens_per_sec,omitempty"` // 输出 Token 速率
 	IsStreaming            bool    `json:"is_streaming"`
 	Cost                   float64 `json:"cost"`
+	ClientKey              string  `json:"client_key_name,omitempty"` // 客户端 Key 名称
 }
 
 // RequestListResult 请求列表结果
@@ -219,6 +222,7 @@ type RequestQueryParams struct {
 	Channel   string `json:"channel"`    // 可选：渠道名称（v5.0）
 	Endpoint  string `json:"endpoint"`   // 可选：端点名称
 	Group     string `json:"group"`      // 可选：组名称
+	ClientKey string `json:"client_key"` // 可选：客户端 Key 名称
 }
 
 // GetRequests 获取请求记录列表（热池+数据库双源查询）
@@ -284,10 +288,11 @@ func (a *App) GetRequests(params RequestQueryParams) (RequestListResult, error)
 		ModelName:    params.Model,
 		Channel:      params.Channel, // v5.0: 渠道筛选
 		EndpointName: params.Endpoint,
-		GroupName:    params.Group,
-		Status:       params.Status,
-		Limit:        pageSize,
-		Offset:       offset,
+		GroupName:     params.Group,
+		ClientKeyName: params.ClientKey,
+		Status:        params.Status,
+		Limit:         pageSize,
+		Offset:        offset,
 	}
 
 	requests, total, err := a.usageTracker.QueryRequestDetailsWithHotPool(ctx, opts)
@@ -324,6 +329,7 @@ func (a *App) GetRequests(params RequestQueryParams) (RequestListResult, error)
 			CacheReadTokens:       r.CacheReadTokens,
 			IsStreaming:           r.IsStreaming,
 			Cost:                  r.TotalCostUSD,
+			ClientKey:             r.ClientKeyName,
 		}
 
 		// 处理指针字段
@@ -333,6 +339,12 @@ func (a *App) GetRequests(params RequestQueryParams) (RequestListResult, error)
 		if r.DurationMs != nil {
 			record.ResponseTime = *r.DurationMs
 		}
+		if r.TTFTMs != nil {
+			record.TTFTMs = *r.TTFTMs
+		}
+		if r.OutputTokensPerSec != nil {
+			record.OutputTokensPerSec = *r.OutputTokensPerSec
+		}
 
 		result.Requests = append(result.Requests, record)
 	}
@@ -353,6 +365,9 @@ type UsageStatsData struct {
 	TotalCostUSD  float64 `json:"total_cost_usd"`
 	TotalTokens   int64   `json:"total_tokens"`
 	FailedCount   int     `json:"failed_requests"`
+
+	// 按客户端 Key 统计（未使用客户端 Key 的请求归入空名称）
+	ClientKeyStats map[string]tracking.ClientKeyStat `json:"client_key_stats,omitempty"`
 }
 
 // UsageStatsQueryParams 使用统计查询参数
@@ -365,6 +380,7 @@ type UsageStatsQueryParams struct {
 	Channel   string `json:"channel"`    // 可选：渠道筛选（v5.0）
 	Endpoint  string `json:"endpoint"`   // 可选：端点筛选
 	Group     string `json:"group"`      // 可选：组筛选
+	ClientKey string `json:"client_key"` // 可选：客户端 Key 筛选
 }
 
 // GetUsageStats 获取使用统计（与 HTTP API 格式一致）
@@ -440,10 +456,11 @@ func (a *App) GetUsageStats(params UsageStatsQueryParams) (UsageStatsData, error
 			ModelName:    params.Model,
 			Channel:      params.Channel, // v5.0: 渠道筛选
 			EndpointName: params.Endpoint,
-			GroupName:    params.Group,
-			Status:       params.Status,
-			Limit:        100000, // 大 limit 获取所有记录
-			Offset:       0,
+			GroupName:     params.Group,
+			ClientKeyName: params.ClientKey,
+			Status:        params.Status,
+			Limit:         100000, // 大 limit 获取所有记录
+			Offset:        0,
 		}
 
 		requests, _, err := a.usageTracker.QueryRequestDetailsWithHotPool(ctx, opts)
@@ -454,6 +471,7 @@ func (a *App) GetUsageStats(params UsageStatsQueryParams) (UsageStatsData, error
 			var totalCost float64
 			var totalDuration int64
 			var durationCount int
+			clientKeyStats := make(map[string]tracking.ClientKeyStat)
 
 			for _, req := range requests {
 				totalRequests++
@@ -467,9 +485,17 @@ func (a *App) GetUsageStats(params UsageStatsQueryParams) (UsageStatsData, error
 				}
 
 				// 累计 Token 和成本
-				totalTokens += req.InputTokens + req.OutputTokens + req.CacheCreationTokens + req.CacheReadTokens
+				reqTokens := req.InputTokens + req.OutputTokens + req.CacheCreationTokens + req.CacheReadTokens
+				totalTokens += reqTokens
 				totalCost += req.TotalCostUSD
 
+				// 按客户端 Key 累计
+				keyStat := clientKeyStats[req.ClientKeyName]
+				keyStat.RequestCount++
+				keyStat.TotalTokens += reqTokens
+				keyStat.TotalCost += req.TotalCostUSD
+				clientKeyStats[req.ClientKeyName] = keyStat
+
 				// 计算耗时
 				if req.DurationMs != nil && *req.DurationMs > 0 {
 					totalDuration += *req.DurationMs
@@ -495,6 +521,7 @@ func (a *App) GetUsageStats(params UsageStatsQueryParams) (UsageStatsData, error
 			result.TotalCostUSD = totalCost
 			result.TotalTokens = totalTokens
 			result.FailedCount = errorRequests
+			result.ClientKeyStats = clientKeyStats
 
 			return result, nil
 		}
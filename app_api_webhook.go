@@ -0,0 +1,308 @@
+// app_api_webhook.go - Webhook 通知管理 API (Wails Bindings)
+// 提供 Webhook 的增删改查、测试发送和投递记录查询功能
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/webhook"
+)
+
+// ============================================================
+// Webhook 通知 API (SQLite)
+// ============================================================
+
+// WebhookInfo Webhook 信息（给前端用的结构体，不返回签名密钥）
+type WebhookInfo struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Format      string   `json:"format"` // json / slack / feishu / dingtalk
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"` // 订阅的事件类型（为空时使用默认告警事件）
+	Template    string   `json:"template"`    // 自定义消息模板
+	SecretSet   bool     `json:"secret_set"`  // 是否配置了签名密钥
+	MaxRetries  int      `json:"max_retries"`
+	Enabled     bool     `json:"enabled"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// WebhookInput 创建/更新 Webhook 的输入参数
+type WebhookInput struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Format      string   `json:"format"` // 未指定时为 json
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	Template    string   `json:"template"`
+	Secret      *string  `json:"secret"`      // 未指定时保留原密钥，空字符串清除密钥
+	MaxRetries  *int     `json:"max_retries"` // 未指定时创建为默认值、更新时保留原值
+	Enabled     *bool    `json:"enabled"`     // 未指定时默认启用
+}
+
+// WebhookDeliveryInfo 投递记录信息
+type WebhookDeliveryInfo struct {
+	ID         int64  `json:"id"`
+	Webhook    string `json:"webhook"`
+	EventType  string `json:"event_type"`
+	Success    bool   `json:"success"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+	Payload    string `json:"payload"`
+	DurationMs int64  `json:"duration_ms"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// WebhookEventTypeInfo 可订阅的事件类型
+type WebhookEventTypeInfo struct {
+	Type    string `json:"type"`
+	Default bool   `json:"default"` // 是否属于未指定事件类型时的默认订阅
+}
+
+// GetWebhooks 获取所有 Webhook
+func (a *App) GetWebhooks() ([]WebhookInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return nil, fmt.Errorf("Webhook 服务未启用 (需要设置 usage_tracking.enabled: true)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.webhookService.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WebhookInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, webhookRecordToInfo(r))
+	}
+
+	return result, nil
+}
+
+// GetWebhook 获取单个 Webhook
+func (a *App) GetWebhook(name string) (WebhookInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return WebhookInfo{}, fmt.Errorf("Webhook 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := a.webhookService.GetWebhook(ctx, name)
+	if err != nil {
+		return WebhookInfo{}, err
+	}
+	if record == nil {
+		return WebhookInfo{}, fmt.Errorf("Webhook '%s' %w", name, store.ErrNotFound)
+	}
+
+	return webhookRecordToInfo(record), nil
+}
+
+// CreateWebhook 创建 Webhook
+func (a *App) CreateWebhook(input WebhookInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return fmt.Errorf("Webhook 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	record := webhookInputToRecord(input.Name, input)
+	if input.Secret != nil {
+		record.Secret = *input.Secret
+	}
+	record.MaxRetries = service.DefaultWebhookMaxRetries
+	if input.MaxRetries != nil {
+		record.MaxRetries = *input.MaxRetries
+	}
+
+	_, err := a.webhookService.CreateWebhook(ctx, record)
+	return err
+}
+
+// UpdateWebhook 更新 Webhook
+func (a *App) UpdateWebhook(name string, input WebhookInput) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return fmt.Errorf("Webhook 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	existing, err := a.webhookService.GetWebhook(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("Webhook '%s' %w", name, store.ErrNotFound)
+	}
+
+	record := webhookInputToRecord(name, input)
+	record.Secret = existing.Secret
+	if input.Secret != nil {
+		record.Secret = *input.Secret
+	}
+	record.MaxRetries = existing.MaxRetries
+	if input.MaxRetries != nil {
+		record.MaxRetries = *input.MaxRetries
+	}
+
+	return a.webhookService.UpdateWebhook(ctx, record)
+}
+
+// DeleteWebhook 删除 Webhook（投递记录保留）
+func (a *App) DeleteWebhook(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return fmt.Errorf("Webhook 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return a.webhookService.DeleteWebhook(ctx, name)
+}
+
+// TestWebhook 向 Webhook 发送一条测试通知，返回投递结果
+func (a *App) TestWebhook(name string) (WebhookDeliveryInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return WebhookDeliveryInfo{}, fmt.Errorf("Webhook 服务未启用")
+	}
+
+	// 包含重试等待时间
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	record, err := a.webhookService.SendTest(ctx, name)
+	if err != nil {
+		return WebhookDeliveryInfo{}, err
+	}
+	return webhookDeliveryRecordToInfo(record), nil
+}
+
+// GetWebhookDeliveries 获取投递记录（name 为空时返回所有 Webhook 的记录）
+func (a *App) GetWebhookDeliveries(name string, limit int) ([]WebhookDeliveryInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.webhookService == nil {
+		return nil, fmt.Errorf("Webhook 服务未启用")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	records, err := a.webhookService.ListDeliveries(ctx, strings.TrimSpace(name), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WebhookDeliveryInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, webhookDeliveryRecordToInfo(r))
+	}
+
+	return result, nil
+}
+
+// GetWebhookEventTypes 获取可订阅的事件类型
+func (a *App) GetWebhookEventTypes() []WebhookEventTypeInfo {
+	defaults := make(map[string]bool, len(webhook.DefaultEventTypes))
+	for _, t := range webhook.DefaultEventTypes {
+		defaults[string(t)] = true
+	}
+
+	supported := webhook.SupportedEventTypes()
+	result := make([]WebhookEventTypeInfo, 0, len(supported))
+	for _, t := range supported {
+		result = append(result, WebhookEventTypeInfo{Type: string(t), Default: defaults[string(t)]})
+	}
+	return result
+}
+
+// webhookInputToRecord 将前端输入转换为数据库记录（密钥和重试次数由调用方处理）
+func webhookInputToRecord(name string, input WebhookInput) *store.WebhookRecord {
+	return &store.WebhookRecord{
+		Name:        strings.TrimSpace(name),
+		URL:         input.URL,
+		Format:      input.Format,
+		Description: input.Description,
+		EventTypes:  input.EventTypes,
+		Template:    input.Template,
+		Enabled:     input.Enabled == nil || *input.Enabled,
+	}
+}
+
+// webhookRecordToInfo 将数据库记录转换为前端 Info 结构
+func webhookRecordToInfo(r *store.WebhookRecord) WebhookInfo {
+	info := WebhookInfo{
+		ID:          r.ID,
+		Name:        r.Name,
+		URL:         r.URL,
+		Format:      r.Format,
+		Description: r.Description,
+		EventTypes:  r.EventTypes,
+		Template:    r.Template,
+		SecretSet:   r.Secret != "",
+		MaxRetries:  r.MaxRetries,
+		Enabled:     r.Enabled,
+	}
+
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	if !r.UpdatedAt.IsZero() {
+		info.UpdatedAt = r.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return info
+}
+
+// webhookDeliveryRecordToInfo 将投递记录转换为前端 Info 结构
+func webhookDeliveryRecordToInfo(r *store.WebhookDeliveryRecord) WebhookDeliveryInfo {
+	info := WebhookDeliveryInfo{
+		ID:         r.ID,
+		Webhook:    r.Webhook,
+		EventType:  r.EventType,
+		Success:    r.Success,
+		Attempts:   r.Attempts,
+		StatusCode: r.StatusCode,
+		Error:      r.Error,
+		Payload:    r.Payload,
+		DurationMs: r.DurationMs,
+	}
+
+	if !r.CreatedAt.IsZero() {
+		info.CreatedAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return info
+}
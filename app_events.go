@@ -4,6 +4,8 @@
 package main
 
 import (
+	"cc-forwarder/internal/service"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -16,11 +18,18 @@ const (
 	EventConfigReloaded = "config:reloaded"
 	EventError          = "error"
 	EventNotification   = "notification"
+	EventCostRecompute  = "cost:recompute"
 )
 
+// canEmit 判断当前是否可以向 Wails 前端推送事件
+// 无界面模式下没有 Wails 运行时上下文，调用 runtime.EventsEmit 会直接终止进程
+func (a *App) canEmit() bool {
+	return a.ctx != nil && !a.headless
+}
+
 // emitSystemStatus 发送系统状态更新到前端
 func (a *App) emitSystemStatus() {
-	if a.ctx == nil {
+	if !a.canEmit() {
 		return
 	}
 
@@ -30,7 +39,7 @@ func (a *App) emitSystemStatus() {
 
 // emitEndpointUpdate 发送端点状态更新到前端
 func (a *App) emitEndpointUpdate() {
-	if a.ctx == nil {
+	if !a.canEmit() {
 		return
 	}
 
@@ -49,7 +58,7 @@ func (a *App) emitEndpointUpdate() {
 
 // emitGroupUpdate 发送组状态更新到前端
 func (a *App) emitGroupUpdate() {
-	if a.ctx == nil {
+	if !a.canEmit() {
 		return
 	}
 
@@ -59,7 +68,7 @@ func (a *App) emitGroupUpdate() {
 
 // emitUsageUpdate 发送使用统计更新到前端
 func (a *App) emitUsageUpdate() {
-	if a.ctx == nil {
+	if !a.canEmit() {
 		return
 	}
 
@@ -67,9 +76,18 @@ func (a *App) emitUsageUpdate() {
 	runtime.EventsEmit(a.ctx, EventUsageUpdate, summary)
 }
 
+// emitCostRecompute 发送成本重算进度到前端
+func (a *App) emitCostRecompute(job service.CostRecomputeJob) {
+	if !a.canEmit() {
+		return
+	}
+
+	runtime.EventsEmit(a.ctx, EventCostRecompute, costRecomputeJobToStatus(&job, a.timezoneLocation()))
+}
+
 // emitNotification 发送通知到前端
 func (a *App) emitNotification(level, title, message string) {
-	if a.ctx == nil {
+	if !a.canEmit() {
 		return
 	}
 
@@ -0,0 +1,62 @@
+// app_events_stream.go - 实时事件流（Server-Sent Events）
+// 在代理服务器上暴露 /events，推送请求、端点和组事件，供外部看板、tmux 状态栏等订阅
+
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// eventStreamPath 事件流路径
+const eventStreamPath = "/events"
+
+// registerEventStream 在代理服务器的 mux 上注册事件流
+func (a *App) registerEventStream(mux *http.ServeMux) {
+	if a.eventStream == nil {
+		return
+	}
+	mux.Handle("GET "+eventStreamPath, a.eventStreamAuth(a.eventStream))
+}
+
+// eventStreamAuth 事件流鉴权
+// 接受管理 Token（auth.admin_token）或代理访问 Token（auth.enabled 时的 auth.token），
+// 浏览器 EventSource 无法设置请求头，因此也可以通过 ?token= 传递
+func (a *App) eventStreamAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tokens []string
+		a.mu.RLock()
+		if a.config != nil {
+			if a.config.Auth.AdminToken != "" {
+				tokens = append(tokens, a.config.Auth.AdminToken)
+			}
+			if a.config.Auth.Enabled && a.config.Auth.Token != "" {
+				tokens = append(tokens, a.config.Auth.Token)
+			}
+		}
+		a.mu.RUnlock()
+
+		if len(tokens) == 0 {
+			http.Error(w, "Event stream disabled: configure auth.admin_token or auth.token", http.StatusForbidden)
+			return
+		}
+
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if token == "" {
+			http.Error(w, "Authorization required. Expected 'Bearer <token>' or ?token=<token>", http.StatusUnauthorized)
+			return
+		}
+
+		for _, expected := range tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+	})
+}
@@ -0,0 +1,122 @@
+// app_headless.go - 无界面服务器模式
+// 不启动 Wails 窗口，复用 App 的完整启动流程，适用于无显示环境的 Linux 服务器
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// headlessShutdownTimeout 无界面模式下优雅关闭的最长等待时间
+const headlessShutdownTimeout = 30 * time.Second
+
+// runHeadless 以无界面模式运行应用，阻塞直到收到退出信号
+// SIGINT/SIGTERM: 优雅关闭；SIGHUP: 重新加载配置文件和数据库设置
+// 返回进程退出码
+func runHeadless(app *App) int {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	return serveHeadless(app, sigCh)
+}
+
+// serveHeadless 启动应用并按顺序处理 sigCh 中的信号，直到收到退出信号
+func serveHeadless(app *App, sigCh <-chan os.Signal) int {
+	app.headless = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.startup(ctx)
+
+	if app.proxyServer == nil {
+		fmt.Fprintln(os.Stderr, "Error: 代理服务器启动失败")
+		app.shutdown(context.Background())
+		return 1
+	}
+
+	app.logger.Info("🖥️ 无界面模式运行中 (SIGTERM/SIGINT 退出, SIGHUP 重新加载)")
+
+	for sig := range sigCh {
+		if sig == syscall.SIGHUP {
+			app.reloadFromSignal()
+			continue
+		}
+
+		app.logger.Info("🛑 收到退出信号", "signal", sig.String())
+		cancel()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), headlessShutdownTimeout)
+		app.shutdown(shutdownCtx)
+		shutdownCancel()
+		return 0
+	}
+
+	return 0
+}
+
+// reloadFromSignal 处理 SIGHUP：重新加载配置文件，并从 SQLite 重新同步设置、端点和定价
+func (a *App) reloadFromSignal() {
+	a.logger.Info("🔄 收到 SIGHUP，正在重新加载配置...")
+
+	// 配置文件重载会触发 setupConfigReload 注册的回调（其中会重新应用数据库设置）
+	// 使用嵌入配置启动时临时文件已删除，只重新应用数据库设置
+	if _, err := os.Stat(a.configPath); err == nil && a.configWatcher != nil {
+		if err := a.configWatcher.Reload(); err != nil {
+			a.logger.Error("❌ 配置文件重新加载失败", "error", err)
+		}
+	} else {
+		a.mu.Lock()
+		a.applySettingsToConfig()
+		a.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// 端点数据以 SQLite 为准（可能被其他工具修改过）
+	if a.endpointService != nil {
+		if err := a.endpointService.SyncFromDatabase(ctx); err != nil {
+			a.logger.Warn("⚠️ 从数据库同步端点失败", "error", err)
+		}
+	}
+
+	// 模型定价和端点倍率
+	if a.modelPricingService != nil {
+		if err := a.modelPricingService.LoadCache(ctx); err != nil {
+			a.logger.Warn("⚠️ 加载模型定价缓存失败", "error", err)
+		}
+	}
+	a.syncPricingToTracker(ctx)
+	a.syncEndpointMultipliersToTracker(ctx)
+
+	// 客户端 Key
+	if a.clientKeyService != nil {
+		if err := a.clientKeyService.LoadCache(ctx); err != nil {
+			a.logger.Warn("⚠️ 加载客户端 Key 缓存失败", "error", err)
+		}
+	}
+
+	// 预算
+	if a.budgetService != nil {
+		if err := a.budgetService.LoadCache(ctx); err != nil {
+			a.logger.Warn("⚠️ 加载预算缓存失败", "error", err)
+		}
+		a.budgetService.Refresh(ctx)
+	}
+
+	// 模型路由规则
+	if a.routingRuleService != nil {
+		if err := a.routingRuleService.LoadCache(ctx); err != nil {
+			a.logger.Warn("⚠️ 加载路由规则缓存失败", "error", err)
+		}
+	}
+
+	a.logger.Info("✅ SIGHUP 重新加载完成")
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/service"
+)
+
+// headlessTestConfig 无界面模式测试用的最小配置
+const headlessTestConfig = `
+server:
+  host: "127.0.0.1"
+  port: %d
+logging:
+  level: "warn"
+openai_compat:
+  enabled: true
+  default_max_tokens: %d
+usage_tracking:
+  enabled: false
+endpoints:
+  - name: "primary"
+    url: "http://127.0.0.1:1"
+    priority: 1
+    token: "sk-test"
+`
+
+// freeTestPort 获取一个空闲的本地端口
+func freeTestPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("获取空闲端口失败: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// TestServeHeadless_SIGHUPReload 收到 SIGHUP 时重新加载配置文件，SIGTERM 时正常退出
+// 重新加载会触发 config:reloaded 推送，无界面模式下调用 Wails 运行时会直接终止测试进程
+func TestServeHeadless_SIGHUPReload(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	port := freeTestPort(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(headlessTestConfig, port, 4096)), 0o644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	app := NewApp()
+	app.configPath = configPath
+
+	sigCh := make(chan os.Signal)
+	exitCode := make(chan int, 1)
+	go func() { exitCode <- serveHeadless(app, sigCh) }()
+
+	// 无缓冲通道：发送成功说明启动已完成，信号循环开始接收
+	sendSignal := func(sig os.Signal) {
+		select {
+		case sigCh <- sig:
+		case code := <-exitCode:
+			t.Fatalf("无界面模式提前退出: %d", code)
+		case <-time.After(30 * time.Second):
+			t.Fatalf("发送 %v 超时", sig)
+		}
+	}
+
+	sendSignal(syscall.SIGHUP)
+
+	// 通过重命名替换配置文件：文件监听只响应写入事件，确保新配置由 SIGHUP 加载
+	updatedPath := configPath + ".new"
+	if err := os.WriteFile(updatedPath, []byte(fmt.Sprintf(headlessTestConfig, port, 2048)), 0o644); err != nil {
+		t.Fatalf("写入新配置文件失败: %v", err)
+	}
+	if err := os.Rename(updatedPath, configPath); err != nil {
+		t.Fatalf("替换配置文件失败: %v", err)
+	}
+	time.Sleep(time.Second)
+	if got := headlessMaxTokens(app); got != 4096 {
+		t.Fatalf("SIGHUP 前不应重新加载配置, default_max_tokens = %d", got)
+	}
+	sendSignal(syscall.SIGHUP)
+	sendSignal(syscall.SIGTERM)
+
+	select {
+	case code := <-exitCode:
+		if code != 0 {
+			t.Errorf("退出码应为 0, 实际 %d", code)
+		}
+	case <-time.After(60 * time.Second):
+		t.Fatal("收到 SIGTERM 后未退出")
+	}
+
+	if got := headlessMaxTokens(app); got != 2048 {
+		t.Errorf("SIGHUP 后应加载新配置, default_max_tokens = %d", got)
+	}
+	if app.config.Server.Port != port {
+		t.Errorf("重新加载不应修改监听端口: %d", app.config.Server.Port)
+	}
+}
+
+// headlessMaxTokens 读取当前配置中的 default_max_tokens
+func headlessMaxTokens(app *App) int {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.config.OpenAICompat.DefaultMaxTokens
+}
+
+// TestCanEmit_Headless 无界面模式下不向 Wails 推送任何事件
+// runtime.EventsEmit 在非 Wails 上下文中会直接终止进程，测试能结束即说明没有调用
+func TestCanEmit_Headless(t *testing.T) {
+	app := &App{ctx: context.Background(), headless: true}
+	if app.canEmit() {
+		t.Fatal("无界面模式下 canEmit 应返回 false")
+	}
+
+	app.emitSystemStatus()
+	app.emitEndpointUpdate()
+	app.emitGroupUpdate()
+	app.emitUsageUpdate()
+	app.emitCostRecompute(service.CostRecomputeJob{})
+	app.emitNotification("info", "test", "message")
+	app.emitError("test", "message")
+	app.emitConfigReloaded()
+
+	if (&App{}).canEmit() {
+		t.Error("未启动时 canEmit 应返回 false")
+	}
+	if !(&App{ctx: context.Background()}).canEmit() {
+		t.Error("桌面模式启动后 canEmit 应返回 true")
+	}
+}
@@ -0,0 +1,312 @@
+// app_replay.go - 抓取重放命令
+// cc-forwarder replay -id <请求ID> [-endpoint <端点名称> | -url <地址>]
+// 未指定端点时离线重放抓取的 SSE 响应（经过 StreamProcessor/TokenParser），指定端点时重新发送抓取的请求
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/capture"
+	"cc-forwarder/internal/proxy"
+	"cc-forwarder/internal/proxy/response"
+	"cc-forwarder/internal/service"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/transport"
+)
+
+// replayOutputLimit 非流式响应最多打印的字节数
+const replayOutputLimit = 4096
+
+// runReplay 执行 replay 子命令，返回进程退出码
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	cfgPath := fs.String("config", "config/config.yaml", "配置文件路径（用于定位抓取目录和端点）")
+	requestID := fs.String("id", "", "要重放的请求 ID（从抓取目录读取）")
+	file := fs.String("file", "", "要重放的抓取文件路径（代替 -id）")
+	attemptIndex := fs.Int("attempt", -1, "重放第几次尝试（从 0 开始，负数从末尾计数）")
+	endpointName := fs.String("endpoint", "", "重新发送到指定端点（名称，从配置文件或数据库查找）")
+	targetURL := fs.String("url", "", "重新发送到指定地址（代替 -endpoint）")
+	token := fs.String("token", "", "配合 -url 使用的 Bearer Token")
+	realtime := fs.Bool("realtime", false, "离线重放时按记录的到达时间间隔输出数据块")
+	printOutput := fs.Bool("output", false, "打印流式处理后转发给客户端的数据")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: %s replay (-id <请求ID> | -file <抓取文件>) [选项]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "未指定 -endpoint/-url 时离线重放抓取的流式响应，否则重新发送抓取的请求")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if (*requestID == "") == (*file == "") {
+		fmt.Fprintln(os.Stderr, "Error: 需要指定 -id 或 -file 其中之一")
+		fs.Usage()
+		return 2
+	}
+	if *endpointName != "" && *targetURL != "" {
+		fmt.Fprintln(os.Stderr, "Error: -endpoint 和 -url 不能同时指定")
+		return 2
+	}
+
+	// 配置文件仅在按 ID 读取或按名称查找端点时必需
+	cfg, cfgErr := config.LoadConfig(*cfgPath)
+	if cfgErr == nil {
+		applyAppDirPaths(cfg)
+	}
+
+	var c *capture.Capture
+	var err error
+	if *file != "" {
+		c, err = capture.LoadFile(*file)
+	} else if cfgErr != nil {
+		err = fmt.Errorf("加载配置失败: %w", cfgErr)
+	} else {
+		c, err = capture.NewStore(cfg.Capture.Dir, 0).Get(*requestID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	attempt, err := c.Attempt(*attemptIndex)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("📼 抓取记录: %s (第 %d/%d 次尝试)\n", c.RequestID, indexOf(c, attempt)+1, len(c.Attempts))
+	fmt.Printf("   原始端点: %s, 请求: %s %s\n", attempt.Endpoint, attempt.Request.Method, attempt.Request.URL)
+
+	if *endpointName == "" && *targetURL == "" {
+		err = replayOffline(ctx, c.RequestID, attempt, *realtime, *printOutput)
+	} else {
+		var ep *config.EndpointConfig
+		if *targetURL != "" {
+			ep = &config.EndpointConfig{Name: "replay", URL: *targetURL, Token: *token}
+		} else if cfgErr != nil {
+			err = fmt.Errorf("加载配置失败: %w", cfgErr)
+		} else {
+			ep, err = findReplayEndpoint(ctx, cfg, *endpointName)
+		}
+		if err == nil {
+			if cfg == nil {
+				cfg = &config.Config{}
+			}
+			err = replayOnline(ctx, cfg, ep, c.RequestID, attempt, *printOutput)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// replayOffline 离线重放抓取的响应
+func replayOffline(ctx context.Context, requestID string, attempt *capture.Attempt, realtime, printOutput bool) error {
+	if attempt.Response == nil {
+		return fmt.Errorf("该次尝试没有响应: %s", attempt.Error)
+	}
+
+	resp := attempt.Response
+	fmt.Printf("   原始响应: %d, %d 个数据块, 耗时 %dms, 完整: %v\n", resp.StatusCode, len(resp.Chunks), attempt.DurationMs, resp.Complete)
+	if resp.Truncated {
+		fmt.Println("⚠️ 响应体超过 max_body_size 已被截断，重放结果可能不完整")
+	}
+	if attempt.Error != "" {
+		fmt.Printf("   原始错误: %s\n", attempt.Error)
+	}
+
+	if !resp.IsStream() {
+		fmt.Println("ℹ️ 非流式响应，离线重放只输出响应体")
+		httpResp, err := capture.NewResponse(ctx, attempt, false)
+		if err != nil {
+			return err
+		}
+		return printResponseBody(httpResp)
+	}
+
+	fmt.Println("🔁 离线重放流式响应...")
+	result, err := proxy.ReplayCapturedStream(ctx, requestID, attempt, realtime)
+	if err != nil {
+		return err
+	}
+	printStreamReplayResult(result, printOutput)
+	return nil
+}
+
+// replayOnline 重新发送抓取的请求到指定端点
+func replayOnline(ctx context.Context, cfg *config.Config, ep *config.EndpointConfig, requestID string, attempt *capture.Attempt, printOutput bool) error {
+	req, err := capture.NewReplayRequest(ctx, attempt, ep.URL)
+	if err != nil {
+		return err
+	}
+	req.Host = req.URL.Host
+
+	if token := replayToken(ep); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if apiKey := replayApiKey(ep); apiKey != "" {
+		req.Header.Set("X-Api-Key", apiKey)
+	}
+	for key, value := range ep.Headers {
+		req.Header.Set(key, value)
+	}
+
+	httpTransport, err := transport.NewTransport(cfg, ep, transport.ProfileStreaming)
+	if err != nil {
+		return err
+	}
+	defer httpTransport.CloseIdleConnections()
+
+	fmt.Printf("🚀 重新发送到 %s: %s %s\n", ep.Name, req.Method, req.URL)
+	start := time.Now()
+	resp, err := (&http.Client{Transport: httpTransport}).Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	fmt.Printf("   响应: %d, 首字节耗时 %v\n", resp.StatusCode, time.Since(start).Round(time.Millisecond))
+
+	if resp.StatusCode < 400 && strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		result := proxy.ReplayStream(ctx, resp, requestID, ep.Name)
+		printStreamReplayResult(result, printOutput)
+		return nil
+	}
+	return printResponseBody(resp)
+}
+
+// findReplayEndpoint 按名称查找端点：先查配置文件，再查 SQLite 端点存储
+func findReplayEndpoint(ctx context.Context, cfg *config.Config, name string) (*config.EndpointConfig, error) {
+	for i := range cfg.Endpoints {
+		if cfg.Endpoints[i].Name == name {
+			return &cfg.Endpoints[i], nil
+		}
+	}
+
+	if _, err := os.Stat(cfg.UsageTracking.DatabasePath); err != nil {
+		return nil, fmt.Errorf("端点 '%s' 不存在", name)
+	}
+
+	db, err := sql.Open("sqlite", cfg.UsageTracking.DatabasePath+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库失败: %w", err)
+	}
+	defer db.Close()
+
+	record, err := store.NewSQLiteEndpointStore(db).Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("查询端点失败: %w", err)
+	}
+	if record == nil {
+		return nil, fmt.Errorf("端点 '%s' 不存在", name)
+	}
+
+	return &config.EndpointConfig{
+		Name:    record.Name,
+		URL:     record.URL,
+		Token:   record.Token,
+		ApiKey:  record.ApiKey,
+		Headers: record.Headers,
+		Timeout: time.Duration(record.TimeoutSeconds) * time.Second,
+		Proxy:   service.EndpointProxyToConfig(record.Proxy),
+	}, nil
+}
+
+// replayToken 端点的 Token（多 Token 端点使用第一个）
+func replayToken(ep *config.EndpointConfig) string {
+	if ep.Token == "" && len(ep.Tokens) > 0 {
+		return ep.Tokens[0].Value
+	}
+	return ep.Token
+}
+
+// replayApiKey 端点的 API Key（多 Key 端点使用第一个）
+func replayApiKey(ep *config.EndpointConfig) string {
+	if ep.ApiKey == "" && len(ep.ApiKeys) > 0 {
+		return ep.ApiKeys[0].Value
+	}
+	return ep.ApiKey
+}
+
+// printStreamReplayResult 打印流式重放结果
+func printStreamReplayResult(result *proxy.StreamReplayResult, printOutput bool) {
+	if printOutput {
+		fmt.Println("---------------- 输出 ----------------")
+		os.Stdout.Write(result.Output)
+		fmt.Println("--------------------------------------")
+	}
+
+	fmt.Printf("   模型: %s, 输出 %d 字节, 耗时 %v\n", result.Model, len(result.Output), result.Duration.Round(time.Millisecond))
+	if result.TokenUsage != nil {
+		fmt.Printf("   Token: 输入 %d, 输出 %d, 缓存创建 %d, 缓存读取 %d\n",
+			result.TokenUsage.InputTokens, result.TokenUsage.OutputTokens,
+			result.TokenUsage.CacheCreationTokens, result.TokenUsage.CacheReadTokens)
+	} else {
+		fmt.Println("   Token: 未解析到使用统计")
+	}
+
+	if result.Complete {
+		fmt.Println("✅ 流完整")
+		return
+	}
+
+	var incomplete *proxy.StreamIncompleteError
+	if errors.As(result.Err, &incomplete) {
+		fmt.Printf("⚠️ 流不完整: %s (%s)\n", incomplete.GetReason(), incomplete.GetFailureReason())
+		return
+	}
+	fmt.Printf("❌ 流处理失败: %v\n", result.Err)
+}
+
+// printResponseBody 打印非流式响应体（已解压，超出部分截断）
+func printResponseBody(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	reader, err := response.NewProcessor().DecompressStreamReader(resp)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil && len(body) == 0 {
+		return fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	fmt.Printf("   响应体 %d 字节:\n", len(body))
+	if len(body) > replayOutputLimit {
+		fmt.Printf("%s\n... (已截断)\n", body[:replayOutputLimit])
+	} else {
+		fmt.Printf("%s\n", body)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ 响应体读取中断: %v\n", err)
+	}
+	return nil
+}
+
+// indexOf 尝试在抓取记录中的序号
+func indexOf(c *capture.Capture, attempt *capture.Attempt) int {
+	for i, a := range c.Attempts {
+		if a == attempt {
+			return i
+		}
+	}
+	return -1
+}
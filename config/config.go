@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -26,6 +27,13 @@ type Config struct {
 	RequestSuspend   RequestSuspendConfig   `yaml:"request_suspend"`         // Request suspension configuration
 	UsageTracking    UsageTrackingConfig    `yaml:"usage_tracking"`          // Usage tracking configuration
 	TokenCounting    TokenCountingConfig    `yaml:"token_counting"`          // Token counting configuration
+	OpenAICompat     OpenAICompatConfig     `yaml:"openai_compat"`           // OpenAI Chat Completions compatibility
+	Budget           BudgetConfig           `yaml:"budget"`                  // Spend budgets and quotas
+	KeyRotation      KeyRotationConfig      `yaml:"key_rotation"`            // Automatic multi-key rotation
+	CircuitBreaker   CircuitBreakerConfig   `yaml:"circuit_breaker"`         // Per-endpoint circuit breaker driven by live traffic
+	Tracing          TracingConfig          `yaml:"tracing"`                 // OpenTelemetry tracing
+	ResponseCache    ResponseCacheConfig    `yaml:"response_cache"`          // Local cache for deterministic non-streaming responses
+	Capture          CaptureConfig          `yaml:"capture"`                 // Request/response capture for offline replay
 	EndpointsStorage EndpointsStorageConfig `yaml:"endpoints_storage"`       // Endpoints storage configuration (v5.0+)
 	Proxy            ProxyConfig            `yaml:"proxy"`
 	Auth             AuthConfig             `yaml:"auth"`
@@ -44,11 +52,12 @@ type ServerConfig struct {
 }
 
 type StrategyConfig struct {
-	Type              string        `yaml:"type"` // "priority" or "fastest"
+	Type              string        `yaml:"type"` // "priority", "fastest", "weighted", "least_outstanding" or "ewma"
 	FastTestEnabled   bool          `yaml:"fast_test_enabled"`   // Enable pre-request fast testing
 	FastTestCacheTTL  time.Duration `yaml:"fast_test_cache_ttl"` // Cache TTL for fast test results
 	FastTestTimeout   time.Duration `yaml:"fast_test_timeout"`   // Timeout for individual fast tests
 	FastTestPath      string        `yaml:"fast_test_path"`      // Path for fast testing (default: health path)
+	RateLimitReserve  float64       `yaml:"rate_limit_reserve"`  // 上游剩余请求/Token 额度低于上限的该比例时，端点排到选择顺序末尾，默认 0.1
 }
 
 type RetryConfig struct {
@@ -56,6 +65,35 @@ type RetryConfig struct {
 	BaseDelay   time.Duration `yaml:"base_delay"`
 	MaxDelay    time.Duration `yaml:"max_delay"`
 	Multiplier  float64       `yaml:"multiplier"`
+	Policy      []RetryRule   `yaml:"policy,omitempty"` // 重试策略规则，优先于内置默认策略（端点 retry_policy 优先于此处）
+}
+
+// 重试策略动作
+const (
+	RetryActionRetry   = "retry"   // 在同一端点重试，达到 max_attempts 后执行 then
+	RetryActionSwitch  = "switch"  // 切换到下一个端点
+	RetryActionSuspend = "suspend" // 挂起请求等待端点恢复或组切换，无法挂起时切换端点
+	RetryActionFail    = "fail"    // 终止请求，直接返回错误
+)
+
+// RetryErrorTypes 重试策略可匹配的错误类型（client_cancel 始终立即停止，不可配置）
+var RetryErrorTypes = []string{
+	"network", "eof", "connection_timeout", "response_timeout", "timeout", "http",
+	"server_error", "stream", "auth", "rate_limit", "parsing", "no_healthy_endpoints", "unknown",
+}
+
+// RetryRule 重试策略规则
+// match 可以是状态码（"404"）、状态码类别（"4xx"）或错误类型（"network"、"eof"、"rate_limit" 等）
+// 匹配优先级：状态码 > 状态码类别 > 错误类型
+type RetryRule struct {
+	Match        string        `yaml:"match" json:"match"`
+	Action       string        `yaml:"action" json:"action"`                                   // retry | switch | suspend | fail
+	MaxAttempts  int           `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`   // 同端点最大尝试次数，0 表示使用 retry.max_attempts
+	Then         string        `yaml:"then,omitempty" json:"then,omitempty"`                   // 重试达到上限后的动作: switch | suspend | fail，默认 switch
+	BaseDelay    time.Duration `yaml:"base_delay,omitempty" json:"base_delay,omitempty"`       // 退避基础延迟，0 表示使用 retry.base_delay；switch 规则设置时切换前按退避等待
+	MaxDelay     time.Duration `yaml:"max_delay,omitempty" json:"max_delay,omitempty"`         // 退避最大延迟，0 表示使用 retry.max_delay
+	Multiplier   float64       `yaml:"multiplier,omitempty" json:"multiplier,omitempty"`       // 退避倍数，0 表示使用 retry.multiplier
+	SuspendAfter time.Duration `yaml:"suspend_after,omitempty" json:"suspend_after,omitempty"` // 退避延迟超过该值时尝试挂起请求，0 表示不挂起
 }
 
 type HealthConfig struct {
@@ -179,8 +217,9 @@ type ProxyConfig struct {
 }
 
 type AuthConfig struct {
-	Enabled bool   `yaml:"enabled"`                   // Enable authentication, default: false
-	Token   string `yaml:"token,omitempty"`           // Bearer token for authentication
+	Enabled    bool   `yaml:"enabled"`                   // Enable authentication, default: false
+	Token      string `yaml:"token,omitempty"`           // Bearer token for authentication
+	AdminToken string `yaml:"admin_token,omitempty"`     // Bearer token for the /admin/api/v1 management API, empty disables it
 }
 
 // TUIConfig is DEPRECATED - TUI has been removed in v4.0
@@ -197,6 +236,69 @@ type TokenCountingConfig struct {
 	EstimationRatio float64 `yaml:"estimation_ratio"` // Token估算比例 (1 token ≈ N 字符)
 }
 
+// OpenAICompatConfig OpenAI 兼容配置
+// 启用后 /v1/chat/completions 请求会被转换为 Anthropic Messages 格式转发
+type OpenAICompatConfig struct {
+	Enabled          bool `yaml:"enabled"`            // 启用 /v1/chat/completions 转换
+	DefaultMaxTokens int  `yaml:"default_max_tokens"` // 请求未指定 max_tokens 时的默认值
+}
+
+// BudgetConfig 预算配置
+// 预算本身存储在 SQLite 中，这里只控制执行开关和消费刷新间隔
+type BudgetConfig struct {
+	Enabled         bool          `yaml:"enabled"`          // 启用预算执行（超过硬限额时拒绝请求）
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // 消费统计刷新间隔，默认 10s
+}
+
+// KeyRotationConfig 多 Key 自动轮换配置
+// 作用于配置了多个 tokens/api-keys 的端点
+type KeyRotationConfig struct {
+	Enabled  bool          `yaml:"enabled"`  // 启用自动轮换（429/402 冷却 Key，401/403 标记 Key 无效）
+	Strategy string        `yaml:"strategy"` // 分配策略: "failover"(固定当前 Key，不可用时切换) | "round_robin" | "least_used"
+	Cooldown time.Duration `yaml:"cooldown"` // 限流 Key 的冷却时间（响应未带 Retry-After 时使用），默认 60s
+}
+
+// CircuitBreakerConfig 端点熔断器配置
+// 按真实请求的滚动错误率和慢请求比例打开熔断，打开期间端点不参与选择，到期后半开放行少量试探请求
+type CircuitBreakerConfig struct {
+	Enabled          bool          `yaml:"enabled"`            // 启用熔断器
+	Window           time.Duration `yaml:"window"`             // 滚动统计窗口，默认 60s
+	MinRequests      int           `yaml:"min_requests"`       // 窗口内请求数达到该值才判定，默认 10
+	ErrorRate        float64       `yaml:"error_rate"`         // 错误率阈值 0~1（5xx、429、连接错误），默认 0.5
+	SlowCallDuration time.Duration `yaml:"slow_call_duration"` // 慢请求阈值（响应头耗时），默认 60s
+	SlowCallRate     float64       `yaml:"slow_call_rate"`     // 慢请求比例阈值 0~1，默认 0.8
+	OpenDuration     time.Duration `yaml:"open_duration"`      // 打开后进入半开前的等待时间，默认 30s
+	HalfOpenRequests int           `yaml:"half_open_requests"` // 半开状态放行的试探请求数，全部成功后关闭，默认 3
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置
+// 启用后请求生命周期（重试、挂起、流式传输）以 span 形式通过 OTLP/HTTP 导出
+type TracingConfig struct {
+	Enabled     bool              `yaml:"enabled"`      // 启用链路追踪
+	Endpoint    string            `yaml:"endpoint"`     // OTLP/HTTP 采集器地址，如 http://localhost:4318（未带路径时使用 /v1/traces）
+	ServiceName string            `yaml:"service_name"` // 上报的服务名称，默认 cc-forwarder
+	SampleRatio *float64          `yaml:"sample_ratio"` // 采样比例 0~1，默认 1（客户端已携带 traceparent 时沿用其采样决定）
+	Headers     map[string]string `yaml:"headers"`      // 导出请求附加的头部（如采集器鉴权）
+	Timeout     time.Duration     `yaml:"timeout"`      // 单次导出超时，默认 10s
+}
+
+// ResponseCacheConfig 响应缓存配置
+// 缓存确定性的非流式请求（temperature 为 0 的 /v1/messages 和 count_tokens），条目存储在 SQLite 中
+type ResponseCacheConfig struct {
+	Enabled    bool          `yaml:"enabled"`     // 启用响应缓存
+	TTL        time.Duration `yaml:"ttl"`         // 缓存有效期，默认 1h
+	MaxEntries int           `yaml:"max_entries"` // 最大条目数（超出时淘汰最久未使用的条目），默认 1000
+}
+
+// CaptureConfig 请求/响应抓取配置
+// 启用后记录转发到端点的完整请求和原始响应（含 SSE 字节流的到达时间），按请求 ID 保存，用于重放和复现问题
+type CaptureConfig struct {
+	Enabled     bool   `yaml:"enabled"`       // 启用抓取
+	Dir         string `yaml:"dir"`           // 抓取文件目录，默认为数据目录下的 captures
+	MaxFiles    int    `yaml:"max_files"`     // 最多保留的抓取文件数（超出时删除最旧的文件），默认 200
+	MaxBodySize int64  `yaml:"max_body_size"` // 单个请求体/响应体最多记录的字节数，默认 10MB
+}
+
 // EndpointsStorageConfig 端点存储配置 (v5.0+)
 // 支持从 YAML 文件或 SQLite 数据库加载端点配置
 type EndpointsStorageConfig struct {
@@ -208,6 +310,7 @@ type EndpointConfig struct {
 	URL                 string            `yaml:"url"`
 	Channel             string            `yaml:"channel,omitempty"`        // v5.0: 渠道标签（用于分组展示）
 	Priority            int               `yaml:"priority"`
+	Weight              int               `yaml:"weight,omitempty"`         // 权重（weighted 策略下同优先级内按权重分配流量），默认: 1
 	Group               string            `yaml:"group,omitempty"`          // DEPRECATED in v4.0
 	GroupPriority       int               `yaml:"group-priority,omitempty"` // DEPRECATED in v4.0: use Priority instead
 	FailoverEnabled     *bool             `yaml:"failover_enabled,omitempty"` // v4.0: 是否参与故障转移，默认: true
@@ -217,9 +320,15 @@ type EndpointConfig struct {
 	Tokens              []TokenConfig     `yaml:"tokens,omitempty"`     // 多 Token 配置（新功能）
 	ApiKeys             []ApiKeyConfig    `yaml:"api-keys,omitempty"`   // 多 API Key 配置（新功能）
 	Timeout             time.Duration     `yaml:"timeout"`
+	MaxConcurrency      int               `yaml:"max_concurrency,omitempty"` // 最大并发请求数（可选），0 表示不限制
+	RPM                 int               `yaml:"rpm,omitempty"`             // 每分钟请求数上限（可选），0 表示不限制
+	TPM                 int               `yaml:"tpm,omitempty"`             // 每分钟 Token 数上限（可选），0 表示不限制
 	Headers             map[string]string `yaml:"headers,omitempty"`
+	ModelMapping        map[string]string `yaml:"model_mapping,omitempty"`         // 模型名称映射：客户端模型 -> 该端点的上游模型名称
+	RetryPolicy         []RetryRule       `yaml:"retry_policy,omitempty"`          // 端点级重试策略规则（可选），优先于全局 retry.policy
 	SupportsCountTokens bool              `yaml:"supports_count_tokens,omitempty"` // 是否支持count_tokens端点
 	Enabled             *bool             `yaml:"enabled,omitempty"`               // v5.0: 是否激活为代理端点（SQLite模式），默认: true
+	Proxy               *ProxyConfig      `yaml:"proxy,omitempty"`                 // 端点级代理（可选）：未配置时使用全局代理，enabled=false 表示直连
 }
 
 // TokenConfig Token 配置项，用于多 Token 切换功能
@@ -298,6 +407,9 @@ func (c *Config) setDefaults() {
 	if c.Strategy.FastTestPath == "" {
 		c.Strategy.FastTestPath = c.Health.HealthPath // Default to health path
 	}
+	if c.Strategy.RateLimitReserve == 0 {
+		c.Strategy.RateLimitReserve = 0.1
+	}
 	if c.Retry.MaxAttempts == 0 {
 		c.Retry.MaxAttempts = 3
 	}
@@ -438,6 +550,88 @@ func (c *Config) setDefaults() {
 	}
 	// TokenCounting.Enabled defaults to false (zero value) for backward compatibility
 
+	// Set OpenAI compatibility defaults
+	if c.OpenAICompat.DefaultMaxTokens == 0 {
+		c.OpenAICompat.DefaultMaxTokens = 4096
+	}
+	// OpenAICompat.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set budget defaults
+	if c.Budget.RefreshInterval == 0 {
+		c.Budget.RefreshInterval = 10 * time.Second
+	}
+	// Budget.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set key rotation defaults
+	if c.KeyRotation.Strategy == "" {
+		c.KeyRotation.Strategy = "failover"
+	}
+	if c.KeyRotation.Cooldown == 0 {
+		c.KeyRotation.Cooldown = 60 * time.Second
+	}
+	// KeyRotation.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set circuit breaker defaults
+	if c.CircuitBreaker.Window == 0 {
+		c.CircuitBreaker.Window = 60 * time.Second
+	}
+	if c.CircuitBreaker.MinRequests == 0 {
+		c.CircuitBreaker.MinRequests = 10
+	}
+	if c.CircuitBreaker.ErrorRate == 0 {
+		c.CircuitBreaker.ErrorRate = 0.5
+	}
+	if c.CircuitBreaker.SlowCallDuration == 0 {
+		c.CircuitBreaker.SlowCallDuration = 60 * time.Second
+	}
+	if c.CircuitBreaker.SlowCallRate == 0 {
+		c.CircuitBreaker.SlowCallRate = 0.8
+	}
+	if c.CircuitBreaker.OpenDuration == 0 {
+		c.CircuitBreaker.OpenDuration = 30 * time.Second
+	}
+	if c.CircuitBreaker.HalfOpenRequests == 0 {
+		c.CircuitBreaker.HalfOpenRequests = 3
+	}
+	// CircuitBreaker.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set tracing defaults
+	if c.Tracing.Endpoint == "" {
+		c.Tracing.Endpoint = "http://localhost:4318"
+	}
+	if c.Tracing.ServiceName == "" {
+		c.Tracing.ServiceName = "cc-forwarder"
+	}
+	if c.Tracing.SampleRatio == nil {
+		ratio := 1.0
+		c.Tracing.SampleRatio = &ratio
+	}
+	if c.Tracing.Timeout == 0 {
+		c.Tracing.Timeout = 10 * time.Second
+	}
+	// Tracing.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set response cache defaults
+	if c.ResponseCache.TTL == 0 {
+		c.ResponseCache.TTL = time.Hour
+	}
+	if c.ResponseCache.MaxEntries == 0 {
+		c.ResponseCache.MaxEntries = 1000
+	}
+	// ResponseCache.Enabled defaults to false (zero value) for backward compatibility
+
+	// Set capture defaults
+	if c.Capture.Dir == "" {
+		c.Capture.Dir = filepath.Join(getConfigAppDataDir(), "data", "captures")
+	}
+	if c.Capture.MaxFiles == 0 {
+		c.Capture.MaxFiles = 200
+	}
+	if c.Capture.MaxBodySize == 0 {
+		c.Capture.MaxBodySize = 10 * 1024 * 1024
+	}
+	// Capture.Enabled defaults to false (zero value) for backward compatibility
+
 	// Set default timeouts for endpoints and handle parameter inheritance (except tokens)
 	var defaultEndpoint *EndpointConfig
 	if len(c.Endpoints) > 0 {
@@ -574,6 +768,93 @@ func (c *Config) findEndpointIndex(name string) int {
 	return -1
 }
 
+// ValidateRetryPolicy 校验重试策略规则
+func ValidateRetryPolicy(rules []RetryRule) error {
+	for i, rule := range rules {
+		if !isValidRetryMatch(rule.Match) {
+			return fmt.Errorf("rules[%d]: 无效的 match '%s'，应为状态码（如 404）、状态码类别（如 4xx）或错误类型（%s）",
+				i, rule.Match, strings.Join(RetryErrorTypes, ", "))
+		}
+		switch rule.Action {
+		case RetryActionRetry, RetryActionSwitch, RetryActionSuspend, RetryActionFail:
+		default:
+			return fmt.Errorf("rules[%d]: action 必须是 retry、switch、suspend 或 fail", i)
+		}
+		switch rule.Then {
+		case "", RetryActionSwitch, RetryActionSuspend, RetryActionFail:
+		default:
+			return fmt.Errorf("rules[%d]: then 必须是 switch、suspend 或 fail", i)
+		}
+		if rule.MaxAttempts < 0 || rule.BaseDelay < 0 || rule.MaxDelay < 0 || rule.Multiplier < 0 || rule.SuspendAfter < 0 {
+			return fmt.Errorf("rules[%d]: max_attempts、延迟和倍数不能为负数", i)
+		}
+	}
+	return nil
+}
+
+// isValidRetryMatch 检查 match 是否为有效的状态码、状态码类别或错误类型
+func isValidRetryMatch(match string) bool {
+	match = strings.ToLower(strings.TrimSpace(match))
+	if len(match) == 3 && match[0] >= '1' && match[0] <= '5' {
+		if match[1:] == "xx" {
+			return true
+		}
+		if match[1] >= '0' && match[1] <= '9' && match[2] >= '0' && match[2] <= '9' {
+			return true
+		}
+	}
+	for _, errorType := range RetryErrorTypes {
+		if match == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetryPolicy 解析重试策略文本（YAML 或 JSON 格式的规则数组），空文本返回 nil
+func ParseRetryPolicy(text string) ([]RetryRule, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var rules []RetryRule
+	if err := yaml.Unmarshal([]byte(text), &rules); err != nil {
+		return nil, fmt.Errorf("解析重试策略失败: %w", err)
+	}
+	if err := ValidateRetryPolicy(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FormatRetryPolicy 将重试策略规则格式化为 YAML 文本，未配置时返回空字符串
+func FormatRetryPolicy(rules []RetryRule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ValidateProxy validates a proxy configuration (global or per-endpoint)
+func ValidateProxy(p ProxyConfig) error {
+	if !p.Enabled {
+		return nil
+	}
+	if p.Type == "" {
+		return fmt.Errorf("proxy type is required when proxy is enabled")
+	}
+	if p.Type != "http" && p.Type != "https" && p.Type != "socks5" {
+		return fmt.Errorf("proxy type must be 'http', 'https', or 'socks5'")
+	}
+	if p.URL == "" && (p.Host == "" || p.Port == 0) {
+		return fmt.Errorf("proxy URL or host:port must be specified when proxy is enabled")
+	}
+	return nil
+}
+
 // validate validates the configuration
 func (c *Config) validate() error {
 	// v5.0+: 当使用 SQLite 存储端点时，允许 YAML 中 endpoints 为空
@@ -582,20 +863,79 @@ func (c *Config) validate() error {
 		return fmt.Errorf("at least one endpoint must be configured (or set endpoints_storage.type: sqlite)")
 	}
 
-	if c.Strategy.Type != "priority" && c.Strategy.Type != "fastest" {
-		return fmt.Errorf("strategy type must be 'priority' or 'fastest'")
+	switch c.Strategy.Type {
+	case "priority", "fastest", "weighted", "least_outstanding", "ewma":
+	default:
+		return fmt.Errorf("strategy type must be 'priority', 'fastest', 'weighted', 'least_outstanding' or 'ewma'")
+	}
+	if c.Strategy.RateLimitReserve < 0 || c.Strategy.RateLimitReserve > 1 {
+		return fmt.Errorf("strategy rate_limit_reserve must be between 0 and 1")
 	}
 
-	// Validate proxy configuration
-	if c.Proxy.Enabled {
-		if c.Proxy.Type == "" {
-			return fmt.Errorf("proxy type is required when proxy is enabled")
+	switch c.KeyRotation.Strategy {
+	case "", "failover", "round_robin", "least_used":
+	default:
+		return fmt.Errorf("key_rotation strategy must be 'failover', 'round_robin' or 'least_used'")
+	}
+
+	// Validate retry policy
+	if err := ValidateRetryPolicy(c.Retry.Policy); err != nil {
+		return fmt.Errorf("retry policy: %w", err)
+	}
+
+	// Validate circuit breaker configuration
+	if c.CircuitBreaker.Window < 0 || c.CircuitBreaker.SlowCallDuration < 0 || c.CircuitBreaker.OpenDuration < 0 {
+		return fmt.Errorf("circuit_breaker durations must be positive")
+	}
+	if c.CircuitBreaker.MinRequests < 0 || c.CircuitBreaker.HalfOpenRequests < 0 {
+		return fmt.Errorf("circuit_breaker min_requests and half_open_requests must be positive")
+	}
+	if c.CircuitBreaker.ErrorRate < 0 || c.CircuitBreaker.ErrorRate > 1 {
+		return fmt.Errorf("circuit_breaker error_rate must be between 0 and 1")
+	}
+	if c.CircuitBreaker.SlowCallRate < 0 || c.CircuitBreaker.SlowCallRate > 1 {
+		return fmt.Errorf("circuit_breaker slow_call_rate must be between 0 and 1")
+	}
+
+	// Validate tracing configuration
+	if c.Tracing.Enabled {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tracing endpoint must be an http(s) URL, got '%s'", c.Tracing.Endpoint)
 		}
-		if c.Proxy.Type != "http" && c.Proxy.Type != "https" && c.Proxy.Type != "socks5" {
-			return fmt.Errorf("proxy type must be 'http', 'https', or 'socks5'")
+		if c.Tracing.SampleRatio != nil && (*c.Tracing.SampleRatio < 0 || *c.Tracing.SampleRatio > 1) {
+			return fmt.Errorf("tracing sample_ratio must be between 0 and 1")
+		}
+	}
+
+	// Validate response cache configuration
+	if c.ResponseCache.TTL < 0 {
+		return fmt.Errorf("response_cache ttl must be positive")
+	}
+	if c.ResponseCache.MaxEntries < 0 {
+		return fmt.Errorf("response_cache max_entries must be positive")
+	}
+
+	// Validate capture configuration
+	if c.Capture.MaxFiles < 0 {
+		return fmt.Errorf("capture max_files must be positive")
+	}
+	if c.Capture.MaxBodySize < 0 {
+		return fmt.Errorf("capture max_body_size must be positive")
+	}
+
+	// Validate proxy configuration
+	if err := ValidateProxy(c.Proxy); err != nil {
+		return err
+	}
+
+	// Validate per-endpoint proxy overrides
+	for _, endpoint := range c.Endpoints {
+		if endpoint.Proxy == nil {
+			continue
 		}
-		if c.Proxy.URL == "" && (c.Proxy.Host == "" || c.Proxy.Port == 0) {
-			return fmt.Errorf("proxy URL or host:port must be specified when proxy is enabled")
+		if err := ValidateProxy(*endpoint.Proxy); err != nil {
+			return fmt.Errorf("endpoint '%s': %w", endpoint.Name, err)
 		}
 	}
 
@@ -670,6 +1010,18 @@ func (c *Config) validate() error {
 				return fmt.Errorf("endpoint %s: api-keys[%d] 必须设置 value", endpoint.Name, j)
 			}
 		}
+		// 验证模型映射两侧的模型名称均不为空
+		for from, to := range endpoint.ModelMapping {
+			if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+				return fmt.Errorf("endpoint %s: model_mapping 的模型名称不能为空", endpoint.Name)
+			}
+		}
+		if err := ValidateRetryPolicy(endpoint.RetryPolicy); err != nil {
+			return fmt.Errorf("endpoint %s: retry_policy: %w", endpoint.Name, err)
+		}
+		if endpoint.MaxConcurrency < 0 || endpoint.RPM < 0 || endpoint.TPM < 0 {
+			return fmt.Errorf("endpoint %s: max_concurrency、rpm、tpm 不能为负数", endpoint.Name)
+		}
 	}
 
 	return nil
@@ -809,6 +1161,11 @@ func (cw *ConfigWatcher) watchLoop() {
 	}
 }
 
+// Reload reloads the configuration from file on demand (e.g. on SIGHUP)
+func (cw *ConfigWatcher) Reload() error {
+	return cw.reloadConfig()
+}
+
 // reloadConfig reloads the configuration from file
 func (cw *ConfigWatcher) reloadConfig() error {
 	newConfig, err := LoadConfig(cw.configPath)
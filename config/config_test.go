@@ -453,4 +453,50 @@ func TestFindEndpointIndex(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	rules, err := ParseRetryPolicy(`
+- match: "404"
+  action: retry
+  max_attempts: 2
+  base_delay: 500ms
+- match: 5xx
+  action: switch
+- match: eof
+  action: retry
+  then: fail
+`)
+	if err != nil {
+		t.Fatalf("解析重试策略失败: %v", err)
+	}
+	if len(rules) != 3 || rules[0].Match != "404" || rules[0].MaxAttempts != 2 || rules[0].BaseDelay != 500*time.Millisecond {
+		t.Fatalf("解析结果不符: %+v", rules)
+	}
+
+	// JSON 格式与格式化往返
+	rules, err = ParseRetryPolicy(`[{"match": "rate_limit", "action": "suspend"}]`)
+	if err != nil || len(rules) != 1 || rules[0].Action != RetryActionSuspend {
+		t.Fatalf("JSON 格式解析失败: %+v, %v", rules, err)
+	}
+	if again, err := ParseRetryPolicy(FormatRetryPolicy(rules)); err != nil || len(again) != 1 || again[0] != rules[0] {
+		t.Fatalf("格式化往返不一致: %+v, %v", again, err)
+	}
+
+	if rules, err := ParseRetryPolicy("  "); err != nil || rules != nil {
+		t.Errorf("空文本应返回 nil: %+v, %v", rules, err)
+	}
+
+	invalid := []string{
+		`[{"match": "600", "action": "retry"}]`,
+		`[{"match": "client_cancel", "action": "retry"}]`,
+		`[{"match": "eof", "action": "wait"}]`,
+		`[{"match": "eof", "action": "retry", "then": "retry"}]`,
+		`[{"match": "eof", "action": "retry", "max_attempts": -1}]`,
+	}
+	for _, text := range invalid {
+		if _, err := ParseRetryPolicy(text); err == nil {
+			t.Errorf("无效策略应返回错误: %s", text)
+		}
+	}
+}
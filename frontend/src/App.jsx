@@ -3,10 +3,11 @@
 // 2025-11-28 (Updated 2025-12-12)
 // ============================================
 
-import { useState, Suspense, lazy, useCallback } from 'react';
+import { useState, Suspense, lazy, useCallback, useEffect } from 'react';
 import Header from '@components/layout/Header.jsx';
 import { LoadingSpinner } from '@components/ui';
 import useSSE from '@hooks/useSSE.js';
+import { fetchStatus } from '@utils/api.js';
 
 // 懒加载页面组件
 const OverviewPage = lazy(() => import('@pages/overview/index.jsx'));
@@ -46,6 +47,11 @@ function App() {
     }
   }, []);
 
+  // 启动时主动获取一次状态，之后由 system:status 事件推送更新
+  useEffect(() => {
+    fetchStatus().then(handleStatusUpdate).catch(() => {});
+  }, [handleStatusUpdate]);
+
   // SSE 连接状态（用于全局状态指示）
   const { connectionStatus } = useSSE(handleStatusUpdate, { events: 'status' });
 
@@ -56,9 +56,14 @@ const useOverviewData = () => {
 
     console.log(`📡 [SSE] 收到${eventType || 'generic'}事件, 变更类型: ${changeType || 'none'}`, sseData);
 
-    // 保存启动时间戳
+    // 保存启动时间戳（Wails system:status 事件只携带 ISO8601 格式的 start_time）
     if (sseData.start_timestamp) {
       setStartTimestamp(sseData.start_timestamp);
+    } else if (sseData.start_time) {
+      const parsed = Math.floor(new Date(sseData.start_time).getTime() / 1000);
+      if (!Number.isNaN(parsed)) {
+        setStartTimestamp(parsed);
+      }
     }
 
     // 检查是否是组切换事件 - 需要重新加载数据
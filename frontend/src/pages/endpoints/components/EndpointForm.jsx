@@ -68,6 +68,23 @@ const PasswordInput = ({ label, name, value, onChange, placeholder, required, he
   );
 };
 
+const FormSelect = ({ label, name, value, onChange, options, help }) => (
+  <div className="space-y-1">
+    <label className="block text-sm font-medium text-slate-700">{label}</label>
+    <select
+      name={name}
+      value={value}
+      onChange={onChange}
+      className="w-full px-3 py-2 border border-slate-200 rounded-lg text-sm bg-white focus:outline-none focus:ring-2 focus:ring-indigo-500/20 focus:border-indigo-500"
+    >
+      {options.map(opt => (
+        <option key={opt.value} value={opt.value}>{opt.label}</option>
+      ))}
+    </select>
+    {help && <p className="text-xs text-slate-400">{help}</p>}
+  </div>
+);
+
 const FormCheckbox = ({ label, name, checked, onChange, help }) => (
   <div className="flex items-start gap-3">
     <input
@@ -84,6 +101,24 @@ const FormCheckbox = ({ label, name, checked, onChange, help }) => (
   </div>
 );
 
+// 模型映射 <-> 文本（每行一条：客户端模型=上游模型）
+const formatModelMapping = (mapping) =>
+  Object.entries(mapping || {}).map(([from, to]) => `${from}=${to}`).join('\n');
+
+const parseModelMapping = (text) => {
+  const mapping = {};
+  for (const line of (text || '').split('\n')) {
+    if (!line.trim()) continue;
+    const index = line.indexOf('=');
+    if (index <= 0) return null;
+    const from = line.slice(0, index).trim();
+    const to = line.slice(index + 1).trim();
+    if (!from || !to) return null;
+    mapping[from] = to;
+  }
+  return mapping;
+};
+
 // ============================================
 // 端点表单组件
 // ============================================
@@ -96,6 +131,23 @@ const EndpointForm = ({
 }) => {
   const isEditMode = !!endpoint;
 
+  // 端点代理配置 -> 表单字段（未配置 = 使用全局代理）
+  const getProxyFormData = (proxy) => {
+    if (!proxy) {
+      return { proxyMode: 'inherit', proxyType: 'socks5', proxyUrl: '', proxyHost: '', proxyPort: '', proxyUsername: '', proxyPassword: '', proxyPasswordSet: false };
+    }
+    return {
+      proxyMode: proxy.enabled ? 'custom' : 'direct',
+      proxyType: proxy.type || 'socks5',
+      proxyUrl: proxy.url || '',
+      proxyHost: proxy.host || '',
+      proxyPort: proxy.port || '',
+      proxyUsername: proxy.username || '',
+      proxyPassword: '', // 后端不返回密码，留空表示保持原密码
+      proxyPasswordSet: !!proxy.password_set
+    };
+  };
+
   // 计算初始表单数据
   const getInitialFormData = () => {
     if (endpoint) {
@@ -106,16 +158,23 @@ const EndpointForm = ({
         token: endpoint.token || '', // v5.0: 本地桌面应用，直接显示已保存的 Token
         apiKey: endpoint.apiKey || '', // v5.0: 本地桌面应用，直接显示已保存的 ApiKey
         priority: endpoint.priority || 1,
+        weight: endpoint.weight || 1,
         failoverEnabled: endpoint.failoverEnabled !== false,
         cooldownSeconds: endpoint.cooldownSeconds || '',
         timeoutSeconds: endpoint.timeoutSeconds || 300,
         supportsCountTokens: endpoint.supportsCountTokens || false,
+        modelMappingText: formatModelMapping(endpoint.modelMapping),
+        retryPolicy: endpoint.retryPolicy || '',
+        maxConcurrency: endpoint.maxConcurrency || 0,
+        rpm: endpoint.rpm || 0,
+        tpm: endpoint.tpm || 0,
         costMultiplier: endpoint.costMultiplier || 1.0,
         inputCostMultiplier: endpoint.inputCostMultiplier || 1.0,
         outputCostMultiplier: endpoint.outputCostMultiplier || 1.0,
         cacheCreationCostMultiplier: endpoint.cacheCreationCostMultiplier || 1.0,
         cacheCreationCostMultiplier1h: endpoint.cacheCreationCostMultiplier1h || 1.0,
-        cacheReadCostMultiplier: endpoint.cacheReadCostMultiplier || 1.0
+        cacheReadCostMultiplier: endpoint.cacheReadCostMultiplier || 1.0,
+        ...getProxyFormData(endpoint.proxy)
       };
     }
     return {
@@ -125,16 +184,23 @@ const EndpointForm = ({
       token: '',
       apiKey: '',
       priority: 1,
+      weight: 1,
       failoverEnabled: true,
       cooldownSeconds: '',
       timeoutSeconds: 300,
       supportsCountTokens: false,
+      modelMappingText: '',
+      retryPolicy: '',
+      maxConcurrency: 0,
+      rpm: 0,
+      tpm: 0,
       costMultiplier: 1.0,
       inputCostMultiplier: 1.0,
       outputCostMultiplier: 1.0,
       cacheCreationCostMultiplier: 1.0,
       cacheCreationCostMultiplier1h: 1.0,
-      cacheReadCostMultiplier: 1.0
+      cacheReadCostMultiplier: 1.0,
+      ...getProxyFormData(null)
     };
   };
 
@@ -144,6 +210,9 @@ const EndpointForm = ({
   // 高级选项展开状态
   const [showAdvanced, setShowAdvanced] = useState(false);
 
+  // 网络代理选项展开状态（已配置端点代理时默认展开）
+  const [showProxy, setShowProxy] = useState(formData.proxyMode !== 'inherit');
+
   // 表单错误
   const [errors, setErrors] = useState({});
 
@@ -178,6 +247,13 @@ const EndpointForm = ({
     if (!isEditMode && !formData.token.trim()) {
       newErrors.token = '请输入 Token';
     }
+    if (formData.proxyMode === 'custom' && !formData.proxyUrl.trim() &&
+        (!formData.proxyHost.trim() || !parseInt(formData.proxyPort))) {
+      newErrors.proxy = '请填写代理 URL 或 主机和端口';
+    }
+    if (parseModelMapping(formData.modelMappingText) === null) {
+      newErrors.modelMapping = '每行格式为 客户端模型=上游模型';
+    }
 
     setErrors(newErrors);
     return Object.keys(newErrors).length === 0;
@@ -192,7 +268,7 @@ const EndpointForm = ({
     }
 
     try {
-      await onSave(formData);
+      await onSave({ ...formData, modelMapping: parseModelMapping(formData.modelMappingText) });
     } catch (error) {
       console.error('保存失败:', error);
       setErrors({ submit: error.message || '保存失败' });
@@ -319,7 +395,7 @@ const EndpointForm = ({
               路由配置
             </h3>
 
-            <div className="grid grid-cols-3 gap-4">
+            <div className="grid grid-cols-4 gap-4">
               <FormInput
                 label="优先级"
                 name="priority"
@@ -330,6 +406,16 @@ const EndpointForm = ({
                 help="数字越小优先级越高"
               />
 
+              <FormInput
+                label="权重"
+                name="weight"
+                value={formData.weight}
+                onChange={handleChange}
+                type="number"
+                placeholder="1"
+                help="weighted 策略下同优先级内按权重分配"
+              />
+
               <FormInput
                 label="超时时间 (秒)"
                 name="timeoutSeconds"
@@ -350,6 +436,38 @@ const EndpointForm = ({
               />
             </div>
 
+            <div className="grid grid-cols-3 gap-4">
+              <FormInput
+                label="最大并发"
+                name="maxConcurrency"
+                value={formData.maxConcurrency}
+                onChange={handleChange}
+                type="number"
+                placeholder="0"
+                help="0 表示不限制"
+              />
+
+              <FormInput
+                label="RPM 上限"
+                name="rpm"
+                value={formData.rpm}
+                onChange={handleChange}
+                type="number"
+                placeholder="0"
+                help="每分钟请求数，0 表示不限制"
+              />
+
+              <FormInput
+                label="TPM 上限"
+                name="tpm"
+                value={formData.tpm}
+                onChange={handleChange}
+                type="number"
+                placeholder="0"
+                help="每分钟 Token 数，0 表示不限制"
+              />
+            </div>
+
             <div className="flex gap-6">
               <FormCheckbox
                 label="参与故障转移"
@@ -367,6 +485,126 @@ const EndpointForm = ({
                 help="端点是否支持 Token 计数 API"
               />
             </div>
+
+            <div className="space-y-1">
+              <label className="block text-sm font-medium text-slate-700">模型映射</label>
+              <textarea
+                name="modelMappingText"
+                value={formData.modelMappingText}
+                onChange={handleChange}
+                rows={3}
+                placeholder="claude-sonnet-4-5=vendor/claude-sonnet"
+                className="w-full px-3 py-2 border border-slate-200 rounded-lg text-sm font-mono focus:outline-none focus:ring-2 focus:ring-indigo-500/20 focus:border-indigo-500"
+              />
+              <p className="text-xs text-slate-400">每行一条：客户端模型=该端点的模型名称，响应中的模型会映射回客户端模型</p>
+              {errors.modelMapping && (
+                <p className="text-xs text-rose-500 mt-1">{errors.modelMapping}</p>
+              )}
+            </div>
+
+            <div className="space-y-1">
+              <label className="block text-sm font-medium text-slate-700">重试策略</label>
+              <textarea
+                name="retryPolicy"
+                value={formData.retryPolicy}
+                onChange={handleChange}
+                rows={3}
+                placeholder={'- match: "404"\n  action: switch'}
+                className="w-full px-3 py-2 border border-slate-200 rounded-lg text-sm font-mono focus:outline-none focus:ring-2 focus:ring-indigo-500/20 focus:border-indigo-500"
+              />
+              <p className="text-xs text-slate-400">YAML/JSON 规则数组，按状态码或错误类型覆盖全局重试策略，留空使用全局策略</p>
+            </div>
+          </div>
+
+          {/* 网络代理（可折叠） */}
+          <div className="mb-6">
+            <button
+              type="button"
+              onClick={() => setShowProxy(!showProxy)}
+              className="flex items-center gap-2 text-sm font-semibold text-slate-500 uppercase tracking-wide hover:text-slate-700 transition-colors"
+            >
+              网络代理
+              {showProxy ? <ChevronUp size={16} /> : <ChevronDown size={16} />}
+            </button>
+
+            {showProxy && (
+              <div className="mt-4 p-4 bg-slate-50 rounded-lg space-y-4">
+                <FormSelect
+                  label="代理模式"
+                  name="proxyMode"
+                  value={formData.proxyMode}
+                  onChange={handleChange}
+                  options={[
+                    { value: 'inherit', label: '使用全局代理配置' },
+                    { value: 'direct', label: '直连（不使用代理）' },
+                    { value: 'custom', label: '独立代理' }
+                  ]}
+                  help="端点级设置会覆盖全局代理，同时作用于转发、健康检查和快速测试"
+                />
+
+                {formData.proxyMode === 'custom' && (
+                  <>
+                    <div className="grid grid-cols-2 gap-4">
+                      <FormSelect
+                        label="代理类型"
+                        name="proxyType"
+                        value={formData.proxyType}
+                        onChange={handleChange}
+                        options={[
+                          { value: 'socks5', label: 'SOCKS5' },
+                          { value: 'http', label: 'HTTP' },
+                          { value: 'https', label: 'HTTPS' }
+                        ]}
+                      />
+
+                      <FormInput
+                        label="代理 URL"
+                        name="proxyUrl"
+                        value={formData.proxyUrl}
+                        onChange={handleChange}
+                        placeholder="socks5://10.0.0.1:1080"
+                        help="填写后忽略主机和端口"
+                      />
+
+                      <FormInput
+                        label="主机"
+                        name="proxyHost"
+                        value={formData.proxyHost}
+                        onChange={handleChange}
+                        placeholder="10.0.0.1"
+                      />
+
+                      <FormInput
+                        label="端口"
+                        name="proxyPort"
+                        value={formData.proxyPort}
+                        onChange={handleChange}
+                        type="number"
+                        placeholder="1080"
+                      />
+
+                      <FormInput
+                        label="用户名 (可选)"
+                        name="proxyUsername"
+                        value={formData.proxyUsername}
+                        onChange={handleChange}
+                      />
+
+                      <PasswordInput
+                        label="密码 (可选)"
+                        name="proxyPassword"
+                        value={formData.proxyPassword}
+                        onChange={handleChange}
+                        placeholder={formData.proxyPasswordSet ? '已设置，留空保持不变' : ''}
+                      />
+                    </div>
+                    {errors.proxy && (
+                      <p className="text-xs text-rose-500">{errors.proxy}</p>
+                    )}
+                  </>
+                )}
+              </div>
+            )}
           </div>
 
           {/* 高级选项（可折叠） */}
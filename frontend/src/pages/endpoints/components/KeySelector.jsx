@@ -36,6 +36,29 @@ const getTypeColorClass = (type) => {
   return colorMap[type] || colorMap['Std'];
 };
 
+/**
+ * Key 轮换状态标签（冷却中/已失效）
+ * @param {Object} key - Key 信息
+ * @returns {Object|null} - { label, className, title }
+ */
+const getKeyStatusBadge = (key) => {
+  if (key.status === 'invalid') {
+    return {
+      label: '已失效',
+      className: 'bg-rose-50 text-rose-600 border-rose-200',
+      title: `上游返回 ${key.last_status_code || ''}，切换到此 Key 可恢复`
+    };
+  }
+  if (key.status === 'cooldown') {
+    return {
+      label: '冷却中',
+      className: 'bg-amber-50 text-amber-600 border-amber-200',
+      title: `冷却至 ${key.cooldown_until || ''}`
+    };
+  }
+  return null;
+};
+
 /**
  * 模拟使用率（基于 index 生成伪随机值）
  * TODO: 后续可从后端 API 获取真实使用率数据
@@ -133,6 +156,7 @@ const TokenSelectionModal = ({ isOpen, onClose, keys, activeKey, onSelect, switc
               const tokenType = inferTokenType(tokenName);
               const tokenTypeColor = getTypeColorClass(tokenType);
               const usagePercent = mockUsagePercentage(key.index);
+              const statusBadge = getKeyStatusBadge(key);
 
               return (
                 <button
@@ -170,9 +194,17 @@ const TokenSelectionModal = ({ isOpen, onClose, keys, activeKey, onSelect, switc
                         </span>
                       </div>
 
-                      {/* Masked Key */}
-                      <div className="text-xs text-slate-400 font-mono truncate">
-                        {key.masked}
+                      {/* Masked Key + 轮换状态 */}
+                      <div className="flex items-center gap-2 text-xs text-slate-400 font-mono truncate">
+                        <span className="truncate">{key.masked}</span>
+                        {statusBadge && (
+                          <span
+                            className={`text-[10px] px-1.5 py-0.5 rounded font-sans font-medium border ${statusBadge.className} flex-shrink-0`}
+                            title={statusBadge.title}
+                          >
+                            {statusBadge.label}
+                          </span>
+                        )}
                       </div>
                     </div>
                   </div>
@@ -0,0 +1,152 @@
+// ============================================
+// 端点首 Token 耗时图组件
+// 2026-10-17
+// ============================================
+
+import { useState, useEffect, useCallback, useRef } from 'react';
+import { RefreshCw, Zap } from 'lucide-react';
+import {
+  BarChart,
+  Bar,
+  XAxis,
+  YAxis,
+  CartesianGrid,
+  Tooltip,
+  ResponsiveContainer
+} from 'recharts';
+import { fetchEndpointTTFTData, formatDuration } from '@utils/api.js';
+
+// 自定义 Tooltip
+const CustomTooltip = ({ active, payload, label }) => {
+  if (!active || !payload || !payload.length) return null;
+
+  return (
+    <div className="bg-white p-3 rounded-lg shadow-lg border border-slate-100 text-sm">
+      <p className="font-medium text-slate-900 mb-2">{label}</p>
+      {payload.map((entry, index) => (
+        <div key={index} className="flex items-center justify-between gap-4">
+          <span className="text-slate-500">{entry.name}:</span>
+          <span className="font-mono font-medium" style={{ color: entry.color }}>
+            {formatDuration(entry.value)}
+          </span>
+        </div>
+      ))}
+      <p className="text-xs text-slate-400 mt-2">样本数: {payload[0].payload.samples}</p>
+    </div>
+  );
+};
+
+const EndpointTTFTChart = () => {
+  const [chartData, setChartData] = useState([]);
+  const [loading, setLoading] = useState(true);
+  const [isRefreshing, setIsRefreshing] = useState(false);
+  const refreshIntervalRef = useRef(null);
+
+  // 加载数据
+  const loadData = useCallback(async (showRefreshing = false) => {
+    if (showRefreshing) {
+      setIsRefreshing(true);
+    }
+    try {
+      const data = await fetchEndpointTTFTData();
+      setChartData(data);
+    } catch (error) {
+      console.error('加载端点首 Token 耗时数据失败:', error);
+    } finally {
+      setLoading(false);
+      setIsRefreshing(false);
+    }
+  }, []);
+
+  // 初始加载
+  useEffect(() => {
+    loadData();
+  }, []); // eslint-disable-line react-hooks/exhaustive-deps
+
+  // 定时刷新（每 30 秒）
+  useEffect(() => {
+    refreshIntervalRef.current = setInterval(() => {
+      loadData(false);
+    }, 30000);
+
+    return () => {
+      if (refreshIntervalRef.current) {
+        clearInterval(refreshIntervalRef.current);
+      }
+    };
+  }, [loadData]);
+
+  return (
+    <div className="bg-white p-6 rounded-2xl border border-slate-200/60 shadow-sm">
+      <div className="flex justify-between items-start mb-1">
+        <div className="flex items-center space-x-2">
+          <div className="p-1.5 bg-amber-50 text-amber-500 rounded-md">
+            <Zap size={16} />
+          </div>
+          <h3 className="font-semibold text-slate-900">端点首 Token 耗时</h3>
+        </div>
+        <button
+          onClick={() => loadData(true)}
+          disabled={isRefreshing}
+          className="p-1.5 text-slate-400 hover:text-slate-600 hover:bg-slate-100 rounded-md transition-colors disabled:opacity-50"
+          title="刷新数据"
+        >
+          <RefreshCw size={14} className={isRefreshing ? 'animate-spin' : ''} />
+        </button>
+      </div>
+      <p className="text-xs text-slate-500 mb-4">最近请求的 TTFT 分位数（p50 / p95）</p>
+
+      <div className="h-[280px] w-full">
+        {loading ? (
+          <div className="h-full flex items-center justify-center text-slate-400">
+            <RefreshCw size={20} className="animate-spin mr-2" />
+            加载中...
+          </div>
+        ) : chartData.length === 0 ? (
+          <div className="h-full flex items-center justify-center text-slate-400 text-sm">
+            暂无数据
+          </div>
+        ) : (
+          <ResponsiveContainer width="100%" height="100%">
+            <BarChart data={chartData} margin={{ top: 10, right: 10, left: 0, bottom: 5 }}>
+              <CartesianGrid strokeDasharray="3 3" vertical={false} stroke="#f1f5f9" />
+              <XAxis
+                dataKey="name"
+                axisLine={false}
+                tickLine={false}
+                tick={{ fill: '#64748b', fontSize: 12 }}
+                interval={0}
+              />
+              <YAxis
+                axisLine={false}
+                tickLine={false}
+                tick={{ fill: '#94a3b8', fontSize: 11 }}
+                tickFormatter={formatDuration}
+                width={55}
+              />
+              <Tooltip content={<CustomTooltip />} cursor={{ fill: '#f8fafc' }} />
+              <Bar dataKey="p50" fill="#fbbf24" barSize={24} radius={[4, 4, 0, 0]} name="p50" />
+              <Bar dataKey="p95" fill="#f97316" barSize={24} radius={[4, 4, 0, 0]} name="p95" />
+            </BarChart>
+          </ResponsiveContainer>
+        )}
+      </div>
+
+      {/* 图例 */}
+      {!loading && chartData.length > 0 && (
+        <div className="flex justify-center space-x-6 mt-4 pt-3 border-t border-slate-100">
+          <div className="flex items-center text-xs text-slate-500">
+            <span className="w-3 h-3 rounded bg-amber-400 mr-2"></span>
+            p50
+          </div>
+          <div className="flex items-center text-xs text-slate-500">
+            <span className="w-3 h-3 rounded bg-orange-500 mr-2"></span>
+            p95
+          </div>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default EndpointTTFTChart;
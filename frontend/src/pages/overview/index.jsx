@@ -13,6 +13,7 @@ import RequestTrendChart from './components/RequestTrendChart.jsx';
 import TokenDistributionChart from './components/TokenDistributionChart.jsx';
 import EndpointHealthChart from './components/EndpointHealthChart.jsx';
 import TokenCostChart from './components/TokenCostChart.jsx';
+import EndpointTTFTChart from './components/EndpointTTFTChart.jsx';
 import ResponseTimeChart from './components/ResponseTimeChart.jsx';
 import ConnectionActivityChart from './components/ConnectionActivityChart.jsx';
 
@@ -62,6 +63,9 @@ const OverviewPage = () => {
         {/* Token 成本 - 全宽 */}
         <TokenCostChart />
 
+        {/* 端点首 Token 耗时 - 全宽 */}
+        <EndpointTTFTChart />
+
         {/* 资源状- Token 分布 & 端点健康 */}
         <div className="grid grid-cols-1 lg:grid-cols-2 gap-6">
           <TokenDistributionChart />
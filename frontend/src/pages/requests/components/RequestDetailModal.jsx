@@ -216,6 +216,8 @@ const RequestDetailModal = ({ isOpen, onClose, request }) => {
                   <InfoRow icon={FileText} label="请求 ID" value={request.requestId} copyable />
                   <InfoRow icon={Calendar} label="时间戳" value={formatTimestamp(request.timestamp)} />
                   <InfoRow icon={Clock} label="持续时间" value={formatDuration(request.duration)} />
+                  <InfoRow icon={Zap} label="首 Token 耗时" value={request.ttft ? formatDuration(request.ttft) : null} />
+                  <InfoRow icon={Activity} label="输出速率" value={request.outputTokensPerSec ? `${request.outputTokensPerSec.toFixed(1)} tok/s` : null} />
                   <InfoRow icon={Server} label="端点" value={request.endpoint} />
                   <InfoRow icon={Layers} label="渠道" value={request.channel || request.group} />
                 </div>
@@ -10,6 +10,7 @@ import {
   RotateCw,
   Pause,
   CheckCircle2,
+  DatabaseZap,
   XCircle,
   Ban,
   Timer
@@ -24,6 +25,7 @@ const ICON_MAP = {
   RotateCw,
   Pause,
   CheckCircle2,
+  DatabaseZap,
   XCircle,
   Ban,
   Timer
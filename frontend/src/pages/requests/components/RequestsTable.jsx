@@ -39,6 +39,10 @@ const renderCell = (columnId, request) => {
       return <span className="text-gray-600 text-xs">{request.endpoint}</span>;
     case 'duration':
       return <span className="text-gray-700 font-mono text-xs">{formatDuration(request.duration)}</span>;
+    case 'ttft':
+      return <span className="text-gray-700 font-mono text-xs">{request.ttft ? formatDuration(request.ttft) : '-'}</span>;
+    case 'outputTokensPerSec':
+      return <span className="text-gray-500 text-right font-mono text-xs">{request.outputTokensPerSec ? `${request.outputTokensPerSec.toFixed(1)} tok/s` : '-'}</span>;
     case 'inputTokens':
       return <span className="text-gray-700 text-right font-mono text-xs">{request.inputTokens}</span>;
     case 'outputTokens':
@@ -12,6 +12,7 @@ export const STATUS_OPTIONS = {
   retry: '重试中',
   suspended: '已挂起',
   completed: '已完成',
+  cache_hit: '缓存命中',
   failed: '已失败',
   cancelled: '已取消'
 };
@@ -47,6 +48,8 @@ export const TABLE_COLUMNS = [
   { id: 'channel', label: '渠道', alwaysVisible: false, width: 'auto' },
   { id: 'endpoint', label: '端点', alwaysVisible: false, width: 'auto' },
   { id: 'duration', label: '耗时', alwaysVisible: false, width: 'auto' },
+  { id: 'ttft', label: '首Token', alwaysVisible: false, width: 'auto' },
+  { id: 'outputTokensPerSec', label: '输出速率', alwaysVisible: false, width: 'auto', align: 'right' },
   { id: 'inputTokens', label: '输入', alwaysVisible: false, width: 'auto', align: 'right' },
   { id: 'outputTokens', label: '输出', alwaysVisible: false, width: 'auto', align: 'right' },
   { id: 'cacheCreationTokens', label: '缓存创建', alwaysVisible: false, width: 'auto', align: 'right' },
@@ -14,13 +14,17 @@ const VALUE_TYPE_CONFIG = {
   float: { type: 'number', step: 0.1 },
   bool: { type: 'checkbox', step: null },
   duration: { type: 'text', step: null, placeholder: '例如: 30s, 5m, 1h' },
+  text: { type: 'textarea', step: null, placeholder: '- match: "404"\n  action: switch' },
   password: { type: 'password', step: null }
 };
 
 // 策略类型选项
 const STRATEGY_OPTIONS = [
   { value: 'priority', label: 'priority (优先级)' },
-  { value: 'fastest', label: 'fastest (最快响应)' }
+  { value: 'fastest', label: 'fastest (最快响应)' },
+  { value: 'weighted', label: 'weighted (按权重)' },
+  { value: 'least_outstanding', label: 'least_outstanding (最少在途请求)' },
+  { value: 'ewma', label: 'ewma (真实请求延迟)' }
 ];
 
 const SettingItem = ({
@@ -134,6 +138,39 @@ const SettingItem = ({
     );
   }
 
+  // 多行文本类型（如 YAML/JSON 格式的重试策略规则）使用多行文本框
+  if (valueType === 'text') {
+    return (
+      <div className="py-3 border-b border-slate-100 last:border-0">
+        <div className="flex items-center gap-2">
+          <span className="text-sm font-medium text-slate-700">{displayLabel}</span>
+          {setting.requires_restart && (
+            <span className="inline-flex items-center px-1.5 py-0.5 rounded text-[10px] font-medium bg-amber-100 text-amber-700">
+              <AlertTriangle size={10} className="mr-0.5" />
+              重启生效
+            </span>
+          )}
+        </div>
+        {setting.description && (
+          <p className="text-xs text-slate-400 mt-0.5">{setting.description}</p>
+        )}
+        <textarea
+          value={localValue}
+          onChange={handleChange}
+          disabled={disabled}
+          rows={6}
+          placeholder={config.placeholder}
+          className={`
+            mt-2 w-full px-3 py-2 bg-slate-50 border border-slate-200 rounded-lg text-sm text-slate-700
+            font-mono
+            focus:outline-none focus:ring-2 focus:ring-indigo-500 focus:border-indigo-500
+            ${disabled ? 'opacity-50 cursor-not-allowed' : ''}
+          `}
+        />
+      </div>
+    );
+  }
+
   // 其他类型使用文本/数字输入框
   return (
     <div className="flex justify-between items-center py-3 border-b border-slate-100 last:border-0">
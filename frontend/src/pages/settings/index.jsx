@@ -18,7 +18,12 @@ import {
   Clock,
   Lock,
   Hash,
-  Archive
+  Archive,
+  Plug,
+  Wallet,
+  KeyRound,
+  DatabaseZap,
+  ShieldAlert
 } from 'lucide-react';
 import { Button, LoadingSpinner, ErrorMessage } from '@components/ui';
 import { SettingItem, SettingsSection, PortInfo } from './components';
@@ -48,6 +53,11 @@ const CATEGORY_ICONS = {
   request: Clock,
   auth: Lock,
   token_counting: Hash,
+  openai_compat: Plug,
+  budget: Wallet,
+  key_rotation: KeyRound,
+  response_cache: DatabaseZap,
+  circuit_breaker: ShieldAlert,
   retention: Archive
 };
 
@@ -283,6 +283,8 @@ export const fetchRequests = async (params = {}) => {
     endpoint: request.endpoint_name || request.endpoint || 'unknown',
     group: request.group_name || request.group || 'default',
     duration: request.duration_ms || request.duration || 0,
+    ttft: request.ttft_ms || request.ttft || 0,
+    outputTokensPerSec: request.output_tokens_per_sec || request.outputTokensPerSec || 0,
     inputTokens: request.input_tokens || request.inputTokens || 0,
     outputTokens: request.output_tokens || request.outputTokens || 0,
     cacheCreationTokens: request.cache_creation_tokens || request.cacheCreationTokens || 0,
@@ -532,6 +534,21 @@ export const fetchEndpointCostsData = async () => {
   }
 };
 
+/**
+ * 获取端点首 Token 耗时分位数（仅桌面端提供）
+ */
+export const fetchEndpointTTFTData = async () => {
+  try {
+    if (isWailsEnvironment()) {
+      return await WailsApi.getEndpointTTFTChart();
+    }
+    return [];
+  } catch (error) {
+    console.error('获取端点首 Token 耗时数据失败:', error);
+    return [];
+  }
+};
+
 /**
  * 获取连接活动数据
  * @param {number} minutes - 时间范围（分钟），默认 60
@@ -40,6 +40,7 @@ export const REQUEST_STATUS = {
   RETRY: 'retry',
   SUSPENDED: 'suspended',
   COMPLETED: 'completed',
+  CACHE_HIT: 'cache_hit',
   FAILED: 'failed',
   ERROR: 'error',
   CANCELLED: 'cancelled',
@@ -78,6 +79,11 @@ export const STATUS_CONFIG = {
     icon: 'CheckCircle2',
     color: 'bg-emerald-100 text-emerald-700 border-emerald-200'
   },
+  [REQUEST_STATUS.CACHE_HIT]: {
+    label: '缓存命中',
+    icon: 'DatabaseZap',
+    color: 'bg-teal-100 text-teal-700 border-teal-200'
+  },
   [REQUEST_STATUS.FAILED]: {
     label: '已失败',
     icon: 'XCircle',
@@ -109,6 +115,7 @@ export const FILTER_STATUS_OPTIONS = [
   { value: 'retry', label: '重试中' },
   { value: 'suspended', label: '已挂起' },
   { value: 'completed', label: '已完成' },
+  { value: 'cache_hit', label: '缓存命中' },
   { value: 'failed', label: '已失败' },
   { value: 'cancelled', label: '已取消' }
 ];
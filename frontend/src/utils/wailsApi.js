@@ -169,6 +169,17 @@ export const getEndpoints = async () => {
     last_check: ep.last_check,
     response_time: ep.response_time_ms,
     consecutive_fail: ep.consecutive_fail,
+    weight: ep.weight,
+    in_flight: ep.in_flight,
+    ewma_latency: ep.ewma_latency_ms,
+    circuit_state: ep.circuit_state || 'closed',
+    circuit_reason: ep.circuit_reason,
+    circuit_opened_at: ep.circuit_opened_at,
+    circuit_half_open_at: ep.circuit_half_open_at,
+    circuit_error_rate: ep.circuit_error_rate,
+    circuit_slow_call_rate: ep.circuit_slow_call_rate,
+    rate_limit: ep.rate_limit || null,
+    capacity: ep.capacity || null,
     never_checked: !ep.last_check
   }));
 
@@ -230,16 +241,26 @@ export const getKeysOverview = async () => {
       index: t.index,
       name: t.name || `Token ${t.index + 1}`,
       masked: t.value,  // 后端返回的是 value 字段（已脱敏）
-      is_active: t.is_active
+      is_active: t.is_active,
+      status: t.status,
+      cooldown_until: t.cooldown_until,
+      usage_count: t.usage_count,
+      last_status_code: t.last_status_code
     })),
     api_keys: (ep.api_keys || []).map(k => ({
       index: k.index,
       name: k.name || `API Key ${k.index + 1}`,
       masked: k.value,  // 后端返回的是 value 字段（已脱敏）
-      is_active: k.is_active
+      is_active: k.is_active,
+      status: k.status,
+      cooldown_until: k.cooldown_until,
+      usage_count: k.usage_count,
+      last_status_code: k.last_status_code
     })),
     current_token_index: ep.current_token_index,
-    current_api_key_index: ep.current_api_key_index
+    current_api_key_index: ep.current_api_key_index,
+    auto_rotation: ep.auto_rotation,
+    strategy: ep.strategy
   }));
 
   const formatted = {
@@ -493,6 +514,8 @@ export const getRequests = async (params = {}) => {
     cache_read_tokens: r.cache_read_tokens,
     duration_ms: r.response_time,
     duration: r.response_time,
+    ttft_ms: r.ttft_ms || 0,
+    output_tokens_per_sec: r.output_tokens_per_sec || 0,
     is_streaming: r.is_streaming,
     total_cost_usd: r.cost,
     cost: r.cost,
@@ -636,6 +659,27 @@ export const getEndpointCosts = async () => {
   return data || [];
 };
 
+// ============================================
+// 端点首 Token 耗时图表 API
+// ============================================
+
+/**
+ * 获取各端点最近请求的首 Token 耗时分位数
+ * @returns {Promise<Array>} - [{name, p50, p95, samples}]（毫秒）
+ */
+export const getEndpointTTFTChart = async () => {
+  await initWails();
+  if (!WailsApp) throw new Error('Wails not available');
+
+  const data = await WailsApp.GetEndpointTTFTChart();
+  return (data || []).map(item => ({
+    name: item.name,
+    p50: item.p50_ms || 0,
+    p95: item.p95_ms || 0,
+    samples: item.samples || 0
+  }));
+};
+
 // ============================================
 // v5.0+ 端点存储管理 API (SQLite)
 // ============================================
@@ -677,10 +721,16 @@ export const getEndpointRecords = async () => {
     apiKeyMasked: r.api_key_masked,
     headers: r.headers || {},
     priority: r.priority,
+    weight: r.weight,
     failoverEnabled: r.failover_enabled,
     cooldownSeconds: r.cooldown_seconds,
     timeoutSeconds: r.timeout_seconds,
     supportsCountTokens: r.supports_count_tokens,
+    modelMapping: r.model_mapping || {},
+    retryPolicy: r.retry_policy || '',
+    maxConcurrency: r.max_concurrency || 0,
+    rpm: r.rpm || 0,
+    tpm: r.tpm || 0,
     costMultiplier: r.cost_multiplier,
     inputCostMultiplier: r.input_cost_multiplier,
     outputCostMultiplier: r.output_cost_multiplier,
@@ -688,6 +738,7 @@ export const getEndpointRecords = async () => {
     cacheCreationCostMultiplier1h: r.cache_creation_cost_multiplier_1h,
     cacheReadCostMultiplier: r.cache_read_cost_multiplier,
     enabled: r.enabled,
+    proxy: r.proxy || null,
     createdAt: r.created_at,
     updatedAt: r.updated_at,
     healthy: r.healthy,
@@ -722,12 +773,19 @@ export const getEndpointRecord = async (name) => {
     apiKeyMasked: r.api_key_masked,
     headers: r.headers || {},
     priority: r.priority,
+    weight: r.weight,
     failoverEnabled: r.failover_enabled,
     cooldownSeconds: r.cooldown_seconds,
     timeoutSeconds: r.timeout_seconds,
     supportsCountTokens: r.supports_count_tokens,
+    modelMapping: r.model_mapping || {},
+    retryPolicy: r.retry_policy || '',
+    maxConcurrency: r.max_concurrency || 0,
+    rpm: r.rpm || 0,
+    tpm: r.tpm || 0,
     costMultiplier: r.cost_multiplier,
     enabled: r.enabled,
+    proxy: r.proxy || null,
     createdAt: r.created_at,
     updatedAt: r.updated_at,
     healthy: r.healthy,
@@ -735,6 +793,31 @@ export const getEndpointRecord = async (name) => {
   };
 };
 
+/**
+ * 将表单中的代理设置转换为后端格式
+ * proxyMode: inherit = 使用全局代理 (null), direct = 直连, custom = 端点独立代理
+ * @param {Object} input - 表单数据
+ * @returns {Object|null} - 端点代理配置
+ */
+const buildEndpointProxy = (input) => {
+  switch (input.proxyMode) {
+    case 'direct':
+      return { enabled: false, type: '' };
+    case 'custom':
+      return {
+        enabled: true,
+        type: input.proxyType || 'http',
+        url: input.proxyUrl || '',
+        host: input.proxyHost || '',
+        port: parseInt(input.proxyPort) || 0,
+        username: input.proxyUsername || '',
+        password: input.proxyPassword || ''
+      };
+    default:
+      return null;
+  }
+};
+
 /**
  * 创建新端点
  * @param {Object} input - 端点配置
@@ -753,16 +836,23 @@ export const createEndpointRecord = async (input) => {
     api_key: input.apiKey || '',
     headers: input.headers || {},
     priority: parseInt(input.priority) || 1,
+    weight: parseInt(input.weight) || 1,
     failover_enabled: input.failoverEnabled !== false,
     cooldown_seconds: input.cooldownSeconds ? parseInt(input.cooldownSeconds) : null,
     timeout_seconds: parseInt(input.timeoutSeconds) || 300,
     supports_count_tokens: input.supportsCountTokens || false,
+    model_mapping: input.modelMapping || {},
+    retry_policy: input.retryPolicy || '',
+    max_concurrency: parseInt(input.maxConcurrency) || 0,
+    rpm: parseInt(input.rpm) || 0,
+    tpm: parseInt(input.tpm) || 0,
     cost_multiplier: parseFloat(input.costMultiplier) || 1.0,
     input_cost_multiplier: parseFloat(input.inputCostMultiplier) || 1.0,
     output_cost_multiplier: parseFloat(input.outputCostMultiplier) || 1.0,
     cache_creation_cost_multiplier: parseFloat(input.cacheCreationCostMultiplier) || 1.0,
     cache_creation_cost_multiplier_1h: parseFloat(input.cacheCreationCostMultiplier1h) || 1.0,
-    cache_read_cost_multiplier: parseFloat(input.cacheReadCostMultiplier) || 1.0
+    cache_read_cost_multiplier: parseFloat(input.cacheReadCostMultiplier) || 1.0,
+    proxy: buildEndpointProxy(input)
   };
 
   await WailsApp.CreateEndpointRecord(record);
@@ -788,16 +878,23 @@ export const updateEndpointRecord = async (name, input) => {
     api_key: input.apiKey || '',
     headers: input.headers || {},
     priority: parseInt(input.priority) || 1,
+    weight: parseInt(input.weight) || 1,
     failover_enabled: input.failoverEnabled !== false,
     cooldown_seconds: input.cooldownSeconds ? parseInt(input.cooldownSeconds) : null,
     timeout_seconds: parseInt(input.timeoutSeconds) || 300,
     supports_count_tokens: input.supportsCountTokens || false,
+    model_mapping: input.modelMapping || {},
+    retry_policy: input.retryPolicy || '',
+    max_concurrency: parseInt(input.maxConcurrency) || 0,
+    rpm: parseInt(input.rpm) || 0,
+    tpm: parseInt(input.tpm) || 0,
     cost_multiplier: parseFloat(input.costMultiplier) || 1.0,
     input_cost_multiplier: parseFloat(input.inputCostMultiplier) || 1.0,
     output_cost_multiplier: parseFloat(input.outputCostMultiplier) || 1.0,
     cache_creation_cost_multiplier: parseFloat(input.cacheCreationCostMultiplier) || 1.0,
     cache_creation_cost_multiplier_1h: parseFloat(input.cacheCreationCostMultiplier1h) || 1.0,
-    cache_read_cost_multiplier: parseFloat(input.cacheReadCostMultiplier) || 1.0
+    cache_read_cost_multiplier: parseFloat(input.cacheReadCostMultiplier) || 1.0,
+    proxy: buildEndpointProxy(input)
   };
 
   await WailsApp.UpdateEndpointRecord(name, record);
@@ -5,28 +5,70 @@ import {logging} from '../models';
 
 export function ActivateGroup(arg1:string):Promise<void>;
 
+export function AddModelPricingVersion(arg1:main.ModelPricingVersionInput):Promise<main.ModelPricingVersionInfo>;
+
+export function ApplyModelPricingImport(arg1:main.ModelPricingImportInput):Promise<main.ModelPricingImportResult>;
+
 export function BatchHealthCheckAll():Promise<main.BatchHealthCheckResult>;
 
 export function BatchUpdateSettings(arg1:main.BatchUpdateSettingsInput):Promise<void>;
 
+export function CancelCostRecompute():Promise<void>;
+
 export function CheckPortAvailable(arg1:number):Promise<boolean>;
 
+export function CreateBudget(arg1:main.BudgetInput):Promise<void>;
+
+export function CreateClientKey(arg1:main.ClientKeyInput):Promise<main.ClientKeyInfo>;
+
 export function CreateEndpointRecord(arg1:main.CreateEndpointInput):Promise<void>;
 
 export function CreateModelPricing(arg1:main.CreateModelPricingInput):Promise<void>;
 
+export function CreateRoutingRule(arg1:main.RoutingRuleInput):Promise<void>;
+
+export function CreateWebhook(arg1:main.WebhookInput):Promise<void>;
+
+export function DeleteBudget(arg1:string):Promise<void>;
+
+export function DeleteCapture(arg1:string):Promise<void>;
+
+export function DeleteClientKey(arg1:string):Promise<void>;
+
 export function DeleteEndpointRecord(arg1:string):Promise<void>;
 
 export function DeleteModelPricing(arg1:string):Promise<void>;
 
+export function DeleteModelPricingVersion(arg1:number):Promise<void>;
+
+export function DeleteResponseCacheEntry(arg1:string):Promise<void>;
+
+export function DeleteRoutingRule(arg1:string):Promise<void>;
+
+export function DeleteWebhook(arg1:string):Promise<void>;
+
 export function GetAllSettings():Promise<Array<main.SettingInfo>>;
 
+export function GetBudget(arg1:string):Promise<main.BudgetInfo>;
+
+export function GetBudgets():Promise<Array<main.BudgetInfo>>;
+
+export function GetCapture(arg1:string):Promise<main.CaptureDetailInfo>;
+
+export function GetCaptures():Promise<Array<main.CaptureSummaryInfo>>;
+
 export function GetChannels():Promise<Array<main.ChannelInfo>>;
 
+export function GetClientKey(arg1:string):Promise<main.ClientKeyInfo>;
+
+export function GetClientKeys():Promise<Array<main.ClientKeyInfo>>;
+
 export function GetConfig():Promise<main.ConfigInfo>;
 
 export function GetConnectionActivityChart(arg1:number):Promise<Array<main.ChartDataPoint>>;
 
+export function GetCostRecomputeStatus():Promise<main.CostRecomputeStatus>;
+
 export function GetEndpointCosts():Promise<Array<main.EndpointCostItem>>;
 
 export function GetEndpointHealthChart():Promise<main.EndpointHealthData>;
@@ -37,6 +79,8 @@ export function GetEndpointRecords():Promise<Array<main.EndpointRecordInfo>>;
 
 export function GetEndpointStorageStatus():Promise<main.EndpointStorageStatus>;
 
+export function GetEndpointTTFTChart():Promise<Array<main.EndpointTTFTItem>>;
+
 export function GetEndpoints():Promise<Array<main.EndpointInfo>>;
 
 export function GetEndpointsByChannel(arg1:string):Promise<Array<main.EndpointRecordInfo>>;
@@ -49,8 +93,12 @@ export function GetLogStreamStatus():Promise<boolean>;
 
 export function GetModelPricing(arg1:string):Promise<main.ModelPricingInfo>;
 
+export function GetModelPricingAudits(arg1:number):Promise<Array<main.ModelPricingAuditInfo>>;
+
 export function GetModelPricingStorageStatus():Promise<main.ModelPricingStorageStatus>;
 
+export function GetModelPricingVersions(arg1:string):Promise<Array<main.ModelPricingVersionInfo>>;
+
 export function GetModelPricings():Promise<Array<main.ModelPricingInfo>>;
 
 export function GetPortInfo():Promise<main.PortInfo>;
@@ -63,8 +111,18 @@ export function GetRequestTrendChart(arg1:number):Promise<Array<main.ChartDataPo
 
 export function GetRequests(arg1:main.RequestQueryParams):Promise<main.RequestListResult>;
 
+export function GetResponseCacheEntries():Promise<Array<main.ResponseCacheEntryInfo>>;
+
+export function GetResponseCacheEntry(arg1:string):Promise<main.ResponseCacheEntryInfo>;
+
+export function GetResponseCacheStats():Promise<main.ResponseCacheStatsInfo>;
+
 export function GetResponseTimeChart(arg1:number):Promise<Array<main.ChartDataPoint>>;
 
+export function GetRoutingRule(arg1:string):Promise<main.RoutingRuleInfo>;
+
+export function GetRoutingRules():Promise<Array<main.RoutingRuleInfo>>;
+
 export function GetSetting(arg1:string,arg2:string):Promise<main.SettingInfo>;
 
 export function GetSettingCategories():Promise<Array<main.CategoryInfo>>;
@@ -81,10 +139,22 @@ export function GetUsageStats(arg1:main.UsageStatsQueryParams):Promise<main.Usag
 
 export function GetUsageSummary(arg1:string,arg2:string):Promise<main.UsageSummary>;
 
+export function GetWebhook(arg1:string):Promise<main.WebhookInfo>;
+
+export function GetWebhookDeliveries(arg1:string,arg2:number):Promise<Array<main.WebhookDeliveryInfo>>;
+
+export function GetWebhookEventTypes():Promise<Array<main.WebhookEventTypeInfo>>;
+
+export function GetWebhooks():Promise<Array<main.WebhookInfo>>;
+
 export function IsProxyRunning():Promise<boolean>;
 
 export function PauseGroup(arg1:string):Promise<void>;
 
+export function PreviewModelPricingImport(arg1:main.ModelPricingImportInput):Promise<main.ModelPricingImportResult>;
+
+export function PurgeResponseCache(arg1:boolean):Promise<main.ResponseCachePurgeResult>;
+
 export function ResetCategorySettings(arg1:string):Promise<void>;
 
 export function ResumeGroup(arg1:string):Promise<void>;
@@ -93,20 +163,36 @@ export function SetDefaultModelPricing(arg1:string):Promise<void>;
 
 export function SetEndpointPriority(arg1:string,arg2:number):Promise<void>;
 
+export function StartCostRecompute(arg1:main.CostRecomputeInput):Promise<main.CostRecomputeStatus>;
+
 export function StartLogStream():Promise<void>;
 
 export function StopLogStream():Promise<void>;
 
 export function SwitchKey(arg1:string,arg2:string,arg3:number):Promise<main.SwitchKeyResult>;
 
+export function TestRoutingRuleMatch(arg1:string):Promise<main.RoutingRuleMatchResult>;
+
+export function TestWebhook(arg1:string):Promise<main.WebhookDeliveryInfo>;
+
+export function ToggleClientKey(arg1:string,arg2:boolean):Promise<void>;
+
 export function ToggleEndpointRecord(arg1:string,arg2:boolean):Promise<void>;
 
 export function TriggerHealthCheck(arg1:string):Promise<void>;
 
+export function UpdateBudget(arg1:string,arg2:main.BudgetInput):Promise<void>;
+
+export function UpdateClientKey(arg1:string,arg2:main.ClientKeyInput):Promise<void>;
+
 export function UpdateEndpointRecord(arg1:string,arg2:main.CreateEndpointInput):Promise<void>;
 
 export function UpdateModelPricing(arg1:string,arg2:main.CreateModelPricingInput):Promise<void>;
 
 export function UpdatePreferredPort(arg1:number):Promise<void>;
 
+export function UpdateRoutingRule(arg1:string,arg2:main.RoutingRuleInput):Promise<void>;
+
 export function UpdateSetting(arg1:main.UpdateSettingInput):Promise<void>;
+
+export function UpdateWebhook(arg1:string,arg2:main.WebhookInput):Promise<void>;
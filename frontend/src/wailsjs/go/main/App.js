@@ -6,6 +6,14 @@ export function ActivateGroup(arg1) {
   return window['go']['main']['App']['ActivateGroup'](arg1);
 }
 
+export function AddModelPricingVersion(arg1) {
+  return window['go']['main']['App']['AddModelPricingVersion'](arg1);
+}
+
+export function ApplyModelPricingImport(arg1) {
+  return window['go']['main']['App']['ApplyModelPricingImport'](arg1);
+}
+
 export function BatchHealthCheckAll() {
   return window['go']['main']['App']['BatchHealthCheckAll']();
 }
@@ -14,10 +22,22 @@ export function BatchUpdateSettings(arg1) {
   return window['go']['main']['App']['BatchUpdateSettings'](arg1);
 }
 
+export function CancelCostRecompute() {
+  return window['go']['main']['App']['CancelCostRecompute']();
+}
+
 export function CheckPortAvailable(arg1) {
   return window['go']['main']['App']['CheckPortAvailable'](arg1);
 }
 
+export function CreateBudget(arg1) {
+  return window['go']['main']['App']['CreateBudget'](arg1);
+}
+
+export function CreateClientKey(arg1) {
+  return window['go']['main']['App']['CreateClientKey'](arg1);
+}
+
 export function CreateEndpointRecord(arg1) {
   return window['go']['main']['App']['CreateEndpointRecord'](arg1);
 }
@@ -26,6 +46,26 @@ export function CreateModelPricing(arg1) {
   return window['go']['main']['App']['CreateModelPricing'](arg1);
 }
 
+export function CreateRoutingRule(arg1) {
+  return window['go']['main']['App']['CreateRoutingRule'](arg1);
+}
+
+export function CreateWebhook(arg1) {
+  return window['go']['main']['App']['CreateWebhook'](arg1);
+}
+
+export function DeleteBudget(arg1) {
+  return window['go']['main']['App']['DeleteBudget'](arg1);
+}
+
+export function DeleteCapture(arg1) {
+  return window['go']['main']['App']['DeleteCapture'](arg1);
+}
+
+export function DeleteClientKey(arg1) {
+  return window['go']['main']['App']['DeleteClientKey'](arg1);
+}
+
 export function DeleteEndpointRecord(arg1) {
   return window['go']['main']['App']['DeleteEndpointRecord'](arg1);
 }
@@ -34,14 +74,54 @@ export function DeleteModelPricing(arg1) {
   return window['go']['main']['App']['DeleteModelPricing'](arg1);
 }
 
+export function DeleteModelPricingVersion(arg1) {
+  return window['go']['main']['App']['DeleteModelPricingVersion'](arg1);
+}
+
+export function DeleteResponseCacheEntry(arg1) {
+  return window['go']['main']['App']['DeleteResponseCacheEntry'](arg1);
+}
+
+export function DeleteRoutingRule(arg1) {
+  return window['go']['main']['App']['DeleteRoutingRule'](arg1);
+}
+
+export function DeleteWebhook(arg1) {
+  return window['go']['main']['App']['DeleteWebhook'](arg1);
+}
+
 export function GetAllSettings() {
   return window['go']['main']['App']['GetAllSettings']();
 }
 
+export function GetBudget(arg1) {
+  return window['go']['main']['App']['GetBudget'](arg1);
+}
+
+export function GetBudgets() {
+  return window['go']['main']['App']['GetBudgets']();
+}
+
+export function GetCapture(arg1) {
+  return window['go']['main']['App']['GetCapture'](arg1);
+}
+
+export function GetCaptures() {
+  return window['go']['main']['App']['GetCaptures']();
+}
+
 export function GetChannels() {
   return window['go']['main']['App']['GetChannels']();
 }
 
+export function GetClientKey(arg1) {
+  return window['go']['main']['App']['GetClientKey'](arg1);
+}
+
+export function GetClientKeys() {
+  return window['go']['main']['App']['GetClientKeys']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -50,6 +130,10 @@ export function GetConnectionActivityChart(arg1) {
   return window['go']['main']['App']['GetConnectionActivityChart'](arg1);
 }
 
+export function GetCostRecomputeStatus() {
+  return window['go']['main']['App']['GetCostRecomputeStatus']();
+}
+
 export function GetEndpointCosts() {
   return window['go']['main']['App']['GetEndpointCosts']();
 }
@@ -70,6 +154,10 @@ export function GetEndpointStorageStatus() {
   return window['go']['main']['App']['GetEndpointStorageStatus']();
 }
 
+export function GetEndpointTTFTChart() {
+  return window['go']['main']['App']['GetEndpointTTFTChart']();
+}
+
 export function GetEndpoints() {
   return window['go']['main']['App']['GetEndpoints']();
 }
@@ -94,10 +182,18 @@ export function GetModelPricing(arg1) {
   return window['go']['main']['App']['GetModelPricing'](arg1);
 }
 
+export function GetModelPricingAudits(arg1) {
+  return window['go']['main']['App']['GetModelPricingAudits'](arg1);
+}
+
 export function GetModelPricingStorageStatus() {
   return window['go']['main']['App']['GetModelPricingStorageStatus']();
 }
 
+export function GetModelPricingVersions(arg1) {
+  return window['go']['main']['App']['GetModelPricingVersions'](arg1);
+}
+
 export function GetModelPricings() {
   return window['go']['main']['App']['GetModelPricings']();
 }
@@ -122,10 +218,30 @@ export function GetRequests(arg1) {
   return window['go']['main']['App']['GetRequests'](arg1);
 }
 
+export function GetResponseCacheEntries() {
+  return window['go']['main']['App']['GetResponseCacheEntries']();
+}
+
+export function GetResponseCacheEntry(arg1) {
+  return window['go']['main']['App']['GetResponseCacheEntry'](arg1);
+}
+
+export function GetResponseCacheStats() {
+  return window['go']['main']['App']['GetResponseCacheStats']();
+}
+
 export function GetResponseTimeChart(arg1) {
   return window['go']['main']['App']['GetResponseTimeChart'](arg1);
 }
 
+export function GetRoutingRule(arg1) {
+  return window['go']['main']['App']['GetRoutingRule'](arg1);
+}
+
+export function GetRoutingRules() {
+  return window['go']['main']['App']['GetRoutingRules']();
+}
+
 export function GetSetting(arg1, arg2) {
   return window['go']['main']['App']['GetSetting'](arg1, arg2);
 }
@@ -158,6 +274,22 @@ export function GetUsageSummary(arg1, arg2) {
   return window['go']['main']['App']['GetUsageSummary'](arg1, arg2);
 }
 
+export function GetWebhook(arg1) {
+  return window['go']['main']['App']['GetWebhook'](arg1);
+}
+
+export function GetWebhookDeliveries(arg1, arg2) {
+  return window['go']['main']['App']['GetWebhookDeliveries'](arg1, arg2);
+}
+
+export function GetWebhookEventTypes() {
+  return window['go']['main']['App']['GetWebhookEventTypes']();
+}
+
+export function GetWebhooks() {
+  return window['go']['main']['App']['GetWebhooks']();
+}
+
 export function IsProxyRunning() {
   return window['go']['main']['App']['IsProxyRunning']();
 }
@@ -166,6 +298,14 @@ export function PauseGroup(arg1) {
   return window['go']['main']['App']['PauseGroup'](arg1);
 }
 
+export function PreviewModelPricingImport(arg1) {
+  return window['go']['main']['App']['PreviewModelPricingImport'](arg1);
+}
+
+export function PurgeResponseCache(arg1) {
+  return window['go']['main']['App']['PurgeResponseCache'](arg1);
+}
+
 export function ResetCategorySettings(arg1) {
   return window['go']['main']['App']['ResetCategorySettings'](arg1);
 }
@@ -182,6 +322,10 @@ export function SetEndpointPriority(arg1, arg2) {
   return window['go']['main']['App']['SetEndpointPriority'](arg1, arg2);
 }
 
+export function StartCostRecompute(arg1) {
+  return window['go']['main']['App']['StartCostRecompute'](arg1);
+}
+
 export function StartLogStream() {
   return window['go']['main']['App']['StartLogStream']();
 }
@@ -194,6 +338,18 @@ export function SwitchKey(arg1, arg2, arg3) {
   return window['go']['main']['App']['SwitchKey'](arg1, arg2, arg3);
 }
 
+export function TestRoutingRuleMatch(arg1) {
+  return window['go']['main']['App']['TestRoutingRuleMatch'](arg1);
+}
+
+export function TestWebhook(arg1) {
+  return window['go']['main']['App']['TestWebhook'](arg1);
+}
+
+export function ToggleClientKey(arg1, arg2) {
+  return window['go']['main']['App']['ToggleClientKey'](arg1, arg2);
+}
+
 export function ToggleEndpointRecord(arg1, arg2) {
   return window['go']['main']['App']['ToggleEndpointRecord'](arg1, arg2);
 }
@@ -202,6 +358,14 @@ export function TriggerHealthCheck(arg1) {
   return window['go']['main']['App']['TriggerHealthCheck'](arg1);
 }
 
+export function UpdateBudget(arg1, arg2) {
+  return window['go']['main']['App']['UpdateBudget'](arg1, arg2);
+}
+
+export function UpdateClientKey(arg1, arg2) {
+  return window['go']['main']['App']['UpdateClientKey'](arg1, arg2);
+}
+
 export function UpdateEndpointRecord(arg1, arg2) {
   return window['go']['main']['App']['UpdateEndpointRecord'](arg1, arg2);
 }
@@ -214,6 +378,15 @@ export function UpdatePreferredPort(arg1) {
   return window['go']['main']['App']['UpdatePreferredPort'](arg1);
 }
 
+export function UpdateRoutingRule(arg1, arg2) {
+  return window['go']['main']['App']['UpdateRoutingRule'](arg1, arg2);
+}
+
 export function UpdateSetting(arg1) {
   return window['go']['main']['App']['UpdateSetting'](arg1);
 }
+
+export function UpdateWebhook(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWebhook'](arg1, arg2);
+}
+
@@ -89,6 +89,184 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class BudgetInfo {
+	    id: number;
+	    name: string;
+	    scope: string;
+	    target: string;
+	    period: string;
+	    description: string;
+	    soft_limit_usd: number;
+	    soft_limit_tokens: number;
+	    hard_limit_usd: number;
+	    hard_limit_tokens: number;
+	    enabled: boolean;
+	    created_at: string;
+	    updated_at: string;
+	    state: string;
+	    spent_usd: number;
+	    spent_tokens: number;
+	    request_count: number;
+	    period_start: string;
+	    period_end: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BudgetInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.scope = source["scope"];
+	        this.target = source["target"];
+	        this.period = source["period"];
+	        this.description = source["description"];
+	        this.soft_limit_usd = source["soft_limit_usd"];
+	        this.soft_limit_tokens = source["soft_limit_tokens"];
+	        this.hard_limit_usd = source["hard_limit_usd"];
+	        this.hard_limit_tokens = source["hard_limit_tokens"];
+	        this.enabled = source["enabled"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	        this.state = source["state"];
+	        this.spent_usd = source["spent_usd"];
+	        this.spent_tokens = source["spent_tokens"];
+	        this.request_count = source["request_count"];
+	        this.period_start = source["period_start"];
+	        this.period_end = source["period_end"];
+	    }
+	}
+	export class BudgetInput {
+	    name: string;
+	    scope: string;
+	    target: string;
+	    period: string;
+	    description: string;
+	    soft_limit_usd: number;
+	    soft_limit_tokens: number;
+	    hard_limit_usd: number;
+	    hard_limit_tokens: number;
+	    enabled?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BudgetInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.scope = source["scope"];
+	        this.target = source["target"];
+	        this.period = source["period"];
+	        this.description = source["description"];
+	        this.soft_limit_usd = source["soft_limit_usd"];
+	        this.soft_limit_tokens = source["soft_limit_tokens"];
+	        this.hard_limit_usd = source["hard_limit_usd"];
+	        this.hard_limit_tokens = source["hard_limit_tokens"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class CaptureAttemptInfo {
+	    endpoint: string;
+	    started_at: string;
+	    duration_ms: number;
+	    method: string;
+	    url: string;
+	    request_headers: Record<string, Array<string>>;
+	    request_body: string;
+	    status_code: number;
+	    response_headers: Record<string, Array<string>>;
+	    response_body: string;
+	    chunk_count: number;
+	    complete: boolean;
+	    truncated: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureAttemptInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.started_at = source["started_at"];
+	        this.duration_ms = source["duration_ms"];
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.request_headers = source["request_headers"];
+	        this.request_body = source["request_body"];
+	        this.status_code = source["status_code"];
+	        this.response_headers = source["response_headers"];
+	        this.response_body = source["response_body"];
+	        this.chunk_count = source["chunk_count"];
+	        this.complete = source["complete"];
+	        this.truncated = source["truncated"];
+	        this.error = source["error"];
+	    }
+	}
+	export class CaptureDetailInfo {
+	    request_id: string;
+	    created_at: string;
+	    attempts: CaptureAttemptInfo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureDetailInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request_id = source["request_id"];
+	        this.created_at = source["created_at"];
+	        this.attempts = this.convertValues(source["attempts"], CaptureAttemptInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CaptureSummaryInfo {
+	    request_id: string;
+	    created_at: string;
+	    attempts: number;
+	    endpoint: string;
+	    path: string;
+	    status_code: number;
+	    stream: boolean;
+	    error: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureSummaryInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request_id = source["request_id"];
+	        this.created_at = source["created_at"];
+	        this.attempts = source["attempts"];
+	        this.endpoint = source["endpoint"];
+	        this.path = source["path"];
+	        this.status_code = source["status_code"];
+	        this.stream = source["stream"];
+	        this.error = source["error"];
+	        this.size = source["size"];
+	    }
+	}
 	export class CategoryInfo {
 	    name: string;
 	    label: string;
@@ -151,6 +329,54 @@ export namespace main {
 	        this.timestamp = source["timestamp"];
 	    }
 	}
+	export class ClientKeyInfo {
+	    id: number;
+	    name: string;
+	    key: string;
+	    description: string;
+	    enabled: boolean;
+	    expires_at: string;
+	    expired: boolean;
+	    created_at: string;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientKeyInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.key = source["key"];
+	        this.description = source["description"];
+	        this.enabled = source["enabled"];
+	        this.expires_at = source["expires_at"];
+	        this.expired = source["expired"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	    }
+	}
+	export class ClientKeyInput {
+	    name: string;
+	    key: string;
+	    description: string;
+	    enabled?: boolean;
+	    expires_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientKeyInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.key = source["key"];
+	        this.description = source["description"];
+	        this.enabled = source["enabled"];
+	        this.expires_at = source["expires_at"];
+	    }
+	}
 	export class ConfigInfo {
 	    server_host: string;
 	    server_port: number;
@@ -175,6 +401,64 @@ export namespace main {
 	        this.endpoint_count = source["endpoint_count"];
 	    }
 	}
+	export class CostRecomputeInput {
+	    start_time: string;
+	    end_time: string;
+	    model_name: string;
+	    endpoint_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CostRecomputeInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start_time = source["start_time"];
+	        this.end_time = source["end_time"];
+	        this.model_name = source["model_name"];
+	        this.endpoint_name = source["endpoint_name"];
+	    }
+	}
+	export class CostRecomputeStatus {
+	    id: number;
+	    status: string;
+	    start_time: string;
+	    end_time: string;
+	    model_name: string;
+	    endpoint_name: string;
+	    total: number;
+	    processed: number;
+	    updated: number;
+	    percent: number;
+	    cost_before: number;
+	    cost_after: number;
+	    error: string;
+	    started_at: string;
+	    finished_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CostRecomputeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.status = source["status"];
+	        this.start_time = source["start_time"];
+	        this.end_time = source["end_time"];
+	        this.model_name = source["model_name"];
+	        this.endpoint_name = source["endpoint_name"];
+	        this.total = source["total"];
+	        this.processed = source["processed"];
+	        this.updated = source["updated"];
+	        this.percent = source["percent"];
+	        this.cost_before = source["cost_before"];
+	        this.cost_after = source["cost_after"];
+	        this.error = source["error"];
+	        this.started_at = source["started_at"];
+	        this.finished_at = source["finished_at"];
+	    }
+	}
 	export class CreateEndpointInput {
 	    channel: string;
 	    name: string;
@@ -183,15 +467,22 @@ export namespace main {
 	    api_key: string;
 	    headers: Record<string, string>;
 	    priority: number;
+	    weight: number;
 	    failover_enabled: boolean;
 	    cooldown_seconds?: number;
 	    timeout_seconds: number;
 	    supports_count_tokens: boolean;
+	    model_mapping: Record<string, string>;
+	    retry_policy: string;
+	    max_concurrency: number;
+	    rpm: number;
+	    tpm: number;
 	    cost_multiplier: number;
 	    input_cost_multiplier: number;
 	    output_cost_multiplier: number;
 	    cache_creation_cost_multiplier: number;
 	    cache_read_cost_multiplier: number;
+	    proxy?: store.EndpointProxy;
 	
 	    static createFrom(source: any = {}) {
 	        return new CreateEndpointInput(source);
@@ -206,16 +497,41 @@ export namespace main {
 	        this.api_key = source["api_key"];
 	        this.headers = source["headers"];
 	        this.priority = source["priority"];
+	        this.weight = source["weight"];
 	        this.failover_enabled = source["failover_enabled"];
 	        this.cooldown_seconds = source["cooldown_seconds"];
 	        this.timeout_seconds = source["timeout_seconds"];
 	        this.supports_count_tokens = source["supports_count_tokens"];
+	        this.model_mapping = source["model_mapping"];
+	        this.retry_policy = source["retry_policy"];
+	        this.max_concurrency = source["max_concurrency"];
+	        this.rpm = source["rpm"];
+	        this.tpm = source["tpm"];
 	        this.cost_multiplier = source["cost_multiplier"];
 	        this.input_cost_multiplier = source["input_cost_multiplier"];
 	        this.output_cost_multiplier = source["output_cost_multiplier"];
 	        this.cache_creation_cost_multiplier = source["cache_creation_cost_multiplier"];
 	        this.cache_read_cost_multiplier = source["cache_read_cost_multiplier"];
+	        this.proxy = this.convertValues(source["proxy"], store.EndpointProxy);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class CreateModelPricingInput {
 	    model_name: string;
@@ -277,6 +593,92 @@ export namespace main {
 	        this.total = source["total"];
 	    }
 	}
+	export class RateLimitWindowInfo {
+	    limit: number;
+	    remaining: number;
+	    reset?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RateLimitWindowInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.limit = source["limit"];
+	        this.remaining = source["remaining"];
+	        this.reset = source["reset"];
+	    }
+	}
+	export class RateLimitInfo {
+	    requests?: RateLimitWindowInfo;
+	    tokens?: RateLimitWindowInfo;
+	    input_tokens?: RateLimitWindowInfo;
+	    output_tokens?: RateLimitWindowInfo;
+	    retry_after_seconds?: number;
+	    retry_at?: string;
+	    near_limit: boolean;
+	    status_code: number;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RateLimitInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requests = this.convertValues(source["requests"], RateLimitWindowInfo);
+	        this.tokens = this.convertValues(source["tokens"], RateLimitWindowInfo);
+	        this.input_tokens = this.convertValues(source["input_tokens"], RateLimitWindowInfo);
+	        this.output_tokens = this.convertValues(source["output_tokens"], RateLimitWindowInfo);
+	        this.retry_after_seconds = source["retry_after_seconds"];
+	        this.retry_at = source["retry_at"];
+	        this.near_limit = source["near_limit"];
+	        this.status_code = source["status_code"];
+	        this.updated_at = source["updated_at"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EndpointCapacityInfo {
+	    in_flight: number;
+	    max_concurrency: number;
+	    requests_per_minute: number;
+	    rpm: number;
+	    tokens_per_minute: number;
+	    tpm: number;
+	    saturated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointCapacityInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.in_flight = source["in_flight"];
+	        this.max_concurrency = source["max_concurrency"];
+	        this.requests_per_minute = source["requests_per_minute"];
+	        this.rpm = source["rpm"];
+	        this.tokens_per_minute = source["tokens_per_minute"];
+	        this.tpm = source["tpm"];
+	        this.saturated = source["saturated"];
+	    }
+	}
 	export class EndpointInfo {
 	    name: string;
 	    url: string;
@@ -289,6 +691,19 @@ export namespace main {
 	    last_check: string;
 	    response_time_ms: number;
 	    consecutive_fail: number;
+	    weight: number;
+	    in_flight: number;
+	    ewma_latency_ms: number;
+	    circuit_state: string;
+	    circuit_reason: string;
+	    circuit_opened_at: string;
+	    circuit_half_open_at: string;
+	    circuit_requests: number;
+	    circuit_error_rate: number;
+	    circuit_slow_call_rate: number;
+	    circuit_avg_latency_ms: number;
+	    rate_limit?: RateLimitInfo;
+	    capacity?: EndpointCapacityInfo;
 	
 	    static createFrom(source: any = {}) {
 	        return new EndpointInfo(source);
@@ -307,13 +722,50 @@ export namespace main {
 	        this.last_check = source["last_check"];
 	        this.response_time_ms = source["response_time_ms"];
 	        this.consecutive_fail = source["consecutive_fail"];
+	        this.weight = source["weight"];
+	        this.in_flight = source["in_flight"];
+	        this.ewma_latency_ms = source["ewma_latency_ms"];
+	        this.circuit_state = source["circuit_state"];
+	        this.circuit_reason = source["circuit_reason"];
+	        this.circuit_opened_at = source["circuit_opened_at"];
+	        this.circuit_half_open_at = source["circuit_half_open_at"];
+	        this.circuit_requests = source["circuit_requests"];
+	        this.circuit_error_rate = source["circuit_error_rate"];
+	        this.circuit_slow_call_rate = source["circuit_slow_call_rate"];
+	        this.circuit_avg_latency_ms = source["circuit_avg_latency_ms"];
+	        this.rate_limit = this.convertValues(source["rate_limit"], RateLimitInfo);
+	        this.capacity = this.convertValues(source["capacity"], EndpointCapacityInfo);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class KeyInfo {
 	    index: number;
 	    name: string;
 	    value: string;
 	    is_active: boolean;
+	    status: string;
+	    cooldown_until?: string;
+	    usage_count: number;
+	    last_status_code?: number;
+	    last_error?: string;
+	    last_used_at?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new KeyInfo(source);
@@ -325,6 +777,12 @@ export namespace main {
 	        this.name = source["name"];
 	        this.value = source["value"];
 	        this.is_active = source["is_active"];
+	        this.status = source["status"];
+	        this.cooldown_until = source["cooldown_until"];
+	        this.usage_count = source["usage_count"];
+	        this.last_status_code = source["last_status_code"];
+	        this.last_error = source["last_error"];
+	        this.last_used_at = source["last_used_at"];
 	    }
 	}
 	export class EndpointKeysInfo {
@@ -333,6 +791,8 @@ export namespace main {
 	    api_keys: KeyInfo[];
 	    current_token_index: number;
 	    current_api_key_index: number;
+	    auto_rotation: boolean;
+	    strategy: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new EndpointKeysInfo(source);
@@ -345,6 +805,8 @@ export namespace main {
 	        this.api_keys = this.convertValues(source["api_keys"], KeyInfo);
 	        this.current_token_index = source["current_token_index"];
 	        this.current_api_key_index = source["current_api_key_index"];
+	        this.auto_rotation = source["auto_rotation"];
+	        this.strategy = source["strategy"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -365,6 +827,30 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class EndpointProxyInfo {
+	    enabled: boolean;
+	    type: string;
+	    url?: string;
+	    host?: string;
+	    port?: number;
+	    username?: string;
+	    password_set: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointProxyInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.type = source["type"];
+	        this.url = source["url"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.username = source["username"];
+	        this.password_set = source["password_set"];
+	    }
+	}
 	export class EndpointRecordInfo {
 	    id: number;
 	    channel: string;
@@ -376,16 +862,23 @@ export namespace main {
 	    api_key_masked: string;
 	    headers: Record<string, string>;
 	    priority: number;
+	    weight: number;
 	    failover_enabled: boolean;
 	    cooldown_seconds?: number;
 	    timeout_seconds: number;
 	    supports_count_tokens: boolean;
+	    model_mapping: Record<string, string>;
+	    retry_policy: string;
+	    max_concurrency: number;
+	    rpm: number;
+	    tpm: number;
 	    cost_multiplier: number;
 	    input_cost_multiplier: number;
 	    output_cost_multiplier: number;
 	    cache_creation_cost_multiplier: number;
 	    cache_read_cost_multiplier: number;
 	    enabled: boolean;
+	    proxy?: EndpointProxyInfo;
 	    created_at: string;
 	    updated_at: string;
 	    healthy: boolean;
@@ -411,16 +904,23 @@ export namespace main {
 	        this.api_key_masked = source["api_key_masked"];
 	        this.headers = source["headers"];
 	        this.priority = source["priority"];
+	        this.weight = source["weight"];
 	        this.failover_enabled = source["failover_enabled"];
 	        this.cooldown_seconds = source["cooldown_seconds"];
 	        this.timeout_seconds = source["timeout_seconds"];
 	        this.supports_count_tokens = source["supports_count_tokens"];
+	        this.model_mapping = source["model_mapping"];
+	        this.retry_policy = source["retry_policy"];
+	        this.max_concurrency = source["max_concurrency"];
+	        this.rpm = source["rpm"];
+	        this.tpm = source["tpm"];
 	        this.cost_multiplier = source["cost_multiplier"];
 	        this.input_cost_multiplier = source["input_cost_multiplier"];
 	        this.output_cost_multiplier = source["output_cost_multiplier"];
 	        this.cache_creation_cost_multiplier = source["cache_creation_cost_multiplier"];
 	        this.cache_read_cost_multiplier = source["cache_read_cost_multiplier"];
 	        this.enabled = source["enabled"];
+	        this.proxy = this.convertValues(source["proxy"], EndpointProxyInfo);
 	        this.created_at = source["created_at"];
 	        this.updated_at = source["updated_at"];
 	        this.healthy = source["healthy"];
@@ -430,6 +930,24 @@ export namespace main {
 	        this.cooldown_until = source["cooldown_until"];
 	        this.cooldown_reason = source["cooldown_reason"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class EndpointStorageStatus {
 	    enabled: boolean;
@@ -449,6 +967,24 @@ export namespace main {
 	        this.enabled_count = source["enabled_count"];
 	    }
 	}
+	export class EndpointTTFTItem {
+	    name: string;
+	    p50_ms: number;
+	    p95_ms: number;
+	    samples: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointTTFTItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.p50_ms = source["p50_ms"];
+	        this.p95_ms = source["p95_ms"];
+	        this.samples = source["samples"];
+	    }
+	}
 	export class GroupInfo {
 	    name: string;
 	    channel: string;
@@ -460,36 +996,232 @@ export namespace main {
 	    cooldown_remain_ms: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new GroupInfo(source);
+	        return new GroupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.channel = source["channel"];
+	        this.active = source["active"];
+	        this.paused = source["paused"];
+	        this.priority = source["priority"];
+	        this.endpoint_count = source["endpoint_count"];
+	        this.in_cooldown = source["in_cooldown"];
+	        this.cooldown_remain_ms = source["cooldown_remain_ms"];
+	    }
+	}
+	
+	export class KeysOverviewResult {
+	    endpoints: EndpointKeysInfo[];
+	    total: number;
+	    timestamp: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new KeysOverviewResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoints = this.convertValues(source["endpoints"], EndpointKeysInfo);
+	        this.total = source["total"];
+	        this.timestamp = source["timestamp"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelPricingInfo {
+	    id: number;
+	    model_name: string;
+	    display_name: string;
+	    description: string;
+	    input_price: number;
+	    output_price: number;
+	    cache_creation_price_5m: number;
+	    cache_creation_price_1h: number;
+	    cache_read_price: number;
+	    is_default: boolean;
+	    created_at: string;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.model_name = source["model_name"];
+	        this.display_name = source["display_name"];
+	        this.description = source["description"];
+	        this.input_price = source["input_price"];
+	        this.output_price = source["output_price"];
+	        this.cache_creation_price_5m = source["cache_creation_price_5m"];
+	        this.cache_creation_price_1h = source["cache_creation_price_1h"];
+	        this.cache_read_price = source["cache_read_price"];
+	        this.is_default = source["is_default"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	    }
+	}
+	export class ModelPricingFieldChangeInfo {
+	    field: string;
+	    old: any;
+	    new: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingFieldChangeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class ModelPricingChangeInfo {
+	    model_name: string;
+	    action: string;
+	    fields: ModelPricingFieldChangeInfo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingChangeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model_name = source["model_name"];
+	        this.action = source["action"];
+	        this.fields = this.convertValues(source["fields"], ModelPricingFieldChangeInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelPricingAuditInfo {
+	    id: number;
+	    source: string;
+	    format: string;
+	    added: number;
+	    changed: number;
+	    unchanged: number;
+	    changes: ModelPricingChangeInfo[];
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingAuditInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source = source["source"];
+	        this.format = source["format"];
+	        this.added = source["added"];
+	        this.changed = source["changed"];
+	        this.unchanged = source["unchanged"];
+	        this.changes = this.convertValues(source["changes"], ModelPricingChangeInfo);
+	        this.created_at = source["created_at"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelPricingImportInput {
+	    content: string;
+	    format: string;
+	    providers: string[];
+	    only_existing: boolean;
+	    source: string;
+	    fingerprint: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingImportInput(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.channel = source["channel"];
-	        this.active = source["active"];
-	        this.paused = source["paused"];
-	        this.priority = source["priority"];
-	        this.endpoint_count = source["endpoint_count"];
-	        this.in_cooldown = source["in_cooldown"];
-	        this.cooldown_remain_ms = source["cooldown_remain_ms"];
+	        this.content = source["content"];
+	        this.format = source["format"];
+	        this.providers = source["providers"];
+	        this.only_existing = source["only_existing"];
+	        this.source = source["source"];
+	        this.fingerprint = source["fingerprint"];
 	    }
 	}
-	
-	export class KeysOverviewResult {
-	    endpoints: EndpointKeysInfo[];
+	export class ModelPricingImportResult {
+	    format: string;
 	    total: number;
-	    timestamp: string;
+	    skipped: number;
+	    new: number;
+	    changed: number;
+	    unchanged: number;
+	    changes: ModelPricingChangeInfo[];
+	    fingerprint: string;
+	    audit_id: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new KeysOverviewResult(source);
+	        return new ModelPricingImportResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.endpoints = this.convertValues(source["endpoints"], EndpointKeysInfo);
+	        this.format = source["format"];
 	        this.total = source["total"];
-	        this.timestamp = source["timestamp"];
+	        this.skipped = source["skipped"];
+	        this.new = source["new"];
+	        this.changed = source["changed"];
+	        this.unchanged = source["unchanged"];
+	        this.changes = this.convertValues(source["changes"], ModelPricingChangeInfo);
+	        this.fingerprint = source["fingerprint"];
+	        this.audit_id = source["audit_id"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -510,54 +1242,74 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class ModelPricingInfo {
+	export class ModelPricingStorageStatus {
+	    enabled: boolean;
+	    total_count: number;
+	    has_default: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricingStorageStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.total_count = source["total_count"];
+	        this.has_default = source["has_default"];
+	    }
+	}
+	export class ModelPricingVersionInfo {
 	    id: number;
 	    model_name: string;
-	    display_name: string;
-	    description: string;
 	    input_price: number;
 	    output_price: number;
 	    cache_creation_price_5m: number;
 	    cache_creation_price_1h: number;
 	    cache_read_price: number;
-	    is_default: boolean;
+	    effective_from: string;
+	    source: string;
 	    created_at: string;
-	    updated_at: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ModelPricingInfo(source);
+	        return new ModelPricingVersionInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.model_name = source["model_name"];
-	        this.display_name = source["display_name"];
-	        this.description = source["description"];
 	        this.input_price = source["input_price"];
 	        this.output_price = source["output_price"];
 	        this.cache_creation_price_5m = source["cache_creation_price_5m"];
 	        this.cache_creation_price_1h = source["cache_creation_price_1h"];
 	        this.cache_read_price = source["cache_read_price"];
-	        this.is_default = source["is_default"];
+	        this.effective_from = source["effective_from"];
+	        this.source = source["source"];
 	        this.created_at = source["created_at"];
-	        this.updated_at = source["updated_at"];
 	    }
 	}
-	export class ModelPricingStorageStatus {
-	    enabled: boolean;
-	    total_count: number;
-	    has_default: boolean;
+	export class ModelPricingVersionInput {
+	    model_name: string;
+	    input_price: number;
+	    output_price: number;
+	    cache_creation_price_5m: number;
+	    cache_creation_price_1h: number;
+	    cache_read_price: number;
+	    effective_from: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ModelPricingStorageStatus(source);
+	        return new ModelPricingVersionInput(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.total_count = source["total_count"];
-	        this.has_default = source["has_default"];
+	        this.model_name = source["model_name"];
+	        this.input_price = source["input_price"];
+	        this.output_price = source["output_price"];
+	        this.cache_creation_price_5m = source["cache_creation_price_5m"];
+	        this.cache_creation_price_1h = source["cache_creation_price_1h"];
+	        this.cache_read_price = source["cache_read_price"];
+	        this.effective_from = source["effective_from"];
 	    }
 	}
 	export class PortInfo {
@@ -598,8 +1350,11 @@ export namespace main {
 	    cache_creation_1h_tokens: number;
 	    cache_read_tokens: number;
 	    response_time: number;
+	    ttft_ms?: number;
+	    output_tokens_per_sec?: number;
 	    is_streaming: boolean;
 	    cost: number;
+	    client_key_name?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new RequestRecord(source);
@@ -626,8 +1381,11 @@ export namespace main {
 	        this.cache_creation_1h_tokens = source["cache_creation_1h_tokens"];
 	        this.cache_read_tokens = source["cache_read_tokens"];
 	        this.response_time = source["response_time"];
+	        this.ttft_ms = source["ttft_ms"];
+	        this.output_tokens_per_sec = source["output_tokens_per_sec"];
 	        this.is_streaming = source["is_streaming"];
 	        this.cost = source["cost"];
+	        this.client_key_name = source["client_key_name"];
 	    }
 	}
 	export class RequestListResult {
@@ -676,6 +1434,7 @@ export namespace main {
 	    channel: string;
 	    endpoint: string;
 	    group: string;
+	    client_key: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new RequestQueryParams(source);
@@ -692,9 +1451,156 @@ export namespace main {
 	        this.channel = source["channel"];
 	        this.endpoint = source["endpoint"];
 	        this.group = source["group"];
+	        this.client_key = source["client_key"];
+	    }
+	}
+	
+	export class ResponseCacheEntryInfo {
+	    cache_key: string;
+	    model: string;
+	    path: string;
+	    body_size: number;
+	    hit_count: number;
+	    expired: boolean;
+	    expires_at: string;
+	    last_hit_at: string;
+	    created_at: string;
+	    headers?: Record<string, Array<string>>;
+	    body?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResponseCacheEntryInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cache_key = source["cache_key"];
+	        this.model = source["model"];
+	        this.path = source["path"];
+	        this.body_size = source["body_size"];
+	        this.hit_count = source["hit_count"];
+	        this.expired = source["expired"];
+	        this.expires_at = source["expires_at"];
+	        this.last_hit_at = source["last_hit_at"];
+	        this.created_at = source["created_at"];
+	        this.headers = source["headers"];
+	        this.body = source["body"];
+	    }
+	}
+	export class ResponseCachePurgeResult {
+	    purged: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResponseCachePurgeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.purged = source["purged"];
+	    }
+	}
+	export class ResponseCacheStatsInfo {
+	    enabled: boolean;
+	    ttl: string;
+	    max_entries: number;
+	    entries: number;
+	    total_hits: number;
+	    total_bytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResponseCacheStatsInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.ttl = source["ttl"];
+	        this.max_entries = source["max_entries"];
+	        this.entries = source["entries"];
+	        this.total_hits = source["total_hits"];
+	        this.total_bytes = source["total_bytes"];
+	    }
+	}
+	export class RoutingRuleInfo {
+	    id: number;
+	    name: string;
+	    model_pattern: string;
+	    description: string;
+	    endpoints: string[];
+	    channels: string[];
+	    priority: number;
+	    fallback: boolean;
+	    enabled: boolean;
+	    created_at: string;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RoutingRuleInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.model_pattern = source["model_pattern"];
+	        this.description = source["description"];
+	        this.endpoints = source["endpoints"];
+	        this.channels = source["channels"];
+	        this.priority = source["priority"];
+	        this.fallback = source["fallback"];
+	        this.enabled = source["enabled"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	    }
+	}
+	export class RoutingRuleInput {
+	    name: string;
+	    model_pattern: string;
+	    description: string;
+	    endpoints: string[];
+	    channels: string[];
+	    priority: number;
+	    fallback: boolean;
+	    enabled?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RoutingRuleInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.model_pattern = source["model_pattern"];
+	        this.description = source["description"];
+	        this.endpoints = source["endpoints"];
+	        this.channels = source["channels"];
+	        this.priority = source["priority"];
+	        this.fallback = source["fallback"];
+	        this.enabled = source["enabled"];
 	    }
 	}
+	export class RoutingRuleMatchResult {
+	    model: string;
+	    matched: boolean;
+	    rule: string;
+	    endpoints: string[];
+	    channels: string[];
+	    fallback: boolean;
 	
+	    static createFrom(source: any = {}) {
+	        return new RoutingRuleMatchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.matched = source["matched"];
+	        this.rule = source["rule"];
+	        this.endpoints = source["endpoints"];
+	        this.channels = source["channels"];
+	        this.fallback = source["fallback"];
+	    }
+	}
 	export class SettingInfo {
 	    id: number;
 	    category: string;
@@ -705,6 +1611,7 @@ export namespace main {
 	    description: string;
 	    display_order: number;
 	    requires_restart: boolean;
+	    value_set?: boolean;
 	    created_at: string;
 	    updated_at: string;
 	
@@ -723,6 +1630,7 @@ export namespace main {
 	        this.description = source["description"];
 	        this.display_order = source["display_order"];
 	        this.requires_restart = source["requires_restart"];
+	        this.value_set = source["value_set"];
 	        this.created_at = source["created_at"];
 	        this.updated_at = source["updated_at"];
 	    }
@@ -826,6 +1734,7 @@ export namespace main {
 	    total_cost_usd: number;
 	    total_tokens: number;
 	    failed_requests: number;
+	    client_key_stats?: Record<string, tracking.ClientKeyStat>;
 	
 	    static createFrom(source: any = {}) {
 	        return new UsageStatsData(source);
@@ -840,7 +1749,26 @@ export namespace main {
 	        this.total_cost_usd = source["total_cost_usd"];
 	        this.total_tokens = source["total_tokens"];
 	        this.failed_requests = source["failed_requests"];
+	        this.client_key_stats = this.convertValues(source["client_key_stats"], tracking.ClientKeyStat, true);
 	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class UsageStatsQueryParams {
 	    period: string;
@@ -851,6 +1779,7 @@ export namespace main {
 	    channel: string;
 	    endpoint: string;
 	    group: string;
+	    client_key: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new UsageStatsQueryParams(source);
@@ -866,6 +1795,7 @@ export namespace main {
 	        this.channel = source["channel"];
 	        this.endpoint = source["endpoint"];
 	        this.group = source["group"];
+	        this.client_key = source["client_key"];
 	    }
 	}
 	export class UsageSummary {
@@ -902,6 +1832,162 @@ export namespace main {
 	        this.all_time_total_tokens = source["all_time_total_tokens"];
 	    }
 	}
+	export class WebhookDeliveryInfo {
+	    id: number;
+	    webhook: string;
+	    event_type: string;
+	    success: boolean;
+	    attempts: number;
+	    status_code: number;
+	    error: string;
+	    payload: string;
+	    duration_ms: number;
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookDeliveryInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.webhook = source["webhook"];
+	        this.event_type = source["event_type"];
+	        this.success = source["success"];
+	        this.attempts = source["attempts"];
+	        this.status_code = source["status_code"];
+	        this.error = source["error"];
+	        this.payload = source["payload"];
+	        this.duration_ms = source["duration_ms"];
+	        this.created_at = source["created_at"];
+	    }
+	}
+	export class WebhookEventTypeInfo {
+	    type: string;
+	    default: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookEventTypeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.default = source["default"];
+	    }
+	}
+	export class WebhookInfo {
+	    id: number;
+	    name: string;
+	    url: string;
+	    format: string;
+	    description: string;
+	    event_types: string[];
+	    template: string;
+	    secret_set: boolean;
+	    max_retries: number;
+	    enabled: boolean;
+	    created_at: string;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.format = source["format"];
+	        this.description = source["description"];
+	        this.event_types = source["event_types"];
+	        this.template = source["template"];
+	        this.secret_set = source["secret_set"];
+	        this.max_retries = source["max_retries"];
+	        this.enabled = source["enabled"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	    }
+	}
+	export class WebhookInput {
+	    name: string;
+	    url: string;
+	    format: string;
+	    description: string;
+	    event_types: string[];
+	    template: string;
+	    secret?: string;
+	    max_retries?: number;
+	    enabled?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.format = source["format"];
+	        this.description = source["description"];
+	        this.event_types = source["event_types"];
+	        this.template = source["template"];
+	        this.secret = source["secret"];
+	        this.max_retries = source["max_retries"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+
+}
+
+export namespace store {
+	
+	export class EndpointProxy {
+	    enabled: boolean;
+	    type: string;
+	    url?: string;
+	    host?: string;
+	    port?: number;
+	    username?: string;
+	    password?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointProxy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.type = source["type"];
+	        this.url = source["url"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	    }
+	}
+
+}
+
+export namespace tracking {
+	
+	export class ClientKeyStat {
+	    request_count: number;
+	    total_tokens: number;
+	    total_cost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientKeyStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request_count = source["request_count"];
+	        this.total_tokens = source["total_tokens"];
+	        this.total_cost = source["total_cost"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,165 @@
+// Package capture 提供请求/响应抓取：记录转发到端点的完整请求和原始响应（含 SSE 字节流的到达时间），
+// 按请求 ID 保存为 JSON 文件，用于重放到指定端点或离线重放流式响应
+package capture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RedactedValue 脱敏后的头部值
+const RedactedValue = "[REDACTED]"
+
+// redactedHeaders 需要脱敏的头部（鉴权和会话相关）
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// Capture 一个请求的抓取记录（按请求 ID 保存，重试时每次端点尝试对应一个 Attempt）
+type Capture struct {
+	RequestID string     `json:"request_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Attempts  []*Attempt `json:"attempts"`
+}
+
+// Attempt 一次端点尝试的请求和响应
+type Attempt struct {
+	Endpoint   string    `json:"endpoint"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Request    Request   `json:"request"`
+	Response   *Response `json:"response,omitempty"` // 连接失败时为空
+	Error      string    `json:"error,omitempty"`    // 连接或读取响应体失败的错误
+}
+
+// Request 发往端点的请求（已应用模型映射，鉴权头部已脱敏）
+type Request struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Header    http.Header `json:"header"`
+	Body      Payload     `json:"body"`
+	Truncated bool        `json:"truncated,omitempty"` // 请求体超过 max_body_size 被截断
+}
+
+// Response 端点返回的原始响应（未解压，按到达顺序分块记录）
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Chunks     []Chunk     `json:"chunks"`
+	Complete   bool        `json:"complete"`            // 响应体已完整读取（读到 EOF）
+	Truncated  bool        `json:"truncated,omitempty"` // 响应体超过 max_body_size 后不再记录
+}
+
+// Chunk 一段响应数据及其到达时间
+type Chunk struct {
+	OffsetMs int64   `json:"offset_ms"` // 相对尝试开始的毫秒数
+	Data     Payload `json:"data"`
+}
+
+// Payload 请求体或响应数据，UTF-8 文本按原文保存，其他数据（如压缩响应）以 base64 保存
+type Payload []byte
+
+// payloadJSON Payload 的 JSON 表示
+type payloadJSON struct {
+	Base64 string `json:"base64"`
+}
+
+// MarshalJSON 文本按字符串输出，二进制数据输出为 {"base64": "..."}
+func (p Payload) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(p) {
+		return json.Marshal(string(p))
+	}
+	return json.Marshal(payloadJSON{Base64: base64.StdEncoding.EncodeToString(p)})
+}
+
+// UnmarshalJSON 解析字符串或 {"base64": "..."}
+func (p *Payload) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var encoded payloadJSON
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+		if err != nil {
+			return fmt.Errorf("解码 base64 数据失败: %w", err)
+		}
+		*p = decoded
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*p = Payload(text)
+	return nil
+}
+
+// Body 拼接所有分块，得到完整的原始响应体
+func (r *Response) Body() []byte {
+	var buf bytes.Buffer
+	for _, chunk := range r.Chunks {
+		buf.Write(chunk.Data)
+	}
+	return buf.Bytes()
+}
+
+// IsStream 响应是否为 SSE 流
+func (r *Response) IsStream() bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// Attempt 获取指定序号的尝试，index 为负数时从末尾计数（-1 为最后一次尝试）
+func (c *Capture) Attempt(index int) (*Attempt, error) {
+	if index < 0 {
+		index += len(c.Attempts)
+	}
+	if index < 0 || index >= len(c.Attempts) {
+		return nil, fmt.Errorf("抓取记录 '%s' 没有第 %d 次尝试（共 %d 次）", c.RequestID, index, len(c.Attempts))
+	}
+	return c.Attempts[index], nil
+}
+
+// RedactHeader 复制头部并脱敏鉴权相关的值，secretHeaders 为额外需要脱敏的头部（如端点配置的自定义头部）
+func RedactHeader(header http.Header, secretHeaders ...string) http.Header {
+	secrets := make(map[string]bool, len(secretHeaders))
+	for _, name := range secretHeaders {
+		secrets[http.CanonicalHeaderKey(name)] = true
+	}
+
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		canonical := http.CanonicalHeaderKey(key)
+		if redactedHeaders[canonical] || secrets[canonical] {
+			redacted[key] = []string{RedactedValue}
+			continue
+		}
+		redacted[key] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// LoadFile 从 JSON 文件读取抓取记录（可用于把抓取文件作为回归测试数据）
+func LoadFile(path string) (*Capture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取抓取文件失败: %w", err)
+	}
+
+	var c Capture
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("解析抓取文件失败: %w", err)
+	}
+	return &c, nil
+}
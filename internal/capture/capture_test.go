@@ -0,0 +1,332 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// TestRecorderCapturesStream 测试抓取请求和分块到达的 SSE 响应
+func TestRecorderCapturesStream(t *testing.T) {
+	events := []string{
+		"event: message_start\ndata: {\"type\":\"message_start\"}\n\n",
+		"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Set-Cookie", "session=secret")
+		for _, event := range events {
+			io.WriteString(w, event)
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewRecorder(config.CaptureConfig{Enabled: true, Dir: dir, MaxFiles: 10, MaxBodySize: 1024})
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport, "ep1", "x-relay-key")}
+
+	ctx := context.WithValue(context.Background(), requestIDKey, "req-test1")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/messages?beta=true", strings.NewReader(`{"model":"m","stream":true}`))
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("X-Relay-Key", "relay-secret")
+	req.Header.Set("Anthropic-Version", "2023-06-01")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	c, err := recorder.Store().Get("req-test1")
+	if err != nil {
+		t.Fatalf("读取抓取记录失败: %v", err)
+	}
+	attempt, err := c.Attempt(-1)
+	if err != nil {
+		t.Fatalf("获取尝试失败: %v", err)
+	}
+
+	if attempt.Endpoint != "ep1" || string(attempt.Request.Body) != `{"model":"m","stream":true}` {
+		t.Errorf("请求记录不匹配: %s, %s", attempt.Endpoint, attempt.Request.Body)
+	}
+	if got := attempt.Request.Header.Get("Authorization"); got != RedactedValue {
+		t.Errorf("Authorization 应被脱敏: %s", got)
+	}
+	if got := attempt.Request.Header.Get("X-Relay-Key"); got != RedactedValue {
+		t.Errorf("端点自定义头部应被脱敏: %s", got)
+	}
+	if got := attempt.Request.Header.Get("Anthropic-Version"); got != "2023-06-01" {
+		t.Errorf("普通头部应保留: %s", got)
+	}
+	if got := attempt.Response.Header.Get("Set-Cookie"); got != RedactedValue {
+		t.Errorf("Set-Cookie 应被脱敏: %s", got)
+	}
+	if !attempt.Response.IsStream() || !attempt.Response.Complete {
+		t.Errorf("应记录为完整的流式响应: stream=%v complete=%v", attempt.Response.IsStream(), attempt.Response.Complete)
+	}
+	if string(attempt.Response.Body()) != string(body) {
+		t.Errorf("记录的响应体与客户端读取的不一致:\n%s\n%s", attempt.Response.Body(), body)
+	}
+	if len(attempt.Response.Chunks) < 2 {
+		t.Fatalf("期望按到达顺序记录多个数据块, 实际 %d", len(attempt.Response.Chunks))
+	}
+	last := attempt.Response.Chunks[len(attempt.Response.Chunks)-1]
+	if last.OffsetMs < 20 {
+		t.Errorf("最后一个数据块的到达时间应晚于 20ms: %d", last.OffsetMs)
+	}
+
+	// 同一请求的重试追加到同一个文件
+	req2, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/messages", strings.NewReader("{}"))
+	resp2, err := client.Do(req2)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp2.Body.Close()
+
+	c, _ = recorder.Store().Get("req-test1")
+	if len(c.Attempts) != 2 {
+		t.Errorf("期望 2 次尝试, 实际 %d", len(c.Attempts))
+	}
+	if c.Attempts[1].Response.Complete {
+		t.Error("未读完就关闭的响应不应标记为完整")
+	}
+}
+
+// TestRecorderTransportError 测试连接失败时记录错误
+func TestRecorderTransportError(t *testing.T) {
+	recorder := NewRecorder(config.CaptureConfig{Enabled: true, Dir: t.TempDir()})
+	failing := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	client := &http.Client{Transport: recorder.Wrap(failing, "ep1")}
+
+	ctx := context.WithValue(context.Background(), requestIDKey, "req-fail")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://upstream/v1/messages", strings.NewReader("{}"))
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("期望请求失败")
+	}
+
+	c, err := recorder.Store().Get("req-fail")
+	if err != nil {
+		t.Fatalf("读取抓取记录失败: %v", err)
+	}
+	if c.Attempts[0].Response != nil || !strings.Contains(c.Attempts[0].Error, "connection refused") {
+		t.Errorf("应记录连接错误: %+v", c.Attempts[0])
+	}
+}
+
+// TestRecorderDisabledAndTruncate 测试未启用时不记录，以及超过上限时截断
+func TestRecorderDisabledAndTruncate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("x", 100))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewRecorder(config.CaptureConfig{Enabled: false, Dir: dir, MaxBodySize: 10})
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport, "ep1")}
+	ctx := context.WithValue(context.Background(), requestIDKey, "req-trunc")
+
+	do := func() {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(strings.Repeat("y", 20)))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if len(body) != 100 {
+			t.Fatalf("客户端应收到完整响应, 实际 %d 字节", len(body))
+		}
+	}
+
+	do()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("未启用时不应写入抓取文件: %d", len(entries))
+	}
+
+	recorder.Configure(config.CaptureConfig{Enabled: true, Dir: dir, MaxBodySize: 10})
+	do()
+
+	c, err := recorder.Store().Get("req-trunc")
+	if err != nil {
+		t.Fatalf("读取抓取记录失败: %v", err)
+	}
+	attempt := c.Attempts[0]
+	if len(attempt.Request.Body) != 10 || !attempt.Request.Truncated {
+		t.Errorf("请求体应截断为 10 字节: %d, %v", len(attempt.Request.Body), attempt.Request.Truncated)
+	}
+	if len(attempt.Response.Body()) != 10 || !attempt.Response.Truncated {
+		t.Errorf("响应体应截断为 10 字节: %d, %v", len(attempt.Response.Body()), attempt.Response.Truncated)
+	}
+}
+
+// TestStoreRotation 测试超过文件数上限时删除最旧的文件
+func TestStoreRotation(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir, 2)
+
+	for i := 0; i < 3; i++ {
+		attempt := &Attempt{Endpoint: "ep", StartedAt: time.Now(), Request: Request{Method: "POST", URL: "http://h/v1/messages"}}
+		id := fmt.Sprintf("req-%d", i)
+		if err := s.Append(id, attempt); err != nil {
+			t.Fatalf("写入 %s 失败: %v", id, err)
+		}
+		// 保证修改时间不同
+		old := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(filepath.Join(dir, id+fileExt), old, old)
+	}
+
+	if _, err := s.Get("req-0"); err == nil {
+		t.Error("最旧的抓取记录应被删除")
+	}
+
+	summaries, err := s.List()
+	if err != nil {
+		t.Fatalf("列表失败: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("期望保留 2 个抓取记录, 实际 %d", len(summaries))
+	}
+	if summaries[0].Path != "/v1/messages" {
+		t.Errorf("摘要路径不匹配: %s", summaries[0].Path)
+	}
+
+	if err := s.Delete("req-2"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if err := s.Delete("req-2"); err == nil {
+		t.Error("删除不存在的记录应返回错误")
+	}
+
+	// 请求 ID 中的路径字符不会逃出抓取目录
+	if err := s.Append("../escape", &Attempt{StartedAt: time.Now()}); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".._escape"+fileExt)); err != nil {
+		t.Errorf("文件名应被清理: %v", err)
+	}
+}
+
+// TestPayloadJSON 测试文本按原文保存、二进制数据以 base64 保存
+func TestPayloadJSON(t *testing.T) {
+	cases := []Payload{
+		Payload("data: {\"type\":\"ping\"}\n\n"),
+		Payload([]byte{0x1f, 0x8b, 0x08, 0x00, 0xff}),
+		Payload(nil),
+	}
+	for _, p := range cases {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("序列化失败: %v", err)
+		}
+
+		var decoded Payload
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("解析 %s 失败: %v", data, err)
+		}
+		if string(decoded) != string(p) {
+			t.Errorf("往返结果不一致: %q -> %s -> %q", p, data, decoded)
+		}
+	}
+
+	data, _ := json.Marshal(Payload("hello"))
+	if string(data) != `"hello"` {
+		t.Errorf("文本应按字符串保存: %s", data)
+	}
+}
+
+// TestReplayHelpers 测试重放请求构造和按数据块返回响应
+func TestReplayHelpers(t *testing.T) {
+	attempt := &Attempt{
+		Endpoint: "ep1",
+		Request: Request{
+			Method: http.MethodPost,
+			URL:    "https://api.example.com/v1/messages?beta=true",
+			Header: http.Header{
+				"Authorization":     {RedactedValue},
+				"Anthropic-Version": {"2023-06-01"},
+				"Content-Length":    {"2"},
+			},
+			Body: Payload("{}"),
+		},
+		Response: &Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"text/event-stream"}},
+			Chunks: []Chunk{
+				{OffsetMs: 0, Data: Payload("event: a\n")},
+				{OffsetMs: 30, Data: Payload("data: {}\n\n")},
+			},
+		},
+	}
+
+	req, err := NewReplayRequest(context.Background(), attempt, "http://127.0.0.1:9000/")
+	if err != nil {
+		t.Fatalf("构造重放请求失败: %v", err)
+	}
+	if req.URL.String() != "http://127.0.0.1:9000/v1/messages?beta=true" {
+		t.Errorf("重放地址不匹配: %s", req.URL)
+	}
+	if req.Header.Get("Authorization") != "" || req.Header.Get("Anthropic-Version") != "2023-06-01" {
+		t.Errorf("重放头部不匹配: %v", req.Header)
+	}
+
+	start := time.Now()
+	resp, err := NewResponse(context.Background(), attempt, true)
+	if err != nil {
+		t.Fatalf("构造响应失败: %v", err)
+	}
+	var reads []string
+	buf := make([]byte, 64)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			reads = append(reads, string(buf[:n]))
+		}
+		if err != nil {
+			if err != io.ErrUnexpectedEOF {
+				t.Errorf("不完整的响应应以 ErrUnexpectedEOF 结束: %v", err)
+			}
+			break
+		}
+	}
+	if len(reads) != 2 || reads[1] != "data: {}\n\n" {
+		t.Errorf("应保持原始分块边界: %q", reads)
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Error("实时模式应按记录的时间间隔返回数据块")
+	}
+
+	attempt.Response.Complete = true
+	resp, _ = NewResponse(context.Background(), attempt, false)
+	if body, err := io.ReadAll(resp.Body); err != nil || string(body) != "event: a\ndata: {}\n\n" {
+		t.Errorf("完整响应读取不匹配: %q, %v", body, err)
+	}
+
+	attempt.Request.Truncated = true
+	if _, err := NewReplayRequest(context.Background(), attempt, "http://127.0.0.1:9000"); err == nil {
+		t.Error("截断的请求不应允许重放")
+	}
+}
+
+// roundTripFunc 函数形式的 RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,236 @@
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// requestIDKey 日志中间件写入请求上下文的请求 ID 键
+const requestIDKey = "conn_id"
+
+// Recorder 抓取记录器：包装端点 Transport，把请求和原始响应写入抓取存储
+// 未启用时包装层直接透传，配置可热更新
+type Recorder struct {
+	mu          sync.RWMutex
+	enabled     bool
+	maxBodySize int64
+	store       *Store
+	fallbackSeq atomic.Int64
+}
+
+// NewRecorder 创建抓取记录器
+func NewRecorder(cfg config.CaptureConfig) *Recorder {
+	r := &Recorder{}
+	r.Configure(cfg)
+	return r
+}
+
+// Configure 应用抓取配置（配置热更新时调用）
+func (r *Recorder) Configure(cfg config.CaptureConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enabled = cfg.Enabled
+	r.maxBodySize = cfg.MaxBodySize
+	if r.store == nil || r.store.Dir() != cfg.Dir {
+		r.store = NewStore(cfg.Dir, cfg.MaxFiles)
+	} else {
+		r.store.SetMaxFiles(cfg.MaxFiles)
+	}
+}
+
+// Enabled 是否启用抓取
+func (r *Recorder) Enabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.enabled
+}
+
+// Store 抓取存储
+func (r *Recorder) Store() *Store {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store
+}
+
+// Wrap 包装端点 Transport，启用时记录经过的请求和响应
+// secretHeaders 为端点配置的自定义头部名称，与内置鉴权头部一样脱敏后保存
+func (r *Recorder) Wrap(base http.RoundTripper, endpoint string, secretHeaders ...string) http.RoundTripper {
+	return &captureTransport{base: base, recorder: r, endpoint: endpoint, secretHeaders: secretHeaders}
+}
+
+// settings 读取当前配置快照
+func (r *Recorder) settings() (bool, int64, *Store) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.enabled, r.maxBodySize, r.store
+}
+
+// requestID 从请求上下文取请求 ID，没有时（如非代理请求）生成一个
+func (r *Recorder) requestID(req *http.Request) string {
+	if id, ok := req.Context().Value(requestIDKey).(string); ok && id != "" {
+		return id
+	}
+	return fmt.Sprintf("capture-%d-%d", time.Now().UnixNano(), r.fallbackSeq.Add(1))
+}
+
+// save 写入一次尝试，失败只记录日志，不影响请求
+func (r *Recorder) save(store *Store, requestID string, attempt *Attempt) {
+	if err := store.Append(requestID, attempt); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [请求抓取] [%s] 保存抓取记录失败: %v", requestID, err))
+		return
+	}
+	slog.Debug(fmt.Sprintf("📼 [请求抓取] [%s] 已保存端点 %s 的抓取记录", requestID, attempt.Endpoint))
+}
+
+// captureTransport 记录请求和响应的 Transport 包装
+// 位于模型映射内层，记录的是实际发往端点的请求和端点返回的原始（未解压）响应
+type captureTransport struct {
+	base          http.RoundTripper
+	recorder      *Recorder
+	endpoint      string
+	secretHeaders []string
+}
+
+// RoundTrip 记录请求，执行后包装响应体以记录到达的数据
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	enabled, maxBodySize, store := t.recorder.settings()
+	if !enabled {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	requestID := t.recorder.requestID(req)
+	attempt := &Attempt{
+		Endpoint:  t.endpoint,
+		StartedAt: start,
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: RedactHeader(req.Header, t.secretHeaders...),
+		},
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+
+		out := req.Clone(req.Context())
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		req = out
+
+		attempt.Request.Body, attempt.Request.Truncated = truncate(body, maxBodySize, 0)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		attempt.DurationMs = time.Since(start).Milliseconds()
+		attempt.Error = err.Error()
+		t.recorder.save(store, requestID, attempt)
+		return resp, err
+	}
+
+	attempt.Response = &Response{
+		StatusCode: resp.StatusCode,
+		Header:     RedactHeader(resp.Header, t.secretHeaders...),
+	}
+	resp.Body = &captureBody{
+		ReadCloser:  resp.Body,
+		recorder:    t.recorder,
+		store:       store,
+		requestID:   requestID,
+		attempt:     attempt,
+		start:       start,
+		maxBodySize: maxBodySize,
+	}
+	return resp, nil
+}
+
+// captureBody 记录响应体的每次读取（保留 SSE 数据块的到达时间），读完或关闭时保存
+type captureBody struct {
+	io.ReadCloser
+	recorder    *Recorder
+	store       *Store
+	requestID   string
+	attempt     *Attempt
+	start       time.Time
+	maxBodySize int64
+	recorded    int64
+	once        sync.Once
+}
+
+// Read 读取并记录数据块
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.record(p[:n])
+	}
+	if err == io.EOF {
+		b.attempt.Response.Complete = true
+		b.finish(nil)
+	} else if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+// Close 关闭响应体并保存（未读完时 Complete 为 false）
+func (b *captureBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+	return err
+}
+
+// record 追加数据块，超过上限后只标记截断
+func (b *captureBody) record(data []byte) {
+	resp := b.attempt.Response
+	if resp.Truncated {
+		return
+	}
+
+	chunk, truncated := truncate(data, b.maxBodySize, b.recorded)
+	if len(chunk) > 0 {
+		resp.Chunks = append(resp.Chunks, Chunk{
+			OffsetMs: time.Since(b.start).Milliseconds(),
+			Data:     chunk,
+		})
+		b.recorded += int64(len(chunk))
+	}
+	resp.Truncated = truncated
+}
+
+// finish 只保存一次
+func (b *captureBody) finish(err error) {
+	b.once.Do(func() {
+		b.attempt.DurationMs = time.Since(b.start).Milliseconds()
+		if err != nil {
+			b.attempt.Error = err.Error()
+		}
+		b.recorder.save(b.store, b.requestID, b.attempt)
+	})
+}
+
+// truncate 按上限截断数据（已记录 used 字节，maxSize <= 0 表示不限制）
+// 返回的数据是副本，避免引用调用方的缓冲区
+func truncate(data []byte, maxSize, used int64) (Payload, bool) {
+	truncated := false
+	if maxSize > 0 && used+int64(len(data)) > maxSize {
+		data = data[:maxSize-used]
+		truncated = true
+	}
+	return append(Payload(nil), data...), truncated
+}
@@ -0,0 +1,142 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NewReplayRequest 根据抓取的请求构造发往 baseURL 的重放请求
+// 保留原请求的方法、路径、查询参数、头部和请求体；脱敏的鉴权头部不会复制，需要调用方按目标端点重新设置
+func NewReplayRequest(ctx context.Context, attempt *Attempt, baseURL string) (*http.Request, error) {
+	if attempt.Request.Truncated {
+		return nil, fmt.Errorf("抓取的请求体已被截断（超过 max_body_size），无法重放")
+	}
+
+	original, err := url.Parse(attempt.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("解析抓取的请求 URL 失败: %w", err)
+	}
+
+	targetURL := strings.TrimRight(baseURL, "/") + original.Path
+	if original.RawQuery != "" {
+		targetURL += "?" + original.RawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, attempt.Request.Method, targetURL, bytes.NewReader(attempt.Request.Body))
+	if err != nil {
+		return nil, fmt.Errorf("创建重放请求失败: %w", err)
+	}
+
+	for key, values := range attempt.Request.Header {
+		// 脱敏的头部（内置鉴权头部和端点自定义头部）不重放，由重放端点重新设置
+		if redactedHeaders[http.CanonicalHeaderKey(key)] || (len(values) == 1 && values[0] == RedactedValue) {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	// 由 Transport 按实际请求体重新计算
+	req.Header.Del("Content-Length")
+
+	return req, nil
+}
+
+// NewResponse 根据抓取的响应构造 http.Response，响应体按记录的数据块依次返回
+// realtime 为 true 时按记录的到达时间间隔返回数据块，用于复现时序相关的问题
+func NewResponse(ctx context.Context, attempt *Attempt, realtime bool) (*http.Response, error) {
+	if attempt.Response == nil {
+		return nil, fmt.Errorf("抓取记录没有响应（%s）", attempt.Error)
+	}
+
+	header := attempt.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", attempt.Response.StatusCode, http.StatusText(attempt.Response.StatusCode)),
+		StatusCode:    attempt.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          NewChunkReader(ctx, attempt.Response, realtime),
+		ContentLength: -1,
+	}, nil
+}
+
+// chunkReader 依次返回抓取的响应数据块
+type chunkReader struct {
+	ctx      context.Context
+	response *Response
+	realtime bool
+	start    time.Time
+	index    int
+	pending  []byte
+}
+
+// NewChunkReader 创建按数据块返回抓取响应体的 Reader
+// 每次 Read 最多返回一个数据块，保持与原始响应相同的分块边界
+func NewChunkReader(ctx context.Context, response *Response, realtime bool) io.ReadCloser {
+	return &chunkReader{ctx: ctx, response: response, realtime: realtime}
+}
+
+// Read 返回下一个数据块，读完所有数据块后：响应完整时返回 io.EOF，否则返回 io.ErrUnexpectedEOF
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.index >= len(r.response.Chunks) {
+			if r.response.Complete {
+				return 0, io.EOF
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+
+		chunk := r.response.Chunks[r.index]
+		r.index++
+		if err := r.wait(chunk.OffsetMs); err != nil {
+			return 0, err
+		}
+		r.pending = chunk.Data
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// wait 实时模式下等待到数据块的到达时间
+func (r *chunkReader) wait(offsetMs int64) error {
+	if !r.realtime {
+		return r.ctx.Err()
+	}
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+
+	delay := time.Duration(offsetMs)*time.Millisecond - time.Since(r.start)
+	if delay <= 0 {
+		return r.ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// Close 关闭 Reader
+func (r *chunkReader) Close() error {
+	return nil
+}
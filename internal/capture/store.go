@@ -0,0 +1,242 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// fileExt 抓取文件扩展名
+const fileExt = ".json"
+
+// Summary 抓取记录摘要（列表展示用，不含请求体和响应体）
+type Summary struct {
+	RequestID  string    `json:"request_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Attempts   int       `json:"attempts"`
+	Endpoint   string    `json:"endpoint"`    // 最后一次尝试的端点
+	Path       string    `json:"path"`        // 最后一次尝试的请求路径
+	StatusCode int       `json:"status_code"` // 最后一次尝试的状态码，连接失败时为 0
+	Stream     bool      `json:"stream"`
+	Error      string    `json:"error,omitempty"`
+	Size       int64     `json:"size"` // 抓取文件大小
+}
+
+// Store 抓取文件存储：每个请求一个 JSON 文件，文件数超过上限时删除最旧的文件
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	maxFiles int
+}
+
+// NewStore 创建抓取文件存储
+func NewStore(dir string, maxFiles int) *Store {
+	return &Store{dir: dir, maxFiles: maxFiles}
+}
+
+// Dir 抓取文件目录
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Append 追加一次尝试到请求的抓取记录（不存在时创建）
+func (s *Store) Append(requestID string, attempt *Attempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("创建抓取目录失败: %w", err)
+	}
+
+	path := s.path(requestID)
+	c, err := LoadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		c = &Capture{RequestID: requestID, CreatedAt: attempt.StartedAt}
+	}
+	c.Attempts = append(c.Attempts, attempt)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化抓取记录失败: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免读取到写了一半的文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("写入抓取文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入抓取文件失败: %w", err)
+	}
+
+	return s.rotateLocked()
+}
+
+// Get 读取请求的抓取记录，不存在时返回错误
+func (s *Store) Get(requestID string) (*Capture, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := LoadFile(s.path(requestID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("抓取记录 '%s' %w", requestID, store.ErrNotFound)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// List 获取所有抓取记录摘要（按创建时间倒序）
+func (s *Store) List() ([]*Summary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.filesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*Summary, 0, len(files))
+	for _, f := range files {
+		c, err := LoadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil || len(c.Attempts) == 0 {
+			continue
+		}
+
+		last := c.Attempts[len(c.Attempts)-1]
+		summary := &Summary{
+			RequestID: c.RequestID,
+			CreatedAt: c.CreatedAt,
+			Attempts:  len(c.Attempts),
+			Endpoint:  last.Endpoint,
+			Path:      requestPath(last.Request.URL),
+			Error:     last.Error,
+			Size:      f.size,
+		}
+		if last.Response != nil {
+			summary.StatusCode = last.Response.StatusCode
+			summary.Stream = last.Response.IsStream()
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+	return summaries, nil
+}
+
+// Delete 删除请求的抓取记录
+func (s *Store) Delete(requestID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(requestID)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("抓取记录 '%s' %w", requestID, store.ErrNotFound)
+		}
+		return fmt.Errorf("删除抓取文件失败: %w", err)
+	}
+	return nil
+}
+
+// SetMaxFiles 更新最多保留的文件数
+func (s *Store) SetMaxFiles(maxFiles int) {
+	s.mu.Lock()
+	s.maxFiles = maxFiles
+	s.mu.Unlock()
+}
+
+// captureFile 抓取文件信息
+type captureFile struct {
+	os.DirEntry
+	size    int64
+	modTime time.Time
+}
+
+// filesLocked 列出目录中的抓取文件（按修改时间从旧到新）
+func (s *Store) filesLocked() ([]captureFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取抓取目录失败: %w", err)
+	}
+
+	files := make([]captureFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, captureFile{DirEntry: entry, size: info.Size(), modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	return files, nil
+}
+
+// rotateLocked 文件数超过上限时删除最旧的文件
+func (s *Store) rotateLocked() error {
+	if s.maxFiles <= 0 {
+		return nil
+	}
+
+	files, err := s.filesLocked()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(files)-s.maxFiles; i++ {
+		if err := os.Remove(filepath.Join(s.dir, files[i].Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除旧抓取文件失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// path 请求 ID 对应的文件路径（文件名只保留安全字符）
+func (s *Store) path(requestID string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, requestID)
+	return filepath.Join(s.dir, name+fileExt)
+}
+
+// requestPath 从请求 URL 中取出路径
+func requestPath(rawURL string) string {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rest := rawURL[i+3:]
+		if j := strings.IndexByte(rest, '/'); j >= 0 {
+			rawURL = rest[j:]
+		} else {
+			return "/"
+		}
+	}
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	return rawURL
+}
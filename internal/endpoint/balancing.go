@@ -0,0 +1,154 @@
+// balancing.go - 负载均衡策略
+// weighted（同优先级内按权重随机）、least_outstanding（最少在途请求）、ewma（真实请求延迟）
+// 负载均衡策略下，活跃端点与参与故障转移的健康端点共同组成端点池，流量分散到多个端点
+
+package endpoint
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 路由策略类型
+const (
+	StrategyPriority         = "priority"
+	StrategyFastest          = "fastest"
+	StrategyWeighted         = "weighted"
+	StrategyLeastOutstanding = "least_outstanding"
+	StrategyEWMA             = "ewma"
+)
+
+// ewmaAlpha EWMA 平滑系数（新样本所占权重）
+const ewmaAlpha = 0.3
+
+// InFlightCounter 在途请求计数接口（由使用追踪热池提供）
+type InFlightCounter interface {
+	GetInFlightByEndpoint() map[string]int
+}
+
+// SetInFlightCounter 设置在途请求计数来源（需在 Start 之前调用）
+func (m *Manager) SetInFlightCounter(counter InFlightCounter) {
+	m.inFlightCounter = counter
+}
+
+// IsBalancingStrategy 是否为负载均衡策略
+func IsBalancingStrategy(strategy string) bool {
+	switch strategy {
+	case StrategyWeighted, StrategyLeastOutstanding, StrategyEWMA:
+		return true
+	}
+	return false
+}
+
+// GetInFlightCounts 获取各端点的在途请求数（未设置计数来源时返回空）
+func (m *Manager) GetInFlightCounts() map[string]int {
+	if m.inFlightCounter == nil {
+		return map[string]int{}
+	}
+	return m.inFlightCounter.GetInFlightByEndpoint()
+}
+
+// RecordLatency 记录真实请求的响应延迟（收到上游响应头的耗时），更新端点 EWMA 延迟
+func (m *Manager) RecordLatency(ep *Endpoint, latency time.Duration) {
+	if ep == nil || latency <= 0 {
+		return
+	}
+
+	ep.mutex.Lock()
+	defer ep.mutex.Unlock()
+
+	if ep.Status.LatencySamples == 0 {
+		ep.Status.EWMALatency = latency
+	} else {
+		ep.Status.EWMALatency = time.Duration(ewmaAlpha*float64(latency) + (1-ewmaAlpha)*float64(ep.Status.EWMALatency))
+	}
+	ep.Status.LatencySamples++
+}
+
+// endpointWeight 获取端点权重（未配置或非法时为 1）
+func endpointWeight(ep *Endpoint) int {
+	if ep.Config.Weight <= 0 {
+		return 1
+	}
+	return ep.Config.Weight
+}
+
+// sortWeighted 按优先级分层，同一优先级内按权重随机排序
+// 使用 Efraimidis-Spirakis 加权随机抽样：key = u^(1/w)，key 越大越靠前
+func sortWeighted(endpoints []*Endpoint, random func() float64) {
+	keys := make(map[*Endpoint]float64, len(endpoints))
+	for _, ep := range endpoints {
+		keys[ep] = math.Pow(random(), 1/float64(endpointWeight(ep)))
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Config.Priority != endpoints[j].Config.Priority {
+			return endpoints[i].Config.Priority < endpoints[j].Config.Priority
+		}
+		return keys[endpoints[i]] > keys[endpoints[j]]
+	})
+}
+
+// sortLeastOutstanding 按在途请求数升序排序，相同时按优先级
+func sortLeastOutstanding(endpoints []*Endpoint, inFlight map[string]int) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		ci, cj := inFlight[endpoints[i].Config.Name], inFlight[endpoints[j].Config.Name]
+		if ci != cj {
+			return ci < cj
+		}
+		return endpoints[i].Config.Priority < endpoints[j].Config.Priority
+	})
+}
+
+// effectiveLatency 获取端点用于 ewma 策略的延迟
+// 没有真实请求样本时使用健康检查延迟
+func effectiveLatency(ep *Endpoint) time.Duration {
+	ep.mutex.RLock()
+	defer ep.mutex.RUnlock()
+
+	if ep.Status.LatencySamples > 0 {
+		return ep.Status.EWMALatency
+	}
+	return ep.Status.ResponseTime
+}
+
+// sortByEWMA 按 EWMA 延迟升序排序，相同时按优先级
+func sortByEWMA(endpoints []*Endpoint) {
+	latencies := make(map[*Endpoint]time.Duration, len(endpoints))
+	for _, ep := range endpoints {
+		latencies[ep] = effectiveLatency(ep)
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		li, lj := latencies[endpoints[i]], latencies[endpoints[j]]
+		if li != lj {
+			return li < lj
+		}
+		return endpoints[i].Config.Priority < endpoints[j].Config.Priority
+	})
+}
+
+// sortBalanced 按负载均衡策略排序端点池
+func (m *Manager) sortBalanced(strategy string, endpoints []*Endpoint, showLogs bool) {
+	switch strategy {
+	case StrategyWeighted:
+		sortWeighted(endpoints, rand.Float64)
+	case StrategyLeastOutstanding:
+		sortLeastOutstanding(endpoints, m.GetInFlightCounts())
+	case StrategyEWMA:
+		sortByEWMA(endpoints)
+	}
+
+	if showLogs && len(endpoints) > 1 {
+		names := make([]string, 0, len(endpoints))
+		for _, ep := range endpoints {
+			names = append(names, ep.Config.Name)
+		}
+		slog.Debug(fmt.Sprintf("⚖️ [负载均衡] 策略: %s, 端点顺序: %s", strategy, strings.Join(names, " → ")))
+	}
+}
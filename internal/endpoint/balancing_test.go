@@ -0,0 +1,191 @@
+package endpoint
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// staticInFlightCounter 测试用在途请求计数
+type staticInFlightCounter map[string]int
+
+func (c staticInFlightCounter) GetInFlightByEndpoint() map[string]int {
+	return c
+}
+
+func newBalancingEndpoint(name string, priority, weight int) *Endpoint {
+	return &Endpoint{
+		Config: config.EndpointConfig{Name: name, Priority: priority, Weight: weight},
+		Status: EndpointStatus{Healthy: true},
+	}
+}
+
+func TestSortWeighted(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2)).Float64
+
+	heavyFirst := 0
+	const rounds = 2000
+	for i := 0; i < rounds; i++ {
+		endpoints := []*Endpoint{
+			newBalancingEndpoint("backup", 2, 100),
+			newBalancingEndpoint("light", 1, 1),
+			newBalancingEndpoint("heavy", 1, 3),
+		}
+		sortWeighted(endpoints, random)
+
+		// 优先级分层：低优先级端点始终排在最后
+		if endpoints[2].Config.Name != "backup" {
+			t.Fatalf("优先级 2 的端点应排在最后，实际顺序: %s, %s, %s",
+				endpoints[0].Config.Name, endpoints[1].Config.Name, endpoints[2].Config.Name)
+		}
+		if endpoints[0].Config.Name == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	// 权重 3:1，heavy 排在首位的概率为 75%
+	ratio := float64(heavyFirst) / rounds
+	if ratio < 0.70 || ratio > 0.80 {
+		t.Errorf("heavy 排在首位的比例 = %.3f，期望约 0.75", ratio)
+	}
+}
+
+func TestSortWeighted_DefaultWeight(t *testing.T) {
+	random := rand.New(rand.NewPCG(3, 4)).Float64
+
+	firstCount := map[string]int{}
+	const rounds = 2000
+	for i := 0; i < rounds; i++ {
+		endpoints := []*Endpoint{
+			newBalancingEndpoint("a", 1, 0),
+			newBalancingEndpoint("b", 1, 1),
+		}
+		sortWeighted(endpoints, random)
+		firstCount[endpoints[0].Config.Name]++
+	}
+
+	// 未配置权重按 1 处理，两个端点机会均等
+	ratio := float64(firstCount["a"]) / rounds
+	if ratio < 0.45 || ratio > 0.55 {
+		t.Errorf("未配置权重的端点排在首位的比例 = %.3f，期望约 0.5", ratio)
+	}
+}
+
+func TestSortLeastOutstanding(t *testing.T) {
+	endpoints := []*Endpoint{
+		newBalancingEndpoint("busy", 1, 1),
+		newBalancingEndpoint("idle-low", 3, 1),
+		newBalancingEndpoint("idle-high", 2, 1),
+		newBalancingEndpoint("medium", 1, 1),
+	}
+	sortLeastOutstanding(endpoints, map[string]int{"busy": 5, "medium": 2})
+
+	want := []string{"idle-high", "idle-low", "medium", "busy"}
+	for i, name := range want {
+		if endpoints[i].Config.Name != name {
+			t.Errorf("位置 %d 期望 %s，实际为 %s", i, name, endpoints[i].Config.Name)
+		}
+	}
+}
+
+func TestRecordLatencyEWMA(t *testing.T) {
+	m := &Manager{}
+	ep := newBalancingEndpoint("ep", 1, 1)
+
+	m.RecordLatency(ep, 100*time.Millisecond)
+	if ep.Status.EWMALatency != 100*time.Millisecond || ep.Status.LatencySamples != 1 {
+		t.Fatalf("首个样本应直接作为 EWMA 延迟: %v (%d)", ep.Status.EWMALatency, ep.Status.LatencySamples)
+	}
+
+	m.RecordLatency(ep, 200*time.Millisecond)
+	if want := 130 * time.Millisecond; ep.Status.EWMALatency != want {
+		t.Errorf("EWMA 延迟 = %v，期望 %v", ep.Status.EWMALatency, want)
+	}
+
+	// 非法样本忽略
+	m.RecordLatency(ep, 0)
+	if ep.Status.LatencySamples != 2 {
+		t.Errorf("非法样本不应计数，样本数 = %d", ep.Status.LatencySamples)
+	}
+}
+
+func TestSortByEWMA(t *testing.T) {
+	m := &Manager{}
+
+	slow := newBalancingEndpoint("slow", 1, 1)
+	m.RecordLatency(slow, 800*time.Millisecond)
+
+	fast := newBalancingEndpoint("fast", 2, 1)
+	m.RecordLatency(fast, 200*time.Millisecond)
+
+	// 没有真实请求样本时使用健康检查延迟
+	probed := newBalancingEndpoint("probed", 3, 1)
+	probed.Status.ResponseTime = 500 * time.Millisecond
+
+	endpoints := []*Endpoint{slow, probed, fast}
+	sortByEWMA(endpoints)
+
+	want := []string{"fast", "probed", "slow"}
+	for i, name := range want {
+		if endpoints[i].Config.Name != name {
+			t.Errorf("位置 %d 期望 %s，实际为 %s", i, name, endpoints[i].Config.Name)
+		}
+	}
+}
+
+func TestGetHealthyEndpoints_LeastOutstandingPool(t *testing.T) {
+	disabled := false
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: StrategyLeastOutstanding},
+		Failover: config.FailoverConfig{Enabled: true},
+		Endpoints: []config.EndpointConfig{
+			{Name: "primary", URL: "https://primary.example.com", Priority: 1},
+			{Name: "secondary", URL: "https://secondary.example.com", Priority: 2},
+			{Name: "tertiary", URL: "https://tertiary.example.com", Priority: 3},
+			{Name: "excluded", URL: "https://excluded.example.com", Priority: 4, FailoverEnabled: &disabled},
+		},
+	}
+
+	m := NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("primary"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+
+	m.SetInFlightCounter(staticInFlightCounter{"primary": 4, "secondary": 1})
+
+	healthy := m.GetHealthyEndpoints()
+	want := []string{"tertiary", "secondary", "primary"}
+	if len(healthy) != len(want) {
+		t.Fatalf("端点池数量 = %d，期望 %d", len(healthy), len(want))
+	}
+	for i, name := range want {
+		if healthy[i].Config.Name != name {
+			t.Errorf("位置 %d 期望 %s，实际为 %s", i, name, healthy[i].Config.Name)
+		}
+	}
+
+	// 关闭故障转移后只使用活跃端点
+	cfg.Failover.Enabled = false
+	if healthy := m.GetHealthyEndpoints(); len(healthy) != 1 || healthy[0].Config.Name != "primary" {
+		t.Errorf("故障转移关闭时不应加入其他端点: %v", endpointNames(healthy))
+	}
+}
+
+func TestIsBalancingStrategy(t *testing.T) {
+	for _, s := range []string{StrategyWeighted, StrategyLeastOutstanding, StrategyEWMA} {
+		if !IsBalancingStrategy(s) {
+			t.Errorf("%s 应为负载均衡策略", s)
+		}
+	}
+	for _, s := range []string{StrategyPriority, StrategyFastest, ""} {
+		if IsBalancingStrategy(s) {
+			t.Errorf("%s 不应为负载均衡策略", s)
+		}
+	}
+}
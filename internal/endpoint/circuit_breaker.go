@@ -0,0 +1,429 @@
+// circuit_breaker.go - 端点熔断器
+// 按真实请求的滚动错误率和慢请求比例打开熔断，到期后半开放行少量试探请求，试探全部成功后关闭
+
+package endpoint
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/events"
+)
+
+// CircuitState 熔断器状态
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // 正常放行
+	CircuitOpen     CircuitState = "open"      // 熔断中，端点不参与选择
+	CircuitHalfOpen CircuitState = "half_open" // 半开，放行有限的试探请求
+)
+
+// circuitBuckets 滚动窗口划分的桶数
+const circuitBuckets = 10
+
+// CircuitStatus 端点熔断器状态快照
+type CircuitStatus struct {
+	State         CircuitState
+	Requests      int           // 窗口内请求数
+	Failures      int           // 窗口内失败请求数
+	SlowCalls     int           // 窗口内慢请求数
+	ErrorRate     float64       // 窗口内错误率
+	SlowCallRate  float64       // 窗口内慢请求比例
+	AvgLatency    time.Duration // 窗口内请求的平均响应头耗时
+	OpenedAt      time.Time     // 最近一次打开时间
+	HalfOpenAt    time.Time     // 打开状态下进入半开的时间
+	Reason        string        // 最近一次打开的原因
+	TrialRequests int           // 半开状态已放行的试探请求数
+}
+
+// CircuitTransition 熔断器状态变化
+type CircuitTransition struct {
+	Endpoint string
+	From     CircuitState
+	To       CircuitState
+	Reason   string
+}
+
+// circuitBucket 滚动窗口中的一个时间桶
+type circuitBucket struct {
+	start          time.Time
+	requests       int
+	failures       int
+	slowCalls      int
+	latency        time.Duration
+	latencySamples int
+}
+
+// circuitBreaker 单个端点的熔断器
+type circuitBreaker struct {
+	state     CircuitState
+	buckets   [circuitBuckets]circuitBucket
+	openedAt  time.Time
+	reason    string
+	trials    int // 半开状态已放行的试探请求数
+	successes int // 半开状态成功的试探请求数
+}
+
+// CircuitBreaker 管理所有端点的熔断器
+type CircuitBreaker struct {
+	breakers map[string]*circuitBreaker // endpoint name -> breaker
+	cfg      config.CircuitBreakerConfig
+	mu       sync.Mutex
+	now      func() time.Time
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		breakers: make(map[string]*circuitBreaker),
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Configure 更新熔断器配置，禁用时清空所有端点的状态
+func (cb *CircuitBreaker) Configure(cfg config.CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cfg.Enabled || cfg.Window != cb.cfg.Window {
+		cb.breakers = make(map[string]*circuitBreaker)
+	}
+	cb.cfg = cfg
+}
+
+// Enabled 是否启用熔断器
+func (cb *CircuitBreaker) Enabled() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.cfg.Enabled
+}
+
+// Allow 端点当前是否可被选择
+// 打开状态到期时转为半开；半开状态下已放行的试探请求达到上限后不再放行
+func (cb *CircuitBreaker) Allow(name string) (bool, *CircuitTransition) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cb.cfg.Enabled {
+		return true, nil
+	}
+	b := cb.breakers[name]
+	if b == nil {
+		return true, nil
+	}
+
+	transition := cb.checkHalfOpen(name, b)
+	switch b.state {
+	case CircuitOpen:
+		return false, transition
+	case CircuitHalfOpen:
+		return b.trials < cb.cfg.HalfOpenRequests, transition
+	}
+	return true, transition
+}
+
+// Acquire 请求发往端点前调用，半开状态下占用一个试探名额
+// 返回值表示该请求是否为试探请求
+func (cb *CircuitBreaker) Acquire(name string) (bool, *CircuitTransition) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cb.cfg.Enabled {
+		return false, nil
+	}
+	b := cb.breakers[name]
+	if b == nil {
+		return false, nil
+	}
+
+	transition := cb.checkHalfOpen(name, b)
+	if b.state == CircuitHalfOpen && b.trials < cb.cfg.HalfOpenRequests {
+		b.trials++
+		return true, transition
+	}
+	return false, transition
+}
+
+// Release 归还未产生结果的试探名额（如客户端取消请求）
+func (cb *CircuitBreaker) Release(name string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if b := cb.breakers[name]; b != nil && b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// Record 记录一次请求结果
+// trial 为 Acquire 的返回值；latency 为响应头耗时，为 0 时不参与慢请求统计
+func (cb *CircuitBreaker) Record(name string, trial, failure bool, latency time.Duration) *CircuitTransition {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cb.cfg.Enabled {
+		return nil
+	}
+	b := cb.breakers[name]
+	if b == nil {
+		b = &circuitBreaker{state: CircuitClosed}
+		cb.breakers[name] = b
+	}
+
+	now := cb.now()
+	slow := latency > 0 && latency >= cb.cfg.SlowCallDuration
+
+	switch b.state {
+	case CircuitOpen:
+		// 打开前已发出的请求，结果不再计入
+		return nil
+
+	case CircuitHalfOpen:
+		if !trial {
+			return nil
+		}
+		if failure || slow {
+			reason := "试探请求失败"
+			if !failure {
+				reason = fmt.Sprintf("试探请求耗时 %v", latency.Round(time.Millisecond))
+			}
+			return cb.open(name, b, now, reason)
+		}
+		b.successes++
+		if b.successes < cb.cfg.HalfOpenRequests {
+			return nil
+		}
+		b.state = CircuitClosed
+		b.buckets = [circuitBuckets]circuitBucket{}
+		b.trials, b.successes = 0, 0
+		return &CircuitTransition{Endpoint: name, From: CircuitHalfOpen, To: CircuitClosed, Reason: "试探请求全部成功"}
+	}
+
+	bucket := cb.currentBucket(b, now)
+	bucket.requests++
+	if failure {
+		bucket.failures++
+	}
+	if slow {
+		bucket.slowCalls++
+	}
+	if latency > 0 {
+		bucket.latency += latency
+		bucket.latencySamples++
+	}
+
+	stats := cb.windowStats(b, now)
+	if stats.Requests < cb.cfg.MinRequests {
+		return nil
+	}
+	if stats.ErrorRate >= cb.cfg.ErrorRate {
+		return cb.open(name, b, now, fmt.Sprintf("错误率 %.0f%% (%d/%d)", stats.ErrorRate*100, stats.Failures, stats.Requests))
+	}
+	if stats.SlowCallRate >= cb.cfg.SlowCallRate {
+		return cb.open(name, b, now, fmt.Sprintf("慢请求比例 %.0f%% (%d/%d)", stats.SlowCallRate*100, stats.SlowCalls, stats.Requests))
+	}
+	return nil
+}
+
+// Status 获取端点的熔断器状态快照
+func (cb *CircuitBreaker) Status(name string) CircuitStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b := cb.breakers[name]
+	if !cb.cfg.Enabled || b == nil {
+		return CircuitStatus{State: CircuitClosed}
+	}
+
+	status := cb.windowStats(b, cb.now())
+	status.State = b.state
+	status.OpenedAt = b.openedAt
+	status.Reason = b.reason
+	status.TrialRequests = b.trials
+	if b.state == CircuitOpen {
+		status.HalfOpenAt = b.openedAt.Add(cb.cfg.OpenDuration)
+	}
+	return status
+}
+
+// Reset 重置端点的熔断器（如手动激活端点时）
+func (cb *CircuitBreaker) Reset(name string) *CircuitTransition {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b := cb.breakers[name]
+	delete(cb.breakers, name)
+	if b == nil || b.state == CircuitClosed {
+		return nil
+	}
+	return &CircuitTransition{Endpoint: name, From: b.state, To: CircuitClosed, Reason: "手动重置"}
+}
+
+// Remove 删除端点的熔断器（端点被删除时）
+func (cb *CircuitBreaker) Remove(name string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	delete(cb.breakers, name)
+}
+
+// open 打开熔断（调用方持有锁）
+func (cb *CircuitBreaker) open(name string, b *circuitBreaker, now time.Time, reason string) *CircuitTransition {
+	from := b.state
+	b.state = CircuitOpen
+	b.openedAt = now
+	b.reason = reason
+	b.trials, b.successes = 0, 0
+	return &CircuitTransition{Endpoint: name, From: from, To: CircuitOpen, Reason: reason}
+}
+
+// checkHalfOpen 打开状态到期后转为半开（调用方持有锁）
+func (cb *CircuitBreaker) checkHalfOpen(name string, b *circuitBreaker) *CircuitTransition {
+	if b.state != CircuitOpen || cb.now().Before(b.openedAt.Add(cb.cfg.OpenDuration)) {
+		return nil
+	}
+	b.state = CircuitHalfOpen
+	b.trials, b.successes = 0, 0
+	return &CircuitTransition{Endpoint: name, From: CircuitOpen, To: CircuitHalfOpen, Reason: "熔断时长已到，放行试探请求"}
+}
+
+// currentBucket 获取当前时间所在的桶，过期的桶会被重置（调用方持有锁）
+func (cb *CircuitBreaker) currentBucket(b *circuitBreaker, now time.Time) *circuitBucket {
+	width := cb.bucketWidth()
+	start := now.Truncate(width)
+	bucket := &b.buckets[(start.UnixNano()/int64(width))%circuitBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = circuitBucket{start: start}
+	}
+	return bucket
+}
+
+// windowStats 汇总窗口内的统计（调用方持有锁）
+func (cb *CircuitBreaker) windowStats(b *circuitBreaker, now time.Time) CircuitStatus {
+	var stats CircuitStatus
+	var latency time.Duration
+	var latencySamples int
+
+	cutoff := now.Add(-cb.cfg.Window)
+	for i := range b.buckets {
+		bucket := &b.buckets[i]
+		if bucket.start.IsZero() || !bucket.start.Add(cb.bucketWidth()).After(cutoff) {
+			continue
+		}
+		stats.Requests += bucket.requests
+		stats.Failures += bucket.failures
+		stats.SlowCalls += bucket.slowCalls
+		latency += bucket.latency
+		latencySamples += bucket.latencySamples
+	}
+
+	if stats.Requests > 0 {
+		stats.ErrorRate = float64(stats.Failures) / float64(stats.Requests)
+		stats.SlowCallRate = float64(stats.SlowCalls) / float64(stats.Requests)
+	}
+	if latencySamples > 0 {
+		stats.AvgLatency = latency / time.Duration(latencySamples)
+	}
+	return stats
+}
+
+// bucketWidth 每个桶覆盖的时长
+func (cb *CircuitBreaker) bucketWidth() time.Duration {
+	width := cb.cfg.Window / circuitBuckets
+	if width <= 0 {
+		width = time.Second
+	}
+	return width
+}
+
+// ============================================================
+// Manager 集成
+// ============================================================
+
+// GetCircuitBreaker 获取端点熔断器
+func (m *Manager) GetCircuitBreaker() *CircuitBreaker {
+	return m.circuitBreaker
+}
+
+// GetCircuitStatus 获取端点的熔断器状态
+func (m *Manager) GetCircuitStatus(name string) CircuitStatus {
+	return m.circuitBreaker.Status(name)
+}
+
+// isCircuitOpen 端点是否被熔断（打开，或半开且试探名额已用完）
+func (m *Manager) isCircuitOpen(ep *Endpoint) bool {
+	allowed, transition := m.circuitBreaker.Allow(ep.Config.Name)
+	m.handleCircuitTransition(transition)
+	if !allowed {
+		slog.Debug(fmt.Sprintf("⏭️ [端点选择] 跳过熔断中的端点: %s", ep.Config.Name))
+	}
+	return !allowed
+}
+
+// AcquireCircuit 请求发往端点前调用，返回该请求是否为半开状态的试探请求
+func (m *Manager) AcquireCircuit(ep *Endpoint) bool {
+	trial, transition := m.circuitBreaker.Acquire(ep.Config.Name)
+	m.handleCircuitTransition(transition)
+	return trial
+}
+
+// ReleaseCircuit 归还未产生结果的试探名额
+func (m *Manager) ReleaseCircuit(ep *Endpoint) {
+	m.circuitBreaker.Release(ep.Config.Name)
+}
+
+// RecordCircuitResult 记录真实请求的结果
+// failure 表示 5xx、429 或连接错误；latency 为响应头耗时，为 0 时不参与慢请求统计
+func (m *Manager) RecordCircuitResult(ep *Endpoint, trial, failure bool, latency time.Duration) {
+	m.handleCircuitTransition(m.circuitBreaker.Record(ep.Config.Name, trial, failure, latency))
+}
+
+// ResetCircuit 重置端点的熔断器
+func (m *Manager) ResetCircuit(name string) {
+	m.handleCircuitTransition(m.circuitBreaker.Reset(name))
+}
+
+// handleCircuitTransition 记录日志、发布事件并刷新前端
+func (m *Manager) handleCircuitTransition(transition *CircuitTransition) {
+	if transition == nil {
+		return
+	}
+
+	switch transition.To {
+	case CircuitOpen:
+		slog.Warn(fmt.Sprintf("🧯 [熔断器] 端点 %s 熔断打开 (%s → %s)，原因: %s",
+			transition.Endpoint, transition.From, transition.To, transition.Reason))
+	case CircuitHalfOpen:
+		slog.Info(fmt.Sprintf("🧯 [熔断器] 端点 %s 进入半开状态，原因: %s", transition.Endpoint, transition.Reason))
+	default:
+		slog.Info(fmt.Sprintf("✅ [熔断器] 端点 %s 熔断关闭 (%s → %s)，原因: %s",
+			transition.Endpoint, transition.From, transition.To, transition.Reason))
+	}
+
+	if m.eventBus != nil {
+		status := m.circuitBreaker.Status(transition.Endpoint)
+		m.eventBus.Publish(events.Event{
+			Type:     events.EventEndpointCircuitChanged,
+			Source:   "circuit_breaker",
+			Priority: events.PriorityHigh,
+			Data: map[string]interface{}{
+				"endpoint":       transition.Endpoint,
+				"from":           string(transition.From),
+				"to":             string(transition.To),
+				"reason":         transition.Reason,
+				"error_rate":     status.ErrorRate,
+				"slow_call_rate": status.SlowCallRate,
+				"requests":       status.Requests,
+				"change_type":    "circuit_changed",
+				"timestamp":      time.Now().Format("2006-01-02 15:04:05"),
+			},
+		})
+	}
+
+	if m.onHealthCheckComplete != nil {
+		go m.onHealthCheckComplete()
+	}
+}
@@ -0,0 +1,181 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+func newTestCircuitBreaker(now *time.Time) *CircuitBreaker {
+	cb := NewCircuitBreaker(config.CircuitBreakerConfig{
+		Enabled:          true,
+		Window:           10 * time.Second,
+		MinRequests:      4,
+		ErrorRate:        0.5,
+		SlowCallDuration: 5 * time.Second,
+		SlowCallRate:     0.8,
+		OpenDuration:     30 * time.Second,
+		HalfOpenRequests: 2,
+	})
+	cb.now = func() time.Time { return *now }
+	return cb
+}
+
+func TestCircuitBreaker_OpensOnErrorRate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+
+	// 请求数不足 min_requests 时不判定
+	for i := 0; i < 3; i++ {
+		if tr := cb.Record("ep", false, true, 0); tr != nil {
+			t.Fatalf("请求数不足时不应打开熔断: %+v", tr)
+		}
+	}
+
+	tr := cb.Record("ep", false, false, 100*time.Millisecond)
+	if tr == nil || tr.From != CircuitClosed || tr.To != CircuitOpen {
+		t.Fatalf("错误率 75%% 应打开熔断，实际 %+v", tr)
+	}
+	if allowed, _ := cb.Allow("ep"); allowed {
+		t.Error("熔断打开时端点不应被选择")
+	}
+
+	status := cb.Status("ep")
+	if status.State != CircuitOpen || status.Requests != 4 || status.Failures != 3 {
+		t.Errorf("状态快照不符: %+v", status)
+	}
+	if !status.HalfOpenAt.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("半开时间不符: %v", status.HalfOpenAt)
+	}
+}
+
+func TestCircuitBreaker_OpensOnSlowCalls(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+
+	var tr *CircuitTransition
+	for i := 0; i < 4; i++ {
+		tr = cb.Record("ep", false, false, 6*time.Second)
+	}
+	if tr == nil || tr.To != CircuitOpen {
+		t.Fatalf("慢请求比例超过阈值应打开熔断，实际 %+v", tr)
+	}
+}
+
+func TestCircuitBreaker_WindowExpires(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+
+	for i := 0; i < 3; i++ {
+		cb.Record("ep", false, true, 0)
+	}
+
+	// 超出窗口的失败不再计入
+	now = now.Add(11 * time.Second)
+	for i := 0; i < 3; i++ {
+		if tr := cb.Record("ep", false, false, time.Second); tr != nil {
+			t.Fatalf("窗口外的失败不应计入: %+v", tr)
+		}
+	}
+	if status := cb.Status("ep"); status.Requests != 3 || status.Failures != 0 {
+		t.Errorf("窗口统计不符: %+v", status)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrials(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+	for i := 0; i < 4; i++ {
+		cb.Record("ep", false, true, 0)
+	}
+
+	// 到期后进入半开，只放行 half_open_requests 个试探请求
+	now = now.Add(31 * time.Second)
+	allowed, tr := cb.Allow("ep")
+	if !allowed || tr == nil || tr.To != CircuitHalfOpen {
+		t.Fatalf("到期后应进入半开，实际 allowed=%v %+v", allowed, tr)
+	}
+	for i := 0; i < 2; i++ {
+		if trial, _ := cb.Acquire("ep"); !trial {
+			t.Fatalf("第 %d 个请求应为试探请求", i+1)
+		}
+	}
+	if allowed, _ := cb.Allow("ep"); allowed {
+		t.Error("试探名额用完后不应再放行")
+	}
+	if trial, _ := cb.Acquire("ep"); trial {
+		t.Error("试探名额用完后的请求不应计为试探请求")
+	}
+
+	// 非试探请求的结果不影响半开状态
+	if tr := cb.Record("ep", false, true, 0); tr != nil {
+		t.Errorf("非试探请求不应改变状态: %+v", tr)
+	}
+
+	if tr := cb.Record("ep", true, false, time.Second); tr != nil {
+		t.Fatalf("试探请求未全部成功前不应关闭: %+v", tr)
+	}
+	tr = cb.Record("ep", true, false, time.Second)
+	if tr == nil || tr.From != CircuitHalfOpen || tr.To != CircuitClosed {
+		t.Fatalf("试探请求全部成功后应关闭，实际 %+v", tr)
+	}
+	if status := cb.Status("ep"); status.State != CircuitClosed || status.Requests != 0 {
+		t.Errorf("关闭后应清空窗口统计: %+v", status)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+	for i := 0; i < 4; i++ {
+		cb.Record("ep", false, true, 0)
+	}
+
+	now = now.Add(31 * time.Second)
+	trial, _ := cb.Acquire("ep")
+	if !trial {
+		t.Fatal("半开状态的请求应为试探请求")
+	}
+
+	// 客户端取消归还名额
+	cb.Release("ep")
+	if status := cb.Status("ep"); status.TrialRequests != 0 {
+		t.Errorf("归还后试探请求数应为 0，实际 %d", status.TrialRequests)
+	}
+
+	trial, _ = cb.Acquire("ep")
+	tr := cb.Record("ep", trial, true, 0)
+	if tr == nil || tr.From != CircuitHalfOpen || tr.To != CircuitOpen {
+		t.Fatalf("试探请求失败应重新打开，实际 %+v", tr)
+	}
+	if status := cb.Status("ep"); !status.OpenedAt.Equal(now) {
+		t.Errorf("重新打开时间不符: %v", status.OpenedAt)
+	}
+}
+
+func TestCircuitBreaker_DisabledAndReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newTestCircuitBreaker(&now)
+	for i := 0; i < 4; i++ {
+		cb.Record("ep", false, true, 0)
+	}
+
+	tr := cb.Reset("ep")
+	if tr == nil || tr.From != CircuitOpen || tr.To != CircuitClosed {
+		t.Fatalf("重置应关闭熔断，实际 %+v", tr)
+	}
+	if allowed, _ := cb.Allow("ep"); !allowed {
+		t.Error("重置后端点应可被选择")
+	}
+
+	cb.Configure(config.CircuitBreakerConfig{Enabled: false})
+	for i := 0; i < 10; i++ {
+		if tr := cb.Record("ep", false, true, 0); tr != nil {
+			t.Fatalf("禁用时不应打开熔断: %+v", tr)
+		}
+	}
+	if status := cb.Status("ep"); status.State != CircuitClosed {
+		t.Errorf("禁用时状态应为 closed，实际 %s", status.State)
+	}
+}
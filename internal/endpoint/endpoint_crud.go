@@ -44,6 +44,13 @@ func (m *Manager) SyncEndpoints(configs []config.EndpointConfig) {
 	m.endpoints = endpoints
 	m.endpointsMu.Unlock()
 
+	// 释放已不存在端点的转发连接
+	names := make([]string, len(configs))
+	for i, cfg := range configs {
+		names[i] = cfg.Name
+	}
+	m.transportPool.Retain(names)
+
 	// 更新 GroupManager（创建组）
 	m.groupManager.UpdateGroups(endpoints)
 
@@ -143,6 +150,12 @@ func (m *Manager) RemoveEndpoint(name string) error {
 
 	// 清理 KeyManager 状态
 	m.keyManager.RemoveEndpoint(name)
+	m.circuitBreaker.Remove(name)
+	m.rateLimits.Remove(name)
+	m.limiter.Remove(name)
+
+	// 关闭该端点的转发连接
+	m.transportPool.Invalidate(name)
 
 	// 更新 GroupManager（在锁内创建快照）
 	snapshot := make([]*Endpoint, len(m.endpoints))
@@ -0,0 +1,317 @@
+// endpoint_limits.go - 端点容量限制
+// 按端点配置的 max_concurrency / rpm / tpm 统计在途请求数和最近一分钟的请求数、Token 数，
+// 达到任一上限的端点在选择时被跳过，发出请求时原子地检查并占用名额；
+// 容量释放时按排队顺序通知一个等待中的请求重新选择端点
+
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// limitWindow RPM/TPM 的统计窗口
+const limitWindow = time.Minute
+
+// ErrEndpointSaturated 发出请求时端点已达到并发或 RPM/TPM 上限（名额被其他请求抢先占用）
+var ErrEndpointSaturated = errors.New("端点容量已满")
+
+// EndpointUsage 端点当前的容量用量
+type EndpointUsage struct {
+	InFlight          int   // 在途请求数
+	RequestsPerMinute int   // 最近一分钟发出的请求数
+	TokensPerMinute   int64 // 最近一分钟消耗的 Token 数
+}
+
+// Saturated 用量是否已达到端点配置的任一上限（上限为 0 表示不限制），返回达到的上限说明
+func (u EndpointUsage) Saturated(cfg config.EndpointConfig) (bool, string) {
+	switch {
+	case cfg.MaxConcurrency > 0 && u.InFlight >= cfg.MaxConcurrency:
+		return true, fmt.Sprintf("并发 %d/%d", u.InFlight, cfg.MaxConcurrency)
+	case cfg.RPM > 0 && u.RequestsPerMinute >= cfg.RPM:
+		return true, fmt.Sprintf("RPM %d/%d", u.RequestsPerMinute, cfg.RPM)
+	case cfg.TPM > 0 && u.TokensPerMinute >= int64(cfg.TPM):
+		return true, fmt.Sprintf("TPM %d/%d", u.TokensPerMinute, cfg.TPM)
+	}
+	return false, ""
+}
+
+// tokenSample 一次请求消耗的 Token 数
+type tokenSample struct {
+	at     time.Time
+	tokens int64
+}
+
+// endpointUsage 单个端点的用量记录
+type endpointUsage struct {
+	inFlight int
+	requests []time.Time   // 最近一分钟的请求时间（按时间顺序）
+	tokens   []tokenSample // 最近一分钟的 Token 消耗（按时间顺序）
+}
+
+// prune 丢弃统计窗口之外的记录
+func (u *endpointUsage) prune(now time.Time) {
+	cutoff := now.Add(-limitWindow)
+	i := 0
+	for i < len(u.requests) && !u.requests[i].After(cutoff) {
+		i++
+	}
+	u.requests = u.requests[i:]
+
+	j := 0
+	for j < len(u.tokens) && !u.tokens[j].at.After(cutoff) {
+		j++
+	}
+	u.tokens = u.tokens[j:]
+}
+
+// snapshot 汇总当前用量
+func (u *endpointUsage) snapshot() EndpointUsage {
+	usage := EndpointUsage{InFlight: u.inFlight, RequestsPerMinute: len(u.requests)}
+	for _, s := range u.tokens {
+		usage.TokensPerMinute += s.tokens
+	}
+	return usage
+}
+
+// EndpointLimiter 统计各端点的容量用量
+type EndpointLimiter struct {
+	usage   map[string]*endpointUsage // endpoint name -> usage
+	waiters []chan struct{}           // 等待容量释放的订阅者（按排队顺序）
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// NewEndpointLimiter 创建端点容量统计
+func NewEndpointLimiter() *EndpointLimiter {
+	return &EndpointLimiter{
+		usage: make(map[string]*endpointUsage),
+		now:   time.Now,
+	}
+}
+
+// get 获取端点的用量记录（调用方持有锁）
+func (l *EndpointLimiter) get(name string) *endpointUsage {
+	u, ok := l.usage[name]
+	if !ok {
+		u = &endpointUsage{}
+		l.usage[name] = u
+	}
+	return u
+}
+
+// TryAcquire 请求发往端点前调用：未达到 cfg 的任一上限时在途请求数加一并计入 RPM
+// 检查和占用在同一把锁内完成，并发请求不会超过上限；返回 false 表示端点已满，未占用名额
+func (l *EndpointLimiter) TryAcquire(name string, cfg config.EndpointConfig) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	u := l.get(name)
+	u.prune(now)
+	if saturated, _ := u.snapshot().Saturated(cfg); saturated {
+		return false
+	}
+	u.inFlight++
+	u.requests = append(u.requests, now)
+	return true
+}
+
+// Release 请求结束（响应体关闭或请求失败）时调用，并通知最早排队的一个请求
+func (l *EndpointLimiter) Release(name string) {
+	l.mu.Lock()
+	if u, ok := l.usage[name]; ok && u.inFlight > 0 {
+		u.inFlight--
+	}
+	l.notifyLocked()
+	l.mu.Unlock()
+}
+
+// RecordTokens 记录端点一次请求消耗的 Token 数
+func (l *EndpointLimiter) RecordTokens(name string, tokens int64) {
+	if tokens <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	u := l.get(name)
+	u.prune(now)
+	u.tokens = append(u.tokens, tokenSample{at: now, tokens: tokens})
+}
+
+// Usage 获取端点当前用量
+func (l *EndpointLimiter) Usage(name string) EndpointUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	u, ok := l.usage[name]
+	if !ok {
+		return EndpointUsage{}
+	}
+	u.prune(l.now())
+	return u.snapshot()
+}
+
+// Subscribe 订阅容量释放通知，订阅者排在队尾
+// 每次归还名额只通知一个尚未收到通知的订阅者（先到先得），订阅者还需按时间重新检查 RPM/TPM 窗口
+func (l *EndpointLimiter) Subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	l.mu.Lock()
+	l.waiters = append(l.waiters, ch)
+	l.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe 取消容量释放通知订阅，未处理的通知转交给后面的订阅者
+func (l *EndpointLimiter) Unsubscribe(ch chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, w := range l.waiters {
+		if w != ch {
+			continue
+		}
+		l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+		select {
+		case <-ch:
+			l.wakeFromLocked(i)
+		default:
+		}
+		return
+	}
+}
+
+// Pass 订阅者收到通知但无法使用释放的容量（例如不支持请求的模型）时调用，把通知转交给后面的订阅者
+func (l *EndpointLimiter) Pass(ch chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, w := range l.waiters {
+		if w == ch {
+			l.wakeFromLocked(i + 1)
+			return
+		}
+	}
+}
+
+// notifyLocked 通知最早排队的一个订阅者（调用方持有锁）
+func (l *EndpointLimiter) notifyLocked() {
+	l.wakeFromLocked(0)
+}
+
+// wakeFromLocked 从 start 开始通知第一个尚未收到通知的订阅者（调用方持有锁）
+func (l *EndpointLimiter) wakeFromLocked(start int) {
+	for _, ch := range l.waiters[start:] {
+		select {
+		case ch <- struct{}{}:
+			return
+		default:
+		}
+	}
+}
+
+// Remove 删除端点的用量记录（端点被删除时），通知所有订阅者重新检查可用端点
+func (l *EndpointLimiter) Remove(name string) {
+	l.mu.Lock()
+	delete(l.usage, name)
+	for _, ch := range l.waiters {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	l.mu.Unlock()
+}
+
+// ============================================================
+// Manager 集成
+// ============================================================
+
+// hasCapacityLimits 端点是否配置了容量上限
+func hasCapacityLimits(ep *Endpoint) bool {
+	return ep.Config.MaxConcurrency > 0 || ep.Config.RPM > 0 || ep.Config.TPM > 0
+}
+
+// isSaturated 端点是否已达到配置的并发或 RPM/TPM 上限
+func (m *Manager) isSaturated(ep *Endpoint) bool {
+	if !hasCapacityLimits(ep) {
+		return false
+	}
+	saturated, reason := m.limiter.Usage(ep.Config.Name).Saturated(ep.Config)
+	if saturated {
+		slog.Debug(fmt.Sprintf("⏭️ [端点选择] 跳过容量已满的端点: %s (%s)", ep.Config.Name, reason))
+	}
+	return saturated
+}
+
+// TryAcquireCapacity 请求发往端点前调用，端点未满时占用在途名额并计入 RPM
+// 返回 false 表示端点已被其他请求占满，调用方应跳过该端点或排队等待，不能直接发出请求
+func (m *Manager) TryAcquireCapacity(ep *Endpoint) bool {
+	return m.limiter.TryAcquire(ep.Config.Name, ep.Config)
+}
+
+// ReleaseCapacity 请求结束时调用，归还在途名额
+func (m *Manager) ReleaseCapacity(ep *Endpoint) {
+	m.limiter.Release(ep.Config.Name)
+}
+
+// RecordEndpointTokens 记录端点消耗的 Token 数（计入 TPM）
+func (m *Manager) RecordEndpointTokens(name string, tokens int64) {
+	m.limiter.RecordTokens(name, tokens)
+}
+
+// GetEndpointUsage 获取端点当前的并发和 RPM/TPM 用量
+func (m *Manager) GetEndpointUsage(name string) EndpointUsage {
+	return m.limiter.Usage(name)
+}
+
+// SubscribeCapacity 订阅端点容量释放通知
+func (m *Manager) SubscribeCapacity() chan struct{} {
+	return m.limiter.Subscribe()
+}
+
+// UnsubscribeCapacity 取消端点容量释放通知订阅
+func (m *Manager) UnsubscribeCapacity(ch chan struct{}) {
+	m.limiter.Unsubscribe(ch)
+}
+
+// PassCapacity 收到容量释放通知但无法使用时，把通知转交给下一个排队的请求
+func (m *Manager) PassCapacity(ch chan struct{}) {
+	m.limiter.Pass(ch)
+}
+
+// HasSaturatedEndpoints 请求可用的端点中是否有仅因容量已满而被跳过的端点
+// 用于在没有可用端点时判断是否值得排队等待容量释放
+func (m *Manager) HasSaturatedEndpoints(ctx context.Context) bool {
+	m.endpointsMu.RLock()
+	snapshot := make([]*Endpoint, len(m.endpoints))
+	copy(snapshot, m.endpoints)
+	m.endpointsMu.RUnlock()
+
+	now := time.Now()
+	var saturated []*Endpoint
+	for _, ep := range snapshot {
+		if !hasCapacityLimits(ep) {
+			continue
+		}
+
+		ep.mutex.RLock()
+		isHealthy := ep.Status.Healthy
+		inCooldown := !ep.Status.CooldownUntil.IsZero() && now.Before(ep.Status.CooldownUntil)
+		ep.mutex.RUnlock()
+
+		if isHealthy && !inCooldown && m.isSaturated(ep) {
+			saturated = append(saturated, ep)
+		}
+	}
+	return len(m.FilterEndpointsForModel(ctx, saturated)) > 0
+}
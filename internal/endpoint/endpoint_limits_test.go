@@ -0,0 +1,200 @@
+package endpoint
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+func TestEndpointLimiter_SlidingWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewEndpointLimiter()
+	limiter.now = func() time.Time { return now }
+	cfg := config.EndpointConfig{MaxConcurrency: 2, RPM: 3, TPM: 1000}
+
+	if !limiter.TryAcquire("ep", cfg) || !limiter.TryAcquire("ep", cfg) {
+		t.Fatal("未达到上限时应占用名额")
+	}
+	if saturated, reason := limiter.Usage("ep").Saturated(cfg); !saturated {
+		t.Fatal("并发达到上限时应视为已满")
+	} else if reason != "并发 2/2" {
+		t.Errorf("上限说明不符: %s", reason)
+	}
+	if limiter.TryAcquire("ep", cfg) {
+		t.Fatal("并发已满时不应占用名额")
+	}
+
+	limiter.Release("ep")
+	if !limiter.TryAcquire("ep", cfg) {
+		t.Fatal("归还后应能再次占用名额")
+	}
+	usage := limiter.Usage("ep")
+	if usage.InFlight != 2 || usage.RequestsPerMinute != 3 {
+		t.Fatalf("用量不符: %+v", usage)
+	}
+	limiter.Release("ep")
+	if saturated, _ := limiter.Usage("ep").Saturated(cfg); !saturated {
+		t.Error("RPM 达到上限时应视为已满")
+	}
+	if limiter.TryAcquire("ep", cfg) {
+		t.Error("RPM 已满时不应占用名额")
+	}
+
+	// 一分钟后请求数滑出窗口
+	now = now.Add(61 * time.Second)
+	limiter.RecordTokens("ep", 600)
+	limiter.RecordTokens("ep", 400)
+	usage = limiter.Usage("ep")
+	if usage.RequestsPerMinute != 0 || usage.TokensPerMinute != 1000 {
+		t.Fatalf("窗口滑动后的用量不符: %+v", usage)
+	}
+	if saturated, reason := usage.Saturated(cfg); !saturated || reason != "TPM 1000/1000" {
+		t.Errorf("TPM 达到上限时应视为已满: %v %s", saturated, reason)
+	}
+	if saturated, _ := usage.Saturated(config.EndpointConfig{}); saturated {
+		t.Error("未配置上限时不应视为已满")
+	}
+}
+
+func TestEndpointLimiter_TryAcquireConcurrentPeak(t *testing.T) {
+	limiter := NewEndpointLimiter()
+	cfg := config.EndpointConfig{MaxConcurrency: 3}
+
+	var inFlight, peak int32
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if !limiter.TryAcquire("ep", cfg) {
+					continue
+				}
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Microsecond)
+				atomic.AddInt32(&inFlight, -1)
+				limiter.Release("ep")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > int32(cfg.MaxConcurrency) {
+		t.Errorf("在途请求峰值 %d 超过并发上限 %d", peak, cfg.MaxConcurrency)
+	}
+	if usage := limiter.Usage("ep"); usage.InFlight != 0 {
+		t.Errorf("在途请求数不符: %d", usage.InFlight)
+	}
+}
+
+func TestEndpointLimiter_ReleaseNotifiesOneSubscriber(t *testing.T) {
+	limiter := NewEndpointLimiter()
+	cfg := config.EndpointConfig{MaxConcurrency: 1}
+	first := limiter.Subscribe()
+	second := limiter.Subscribe()
+	defer limiter.Unsubscribe(second)
+
+	limiter.TryAcquire("ep", cfg)
+	limiter.Release("ep")
+	limiter.Release("ep") // 多余的归还不会让计数变为负数
+	if usage := limiter.Usage("ep"); usage.InFlight != 0 {
+		t.Errorf("在途请求数不符: %d", usage.InFlight)
+	}
+
+	// 两次归还依次通知两个订阅者，每个订阅者只收到一次
+	select {
+	case <-first:
+	default:
+		t.Fatal("归还名额时应通知最早排队的订阅者")
+	}
+	select {
+	case <-second:
+	default:
+		t.Fatal("第二次归还应通知下一个订阅者")
+	}
+
+	// 只归还一次时后面的订阅者不会被唤醒
+	limiter.Release("ep")
+	select {
+	case <-second:
+		t.Fatal("一次归还只应通知一个订阅者")
+	default:
+	}
+
+	// 收到通知后放弃排队，通知转交给下一个订阅者
+	third := limiter.Subscribe()
+	defer limiter.Unsubscribe(third)
+	limiter.Unsubscribe(first)
+	select {
+	case <-second:
+	default:
+		t.Fatal("first 已取消订阅，应通知 second")
+	}
+	limiter.Pass(second)
+	select {
+	case <-third:
+	default:
+		t.Fatal("转交的通知应送达下一个订阅者")
+	}
+}
+
+func TestManager_SaturatedEndpointsSkipped(t *testing.T) {
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: StrategyWeighted},
+		Failover: config.FailoverConfig{Enabled: true},
+		Endpoints: []config.EndpointConfig{
+			{Name: "primary", URL: "https://primary.example.com", Priority: 1, MaxConcurrency: 1},
+			{Name: "secondary", URL: "https://secondary.example.com", Priority: 1, RPM: 1},
+		},
+	}
+	m := NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("primary"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+
+	ctx := context.Background()
+	if m.HasSaturatedEndpoints(ctx) {
+		t.Fatal("没有请求时不应有已满的端点")
+	}
+
+	primary := m.GetEndpointByNameAny("primary")
+	if !m.TryAcquireCapacity(primary) {
+		t.Fatal("空闲端点应能占用名额")
+	}
+	if m.TryAcquireCapacity(primary) {
+		t.Fatal("并发已满的端点不应再占用名额")
+	}
+	if names := endpointNames(m.GetEndpointsForRequest(ctx)); len(names) != 1 || names[0] != "secondary" {
+		t.Fatalf("并发已满的端点应被跳过: %v", names)
+	}
+
+	m.TryAcquireCapacity(m.GetEndpointByNameAny("secondary"))
+	if len(m.GetEndpointsForRequest(ctx)) != 0 {
+		t.Fatal("所有端点已满时不应有可用端点")
+	}
+	if !m.HasSaturatedEndpoints(ctx) {
+		t.Error("应报告存在已满的端点")
+	}
+
+	m.ReleaseCapacity(primary)
+	if names := endpointNames(m.GetEndpointsForRequest(ctx)); len(names) != 1 || names[0] != "primary" {
+		t.Errorf("归还名额后端点应恢复可用: %v", names)
+	}
+	if usage := m.GetEndpointUsage("secondary"); usage.InFlight != 1 || usage.RequestsPerMinute != 1 {
+		t.Errorf("用量不符: %+v", usage)
+	}
+}
@@ -11,6 +11,35 @@ import (
 	"time"
 )
 
+// EndpointGate 端点准入检查接口
+// 返回 false 的端点在选择时跳过（与冷却中的端点处理方式相同，可触发故障转移）
+type EndpointGate interface {
+	AllowEndpoint(name, channel string) bool
+}
+
+// SetEndpointGate 设置端点准入检查（需在 Start 之前调用）
+func (m *Manager) SetEndpointGate(gate EndpointGate) {
+	m.endpointGate = gate
+}
+
+// isGated 检查端点是否被准入检查、熔断器或容量上限拦截
+func (m *Manager) isGated(ep *Endpoint) bool {
+	if m.isCircuitOpen(ep) {
+		return true
+	}
+	if m.isSaturated(ep) {
+		return true
+	}
+	if m.endpointGate == nil {
+		return false
+	}
+	if m.endpointGate.AllowEndpoint(ep.Config.Name, ep.Config.Channel) {
+		return false
+	}
+	slog.Debug(fmt.Sprintf("⏭️ [端点选择] 跳过未通过准入检查的端点: %s", ep.Config.Name))
+	return true
+}
+
 // GetHealthyEndpoints returns a list of healthy endpoints from active groups based on strategy
 // v5.0 Desktop: 支持故障转移 - 活跃端点不健康时，返回其他 failover_enabled=true 的健康端点
 func (m *Manager) GetHealthyEndpoints() []*Endpoint {
@@ -33,12 +62,19 @@ func (m *Manager) GetHealthyEndpoints() []*Endpoint {
 		endpoint.mutex.RUnlock()
 
 		if isHealthy && !inCooldown {
-			healthy = append(healthy, endpoint)
+			if !m.isGated(endpoint) {
+				healthy = append(healthy, endpoint)
+			}
 		} else if inCooldown {
 			slog.Debug(fmt.Sprintf("⏭️ [端点选择] 跳过冷却中的端点: %s", endpoint.Config.Name))
 		}
 	}
 
+	// 负载均衡策略：启用故障转移时，参与故障转移的健康端点与活跃端点共同分担流量
+	if m.config.Failover.Enabled && IsBalancingStrategy(m.config.Strategy.Type) {
+		healthy = append(healthy, m.getFailoverEndpoints(activeEndpoints, snapshot)...)
+	}
+
 	// 2. 如果活跃端点健康且不在冷却中，直接返回
 	if len(healthy) > 0 {
 		return m.sortHealthyEndpoints(healthy, true)
@@ -96,7 +132,7 @@ func (m *Manager) getFailoverEndpoints(activeEndpoints, snapshot []*Endpoint) []
 			continue
 		}
 
-		if isHealthy {
+		if isHealthy && !m.isGated(endpoint) {
 			failoverEndpoints = append(failoverEndpoints, endpoint)
 		}
 	}
@@ -132,9 +168,11 @@ func (m *Manager) sortHealthyEndpoints(healthy []*Endpoint, showLogs bool) []*En
 			defer healthy[j].mutex.RUnlock()
 			return healthy[i].Status.ResponseTime < healthy[j].Status.ResponseTime
 		})
+	case StrategyWeighted, StrategyLeastOutstanding, StrategyEWMA:
+		m.sortBalanced(m.config.Strategy.Type, healthy, showLogs)
 	}
 
-	return healthy
+	return m.deprioritizeNearLimit(healthy)
 }
 
 // GetFastestEndpointsWithRealTimeTest returns endpoints from active groups sorted by real-time testing
@@ -152,10 +190,17 @@ func (m *Manager) GetFastestEndpointsWithRealTimeTest(ctx context.Context) []*En
 	var healthy []*Endpoint
 	for _, endpoint := range activeEndpoints {
 		endpoint.mutex.RLock()
-		if endpoint.Status.Healthy {
+		isHealthy := endpoint.Status.Healthy
+		endpoint.mutex.RUnlock()
+
+		if isHealthy && !m.isGated(endpoint) {
 			healthy = append(healthy, endpoint)
 		}
-		endpoint.mutex.RUnlock()
+	}
+
+	// 负载均衡策略：启用故障转移时，参与故障转移的健康端点与活跃端点共同分担流量
+	if m.config.Failover.Enabled && IsBalancingStrategy(m.config.Strategy.Type) {
+		healthy = append(healthy, m.getFailoverEndpoints(activeEndpoints, snapshot)...)
 	}
 
 	// 2. 如果活跃端点不健康，尝试故障转移
@@ -233,6 +278,7 @@ func (m *Manager) GetFastestEndpointsWithRealTimeTest(ctx context.Context) []*En
 	for _, result := range sortedResults {
 		endpoints = append(endpoints, result.Endpoint)
 	}
+	endpoints = m.deprioritizeNearLimit(endpoints)
 
 	// Log the successful endpoint ranking
 	if len(endpoints) > 0 {
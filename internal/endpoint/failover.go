@@ -43,6 +43,10 @@ func (m *Manager) TriggerRequestFailover(failedEndpointName string, reason strin
 	if failedEndpoint.Config.Cooldown != nil && *failedEndpoint.Config.Cooldown > 0 {
 		cooldownDuration = *failedEndpoint.Config.Cooldown
 	}
+	// 上游返回了限流解除时间时，按上游要求冷却（多 Key 轮换端点已冷却对应的 Key，与 RecordRateLimit 一致）
+	if retryAfter := m.RateLimitRetryAfter(failedEndpointName); retryAfter > 0 && !m.rotatesKeys(failedEndpoint) {
+		cooldownDuration = retryAfter
+	}
 
 	// 设置冷却状态
 	failedEndpoint.mutex.Lock()
@@ -51,6 +55,7 @@ func (m *Manager) TriggerRequestFailover(failedEndpointName string, reason strin
 	failedEndpoint.mutex.Unlock()
 
 	slog.Info(fmt.Sprintf("⏱️ [故障转移] 端点 %s 进入冷却，持续 %v", failedEndpointName, cooldownDuration))
+	m.notifyGroupCooldown(failedEndpointName, time.Now().Add(cooldownDuration), reason)
 
 	// 2. 停用失败端点的组
 	if err := m.groupManager.DeactivateGroup(failedEndpointName); err != nil {
@@ -86,7 +91,7 @@ func (m *Manager) TriggerRequestFailover(failedEndpointName string, reason strin
 }
 
 // selectNextFailoverEndpoint 选择下一个故障转移端点
-// 按优先级选择 failover_enabled=true 且健康且不在冷却中的端点
+// 按优先级选择 failover_enabled=true 且健康、不在冷却中且未被熔断的端点
 func (m *Manager) selectNextFailoverEndpoint(excludeEndpoint string) string {
 	m.endpointsMu.RLock()
 	snapshot := make([]*Endpoint, len(m.endpoints))
@@ -130,6 +135,10 @@ func (m *Manager) selectNextFailoverEndpoint(excludeEndpoint string) string {
 			continue
 		}
 
+		if m.isCircuitOpen(ep) {
+			continue
+		}
+
 		return ep.Config.Name
 	}
 
@@ -149,13 +158,15 @@ func (m *Manager) IsEndpointInCooldown(name string) bool {
 	return !ep.Status.CooldownUntil.IsZero() && time.Now().Before(ep.Status.CooldownUntil)
 }
 
-// ClearEndpointCooldown 清除端点冷却状态（用于手动激活时）
+// ClearEndpointCooldown 清除端点冷却状态和熔断状态（用于手动激活时）
 func (m *Manager) ClearEndpointCooldown(name string) {
 	ep := m.GetEndpointByNameAny(name)
 	if ep == nil {
 		return
 	}
 
+	m.ResetCircuit(name)
+
 	ep.mutex.Lock()
 	defer ep.mutex.Unlock()
 
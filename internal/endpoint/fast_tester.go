@@ -114,6 +114,20 @@ func (ft *FastTester) TestEndpointsParallel(ctx context.Context, endpoints []*En
 	return results, false
 }
 
+// clientFor 返回测试端点使用的 HTTP 客户端（遵循端点级代理覆盖）
+func (ft *FastTester) clientFor(endpoint *Endpoint) *http.Client {
+	if ft.manager != nil {
+		return ft.manager.probeClient(endpoint, ft.config.Strategy.FastTestTimeout)
+	}
+	if endpoint.Config.Proxy != nil {
+		if httpTransport, err := transport.CreateEndpointTransport(ft.config, &endpoint.Config); err == nil {
+			httpTransport.DisableKeepAlives = true // 未接入连接池，避免遗留空闲连接
+			return &http.Client{Timeout: ft.config.Strategy.FastTestTimeout, Transport: httpTransport}
+		}
+	}
+	return ft.client
+}
+
 // testSingleEndpoint tests a single endpoint
 func (ft *FastTester) testSingleEndpoint(ctx context.Context, endpoint *Endpoint) *FastTestResult {
 	start := time.Now()
@@ -150,7 +164,7 @@ func (ft *FastTester) testSingleEndpoint(ctx context.Context, endpoint *Endpoint
 		req.Header.Set(key, value)
 	}
 
-	resp, err := ft.client.Do(req)
+	resp, err := ft.clientFor(endpoint).Do(req)
 	responseTime := time.Since(start)
 
 	if err != nil {
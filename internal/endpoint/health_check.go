@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"cc-forwarder/internal/transport"
 	"cc-forwarder/internal/utils"
 )
 
@@ -106,6 +107,16 @@ func (m *Manager) performHealthChecks() {
 
 		slog.Debug(fmt.Sprintf("🩺 [健康检查] SQLite 模式：检查所有 %d 个端点（包括未激活）",
 			len(endpointsToCheck)))
+	} else if IsBalancingStrategy(m.config.Strategy.Type) || m.HasModelRoutes() {
+		// 负载均衡策略/模型路由：非活跃端点也可能接收请求，需要检查所有端点
+		endpointsToCheck = snapshot
+
+		if len(endpointsToCheck) == 0 {
+			slog.Debug("🩺 [健康检查] 没有配置的端点，跳过健康检查")
+			return
+		}
+
+		slog.Debug(fmt.Sprintf("🩺 [健康检查] 负载均衡/模型路由：检查所有 %d 个端点", len(endpointsToCheck)))
 	} else if m.config.Group.AutoSwitchBetweenGroups {
 		// v4.0 Auto mode: only check active group endpoints
 		endpointsToCheck = m.groupManager.FilterEndpointsByActiveGroups(snapshot)
@@ -163,6 +174,20 @@ func (m *Manager) performHealthChecks() {
 	}
 }
 
+// probeClient 返回探测请求使用的 HTTP 客户端
+// 复用连接池中的端点 Transport（遵循端点级代理），失败时回退到全局客户端
+func (m *Manager) probeClient(endpoint *Endpoint, timeout time.Duration) *http.Client {
+	if m.transportPool != nil {
+		rt, err := m.transportPool.Get(&endpoint.Config, transport.ProfileProbe)
+		if err == nil {
+			return &http.Client{Timeout: timeout, Transport: rt}
+		}
+		slog.Warn(fmt.Sprintf("⚠️ [健康检查] 创建端点 Transport 失败，使用全局代理配置: %s - %v",
+			endpoint.Config.Name, err))
+	}
+	return m.client
+}
+
 // checkEndpointHealth checks the health of a single endpoint
 func (m *Manager) checkEndpointHealth(endpoint *Endpoint) {
 	start := time.Now()
@@ -180,7 +205,7 @@ func (m *Manager) checkEndpointHealth(endpoint *Endpoint) {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	resp, err := m.client.Do(req)
+	resp, err := m.probeClient(endpoint, m.config.Health.Timeout).Do(req)
 	responseTime := time.Since(start)
 
 	if err != nil {
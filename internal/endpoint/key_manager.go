@@ -6,31 +6,104 @@ import (
 	"time"
 )
 
+// Key 类型
+const (
+	KeyTypeToken  = "token"
+	KeyTypeApiKey = "api_key"
+)
+
+// Key 分配策略（自动轮换启用时生效）
+const (
+	KeyStrategyFailover   = "failover"    // 固定使用当前 Key，不可用时切换到下一个可用 Key
+	KeyStrategyRoundRobin = "round_robin" // 按请求轮询可用 Key
+	KeyStrategyLeastUsed  = "least_used"  // 选择使用次数最少的可用 Key
+)
+
+// defaultKeyCooldown 限流 Key 的默认冷却时间
+const defaultKeyCooldown = 60 * time.Second
+
 // KeyManager 管理所有端点的 API Key 状态
 // 支持每个端点独立管理多个 Token 和 API Key，通过索引切换当前使用的 Key
+// 启用自动轮换后，按策略为每个请求分配 Key，并根据上游响应冷却或禁用 Key
 type KeyManager struct {
 	states map[string]*EndpointKeyState // endpoint name -> state
 	mu     sync.RWMutex
+
+	// 自动轮换配置
+	autoRotate bool
+	strategy   string
+	cooldown   time.Duration
+}
+
+// KeyStatus 单个 Key 的运行状态
+type KeyStatus struct {
+	CooldownUntil  time.Time // 限流/额度耗尽冷却截止时间
+	Invalid        bool      // 认证失败（401/403），手动切换到该 Key 前不再自动使用
+	LastStatusCode int       // 最近一次错误的上游状态码
+	LastError      string    // 最近一次错误说明
+	UsageCount     int64     // 累计分配的请求数
+	LastUsedAt     time.Time // 最近一次分配时间
+}
+
+// Available 是否可被自动选择
+func (s KeyStatus) Available(now time.Time) bool {
+	return !s.Invalid && !now.Before(s.CooldownUntil)
 }
 
 // EndpointKeyState 端点的 Key 状态
 type EndpointKeyState struct {
-	EndpointName      string    // 端点名称
-	ActiveTokenIndex  int       // 当前激活的 Token 索引
-	ActiveApiKeyIndex int       // 当前激活的 API Key 索引
-	TokenCount        int       // Token 总数
-	ApiKeyCount       int       // API Key 总数
-	LastSwitchTime    time.Time // 最后切换时间
+	EndpointName      string      // 端点名称
+	ActiveTokenIndex  int         // 当前激活的 Token 索引
+	ActiveApiKeyIndex int         // 当前激活的 API Key 索引
+	TokenCount        int         // Token 总数
+	ApiKeyCount       int         // API Key 总数
+	LastSwitchTime    time.Time   // 最后切换时间
+	TokenStatus       []KeyStatus // 各 Token 的运行状态（与配置顺序一致）
+	ApiKeyStatus      []KeyStatus // 各 API Key 的运行状态
+	tokenCursor       int         // 轮询游标
+	apiKeyCursor      int
 	mu                sync.RWMutex
 }
 
 // NewKeyManager 创建新的 Key 管理器
 func NewKeyManager() *KeyManager {
 	return &KeyManager{
-		states: make(map[string]*EndpointKeyState),
+		states:   make(map[string]*EndpointKeyState),
+		strategy: KeyStrategyFailover,
+		cooldown: defaultKeyCooldown,
 	}
 }
 
+// Configure 更新自动轮换配置
+func (km *KeyManager) Configure(autoRotate bool, strategy string, cooldown time.Duration) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if strategy == "" {
+		strategy = KeyStrategyFailover
+	}
+	if cooldown <= 0 {
+		cooldown = defaultKeyCooldown
+	}
+	km.autoRotate = autoRotate
+	km.strategy = strategy
+	km.cooldown = cooldown
+}
+
+// IsAutoRotate 是否启用自动轮换
+func (km *KeyManager) IsAutoRotate() bool {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.autoRotate
+}
+
+// GetStrategy 获取 Key 分配策略
+func (km *KeyManager) GetStrategy() string {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.strategy
+}
+
 // InitEndpoint 初始化端点的 Key 状态
 func (km *KeyManager) InitEndpoint(endpointName string, tokenCount, apiKeyCount int) {
 	km.mu.Lock()
@@ -42,6 +115,8 @@ func (km *KeyManager) InitEndpoint(endpointName string, tokenCount, apiKeyCount
 		ActiveApiKeyIndex: 0,
 		TokenCount:        tokenCount,
 		ApiKeyCount:       apiKeyCount,
+		TokenStatus:       make([]KeyStatus, tokenCount),
+		ApiKeyStatus:      make([]KeyStatus, apiKeyCount),
 	}
 }
 
@@ -90,6 +165,10 @@ func (km *KeyManager) SwitchToken(endpointName string, index int) error {
 
 	state.ActiveTokenIndex = index
 	state.LastSwitchTime = time.Now()
+	// 手动切换视为确认该 Key 可用，清除冷却和无效标记
+	if index < len(state.TokenStatus) {
+		state.TokenStatus[index].clearFailure()
+	}
 	return nil
 }
 
@@ -112,6 +191,10 @@ func (km *KeyManager) SwitchApiKey(endpointName string, index int) error {
 
 	state.ActiveApiKeyIndex = index
 	state.LastSwitchTime = time.Now()
+	// 手动切换视为确认该 Key 可用，清除冷却和无效标记
+	if index < len(state.ApiKeyStatus) {
+		state.ApiKeyStatus[index].clearFailure()
+	}
 	return nil
 }
 
@@ -124,14 +207,7 @@ func (km *KeyManager) GetEndpointKeyState(endpointName string) *EndpointKeyState
 		state.mu.RLock()
 		defer state.mu.RUnlock()
 		// 返回副本
-		return &EndpointKeyState{
-			EndpointName:      state.EndpointName,
-			ActiveTokenIndex:  state.ActiveTokenIndex,
-			ActiveApiKeyIndex: state.ActiveApiKeyIndex,
-			TokenCount:        state.TokenCount,
-			ApiKeyCount:       state.ApiKeyCount,
-			LastSwitchTime:    state.LastSwitchTime,
-		}
+		return state.snapshot()
 	}
 	return nil
 }
@@ -144,14 +220,7 @@ func (km *KeyManager) GetAllStates() map[string]*EndpointKeyState {
 	result := make(map[string]*EndpointKeyState)
 	for name, state := range km.states {
 		state.mu.RLock()
-		result[name] = &EndpointKeyState{
-			EndpointName:      state.EndpointName,
-			ActiveTokenIndex:  state.ActiveTokenIndex,
-			ActiveApiKeyIndex: state.ActiveApiKeyIndex,
-			TokenCount:        state.TokenCount,
-			ApiKeyCount:       state.ApiKeyCount,
-			LastSwitchTime:    state.LastSwitchTime,
-		}
+		result[name] = state.snapshot()
 		state.mu.RUnlock()
 	}
 	return result
@@ -194,6 +263,8 @@ func (km *KeyManager) UpdateEndpointKeyCount(endpointName string, tokenCount, ap
 
 		state.TokenCount = tokenCount
 		state.ApiKeyCount = apiKeyCount
+		state.TokenStatus = resizeKeyStatus(state.TokenStatus, tokenCount)
+		state.ApiKeyStatus = resizeKeyStatus(state.ApiKeyStatus, apiKeyCount)
 
 		// 如果当前索引超出新范围，重置为 0
 		if state.ActiveTokenIndex >= tokenCount {
@@ -210,6 +281,8 @@ func (km *KeyManager) UpdateEndpointKeyCount(endpointName string, tokenCount, ap
 			ActiveApiKeyIndex: 0,
 			TokenCount:        tokenCount,
 			ApiKeyCount:       apiKeyCount,
+			TokenStatus:       make([]KeyStatus, tokenCount),
+			ApiKeyStatus:      make([]KeyStatus, apiKeyCount),
 		}
 	}
 }
@@ -220,3 +293,203 @@ func (km *KeyManager) RemoveEndpoint(endpointName string) {
 	defer km.mu.Unlock()
 	delete(km.states, endpointName)
 }
+
+// ============================================================
+// 自动轮换
+// ============================================================
+
+// SelectKey 为新请求分配 Key 索引并计入使用次数
+// 未启用自动轮换时始终返回当前激活的索引（与手动切换行为一致）
+// 所有 Key 都不可用时保持使用当前激活的 Key
+func (km *KeyManager) SelectKey(endpointName, keyType string) int {
+	km.mu.RLock()
+	state, exists := km.states[endpointName]
+	autoRotate, strategy := km.autoRotate, km.strategy
+	km.mu.RUnlock()
+
+	if !exists {
+		return 0
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	active, statuses, cursor := state.keys(keyType)
+	if len(statuses) == 0 {
+		return *active
+	}
+
+	now := time.Now()
+	index := *active
+	if autoRotate {
+		switch strategy {
+		case KeyStrategyRoundRobin:
+			if next := nextAvailableKey(statuses, *cursor, now); next >= 0 {
+				index = next
+				*cursor = (next + 1) % len(statuses)
+			}
+		case KeyStrategyLeastUsed:
+			if least := leastUsedKey(statuses, now); least >= 0 {
+				index = least
+			}
+		default:
+			// 当前 Key 冷却中或无效时（例如重启后恢复的状态）切换到下一个可用 Key
+			if index >= len(statuses) || !statuses[index].Available(now) {
+				if next := nextAvailableKey(statuses, index+1, now); next >= 0 {
+					index = next
+					*active = next
+					state.LastSwitchTime = now
+				}
+			}
+		}
+	}
+
+	if index < 0 || index >= len(statuses) {
+		index = 0
+	}
+	statuses[index].UsageCount++
+	statuses[index].LastUsedAt = now
+	return index
+}
+
+// ReportResult 记录 Key 的上游响应结果
+// 429/402 使 Key 进入冷却（优先使用 Retry-After），401/403 将 Key 标记为无效；
+// 出错的是当前激活的 Key 时切换到下一个可用 Key
+// 返回状态是否变化，以及切换后的索引（未切换时为 -1）
+func (km *KeyManager) ReportResult(endpointName, keyType string, index, statusCode int, retryAfter time.Duration) (bool, int) {
+	km.mu.RLock()
+	state, exists := km.states[endpointName]
+	autoRotate, cooldown := km.autoRotate, km.cooldown
+	km.mu.RUnlock()
+
+	if !exists || !autoRotate {
+		return false, -1
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	active, statuses, _ := state.keys(keyType)
+	if index < 0 || index >= len(statuses) {
+		return false, -1
+	}
+
+	now := time.Now()
+	status := &statuses[index]
+
+	switch statusCode {
+	case 429, 402:
+		if retryAfter <= 0 {
+			retryAfter = cooldown
+		}
+		status.CooldownUntil = now.Add(retryAfter)
+		status.LastStatusCode = statusCode
+		status.LastError = fmt.Sprintf("HTTP %d: 限流或额度耗尽，冷却至 %s", statusCode, status.CooldownUntil.Format("15:04:05"))
+	case 401, 403:
+		status.Invalid = true
+		status.LastStatusCode = statusCode
+		status.LastError = fmt.Sprintf("HTTP %d: 认证失败，Key 已标记为无效", statusCode)
+	default:
+		return false, -1
+	}
+
+	switchedTo := -1
+	if index == *active && len(statuses) > 1 {
+		if next := nextAvailableKey(statuses, index+1, now); next >= 0 && next != index {
+			*active = next
+			state.LastSwitchTime = now
+			switchedTo = next
+		}
+	}
+
+	return true, switchedTo
+}
+
+// RestoreKeyStatus 恢复持久化的 Key 状态（用于启动时加载）
+func (km *KeyManager) RestoreKeyStatus(endpointName, keyType string, index int, status KeyStatus, active bool) {
+	km.mu.RLock()
+	state, exists := km.states[endpointName]
+	km.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	activeIndex, statuses, _ := state.keys(keyType)
+	if index < 0 || index >= len(statuses) {
+		return
+	}
+	statuses[index] = status
+	if active {
+		*activeIndex = index
+	}
+}
+
+// keys 返回指定类型的激活索引、状态列表和轮询游标（调用方需持有 state.mu）
+func (s *EndpointKeyState) keys(keyType string) (*int, []KeyStatus, *int) {
+	if keyType == KeyTypeApiKey {
+		return &s.ActiveApiKeyIndex, s.ApiKeyStatus, &s.apiKeyCursor
+	}
+	return &s.ActiveTokenIndex, s.TokenStatus, &s.tokenCursor
+}
+
+// snapshot 复制状态（调用方需持有 state.mu 读锁）
+func (s *EndpointKeyState) snapshot() *EndpointKeyState {
+	return &EndpointKeyState{
+		EndpointName:      s.EndpointName,
+		ActiveTokenIndex:  s.ActiveTokenIndex,
+		ActiveApiKeyIndex: s.ActiveApiKeyIndex,
+		TokenCount:        s.TokenCount,
+		ApiKeyCount:       s.ApiKeyCount,
+		LastSwitchTime:    s.LastSwitchTime,
+		TokenStatus:       append([]KeyStatus(nil), s.TokenStatus...),
+		ApiKeyStatus:      append([]KeyStatus(nil), s.ApiKeyStatus...),
+	}
+}
+
+// clearFailure 清除冷却和无效标记
+func (s *KeyStatus) clearFailure() {
+	s.CooldownUntil = time.Time{}
+	s.Invalid = false
+	s.LastStatusCode = 0
+	s.LastError = ""
+}
+
+// nextAvailableKey 从 start 开始循环查找第一个可用 Key，全部不可用时返回 -1
+func nextAvailableKey(statuses []KeyStatus, start int, now time.Time) int {
+	n := len(statuses)
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		if statuses[idx].Available(now) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// leastUsedKey 返回使用次数最少的可用 Key，全部不可用时返回 -1
+func leastUsedKey(statuses []KeyStatus, now time.Time) int {
+	best := -1
+	for i, st := range statuses {
+		if !st.Available(now) {
+			continue
+		}
+		if best < 0 || st.UsageCount < statuses[best].UsageCount {
+			best = i
+		}
+	}
+	return best
+}
+
+// resizeKeyStatus 调整状态列表长度（保留已有 Key 的状态）
+func resizeKeyStatus(statuses []KeyStatus, count int) []KeyStatus {
+	if len(statuses) == count {
+		return statuses
+	}
+	resized := make([]KeyStatus, count)
+	copy(resized, statuses)
+	return resized
+}
@@ -0,0 +1,217 @@
+// key_rotation.go - 多 Key 自动轮换
+// 按策略为请求分配 Key，根据上游 429/401 等响应冷却或禁用 Key，并支持状态持久化
+
+package endpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cc-forwarder/internal/events"
+)
+
+// KeyStateSnapshot Key 状态快照（用于持久化）
+// 按 Key 值指纹匹配，配置中 Key 顺序变化后仍能正确恢复
+type KeyStateSnapshot struct {
+	EndpointName string
+	KeyType      string // token/api_key
+	KeyHash      string // Key 值指纹
+	Active       bool   // 是否为当前激活的 Key
+	Status       KeyStatus
+}
+
+// SetOnKeyStateChanged 设置 Key 状态变化回调（冷却、失效、自动切换时触发）
+func (m *Manager) SetOnKeyStateChanged(fn func()) {
+	m.onKeyStateChanged = fn
+}
+
+// SelectTokenForRequest 为转发请求选择 Token
+// 多 Token 端点按轮换策略分配，其余情况与 GetTokenForEndpoint 相同
+func (m *Manager) SelectTokenForRequest(ep *Endpoint) string {
+	if len(ep.Config.Tokens) > 1 {
+		index := m.keyManager.SelectKey(ep.Config.Name, KeyTypeToken)
+		if index >= 0 && index < len(ep.Config.Tokens) {
+			return ep.Config.Tokens[index].Value
+		}
+	}
+	return m.GetTokenForEndpoint(ep)
+}
+
+// SelectApiKeyForRequest 为转发请求选择 API Key
+func (m *Manager) SelectApiKeyForRequest(ep *Endpoint) string {
+	if len(ep.Config.ApiKeys) > 1 {
+		index := m.keyManager.SelectKey(ep.Config.Name, KeyTypeApiKey)
+		if index >= 0 && index < len(ep.Config.ApiKeys) {
+			return ep.Config.ApiKeys[index].Value
+		}
+	}
+	return m.GetApiKeyForEndpoint(ep)
+}
+
+// ReportKeyResult 根据上游响应更新所用 Key 的状态
+// token/apiKey 为请求实际携带的值，retryAfter 为响应的 Retry-After（未提供时为 0）
+// 401/403 只标记用于鉴权的凭据：携带 Token 时以 Authorization 鉴权，不连带标记 API Key
+func (m *Manager) ReportKeyResult(ep *Endpoint, token, apiKey string, statusCode int, retryAfter time.Duration) {
+	changed := false
+
+	if (statusCode == 401 || statusCode == 403) && token != "" {
+		apiKey = ""
+	}
+
+	if token != "" && len(ep.Config.Tokens) > 1 {
+		for i, t := range ep.Config.Tokens {
+			if t.Value == token {
+				changed = m.reportKey(ep, KeyTypeToken, i, statusCode, retryAfter) || changed
+				break
+			}
+		}
+	}
+	if apiKey != "" && len(ep.Config.ApiKeys) > 1 {
+		for i, k := range ep.Config.ApiKeys {
+			if k.Value == apiKey {
+				changed = m.reportKey(ep, KeyTypeApiKey, i, statusCode, retryAfter) || changed
+				break
+			}
+		}
+	}
+
+	if changed && m.onKeyStateChanged != nil {
+		m.onKeyStateChanged()
+	}
+}
+
+// reportKey 更新单个 Key 的状态，自动切换时记录日志并发布事件
+func (m *Manager) reportKey(ep *Endpoint, keyType string, index, statusCode int, retryAfter time.Duration) bool {
+	changed, switchedTo := m.keyManager.ReportResult(ep.Config.Name, keyType, index, statusCode, retryAfter)
+	if !changed {
+		return false
+	}
+
+	keyName := keyDisplayName(ep, keyType, index)
+	slog.Warn(fmt.Sprintf("🔑 [Key轮换] 端点 %s 的 %s 返回 HTTP %d，已%s",
+		ep.Config.Name, keyName, statusCode, keyFailureAction(statusCode)))
+
+	if switchedTo < 0 {
+		return true
+	}
+
+	newKeyName := keyDisplayName(ep, keyType, switchedTo)
+	slog.Info(fmt.Sprintf("🔑 [Key轮换] 端点 %s 自动切换到: %s (索引: %d)", ep.Config.Name, newKeyName, switchedTo))
+
+	if m.eventBus != nil {
+		m.eventBus.Publish(events.Event{
+			Type:     "endpoint_key_changed",
+			Source:   "key_manager",
+			Priority: events.PriorityHigh,
+			Data: map[string]interface{}{
+				"endpoint":    ep.Config.Name,
+				"key_type":    keyType,
+				"new_index":   switchedTo,
+				"key_name":    newKeyName,
+				"reason":      "auto_rotation",
+				"status_code": statusCode,
+				"timestamp":   time.Now().Format("2006-01-02 15:04:05"),
+			},
+		})
+	}
+
+	return true
+}
+
+// KeyStateSnapshots 导出所有多 Key 端点的 Key 状态（用于持久化）
+func (m *Manager) KeyStateSnapshots() []KeyStateSnapshot {
+	var snapshots []KeyStateSnapshot
+
+	for _, ep := range m.GetAllEndpoints() {
+		state := m.keyManager.GetEndpointKeyState(ep.Config.Name)
+		if state == nil {
+			continue
+		}
+
+		for i, t := range ep.Config.Tokens {
+			if i < len(state.TokenStatus) {
+				snapshots = append(snapshots, KeyStateSnapshot{
+					EndpointName: ep.Config.Name,
+					KeyType:      KeyTypeToken,
+					KeyHash:      keyFingerprint(t.Value),
+					Active:       state.ActiveTokenIndex == i,
+					Status:       state.TokenStatus[i],
+				})
+			}
+		}
+		for i, k := range ep.Config.ApiKeys {
+			if i < len(state.ApiKeyStatus) {
+				snapshots = append(snapshots, KeyStateSnapshot{
+					EndpointName: ep.Config.Name,
+					KeyType:      KeyTypeApiKey,
+					KeyHash:      keyFingerprint(k.Value),
+					Active:       state.ActiveApiKeyIndex == i,
+					Status:       state.ApiKeyStatus[i],
+				})
+			}
+		}
+	}
+
+	return snapshots
+}
+
+// RestoreKeyStates 恢复持久化的 Key 状态，返回恢复的 Key 数量
+// 已不存在的端点或 Key 会被忽略
+func (m *Manager) RestoreKeyStates(snapshots []KeyStateSnapshot) int {
+	type keyRef struct {
+		endpoint, keyType, hash string
+	}
+	lookup := make(map[keyRef]KeyStateSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		lookup[keyRef{s.EndpointName, s.KeyType, s.KeyHash}] = s
+	}
+
+	restored := 0
+	for _, ep := range m.GetAllEndpoints() {
+		for i, t := range ep.Config.Tokens {
+			if s, ok := lookup[keyRef{ep.Config.Name, KeyTypeToken, keyFingerprint(t.Value)}]; ok {
+				m.keyManager.RestoreKeyStatus(ep.Config.Name, KeyTypeToken, i, s.Status, s.Active)
+				restored++
+			}
+		}
+		for i, k := range ep.Config.ApiKeys {
+			if s, ok := lookup[keyRef{ep.Config.Name, KeyTypeApiKey, keyFingerprint(k.Value)}]; ok {
+				m.keyManager.RestoreKeyStatus(ep.Config.Name, KeyTypeApiKey, i, s.Status, s.Active)
+				restored++
+			}
+		}
+	}
+
+	return restored
+}
+
+// keyFingerprint 计算 Key 值指纹（不保存明文）
+func keyFingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// keyDisplayName 获取 Key 的显示名称
+func keyDisplayName(ep *Endpoint, keyType string, index int) string {
+	if keyType == KeyTypeApiKey {
+		if index >= 0 && index < len(ep.Config.ApiKeys) && ep.Config.ApiKeys[index].Name != "" {
+			return ep.Config.ApiKeys[index].Name
+		}
+		return fmt.Sprintf("API Key %d", index+1)
+	}
+	if index >= 0 && index < len(ep.Config.Tokens) && ep.Config.Tokens[index].Name != "" {
+		return ep.Config.Tokens[index].Name
+	}
+	return fmt.Sprintf("Token %d", index+1)
+}
+
+// keyFailureAction 描述 Key 失败后的处理方式（用于日志）
+func keyFailureAction(statusCode int) string {
+	if statusCode == 401 || statusCode == 403 {
+		return "标记为无效"
+	}
+	return "进入冷却"
+}
@@ -0,0 +1,226 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+func TestKeyManager_SelectKey_Disabled(t *testing.T) {
+	km := NewKeyManager()
+	km.InitEndpoint("ep", 3, 0)
+
+	// 未启用自动轮换时始终使用当前激活的 Key，且不处理上游结果
+	for i := 0; i < 3; i++ {
+		if idx := km.SelectKey("ep", KeyTypeToken); idx != 0 {
+			t.Errorf("期望索引 0，实际为 %d", idx)
+		}
+	}
+	if changed, _ := km.ReportResult("ep", KeyTypeToken, 0, 429, 0); changed {
+		t.Error("未启用自动轮换时不应改变 Key 状态")
+	}
+}
+
+func TestKeyManager_Failover(t *testing.T) {
+	km := NewKeyManager()
+	km.Configure(true, KeyStrategyFailover, time.Minute)
+	km.InitEndpoint("ep", 3, 0)
+
+	if idx := km.SelectKey("ep", KeyTypeToken); idx != 0 {
+		t.Fatalf("期望初始索引 0，实际为 %d", idx)
+	}
+
+	// 429 使当前 Key 冷却并切换到下一个
+	changed, switchedTo := km.ReportResult("ep", KeyTypeToken, 0, 429, 30*time.Second)
+	if !changed || switchedTo != 1 {
+		t.Fatalf("期望切换到索引 1，实际 changed=%v switchedTo=%d", changed, switchedTo)
+	}
+	state := km.GetEndpointKeyState("ep")
+	if state.ActiveTokenIndex != 1 {
+		t.Errorf("期望激活索引 1，实际为 %d", state.ActiveTokenIndex)
+	}
+	if until := state.TokenStatus[0].CooldownUntil; time.Until(until) > 31*time.Second || time.Until(until) < 29*time.Second {
+		t.Errorf("冷却时间应使用 Retry-After: %v", until)
+	}
+
+	// 401 标记 Key 无效并切换
+	_, switchedTo = km.ReportResult("ep", KeyTypeToken, 1, 401, 0)
+	if switchedTo != 2 {
+		t.Fatalf("期望切换到索引 2，实际为 %d", switchedTo)
+	}
+	if !km.GetEndpointKeyState("ep").TokenStatus[1].Invalid {
+		t.Error("401 后 Key 应被标记为无效")
+	}
+
+	// 最后一个 Key 也不可用时保持当前 Key
+	_, switchedTo = km.ReportResult("ep", KeyTypeToken, 2, 429, 0)
+	if switchedTo != -1 {
+		t.Errorf("没有可用 Key 时不应切换，实际切换到 %d", switchedTo)
+	}
+	if idx := km.SelectKey("ep", KeyTypeToken); idx != 2 {
+		t.Errorf("全部不可用时应保持当前 Key，实际为 %d", idx)
+	}
+
+	// 成功响应不改变状态
+	if changed, _ := km.ReportResult("ep", KeyTypeToken, 2, 200, 0); changed {
+		t.Error("成功响应不应改变状态")
+	}
+
+	// 手动切换清除无效标记
+	if err := km.SwitchToken("ep", 1); err != nil {
+		t.Fatalf("手动切换失败: %v", err)
+	}
+	if km.GetEndpointKeyState("ep").TokenStatus[1].Invalid {
+		t.Error("手动切换后应清除无效标记")
+	}
+}
+
+func TestKeyManager_RoundRobin(t *testing.T) {
+	km := NewKeyManager()
+	km.Configure(true, KeyStrategyRoundRobin, time.Minute)
+	km.InitEndpoint("ep", 3, 0)
+
+	var got []int
+	for i := 0; i < 4; i++ {
+		got = append(got, km.SelectKey("ep", KeyTypeToken))
+	}
+	want := []int{0, 1, 2, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("轮询顺序 = %v，期望 %v", got, want)
+		}
+	}
+
+	// 冷却中的 Key 被跳过
+	km.ReportResult("ep", KeyTypeToken, 1, 429, 0)
+	got = got[:0]
+	for i := 0; i < 3; i++ {
+		got = append(got, km.SelectKey("ep", KeyTypeToken))
+	}
+	for _, idx := range got {
+		if idx == 1 {
+			t.Fatalf("冷却中的 Key 不应被分配: %v", got)
+		}
+	}
+}
+
+func TestKeyManager_LeastUsed(t *testing.T) {
+	km := NewKeyManager()
+	km.Configure(true, KeyStrategyLeastUsed, time.Minute)
+	km.InitEndpoint("ep", 0, 3)
+
+	counts := make([]int, 3)
+	for i := 0; i < 9; i++ {
+		counts[km.SelectKey("ep", KeyTypeApiKey)]++
+	}
+	for i, c := range counts {
+		if c != 3 {
+			t.Errorf("API Key %d 分配次数 = %d，期望 3", i, c)
+		}
+	}
+
+	km.ReportResult("ep", KeyTypeApiKey, 0, 403, 0)
+	for i := 0; i < 4; i++ {
+		if idx := km.SelectKey("ep", KeyTypeApiKey); idx == 0 {
+			t.Fatal("无效的 Key 不应被分配")
+		}
+	}
+}
+
+func TestManager_KeyRotationPersistence(t *testing.T) {
+	newManager := func() *Manager {
+		cfg := &config.Config{
+			Strategy:    config.StrategyConfig{Type: "priority"},
+			KeyRotation: config.KeyRotationConfig{Enabled: true, Strategy: KeyStrategyFailover, Cooldown: time.Minute},
+			Endpoints: []config.EndpointConfig{{
+				Name: "multi",
+				URL:  "https://api.example.com",
+				Tokens: []config.TokenConfig{
+					{Name: "first", Value: "sk-first-token"},
+					{Name: "second", Value: "sk-second-token"},
+				},
+			}},
+		}
+		return NewManager(cfg)
+	}
+
+	m := newManager()
+	ep := m.GetEndpointByNameAny("multi")
+
+	notified := 0
+	m.SetOnKeyStateChanged(func() { notified++ })
+
+	if token := m.SelectTokenForRequest(ep); token != "sk-first-token" {
+		t.Fatalf("期望使用第一个 Token，实际为 %s", token)
+	}
+	m.ReportKeyResult(ep, "sk-first-token", "", 429, 0)
+	if notified != 1 {
+		t.Errorf("状态变化回调次数 = %d，期望 1", notified)
+	}
+	if token := m.SelectTokenForRequest(ep); token != "sk-second-token" {
+		t.Fatalf("429 后应切换到第二个 Token，实际为 %s", token)
+	}
+
+	info := m.GetEndpointKeysInfo("multi")
+	tokens := info["tokens"].([]map[string]interface{})
+	if tokens[0]["status"] != "cooldown" || tokens[1]["status"] != "available" {
+		t.Errorf("Key 状态信息不匹配: %v", tokens)
+	}
+
+	// 重启后恢复（按 Key 值指纹匹配）
+	snapshots := m.KeyStateSnapshots()
+	restoredManager := newManager()
+	if n := restoredManager.RestoreKeyStates(snapshots); n != 2 {
+		t.Fatalf("恢复数量 = %d，期望 2", n)
+	}
+	state := restoredManager.GetKeyManager().GetEndpointKeyState("multi")
+	if state.ActiveTokenIndex != 1 || state.TokenStatus[0].CooldownUntil.IsZero() {
+		t.Errorf("恢复后的状态不匹配: %+v", state)
+	}
+}
+
+func TestManager_ReportKeyResult_AuthFailureMarksSentCredential(t *testing.T) {
+	cfg := &config.Config{
+		Strategy:    config.StrategyConfig{Type: "priority"},
+		KeyRotation: config.KeyRotationConfig{Enabled: true, Strategy: KeyStrategyFailover, Cooldown: time.Minute},
+		Endpoints: []config.EndpointConfig{{
+			Name: "both",
+			URL:  "https://api.example.com",
+			Tokens: []config.TokenConfig{
+				{Name: "t1", Value: "sk-token-1"},
+				{Name: "t2", Value: "sk-token-2"},
+			},
+			ApiKeys: []config.ApiKeyConfig{
+				{Name: "k1", Value: "sk-key-1"},
+				{Name: "k2", Value: "sk-key-2"},
+			},
+		}},
+	}
+	m := NewManager(cfg)
+	ep := m.GetEndpointByNameAny("both")
+
+	// 同时携带 Token 和 API Key：401 只标记 Token
+	m.ReportKeyResult(ep, "sk-token-1", "sk-key-1", 401, 0)
+	state := m.GetKeyManager().GetEndpointKeyState("both")
+	if !state.TokenStatus[0].Invalid {
+		t.Error("401 应将所用 Token 标记为无效")
+	}
+	if state.ApiKeyStatus[0].Invalid {
+		t.Error("携带 Token 时 401 不应标记 API Key")
+	}
+
+	// 只携带 API Key：标记 API Key
+	m.ReportKeyResult(ep, "", "sk-key-2", 403, 0)
+	state = m.GetKeyManager().GetEndpointKeyState("both")
+	if !state.ApiKeyStatus[1].Invalid {
+		t.Error("仅携带 API Key 时 403 应将其标记为无效")
+	}
+
+	// 429 对两个凭据都生效
+	m.ReportKeyResult(ep, "sk-token-2", "sk-key-1", 429, 0)
+	state = m.GetKeyManager().GetEndpointKeyState("both")
+	if state.TokenStatus[1].CooldownUntil.IsZero() || state.ApiKeyStatus[0].CooldownUntil.IsZero() {
+		t.Error("429 应使所用 Token 和 API Key 都进入冷却")
+	}
+}
@@ -118,15 +118,21 @@ func (m *Manager) GetEndpointKeysInfo(endpointName string) map[string]interface{
 
 	state := m.keyManager.GetEndpointKeyState(endpointName)
 
+	now := time.Now()
+
 	// 构建 Token 列表（脱敏）
 	tokens := make([]map[string]interface{}, 0)
 	for i, t := range ep.Config.Tokens {
-		tokens = append(tokens, map[string]interface{}{
+		info := map[string]interface{}{
 			"index":     i,
 			"name":      t.Name,
 			"masked":    maskKey(t.Value),
 			"is_active": state != nil && state.ActiveTokenIndex == i,
-		})
+		}
+		if state != nil && i < len(state.TokenStatus) {
+			addKeyStatusInfo(info, state.TokenStatus[i], now)
+		}
+		tokens = append(tokens, info)
 	}
 	// 单 Token 情况
 	if len(tokens) == 0 && ep.Config.Token != "" {
@@ -141,12 +147,16 @@ func (m *Manager) GetEndpointKeysInfo(endpointName string) map[string]interface{
 	// 构建 API Key 列表（脱敏）
 	apiKeys := make([]map[string]interface{}, 0)
 	for i, k := range ep.Config.ApiKeys {
-		apiKeys = append(apiKeys, map[string]interface{}{
+		info := map[string]interface{}{
 			"index":     i,
 			"name":      k.Name,
 			"masked":    maskKey(k.Value),
 			"is_active": state != nil && state.ActiveApiKeyIndex == i,
-		})
+		}
+		if state != nil && i < len(state.ApiKeyStatus) {
+			addKeyStatusInfo(info, state.ApiKeyStatus[i], now)
+		}
+		apiKeys = append(apiKeys, info)
 	}
 	if len(apiKeys) == 0 && ep.Config.ApiKey != "" {
 		apiKeys = append(apiKeys, map[string]interface{}{
@@ -162,6 +172,8 @@ func (m *Manager) GetEndpointKeysInfo(endpointName string) map[string]interface{
 		"tokens":             tokens,
 		"api_keys":           apiKeys,
 		"supports_switching": len(ep.Config.Tokens) > 1 || len(ep.Config.ApiKeys) > 1,
+		"auto_rotation":      m.keyManager.IsAutoRotate(),
+		"strategy":           m.keyManager.GetStrategy(),
 	}
 
 	if state != nil && !state.LastSwitchTime.IsZero() {
@@ -171,6 +183,26 @@ func (m *Manager) GetEndpointKeysInfo(endpointName string) map[string]interface{
 	return result
 }
 
+// addKeyStatusInfo 添加 Key 运行状态信息（available/cooldown/invalid）
+func addKeyStatusInfo(info map[string]interface{}, st KeyStatus, now time.Time) {
+	status := "available"
+	if st.Invalid {
+		status = "invalid"
+	} else if now.Before(st.CooldownUntil) {
+		status = "cooldown"
+		info["cooldown_until"] = st.CooldownUntil.Format("2006-01-02 15:04:05")
+	}
+	info["status"] = status
+	info["usage_count"] = st.UsageCount
+	if st.LastStatusCode != 0 {
+		info["last_status_code"] = st.LastStatusCode
+		info["last_error"] = st.LastError
+	}
+	if !st.LastUsedAt.IsZero() {
+		info["last_used_at"] = st.LastUsedAt.Format("2006-01-02 15:04:05")
+	}
+}
+
 // maskKey 脱敏 Key 值，只显示前4位和后4位
 func maskKey(key string) string {
 	if len(key) <= 8 {
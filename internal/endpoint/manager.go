@@ -5,6 +5,9 @@
 // - endpoint_crud.go: 动态端点管理
 // - failover.go: 故障转移
 // - key_switch.go: Key 切换
+// - circuit_breaker.go: 端点熔断器
+// - rate_limit.go: 上游限流响应头
+// - endpoint_limits.go: 端点并发和 RPM/TPM 上限
 // - notification.go: 通知相关
 
 package endpoint
@@ -31,6 +34,8 @@ type EndpointStatus struct {
 	NeverChecked     bool      // 表示从未被检测过
 	CooldownUntil    time.Time // 请求失败冷却截止时间
 	CooldownReason   string    // 冷却原因（如 "HTTP 503"）
+	EWMALatency      time.Duration // 真实请求响应延迟的指数加权平均（ewma 策略使用）
+	LatencySamples   int64         // 参与 EWMA 计算的请求数
 }
 
 // Endpoint represents an endpoint with its configuration and status
@@ -52,6 +57,8 @@ type Manager struct {
 	fastTester  *FastTester
 	groupManager *GroupManager
 	keyManager   *KeyManager // 管理多 API Key 状态
+	// 按端点复用的转发 Transport 连接池
+	transportPool *transport.Pool
 	// EventBus for decoupled event publishing
 	eventBus events.EventBus
 	// 健康检查完成回调（用于推送 Wails 事件）
@@ -59,6 +66,20 @@ type Manager struct {
 	// 故障转移回调（用于同步数据库）
 	// 参数: failedEndpoint 失败的端点名, newEndpoint 新激活的端点名
 	onFailoverTriggered func(failedEndpoint, newEndpoint string)
+	// 端点准入检查（预算等），未通过的端点在选择时跳过
+	endpointGate EndpointGate
+	// Key 状态变化回调（用于持久化冷却/无效状态）
+	onKeyStateChanged func()
+	// 在途请求计数（least_outstanding 策略使用）
+	inFlightCounter InFlightCounter
+	// 模型路由（按请求模型限制可用端点）
+	modelRouter ModelRouter
+	// 端点熔断器（按真实请求的错误率和延迟）
+	circuitBreaker *CircuitBreaker
+	// 上游限流快照（retry-after、anthropic-ratelimit-* 响应头）
+	rateLimits *RateLimitTracker
+	// 端点容量统计（max_concurrency、rpm、tpm）
+	limiter *EndpointLimiter
 }
 
 // NewManager creates a new endpoint manager
@@ -84,6 +105,10 @@ func NewManager(cfg *config.Config) *Manager {
 		fastTester:   NewFastTester(cfg),
 		groupManager: NewGroupManager(cfg),
 		keyManager:   NewKeyManager(), // 初始化 Key 管理器
+		transportPool: transport.NewPool(cfg),
+		circuitBreaker: NewCircuitBreaker(cfg.CircuitBreaker),
+		rateLimits:     NewRateLimitTracker(),
+		limiter:        NewEndpointLimiter(),
 	}
 
 	// Initialize endpoints
@@ -110,6 +135,9 @@ func NewManager(cfg *config.Config) *Manager {
 		manager.keyManager.InitEndpoint(endpointCfg.Name, tokenCount, apiKeyCount)
 	}
 
+	// 配置多 Key 自动轮换
+	manager.keyManager.Configure(cfg.KeyRotation.Enabled, cfg.KeyRotation.Strategy, cfg.KeyRotation.Cooldown)
+
 	// Set manager reference in fast tester for dynamic token resolution
 	manager.fastTester.SetManager(manager)
 
@@ -129,6 +157,12 @@ func (m *Manager) Start() {
 func (m *Manager) Stop() {
 	m.cancel()
 	m.wg.Wait()
+	m.transportPool.Close()
+}
+
+// GetTransportPool returns the per-endpoint transport pool used for forwarding
+func (m *Manager) GetTransportPool() *transport.Pool {
+	return m.transportPool
 }
 
 // UpdateConfig updates the manager configuration (hot-reload)
@@ -145,6 +179,15 @@ func (m *Manager) UpdateConfig(cfg *config.Config) {
 		m.fastTester.UpdateConfig(cfg)
 	}
 
+	// 代理等传输参数变化时重建对应的转发 Transport
+	m.transportPool.UpdateConfig(cfg)
+
+	// 多 Key 自动轮换配置
+	m.keyManager.Configure(cfg.KeyRotation.Enabled, cfg.KeyRotation.Strategy, cfg.KeyRotation.Cooldown)
+
+	// 熔断器配置
+	m.circuitBreaker.Configure(cfg.CircuitBreaker)
+
 	// Recreate transport with new proxy configuration
 	if transport, err := transport.CreateTransport(cfg); err == nil {
 		m.client = &http.Client{
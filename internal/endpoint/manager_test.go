@@ -49,6 +49,11 @@ func (m *MockEventBus) GetStats() events.BusStats {
 	return events.BusStats{}
 }
 
+// Subscribe 实现EventBus接口
+func (m *MockEventBus) Subscribe(filter events.SubscriptionFilter) (<-chan events.Event, func()) {
+	return make(chan events.Event), func() {}
+}
+
 func TestHealthCheckWithAPIEndpoint(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -0,0 +1,162 @@
+// model_routing.go - 模型路由
+// 按请求模型限制可用端点：命中路由规则时，端点选择和故障转移都只在规则允许的端点内进行
+
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ModelRoute 模型路由规则匹配结果
+type ModelRoute struct {
+	Rule      string   // 规则名称
+	Endpoints []string // 允许的端点名称
+	Channels  []string // 允许的渠道名称
+	Fallback  bool     // 允许的端点均不可用时回退到常规端点选择
+}
+
+// Allows 判断端点是否被规则允许
+func (r *ModelRoute) Allows(ep *Endpoint) bool {
+	return slices.Contains(r.Endpoints, ep.Config.Name) ||
+		(ep.Config.Channel != "" && slices.Contains(r.Channels, ep.Config.Channel))
+}
+
+// ModelRouter 模型路由接口（由路由规则服务提供）
+type ModelRouter interface {
+	// HasRoutes 是否存在启用的路由规则（没有规则时跳过模型解析）
+	HasRoutes() bool
+	// MatchModel 返回模型命中的第一条路由规则
+	MatchModel(model string) (*ModelRoute, bool)
+}
+
+// requestModelContextKey 请求上下文中模型名称的键
+type requestModelContextKey struct{}
+
+// WithRequestModel 将请求模型名称写入上下文（供端点选择使用）
+func WithRequestModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, requestModelContextKey{}, model)
+}
+
+// RequestModelFromContext 获取请求模型名称（未解析时为空）
+func RequestModelFromContext(ctx context.Context) string {
+	model, _ := ctx.Value(requestModelContextKey{}).(string)
+	return model
+}
+
+// SetModelRouter 设置模型路由（需在 Start 之前调用）
+func (m *Manager) SetModelRouter(router ModelRouter) {
+	m.modelRouter = router
+}
+
+// HasModelRoutes 是否存在启用的模型路由规则
+func (m *Manager) HasModelRoutes() bool {
+	return m.modelRouter != nil && m.modelRouter.HasRoutes()
+}
+
+// GetEndpointsForRequest 获取请求可用的端点列表
+// fastest 策略且启用快速测试时使用实时测试结果，否则使用健康端点；然后应用模型路由规则
+func (m *Manager) GetEndpointsForRequest(ctx context.Context) []*Endpoint {
+	var endpoints []*Endpoint
+	if m.config.Strategy.Type == StrategyFastest && m.config.Strategy.FastTestEnabled {
+		endpoints = m.GetFastestEndpointsWithRealTimeTest(ctx)
+	} else {
+		endpoints = m.GetHealthyEndpoints()
+	}
+
+	route := m.matchModelRoute(ctx)
+	if route == nil {
+		return endpoints
+	}
+
+	// 常规选择结果中允许的端点优先，其余允许的健康端点作为故障转移候选
+	allowed := filterByRoute(endpoints, route)
+	allowed = append(allowed, m.getRoutedEndpoints(route, allowed)...)
+
+	if len(allowed) == 0 && route.Fallback {
+		slog.Warn(fmt.Sprintf("⚠️ [模型路由] 规则 %s 允许的端点均不可用，回退到常规端点选择", route.Rule))
+		return endpoints
+	}
+
+	return allowed
+}
+
+// FilterEndpointsForModel 按模型路由规则过滤端点列表（用于忽略健康状态的回退场景）
+// 未命中规则，或过滤后为空且规则允许回退时返回原列表
+func (m *Manager) FilterEndpointsForModel(ctx context.Context, endpoints []*Endpoint) []*Endpoint {
+	route := m.matchModelRoute(ctx)
+	if route == nil {
+		return endpoints
+	}
+
+	allowed := filterByRoute(endpoints, route)
+	if len(allowed) == 0 && route.Fallback {
+		return endpoints
+	}
+	return allowed
+}
+
+// matchModelRoute 获取请求模型命中的路由规则
+func (m *Manager) matchModelRoute(ctx context.Context) *ModelRoute {
+	if m.modelRouter == nil {
+		return nil
+	}
+
+	model := RequestModelFromContext(ctx)
+	if model == "" {
+		return nil
+	}
+
+	route, ok := m.modelRouter.MatchModel(model)
+	if !ok {
+		return nil
+	}
+
+	slog.Debug(fmt.Sprintf("🧭 [模型路由] 模型 %s 命中规则 %s", model, route.Rule))
+	return route
+}
+
+// getRoutedEndpoints 获取规则允许的其他健康端点（排除 exclude 中的端点，按路由策略排序）
+// 规则显式指定了可用端点，因此不受激活状态和故障转移开关限制
+func (m *Manager) getRoutedEndpoints(route *ModelRoute, exclude []*Endpoint) []*Endpoint {
+	m.endpointsMu.RLock()
+	snapshot := make([]*Endpoint, len(m.endpoints))
+	copy(snapshot, m.endpoints)
+	m.endpointsMu.RUnlock()
+
+	now := time.Now()
+	var routed []*Endpoint
+	for _, ep := range snapshot {
+		if !route.Allows(ep) || slices.Contains(exclude, ep) {
+			continue
+		}
+
+		ep.mutex.RLock()
+		isHealthy := ep.Status.Healthy
+		inCooldown := !ep.Status.CooldownUntil.IsZero() && now.Before(ep.Status.CooldownUntil)
+		ep.mutex.RUnlock()
+
+		if isHealthy && !inCooldown && !m.isGated(ep) {
+			routed = append(routed, ep)
+		}
+	}
+
+	if len(routed) == 0 {
+		return nil
+	}
+	return m.sortHealthyEndpoints(routed, false)
+}
+
+// filterByRoute 保留规则允许的端点（保持原有顺序）
+func filterByRoute(endpoints []*Endpoint, route *ModelRoute) []*Endpoint {
+	var allowed []*Endpoint
+	for _, ep := range endpoints {
+		if route.Allows(ep) {
+			allowed = append(allowed, ep)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,115 @@
+package endpoint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// staticModelRouter 测试用模型路由：模型名 -> 规则
+type staticModelRouter map[string]*ModelRoute
+
+func (r staticModelRouter) HasRoutes() bool {
+	return len(r) > 0
+}
+
+func (r staticModelRouter) MatchModel(model string) (*ModelRoute, bool) {
+	route, ok := r[model]
+	return route, ok
+}
+
+// newModelRoutingManager 创建测试用管理器：opus-relay 为激活端点，其余端点均健康
+func newModelRoutingManager(t *testing.T) *Manager {
+	t.Helper()
+
+	disabled := false
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: StrategyPriority},
+		Failover: config.FailoverConfig{Enabled: true},
+		Endpoints: []config.EndpointConfig{
+			{Name: "opus-relay", URL: "https://opus.example.com", Priority: 1},
+			{Name: "sonnet-relay-1", URL: "https://sonnet1.example.com", Priority: 2, Channel: "sonnet"},
+			{Name: "sonnet-relay-2", URL: "https://sonnet2.example.com", Priority: 3, Channel: "sonnet", FailoverEnabled: &disabled},
+		},
+	}
+
+	m := NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("opus-relay"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+
+	m.SetModelRouter(staticModelRouter{
+		"claude-opus":   {Rule: "opus", Endpoints: []string{"opus-relay"}},
+		"claude-sonnet": {Rule: "sonnet", Channels: []string{"sonnet"}},
+		"claude-haiku":  {Rule: "haiku", Endpoints: []string{"missing"}, Fallback: true},
+		"claude-strict": {Rule: "strict", Endpoints: []string{"missing"}},
+	})
+	return m
+}
+
+func endpointNames(endpoints []*Endpoint) []string {
+	names := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		names = append(names, ep.Config.Name)
+	}
+	return names
+}
+
+func assertEndpointNames(t *testing.T, got []*Endpoint, want ...string) {
+	t.Helper()
+	names := endpointNames(got)
+	if len(names) != len(want) {
+		t.Fatalf("端点列表 = %v，期望 %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("端点列表 = %v，期望 %v", names, want)
+		}
+	}
+}
+
+func TestGetEndpointsForRequest_ModelRouting(t *testing.T) {
+	m := newModelRoutingManager(t)
+	ctx := context.Background()
+
+	// 未解析模型或未命中规则：常规选择
+	assertEndpointNames(t, m.GetEndpointsForRequest(ctx), "opus-relay")
+	assertEndpointNames(t, m.GetEndpointsForRequest(WithRequestModel(ctx, "claude-unknown")), "opus-relay")
+
+	// 激活端点被允许
+	assertEndpointNames(t, m.GetEndpointsForRequest(WithRequestModel(ctx, "claude-opus")), "opus-relay")
+
+	// 激活端点不被允许：使用规则允许的其他健康端点（不受故障转移开关限制）
+	sonnetCtx := WithRequestModel(ctx, "claude-sonnet")
+	assertEndpointNames(t, m.GetEndpointsForRequest(sonnetCtx), "sonnet-relay-1", "sonnet-relay-2")
+
+	// 冷却中的端点跳过，故障转移仍限制在允许的端点内
+	sonnet1 := m.GetEndpointByNameAny("sonnet-relay-1")
+	sonnet1.Status.CooldownUntil = time.Now().Add(time.Minute)
+	assertEndpointNames(t, m.GetEndpointsForRequest(sonnetCtx), "sonnet-relay-2")
+
+	// 允许的端点均不可用：按规则决定是否回退
+	assertEndpointNames(t, m.GetEndpointsForRequest(WithRequestModel(ctx, "claude-haiku")), "opus-relay")
+	if eps := m.GetEndpointsForRequest(WithRequestModel(ctx, "claude-strict")); len(eps) != 0 {
+		t.Errorf("不允许回退时应返回空列表，实际为 %v", endpointNames(eps))
+	}
+}
+
+func TestFilterEndpointsForModel(t *testing.T) {
+	m := newModelRoutingManager(t)
+	ctx := context.Background()
+	all := m.GetAllEndpoints()
+
+	assertEndpointNames(t, m.FilterEndpointsForModel(ctx, all), "opus-relay", "sonnet-relay-1", "sonnet-relay-2")
+	assertEndpointNames(t, m.FilterEndpointsForModel(WithRequestModel(ctx, "claude-sonnet"), all), "sonnet-relay-1", "sonnet-relay-2")
+	assertEndpointNames(t, m.FilterEndpointsForModel(WithRequestModel(ctx, "claude-haiku"), all), "opus-relay", "sonnet-relay-1", "sonnet-relay-2")
+	if eps := m.FilterEndpointsForModel(WithRequestModel(ctx, "claude-strict"), all); len(eps) != 0 {
+		t.Errorf("不允许回退时应返回空列表，实际为 %v", endpointNames(eps))
+	}
+}
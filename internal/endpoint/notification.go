@@ -150,3 +150,31 @@ func (m *Manager) notifyWebGroupChange(eventType, groupName string) {
 
 	slog.Debug(fmt.Sprintf("📢 [组管理] 发布组状态变化事件: %s (组: %s)", eventType, groupName))
 }
+
+// CooldownGroup 将组设置为冷却状态并发布组冷却事件（手动模式下组被暂停，不发布冷却事件）
+func (m *Manager) CooldownGroup(groupName, reason string) {
+	m.groupManager.SetGroupCooldown(groupName)
+	if remaining := m.groupManager.GetGroupCooldownRemaining(groupName); remaining > 0 {
+		m.notifyGroupCooldown(groupName, time.Now().Add(remaining), reason)
+	}
+}
+
+// notifyGroupCooldown 发布组（端点）进入冷却事件
+func (m *Manager) notifyGroupCooldown(groupName string, until time.Time, reason string) {
+	if m.eventBus == nil {
+		return
+	}
+
+	m.eventBus.Publish(events.Event{
+		Type:     events.EventGroupCooldown,
+		Source:   "endpoint_manager",
+		Priority: events.PriorityHigh,
+		Data: map[string]interface{}{
+			"group":            groupName,
+			"reason":           reason,
+			"cooldown_until":   until.Format("2006-01-02 15:04:05"),
+			"cooldown_seconds": int64(time.Until(until).Round(time.Second).Seconds()),
+			"change_type":      "group_cooldown",
+		},
+	})
+}
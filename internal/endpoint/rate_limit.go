@@ -0,0 +1,304 @@
+// rate_limit.go - 上游限流响应头
+// 解析 retry-after 和 anthropic-ratelimit-* 响应头，记录每个端点最近一次的限流快照：
+// 限流响应按 Retry-After 冷却端点，剩余额度接近上限的端点在选择时排到后面
+
+package endpoint
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitReserve 剩余额度低于上限的该比例时视为接近限流
+const defaultRateLimitReserve = 0.1
+
+// RateLimitWindow 一类额度（请求数或 Token 数）的状态，Limit 为 0 表示上游未返回
+type RateLimitWindow struct {
+	Limit     int64
+	Remaining int64
+	Reset     time.Time // 额度完全恢复的时间
+}
+
+// RateLimitSnapshot 端点最近一次响应携带的限流信息
+type RateLimitSnapshot struct {
+	Requests     RateLimitWindow
+	Tokens       RateLimitWindow
+	InputTokens  RateLimitWindow
+	OutputTokens RateLimitWindow
+	RetryAfter   time.Duration // 最近一次限流响应要求的等待时间
+	RetryAt      time.Time     // RetryAfter 对应的限流解除时间
+	StatusCode   int           // 最近一次记录的响应状态码
+	UpdatedAt    time.Time
+}
+
+// IsZero 端点是否还没有限流快照
+func (s RateLimitSnapshot) IsZero() bool {
+	return s.UpdatedAt.IsZero()
+}
+
+// windows 返回所有额度窗口
+func (s *RateLimitSnapshot) windows() []*RateLimitWindow {
+	return []*RateLimitWindow{&s.Requests, &s.Tokens, &s.InputTokens, &s.OutputTokens}
+}
+
+// NearLimit 是否处于限流中，或任一额度的剩余量不超过上限的 reserve 比例（已过重置时间的额度视为已恢复）
+func (s RateLimitSnapshot) NearLimit(now time.Time, reserve float64) bool {
+	if now.Before(s.RetryAt) {
+		return true
+	}
+	for _, w := range s.windows() {
+		if w.Limit <= 0 || (!w.Reset.IsZero() && !now.Before(w.Reset)) {
+			continue
+		}
+		if float64(w.Remaining) <= float64(w.Limit)*reserve {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetryAfter 解析 Retry-After 头（秒数或 HTTP 日期），无效时返回 0
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// ParseRateLimitHeaders 解析响应中的限流信息，没有任何限流相关头部时返回 false
+// 错误响应未带 Retry-After 时，使用已耗尽额度中最晚的重置时间作为等待时间
+func ParseRateLimitHeaders(header http.Header, statusCode int, now time.Time) (RateLimitSnapshot, bool) {
+	snapshot := RateLimitSnapshot{StatusCode: statusCode, UpdatedAt: now}
+	found := false
+	for prefix, w := range map[string]*RateLimitWindow{
+		"requests":      &snapshot.Requests,
+		"tokens":        &snapshot.Tokens,
+		"input-tokens":  &snapshot.InputTokens,
+		"output-tokens": &snapshot.OutputTokens,
+	} {
+		if parseRateLimitWindow(header, "anthropic-ratelimit-"+prefix, now, w) {
+			found = true
+		}
+	}
+
+	if statusCode >= 400 {
+		snapshot.RetryAfter = ParseRetryAfter(header.Get("Retry-After"), now)
+		if snapshot.RetryAfter > 0 {
+			found = true
+		} else if statusCode == http.StatusTooManyRequests {
+			for _, w := range snapshot.windows() {
+				if w.Limit > 0 && w.Remaining <= 0 && w.Reset.After(now) && w.Reset.Sub(now) > snapshot.RetryAfter {
+					snapshot.RetryAfter = w.Reset.Sub(now)
+				}
+			}
+		}
+		if snapshot.RetryAfter > 0 {
+			snapshot.RetryAt = now.Add(snapshot.RetryAfter)
+		}
+	}
+	return snapshot, found
+}
+
+// parseRateLimitWindow 解析 <prefix>-limit / -remaining / -reset 三个头部
+func parseRateLimitWindow(header http.Header, prefix string, now time.Time, w *RateLimitWindow) bool {
+	limit, errLimit := strconv.ParseInt(strings.TrimSpace(header.Get(prefix+"-limit")), 10, 64)
+	remaining, errRemaining := strconv.ParseInt(strings.TrimSpace(header.Get(prefix+"-remaining")), 10, 64)
+	if errLimit != nil || errRemaining != nil || limit <= 0 {
+		return false
+	}
+	w.Limit = limit
+	w.Remaining = remaining
+	w.Reset = parseRateLimitReset(header.Get(prefix+"-reset"), now)
+	return true
+}
+
+// parseRateLimitReset 解析重置时间：RFC 3339 时间戳、秒数或 Go 时长（如 "6m0s"）
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(d)
+	}
+	return time.Time{}
+}
+
+// RateLimitTracker 记录各端点最近一次的限流快照
+type RateLimitTracker struct {
+	snapshots map[string]RateLimitSnapshot // endpoint name -> snapshot
+	mu        sync.RWMutex
+	now       func() time.Time
+}
+
+// NewRateLimitTracker 创建限流快照记录器
+func NewRateLimitTracker() *RateLimitTracker {
+	return &RateLimitTracker{
+		snapshots: make(map[string]RateLimitSnapshot),
+		now:       time.Now,
+	}
+}
+
+// Record 记录端点响应的限流信息，返回更新后的快照及该响应是否携带限流信息
+// 响应未返回的额度沿用上一次的值；成功响应清除未到期的限流等待
+func (t *RateLimitTracker) Record(name string, header http.Header, statusCode int) (RateLimitSnapshot, bool) {
+	now := t.now()
+	parsed, ok := ParseRateLimitHeaders(header, statusCode, now)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, exists := t.snapshots[name]
+	if !ok {
+		if exists && statusCode < 400 && !prev.RetryAt.IsZero() {
+			prev.RetryAfter, prev.RetryAt = 0, time.Time{}
+			prev.StatusCode, prev.UpdatedAt = statusCode, now
+			t.snapshots[name] = prev
+		}
+		return t.snapshots[name], false
+	}
+
+	if exists {
+		prevWindows := prev.windows()
+		for i, w := range parsed.windows() {
+			if w.Limit == 0 {
+				*w = *prevWindows[i]
+			}
+		}
+	}
+	t.snapshots[name] = parsed
+	return parsed, true
+}
+
+// Snapshot 获取端点的限流快照
+func (t *RateLimitTracker) Snapshot(name string) RateLimitSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.snapshots[name]
+}
+
+// NearLimit 端点是否接近限流
+func (t *RateLimitTracker) NearLimit(name string, reserve float64) bool {
+	t.mu.RLock()
+	snapshot, ok := t.snapshots[name]
+	t.mu.RUnlock()
+	return ok && snapshot.NearLimit(t.now(), reserve)
+}
+
+// Remove 删除端点的限流快照（端点被删除时）
+func (t *RateLimitTracker) Remove(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.snapshots, name)
+}
+
+// ============================================================
+// Manager 集成
+// ============================================================
+
+// GetRateLimitSnapshot 获取端点最近一次的限流快照
+func (m *Manager) GetRateLimitSnapshot(name string) RateLimitSnapshot {
+	return m.rateLimits.Snapshot(name)
+}
+
+// RecordRateLimit 记录端点响应携带的限流信息，返回该响应要求的等待时间（未要求时为 0）
+// 429 或 5xx 带 Retry-After（或额度已耗尽）时，端点冷却到限流解除；
+// 启用 Key 轮换的多 Key 端点只冷却对应的 Key，不冷却整个端点
+func (m *Manager) RecordRateLimit(ep *Endpoint, statusCode int, header http.Header) time.Duration {
+	snapshot, ok := m.rateLimits.Record(ep.Config.Name, header, statusCode)
+	if !ok || snapshot.RetryAfter <= 0 {
+		return 0
+	}
+	if statusCode != http.StatusTooManyRequests && statusCode < 500 {
+		return snapshot.RetryAfter
+	}
+	if m.rotatesKeys(ep) {
+		return snapshot.RetryAfter
+	}
+
+	ep.mutex.Lock()
+	extended := snapshot.RetryAt.After(ep.Status.CooldownUntil)
+	if extended {
+		ep.Status.CooldownUntil = snapshot.RetryAt
+		ep.Status.CooldownReason = fmt.Sprintf("HTTP %d 限流", statusCode)
+	}
+	ep.mutex.Unlock()
+
+	if extended {
+		slog.Warn(fmt.Sprintf("⏳ [限流] 端点 %s 返回 HTTP %d，按上游要求冷却 %v",
+			ep.Config.Name, statusCode, snapshot.RetryAfter))
+		if m.onHealthCheckComplete != nil {
+			go m.onHealthCheckComplete()
+		}
+	}
+	return snapshot.RetryAfter
+}
+
+// rotatesKeys 端点是否启用了多 Key 自动轮换（限流时冷却 Key 而不是整个端点）
+func (m *Manager) rotatesKeys(ep *Endpoint) bool {
+	return m.config.KeyRotation.Enabled && (len(ep.Config.Tokens) > 1 || len(ep.Config.ApiKeys) > 1)
+}
+
+// RateLimitRetryAfter 端点处于上游限流中时返回剩余等待时间，否则返回 0
+func (m *Manager) RateLimitRetryAfter(name string) time.Duration {
+	snapshot := m.rateLimits.Snapshot(name)
+	if remaining := time.Until(snapshot.RetryAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// IsNearRateLimit 端点是否处于上游限流中或剩余额度接近上限
+func (m *Manager) IsNearRateLimit(name string) bool {
+	return m.rateLimits.NearLimit(name, m.rateLimitReserve())
+}
+
+// rateLimitReserve 接近限流的剩余额度比例
+func (m *Manager) rateLimitReserve() float64 {
+	if m.config.Strategy.RateLimitReserve > 0 {
+		return m.config.Strategy.RateLimitReserve
+	}
+	return defaultRateLimitReserve
+}
+
+// deprioritizeNearLimit 将接近限流的端点稳定地移到列表末尾，其余端点保持原有顺序
+func (m *Manager) deprioritizeNearLimit(endpoints []*Endpoint) []*Endpoint {
+	if len(endpoints) < 2 {
+		return endpoints
+	}
+
+	result := make([]*Endpoint, 0, len(endpoints))
+	var nearLimit []*Endpoint
+	for _, ep := range endpoints {
+		if m.IsNearRateLimit(ep.Config.Name) {
+			nearLimit = append(nearLimit, ep)
+			continue
+		}
+		result = append(result, ep)
+	}
+	for _, ep := range nearLimit {
+		slog.Debug(fmt.Sprintf("📉 [端点选择] 端点 %s 剩余额度接近上限，降低选择顺序", ep.Config.Name))
+	}
+	return append(result, nearLimit...)
+}
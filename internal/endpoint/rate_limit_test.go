@@ -0,0 +1,190 @@
+package endpoint
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+func rateLimitHeader(pairs ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		h.Set(pairs[i], pairs[i+1])
+	}
+	return h
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := rateLimitHeader(
+		"anthropic-ratelimit-requests-limit", "50",
+		"anthropic-ratelimit-requests-remaining", "3",
+		"anthropic-ratelimit-requests-reset", now.Add(20*time.Second).Format(time.RFC3339),
+		"anthropic-ratelimit-tokens-limit", "40000",
+		"anthropic-ratelimit-tokens-remaining", "0",
+		"anthropic-ratelimit-tokens-reset", now.Add(45*time.Second).Format(time.RFC3339),
+	)
+
+	snapshot, ok := ParseRateLimitHeaders(h, http.StatusOK, now)
+	if !ok {
+		t.Fatal("应解析出限流信息")
+	}
+	if snapshot.Requests.Limit != 50 || snapshot.Requests.Remaining != 3 {
+		t.Errorf("请求额度不符: %+v", snapshot.Requests)
+	}
+	if !snapshot.Tokens.Reset.Equal(now.Add(45 * time.Second)) {
+		t.Errorf("Token 重置时间不符: %v", snapshot.Tokens.Reset)
+	}
+	if snapshot.InputTokens.Limit != 0 {
+		t.Errorf("未返回的额度应为空: %+v", snapshot.InputTokens)
+	}
+	if snapshot.RetryAfter != 0 {
+		t.Errorf("成功响应不应有等待时间: %v", snapshot.RetryAfter)
+	}
+
+	// 429 未带 Retry-After 时使用已耗尽额度的重置时间
+	snapshot, _ = ParseRateLimitHeaders(h, http.StatusTooManyRequests, now)
+	if snapshot.RetryAfter != 45*time.Second || !snapshot.RetryAt.Equal(now.Add(45*time.Second)) {
+		t.Errorf("等待时间应取耗尽额度的重置时间，实际 %v", snapshot.RetryAfter)
+	}
+
+	// Retry-After 优先
+	h.Set("Retry-After", "7")
+	snapshot, _ = ParseRateLimitHeaders(h, http.StatusTooManyRequests, now)
+	if snapshot.RetryAfter != 7*time.Second {
+		t.Errorf("应使用 Retry-After，实际 %v", snapshot.RetryAfter)
+	}
+
+	if _, ok := ParseRateLimitHeaders(http.Header{}, http.StatusTooManyRequests, now); ok {
+		t.Error("没有限流头部时不应记录")
+	}
+}
+
+func TestRateLimitSnapshot_NearLimit(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := RateLimitSnapshot{
+		Requests: RateLimitWindow{Limit: 100, Remaining: 50, Reset: now.Add(time.Minute)},
+		Tokens:   RateLimitWindow{Limit: 1000, Remaining: 80, Reset: now.Add(time.Minute)},
+	}
+	if !snapshot.NearLimit(now, 0.1) {
+		t.Error("Token 剩余 8% 应视为接近上限")
+	}
+	if snapshot.NearLimit(now, 0.05) {
+		t.Error("余量比例 5% 时不应视为接近上限")
+	}
+	if snapshot.NearLimit(now.Add(2*time.Minute), 0.1) {
+		t.Error("过了重置时间的额度应视为已恢复")
+	}
+
+	limited := RateLimitSnapshot{RetryAt: now.Add(time.Second)}
+	if !limited.NearLimit(now, 0.1) {
+		t.Error("限流等待中应视为接近上限")
+	}
+}
+
+func TestRateLimitTracker_Record(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewRateLimitTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("ep", rateLimitHeader(
+		"anthropic-ratelimit-requests-limit", "50",
+		"anthropic-ratelimit-requests-remaining", "40",
+	), http.StatusOK)
+
+	// 只带 Retry-After 的 429 保留上一次的额度
+	snapshot, ok := tracker.Record("ep", rateLimitHeader("Retry-After", "30"), http.StatusTooManyRequests)
+	if !ok || snapshot.RetryAfter != 30*time.Second || snapshot.Requests.Remaining != 40 {
+		t.Fatalf("快照不符: ok=%v %+v", ok, snapshot)
+	}
+
+	// 没有限流头部的成功响应清除等待
+	if _, ok := tracker.Record("ep", http.Header{}, http.StatusOK); ok {
+		t.Error("没有限流头部的响应不应视为携带限流信息")
+	}
+	snapshot = tracker.Snapshot("ep")
+	if !snapshot.RetryAt.IsZero() || snapshot.StatusCode != http.StatusOK || snapshot.Requests.Limit != 50 {
+		t.Errorf("成功响应后应清除等待并保留额度: %+v", snapshot)
+	}
+
+	tracker.Remove("ep")
+	if !tracker.Snapshot("ep").IsZero() {
+		t.Error("删除后快照应为空")
+	}
+}
+
+func TestManager_RecordRateLimitCooldownAndSelection(t *testing.T) {
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: StrategyWeighted},
+		Failover: config.FailoverConfig{Enabled: true},
+		Endpoints: []config.EndpointConfig{
+			{Name: "primary", URL: "https://primary.example.com", Priority: 1},
+			{Name: "secondary", URL: "https://secondary.example.com", Priority: 1},
+			{Name: "tertiary", URL: "https://tertiary.example.com", Priority: 2},
+		},
+	}
+	m := NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("primary"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+
+	// 剩余额度接近上限的端点排到末尾
+	primary := m.GetEndpointByNameAny("primary")
+	m.RecordRateLimit(primary, http.StatusOK, rateLimitHeader(
+		"anthropic-ratelimit-requests-limit", "100",
+		"anthropic-ratelimit-requests-remaining", "5",
+		"anthropic-ratelimit-requests-reset", time.Now().Add(time.Minute).Format(time.RFC3339),
+	))
+	healthy := m.GetHealthyEndpoints()
+	if len(healthy) != 3 || healthy[2].Config.Name != "primary" {
+		t.Fatalf("接近限流的端点应排在末尾: %v", endpointNames(healthy))
+	}
+	if healthy[0].Config.Name != "secondary" || healthy[1].Config.Name != "tertiary" {
+		t.Errorf("其余端点应保持原有顺序: %v", endpointNames(healthy))
+	}
+
+	// 429 按 Retry-After 冷却端点
+	secondary := m.GetEndpointByNameAny("secondary")
+	if wait := m.RecordRateLimit(secondary, http.StatusTooManyRequests, rateLimitHeader("Retry-After", "20")); wait != 20*time.Second {
+		t.Errorf("返回的等待时间不符: %v", wait)
+	}
+	if until := m.GetEndpointStatus("secondary").CooldownUntil; time.Until(until) < 19*time.Second {
+		t.Errorf("端点应冷却约 20s，实际截止 %v", until)
+	}
+	if wait := m.RateLimitRetryAfter("secondary"); wait <= 19*time.Second || wait > 20*time.Second {
+		t.Errorf("剩余等待时间不符: %v", wait)
+	}
+	for _, ep := range m.GetHealthyEndpoints() {
+		if ep.Config.Name == "secondary" {
+			t.Error("冷却中的端点不应被选择")
+		}
+	}
+
+	// 启用 Key 轮换的多 Key 端点只冷却 Key
+	cfg.KeyRotation.Enabled = true
+	tertiary := m.GetEndpointByNameAny("tertiary")
+	tertiary.Config.Tokens = []config.TokenConfig{{Value: "a"}, {Value: "b"}}
+	m.RecordRateLimit(tertiary, http.StatusTooManyRequests, rateLimitHeader("Retry-After", "20"))
+	if !m.GetEndpointStatus("tertiary").CooldownUntil.IsZero() {
+		t.Error("多 Key 端点不应整体冷却")
+	}
+
+	// 请求级故障转移：普通端点按 Retry-After 冷却，多 Key 端点使用默认冷却时间
+	cfg.Failover = config.FailoverConfig{Enabled: true, DefaultCooldown: 5 * time.Minute}
+	if _, err := m.TriggerRequestFailover("secondary", "HTTP 429"); err != nil {
+		t.Fatalf("故障转移失败: %v", err)
+	}
+	if remaining := time.Until(m.GetEndpointStatus("secondary").CooldownUntil); remaining > 20*time.Second {
+		t.Errorf("普通端点应按 Retry-After 冷却，实际剩余 %v", remaining)
+	}
+	m.TriggerRequestFailover("tertiary", "HTTP 429")
+	if remaining := time.Until(m.GetEndpointStatus("tertiary").CooldownUntil); remaining < 4*time.Minute {
+		t.Errorf("多 Key 端点不应按 Retry-After 冷却，实际剩余 %v", remaining)
+	}
+}
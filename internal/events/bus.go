@@ -21,6 +21,9 @@ type EventBus interface {
 
 	// 获取统计信息
 	GetStats() BusStats
+
+	// 订阅事件，返回事件通道和取消订阅函数
+	Subscribe(filter SubscriptionFilter) (<-chan Event, func())
 }
 
 // SSE 广播器接口
@@ -56,6 +59,12 @@ type eventBus struct {
 	filters      map[EventType]EventFilter
 	rateLimiters map[EventType]*rateLimiter
 
+	// 订阅者
+	subscribers map[uint64]*subscriber
+	nextSubID   uint64
+	stopped     bool
+	subMu       sync.RWMutex
+
 	// 统计信息
 	stats   BusStats
 	statsMu sync.RWMutex
@@ -73,6 +82,10 @@ type BusStats struct {
 	EventsByType     map[EventType]int64      `json:"events_by_type"`
 	EventsByPriority map[EventPriority]int64  `json:"events_by_priority"`
 	StartTime        time.Time                `json:"start_time"`
+
+	// 订阅者缓冲区满被丢弃的事件数（按订阅者累计）
+	SubscriberDroppedEvents int64             `json:"subscriber_dropped_events"`
+	Subscribers             []SubscriberStats `json:"subscribers"`
 }
 
 // 频率限制器
@@ -105,6 +118,7 @@ func NewEventBus(logger *slog.Logger) EventBus {
 		eventChan:    make(chan Event, 1000), // 缓冲区大小
 		filters:      make(map[EventType]EventFilter),
 		rateLimiters: make(map[EventType]*rateLimiter),
+		subscribers:  make(map[uint64]*subscriber),
 		stats: BusStats{
 			EventsByType:     make(map[EventType]int64),
 			EventsByPriority: make(map[EventPriority]int64),
@@ -139,6 +153,13 @@ func (eb *eventBus) setupDefaultFilters() {
 		RateLimit:       100 * time.Millisecond,
 	}
 
+	// 挂起超时事件过滤器 - 运维告警事件，立即推送
+	eb.filters[EventRequestSuspendTimeout] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0, // 无限制
+	}
+
 	// 端点健康事件过滤器 - 关键事件，立即推送
 	eb.filters[EventEndpointHealthy] = EventFilter{
 		ShouldBroadcast: func(event Event) bool { return true },
@@ -152,6 +173,12 @@ func (eb *eventBus) setupDefaultFilters() {
 		RateLimit:       0, // 无限制
 	}
 
+	eb.filters[EventEndpointCircuitChanged] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0, // 无限制
+	}
+
 	// 连接统计事件过滤器 - 低优先级，限制频率
 	eb.filters[EventConnectionStats] = EventFilter{
 		ShouldBroadcast: func(event Event) bool { return true },
@@ -185,6 +212,19 @@ func (eb *eventBus) setupDefaultFilters() {
 		RateLimit:       0, // 无限制
 	}
 
+	// 预算事件过滤器 - 状态变化时才发布，立即推送
+	eb.filters[EventBudgetSoftLimitExceeded] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0, // 无限制
+	}
+
+	eb.filters[EventBudgetHardLimitExceeded] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0, // 无限制
+	}
+
 	// 系统统计更新事件过滤器 - 系统级统计数据，适度限制频率
 	eb.filters[EventSystemStatsUpdated] = EventFilter{
 		ShouldBroadcast: func(event Event) bool { return true },
@@ -206,6 +246,13 @@ func (eb *eventBus) setupDefaultFilters() {
 		RateLimit:       0, // 暂时移除频率限制用于调试
 	}
 
+	// 组冷却事件过滤器 - 运维告警事件，立即推送
+	eb.filters[EventGroupCooldown] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0, // 无限制
+	}
+
 	// 初始化频率限制器
 	for eventType, filter := range eb.filters {
 		if filter.RateLimit > 0 {
@@ -270,6 +317,7 @@ func (eb *eventBus) Stop() error {
 	close(eb.eventChan)
 
 	eb.wg.Wait()
+	eb.closeSubscribers()
 
 	eb.logger.Info("EventBus stopped")
 	return nil
@@ -277,17 +325,21 @@ func (eb *eventBus) Stop() error {
 
 // GetStats 获取统计信息
 func (eb *eventBus) GetStats() BusStats {
+	subscribers := eb.subscriberStats()
+
 	eb.statsMu.RLock()
 	defer eb.statsMu.RUnlock()
 
 	// 深拷贝统计信息
 	stats := BusStats{
-		TotalEvents:      eb.stats.TotalEvents,
-		ProcessedEvents:  eb.stats.ProcessedEvents,
-		DroppedEvents:    eb.stats.DroppedEvents,
-		EventsByType:     make(map[EventType]int64),
-		EventsByPriority: make(map[EventPriority]int64),
-		StartTime:        eb.stats.StartTime,
+		TotalEvents:             eb.stats.TotalEvents,
+		ProcessedEvents:         eb.stats.ProcessedEvents,
+		DroppedEvents:           eb.stats.DroppedEvents,
+		EventsByType:            make(map[EventType]int64),
+		EventsByPriority:        make(map[EventPriority]int64),
+		StartTime:               eb.stats.StartTime,
+		SubscriberDroppedEvents: eb.stats.SubscriberDroppedEvents,
+		Subscribers:             subscribers,
 	}
 
 	for k, v := range eb.stats.EventsByType {
@@ -325,14 +377,17 @@ func (eb *eventBus) eventProcessor() {
 
 // 处理单个事件
 func (eb *eventBus) processEvent(event Event) {
+	// 更新处理统计
+	eb.updateStats(event, "processed")
+
+	// 订阅者不受 SSE 过滤器和频率限制影响，由订阅方自行合并
+	eb.deliverToSubscribers(event)
+
 	// v5.0 优化：Wails 桌面应用不使用 SSE 广播，提前返回避免无用的过滤/限流操作
 	if eb.sseBroadcaster == nil {
 		return
 	}
 
-	// 更新处理统计
-	eb.updateStats(event, "processed")
-
 	// 获取事件过滤器
 	filter, exists := eb.filters[event.Type]
 	if !exists {
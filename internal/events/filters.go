@@ -12,14 +12,22 @@ type FilterManager struct {
 	rateLimiters map[EventType]*rateLimiter
 	mu           sync.RWMutex
 	logger       *slog.Logger
+
+	// 按事件主体（请求、端点或组）计算的频率限制器，见 Apply
+	subjectLimiters map[string]*rateLimiter
+	subjectMu       sync.Mutex
 }
 
+// 主体频率限制器超过该数量时清理已过限制周期的条目
+const maxSubjectLimiters = 1024
+
 // NewFilterManager 创建新的过滤器管理器
 func NewFilterManager(logger *slog.Logger) *FilterManager {
 	fm := &FilterManager{
-		filters:      make(map[EventType]EventFilter),
-		rateLimiters: make(map[EventType]*rateLimiter),
-		logger:       logger,
+		filters:         make(map[EventType]EventFilter),
+		rateLimiters:    make(map[EventType]*rateLimiter),
+		subjectLimiters: make(map[string]*rateLimiter),
+		logger:          logger,
 	}
 
 	// 设置默认过滤器
@@ -66,6 +74,13 @@ func (fm *FilterManager) setupDefaultFilters() {
 	fm.filters[EventRequestUpdated] = requestFilter
 	fm.filters[EventRequestCompleted] = requestFilter
 
+	// 挂起超时事件过滤器 - 运维告警事件，无限制
+	fm.filters[EventRequestSuspendTimeout] = EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       0,
+	}
+
 	// 端点健康事件过滤器 - 关键事件，无限制
 	endpointFilter := EventFilter{
 		ShouldBroadcast: func(event Event) bool { return true },
@@ -90,6 +105,7 @@ func (fm *FilterManager) setupDefaultFilters() {
 
 	fm.filters[EventEndpointHealthy] = endpointFilter
 	fm.filters[EventEndpointUnhealthy] = endpointFilter
+	fm.filters[EventEndpointCircuitChanged] = endpointFilter
 
 	// 连接统计事件过滤器 - 低优先级，控制频率
 	connectionFilter := EventFilter{
@@ -127,6 +143,18 @@ func (fm *FilterManager) setupDefaultFilters() {
 
 	fm.filters[EventConnectionStats] = connectionFilter
 
+	// 组事件过滤器 - 状态变化适度限制，冷却和健康统计立即推送
+	groupFilter := EventFilter{
+		ShouldBroadcast: func(event Event) bool { return true },
+		DataTransformer: func(event Event) map[string]interface{} { return event.Data },
+		RateLimit:       100 * time.Millisecond,
+	}
+
+	fm.filters[EventGroupStatusChanged] = groupFilter
+	groupFilter.RateLimit = 0
+	fm.filters[EventGroupHealthStatsChanged] = groupFilter
+	fm.filters[EventGroupCooldown] = groupFilter
+
 	// 响应接收事件过滤器
 	responseFilter := EventFilter{
 		ShouldBroadcast: func(event Event) bool {
@@ -163,6 +191,8 @@ func (fm *FilterManager) setupDefaultFilters() {
 
 	fm.filters[EventSystemError] = systemFilter
 	fm.filters[EventConfigChanged] = systemFilter
+	fm.filters[EventBudgetSoftLimitExceeded] = systemFilter
+	fm.filters[EventBudgetHardLimitExceeded] = systemFilter
 
 	// 初始化频率限制器
 	for eventType, filter := range fm.filters {
@@ -192,6 +222,67 @@ func (fm *FilterManager) GetRateLimiter(eventType EventType) (*rateLimiter, bool
 	return limiter, exists
 }
 
+// Apply 按过滤器处理事件，返回转换后的数据；没有过滤器、被过滤或被限流时返回 false
+// 频率限制按事件类型和事件主体（请求 ID、端点或组）分别计算：
+// 同一请求的高频状态更新被限流，不同请求、端点的事件互不影响
+func (fm *FilterManager) Apply(event Event) (map[string]interface{}, bool) {
+	filter, exists := fm.GetFilter(event.Type)
+	if !exists {
+		return nil, false
+	}
+	if filter.ShouldBroadcast != nil && !filter.ShouldBroadcast(event) {
+		return nil, false
+	}
+	if filter.RateLimit > 0 && !fm.allowSubject(event, filter.RateLimit) {
+		return nil, false
+	}
+	if filter.DataTransformer == nil {
+		return event.Data, true
+	}
+	return filter.DataTransformer(event), true
+}
+
+// allowSubject 检查事件主体的频率限制
+func (fm *FilterManager) allowSubject(event Event, limit time.Duration) bool {
+	key := string(event.Type) + "|" + EventSubject(event)
+
+	fm.subjectMu.Lock()
+	defer fm.subjectMu.Unlock()
+
+	limiter, exists := fm.subjectLimiters[key]
+	if !exists {
+		if len(fm.subjectLimiters) >= maxSubjectLimiters {
+			fm.pruneSubjectLimiters()
+		}
+		limiter = &rateLimiter{limit: limit}
+		fm.subjectLimiters[key] = limiter
+	}
+	return limiter.Allow()
+}
+
+// pruneSubjectLimiters 删除已过限制周期的主体频率限制器（调用方持有 subjectMu）
+func (fm *FilterManager) pruneSubjectLimiters() {
+	now := time.Now()
+	for key, limiter := range fm.subjectLimiters {
+		limiter.mu.Lock()
+		expired := now.Sub(limiter.lastTime) >= limiter.limit
+		limiter.mu.Unlock()
+		if expired {
+			delete(fm.subjectLimiters, key)
+		}
+	}
+}
+
+// EventSubject 事件主体：请求事件为请求 ID，端点事件为端点名称，组事件为组名称
+func EventSubject(event Event) string {
+	for _, key := range []string{"request_id", "endpoint", "endpoint_name", "group"} {
+		if value, ok := event.Data[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // SetCustomFilter 设置自定义过滤器
 func (fm *FilterManager) SetCustomFilter(eventType EventType, filter EventFilter) {
 	fm.mu.Lock()
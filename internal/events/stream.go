@@ -0,0 +1,364 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 事件流默认参数
+const (
+	// 回放缓冲区默认保留的事件数
+	defaultStreamReplaySize = 1000
+	// 单个客户端的发送缓冲区，写入跟不上时断开连接，客户端可凭 Last-Event-ID 续传
+	streamClientBuffer = 256
+	// 心跳间隔（SSE 注释行，防止空闲连接被中间代理断开）
+	streamHeartbeatInterval = 15 * time.Second
+	// 客户端断线后的建议重连间隔（毫秒）
+	streamRetryMillis = 3000
+)
+
+// streamCategories 事件流推送的事件分类
+var streamCategories = []string{"request", "endpoint", "group"}
+
+// StreamEvent 事件流中的一条事件
+type StreamEvent struct {
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	Category  string                 `json:"category"`
+	Source    string                 `json:"source"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+
+	seq uint64
+}
+
+// StreamFilter 客户端的事件过滤条件（留空的条件不限制）
+type StreamFilter struct {
+	// 事件类型或分类（request / endpoint / group）
+	Types []string
+	// 端点名称，只推送与这些端点相关的事件
+	Endpoints []string
+}
+
+// ParseStreamFilter 从查询参数解析过滤条件：types=request_updated,endpoint&endpoint=a,b
+func ParseStreamFilter(query url.Values) (StreamFilter, error) {
+	filter := StreamFilter{
+		Types:     splitQueryList(query["types"]),
+		Endpoints: splitQueryList(query["endpoint"]),
+	}
+	for _, t := range filter.Types {
+		if containsString(streamCategories, t) {
+			continue
+		}
+		category, ok := EventTypeMapping[EventType(t)]
+		if !ok || !containsString(streamCategories, category) {
+			return filter, fmt.Errorf("不支持的事件类型: %s", t)
+		}
+	}
+	return filter, nil
+}
+
+// splitQueryList 拆分逗号分隔的查询参数（参数可重复）
+func splitQueryList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// Matches 事件是否满足过滤条件
+func (f StreamFilter) Matches(event StreamEvent) bool {
+	if len(f.Types) > 0 && !containsString(f.Types, string(event.Type)) && !containsString(f.Types, event.Category) {
+		return false
+	}
+	if len(f.Endpoints) > 0 && !containsString(f.Endpoints, streamEventEndpoint(event)) {
+		return false
+	}
+	return true
+}
+
+// streamEventEndpoint 事件关联的端点名称（请求开始时尚未选择端点，组事件没有端点）
+func streamEventEndpoint(event StreamEvent) string {
+	for _, key := range []string{"endpoint", "endpoint_name"} {
+		if value, ok := event.Data[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// streamClient 事件流客户端
+type streamClient struct {
+	filter StreamFilter
+	ch     chan StreamEvent
+	closed bool
+}
+
+// StreamHub 事件流中心
+// 订阅 EventBus 的请求、端点和组事件，经 FilterManager 过滤和限流后分配递增 ID，
+// 保存在有界回放缓冲区中并分发给各 SSE 客户端
+type StreamHub struct {
+	bus     EventBus
+	filters *FilterManager
+
+	// 事件 ID 格式为 "<启动时间>-<序号>"，重启后旧 ID 不会被误认为可续传
+	epoch string
+
+	mu      sync.Mutex
+	seq     uint64
+	replay  []StreamEvent // 环形缓冲区
+	start   int           // 最旧事件在 replay 中的位置
+	size    int
+	clients map[*streamClient]struct{}
+	stopped bool
+
+	unsubscribe func()
+	done        chan struct{}
+}
+
+// NewStreamHub 创建事件流中心，replaySize <= 0 时使用默认回放缓冲区大小
+func NewStreamHub(bus EventBus, filters *FilterManager, replaySize int) *StreamHub {
+	if replaySize <= 0 {
+		replaySize = defaultStreamReplaySize
+	}
+	return &StreamHub{
+		bus:     bus,
+		filters: filters,
+		epoch:   strconv.FormatInt(time.Now().Unix(), 10),
+		replay:  make([]StreamEvent, replaySize),
+		clients: make(map[*streamClient]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start 订阅 EventBus 并开始分发
+func (h *StreamHub) Start() {
+	ch, unsubscribe := h.bus.Subscribe(SubscriptionFilter{
+		Name:       "event_stream",
+		Categories: streamCategories,
+		BufferSize: 1024,
+	})
+	h.unsubscribe = unsubscribe
+
+	go func() {
+		defer close(h.done)
+		for event := range ch {
+			if data, ok := h.filters.Apply(event); ok {
+				h.publish(event, data)
+			}
+		}
+		h.closeClients()
+	}()
+}
+
+// Stop 取消订阅并关闭所有客户端连接
+func (h *StreamHub) Stop() {
+	if h.unsubscribe == nil {
+		return
+	}
+	h.unsubscribe()
+	<-h.done
+}
+
+// ClientCount 当前连接的客户端数
+func (h *StreamHub) ClientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// publish 分配 ID，写入回放缓冲区并分发给匹配的客户端
+func (h *StreamHub) publish(event Event, data map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	streamEvent := StreamEvent{
+		ID:        h.epoch + "-" + strconv.FormatUint(h.seq, 10),
+		Type:      event.Type,
+		Category:  EventTypeMapping[event.Type],
+		Source:    event.Source,
+		Timestamp: event.Timestamp,
+		Data:      data,
+		seq:       h.seq,
+	}
+
+	if h.size < len(h.replay) {
+		h.replay[(h.start+h.size)%len(h.replay)] = streamEvent
+		h.size++
+	} else {
+		h.replay[h.start] = streamEvent
+		h.start = (h.start + 1) % len(h.replay)
+	}
+
+	for client := range h.clients {
+		if !client.filter.Matches(streamEvent) {
+			continue
+		}
+		select {
+		case client.ch <- streamEvent:
+		default:
+			// 客户端消费过慢：断开连接，由客户端凭 Last-Event-ID 重连续传
+			h.removeClient(client)
+		}
+	}
+}
+
+// subscribe 注册客户端并返回需要回放的事件
+// gap 为 true 表示 Last-Event-ID 已超出回放范围（或来自服务重启前），客户端应重新拉取完整状态
+func (h *StreamHub) subscribe(filter StreamFilter, lastEventID string) (client *streamClient, replay []StreamEvent, gap bool) {
+	client = &streamClient{filter: filter, ch: make(chan StreamEvent, streamClientBuffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		close(client.ch)
+		client.closed = true
+		return client, nil, false
+	}
+	h.clients[client] = struct{}{}
+
+	if lastEventID == "" {
+		return client, nil, false
+	}
+
+	after, ok := h.parseEventID(lastEventID)
+	oldest := h.seq - uint64(h.size) // 回放缓冲区之前的最后一个序号
+	if !ok || after > h.seq || after < oldest {
+		gap = true
+		after = oldest
+	}
+
+	for i := 0; i < h.size; i++ {
+		event := h.replay[(h.start+i)%len(h.replay)]
+		if event.seq > after && filter.Matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	return client, replay, gap
+}
+
+// parseEventID 解析本次启动分配的事件 ID，返回序号
+func (h *StreamHub) parseEventID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+// unsubscribeClient 移除客户端
+func (h *StreamHub) unsubscribeClient(client *streamClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeClient(client)
+}
+
+// removeClient 移除并关闭客户端（调用方持有 mu）
+func (h *StreamHub) removeClient(client *streamClient) {
+	delete(h.clients, client)
+	if !client.closed {
+		client.closed = true
+		close(client.ch)
+	}
+}
+
+// closeClients 关闭所有客户端（停止时）
+func (h *StreamHub) closeClients() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	for client := range h.clients {
+		h.removeClient(client)
+	}
+}
+
+// ServeHTTP 以 Server-Sent Events 推送事件
+// 查询参数 types / endpoint 过滤事件，断线重连时按 Last-Event-ID 请求头（或 last_event_id 参数）回放错过的事件
+func (h *StreamHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := ParseStreamFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	client, replay, gap := h.subscribe(filter, lastEventID)
+	defer h.unsubscribeClient(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+	if gap {
+		// 错过的事件已不在回放缓冲区中，通知客户端重新拉取完整状态
+		data, _ := json.Marshal(map[string]string{"reason": "replay_unavailable", "last_event_id": lastEventID})
+		fmt.Fprintf(w, "event: reset\ndata: %s\n\n", data)
+	}
+	for _, event := range replay {
+		if err := writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-client.ch:
+			if !ok {
+				return
+			}
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeStreamEvent 写入一条 SSE 事件（事件名为事件类型）
+func writeStreamEvent(w http.ResponseWriter, event StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
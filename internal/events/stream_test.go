@@ -0,0 +1,178 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterManager_ApplyRateLimitsPerSubject(t *testing.T) {
+	fm := NewFilterManager(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	update := func(id string) Event {
+		return Event{Type: EventRequestUpdated, Data: map[string]interface{}{"request_id": id, "client_ip": "1.2.3.4"}}
+	}
+	data, ok := fm.Apply(update("req-1"))
+	if !ok || data["client_ip"] != nil || data["request_id"] != "req-1" {
+		t.Fatalf("首个事件应通过并去除敏感字段: %v %v", ok, data)
+	}
+	if _, ok := fm.Apply(update("req-1")); ok {
+		t.Error("同一请求的高频更新应被限流")
+	}
+	if _, ok := fm.Apply(update("req-2")); !ok {
+		t.Error("不同请求的更新不应互相限流")
+	}
+
+	if _, ok := fm.Apply(Event{Type: EventRequestUpdated, Data: map[string]interface{}{"request_id": "req-3", "change_type": "heartbeat"}}); ok {
+		t.Error("心跳更新应被过滤")
+	}
+	if _, ok := fm.Apply(Event{Type: "custom"}); ok {
+		t.Error("没有过滤器的事件不应通过")
+	}
+}
+
+// publishTest 直接向事件流中心发布事件（绕过 EventBus 和限流）
+func publishTest(h *StreamHub, eventType EventType, data map[string]interface{}) {
+	h.publish(Event{Type: eventType, Timestamp: time.Now(), Data: data}, data)
+}
+
+func TestStreamFilter(t *testing.T) {
+	if _, err := ParseStreamFilter(url.Values{"types": {"request_started,bogus"}}); err == nil {
+		t.Error("未知事件类型应返回错误")
+	}
+	if _, err := ParseStreamFilter(url.Values{"types": {"system_stats_updated"}}); err == nil {
+		t.Error("不在事件流中的分类应返回错误")
+	}
+
+	filter, err := ParseStreamFilter(url.Values{"types": {"endpoint, request_completed"}, "endpoint": {"primary"}})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	cases := []struct {
+		event StreamEvent
+		want  bool
+	}{
+		{StreamEvent{Type: EventEndpointUnhealthy, Category: "endpoint", Data: map[string]interface{}{"endpoint": "primary"}}, true},
+		{StreamEvent{Type: EventEndpointUnhealthy, Category: "endpoint", Data: map[string]interface{}{"endpoint": "backup"}}, false},
+		{StreamEvent{Type: EventRequestCompleted, Category: "request", Data: map[string]interface{}{"endpoint_name": "primary"}}, true},
+		{StreamEvent{Type: EventRequestUpdated, Category: "request", Data: map[string]interface{}{"endpoint_name": "primary"}}, false},
+	}
+	for _, c := range cases {
+		if got := filter.Matches(c.event); got != c.want {
+			t.Errorf("%s %v: got %v, want %v", c.event.Type, c.event.Data, got, c.want)
+		}
+	}
+}
+
+func TestStreamHub_ReplayFromLastEventID(t *testing.T) {
+	h := NewStreamHub(nil, nil, 3)
+	for _, endpoint := range []string{"a", "b", "a", "b", "a"} {
+		publishTest(h, EventEndpointHealthy, map[string]interface{}{"endpoint": endpoint})
+	}
+
+	// 缓冲区保留最近 3 条（序号 3-5）
+	client, replay, gap := h.subscribe(StreamFilter{}, h.epoch+"-3")
+	if gap || len(replay) != 2 || replay[0].ID != h.epoch+"-4" || replay[1].ID != h.epoch+"-5" {
+		t.Errorf("应回放序号 3 之后的事件: gap=%v %+v", gap, replay)
+	}
+	h.unsubscribeClient(client)
+
+	_, replay, gap = h.subscribe(StreamFilter{Endpoints: []string{"a"}}, h.epoch+"-1")
+	if !gap || len(replay) != 2 || replay[0].ID != h.epoch+"-3" {
+		t.Errorf("超出回放范围时应标记缺口并回放整个缓冲区: gap=%v %+v", gap, replay)
+	}
+
+	if _, replay, gap = h.subscribe(StreamFilter{}, "123-4"); !gap || len(replay) != 3 {
+		t.Errorf("服务重启前的 ID 应标记缺口: gap=%v %d", gap, len(replay))
+	}
+	if _, replay, gap = h.subscribe(StreamFilter{}, h.epoch+"-5"); gap || len(replay) != 0 {
+		t.Errorf("最新 ID 不应回放: gap=%v %d", gap, len(replay))
+	}
+}
+
+func TestStreamHub_DisconnectsSlowClient(t *testing.T) {
+	h := NewStreamHub(nil, nil, 10)
+	client, _, _ := h.subscribe(StreamFilter{}, "")
+	for i := 0; i <= streamClientBuffer; i++ {
+		publishTest(h, EventEndpointHealthy, map[string]interface{}{"endpoint": "a"})
+	}
+	if h.ClientCount() != 0 {
+		t.Error("消费过慢的客户端应被断开")
+	}
+	for range client.ch {
+	}
+}
+
+func TestStreamHub_ServeHTTP(t *testing.T) {
+	bus := newTestBus(t)
+	defer bus.Stop()
+
+	h := NewStreamHub(bus, NewFilterManager(slog.New(slog.NewTextHandler(io.Discard, nil))), 10)
+	h.Start()
+	defer h.Stop()
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	if resp, err := http.Get(server.URL + "?types=bogus"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("未知事件类型应返回 400: %v", err)
+	}
+
+	resp, err := http.Get(server.URL + "?types=endpoint")
+	if err != nil {
+		t.Fatalf("连接事件流失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type 不符: %s", ct)
+	}
+
+	for deadline := time.Now().Add(time.Second); h.ClientCount() == 0 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+	bus.Publish(Event{Type: EventRequestStarted, Data: map[string]interface{}{"request_id": "req-1"}})
+	bus.Publish(Event{Type: EventEndpointUnhealthy, Data: map[string]interface{}{"endpoint": "primary"}})
+
+	lines := make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var id, name string
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("事件流意外关闭")
+			}
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				var event StreamEvent
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+					t.Fatalf("解析事件失败: %v", err)
+				}
+				if name != "endpoint_unhealthy" || event.ID != id || event.Category != "endpoint" || event.Data["endpoint"] != "primary" {
+					t.Errorf("收到的事件不符: %s %s %+v", id, name, event)
+				}
+				return
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("等待事件超时")
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// 订阅者默认缓冲区大小
+const defaultSubscriberBuffer = 256
+
+// SubscriptionFilter 订阅过滤条件（各条件同时满足才投递，留空的条件不限制）
+type SubscriptionFilter struct {
+	// 订阅者名称，用于统计和日志
+	Name string
+
+	// 只接收这些事件类型
+	Types []EventType
+
+	// 只接收这些前端分类的事件（见 EventTypeMapping，如 request / endpoint / group）
+	Categories []string
+
+	// 只接收不低于该优先级的事件
+	MinPriority EventPriority
+
+	// 订阅缓冲区大小，<= 0 时使用默认值；缓冲区满时丢弃新事件并计数
+	BufferSize int
+}
+
+// Matches 事件是否满足订阅过滤条件
+func (f SubscriptionFilter) Matches(event Event) bool {
+	if event.Priority < f.MinPriority {
+		return false
+	}
+	if len(f.Types) > 0 && !containsEventType(f.Types, event.Type) {
+		return false
+	}
+	if len(f.Categories) > 0 {
+		category, ok := EventTypeMapping[event.Type]
+		if !ok || !containsString(f.Categories, category) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsEventType(types []EventType, t EventType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriberStats 单个订阅者的投递统计
+type SubscriberStats struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Buffer    int    `json:"buffer"`
+	Pending   int    `json:"pending"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"`
+}
+
+// subscriber 事件订阅者
+type subscriber struct {
+	id        uint64
+	filter    SubscriptionFilter
+	ch        chan Event
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	closeOnce sync.Once
+}
+
+// close 关闭订阅通道（可重复调用）
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() { close(s.ch) })
+}
+
+// stats 订阅者统计快照
+func (s *subscriber) stats() SubscriberStats {
+	return SubscriberStats{
+		ID:        s.id,
+		Name:      s.filter.Name,
+		Buffer:    cap(s.ch),
+		Pending:   len(s.ch),
+		Delivered: s.delivered.Load(),
+		Dropped:   s.dropped.Load(),
+	}
+}
+
+// Subscribe 订阅事件，返回只读事件通道和取消订阅函数
+// 每个订阅者有独立的有界缓冲区，消费不及时时丢弃新事件，不会阻塞发布方和其他订阅者；
+// 取消订阅或 EventBus 停止时通道被关闭
+func (eb *eventBus) Subscribe(filter SubscriptionFilter) (<-chan Event, func()) {
+	size := filter.BufferSize
+	if size <= 0 {
+		size = defaultSubscriberBuffer
+	}
+
+	sub := &subscriber{
+		filter: filter,
+		ch:     make(chan Event, size),
+	}
+
+	eb.subMu.Lock()
+	if eb.stopped {
+		eb.subMu.Unlock()
+		sub.close()
+		return sub.ch, func() {}
+	}
+	eb.nextSubID++
+	sub.id = eb.nextSubID
+	eb.subscribers[sub.id] = sub
+	eb.subMu.Unlock()
+
+	eb.logger.Debug("EventBus subscriber added", "id", sub.id, "name", filter.Name, "buffer", size)
+
+	unsubscribe := func() {
+		eb.subMu.Lock()
+		delete(eb.subscribers, sub.id)
+		eb.subMu.Unlock()
+		sub.close()
+	}
+	return sub.ch, unsubscribe
+}
+
+// deliverToSubscribers 将事件投递给匹配的订阅者（非阻塞）
+func (eb *eventBus) deliverToSubscribers(event Event) {
+	eb.subMu.RLock()
+	defer eb.subMu.RUnlock()
+
+	for _, sub := range eb.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+			sub.delivered.Add(1)
+		default:
+			// 订阅者缓冲区满，丢弃事件
+			if sub.dropped.Add(1) == 1 {
+				eb.logger.Warn("EventBus subscriber buffer full, dropping events", "subscriber", sub.filter.Name, "type", event.Type)
+			}
+			eb.statsMu.Lock()
+			eb.stats.SubscriberDroppedEvents++
+			eb.statsMu.Unlock()
+		}
+	}
+}
+
+// closeSubscribers 关闭并移除所有订阅者（EventBus 停止时）
+func (eb *eventBus) closeSubscribers() {
+	eb.subMu.Lock()
+	defer eb.subMu.Unlock()
+
+	eb.stopped = true
+	for id, sub := range eb.subscribers {
+		sub.close()
+		delete(eb.subscribers, id)
+	}
+}
+
+// subscriberStats 所有订阅者的统计快照
+func (eb *eventBus) subscriberStats() []SubscriberStats {
+	eb.subMu.RLock()
+	defer eb.subMu.RUnlock()
+
+	result := make([]SubscriberStats, 0, len(eb.subscribers))
+	for _, sub := range eb.subscribers {
+		result = append(result, sub.stats())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestBus(t *testing.T) EventBus {
+	t.Helper()
+	bus := NewEventBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := bus.Start(); err != nil {
+		t.Fatalf("启动事件总线失败: %v", err)
+	}
+	return bus
+}
+
+func receiveEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("订阅通道已关闭")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("等待事件超时")
+	}
+	return Event{}
+}
+
+func TestSubscriptionFilter_Matches(t *testing.T) {
+	filter := SubscriptionFilter{Categories: []string{"endpoint", "group"}, MinPriority: PriorityNormal}
+	if !filter.Matches(Event{Type: EventEndpointUnhealthy, Priority: PriorityHigh}) {
+		t.Error("端点事件应匹配")
+	}
+	if filter.Matches(Event{Type: EventGroupStatusChanged, Priority: PriorityLow}) {
+		t.Error("低于最低优先级的事件不应匹配")
+	}
+	if filter.Matches(Event{Type: EventRequestStarted, Priority: PriorityHigh}) {
+		t.Error("其他分类的事件不应匹配")
+	}
+
+	byType := SubscriptionFilter{Types: []EventType{EventRequestCompleted}}
+	if !byType.Matches(Event{Type: EventRequestCompleted}) || byType.Matches(Event{Type: EventRequestStarted}) {
+		t.Error("按事件类型过滤不符")
+	}
+	if !(SubscriptionFilter{}).Matches(Event{Type: "custom"}) {
+		t.Error("空过滤条件应匹配所有事件")
+	}
+}
+
+func TestEventBus_SubscribeDeliversAndDrops(t *testing.T) {
+	bus := newTestBus(t)
+	defer bus.Stop()
+
+	requests, unsubscribeRequests := bus.Subscribe(SubscriptionFilter{Name: "requests", Categories: []string{"request"}, BufferSize: 2})
+	endpoints, _ := bus.Subscribe(SubscriptionFilter{Name: "endpoints", Categories: []string{"endpoint"}})
+
+	// 请求订阅者不消费，缓冲区满后丢弃，不影响其他订阅者
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Type: EventRequestUpdated, Data: map[string]interface{}{"seq": i}})
+	}
+	bus.Publish(Event{Type: EventEndpointUnhealthy, Data: map[string]interface{}{"endpoint": "primary"}})
+
+	if event := receiveEvent(t, endpoints); event.Data["endpoint"] != "primary" {
+		t.Errorf("端点订阅者收到的事件不符: %+v", event)
+	}
+	if event := receiveEvent(t, requests); event.Data["seq"] != 0 {
+		t.Errorf("应按发布顺序投递: %+v", event)
+	}
+
+	stats := bus.GetStats()
+	if stats.SubscriberDroppedEvents != 3 {
+		t.Errorf("丢弃计数不符: %d", stats.SubscriberDroppedEvents)
+	}
+	if len(stats.Subscribers) != 2 || stats.Subscribers[0].Name != "requests" ||
+		stats.Subscribers[0].Delivered != 2 || stats.Subscribers[0].Dropped != 3 || stats.Subscribers[0].Buffer != 2 {
+		t.Errorf("订阅者统计不符: %+v", stats.Subscribers)
+	}
+
+	unsubscribeRequests()
+	unsubscribeRequests() // 重复取消订阅是安全的
+	receiveEvent(t, requests)
+	if _, ok := <-requests; ok {
+		t.Error("取消订阅后通道应关闭")
+	}
+	if len(bus.GetStats().Subscribers) != 1 {
+		t.Error("取消订阅后应移除订阅者")
+	}
+}
+
+func TestEventBus_StopClosesSubscribers(t *testing.T) {
+	bus := newTestBus(t)
+	ch, unsubscribe := bus.Subscribe(SubscriptionFilter{})
+
+	bus.Stop()
+	if _, ok := <-ch; ok {
+		t.Error("停止后订阅通道应关闭")
+	}
+	unsubscribe()
+
+	late, _ := bus.Subscribe(SubscriptionFilter{})
+	if _, ok := <-late; ok {
+		t.Error("停止后订阅应返回已关闭的通道")
+	}
+}
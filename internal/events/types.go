@@ -11,10 +11,16 @@ const (
 	EventRequestUpdated   EventType = "request_updated"
 	EventRequestCompleted EventType = "request_completed"
 
+	// 挂起请求等待超时事件（等待组切换、端点恢复或端点容量）
+	EventRequestSuspendTimeout EventType = "request_suspend_timeout"
+
 	// 端点健康事件
 	EventEndpointHealthy   EventType = "endpoint_healthy"
 	EventEndpointUnhealthy EventType = "endpoint_unhealthy"
 
+	// 端点熔断器状态变化事件（closed/open/half_open）
+	EventEndpointCircuitChanged EventType = "endpoint_circuit_changed"
+
 	// 连接统计事件
 	EventConnectionStats        EventType = "connection_stats"
 	EventConnectionStatsUpdated EventType = "connection_stats_updated"
@@ -24,10 +30,17 @@ const (
 	EventGroupStatusChanged      EventType = "group_status_changed"
 	EventGroupHealthStatsChanged EventType = "group_health_stats_changed"
 
+	// 组（端点）进入冷却事件
+	EventGroupCooldown EventType = "group_cooldown"
+
 	// 系统级事件
 	EventSystemError        EventType = "system_error"
 	EventSystemStatsUpdated EventType = "system_stats_updated"
 	EventConfigChanged      EventType = "config_changed"
+
+	// 预算事件
+	EventBudgetSoftLimitExceeded EventType = "budget_soft_limit_exceeded"
+	EventBudgetHardLimitExceeded EventType = "budget_hard_limit_exceeded"
 )
 
 // 事件优先级
@@ -55,14 +68,19 @@ var EventTypeMapping = map[EventType]string{
 	EventRequestStarted:          "request",
 	EventRequestUpdated:          "request",
 	EventRequestCompleted:        "request",
+	EventRequestSuspendTimeout:   "request",
 	EventEndpointHealthy:         "endpoint",
 	EventEndpointUnhealthy:       "endpoint",
+	EventEndpointCircuitChanged:  "endpoint",
 	EventConnectionStats:         "connection",
 	EventConnectionStatsUpdated:  "connection",
 	EventResponseReceived:        "connection",
 	EventGroupStatusChanged:      "group",
 	EventGroupHealthStatsChanged: "group",
+	EventGroupCooldown:           "group",
 	EventSystemError:             "status",
 	EventSystemStatsUpdated:      "status",
 	EventConfigChanged:           "config",
+	EventBudgetSoftLimitExceeded: "budget",
+	EventBudgetHardLimitExceeded: "budget",
 }
\ No newline at end of file
@@ -2,12 +2,33 @@ package middleware
 
 import (
 	"cc-forwarder/config"
+	"context"
 	"net/http"
 	"strings"
 )
 
+// ClientKeyResolver 校验客户端 Key 并返回其名称（多客户端 Key 鉴权）
+type ClientKeyResolver interface {
+	ResolveClientKey(token string) (name string, ok bool)
+}
+
+// clientKeyContextKey 请求上下文中客户端 Key 名称的键
+type clientKeyContextKey struct{}
+
+// WithClientKey 将鉴权通过的客户端 Key 名称写入上下文
+func WithClientKey(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, clientKeyContextKey{}, name)
+}
+
+// ClientKeyFromContext 获取鉴权使用的客户端 Key 名称（使用全局 Token 或未启用鉴权时为空）
+func ClientKeyFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(clientKeyContextKey{}).(string)
+	return name
+}
+
 type AuthMiddleware struct {
-	config config.AuthConfig
+	config   config.AuthConfig
+	resolver ClientKeyResolver
 }
 
 func NewAuthMiddleware(cfg config.AuthConfig) *AuthMiddleware {
@@ -16,6 +37,11 @@ func NewAuthMiddleware(cfg config.AuthConfig) *AuthMiddleware {
 	}
 }
 
+// SetClientKeyResolver sets the resolver used to accept per-client keys in addition to the global token
+func (am *AuthMiddleware) SetClientKeyResolver(resolver ClientKeyResolver) {
+	am.resolver = resolver
+}
+
 func (am *AuthMiddleware) Wrap(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !am.config.Enabled {
@@ -35,16 +61,24 @@ func (am *AuthMiddleware) Wrap(next http.Handler) http.Handler {
 		}
 
 		token := strings.TrimPrefix(auth, "Bearer ")
-		if token != am.config.Token {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+		if am.config.Token != "" && token == am.config.Token {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// 客户端 Key：记录 Key 名称用于按使用者统计
+		if am.resolver != nil {
+			if name, ok := am.resolver.ResolveClientKey(token); ok {
+				next.ServeHTTP(w, r.WithContext(WithClientKey(r.Context(), name)))
+				return
+			}
+		}
+
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
 	})
 }
 
 // UpdateConfig updates the auth middleware configuration
 func (am *AuthMiddleware) UpdateConfig(cfg config.AuthConfig) {
 	am.config = cfg
-}
\ No newline at end of file
+}
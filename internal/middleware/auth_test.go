@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cc-forwarder/config"
+)
+
+// staticClientKeyResolver 测试用客户端 Key 解析器：Key 值 -> 名称
+type staticClientKeyResolver map[string]string
+
+func (r staticClientKeyResolver) ResolveClientKey(token string) (string, bool) {
+	name, ok := r[token]
+	return name, ok
+}
+
+// TestAuthMiddleware_ClientKeys 测试全局 Token 与客户端 Key 鉴权
+func TestAuthMiddleware_ClientKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		authHeader string
+		wantStatus int
+		wantKey    string
+	}{
+		{"全局 Token", "global-secret", "Bearer global-secret", http.StatusOK, ""},
+		{"客户端 Key", "global-secret", "Bearer alice-key", http.StatusOK, "alice"},
+		{"未配置全局 Token 时使用客户端 Key", "", "Bearer alice-key", http.StatusOK, "alice"},
+		{"未知 Key", "global-secret", "Bearer unknown", http.StatusUnauthorized, ""},
+		{"未配置全局 Token 时拒绝空 Token", "", "Bearer ", http.StatusUnauthorized, ""},
+		{"缺少 Authorization", "global-secret", "", http.StatusUnauthorized, ""},
+		{"格式错误", "global-secret", "Basic alice-key", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := NewAuthMiddleware(config.AuthConfig{Enabled: true, Token: tt.token})
+			am.SetClientKeyResolver(staticClientKeyResolver{"alice-key": "alice"})
+
+			var gotKey string
+			handler := am.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotKey = ClientKeyFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("状态码 = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotKey != tt.wantKey {
+				t.Errorf("客户端 Key 名称 = %q, want %q", gotKey, tt.wantKey)
+			}
+		})
+	}
+}
+
+// TestAuthMiddleware_Disabled 测试未启用鉴权时直接放行
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	am := NewAuthMiddleware(config.AuthConfig{Enabled: false})
+	called := false
+	handler := am.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if name := ClientKeyFromContext(r.Context()); name != "" {
+			t.Errorf("未启用鉴权时不应有客户端 Key 名称: %q", name)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	if !called {
+		t.Error("未启用鉴权时应放行请求")
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime"
+	"strconv"
 	"time"
 
 	"cc-forwarder/internal/endpoint"
 	"cc-forwarder/internal/events"
 	"cc-forwarder/internal/monitor"
+	"cc-forwarder/internal/tracking"
+	"cc-forwarder/internal/transport"
 )
 
 // MonitoringMiddleware provides health and metrics endpoints
@@ -18,8 +21,10 @@ type MonitoringMiddleware struct {
 	endpointManager *endpoint.Manager
 	metrics         *monitor.Metrics
 	eventBus        events.EventBus
+	usageTracker    *tracking.UsageTracker
 	lastBroadcast   map[string]time.Time
 	startTime       time.Time
+	unsubscribe     func() // 端点事件订阅的取消函数
 }
 
 // NewMonitoringMiddleware creates a new monitoring middleware
@@ -37,6 +42,11 @@ func (mm *MonitoringMiddleware) SetEventBus(eventBus events.EventBus) {
 	mm.eventBus = eventBus
 }
 
+// SetUsageTracker 设置使用跟踪器（用于导出热池和归档队列指标）
+func (mm *MonitoringMiddleware) SetUsageTracker(tracker *tracking.UsageTracker) {
+	mm.usageTracker = tracker
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status    string              `json:"status"`
@@ -150,49 +160,170 @@ func (mm *MonitoringMiddleware) handleDetailedHealth(w http.ResponseWriter, r *h
 	json.NewEncoder(w).Encode(response)
 }
 
-// handleMetrics handles metrics endpoint
+// handleMetrics handles metrics endpoint (Prometheus text exposition)
 func (mm *MonitoringMiddleware) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	w.Header().Set("Content-Type", PrometheusContentType)
+
+	p := &promWriter{w: w}
+	mm.writeEndpointMetrics(p)
+	mm.writeRequestMetrics(p)
+	mm.writeTrackingMetrics(p)
+	mm.writeTransportPoolMetrics(p)
+}
+
+// writeEndpointMetrics writes endpoint health gauges
+func (mm *MonitoringMiddleware) writeEndpointMetrics(p *promWriter) {
 	endpoints := mm.endpointManager.GetAllEndpoints()
-	
-	w.Header().Set("Content-Type", "text/plain")
-	
-	// Basic Prometheus-style metrics
-	fmt.Fprintf(w, "# HELP endpoint_forwarder_endpoints_total Total number of configured endpoints\n")
-	fmt.Fprintf(w, "# TYPE endpoint_forwarder_endpoints_total gauge\n")
-	fmt.Fprintf(w, "endpoint_forwarder_endpoints_total %d\n", len(endpoints))
-	
-	fmt.Fprintf(w, "# HELP endpoint_forwarder_endpoints_healthy Number of healthy endpoints\n")
-	fmt.Fprintf(w, "# TYPE endpoint_forwarder_endpoints_healthy gauge\n")
-	
+
+	var healthy, responseTime, consecutiveFails []promSample
 	healthyCount := 0
 	for _, ep := range endpoints {
-		if ep.IsHealthy() {
+		status := ep.GetStatus()
+		if status.Healthy {
 			healthyCount++
 		}
-		
-		// Individual endpoint metrics
-		healthy := 0
-		if ep.IsHealthy() {
-			healthy = 1
+
+		labels := []string{"name", ep.Config.Name, "url", ep.Config.URL}
+		healthy = append(healthy, promSample{
+			labels: []string{"name", ep.Config.Name, "url", ep.Config.URL, "priority", strconv.Itoa(ep.Config.Priority)},
+			value:  boolValue(status.Healthy),
+		})
+		responseTime = append(responseTime, promSample{labels: labels, value: float64(status.ResponseTime.Milliseconds())})
+		consecutiveFails = append(consecutiveFails, promSample{labels: labels, value: float64(status.ConsecutiveFails)})
+	}
+
+	p.family("endpoint_forwarder_endpoints_total", "Total number of configured endpoints", "gauge",
+		[]promSample{{value: float64(len(endpoints))}})
+	p.family("endpoint_forwarder_endpoints_healthy", "Number of healthy endpoints", "gauge",
+		[]promSample{{value: float64(healthyCount)}})
+	p.family("endpoint_forwarder_endpoint_healthy", "Whether the endpoint is healthy (1) or not (0)", "gauge", healthy)
+	p.family("endpoint_forwarder_endpoint_response_time_ms", "Last health check response time in milliseconds", "gauge", responseTime)
+	p.family("endpoint_forwarder_endpoint_consecutive_fails", "Consecutive failed health checks", "gauge", consecutiveFails)
+}
+
+// writeRequestMetrics writes request, latency, token, cost, retry and suspension metrics
+func (mm *MonitoringMiddleware) writeRequestMetrics(p *promWriter) {
+	stats := mm.metrics.GetRequestStats()
+
+	requests := make([]promSample, 0, len(stats.Requests))
+	for k, v := range stats.Requests {
+		requests = append(requests, promSample{
+			labels: []string{"endpoint", k.Endpoint, "model", k.Model, "status", k.Status, "failure_reason", k.FailureReason},
+			value:  float64(v),
+		})
+	}
+	p.family("endpoint_forwarder_requests_total", "Requests that reached a final state", "counter", requests)
+
+	p.histogram("endpoint_forwarder_request_duration_seconds", "End-to-end request duration in seconds", stats.Latency)
+	p.histogram("endpoint_forwarder_ttft_seconds", "Time to first token (first streamed content delta, or first response byte for non-streaming requests) in seconds", stats.TTFT)
+
+	tokens := make([]promSample, 0, len(stats.Tokens))
+	for k, v := range stats.Tokens {
+		tokens = append(tokens, promSample{
+			labels: []string{"endpoint", k.Endpoint, "model", k.Model, "type", k.Type},
+			value:  float64(v),
+		})
+	}
+	p.family("endpoint_forwarder_tokens_total", "Tokens consumed by type (input/output/cache_creation_5m/cache_creation_1h/cache_read)", "counter", tokens)
+
+	costs := make([]promSample, 0, len(stats.CostUSD))
+	for k, v := range stats.CostUSD {
+		costs = append(costs, promSample{labels: []string{"endpoint", k.Endpoint, "model", k.Model}, value: v})
+	}
+	p.family("endpoint_forwarder_cost_usd_total", "Estimated request cost in USD", "counter", costs)
+
+	retries := make([]promSample, 0, len(stats.Retries))
+	for endpoint, v := range stats.Retries {
+		retries = append(retries, promSample{labels: []string{"endpoint", endpoint}, value: float64(v)})
+	}
+	p.family("endpoint_forwarder_retries_total", "Retry attempts before the final state, by final endpoint", "counter", retries)
+
+	snapshot := mm.metrics.GetMetrics()
+	p.family("endpoint_forwarder_active_connections", "Active client connections", "gauge",
+		[]promSample{{value: float64(len(snapshot.ActiveConnections))}})
+	p.family("endpoint_forwarder_suspended_requests", "Requests currently suspended waiting for an endpoint", "gauge",
+		[]promSample{{value: float64(snapshot.SuspendedRequests)}})
+	p.family("endpoint_forwarder_suspended_requests_total", "Requests that were suspended", "counter",
+		[]promSample{{value: float64(snapshot.TotalSuspendedRequests)}})
+	p.family("endpoint_forwarder_suspended_requests_resumed_total", "Suspended requests resumed successfully", "counter",
+		[]promSample{{value: float64(snapshot.SuccessfulSuspendedRequests)}})
+	p.family("endpoint_forwarder_suspended_requests_timeout_total", "Suspended requests that timed out", "counter",
+		[]promSample{{value: float64(snapshot.TimeoutSuspendedRequests)}})
+}
+
+// writeTrackingMetrics writes hot pool and archive queue stats
+func (mm *MonitoringMiddleware) writeTrackingMetrics(p *promWriter) {
+	if mm.usageTracker == nil {
+		return
+	}
+
+	if hp := mm.usageTracker.GetHotPoolStats(); hp != nil {
+		p.family("endpoint_forwarder_hot_pool_size", "Requests currently held in the hot pool", "gauge",
+			[]promSample{{value: float64(hp.CurrentSize)}})
+		p.family("endpoint_forwarder_hot_pool_peak_size", "Peak hot pool size", "gauge",
+			[]promSample{{value: float64(hp.PeakSize)}})
+		p.family("endpoint_forwarder_hot_pool_added_total", "Requests added to the hot pool", "counter",
+			[]promSample{{value: float64(hp.TotalAdded)}})
+		p.family("endpoint_forwarder_hot_pool_archived_total", "Requests moved from the hot pool to the archive queue", "counter",
+			[]promSample{{value: float64(hp.TotalArchived)}})
+		p.family("endpoint_forwarder_hot_pool_expired_total", "Stale requests removed from the hot pool", "counter",
+			[]promSample{{value: float64(hp.TotalExpired)}})
+		p.family("endpoint_forwarder_hot_pool_overflow_total", "Requests dropped because the hot pool was full", "counter",
+			[]promSample{{value: float64(hp.TotalOverflow)}})
+	}
+
+	if am := mm.usageTracker.GetArchiveStats(); am != nil {
+		p.family("endpoint_forwarder_archive_queue_length", "Requests waiting in the archive queue", "gauge",
+			[]promSample{{value: float64(am.ChannelLength)}})
+		p.family("endpoint_forwarder_archive_received_total", "Requests received by the archive manager", "counter",
+			[]promSample{{value: float64(am.TotalReceived)}})
+		p.family("endpoint_forwarder_archive_written_total", "Requests written to the database", "counter",
+			[]promSample{{value: float64(am.TotalArchived)}})
+		p.family("endpoint_forwarder_archive_failed_total", "Requests that failed to be written", "counter",
+			[]promSample{{value: float64(am.TotalFailed)}})
+		p.family("endpoint_forwarder_archive_dropped_total", "Requests dropped because the archive queue was full", "counter",
+			[]promSample{{value: float64(am.TotalDropped)}})
+		p.family("endpoint_forwarder_archive_batches_total", "Archive batches flushed", "counter",
+			[]promSample{{value: float64(am.TotalBatches)}})
+	}
+}
+
+// writeTransportPoolMetrics writes per-endpoint transport pool stats
+func (mm *MonitoringMiddleware) writeTransportPoolMetrics(p *promWriter) {
+	pool := mm.endpointManager.GetTransportPool()
+	if pool == nil {
+		return
+	}
+	stats := pool.Stats()
+
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(s transport.PoolStats) int64
+	}{
+		{"endpoint_forwarder_transport_open_conns", "Open upstream connections", "gauge", func(s transport.PoolStats) int64 { return s.OpenConns }},
+		{"endpoint_forwarder_transport_active_requests", "In-flight upstream requests", "gauge", func(s transport.PoolStats) int64 { return s.ActiveRequests }},
+		{"endpoint_forwarder_transport_idle_conns", "Estimated idle upstream connections", "gauge", func(s transport.PoolStats) int64 { return s.IdleConns }},
+		{"endpoint_forwarder_transport_dials_total", "New upstream connections dialed", "counter", func(s transport.PoolStats) int64 { return s.Dials }},
+		{"endpoint_forwarder_transport_tls_handshakes_total", "TLS handshakes performed", "counter", func(s transport.PoolStats) int64 { return s.TLSHandshakes }},
+		{"endpoint_forwarder_transport_reused_conns_total", "Upstream requests served on a reused connection", "counter", func(s transport.PoolStats) int64 { return s.ReusedConns }},
+		{"endpoint_forwarder_transport_requests_total", "Upstream requests sent through the pool", "counter", func(s transport.PoolStats) int64 { return s.Requests }},
+		{"endpoint_forwarder_transport_rebuilds_total", "Transport rebuilds caused by config changes", "counter", func(s transport.PoolStats) int64 { return s.Rebuilds }},
+	}
+
+	for _, m := range metrics {
+		samples := make([]promSample, 0, len(stats))
+		for _, s := range stats {
+			samples = append(samples, promSample{labels: []string{"endpoint", s.Endpoint, "profile", s.Profile}, value: float64(m.value(s))})
 		}
-		
-		fmt.Fprintf(w, "endpoint_forwarder_endpoint_healthy{name=\"%s\",url=\"%s\",priority=\"%d\"} %d\n",
-			ep.Config.Name, ep.Config.URL, ep.Config.Priority, healthy)
-		
-		fmt.Fprintf(w, "endpoint_forwarder_endpoint_response_time_ms{name=\"%s\",url=\"%s\"} %d\n",
-			ep.Config.Name, ep.Config.URL, ep.GetResponseTime().Milliseconds())
-		
-		status := ep.GetStatus()
-		fmt.Fprintf(w, "endpoint_forwarder_endpoint_consecutive_fails{name=\"%s\",url=\"%s\"} %d\n",
-			ep.Config.Name, ep.Config.URL, status.ConsecutiveFails)
+		p.family(m.name, m.help, m.kind, samples)
 	}
-	
-	fmt.Fprintf(w, "endpoint_forwarder_endpoints_healthy %d\n", healthyCount)
 }
 
 // GetMetrics returns the metrics instance for TUI access
@@ -276,6 +407,14 @@ func (mm *MonitoringMiddleware) formatResponseTime(duration time.Duration) strin
 	return fmt.Sprintf("%.2fs", duration.Seconds())
 }
 
+// RecordRequestOutcome 记录请求最终结果（请求计数、耗时、首 Token、Token、成本）
+// 代理处理器未设置监控中间件时以 nil 指针传入，这里需要安全返回
+func (mm *MonitoringMiddleware) RecordRequestOutcome(outcome monitor.RequestOutcome) {
+	if mm != nil && mm.metrics != nil {
+		mm.metrics.RecordRequestOutcome(outcome)
+	}
+}
+
 // RecordRetry records a retry attempt
 func (mm *MonitoringMiddleware) RecordRetry(connID string, endpoint string) {
 	mm.metrics.RecordRetry(connID, endpoint)
@@ -296,6 +435,37 @@ func (mm *MonitoringMiddleware) UpdateEndpointHealthStatus() {
 	// 不再广播端点事件 - 由 endpoint_manager 负责
 }
 
+// SubscribeEndpointEvents 订阅端点健康与熔断事件，端点状态变化时刷新监控指标中的端点健康状态
+// 取消订阅或 EventBus 停止时订阅通道关闭，协程随之退出
+func (mm *MonitoringMiddleware) SubscribeEndpointEvents() {
+	if mm.eventBus == nil || mm.endpointManager == nil || mm.unsubscribe != nil {
+		return
+	}
+
+	eventCh, unsubscribe := mm.eventBus.Subscribe(events.SubscriptionFilter{
+		Name:       "monitoring_middleware",
+		Categories: []string{"endpoint"},
+		BufferSize: 64,
+	})
+	mm.unsubscribe = unsubscribe
+	mm.UpdateEndpointHealthStatus()
+
+	go func() {
+		for event := range eventCh {
+			mm.UpdateEndpointHealthStatus()
+			slog.Debug(fmt.Sprintf("📊 [监控订阅] 端点事件 %s，已刷新端点健康状态", event.Type))
+		}
+	}()
+}
+
+// UnsubscribeEndpointEvents 取消端点事件订阅
+func (mm *MonitoringMiddleware) UnsubscribeEndpointEvents() {
+	if mm.unsubscribe != nil {
+		mm.unsubscribe()
+		mm.unsubscribe = nil
+	}
+}
+
 // UpdateConnectionEndpoint updates the endpoint name for an active connection
 func (mm *MonitoringMiddleware) UpdateConnectionEndpoint(connID, endpoint string) {
 	mm.metrics.UpdateConnectionEndpoint(connID, endpoint)
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cc-forwarder/internal/monitor"
+)
+
+// PrometheusContentType Prometheus 文本格式 0.0.4
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// promSample 单个样本：标签按 name, value 成对排列
+type promSample struct {
+	labels []string
+	value  float64
+}
+
+// promWriter 输出 Prometheus 文本格式，负责 HELP 转义和标签值转义
+type promWriter struct {
+	w io.Writer
+}
+
+// family 输出一个指标族的 HELP/TYPE 和全部样本（样本按标签排序，输出稳定）
+func (p *promWriter) family(name, help, kind string, samples []promSample) {
+	p.header(name, help, kind)
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return strings.Join(samples[i].labels, "\xff") < strings.Join(samples[j].labels, "\xff")
+	})
+	for _, s := range samples {
+		p.sample(name, s.labels, s.value)
+	}
+}
+
+// histogram 输出直方图指标族
+func (p *promWriter) histogram(name, help string, series map[monitor.ModelLabels]monitor.Histogram) {
+	p.header(name, help, "histogram")
+
+	keys := make([]monitor.ModelLabels, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Endpoint != keys[j].Endpoint {
+			return keys[i].Endpoint < keys[j].Endpoint
+		}
+		return keys[i].Model < keys[j].Model
+	})
+
+	for _, k := range keys {
+		h := series[k]
+		labels := []string{"endpoint", k.Endpoint, "model", k.Model}
+		for i, upper := range h.Buckets {
+			p.sample(name+"_bucket", append(labels, "le", formatPromValue(upper)), float64(h.Counts[i]))
+		}
+		p.sample(name+"_bucket", append(labels, "le", "+Inf"), float64(h.Count))
+		p.sample(name+"_sum", labels, h.Sum)
+		p.sample(name+"_count", labels, float64(h.Count))
+	}
+}
+
+func (p *promWriter) header(name, help, kind string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n", name, helpEscaper.Replace(help))
+	fmt.Fprintf(p.w, "# TYPE %s %s\n", name, kind)
+}
+
+func (p *promWriter) sample(name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(labelValueEscaper.Replace(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatPromValue(value))
+	b.WriteByte('\n')
+	io.WriteString(p.w, b.String())
+}
+
+// formatPromValue 格式化样本值（整数不带小数点，特殊值使用 Prometheus 写法）
+func formatPromValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// boolValue 布尔值转换为 0/1
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/endpoint"
+	"cc-forwarder/internal/monitor"
+	"cc-forwarder/internal/transport"
+)
+
+func TestPromWriter_Escaping(t *testing.T) {
+	var b strings.Builder
+	p := &promWriter{w: &b}
+	p.family("test_metric", "help with \\ and\nnewline", "gauge", []promSample{
+		{labels: []string{"name", `a "quoted" \ name` + "\nnext"}, value: 1.5},
+	})
+
+	want := "# HELP test_metric help with \\\\ and\\nnewline\n" +
+		"# TYPE test_metric gauge\n" +
+		`test_metric{name="a \"quoted\" \\ name\nnext"} 1.5` + "\n"
+	if b.String() != want {
+		t.Errorf("输出不正确:\n%s\n期望:\n%s", b.String(), want)
+	}
+}
+
+func TestHandleMetrics_RequestOutcomes(t *testing.T) {
+	cfg := &config.Config{
+		Endpoints: []config.EndpointConfig{
+			{Name: `relay"1`, URL: "https://relay.example.com", Priority: 1},
+		},
+	}
+	mm := NewMonitoringMiddleware(endpoint.NewManager(cfg))
+
+	mm.RecordRequestOutcome(monitor.RequestOutcome{
+		Endpoint: `relay"1`,
+		Model:    "claude-sonnet-4-5",
+		Status:   "completed",
+		Duration: 1200 * time.Millisecond,
+		TTFT:     300 * time.Millisecond,
+		Retries:  1,
+		Tokens: &monitor.TokenUsage{
+			InputTokens:         100,
+			OutputTokens:        20,
+			CacheCreationTokens: 50,
+			CacheReadTokens:     10,
+		},
+		CacheCreation1hTokens: 50,
+		CostUSD:               0.25,
+	})
+	mm.RecordRequestOutcome(monitor.RequestOutcome{
+		Endpoint:      `relay"1`,
+		Model:         "claude-sonnet-4-5",
+		Status:        "failed",
+		FailureReason: "rate_limited",
+		Duration:      40 * time.Second,
+	})
+
+	rec := httptest.NewRecorder()
+	mm.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != PrometheusContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`endpoint_forwarder_endpoint_healthy{name="relay\"1",url="https://relay.example.com",priority="1"} 0`,
+		`endpoint_forwarder_requests_total{endpoint="relay\"1",model="claude-sonnet-4-5",status="completed",failure_reason=""} 1`,
+		`endpoint_forwarder_requests_total{endpoint="relay\"1",model="claude-sonnet-4-5",status="failed",failure_reason="rate_limited"} 1`,
+		`endpoint_forwarder_request_duration_seconds_bucket{endpoint="relay\"1",model="claude-sonnet-4-5",le="1"} 0`,
+		`endpoint_forwarder_request_duration_seconds_bucket{endpoint="relay\"1",model="claude-sonnet-4-5",le="2.5"} 1`,
+		`endpoint_forwarder_request_duration_seconds_bucket{endpoint="relay\"1",model="claude-sonnet-4-5",le="+Inf"} 2`,
+		`endpoint_forwarder_request_duration_seconds_count{endpoint="relay\"1",model="claude-sonnet-4-5"} 2`,
+		`endpoint_forwarder_ttft_seconds_bucket{endpoint="relay\"1",model="claude-sonnet-4-5",le="0.5"} 1`,
+		`endpoint_forwarder_ttft_seconds_count{endpoint="relay\"1",model="claude-sonnet-4-5"} 1`,
+		`endpoint_forwarder_tokens_total{endpoint="relay\"1",model="claude-sonnet-4-5",type="input"} 100`,
+		`endpoint_forwarder_tokens_total{endpoint="relay\"1",model="claude-sonnet-4-5",type="cache_creation_1h"} 50`,
+		`endpoint_forwarder_tokens_total{endpoint="relay\"1",model="claude-sonnet-4-5",type="cache_read"} 10`,
+		`endpoint_forwarder_cost_usd_total{endpoint="relay\"1",model="claude-sonnet-4-5"} 0.25`,
+		`endpoint_forwarder_retries_total{endpoint="relay\"1"} 1`,
+		`endpoint_forwarder_suspended_requests 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("缺少指标行: %s", line)
+		}
+	}
+
+	// 已区分 5m/1h 时，缓存总数不应再计入 5m
+	if strings.Contains(body, `type="cache_creation_5m"`) {
+		t.Error("已区分 1h 缓存时不应输出 5m 缓存计数")
+	}
+}
+
+func TestHandleMetrics_TransportPoolLabels(t *testing.T) {
+	ep := config.EndpointConfig{Name: "relay", URL: "https://relay.example.com", Priority: 1}
+	manager := endpoint.NewManager(&config.Config{Endpoints: []config.EndpointConfig{ep}})
+	if _, err := manager.GetTransportPool().Get(&ep, transport.ProfileRegular); err != nil {
+		t.Fatalf("创建 Transport 失败: %v", err)
+	}
+	mm := NewMonitoringMiddleware(manager)
+
+	rec := httptest.NewRecorder()
+	mm.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// 与其他按端点的指标使用相同的 endpoint 标签，便于关联查询
+	line := `endpoint_forwarder_transport_requests_total{endpoint="relay",profile="regular"} 0`
+	if !strings.Contains(rec.Body.String(), line+"\n") {
+		t.Errorf("缺少指标行: %s", line)
+	}
+}
@@ -64,6 +64,15 @@ type Metrics struct {
 	TokenHistory                []TokenHistoryPoint
 	SuspendedRequestHistory     []SuspendedRequestHistoryPoint
 	MaxHistoryPoints            int
+
+	// 请求结果统计（Prometheus 导出用，见 request_stats.go）
+	requestCounts     map[RequestLabels]int64
+	latencyHistograms map[ModelLabels]*Histogram
+	ttftHistograms    map[ModelLabels]*Histogram
+	tokenCounts       map[TokenLabels]int64
+	costByModel       map[ModelLabels]float64
+	retriesByEndpoint map[string]int64
+	ttftWindows       map[string]*durationWindow // 各端点最近的首 Token 耗时样本
 }
 
 // EndpointMetrics tracks metrics for a specific endpoint
@@ -767,6 +776,11 @@ func (m *Metrics) GetEndpointPerformanceData() []map[string]interface{} {
 			avgResponseTime = endpoint.TotalResponseTime / time.Duration(endpoint.TotalRequests)
 		}
 
+		var ttft TTFTPercentiles
+		if window := m.ttftWindows[endpoint.Name]; window != nil {
+			ttft = window.percentiles()
+		}
+
 		result = append(result, map[string]interface{}{
 			"name":                 endpoint.Name,
 			"url":                  endpoint.URL,
@@ -782,6 +796,9 @@ func (m *Metrics) GetEndpointPerformanceData() []map[string]interface{} {
 			"retry_count":          endpoint.RetryCount,
 			"last_used":            endpoint.LastUsed,
 			"token_usage":          endpoint.TokenUsage,
+			"ttft_p50_ms":          ttft.P50.Milliseconds(),
+			"ttft_p95_ms":          ttft.P95.Milliseconds(),
+			"ttft_samples":         ttft.Samples,
 		})
 	}
 
@@ -0,0 +1,264 @@
+package monitor
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencyBuckets 请求总耗时直方图的桶上界（秒）
+var LatencyBuckets = []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600}
+
+// TTFTBuckets 首 Token 耗时直方图的桶上界（秒）
+var TTFTBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 10, 20, 30, 60}
+
+// TTFTSampleSize 每个端点保留的最近首 Token 耗时样本数（用于计算 p50/p95）
+const TTFTSampleSize = 500
+
+// Token 类型标签
+const (
+	TokenTypeInput           = "input"
+	TokenTypeOutput          = "output"
+	TokenTypeCacheCreation5m = "cache_creation_5m"
+	TokenTypeCacheCreation1h = "cache_creation_1h"
+	TokenTypeCacheRead       = "cache_read"
+)
+
+// RequestOutcome 请求到达最终状态时的结果数据
+type RequestOutcome struct {
+	Endpoint              string
+	Model                 string
+	Status                string        // completed / cache_hit / failed / cancelled
+	FailureReason         string        // 失败原因或数据质量标记，可为空
+	Duration              time.Duration // 请求总耗时
+	TTFT                  time.Duration // 首 Token 耗时，0 表示未向客户端写出数据
+	Retries               int           // 最终状态前的重试次数
+	Tokens                *TokenUsage
+	CacheCreation5mTokens int64 // 5 分钟缓存创建 Token
+	CacheCreation1hTokens int64 // 1 小时缓存创建 Token
+	CostUSD               float64
+}
+
+// RequestLabels 请求计数的标签组合
+type RequestLabels struct {
+	Endpoint      string
+	Model         string
+	Status        string
+	FailureReason string
+}
+
+// ModelLabels 端点 + 模型标签组合
+type ModelLabels struct {
+	Endpoint string
+	Model    string
+}
+
+// TokenLabels Token 计数的标签组合
+type TokenLabels struct {
+	Endpoint string
+	Model    string
+	Type     string
+}
+
+// Histogram 累积直方图（Prometheus 语义：Counts[i] 为 <= Buckets[i] 的观测数）
+type Histogram struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Observe 记录一次观测
+func (h *Histogram) Observe(v float64) {
+	for i := sort.SearchFloat64s(h.Buckets, v); i < len(h.Buckets); i++ {
+		h.Counts[i]++
+	}
+	h.Count++
+	h.Sum += v
+}
+
+func (h *Histogram) clone() Histogram {
+	counts := make([]uint64, len(h.Counts))
+	copy(counts, h.Counts)
+	return Histogram{Buckets: h.Buckets, Counts: counts, Count: h.Count, Sum: h.Sum}
+}
+
+// RequestStats 请求结果统计快照
+type RequestStats struct {
+	Requests map[RequestLabels]int64
+	Latency  map[ModelLabels]Histogram
+	TTFT     map[ModelLabels]Histogram
+	Tokens   map[TokenLabels]int64
+	CostUSD  map[ModelLabels]float64
+	Retries  map[string]int64 // 按最终端点统计
+}
+
+// RecordRequestOutcome 记录请求最终结果（计数、耗时、Token、成本）
+func (m *Metrics) RecordRequestOutcome(outcome RequestOutcome) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.requestCounts == nil {
+		m.requestCounts = make(map[RequestLabels]int64)
+		m.latencyHistograms = make(map[ModelLabels]*Histogram)
+		m.ttftHistograms = make(map[ModelLabels]*Histogram)
+		m.tokenCounts = make(map[TokenLabels]int64)
+		m.costByModel = make(map[ModelLabels]float64)
+		m.retriesByEndpoint = make(map[string]int64)
+		m.ttftWindows = make(map[string]*durationWindow)
+	}
+
+	m.requestCounts[RequestLabels{
+		Endpoint:      outcome.Endpoint,
+		Model:         outcome.Model,
+		Status:        outcome.Status,
+		FailureReason: outcome.FailureReason,
+	}]++
+
+	key := ModelLabels{Endpoint: outcome.Endpoint, Model: outcome.Model}
+
+	latency := m.latencyHistograms[key]
+	if latency == nil {
+		latency = newHistogram(LatencyBuckets)
+		m.latencyHistograms[key] = latency
+	}
+	latency.Observe(outcome.Duration.Seconds())
+
+	if outcome.TTFT > 0 {
+		ttft := m.ttftHistograms[key]
+		if ttft == nil {
+			ttft = newHistogram(TTFTBuckets)
+			m.ttftHistograms[key] = ttft
+		}
+		ttft.Observe(outcome.TTFT.Seconds())
+
+		window := m.ttftWindows[outcome.Endpoint]
+		if window == nil {
+			window = &durationWindow{samples: make([]time.Duration, 0, TTFTSampleSize)}
+			m.ttftWindows[outcome.Endpoint] = window
+		}
+		window.add(outcome.TTFT)
+	}
+
+	if outcome.Tokens != nil {
+		// 未区分 5m/1h 时按 5m 缓存统计，与成本计算保持一致
+		cache5m, cache1h := outcome.CacheCreation5mTokens, outcome.CacheCreation1hTokens
+		if cache5m == 0 && cache1h == 0 {
+			cache5m = outcome.Tokens.CacheCreationTokens
+		}
+
+		counts := map[string]int64{
+			TokenTypeInput:           outcome.Tokens.InputTokens,
+			TokenTypeOutput:          outcome.Tokens.OutputTokens,
+			TokenTypeCacheCreation5m: cache5m,
+			TokenTypeCacheCreation1h: cache1h,
+			TokenTypeCacheRead:       outcome.Tokens.CacheReadTokens,
+		}
+		for tokenType, n := range counts {
+			if n > 0 {
+				m.tokenCounts[TokenLabels{Endpoint: outcome.Endpoint, Model: outcome.Model, Type: tokenType}] += n
+			}
+		}
+	}
+
+	if outcome.CostUSD > 0 {
+		m.costByModel[key] += outcome.CostUSD
+	}
+	if outcome.Retries > 0 {
+		m.retriesByEndpoint[outcome.Endpoint] += int64(outcome.Retries)
+	}
+}
+
+// GetRequestStats 返回请求结果统计快照
+func (m *Metrics) GetRequestStats() RequestStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := RequestStats{
+		Requests: make(map[RequestLabels]int64, len(m.requestCounts)),
+		Latency:  make(map[ModelLabels]Histogram, len(m.latencyHistograms)),
+		TTFT:     make(map[ModelLabels]Histogram, len(m.ttftHistograms)),
+		Tokens:   make(map[TokenLabels]int64, len(m.tokenCounts)),
+		CostUSD:  make(map[ModelLabels]float64, len(m.costByModel)),
+		Retries:  make(map[string]int64, len(m.retriesByEndpoint)),
+	}
+
+	for k, v := range m.requestCounts {
+		stats.Requests[k] = v
+	}
+	for k, v := range m.latencyHistograms {
+		stats.Latency[k] = v.clone()
+	}
+	for k, v := range m.ttftHistograms {
+		stats.TTFT[k] = v.clone()
+	}
+	for k, v := range m.tokenCounts {
+		stats.Tokens[k] = v
+	}
+	for k, v := range m.costByModel {
+		stats.CostUSD[k] = v
+	}
+	for k, v := range m.retriesByEndpoint {
+		stats.Retries[k] = v
+	}
+
+	return stats
+}
+
+// TTFTPercentiles 端点最近请求的首 Token 耗时分位数
+type TTFTPercentiles struct {
+	P50     time.Duration
+	P95     time.Duration
+	Samples int
+}
+
+// GetEndpointTTFTPercentiles 返回各端点最近 TTFTSampleSize 个请求的首 Token 耗时分位数
+func (m *Metrics) GetEndpointTTFTPercentiles() map[string]TTFTPercentiles {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]TTFTPercentiles, len(m.ttftWindows))
+	for name, window := range m.ttftWindows {
+		result[name] = window.percentiles()
+	}
+	return result
+}
+
+// durationWindow 固定容量的最近样本环形缓冲
+type durationWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func (w *durationWindow) add(d time.Duration) {
+	if len(w.samples) < TTFTSampleSize {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % TTFTSampleSize
+}
+
+// percentiles 按最近邻秩法计算 p50/p95
+func (w *durationWindow) percentiles() TTFTPercentiles {
+	n := len(w.samples)
+	if n == 0 {
+		return TTFTPercentiles{}
+	}
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, w.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p float64) time.Duration {
+		return sorted[int(math.Ceil(p*float64(n)))-1]
+	}
+	return TTFTPercentiles{P50: rank(0.50), P95: rank(0.95), Samples: n}
+}
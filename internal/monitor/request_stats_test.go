@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndpointTTFTPercentiles(t *testing.T) {
+	m := NewMetrics()
+	m.UpdateEndpointHealth("relay", "https://relay.example.com", true, 1)
+
+	// 1..100ms 各一次，另有一个未写出数据的请求（TTFT 为 0，不计入）
+	for i := 1; i <= 100; i++ {
+		m.RecordRequestOutcome(RequestOutcome{Endpoint: "relay", Status: "completed", TTFT: time.Duration(i) * time.Millisecond})
+	}
+	m.RecordRequestOutcome(RequestOutcome{Endpoint: "relay", Status: "failed"})
+
+	got := m.GetEndpointTTFTPercentiles()["relay"]
+	if got.P50 != 50*time.Millisecond || got.P95 != 95*time.Millisecond || got.Samples != 100 {
+		t.Errorf("分位数不正确: %+v", got)
+	}
+
+	perf := m.GetEndpointPerformanceData()
+	if len(perf) != 1 || perf[0]["ttft_p50_ms"] != int64(50) || perf[0]["ttft_p95_ms"] != int64(95) {
+		t.Errorf("端点性能数据缺少 TTFT 分位数: %v", perf)
+	}
+
+	// 超出窗口容量后只保留最近的样本
+	for i := 0; i < TTFTSampleSize; i++ {
+		m.RecordRequestOutcome(RequestOutcome{Endpoint: "relay", Status: "completed", TTFT: time.Second})
+	}
+	got = m.GetEndpointTTFTPercentiles()["relay"]
+	if got.P50 != time.Second || got.Samples != TTFTSampleSize {
+		t.Errorf("窗口应只保留最近 %d 个样本: %+v", TTFTSampleSize, got)
+	}
+}
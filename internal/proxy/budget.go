@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"cc-forwarder/internal/middleware"
+)
+
+// budgetCheckedPath 受预算限制的请求路径（count_tokens 等不计费接口不受限）
+const budgetCheckedPath = "/v1/messages"
+
+// BudgetGuard 预算检查接口（由 service.BudgetService 实现）
+// 返回拒绝原因，未超过硬限额时返回空字符串
+type BudgetGuard interface {
+	// RequestRejectReason 检查全局和客户端 Key 预算
+	RequestRejectReason(clientKey string) string
+	// EndpointRejectReason 检查端点和渠道预算（超限端点在选择时被跳过）
+	EndpointRejectReason() string
+}
+
+// SetBudgetGuard 设置预算检查
+func (h *Handler) SetBudgetGuard(guard BudgetGuard) {
+	h.budgetGuard = guard
+}
+
+// checkBudget 检查预算，超过硬限额时返回 Anthropic 格式错误并返回 false
+func (h *Handler) checkBudget(w http.ResponseWriter, r *http.Request) bool {
+	if h.budgetGuard == nil || r.URL.Path != budgetCheckedPath {
+		return true
+	}
+
+	reason := h.budgetGuard.RequestRejectReason(middleware.ClientKeyFromContext(r.Context()))
+	if reason == "" {
+		// 端点/渠道预算超限只跳过对应端点，没有其他可用端点时才拒绝
+		if endpointReason := h.budgetGuard.EndpointRejectReason(); endpointReason != "" && len(h.endpointManager.GetHealthyEndpoints()) == 0 {
+			reason = endpointReason
+		}
+	}
+	if reason == "" {
+		return true
+	}
+
+	slog.Warn(fmt.Sprintf("🚫 [预算] 拒绝请求: %s", reason), "client_ip", r.RemoteAddr)
+	writeAnthropicError(w, http.StatusTooManyRequests, "rate_limit_error", reason)
+	return false
+}
+
+// writeAnthropicError 写入 Anthropic API 格式的错误响应
+func writeAnthropicError(w http.ResponseWriter, statusCode int, errorType, message string) {
+	type errorDetail struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	body, _ := json.Marshal(struct {
+		Type  string      `json:"type"`
+		Error errorDetail `json:"error"`
+	}{Type: "error", Error: errorDetail{Type: errorType, Message: message}})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// staticBudgetGuard 测试用预算检查：对指定客户端 Key 返回拒绝原因
+type staticBudgetGuard map[string]string
+
+func (g staticBudgetGuard) RequestRejectReason(clientKey string) string { return g[clientKey] }
+func (g staticBudgetGuard) EndpointRejectReason() string                { return "" }
+
+// TestCheckBudget 测试超过硬限额时返回 Anthropic 格式的 429 错误
+func TestCheckBudget(t *testing.T) {
+	h := &Handler{budgetGuard: staticBudgetGuard{"": "Budget 'global' (global) exceeded"}}
+
+	// 非计费路径不受限
+	rec := httptest.NewRecorder()
+	if !h.checkBudget(rec, httptest.NewRequest(http.MethodPost, "/v1/messages/count_tokens", nil)) {
+		t.Error("count_tokens 不应受预算限制")
+	}
+
+	rec = httptest.NewRecorder()
+	if h.checkBudget(rec, httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader("{}"))) {
+		t.Fatal("超过硬限额时应拒绝请求")
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("状态码 = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	var body struct {
+		Type  string `json:"type"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if body.Type != "error" || body.Error.Type != "rate_limit_error" || !strings.Contains(body.Error.Message, "global") {
+		t.Errorf("错误响应格式不匹配: %s", rec.Body.String())
+	}
+
+	// 未设置预算检查时放行
+	if !(&Handler{}).checkBudget(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/messages", nil)) {
+		t.Error("未设置预算检查时应放行")
+	}
+}
@@ -45,6 +45,7 @@ type ErrorContext struct {
 	OriginalError  error
 	RetryableAfter time.Duration // 建议重试延迟
 	MaxRetries     int
+	StatusCode     int // 上游响应状态码（非 HTTP 状态码错误时为 0）
 }
 
 // ErrorRecoveryManager 错误恢复管理器
@@ -77,6 +78,7 @@ func (erm *ErrorRecoveryManager) ClassifyError(err error, requestID, endpoint, g
 		AttemptCount:  attempt,
 		OriginalError: err,
 		MaxRetries:    erm.maxRetries,
+		StatusCode:    parseHTTPStatusCode(err),
 	}
 
 	if err == nil {
@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"cc-forwarder/config"
+	"cc-forwarder/internal/capture"
 	"cc-forwarder/internal/endpoint"
 	"cc-forwarder/internal/events"
 	"cc-forwarder/internal/middleware"
 	"cc-forwarder/internal/monitor"
 	"cc-forwarder/internal/proxy/handlers"
+	"cc-forwarder/internal/proxy/openai"
 	"cc-forwarder/internal/proxy/response"
 	"cc-forwarder/internal/tracking"
 )
@@ -41,6 +43,10 @@ type Handler struct {
 	sharedSuspensionManager handlers.SuspensionManager
 	// 🚀 [端点自愈] 端点恢复信号管理器
 	recoverySignalManager *EndpointRecoverySignalManager
+	// 预算检查（超过硬限额时拒绝请求）
+	budgetGuard BudgetGuard
+	// 响应缓存（确定性非流式请求）
+	responseCache handlers.ResponseCache
 }
 
 // TokenParserProviderImpl 实现TokenParserProvider接口
@@ -80,6 +86,10 @@ func (spa *StreamProcessorAdapter) ProcessStreamWithRetry(ctx context.Context, r
 	return spa.innerProcessor.ProcessStreamWithRetry(ctx, resp)
 }
 
+func (spa *StreamProcessorAdapter) GetTimings() (time.Time, time.Time) {
+	return spa.innerProcessor.GetTimings()
+}
+
 // ErrorRecoveryManagerAdapter 适配*ErrorRecoveryManager到handlers.ErrorRecoveryManager
 type ErrorRecoveryManagerAdapter struct {
 	innerManager *ErrorRecoveryManager
@@ -96,6 +106,7 @@ func (era *ErrorRecoveryManagerAdapter) ClassifyError(err error, connID, endpoin
 		OriginalError:  ctx.OriginalError,
 		RetryableAfter: ctx.RetryableAfter,
 		MaxRetries:     ctx.MaxRetries,
+		StatusCode:     ctx.StatusCode,
 	}
 }
 
@@ -109,6 +120,7 @@ func (era *ErrorRecoveryManagerAdapter) HandleFinalFailure(errorCtx handlers.Err
 		OriginalError:  errorCtx.OriginalError,
 		RetryableAfter: errorCtx.RetryableAfter,
 		MaxRetries:     errorCtx.MaxRetries,
+		StatusCode:     errorCtx.StatusCode,
 	}
 	era.innerManager.HandleFinalFailure(&innerCtx)
 }
@@ -220,7 +232,12 @@ type RetryManagerFactoryImpl struct {
 }
 
 func (f *RetryManagerFactoryImpl) NewRetryManager() handlers.RetryManager {
-	return NewRetryManager(f.config, f.errorRecovery, f.endpointManager)
+	// 优先使用端点管理器持有的最新配置，确保配置热更新后的重试策略立即生效
+	cfg := f.config
+	if f.endpointManager != nil && f.endpointManager.GetConfig() != nil {
+		cfg = f.endpointManager.GetConfig()
+	}
+	return NewRetryManager(cfg, f.errorRecovery, f.endpointManager)
 }
 
 type SuspensionManagerFactoryImpl struct {
@@ -379,6 +396,9 @@ func (h *Handler) SetUsageTracker(ut *tracking.UsageTracker) {
 			// 🔧 [Critical修复] 使用保存的共享SuspensionManager实例
 			h.sharedSuspensionManager,
 		)
+		if h.responseCache != nil {
+			h.regularHandler.SetResponseCache(h.responseCache)
+		}
 	}
 	
 	// 重新创建streamingHandler以包含usageTracker
@@ -412,6 +432,22 @@ func (h *Handler) GetRetryHandler() *RetryHandler {
 // SetEventBus 设置EventBus事件总线
 func (h *Handler) SetEventBus(eventBus events.EventBus) {
 	h.eventBus = eventBus
+	if sm, ok := h.sharedSuspensionManager.(*SuspensionManager); ok {
+		sm.SetEventBus(eventBus)
+	}
+}
+
+// SetResponseCache 设置响应缓存（重建 regularHandler 时保持）
+func (h *Handler) SetResponseCache(cache handlers.ResponseCache) {
+	h.responseCache = cache
+	if h.regularHandler != nil {
+		h.regularHandler.SetResponseCache(cache)
+	}
+}
+
+// SetCaptureRecorder 设置请求抓取记录器（所有请求处理器共用同一个 Forwarder）
+func (h *Handler) SetCaptureRecorder(recorder *capture.Recorder) {
+	h.forwarder.SetCapture(recorder)
 }
 
 // extractModelFromRequestBody 从请求体中提取模型名称
@@ -438,6 +474,17 @@ func (h *Handler) extractModelFromRequestBody(bodyBytes []byte, path string) str
 	return ""
 }
 
+// withRequestModel 存在模型路由规则时解析请求模型并写入上下文，供端点选择使用
+func (h *Handler) withRequestModel(ctx context.Context, bodyBytes []byte, path string) context.Context {
+	if !h.endpointManager.HasModelRoutes() {
+		return ctx
+	}
+	if modelName := h.extractModelFromRequestBody(bodyBytes, path); modelName != "" {
+		return endpoint.WithRequestModel(ctx, modelName)
+	}
+	return ctx
+}
+
 // ServeHTTP implements the http.Handler interface
 // 统一请求分发逻辑 - 整合流式处理、错误恢复和生命周期管理
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -458,12 +505,28 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			r.Body.Close()
 		}
 
+		// 🧭 [模型路由] count_tokens 同样按请求模型的路由规则选择端点
+		ctx = h.withRequestModel(ctx, bodyBytes, r.URL.Path)
+		r = r.WithContext(ctx)
+
 		// 使用CountTokensHandler处理
 		countTokensHandler := handlers.NewCountTokensHandler(h.config, h.endpointManager, h.forwarder)
+		countTokensHandler.SetResponseCache(h.responseCache)
 		countTokensHandler.Handle(ctx, w, r, bodyBytes, connID)
 		return
 	}
 
+	// 🔌 [OpenAI兼容] 将 Chat Completions 请求转换为 Messages 格式后走标准转发流程
+	if r.URL.Path == openai.ChatCompletionsPath && h.config.OpenAICompat.Enabled {
+		h.serveChatCompletions(w, r)
+		return
+	}
+
+	// 💰 [预算] 超过硬限额时直接拒绝
+	if !h.checkBudget(w, r) {
+		return
+	}
+
 	// 创建请求上下文
 	ctx := r.Context()
 	
@@ -475,6 +538,12 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	
 	// 创建统一的请求生命周期管理器
 	lifecycleManager := NewRequestLifecycleManagerWithRecoverySignal(h.usageTracker, h.monitoringMiddleware, connID, h.eventBus, h.recoverySignalManager)
+	lifecycleManager.SetTokenRecorder(h.endpointManager)
+
+	// 🔭 [链路追踪] 开始请求根 span（沿用客户端 traceparent），处理结束时兜底结束
+	ctx = lifecycleManager.StartTrace(ctx, r.Header)
+	r = r.WithContext(ctx)
+	defer lifecycleManager.EndTrace()
 	
 	// 克隆请求体用于重试
 	var bodyBytes []byte
@@ -489,6 +558,10 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r.Body.Close()
 	}
 
+	// 🧭 [模型路由] 存在路由规则时同步解析模型，供端点选择使用
+	ctx = h.withRequestModel(ctx, bodyBytes, r.URL.Path)
+	r = r.WithContext(ctx)
+
 	// 异步解析请求体中的模型名称（不阻塞主转发流程）
 	go func(body []byte, path string) {
 		if modelName := h.extractModelFromRequestBody(body, path); modelName != "" {
@@ -502,6 +575,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// 开始请求跟踪（传递流式标记）
 	clientIP := r.RemoteAddr
 	userAgent := r.Header.Get("User-Agent")
+	lifecycleManager.SetClientKey(middleware.ClientKeyFromContext(ctx))
 	lifecycleManager.StartRequest(clientIP, userAgent, r.Method, r.URL.Path, isSSE)
 	
 	// 统一请求处理
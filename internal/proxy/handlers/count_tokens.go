@@ -21,6 +21,7 @@ type CountTokensHandler struct {
 	config          *config.Config
 	endpointManager *endpoint.Manager
 	forwarder       *Forwarder
+	responseCache   ResponseCache
 }
 
 // NewCountTokensHandler 创建 CountTokensHandler
@@ -32,6 +33,11 @@ func NewCountTokensHandler(cfg *config.Config, em *endpoint.Manager, f *Forwarde
 	}
 }
 
+// SetResponseCache 设置响应缓存（只缓存上游返回的结果，不缓存本地估算）
+func (h *CountTokensHandler) SetResponseCache(cache ResponseCache) {
+	h.responseCache = cache
+}
+
 // CountTokensRequest 定义 count_tokens 请求结构
 type CountTokensRequest struct {
 	Model    string                   `json:"model"`
@@ -49,12 +55,31 @@ type CountTokensResponse struct {
 func (h *CountTokensHandler) Handle(ctx context.Context, w http.ResponseWriter, r *http.Request, bodyBytes []byte, connID string) {
 	slog.Info(fmt.Sprintf("🔢 [Token计数] [%s] 收到count_tokens请求", connID))
 
+	// 0. 命中响应缓存时直接返回
+	if h.responseCache != nil {
+		if header, result, ok := h.responseCache.Lookup(ctx, r.URL.Path, bodyBytes); ok {
+			for key, values := range header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+			w.Header().Set(ResponseCacheHeader, "HIT")
+			w.WriteHeader(http.StatusOK)
+			w.Write(result)
+			slog.Info(fmt.Sprintf("💾 [响应缓存] [%s] 命中缓存: %s, 大小: %d字节", connID, r.URL.Path, len(result)))
+			return
+		}
+	}
+
 	// 1. 找配置了 supports_count_tokens: true 的端点
-	supportedEndpoints := h.getSupportedEndpoints()
+	supportedEndpoints := h.getSupportedEndpoints(ctx)
 
 	// 2. 如果有，尝试转发
 	if len(supportedEndpoints) > 0 {
-		if result, ok := h.tryForward(ctx, r, bodyBytes, supportedEndpoints, connID); ok {
+		if header, result, ok := h.tryForward(ctx, r, bodyBytes, supportedEndpoints, connID); ok {
+			if h.responseCache != nil {
+				h.responseCache.Store(ctx, r.URL.Path, bodyBytes, header, result)
+			}
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			w.Write(result)
@@ -71,9 +96,9 @@ func (h *CountTokensHandler) Handle(ctx context.Context, w http.ResponseWriter,
 	h.respondWithEstimation(w, bodyBytes, connID)
 }
 
-// getSupportedEndpoints 获取支持count_tokens的端点
-func (h *CountTokensHandler) getSupportedEndpoints() []*endpoint.Endpoint {
-	allEndpoints := h.endpointManager.GetHealthyEndpoints()
+// getSupportedEndpoints 获取支持count_tokens的端点（遵循请求模型的路由规则）
+func (h *CountTokensHandler) getSupportedEndpoints(ctx context.Context) []*endpoint.Endpoint {
+	allEndpoints := h.endpointManager.GetEndpointsForRequest(ctx)
 	var supported []*endpoint.Endpoint
 
 	for _, ep := range allEndpoints {
@@ -85,8 +110,8 @@ func (h *CountTokensHandler) getSupportedEndpoints() []*endpoint.Endpoint {
 	return supported
 }
 
-// tryForward 尝试转发到支持的端点
-func (h *CountTokensHandler) tryForward(ctx context.Context, r *http.Request, bodyBytes []byte, endpoints []*endpoint.Endpoint, connID string) ([]byte, bool) {
+// tryForward 尝试转发到支持的端点，成功时返回上游响应头和响应体
+func (h *CountTokensHandler) tryForward(ctx context.Context, r *http.Request, bodyBytes []byte, endpoints []*endpoint.Endpoint, connID string) (http.Header, []byte, bool) {
 	for _, ep := range endpoints {
 		targetURL := ep.Config.URL + "/v1/messages/count_tokens"
 		req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewReader(bodyBytes))
@@ -96,7 +121,7 @@ func (h *CountTokensHandler) tryForward(ctx context.Context, r *http.Request, bo
 
 		h.forwarder.CopyHeaders(r, req, ep)
 
-		httpTransport, err := transport.CreateTransport(h.config)
+		httpTransport, err := h.forwarder.RoundTripper(ep, transport.ProfileRegular)
 		if err != nil {
 			continue
 		}
@@ -116,12 +141,12 @@ func (h *CountTokensHandler) tryForward(ctx context.Context, r *http.Request, bo
 		if resp.StatusCode == http.StatusOK {
 			if bodyBytes, err := io.ReadAll(resp.Body); err == nil {
 				slog.Info(fmt.Sprintf("✅ [转发成功] [%s] 端点: %s", connID, ep.Config.Name))
-				return bodyBytes, true
+				return resp.Header, bodyBytes, true
 			}
 		}
 	}
 
-	return nil, false
+	return nil, nil, false
 }
 
 // respondWithEstimation 返回本地估算结果
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/endpoint"
+)
+
+// countTokensRouter 测试用模型路由：claude-sonnet 只允许 sonnet-relay
+type countTokensRouter struct{}
+
+func (countTokensRouter) HasRoutes() bool { return true }
+
+func (countTokensRouter) MatchModel(model string) (*endpoint.ModelRoute, bool) {
+	if model == "claude-sonnet" {
+		return &endpoint.ModelRoute{Rule: "sonnet", Endpoints: []string{"sonnet-relay"}}, true
+	}
+	return nil, false
+}
+
+func TestCountTokensHandler_FollowsModelRouting(t *testing.T) {
+	hits := make(map[string]int)
+	newUpstream := func(name string, tokens string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[name]++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"input_tokens":` + tokens + `}`))
+		}))
+	}
+	opus := newUpstream("opus-relay", "1")
+	defer opus.Close()
+	sonnet := newUpstream("sonnet-relay", "2")
+	defer sonnet.Close()
+
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: endpoint.StrategyPriority},
+		Failover: config.FailoverConfig{Enabled: true},
+		Endpoints: []config.EndpointConfig{
+			{Name: "opus-relay", URL: opus.URL, Priority: 1, SupportsCountTokens: true, Timeout: 5 * time.Second},
+			{Name: "sonnet-relay", URL: sonnet.URL, Priority: 2, SupportsCountTokens: true, Timeout: 5 * time.Second},
+		},
+	}
+	m := endpoint.NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("opus-relay"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+	m.SetModelRouter(countTokensRouter{})
+
+	h := NewCountTokensHandler(cfg, m, NewForwarder(cfg, m))
+	body := []byte(`{"model":"claude-sonnet","messages":[{"role":"user","content":"hi"}]}`)
+	ctx := endpoint.WithRequestModel(context.Background(), "claude-sonnet")
+	req := httptest.NewRequest(http.MethodPost, "/v1/messages/count_tokens", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	h.Handle(ctx, rec, req, body, "req-1")
+
+	if hits["opus-relay"] != 0 || hits["sonnet-relay"] != 1 {
+		t.Fatalf("count_tokens 应只转发到路由允许的端点: %v", hits)
+	}
+	if !strings.Contains(rec.Body.String(), `"input_tokens":2`) {
+		t.Errorf("响应不符: %s", rec.Body.String())
+	}
+}
+
+// memoryResponseCache 测试用内存响应缓存
+type memoryResponseCache map[string][]byte
+
+func (c memoryResponseCache) Lookup(ctx context.Context, path string, body []byte) (http.Header, []byte, bool) {
+	cached, ok := c[path+string(body)]
+	return http.Header{"Content-Type": {"application/json"}}, cached, ok
+}
+
+func (c memoryResponseCache) Store(ctx context.Context, path string, body []byte, header http.Header, responseBody []byte) {
+	c[path+string(body)] = responseBody
+}
+
+func TestCountTokensHandler_ResponseCache(t *testing.T) {
+	hits := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"input_tokens":42}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Strategy: config.StrategyConfig{Type: endpoint.StrategyPriority},
+		Endpoints: []config.EndpointConfig{
+			{Name: "relay", URL: upstream.URL, Priority: 1, SupportsCountTokens: true, Timeout: 5 * time.Second},
+		},
+	}
+	m := endpoint.NewManager(cfg)
+	for _, ep := range m.GetAllEndpoints() {
+		ep.Status.Healthy = true
+	}
+	m.GetGroupManager().UpdateGroups(m.GetAllEndpoints())
+	if err := m.GetGroupManager().ManualActivateGroup("relay"); err != nil {
+		t.Fatalf("激活端点失败: %v", err)
+	}
+
+	h := NewCountTokensHandler(cfg, m, NewForwarder(cfg, m))
+	h.SetResponseCache(memoryResponseCache{})
+	body := []byte(`{"model":"claude-sonnet","messages":[{"role":"user","content":"hi"}]}`)
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/messages/count_tokens", nil)
+		rec := httptest.NewRecorder()
+		h.Handle(req.Context(), rec, req, body, "req-cache")
+		return rec
+	}
+
+	if first := send(); first.Header().Get(ResponseCacheHeader) != "" {
+		t.Fatal("首次请求不应命中缓存")
+	}
+	second := send()
+	if second.Header().Get(ResponseCacheHeader) != "HIT" || second.Body.String() != `{"input_tokens":42}` {
+		t.Errorf("第二次请求应命中缓存: %v %s", second.Header(), second.Body.String())
+	}
+	if hits != 1 {
+		t.Errorf("命中缓存时不应转发到上游，转发次数: %d", hits)
+	}
+}
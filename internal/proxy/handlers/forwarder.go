@@ -3,14 +3,19 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"cc-forwarder/config"
+	"cc-forwarder/internal/capture"
 	"cc-forwarder/internal/endpoint"
+	"cc-forwarder/internal/tracing"
 	"cc-forwarder/internal/transport"
 )
 
@@ -18,6 +23,7 @@ import (
 type Forwarder struct {
 	config          *config.Config
 	endpointManager *endpoint.Manager
+	capture         *capture.Recorder
 }
 
 // NewForwarder 创建新的Forwarder实例
@@ -28,7 +34,13 @@ func NewForwarder(cfg *config.Config, endpointManager *endpoint.Manager) *Forwar
 	}
 }
 
+// SetCapture 设置请求抓取记录器
+func (f *Forwarder) SetCapture(recorder *capture.Recorder) {
+	f.capture = recorder
+}
+
 // ForwardRequestToEndpoint 转发请求到指定端点
+// 端点配置了模型映射时，请求体中的模型名称会替换为端点的上游名称（见 modelMappingTransport）
 func (f *Forwarder) ForwardRequestToEndpoint(ctx context.Context, r *http.Request, bodyBytes []byte, ep *endpoint.Endpoint) (*http.Response, error) {
 	// 创建目标URL
 	targetURL := ep.Config.URL + r.URL.Path
@@ -45,31 +57,12 @@ func (f *Forwarder) ForwardRequestToEndpoint(ctx context.Context, r *http.Reques
 	// 复制和修改头部
 	f.CopyHeaders(r, req, ep)
 
-	// 创建HTTP传输
-	httpTransport, err := transport.CreateTransport(f.config)
+	// 获取端点复用的流式 Transport（保持连接，避免每次请求重新握手）
+	httpTransport, err := f.RoundTripper(ep, transport.ProfileStreaming)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
-	
-	// 优化传输设置用于流式处理
-	httpTransport.DisableKeepAlives = false
-	httpTransport.MaxIdleConns = 10
-	httpTransport.MaxIdleConnsPerHost = 2
-	httpTransport.IdleConnTimeout = 0 // 无空闲超时
-	httpTransport.TLSHandshakeTimeout = 10 * time.Second
-	httpTransport.ExpectContinueTimeout = 1 * time.Second
-
-	// 从配置中读取响应头超时时间，默认60秒
-	responseHeaderTimeout := f.config.Streaming.ResponseHeaderTimeout
-	if responseHeaderTimeout == 0 {
-		responseHeaderTimeout = 60 * time.Second
-	}
-	httpTransport.ResponseHeaderTimeout = responseHeaderTimeout
-
-	httpTransport.DisableCompression = true // 禁用压缩以防缓冲延迟
-	httpTransport.WriteBufferSize = 4096    // 较小的写缓冲区
-	httpTransport.ReadBufferSize = 4096     // 较小的读缓冲区
-	
+
 	client := &http.Client{
 		Timeout:   0, // 流式请求无超时
 		Transport: httpTransport,
@@ -90,6 +83,137 @@ func (f *Forwarder) ForwardRequestToEndpoint(ctx context.Context, r *http.Reques
 	return resp, nil
 }
 
+// RoundTripper 获取端点的复用 Transport
+// 按端点和档位缓存在端点管理器的连接池中，端点或代理配置变化时自动重建
+// 启用请求抓取时在最内层记录实际发往端点的请求和原始响应
+// 端点配置了模型映射时改写请求和响应中的模型名称
+// 外层包装用于上报真实请求延迟（ewma 策略）、熔断器统计和多 Key 端点各 Key 的响应状态
+func (f *Forwarder) RoundTripper(ep *endpoint.Endpoint, profile transport.Profile) (http.RoundTripper, error) {
+	var rt http.RoundTripper
+	var err error
+	if f.endpointManager != nil && f.endpointManager.GetTransportPool() != nil {
+		rt, err = f.endpointManager.GetTransportPool().Get(&ep.Config, profile)
+	} else {
+		rt, err = transport.NewTransport(f.config, &ep.Config, profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.capture != nil {
+		secretHeaders := make([]string, 0, len(ep.Config.Headers))
+		for name := range ep.Config.Headers {
+			secretHeaders = append(secretHeaders, name)
+		}
+		rt = f.capture.Wrap(rt, ep.Config.Name, secretHeaders...)
+	}
+
+	if len(ep.Config.ModelMapping) > 0 {
+		rt = &modelMappingTransport{base: rt, endpoint: ep.Config.Name, mapping: ep.Config.ModelMapping}
+	}
+
+	if f.endpointManager == nil {
+		return rt, nil
+	}
+	return &reportingTransport{
+		base:       rt,
+		manager:    f.endpointManager,
+		ep:         ep,
+		reportKeys: len(ep.Config.Tokens) > 1 || len(ep.Config.ApiKeys) > 1,
+	}, nil
+}
+
+// reportingTransport 在收到响应后上报端点延迟、熔断器统计和所用 Key 的状态，并统计端点在途请求数
+type reportingTransport struct {
+	base       http.RoundTripper
+	manager    *endpoint.Manager
+	ep         *endpoint.Endpoint
+	reportKeys bool // 多 Key 端点上报 Key 状态（用于自动轮换）
+}
+
+// RoundTrip 执行请求并上报结果
+// 端点已达到并发或 RPM/TPM 上限时不发出请求，返回 endpoint.ErrEndpointSaturated
+func (t *reportingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.manager.TryAcquireCapacity(t.ep) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("端点 %s: %w", t.ep.Config.Name, endpoint.ErrEndpointSaturated)
+	}
+	trial := t.manager.AcquireCircuit(t.ep)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.manager.ReleaseCapacity(t.ep)
+		// 客户端取消不计入熔断统计，归还试探名额
+		if errors.Is(req.Context().Err(), context.Canceled) {
+			if trial {
+				t.manager.ReleaseCircuit(t.ep)
+			}
+		} else {
+			t.manager.RecordCircuitResult(t.ep, trial, true, 0)
+		}
+		return resp, err
+	}
+
+	elapsed := time.Since(start)
+	isMessages := strings.HasSuffix(req.URL.Path, "/v1/messages")
+
+	// 只统计 Messages 请求的成功响应，避免 count_tokens 及快速返回的 429/5xx 拉低 EWMA
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && isMessages {
+		t.manager.RecordLatency(t.ep, elapsed)
+	}
+
+	// 熔断统计：5xx 和 429 计为失败，Messages 请求的响应头耗时参与慢请求统计
+	var latency time.Duration
+	if isMessages {
+		latency = elapsed
+	}
+	failure := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	t.manager.RecordCircuitResult(t.ep, trial, failure, latency)
+
+	// 上游限流响应头：记录剩余额度，限流时按 Retry-After 冷却端点
+	retryAfter := t.manager.RecordRateLimit(t.ep, resp.StatusCode, resp.Header)
+
+	if t.reportKeys {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		t.manager.ReportKeyResult(t.ep, token, req.Header.Get("X-Api-Key"), resp.StatusCode, retryAfter)
+	}
+
+	// 响应体关闭（流式响应读取结束）时才归还在途名额
+	resp.Body = &capacityBody{ReadCloser: resp.Body, manager: t.manager, ep: t.ep}
+	return resp, nil
+}
+
+// upstreamRetryAfter 失败响应自身要求的等待时间（Retry-After，429 时还包括已耗尽额度的重置时间），未要求时返回 0
+func upstreamRetryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	snapshot, _ := endpoint.ParseRateLimitHeaders(resp.Header, resp.StatusCode, time.Now())
+	return snapshot.RetryAfter
+}
+
+// isEndpointSaturated 请求是否因端点容量已满而未发出（应跳过该端点，不计为端点失败）
+func isEndpointSaturated(err error) bool {
+	return errors.Is(err, endpoint.ErrEndpointSaturated)
+}
+
+// capacityBody 响应体关闭时归还端点的在途名额
+type capacityBody struct {
+	io.ReadCloser
+	manager   *endpoint.Manager
+	ep        *endpoint.Endpoint
+	closeOnce sync.Once
+}
+
+func (b *capacityBody) Close() error {
+	b.closeOnce.Do(func() {
+		b.manager.ReleaseCapacity(b.ep)
+	})
+	return b.ReadCloser.Close()
+}
+
 // CopyHeaders 复制头部逻辑
 func (f *Forwarder) CopyHeaders(src *http.Request, dst *http.Request, ep *endpoint.Endpoint) {
 	// List of headers to skip/remove
@@ -117,14 +241,14 @@ func (f *Forwarder) CopyHeaders(src *http.Request, dst *http.Request, ep *endpoi
 		dst.Host = u.Host
 	}
 
-	// Add or override Authorization header with dynamically resolved token
-	token := f.endpointManager.GetTokenForEndpoint(ep)
+	// Add or override Authorization header with dynamically resolved token (按轮换策略分配)
+	token := f.endpointManager.SelectTokenForRequest(ep)
 	if token != "" {
 		dst.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	// Add or override X-Api-Key header with dynamically resolved api-key
-	apiKey := f.endpointManager.GetApiKeyForEndpoint(ep)
+	apiKey := f.endpointManager.SelectApiKeyForRequest(ep)
 	if apiKey != "" {
 		dst.Header.Set("X-Api-Key", apiKey)
 	}
@@ -134,6 +258,9 @@ func (f *Forwarder) CopyHeaders(src *http.Request, dst *http.Request, ep *endpoi
 		dst.Header.Set(key, value)
 	}
 
+	// Propagate trace context to the endpoint (replaces the client's traceparent when tracing is enabled)
+	tracing.Inject(dst.Context(), dst.Header)
+
 	// Remove hop-by-hop headers
 	hopByHopHeaders := []string{
 		"Connection",
@@ -146,4 +146,52 @@ func TestForwarder_CopyHeaders(t *testing.T) {
 	if dstReq.Header.Get("X-API-Key") == "client-api-key" {
 		t.Errorf("Expected client X-API-Key to be removed")
 	}
-}
\ No newline at end of file
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"空值", "", 0},
+		{"秒数", "30", 30 * time.Second},
+		{"负数", "-5", 0},
+		{"HTTP 日期", now.Add(2 * time.Minute).Format(http.TimeFormat), 2 * time.Minute},
+		{"过去的日期", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"无效值", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := endpoint.ParseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpstreamRetryAfter(t *testing.T) {
+	response := func(status int, pairs ...string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			resp.Header.Set(pairs[i], pairs[i+1])
+		}
+		return resp
+	}
+
+	if got := upstreamRetryAfter(response(http.StatusTooManyRequests, "Retry-After", "15")); got != 15*time.Second {
+		t.Errorf("429 应使用响应自身的 Retry-After, 实际 %v", got)
+	}
+	if got := upstreamRetryAfter(response(http.StatusServiceUnavailable, "Retry-After", "3")); got != 3*time.Second {
+		t.Errorf("503 应使用响应自身的 Retry-After, 实际 %v", got)
+	}
+	if got := upstreamRetryAfter(response(http.StatusTooManyRequests)); got != 0 {
+		t.Errorf("未带 Retry-After 时应返回 0, 实际 %v", got)
+	}
+	if got := upstreamRetryAfter(nil); got != 0 {
+		t.Errorf("无响应时应返回 0, 实际 %v", got)
+	}
+}
@@ -40,6 +40,7 @@ func (sr SuspensionResult) String() string {
 type RequestLifecycleManager interface {
 	GetRequestID() string
 	SetEndpoint(name, group, channel string)
+	SetModelMapping(mapping map[string]string)               // 设置当前端点的模型映射
 	SetModel(modelName string)                               // 简单设置模型
 	SetModelWithComparison(modelName, source string)        // 带对比的设置模型
 	HasModel() bool                                          // 检查是否已有模型
@@ -60,6 +61,14 @@ type RequestLifecycleManager interface {
 	MapErrorTypeToFailureReason(errorType ErrorType) string // 映射ErrorType到failure_reason
 	FailRequest(failureReason, errorDetail string, httpStatus int) // 标记请求为最终失败
 	CancelRequest(cancelReason string, tokens *tracking.TokenUsage) // 标记请求被取消
+	MarkFirstByte()                                                 // 记录首个响应字节写给客户端的时间
+	SetStreamTimings(firstByte, firstContent time.Time)             // 记录流式响应的首字节和首个内容增量时间
+	CompleteFromCache(tokens *tracking.TokenUsage)                  // 以缓存命中完成请求（不计成本）
+	// 链路追踪：端点尝试与流式传输的 span
+	StartAttempt(ctx context.Context, endpointName string, attempt int) context.Context
+	EndAttempt(resp *http.Response, err error)
+	StartStream(ctx context.Context) context.Context
+	EndStream(tokens *tracking.TokenUsage, err error)
 }
 
 // ErrorRecoveryManager 错误恢复管理器接口
@@ -79,6 +88,9 @@ type ErrorContext struct {
 	OriginalError  error
 	RetryableAfter time.Duration
 	MaxRetries     int
+	StatusCode     int // 上游响应状态码（非 HTTP 状态码错误时为 0），用于按状态码匹配重试策略
+	// 失败响应通过 Retry-After 或 anthropic-ratelimit-* 响应头要求的等待时间（未返回时为 0）
+	UpstreamRetryAfter time.Duration
 }
 
 // ErrorType 错误类型枚举
@@ -122,6 +134,7 @@ type TokenParser interface {
 // 修改版本：返回Token使用信息和模型名称而非直接记录到usageTracker
 type StreamProcessor interface {
 	ProcessStreamWithRetry(ctx context.Context, resp *http.Response) (*tracking.TokenUsage, string, error)
+	GetTimings() (firstByte, firstContent time.Time) // 首字节和首个内容增量的时间
 }
 
 // RetryHandler 重试处理器接口  
@@ -212,6 +225,13 @@ type RetryManager interface {
 	ShouldRetryWithDecision(errorCtx *ErrorContext, localAttempt int, globalAttempt int, isStreaming bool) RetryDecision
 }
 
+// ResponseCache 响应缓存接口（由 service.ResponseCacheService 实现）
+// 请求不可缓存（流式、非确定性、路径不支持）时 Lookup 返回 false，Store 不做任何操作
+type ResponseCache interface {
+	Lookup(ctx context.Context, path string, body []byte) (http.Header, []byte, bool)
+	Store(ctx context.Context, path string, body []byte, header http.Header, responseBody []byte)
+}
+
 // SuspensionManager 挂起管理器接口
 type SuspensionManager interface {
 	ShouldSuspend(ctx context.Context) bool
@@ -219,6 +239,8 @@ type SuspensionManager interface {
 	WaitForEndpointRecovery(ctx context.Context, connID, failedEndpoint string) bool // 🚀 [端点自愈] 新增端点恢复等待方法
 	// 🎯 [挂起取消区分] 新增带结果的端点恢复等待方法，能区分成功/超时/取消
 	WaitForEndpointRecoveryWithResult(ctx context.Context, connID, failedEndpoint string) SuspensionResult
+	// 所有可用端点都达到并发或 RPM/TPM 上限时排队等待容量释放
+	WaitForEndpointCapacity(ctx context.Context, connID string) SuspensionResult
 	GetSuspendedRequestsCount() int
 	// 🔧 [热更新] 更新配置
 	UpdateConfig(cfg *config.Config)
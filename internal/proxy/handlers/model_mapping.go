@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// modelMappingTransport 按端点模型映射改写请求体中的模型名称
+// 响应中的上游模型名称会映射回客户端请求的模型，客户端始终只看到规范名称
+type modelMappingTransport struct {
+	base     http.RoundTripper
+	endpoint string
+	mapping  map[string]string // 客户端模型 -> 上游模型
+}
+
+// RoundTrip 改写请求模型并包装响应体
+func (t *modelMappingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	mapped, model, upstream := mapRequestModel(body, t.mapping)
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(mapped))
+	out.ContentLength = int64(len(mapped))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(mapped)), nil
+	}
+
+	if upstream == "" {
+		return t.base.RoundTrip(out)
+	}
+
+	// 要求上游返回未压缩的响应，以便逐行改写模型名称
+	out.Header.Set("Accept-Encoding", "identity")
+	slog.Debug(fmt.Sprintf("🔁 [模型映射] 端点: %s, 模型: %s -> %s", t.endpoint, model, upstream))
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		return resp, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "" && encoding != "identity" {
+		slog.Warn(fmt.Sprintf("⚠️ [模型映射] 端点 %s 返回了压缩响应 (%s)，跳过响应模型名称映射", t.endpoint, encoding))
+		return resp, nil
+	}
+
+	resp.Body = newModelRewriteReader(resp.Body, upstream, model)
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// mapRequestModel 按映射改写请求体顶层的 model 字段（保持其余内容不变）
+// 返回改写后的请求体、客户端模型和上游模型；未命中映射时原样返回，上游模型为空
+func mapRequestModel(body []byte, mapping map[string]string) ([]byte, string, string) {
+	start, end, model, ok := findTopLevelModel(body)
+	if !ok {
+		return body, model, ""
+	}
+
+	upstream := mapping[model]
+	if upstream == "" || upstream == model {
+		return body, model, ""
+	}
+
+	quoted, err := json.Marshal(upstream)
+	if err != nil {
+		return body, model, ""
+	}
+
+	mapped := make([]byte, 0, len(body)-(end-start)+len(quoted))
+	mapped = append(mapped, body[:start]...)
+	mapped = append(mapped, quoted...)
+	mapped = append(mapped, body[end:]...)
+	return mapped, model, upstream
+}
+
+// findTopLevelModel 查找 JSON 对象顶层 model 字段值的位置 [start, end)
+func findTopLevelModel(body []byte) (int, int, string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return 0, 0, "", false
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, "", false
+		}
+		key, _ := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return 0, 0, "", false
+		}
+		if key != "model" {
+			continue
+		}
+
+		var model string
+		if err := json.Unmarshal(raw, &model); err != nil {
+			return 0, 0, "", false
+		}
+		end := int(dec.InputOffset())
+		return end - len(raw), end, model, true
+	}
+
+	return 0, 0, "", false
+}
+
+// modelRewriteReader 将响应中的 "model":"上游模型" 替换为客户端模型
+// 按上游数据块转发，只暂存块末尾不完整的行，SSE 事件以换行结束，不会延迟事件转发
+type modelRewriteReader struct {
+	src     io.ReadCloser
+	pattern *regexp.Regexp
+	from    []byte // 上游模型（JSON 字符串形式）
+	to      []byte // 客户端模型（JSON 字符串形式）
+	buf     []byte
+	partial []byte // 尚未收到换行的行
+	pending []byte
+	err     error
+}
+
+// newModelRewriteReader 创建响应模型名称改写读取器
+func newModelRewriteReader(rc io.ReadCloser, upstream, model string) io.ReadCloser {
+	from, _ := json.Marshal(upstream)
+	to, _ := json.Marshal(model)
+	return &modelRewriteReader{
+		src:     rc,
+		pattern: regexp.MustCompile(`"model"\s*:\s*` + regexp.QuoteMeta(string(from))),
+		from:    from,
+		to:      to,
+		buf:     make([]byte, 32*1024),
+	}
+}
+
+// Read 实现 io.Reader
+func (r *modelRewriteReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			if len(r.partial) == 0 {
+				return 0, r.err
+			}
+			r.pending, r.partial = r.rewrite(r.partial), nil
+			break
+		}
+
+		n, err := r.src.Read(r.buf)
+		r.err = err
+		data := append(r.partial, r.buf[:n]...)
+		idx := bytes.LastIndexByte(data, '\n')
+		if idx < 0 {
+			r.partial = data
+			continue
+		}
+		r.pending = r.rewrite(data[:idx+1])
+		r.partial = append([]byte(nil), data[idx+1:]...)
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// rewrite 替换数据中的模型名称
+func (r *modelRewriteReader) rewrite(line []byte) []byte {
+	if !bytes.Contains(line, r.from) {
+		return line
+	}
+	return r.pattern.ReplaceAllFunc(line, func(match []byte) []byte {
+		out := make([]byte, 0, len(match)-len(r.from)+len(r.to))
+		out = append(out, match[:len(match)-len(r.from)]...)
+		return append(out, r.to...)
+	})
+}
+
+// Close 关闭底层响应体
+func (r *modelRewriteReader) Close() error {
+	return r.src.Close()
+}
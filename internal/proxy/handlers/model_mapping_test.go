@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/endpoint"
+)
+
+func TestMapRequestModel(t *testing.T) {
+	mapping := map[string]string{"claude-sonnet-4-5": "vendor/claude-sonnet"}
+
+	tests := []struct {
+		name         string
+		body         string
+		wantBody     string
+		wantModel    string
+		wantUpstream string
+	}{
+		{
+			name:         "命中映射",
+			body:         `{"model":"claude-sonnet-4-5","max_tokens":10}`,
+			wantBody:     `{"model":"vendor/claude-sonnet","max_tokens":10}`,
+			wantModel:    "claude-sonnet-4-5",
+			wantUpstream: "vendor/claude-sonnet",
+		},
+		{
+			name:         "保持字段顺序和空白",
+			body:         "{\n  \"stream\": true,\n  \"model\" : \"claude-sonnet-4-5\",\n  \"metadata\": {\"model\": \"x\"}\n}",
+			wantBody:     "{\n  \"stream\": true,\n  \"model\" : \"vendor/claude-sonnet\",\n  \"metadata\": {\"model\": \"x\"}\n}",
+			wantModel:    "claude-sonnet-4-5",
+			wantUpstream: "vendor/claude-sonnet",
+		},
+		{
+			name:      "嵌套字段不改写",
+			body:      `{"messages":[{"role":"user","content":{"model":"claude-sonnet-4-5"}}],"model":"claude-opus-4-1"}`,
+			wantBody:  `{"messages":[{"role":"user","content":{"model":"claude-sonnet-4-5"}}],"model":"claude-opus-4-1"}`,
+			wantModel: "claude-opus-4-1",
+		},
+		{
+			name:     "非 JSON 请求体",
+			body:     `not json`,
+			wantBody: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, model, upstream := mapRequestModel([]byte(tt.body), mapping)
+			if string(body) != tt.wantBody {
+				t.Errorf("请求体 = %s，期望 %s", body, tt.wantBody)
+			}
+			if model != tt.wantModel || upstream != tt.wantUpstream {
+				t.Errorf("模型 = (%q, %q)，期望 (%q, %q)", model, upstream, tt.wantModel, tt.wantUpstream)
+			}
+		})
+	}
+}
+
+func TestModelRewriteReader(t *testing.T) {
+	stream := "event: message_start\n" +
+		`data: {"type":"message_start","message":{"id":"msg_1","model":"vendor/claude-sonnet","content":[]}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"{\"model\":\"vendor/claude-sonnet\"}"}}` + "\n\n" +
+		`{"model": "vendor/claude-sonnet"}`
+
+	want := "event: message_start\n" +
+		`data: {"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4-5","content":[]}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"{\"model\":\"vendor/claude-sonnet\"}"}}` + "\n\n" +
+		`{"model": "claude-sonnet-4-5"}`
+
+	// 上游数据块可能在行中间截断
+	sources := map[string]io.Reader{
+		"整块读取":  strings.NewReader(stream),
+		"逐字节读取": iotest.OneByteReader(strings.NewReader(stream)),
+	}
+	for name, src := range sources {
+		reader := newModelRewriteReader(io.NopCloser(src), "vendor/claude-sonnet", "claude-sonnet-4-5")
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("%s: 读取失败: %v", name, err)
+		}
+		if string(out) != want {
+			t.Errorf("%s: 改写结果不正确:\n%s\n期望:\n%s", name, out, want)
+		}
+	}
+}
+
+func TestForwarder_ModelMapping(t *testing.T) {
+	var upstreamModel, acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		upstreamModel = body.Model
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"type":"message_start","message":{"model":"`+body.Model+`"}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	ep := &endpoint.Endpoint{Config: config.EndpointConfig{
+		Name:         "vendor",
+		URL:          server.URL,
+		Timeout:      30 * time.Second,
+		ModelMapping: map[string]string{"claude-sonnet-4-5": "vendor/claude-sonnet"},
+	}}
+	forwarder := NewForwarder(cfg, endpoint.NewManager(cfg))
+
+	forward := func(model string) string {
+		t.Helper()
+		bodyBytes := []byte(`{"model":"` + model + `","stream":true}`)
+		req := httptest.NewRequest("POST", "/v1/messages", bytes.NewReader(bodyBytes))
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		resp, err := forwarder.ForwardRequestToEndpoint(context.Background(), req, bodyBytes, ep)
+		if err != nil {
+			t.Fatalf("转发失败: %v", err)
+		}
+		defer resp.Body.Close()
+		out, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("读取响应失败: %v", err)
+		}
+		return string(out)
+	}
+
+	// 命中映射：上游收到映射后的名称，客户端看到规范名称
+	out := forward("claude-sonnet-4-5")
+	if upstreamModel != "vendor/claude-sonnet" {
+		t.Errorf("上游收到的模型 = %q，期望 vendor/claude-sonnet", upstreamModel)
+	}
+	if acceptEncoding != "identity" {
+		t.Errorf("映射请求的 Accept-Encoding = %q，期望 identity", acceptEncoding)
+	}
+	if !strings.Contains(out, `"model":"claude-sonnet-4-5"`) {
+		t.Errorf("响应模型未映射回客户端模型: %s", out)
+	}
+
+	// 未命中映射：请求原样转发
+	out = forward("claude-opus-4-1")
+	if upstreamModel != "claude-opus-4-1" {
+		t.Errorf("上游收到的模型 = %q，期望 claude-opus-4-1", upstreamModel)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("未映射请求的 Accept-Encoding = %q，期望 gzip", acceptEncoding)
+	}
+	if !strings.Contains(out, `"model":"claude-opus-4-1"`) {
+		t.Errorf("响应不应被改写: %s", out)
+	}
+}
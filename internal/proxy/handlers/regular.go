@@ -31,6 +31,8 @@ type RegularHandler struct {
 	suspensionManagerFactory SuspensionManagerFactory
 	// 🔧 [修复] 共享SuspensionManager实例，确保全局挂起限制生效
 	sharedSuspensionManager SuspensionManager
+	// 响应缓存（未启用时为 nil）
+	responseCache ResponseCache
 }
 
 // NewRegularHandler 创建新的RegularHandler实例
@@ -88,6 +90,11 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 
 	slog.Info(fmt.Sprintf("🔄 [常规架构] [%s] 使用unified v3架构", connID))
 
+	// 💾 [响应缓存] 命中时直接返回缓存响应，不选择端点
+	if rh.serveFromCache(ctx, w, r, bodyBytes, lifecycleManager) {
+		return
+	}
+
 	// 创建管理器 - 修复依赖注入
 	retryMgr := rh.retryManagerFactory.NewRetryManager()
 	errorRecovery := rh.errorRecoveryFactory.NewErrorRecoveryManager(rh.usageTracker)
@@ -96,6 +103,13 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 	for {
 		// 获取端点列表
 		endpoints := retryMgr.GetHealthyEndpoints(ctx)
+		if len(endpoints) == 0 && rh.endpointManager.HasSaturatedEndpoints(ctx) {
+			// 🚦 [容量排队] 端点仅因并发或 RPM/TPM 上限被跳过，排队等待容量释放而不是回退到已满的端点
+			if !rh.waitForEndpointCapacity(ctx, w, r, lifecycleManager) {
+				return
+			}
+			continue
+		}
 		if len(endpoints) == 0 {
 			// 创建特殊错误，交给错误分类和重试系统处理
 			noHealthyErr := fmt.Errorf("no healthy endpoints available")
@@ -104,8 +118,8 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 
 			if errorCtx.ErrorType == ErrorTypeNoHealthyEndpoints {
 				// 尝试获取所有活跃端点，忽略健康状态
-				allActiveEndpoints := rh.endpointManager.GetGroupManager().FilterEndpointsByActiveGroups(
-					rh.endpointManager.GetAllEndpoints())
+				allActiveEndpoints := rh.endpointManager.FilterEndpointsForModel(ctx,
+					rh.endpointManager.GetGroupManager().FilterEndpointsByActiveGroups(rh.endpointManager.GetAllEndpoints()))
 
 				if len(allActiveEndpoints) > 0 {
 					slog.InfoContext(ctx, fmt.Sprintf("🔄 [健康检查回退] [%s] 忽略健康状态，尝试 %d 个活跃端点",
@@ -132,8 +146,12 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 
 		// 内层循环处理端点重试
 		groupSwitchNeeded := false
+		saturatedEndpoints := 0           // 发出请求时容量已满而跳过的端点数
+		var lastFailed *endpoint.Endpoint // 最后一个真正失败的端点，用于请求级故障转移
 		for i, endpoint := range endpoints {
+			endpointSaturated := false
 			lifecycleManager.SetEndpoint(endpoint.Config.Name, endpoint.Config.Group, endpoint.Config.Channel)
+			lifecycleManager.SetModelMapping(endpoint.Config.ModelMapping)
 			lifecycleManager.UpdateStatus("forwarding", i, 0)
 
 			// 🔧 [端点上下文修复] 立即设置端点信息到请求上下文，确保所有分支（成功/失败/取消）的日志都能正确记录端点
@@ -154,11 +172,20 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 				default:
 				}
 
-				// 🔢 [关键修复] 每次尝试开始时增加全局计数 - 确保生命周期和重试策略正确
-				globalAttemptCount := lifecycleManager.IncrementAttempt()
-
 				// 执行请求
-				resp, err := rh.executeRequest(ctx, r, bodyBytes, endpoint)
+				attemptCtx := lifecycleManager.StartAttempt(ctx, endpoint.Config.Name, attempt)
+				resp, err := rh.executeRequest(attemptCtx, r, bodyBytes, endpoint)
+				lifecycleManager.EndAttempt(resp, err)
+
+				// 🚦 [容量限制] 端点名额已被其他请求占满，请求未发出：跳过该端点，不计为端点失败
+				if isEndpointSaturated(err) {
+					slog.Info(fmt.Sprintf("🚦 [容量已满] [%s] 端点 %s 已达到并发或 RPM/TPM 上限，跳过", connID, endpoint.Config.Name))
+					endpointSaturated = true
+					break attemptLoop
+				}
+
+				// 🔢 [关键修复] 请求确实发出后才增加全局计数 - 容量已满跳过的端点不计为尝试
+				globalAttemptCount := lifecycleManager.IncrementAttempt()
 
 				if err == nil && IsSuccessStatus(resp.StatusCode) {
 					// ✅ [重试决策] 成功请求的决策日志 - 保持监控完整性
@@ -166,7 +193,7 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 						connID, endpoint.Config.Name, attempt))
 
 					lifecycleManager.UpdateStatus("processing", globalAttemptCount, resp.StatusCode)
-					rh.processSuccessResponse(ctx, w, resp, lifecycleManager, endpoint.Config.Name, r)
+					rh.processSuccessResponse(ctx, w, resp, lifecycleManager, endpoint.Config.Name, r, bodyBytes)
 					return
 				}
 
@@ -191,6 +218,9 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 
 				// 🔧 使用增强的RetryManager进行统一决策
 				errorCtx := errorRecovery.ClassifyError(err, connID, endpoint.Config.Name, endpoint.Config.Group, attempt-1)
+				if errorCtx.StatusCode > 0 {
+					errorCtx.UpstreamRetryAfter = upstreamRetryAfter(resp)
+				}
 
 				// 🚀 [状态机重构] Phase 4: 分离状态转换与失败原因记录
 				// 预设错误上下文（避免重复分类），由HandleError统一记录失败原因
@@ -275,6 +305,12 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 				}
 			}
 
+			if endpointSaturated {
+				saturatedEndpoints++
+				continue
+			}
+			lastFailed = endpoint
+
 			// 如果需要组切换，跳出端点循环
 			if groupSwitchNeeded {
 				break
@@ -286,9 +322,17 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 			continue
 		}
 
+		// 🚦 [容量排队] 所有端点都因容量已满被跳过，排队等待容量释放后重新选择端点
+		if saturatedEndpoints == len(endpoints) {
+			if !rh.waitForEndpointCapacity(ctx, w, r, lifecycleManager) {
+				return
+			}
+			continue
+		}
+
 		// 所有端点都失败了，尝试触发请求级别故障转移
-		if len(endpoints) > 0 {
-			lastEndpoint := endpoints[len(endpoints)-1]
+		if lastFailed != nil {
+			lastEndpoint := lastFailed
 
 			newEndpointName, err := rh.endpointManager.TriggerRequestFailover(
 				lastEndpoint.Config.Name,
@@ -319,10 +363,7 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 
 	// 检查是否应该挂起请求
 	if suspensionMgr.ShouldSuspend(ctx) {
-		currentEndpoints := rh.endpointManager.GetHealthyEndpoints()
-		if cfg := rh.endpointManager.GetConfig(); cfg != nil && cfg.Strategy.Type == "fastest" && cfg.Strategy.FastTestEnabled {
-			currentEndpoints = rh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-		}
+		currentEndpoints := rh.endpointManager.GetEndpointsForRequest(ctx)
 
 		// 🚀 [状态机重构] Phase 4: 挂起时更新状态（移除重复的失败原因记录）
 		lifecycleManager.UpdateStatus("suspended", -1, 0)
@@ -340,12 +381,7 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 			slog.Info(fmt.Sprintf("🚀 [挂起恢复] [%s] 端点已恢复或组切换完成，重新获取端点", connID))
 
 			// 重新获取健康端点
-			var newEndpoints []*endpoint.Endpoint
-			if rh.endpointManager.GetConfig().Strategy.Type == "fastest" && rh.endpointManager.GetConfig().Strategy.FastTestEnabled {
-				newEndpoints = rh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-			} else {
-				newEndpoints = rh.endpointManager.GetHealthyEndpoints()
-			}
+			newEndpoints := rh.endpointManager.GetEndpointsForRequest(ctx)
 
 			if len(newEndpoints) > 0 {
 				slog.Info(fmt.Sprintf("🔄 [重新开始] [%s] 获取到 %d 个新端点，重新开始常规处理", connID, len(newEndpoints)))
@@ -379,6 +415,24 @@ func (rh *RegularHandler) HandleRegularRequestUnified(ctx context.Context, w htt
 	http.Error(w, "All endpoints failed", http.StatusBadGateway)
 }
 
+// waitForEndpointCapacity 排队等待端点容量释放，取消或超时时结束请求并返回 false
+func (rh *RegularHandler) waitForEndpointCapacity(ctx context.Context, w http.ResponseWriter, r *http.Request, lifecycleManager RequestLifecycleManager) bool {
+	lifecycleManager.UpdateStatus("suspended", 0, 0)
+	switch rh.sharedSuspensionManager.WaitForEndpointCapacity(ctx, lifecycleManager.GetRequestID()) {
+	case SuspensionSuccess:
+		return true
+	case SuspensionCancelled:
+		*r = *r.WithContext(context.WithValue(r.Context(), "final_status_code", 499))
+		lifecycleManager.CancelRequest("suspended then cancelled", nil)
+		http.Error(w, "Request cancelled during suspension", 499)
+		return false
+	default:
+		lifecycleManager.FailRequest("endpoint_saturated", "All endpoints are saturated", http.StatusServiceUnavailable)
+		http.Error(w, "All endpoints are saturated", http.StatusServiceUnavailable)
+		return false
+	}
+}
+
 // executeRequest 执行单个请求
 func (rh *RegularHandler) executeRequest(ctx context.Context, r *http.Request, bodyBytes []byte, endpoint *endpoint.Endpoint) (*http.Response, error) {
 	// 创建目标请求
@@ -395,8 +449,8 @@ func (rh *RegularHandler) executeRequest(ctx context.Context, r *http.Request, b
 	// 复制和修改头部
 	rh.forwarder.CopyHeaders(r, req, endpoint)
 
-	// 创建HTTP传输
-	httpTransport, err := transport.CreateTransport(rh.config)
+	// 获取端点复用的 Transport
+	httpTransport, err := rh.forwarder.RoundTripper(endpoint, transport.ProfileRegular)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
@@ -411,7 +465,7 @@ func (rh *RegularHandler) executeRequest(ctx context.Context, r *http.Request, b
 }
 
 // processSuccessResponse 处理成功响应
-func (rh *RegularHandler) processSuccessResponse(ctx context.Context, w http.ResponseWriter, resp *http.Response, lifecycleManager RequestLifecycleManager, endpointName string, r *http.Request) {
+func (rh *RegularHandler) processSuccessResponse(ctx context.Context, w http.ResponseWriter, resp *http.Response, lifecycleManager RequestLifecycleManager, endpointName string, r *http.Request, bodyBytes []byte) {
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			slog.Warn("Failed to close response body", "request_id", lifecycleManager.GetRequestID(), "error", err)
@@ -436,6 +490,7 @@ func (rh *RegularHandler) processSuccessResponse(ctx context.Context, w http.Res
 	}
 
 	// 写入响应体到客户端
+	lifecycleManager.MarkFirstByte()
 	if _, err := w.Write(responseBytes); err != nil {
 		connID := lifecycleManager.GetRequestID()
 		lifecycleManager.HandleError(fmt.Errorf("failed to write response: %w", err))
@@ -445,6 +500,9 @@ func (rh *RegularHandler) processSuccessResponse(ctx context.Context, w http.Res
 		return
 	}
 
+	// 💾 [响应缓存] 保存确定性请求的成功响应
+	rh.storeInCache(ctx, r, bodyBytes, resp, responseBytes)
+
 	// ✅ 同步Token解析：简化逻辑，避免协程控制问题
 	connID := lifecycleManager.GetRequestID()
 	slog.Debug(fmt.Sprintf("🔄 [Token解析] [%s] 开始Token解析", connID))
@@ -513,8 +571,8 @@ func (rh *RegularHandler) HandleRegularRequest(ctx context.Context, w http.Respo
 		// Copy headers from original request
 		rh.forwarder.CopyHeaders(r, req, ep)
 
-		// Create HTTP client with timeout and proxy support
-		httpTransport, err := transport.CreateTransport(rh.config)
+		// Create HTTP client with timeout and proxy support (pooled per endpoint)
+		httpTransport, err := rh.forwarder.RoundTripper(ep, transport.ProfileRegular)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create transport: %w", err)
 		}
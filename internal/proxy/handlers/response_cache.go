@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"cc-forwarder/internal/tracking"
+)
+
+// ResponseCacheHeader 缓存命中时附加的响应头
+const ResponseCacheHeader = "X-Response-Cache"
+
+// SetResponseCache 设置响应缓存
+func (rh *RegularHandler) SetResponseCache(cache ResponseCache) {
+	rh.responseCache = cache
+}
+
+// serveFromCache 查找缓存响应，命中时直接写给客户端并以 cache_hit 完成请求
+func (rh *RegularHandler) serveFromCache(ctx context.Context, w http.ResponseWriter, r *http.Request, bodyBytes []byte, lifecycleManager RequestLifecycleManager) bool {
+	if rh.responseCache == nil {
+		return false
+	}
+
+	header, responseBytes, ok := rh.responseCache.Lookup(ctx, r.URL.Path, bodyBytes)
+	if !ok {
+		return false
+	}
+
+	connID := lifecycleManager.GetRequestID()
+	for key, values := range header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.Header().Set(ResponseCacheHeader, "HIT")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(responseBytes); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [响应缓存] [%s] 写入缓存响应失败: %v", connID, err))
+	}
+
+	// 缓存响应的 Token 用量仅用于展示，不计成本
+	var tokenUsage *tracking.TokenUsage
+	if r.URL.Path != "/v1/messages/count_tokens" {
+		var modelName string
+		tokenUsage, modelName = rh.tokenAnalyzer.AnalyzeResponseForTokensUnified(responseBytes, connID, "")
+		if modelName != "unknown" && modelName != "" && modelName != "default" {
+			lifecycleManager.SetModelWithComparison(modelName, "缓存响应解析")
+		}
+	}
+	lifecycleManager.CompleteFromCache(tokenUsage)
+
+	slog.Info(fmt.Sprintf("💾 [响应缓存] [%s] 命中缓存: %s, 大小: %d字节", connID, r.URL.Path, len(responseBytes)))
+	return true
+}
+
+// storeInCache 保存成功响应（只缓存 200 响应，是否可缓存由 ResponseCache 判断）
+func (rh *RegularHandler) storeInCache(ctx context.Context, r *http.Request, bodyBytes []byte, resp *http.Response, responseBytes []byte) {
+	if rh.responseCache == nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	rh.responseCache.Store(ctx, r.URL.Path, bodyBytes, resp.Header, responseBytes)
+}
@@ -180,17 +180,41 @@ func (sh *StreamingHandler) setStreamingHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Headers", "Cache-Control")
 }
 
+// waitForEndpointCapacity 排队等待端点容量释放，取消或超时时结束请求并返回 false
+func (sh *StreamingHandler) waitForEndpointCapacity(ctx context.Context, w http.ResponseWriter, r *http.Request, lifecycleManager RequestLifecycleManager, flusher http.Flusher) bool {
+	lifecycleManager.UpdateStatus("suspended", 0, 0)
+	switch sh.sharedSuspensionManager.WaitForEndpointCapacity(ctx, lifecycleManager.GetRequestID()) {
+	case SuspensionSuccess:
+		return true
+	case SuspensionCancelled:
+		*r = *r.WithContext(context.WithValue(r.Context(), "final_status_code", 499))
+		lifecycleManager.CancelRequest("suspended then cancelled", nil)
+		fmt.Fprintf(w, "data: cancelled: 客户端取消请求\n\n")
+		flusher.Flush()
+		return false
+	default:
+		lifecycleManager.FailRequest("endpoint_saturated", "All endpoints are saturated", http.StatusServiceUnavailable)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "data: error: All endpoints are saturated\n\n")
+		flusher.Flush()
+		return false
+	}
+}
+
 // executeStreamingWithRetry 执行带重试的流式处理
 func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w http.ResponseWriter, r *http.Request, bodyBytes []byte, lifecycleManager RequestLifecycleManager, flusher http.Flusher) {
 	connID := lifecycleManager.GetRequestID()
 	var lastFailedEndpoint string // 🚀 [端点自愈] 追踪最后失败的端点
 
 	// 获取健康端点
-	var endpoints []*endpoint.Endpoint
-	if sh.endpointManager.GetConfig().Strategy.Type == "fastest" && sh.endpointManager.GetConfig().Strategy.FastTestEnabled {
-		endpoints = sh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-	} else {
-		endpoints = sh.endpointManager.GetHealthyEndpoints()
+	endpoints := sh.endpointManager.GetEndpointsForRequest(ctx)
+
+	// 🚦 [容量排队] 端点仅因并发或 RPM/TPM 上限被跳过，排队等待容量释放而不是回退到已满的端点
+	for len(endpoints) == 0 && sh.endpointManager.HasSaturatedEndpoints(ctx) {
+		if !sh.waitForEndpointCapacity(ctx, w, r, lifecycleManager, flusher) {
+			return
+		}
+		endpoints = sh.endpointManager.GetEndpointsForRequest(ctx)
 	}
 
 	if len(endpoints) == 0 {
@@ -201,8 +225,8 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 
 		if errorCtx.ErrorType == ErrorTypeNoHealthyEndpoints {
 			// 尝试获取所有活跃端点，忽略健康状态
-			allActiveEndpoints := sh.endpointManager.GetGroupManager().FilterEndpointsByActiveGroups(
-				sh.endpointManager.GetAllEndpoints())
+			allActiveEndpoints := sh.endpointManager.FilterEndpointsForModel(ctx,
+				sh.endpointManager.GetGroupManager().FilterEndpointsByActiveGroups(sh.endpointManager.GetAllEndpoints()))
 
 			if len(allActiveEndpoints) > 0 {
 				slog.InfoContext(ctx, fmt.Sprintf("🔄 [健康检查回退] [%s] 忽略健康状态，尝试 %d 个活跃端点",
@@ -237,12 +261,16 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 	// 尝试端点直到成功
 	var lastErr error // 声明在外层作用域，供最终错误处理使用
 	var lastResp *http.Response // 🔧 [修复] 添加lastResp变量，用于获取真实HTTP状态码
+	saturatedEndpoints := 0     // 发出请求时容量已满而跳过的端点数
 	// 🔢 [重构] 移除currentAttemptCount变量，统一由LifecycleManager管理计数
+endpointLoop:
 	for i := 0; i < len(endpoints); i++ {
 		ep := endpoints[i]
+		previousFailedEndpoint := lastFailedEndpoint
 		lastFailedEndpoint = ep.Config.Name // 🚀 [端点自愈] 记录当前尝试的端点
 		// 更新生命周期管理器信息
 		lifecycleManager.SetEndpoint(ep.Config.Name, ep.Config.Group, ep.Config.Channel)
+		lifecycleManager.SetModelMapping(ep.Config.ModelMapping)
 		lifecycleManager.UpdateStatus("forwarding", i, 0)
 
 		// 🔧 [端点上下文修复] 立即设置端点信息到请求上下文，确保所有分支（成功/失败/取消）的日志都能正确记录端点
@@ -252,8 +280,10 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 		endpointSuccess := false
 		var attempt int // 声明在外部，循环结束后仍可访问
 		var lastDecision *RetryDecision // 保存最后的重试决策，用于外层逻辑
+		// 重试策略规则可单独设置 max_attempts，由重试管理器给出同端点的尝试上限
+		maxAttempts := sh.retryManagerFactory.NewRetryManager().GetMaxAttempts()
 
-		for attempt = 1; attempt <= sh.config.Retry.MaxAttempts; attempt++ {
+		for attempt = 1; attempt <= maxAttempts; attempt++ {
 			// 检查是否被取消
 			select {
 			case <-ctx.Done():
@@ -269,7 +299,17 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 			}
 
 			// 尝试连接端点
-			resp, err := sh.forwarder.ForwardRequestToEndpoint(ctx, r, bodyBytes, ep)
+			attemptCtx := lifecycleManager.StartAttempt(ctx, ep.Config.Name, attempt)
+			resp, err := sh.forwarder.ForwardRequestToEndpoint(attemptCtx, r, bodyBytes, ep)
+			lifecycleManager.EndAttempt(resp, err)
+
+			// 🚦 [容量限制] 端点名额已被其他请求占满，请求未发出：跳过该端点，不计为端点失败
+			if isEndpointSaturated(err) {
+				slog.Info(fmt.Sprintf("🚦 [容量已满] [%s] 端点 %s 已达到并发或 RPM/TPM 上限，跳过", connID, ep.Config.Name))
+				saturatedEndpoints++
+				lastFailedEndpoint = previousFailedEndpoint
+				continue endpointLoop
+			}
 			// 🔧 [修复] 保存最后的响应，用于获取真实HTTP状态码
 			lastResp = resp
 			if err == nil && IsSuccessStatus(resp.StatusCode) {
@@ -298,7 +338,9 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 				slog.Info(fmt.Sprintf("🚀 [开始流式处理] [%s] 端点: %s", connID, ep.Config.Name))
 
 				// 执行流式处理并获取Token信息和模型名称
-				finalTokenUsage, modelName, err := processor.ProcessStreamWithRetry(ctx, resp)
+				finalTokenUsage, modelName, err := processor.ProcessStreamWithRetry(lifecycleManager.StartStream(ctx), resp)
+				lifecycleManager.EndStream(finalTokenUsage, err)
+				lifecycleManager.SetStreamTimings(processor.GetTimings())
 				if err != nil {
 					// 🔧 [结构化错误处理] 2025-12-11: 优先使用接口断言处理流不完整错误
 					if streamErr, ok := err.(StreamIncompleteErrorInterface); ok {
@@ -442,6 +484,9 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 			// 🔧 使用增强的RetryManager进行统一决策
 			errorRecovery := sh.errorRecoveryFactory.NewErrorRecoveryManager(sh.usageTracker)
 			errorCtx := errorRecovery.ClassifyError(lastErr, connID, ep.Config.Name, ep.Config.Group, attempt-1)
+			if errorCtx.StatusCode > 0 {
+				errorCtx.UpstreamRetryAfter = upstreamRetryAfter(resp)
+			}
 
 			// 🚀 [状态机重构] Phase 4: 分离状态转换与失败原因记录
 			// 预设错误上下文（避免重复分类），由HandleError统一记录失败原因
@@ -549,7 +594,7 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 			}
 
 			// 🚀 [状态机重构] Phase 4: 重试状态管理
-			if decision.RetrySameEndpoint && attempt < sh.config.Retry.MaxAttempts {
+			if decision.RetrySameEndpoint && attempt < maxAttempts {
 				// 更新为重试状态
 				lifecycleManager.UpdateStatus("retry", globalAttemptCount, 0)
 
@@ -559,7 +604,7 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 
 				// 向客户端发送重试信息
 				fmt.Fprintf(w, "data: retry: 重试端点 %s (尝试 %d/%d)，等待 %v...\n\n",
-					ep.Config.Name, attempt+1, sh.config.Retry.MaxAttempts, decision.Delay)
+					ep.Config.Name, attempt+1, maxAttempts, decision.Delay)
 				flusher.Flush()
 
 				// 等待延迟，同时检查取消
@@ -583,8 +628,8 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 		if !endpointSuccess {
 			// 修复计数逻辑：处理提前break和自然跑满两种情况
 			actualAttempts := attempt
-			if actualAttempts > sh.config.Retry.MaxAttempts {
-				actualAttempts = sh.config.Retry.MaxAttempts
+			if actualAttempts > maxAttempts {
+				actualAttempts = maxAttempts
 			}
 
 			// 🚀 [改进版方案1] 使用已保存的重试决策，避免重复错误分类
@@ -652,6 +697,14 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 		}
 	}
 
+	// 🚦 [容量排队] 所有端点都因容量已满被跳过，排队等待容量释放后重新开始
+	if saturatedEndpoints == len(endpoints) {
+		if sh.waitForEndpointCapacity(ctx, w, r, lifecycleManager, flusher) {
+			sh.executeStreamingWithRetry(ctx, w, r, bodyBytes, lifecycleManager, flusher)
+		}
+		return
+	}
+
 	// 🔄 [请求级故障转移] 所有端点都失败了，尝试触发故障转移
 	if lastFailedEndpoint != "" {
 		newEndpointName, err := sh.endpointManager.TriggerRequestFailover(
@@ -681,10 +734,7 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 
 	// 检查是否应该挂起请求
 	if suspensionMgr.ShouldSuspend(ctx) {
-		currentEndpoints := sh.endpointManager.GetHealthyEndpoints()
-		if cfg := sh.endpointManager.GetConfig(); cfg != nil && cfg.Strategy.Type == "fastest" && cfg.Strategy.FastTestEnabled {
-			currentEndpoints = sh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-		}
+		currentEndpoints := sh.endpointManager.GetEndpointsForRequest(ctx)
 
 		// 🚀 [状态机重构] Phase 4: 挂起时更新状态（移除重复的失败原因记录）
 		lifecycleManager.UpdateStatus("suspended", -1, 0)
@@ -705,12 +755,7 @@ func (sh *StreamingHandler) executeStreamingWithRetry(ctx context.Context, w htt
 			flusher.Flush()
 
 			// 重新获取健康端点
-			var newEndpoints []*endpoint.Endpoint
-			if sh.endpointManager.GetConfig().Strategy.Type == "fastest" && sh.endpointManager.GetConfig().Strategy.FastTestEnabled {
-				newEndpoints = sh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-			} else {
-				newEndpoints = sh.endpointManager.GetHealthyEndpoints()
-			}
+			newEndpoints := sh.endpointManager.GetEndpointsForRequest(ctx)
 
 			if len(newEndpoints) > 0 {
 				// 更新端点列表，重新开始处理
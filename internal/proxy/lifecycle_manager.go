@@ -4,10 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"cc-forwarder/internal/endpoint"
 	"cc-forwarder/internal/events"
 	"cc-forwarder/internal/monitor"
@@ -19,6 +22,7 @@ import (
 type MonitoringMiddlewareInterface interface {
 	RecordTokenUsage(connID string, endpoint string, tokens *monitor.TokenUsage)
 	RecordFailedRequestTokens(connID, endpoint string, tokens *monitor.TokenUsage, failureReason string) // 新增方法
+	RecordRequestOutcome(outcome monitor.RequestOutcome)                                                 // 记录请求最终结果
 }
 
 // RetryDecision 重试决策结果
@@ -46,13 +50,24 @@ type RequestLifecycleManager struct {
 	errorRecovery         *ErrorRecoveryManager          // 错误恢复管理器
 	eventBus              events.EventBus                // EventBus事件总线
 	recoverySignalManager *EndpointRecoverySignalManager // 端点恢复信号管理器
+	tokenRecorder         EndpointTokenRecorder          // 端点 Token 用量统计（TPM 上限）
 	requestID             string                         // 请求唯一标识符
 	startTime             time.Time                      // 请求开始时间
+	firstByteAt           time.Time                      // 首个响应字节写给客户端的时间
+	firstContentAt        time.Time                      // 首个内容增量到达的时间（仅流式请求）
+	firstByteMu           sync.Mutex                     // 保护首字节和首个内容增量时间
+	outcomeRecorded       bool                           // 是否已向监控记录最终结果
+	outcomeMu             sync.Mutex                     // 保护最终结果记录标记
+	trace                 requestTrace                   // 链路追踪 span
+	traceMu               sync.Mutex                     // 保护链路追踪 span
 	modelMu               sync.RWMutex                   // 保护模型字段的读写锁
-	modelName             string                         // 模型名称
+	modelName             string                         // 模型名称（客户端请求的规范名称，用于计费）
+	upstreamModel         string                         // 端点模型映射后的上游模型名称
+	modelMapping          map[string]string              // 当前端点的模型映射（客户端模型 -> 上游模型）
 	channel               string                         // 渠道标签
 	endpointName          string                         // 端点名称
 	groupName             string                         // 组名称
+	clientKey             string                         // 鉴权使用的客户端 Key 名称
 	retryCount            int                            // 重试计数
 	lastStatus            string                         // 最后状态
 	lastError             error                          // 最后一次错误
@@ -66,6 +81,11 @@ type RequestLifecycleManager struct {
 	pendingErrorMu        sync.Mutex                     // 保护预先计算错误上下文的互斥锁
 }
 
+// EndpointTokenRecorder 统计端点消耗的 Token 数（用于端点 TPM 上限）
+type EndpointTokenRecorder interface {
+	RecordEndpointTokens(name string, tokens int64)
+}
+
 // NewRequestLifecycleManager 创建新的请求生命周期管理器
 func NewRequestLifecycleManager(usageTracker *tracking.UsageTracker, monitoringMiddleware MonitoringMiddlewareInterface, requestID string, eventBus events.EventBus) *RequestLifecycleManager {
 	return &RequestLifecycleManager{
@@ -96,9 +116,20 @@ func NewRequestLifecycleManagerWithRecoverySignal(usageTracker *tracking.UsageTr
 // StartRequest 开始请求跟踪
 // 调用 RecordRequestStart 记录请求开始，并发布请求开始事件
 func (rlm *RequestLifecycleManager) StartRequest(clientIP, userAgent, method, path string, isStreaming bool) {
+	span := rlm.rootSpan()
+	span.SetName(method + " " + path)
+	span.SetAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+		attribute.String("client.address", clientIP),
+		attribute.String("user_agent.original", userAgent),
+		attribute.Bool("request.streaming", isStreaming),
+		attribute.String("client_key", rlm.clientKey),
+	)
+
 	// 原有的数据记录逻辑
 	if rlm.usageTracker != nil && rlm.requestID != "" {
-		rlm.usageTracker.RecordRequestStart(rlm.requestID, clientIP, userAgent, method, path, isStreaming)
+		rlm.usageTracker.RecordRequestStartWithClientKey(rlm.requestID, clientIP, userAgent, method, path, rlm.clientKey, isStreaming)
 		slog.Info(fmt.Sprintf("🚀 Request started [%s]", rlm.requestID))
 	}
 
@@ -115,6 +146,7 @@ func (rlm *RequestLifecycleManager) StartRequest(clientIP, userAgent, method, pa
 				"method":       method,
 				"path":         path,
 				"is_streaming": isStreaming,
+				"client_key":   rlm.clientKey,
 				"change_type":  "request_started",
 			},
 		})
@@ -171,6 +203,8 @@ func (rlm *RequestLifecycleManager) UpdateStatus(status string, retryCount, http
 		}
 	}
 
+	rlm.traceStatus(status, actualRetryCount, httpStatus)
+
 	// 调用统一的状态通知方法
 	rlm.notifyStatusChange(status, actualRetryCount, httpStatus)
 }
@@ -197,7 +231,7 @@ func (rlm *RequestLifecycleManager) notifyStatusChange(status string, retryCount
 			changeType = "suspended_change"
 		case "retry":
 			changeType = "retry_attempt"
-		case "completed":
+		case "completed", "cache_hit":
 			changeType = "request_completed"
 		case "failed":
 			priority = events.PriorityHigh
@@ -270,6 +304,10 @@ func (rlm *RequestLifecycleManager) CompleteRequest(tokens *tracking.TokenUsage)
 		if modelName == "" {
 			modelName = "unknown"
 		}
+		modelLabel := modelName
+		if upstream := rlm.GetUpstreamModel(); upstream != "" {
+			modelLabel = fmt.Sprintf("%s (上游: %s)", modelName, upstream)
+		}
 		// 同时记录到监控中间件（用于Web图表显示）
 		if rlm.monitoringMiddleware != nil && tokens != nil {
 			monitorTokens := &monitor.TokenUsage{
@@ -289,16 +327,19 @@ func (rlm *RequestLifecycleManager) CompleteRequest(tokens *tracking.TokenUsage)
 			slog.Info(fmt.Sprintf("✅ [请求完成] [%s] 端点: %s (总尝试 %d 个端点)",
 				rlm.requestID, rlm.endpointName, rlm.retryCount+1))
 			slog.Info(fmt.Sprintf("📊 [Token统计] [%s] 模型: %s, 输入[%d] 输出[%d] 总计[%d] 缓存[%d], 耗时: %dms",
-				rlm.requestID, modelName, tokens.InputTokens, tokens.OutputTokens,
+				rlm.requestID, modelLabel, tokens.InputTokens, tokens.OutputTokens,
 				totalTokens, cacheTokens, duration.Milliseconds()))
 		} else {
 			slog.Info(fmt.Sprintf("✅ [请求完成] [%s] 端点: %s, 组: %s, 模型: %s, 耗时: %dms (无Token统计)",
-				rlm.requestID, rlm.endpointName, rlm.groupName, modelName, duration.Milliseconds()))
+				rlm.requestID, rlm.endpointName, rlm.groupName, modelLabel, duration.Milliseconds()))
 		}
 		// 记录请求成功完成到使用跟踪器（包括状态、耗时、Token、成本）
+		rlm.recordResponseSpeed(tokens)
 		rlm.usageTracker.RecordRequestSuccess(rlm.requestID, modelName, tokens, duration)
 		slog.Info(fmt.Sprintf("✅ Request completed [%s]", rlm.requestID))
 	}
+	rlm.recordOutcome("completed", "", tokens, duration)
+	rlm.finishTrace("completed", "", tokens, 200)
 
 	// 调用统一的状态通知方法
 	rlm.notifyStatusChange("completed", rlm.retryCount, 200)
@@ -349,6 +390,7 @@ func (rlm *RequestLifecycleManager) CompleteRequestWithQuality(tokens *tracking.
 
 		// 🔧 [方案A核心] 使用 RecordRequestSuccessWithQuality 一次性完成所有字段设置
 		// 包括 status、tokens、duration 和 failureReason，避免两次独立操作的时序问题
+		rlm.recordResponseSpeed(tokens)
 		rlm.usageTracker.RecordRequestSuccessWithQuality(rlm.requestID, modelName, tokens, duration, failureReason)
 
 		if failureReason != "" {
@@ -356,11 +398,31 @@ func (rlm *RequestLifecycleManager) CompleteRequestWithQuality(tokens *tracking.
 		}
 		slog.Info(fmt.Sprintf("✅ Request completed [%s]", rlm.requestID))
 	}
+	rlm.recordOutcome("completed", failureReason, tokens, duration)
+	rlm.finishTrace("completed", failureReason, tokens, 200)
 
 	// 调用统一的状态通知方法
 	rlm.notifyStatusChange("completed", rlm.retryCount, 200)
 }
 
+// CompleteFromCache 以响应缓存命中完成请求
+// 请求未转发到任何端点：不广播端点成功信号，不计成本，Token 仅作展示
+func (rlm *RequestLifecycleManager) CompleteFromCache(tokens *tracking.TokenUsage) {
+	duration := time.Since(rlm.startTime)
+	if rlm.usageTracker != nil && rlm.requestID != "" {
+		rlm.usageTracker.RecordRequestCacheHit(rlm.requestID, rlm.getModelNameForCost(), tokens, duration)
+	}
+
+	slog.Info(fmt.Sprintf("💾 [请求完成] [%s] 响应缓存命中, 模型: %s, 耗时: %dms",
+		rlm.requestID, rlm.getModelNameForCost(), duration.Milliseconds()))
+	// 缓存命中没有消耗上游 Token，监控指标只计请求数和耗时
+	rlm.recordOutcome("cache_hit", "", nil, duration)
+	rlm.finishTrace("cache_hit", "", tokens, 200)
+
+	// 调用统一的状态通知方法
+	rlm.notifyStatusChange("cache_hit", rlm.retryCount, 200)
+}
+
 // HandleNonTokenResponse 处理非Token响应的Fallback机制
 // 用于处理不包含Token信息的响应（如健康检查、配置查询等）
 func (rlm *RequestLifecycleManager) HandleNonTokenResponse(responseContent string) {
@@ -426,6 +488,53 @@ func (rlm *RequestLifecycleManager) SetEndpoint(endpointName, groupName, channel
 	}
 }
 
+// SetClientKey 设置鉴权使用的客户端 Key 名称（需在 StartRequest 之前调用）
+func (rlm *RequestLifecycleManager) SetClientKey(name string) {
+	rlm.clientKey = name
+}
+
+// SetTokenRecorder 设置端点 Token 用量统计（请求结束时上报最终端点消耗的 Token 数）
+func (rlm *RequestLifecycleManager) SetTokenRecorder(recorder EndpointTokenRecorder) {
+	rlm.tokenRecorder = recorder
+}
+
+// GetClientKey 获取客户端 Key 名称
+func (rlm *RequestLifecycleManager) GetClientKey() string {
+	return rlm.clientKey
+}
+
+// SetModelMapping 设置当前端点的模型映射（切换端点时调用，未配置映射时传 nil）
+func (rlm *RequestLifecycleManager) SetModelMapping(mapping map[string]string) {
+	rlm.modelMu.Lock()
+	defer rlm.modelMu.Unlock()
+
+	rlm.modelMapping = mapping
+	rlm.upstreamModel = ""
+	if rlm.modelName != "" {
+		rlm.upstreamModel = mapping[rlm.modelName]
+	}
+}
+
+// canonicalModelLocked 将上游模型名称还原为客户端模型（调用方需持有 modelMu）
+func (rlm *RequestLifecycleManager) canonicalModelLocked(model string) (string, bool) {
+	if len(rlm.modelMapping) == 0 {
+		return model, false
+	}
+	if upstream, ok := rlm.modelMapping[rlm.modelName]; ok && upstream == model {
+		return rlm.modelName, true
+	}
+	canonical := ""
+	for from, to := range rlm.modelMapping {
+		if to == model && (canonical == "" || from < canonical) {
+			canonical = from
+		}
+	}
+	if canonical == "" {
+		return model, false
+	}
+	return canonical, true
+}
+
 // SetModel 设置模型名称（线程安全）
 // 简单版本，只在模型为空或unknown时设置
 func (rlm *RequestLifecycleManager) SetModel(modelName string) {
@@ -435,12 +544,16 @@ func (rlm *RequestLifecycleManager) SetModel(modelName string) {
 	// 只在当前模型为空或unknown时设置，避免覆盖更准确的模型信息
 	if rlm.modelName == "" || rlm.modelName == "unknown" {
 		rlm.modelName = modelName
+		if upstream, ok := rlm.modelMapping[modelName]; ok {
+			rlm.upstreamModel = upstream
+		}
 		slog.Debug(fmt.Sprintf("🏷️ [模型提取] [%s] 从请求中获取模型名称: %s", rlm.requestID, modelName))
 	}
 }
 
 // SetModelWithComparison 设置模型名称并进行对比检查（线程安全）
 // 如果已有模型，会进行对比并在不一致时输出警告，最终以新模型为准
+// 端点配置了模型映射时，上游模型名称会还原为客户端模型，两个名称都会记录
 func (rlm *RequestLifecycleManager) SetModelWithComparison(newModelName, source string) {
 	rlm.modelMu.Lock()
 	defer rlm.modelMu.Unlock()
@@ -450,6 +563,13 @@ func (rlm *RequestLifecycleManager) SetModelWithComparison(newModelName, source
 		return
 	}
 
+	// 响应中的模型为上游名称（响应未改写时）：还原为客户端模型，计费使用规范名称
+	if canonical, ok := rlm.canonicalModelLocked(newModelName); ok {
+		rlm.upstreamModel = newModelName
+		slog.Debug(fmt.Sprintf("🔁 [模型映射] [%s] %s模型: %s -> 客户端模型: %s", rlm.requestID, source, newModelName, canonical))
+		newModelName = canonical
+	}
+
 	// 如果当前没有模型或为unknown，直接设置
 	if rlm.modelName == "" || rlm.modelName == "unknown" {
 		rlm.modelName = newModelName
@@ -482,6 +602,13 @@ func (rlm *RequestLifecycleManager) GetModelName() string {
 	return rlm.modelName
 }
 
+// GetUpstreamModel 获取端点模型映射后的上游模型名称（未映射时为空）
+func (rlm *RequestLifecycleManager) GetUpstreamModel() string {
+	rlm.modelMu.RLock()
+	defer rlm.modelMu.RUnlock()
+	return rlm.upstreamModel
+}
+
 // HasModel 检查是否已有有效的模型名称（线程安全）
 func (rlm *RequestLifecycleManager) HasModel() bool {
 	rlm.modelMu.RLock()
@@ -542,6 +669,10 @@ func (rlm *RequestLifecycleManager) GetStats() map[string]any {
 		"start_time":  rlm.startTime.Format(time.RFC3339),
 	}
 
+	if upstream := rlm.GetUpstreamModel(); upstream != "" {
+		stats["upstream_model"] = upstream
+	}
+
 	// 如果有错误信息，包含在统计中
 	if rlm.lastError != nil {
 		stats["last_error"] = rlm.lastError.Error()
@@ -577,6 +708,7 @@ func (rlm *RequestLifecycleManager) PrepareErrorContext(errorCtx *handlers.Error
 		OriginalError:  errorCtx.OriginalError,
 		RetryableAfter: errorCtx.RetryableAfter,
 		MaxRetries:     errorCtx.MaxRetries,
+		StatusCode:     errorCtx.StatusCode,
 	}
 
 	rlm.pendingErrorContext = converted
@@ -671,11 +803,14 @@ func (rlm *RequestLifecycleManager) FailRequest(failureReason, errorDetail strin
 
 	// 🚀 [架构重构] 使用统一的最终失败记录方法，一次性更新所有相关字段
 	if rlm.usageTracker != nil {
+		rlm.recordResponseSpeed(nil)
 		rlm.usageTracker.RecordRequestFinalFailure(rlm.requestID, modelName, "failed", failureReason, errorDetail, duration, httpStatus, nil)
 	}
 
 	slog.Error(fmt.Sprintf("❌ [请求最终失败] [%s] 端点: %s, 原因: %s, 状态码: %d, 耗时: %dms",
 		rlm.requestID, rlm.endpointName, failureReason, httpStatus, duration.Milliseconds()))
+	rlm.recordOutcome("failed", failureReason, nil, duration)
+	rlm.finishTrace("failed", failureReason, nil, httpStatus)
 
 	// 调用统一的状态通知方法
 	rlm.notifyStatusChange("failed", rlm.retryCount, httpStatus)
@@ -690,6 +825,7 @@ func (rlm *RequestLifecycleManager) CancelRequest(cancelReason string, tokens *t
 
 	// 🚀 [架构重构] 使用统一的最终失败记录方法，一次性更新所有相关字段
 	if rlm.usageTracker != nil {
+		rlm.recordResponseSpeed(tokens)
 		rlm.usageTracker.RecordRequestFinalFailure(rlm.requestID, modelName, "cancelled", cancelReason, "", duration, 499, tokens)
 	}
 
@@ -701,11 +837,174 @@ func (rlm *RequestLifecycleManager) CancelRequest(cancelReason string, tokens *t
 		slog.Info(fmt.Sprintf("🚫 [请求被取消] [%s] 端点: %s, 组: %s, 耗时: %dms, 原因: %s",
 			rlm.requestID, rlm.endpointName, rlm.groupName, duration.Milliseconds(), cancelReason))
 	}
+	rlm.recordOutcome("cancelled", "", tokens, duration)
+	rlm.finishTrace("cancelled", cancelReason, tokens, 499)
 
 	// 调用统一的状态通知方法
 	rlm.notifyStatusChange("cancelled", rlm.retryCount, 499)
 }
 
+// MarkFirstByte 记录首个响应字节写给客户端的时间（仅第一次调用生效）
+func (rlm *RequestLifecycleManager) MarkFirstByte() {
+	rlm.firstByteMu.Lock()
+	defer rlm.firstByteMu.Unlock()
+	if rlm.firstByteAt.IsZero() {
+		rlm.firstByteAt = time.Now()
+	}
+}
+
+// SetStreamTimings 记录流式处理器观测到的首字节和首个内容增量时间（零值表示未出现）
+func (rlm *RequestLifecycleManager) SetStreamTimings(firstByte, firstContent time.Time) {
+	rlm.firstByteMu.Lock()
+	defer rlm.firstByteMu.Unlock()
+	if !firstByte.IsZero() && (rlm.firstByteAt.IsZero() || firstByte.Before(rlm.firstByteAt)) {
+		rlm.firstByteAt = firstByte
+	}
+	if !firstContent.IsZero() {
+		rlm.firstContentAt = firstContent
+	}
+}
+
+// GetTTFT 获取首 Token 耗时：流式请求取首个内容增量到达的时间，否则取首字节时间，未写出数据时返回 0
+func (rlm *RequestLifecycleManager) GetTTFT() time.Duration {
+	rlm.firstByteMu.Lock()
+	defer rlm.firstByteMu.Unlock()
+	switch {
+	case !rlm.firstContentAt.IsZero():
+		return rlm.firstContentAt.Sub(rlm.startTime)
+	case !rlm.firstByteAt.IsZero():
+		return rlm.firstByteAt.Sub(rlm.startTime)
+	}
+	return 0
+}
+
+// GetOutputTokensPerSec 计算输出 Token 速率（tok/s）
+// 流式请求按首个内容增量到结束的生成时长计算，非流式请求按总耗时计算
+func (rlm *RequestLifecycleManager) GetOutputTokensPerSec(tokens *tracking.TokenUsage, end time.Time) float64 {
+	if tokens == nil || tokens.OutputTokens <= 0 {
+		return 0
+	}
+
+	rlm.firstByteMu.Lock()
+	generationStart := rlm.firstContentAt
+	rlm.firstByteMu.Unlock()
+
+	window := end.Sub(generationStart)
+	if generationStart.IsZero() || window <= 0 {
+		window = end.Sub(rlm.startTime)
+	}
+	if window <= 0 {
+		return 0
+	}
+	return math.Round(float64(tokens.OutputTokens)/window.Seconds()*100) / 100
+}
+
+// recordResponseSpeed 在请求结束前将首 Token 耗时和输出速率写入使用跟踪器
+func (rlm *RequestLifecycleManager) recordResponseSpeed(tokens *tracking.TokenUsage) {
+	if rlm.usageTracker == nil {
+		return
+	}
+
+	var opts tracking.UpdateOptions
+	if ttft := rlm.GetTTFT(); ttft > 0 {
+		ttftMs := ttft.Milliseconds()
+		opts.TTFTMs = &ttftMs
+	}
+	if rate := rlm.GetOutputTokensPerSec(tokens, time.Now()); rate > 0 {
+		opts.OutputTokensPerSec = &rate
+	}
+	if opts.TTFTMs == nil && opts.OutputTokensPerSec == nil {
+		return
+	}
+	rlm.usageTracker.RecordRequestUpdate(rlm.requestID, opts)
+}
+
+// recordOutcome 向监控中间件记录请求最终结果（每个请求只记录一次）
+func (rlm *RequestLifecycleManager) recordOutcome(status, failureReason string, tokens *tracking.TokenUsage, duration time.Duration) {
+	rlm.outcomeMu.Lock()
+	if rlm.outcomeRecorded {
+		rlm.outcomeMu.Unlock()
+		return
+	}
+	rlm.outcomeRecorded = true
+	rlm.outcomeMu.Unlock()
+
+	// 端点 TPM 统计：输入、缓存创建和输出 Token（缓存读取不计入）
+	if rlm.tokenRecorder != nil && tokens != nil && rlm.endpointName != "" {
+		rlm.tokenRecorder.RecordEndpointTokens(rlm.endpointName,
+			tokens.InputTokens+tokens.CacheCreationTokens+tokens.OutputTokens)
+	}
+
+	outcome := monitor.RequestOutcome{
+		Endpoint:      rlm.endpointName,
+		Model:         rlm.getModelNameForCost(),
+		Status:        status,
+		FailureReason: failureReason,
+		Duration:      duration,
+		TTFT:          rlm.GetTTFT(),
+	}
+	if attempts := rlm.GetAttemptCount(); attempts > 1 {
+		outcome.Retries = attempts - 1
+	}
+	if tokens != nil && rlm.usageTracker != nil {
+		outcome.CostUSD = rlm.usageTracker.EstimateCost(outcome.Model, rlm.endpointName, tokens)
+	}
+
+	rlm.publishCompleted(outcome, tokens)
+
+	if rlm.monitoringMiddleware == nil {
+		return
+	}
+
+	if tokens != nil {
+		outcome.Tokens = &monitor.TokenUsage{
+			InputTokens:         tokens.InputTokens,
+			OutputTokens:        tokens.OutputTokens,
+			CacheCreationTokens: tokens.CacheCreationTokens,
+			CacheReadTokens:     tokens.CacheReadTokens,
+		}
+		outcome.CacheCreation5mTokens = tokens.CacheCreation5mTokens
+		outcome.CacheCreation1hTokens = tokens.CacheCreation1hTokens
+	}
+
+	rlm.monitoringMiddleware.RecordRequestOutcome(outcome)
+}
+
+// publishCompleted 发布请求完成事件（每个请求一次，包含最终状态、耗时、Token 和成本）
+func (rlm *RequestLifecycleManager) publishCompleted(outcome monitor.RequestOutcome, tokens *tracking.TokenUsage) {
+	if rlm.eventBus == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"request_id":     rlm.requestID,
+		"endpoint_name":  rlm.endpointName,
+		"channel":        rlm.channel,
+		"group_name":     rlm.groupName,
+		"model_name":     outcome.Model,
+		"status":         outcome.Status,
+		"failure_reason": outcome.FailureReason,
+		"duration_ms":    outcome.Duration.Milliseconds(),
+		"ttft_ms":        outcome.TTFT.Milliseconds(),
+		"retry_count":    outcome.Retries,
+		"cost_usd":       outcome.CostUSD,
+		"change_type":    "request_completed",
+	}
+	if tokens != nil {
+		data["input_tokens"] = tokens.InputTokens
+		data["output_tokens"] = tokens.OutputTokens
+		data["cache_creation_tokens"] = tokens.CacheCreationTokens
+		data["cache_read_tokens"] = tokens.CacheReadTokens
+	}
+
+	rlm.eventBus.Publish(events.Event{
+		Type:     events.EventRequestCompleted,
+		Source:   "lifecycle_manager",
+		Priority: events.PriorityNormal,
+		Data:     data,
+	})
+}
+
 // GetLastError 获取最后一次错误
 func (rlm *RequestLifecycleManager) GetLastError() error {
 	return rlm.lastError
@@ -1,10 +1,20 @@
 package proxy
 
 import (
+	"context"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"cc-forwarder/internal/monitor"
 	"cc-forwarder/internal/tracking"
 )
 
@@ -425,4 +435,197 @@ func TestRequestLifecycleManager_CompleteRequestWithQuality_WithTokens(t *testin
 	}
 
 	t.Log("✅ 带 Token 的质量标记测试通过")
-}
\ No newline at end of file
+}
+func TestRequestLifecycleManager_SetModelWithComparison_ModelMapping(t *testing.T) {
+	rlm := NewRequestLifecycleManager(nil, nil, "test-model-mapping", nil)
+	rlm.SetModel("claude-sonnet-4-5")
+	rlm.SetModelMapping(map[string]string{"claude-sonnet-4-5": "vendor/claude-sonnet"})
+
+	if got := rlm.GetUpstreamModel(); got != "vendor/claude-sonnet" {
+		t.Errorf("上游模型应为 vendor/claude-sonnet，实际为 %q", got)
+	}
+
+	// 响应未改写时返回上游名称：还原为客户端模型，计费使用规范名称
+	rlm.SetModelWithComparison("vendor/claude-sonnet", "message_start")
+	if got := rlm.GetModelName(); got != "claude-sonnet-4-5" {
+		t.Errorf("模型应保持为 claude-sonnet-4-5，实际为 %q", got)
+	}
+	if got := rlm.GetStats()["upstream_model"]; got != "vendor/claude-sonnet" {
+		t.Errorf("统计信息中的上游模型不正确: %v", got)
+	}
+
+	// 切换到未配置映射的端点后不再记录上游模型
+	rlm.SetModelMapping(nil)
+	if got := rlm.GetUpstreamModel(); got != "" {
+		t.Errorf("未配置映射时上游模型应为空，实际为 %q", got)
+	}
+	rlm.SetModelWithComparison("claude-sonnet-4-5-20250929", "message_start")
+	if got := rlm.GetModelName(); got != "claude-sonnet-4-5-20250929" {
+		t.Errorf("未配置映射时应以响应模型为准，实际为 %q", got)
+	}
+}
+
+func TestRequestLifecycleManager_SetModelWithComparison_MappingBeforeModel(t *testing.T) {
+	// 请求体模型尚未解析完成时，响应中的上游名称也应还原为客户端模型
+	rlm := NewRequestLifecycleManager(nil, nil, "test-model-mapping-early", nil)
+	rlm.SetModelMapping(map[string]string{"claude-opus-4-1": "vendor/opus"})
+
+	rlm.SetModelWithComparison("vendor/opus", "常规响应解析")
+	if got := rlm.GetModelName(); got != "claude-opus-4-1" {
+		t.Errorf("模型应为 claude-opus-4-1，实际为 %q", got)
+	}
+	if got := rlm.GetUpstreamModel(); got != "vendor/opus" {
+		t.Errorf("上游模型应为 vendor/opus，实际为 %q", got)
+	}
+}
+
+// recordingMonitor 测试用监控中间件：记录请求最终结果
+type recordingMonitor struct {
+	outcomes []monitor.RequestOutcome
+}
+
+func (m *recordingMonitor) RecordTokenUsage(connID string, endpoint string, tokens *monitor.TokenUsage) {
+}
+
+func (m *recordingMonitor) RecordFailedRequestTokens(connID, endpoint string, tokens *monitor.TokenUsage, failureReason string) {
+}
+
+func (m *recordingMonitor) RecordRequestOutcome(outcome monitor.RequestOutcome) {
+	m.outcomes = append(m.outcomes, outcome)
+}
+
+func TestRequestLifecycleManager_RecordOutcome(t *testing.T) {
+	mon := &recordingMonitor{}
+	rlm := NewRequestLifecycleManager(nil, mon, "test-outcome", nil)
+	rlm.SetEndpoint("relay", "relay", "")
+	rlm.SetModel("claude-sonnet-4-5")
+	rlm.IncrementAttempt()
+	rlm.IncrementAttempt()
+
+	time.Sleep(5 * time.Millisecond)
+	rlm.MarkFirstByte()
+	ttft := rlm.GetTTFT()
+	rlm.MarkFirstByte() // 只记录第一次
+	if ttft <= 0 || rlm.GetTTFT() != ttft {
+		t.Fatalf("首字节耗时不正确: %v / %v", ttft, rlm.GetTTFT())
+	}
+
+	tokens := &tracking.TokenUsage{InputTokens: 10, OutputTokens: 5, CacheCreation1hTokens: 3, CacheCreationTokens: 3}
+	rlm.CompleteRequestWithQuality(tokens, "incomplete_stream")
+	rlm.FailRequest("late_failure", "", 502) // 已记录最终结果，不再重复记录
+
+	if len(mon.outcomes) != 1 {
+		t.Fatalf("应只记录一次最终结果，实际 %d 次", len(mon.outcomes))
+	}
+	got := mon.outcomes[0]
+	if got.Endpoint != "relay" || got.Model != "claude-sonnet-4-5" || got.Status != "completed" || got.FailureReason != "incomplete_stream" {
+		t.Errorf("最终结果标签不正确: %+v", got)
+	}
+	if got.Retries != 1 || got.TTFT != ttft || got.Duration < ttft {
+		t.Errorf("重试次数或耗时不正确: %+v", got)
+	}
+	if got.Tokens == nil || got.Tokens.InputTokens != 10 || got.CacheCreation1hTokens != 3 {
+		t.Errorf("Token 信息不正确: %+v", got.Tokens)
+	}
+}
+
+func TestRequestLifecycleManager_StreamTimings(t *testing.T) {
+	rlm := NewRequestLifecycleManager(nil, nil, "test-stream-timings", nil)
+	start := rlm.startTime
+	tokens := &tracking.TokenUsage{OutputTokens: 100}
+
+	rlm.SetStreamTimings(start.Add(100*time.Millisecond), start.Add(300*time.Millisecond))
+	if got := rlm.GetTTFT(); got != 300*time.Millisecond {
+		t.Errorf("首 Token 耗时应取首个内容增量时间 300ms，实际 %v", got)
+	}
+	// 生成时长 = 结束 - 首个内容增量 = 2s
+	if got := rlm.GetOutputTokensPerSec(tokens, start.Add(2300*time.Millisecond)); got != 50 {
+		t.Errorf("输出速率应为 50 tok/s，实际 %v", got)
+	}
+	if got := rlm.GetOutputTokensPerSec(nil, start.Add(time.Second)); got != 0 {
+		t.Errorf("无 Token 时输出速率应为 0，实际 %v", got)
+	}
+
+	// 非流式请求：TTFT 取首字节，速率按总耗时计算
+	regular := NewRequestLifecycleManager(nil, nil, "test-regular-timings", nil)
+	regular.SetStreamTimings(regular.startTime.Add(800*time.Millisecond), time.Time{})
+	if got := regular.GetTTFT(); got != 800*time.Millisecond {
+		t.Errorf("无内容增量时首 Token 耗时应取首字节时间，实际 %v", got)
+	}
+	if got := regular.GetOutputTokensPerSec(tokens, regular.startTime.Add(4*time.Second)); got != 25 {
+		t.Errorf("输出速率应为 25 tok/s，实际 %v", got)
+	}
+}
+
+func TestRequestLifecycleManager_TraceSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(propagator)
+	}()
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	rlm := NewRequestLifecycleManager(nil, nil, "test-trace", nil)
+	ctx := rlm.StartTrace(context.Background(), header)
+	rlm.StartRequest("127.0.0.1", "test", "POST", "/v1/messages", true)
+	rlm.SetEndpoint("relay", "relay", "")
+	rlm.SetModel("claude-sonnet-4-5")
+
+	rlm.StartAttempt(ctx, "relay", 1)
+	rlm.EndAttempt(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	rlm.UpdateStatus("retry", 1, 0)
+
+	rlm.StartAttempt(ctx, "relay", 2)
+	rlm.EndAttempt(&http.Response{StatusCode: http.StatusOK}, nil)
+	tokens := &tracking.TokenUsage{InputTokens: 10, OutputTokens: 5}
+	rlm.StartStream(ctx)
+	rlm.EndStream(tokens, nil)
+
+	rlm.CompleteRequest(tokens)
+	rlm.EndTrace() // 已结束，不应重复结束
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("应结束 4 个 span，实际 %d 个", len(spans))
+	}
+
+	root := spans[3]
+	if root.Name() != "POST /v1/messages" {
+		t.Errorf("根 span 名称 = %s", root.Name())
+	}
+	if root.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("根 span 应沿用客户端 traceparent 作为父 span")
+	}
+	if root.Status().Code != codes.Ok {
+		t.Errorf("完成的请求状态应为 Ok，实际 %v", root.Status())
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range root.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["model"].AsString() != "claude-sonnet-4-5" || attrs["request.status"].AsString() != "completed" ||
+		attrs["gen_ai.usage.output_tokens"].AsInt64() != 5 {
+		t.Errorf("根 span 属性不正确: %v", root.Attributes())
+	}
+	if len(root.Events()) != 1 || root.Events()[0].Name != "status.retry" {
+		t.Errorf("根 span 应记录重试事件: %v", root.Events())
+	}
+
+	for i, span := range spans[:3] {
+		if span.SpanContext().TraceID() != root.SpanContext().TraceID() || span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("span %d (%s) 不是根 span 的子 span", i, span.Name())
+		}
+	}
+	if spans[0].Name() != "proxy.attempt" || spans[0].Status().Code != codes.Error {
+		t.Errorf("503 的尝试应标记为错误: %s %v", spans[0].Name(), spans[0].Status())
+	}
+	if spans[1].Status().Code == codes.Error || spans[2].Name() != "proxy.stream" {
+		t.Errorf("成功尝试或流式 span 不正确: %v / %s", spans[1].Status(), spans[2].Name())
+	}
+}
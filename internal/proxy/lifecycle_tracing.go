@@ -0,0 +1,194 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"cc-forwarder/internal/tracing"
+	"cc-forwarder/internal/tracking"
+)
+
+// requestTrace 请求生命周期的 span 集合
+// 根 span 覆盖整个请求，每次端点尝试和流式传输各自对应一个子 span
+type requestTrace struct {
+	root    trace.Span
+	attempt trace.Span // 当前端点尝试（收到响应头或出错时结束）
+	stream  trace.Span // 流式响应传输
+	ended   bool
+}
+
+// StartTrace 开始请求根 span，沿用客户端请求头中的 traceparent
+// 返回携带根 span 的上下文，后续转发、挂起等待都应使用该上下文
+func (rlm *RequestLifecycleManager) StartTrace(ctx context.Context, header http.Header) context.Context {
+	ctx, span := tracing.Tracer().Start(tracing.Extract(ctx, header), "proxy.request",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("request.id", rlm.requestID)))
+
+	rlm.traceMu.Lock()
+	rlm.trace.root = span
+	rlm.traceMu.Unlock()
+	return ctx
+}
+
+// rootSpan 返回根 span，未开始追踪时返回 no-op span
+func (rlm *RequestLifecycleManager) rootSpan() trace.Span {
+	rlm.traceMu.Lock()
+	defer rlm.traceMu.Unlock()
+	if rlm.trace.root == nil || rlm.trace.ended {
+		return trace.SpanFromContext(context.Background())
+	}
+	return rlm.trace.root
+}
+
+// traceStatus 在根 span 上记录状态变更事件
+func (rlm *RequestLifecycleManager) traceStatus(status string, retryCount, httpStatus int) {
+	rlm.rootSpan().AddEvent("status."+status, trace.WithAttributes(
+		attribute.String("endpoint", rlm.endpointName),
+		attribute.Int("attempt", retryCount),
+		attribute.Int("http.response.status_code", httpStatus),
+	))
+}
+
+// StartAttempt 开始一次端点尝试的 span（attempt 为该端点内的尝试序号），返回用于转发请求的上下文
+func (rlm *RequestLifecycleManager) StartAttempt(ctx context.Context, endpointName string, attempt int) context.Context {
+	ctx, span := tracing.Tracer().Start(ctx, "proxy.attempt",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("endpoint", endpointName),
+			attribute.Int("attempt", attempt),
+		))
+
+	rlm.traceMu.Lock()
+	previous := rlm.trace.attempt
+	rlm.trace.attempt = span
+	rlm.traceMu.Unlock()
+
+	if previous != nil {
+		previous.End()
+	}
+	return ctx
+}
+
+// EndAttempt 结束当前端点尝试的 span，记录响应状态码或错误
+func (rlm *RequestLifecycleManager) EndAttempt(resp *http.Response, err error) {
+	rlm.traceMu.Lock()
+	span := rlm.trace.attempt
+	rlm.trace.attempt = nil
+	rlm.traceMu.Unlock()
+
+	if span == nil {
+		return
+	}
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case resp != nil && resp.StatusCode >= http.StatusBadRequest:
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	span.End()
+}
+
+// StartStream 开始流式响应传输的 span
+func (rlm *RequestLifecycleManager) StartStream(ctx context.Context) context.Context {
+	ctx, span := tracing.Tracer().Start(ctx, "proxy.stream",
+		trace.WithAttributes(attribute.String("endpoint", rlm.endpointName)))
+
+	rlm.traceMu.Lock()
+	rlm.trace.stream = span
+	rlm.traceMu.Unlock()
+	return ctx
+}
+
+// EndStream 结束流式响应传输的 span，记录 Token 用量或错误
+func (rlm *RequestLifecycleManager) EndStream(tokens *tracking.TokenUsage, err error) {
+	rlm.traceMu.Lock()
+	span := rlm.trace.stream
+	rlm.trace.stream = nil
+	rlm.traceMu.Unlock()
+
+	if span == nil {
+		return
+	}
+	if tokens != nil {
+		span.SetAttributes(tokenAttributes(tokens)...)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EndTrace 兜底结束根 span：处理流程未进入最终状态就返回时，按最后状态结束
+func (rlm *RequestLifecycleManager) EndTrace() {
+	rlm.finishTrace(rlm.GetLastStatus(), "", nil, rlm.GetFinalStatusCode())
+}
+
+// finishTrace 记录请求最终结果并结束所有未结束的 span（每个请求只执行一次）
+func (rlm *RequestLifecycleManager) finishTrace(status, failureReason string, tokens *tracking.TokenUsage, httpStatus int) {
+	rlm.traceMu.Lock()
+	if rlm.trace.root == nil || rlm.trace.ended {
+		rlm.traceMu.Unlock()
+		return
+	}
+	rlm.trace.ended = true
+	root, attempt, stream := rlm.trace.root, rlm.trace.attempt, rlm.trace.stream
+	rlm.trace.attempt, rlm.trace.stream = nil, nil
+	rlm.traceMu.Unlock()
+
+	for _, span := range []trace.Span{attempt, stream} {
+		if span != nil {
+			span.End()
+		}
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("request.status", status),
+		attribute.String("endpoint", rlm.endpointName),
+		attribute.String("group", rlm.groupName),
+		attribute.String("model", rlm.getModelNameForCost()),
+		attribute.Int("attempts", rlm.GetAttemptCount()),
+	}
+	if upstream := rlm.GetUpstreamModel(); upstream != "" {
+		attrs = append(attrs, attribute.String("model.upstream", upstream))
+	}
+	if failureReason != "" {
+		attrs = append(attrs, attribute.String("failure_reason", failureReason))
+	}
+	if httpStatus > 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", httpStatus))
+	}
+	if ttft := rlm.GetTTFT(); ttft > 0 {
+		attrs = append(attrs, attribute.Int64("ttft_ms", ttft.Milliseconds()))
+	}
+	if tokens != nil {
+		attrs = append(attrs, tokenAttributes(tokens)...)
+	}
+	root.SetAttributes(attrs...)
+
+	switch status {
+	case "completed", "cache_hit":
+		root.SetStatus(codes.Ok, "")
+	case "failed", "error", "timeout":
+		root.SetStatus(codes.Error, failureReason)
+	}
+	root.End()
+}
+
+// tokenAttributes Token 用量的 span 属性
+func tokenAttributes(tokens *tracking.TokenUsage) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("gen_ai.usage.input_tokens", tokens.InputTokens),
+		attribute.Int64("gen_ai.usage.output_tokens", tokens.OutputTokens),
+		attribute.Int64("tokens.cache_creation", tokens.CacheCreationTokens),
+		attribute.Int64("tokens.cache_read", tokens.CacheReadTokens),
+	}
+}
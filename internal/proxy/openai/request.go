@@ -0,0 +1,303 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ConvertedRequest 转换后的 Anthropic 请求及流式选项
+type ConvertedRequest struct {
+	Body         []byte // Anthropic Messages 请求体
+	Model        string // 请求的模型名称
+	Stream       bool   // 是否为流式请求
+	IncludeUsage bool   // 流式结束时是否返回 usage 块（stream_options.include_usage）
+}
+
+// ConvertRequest 将 OpenAI Chat Completions 请求体转换为 Anthropic Messages 请求体
+// defaultMaxTokens <= 0 时使用 DefaultMaxTokens
+func ConvertRequest(body []byte, defaultMaxTokens int) (*ConvertedRequest, error) {
+	var req ChatCompletionRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if req.Model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages must not be empty")
+	}
+
+	out := messagesRequest{
+		Model:       req.Model,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stream:      req.Stream,
+	}
+
+	// max_tokens：优先 max_completion_tokens，其次 max_tokens，最后默认值
+	switch {
+	case req.MaxCompletionTokens != nil && *req.MaxCompletionTokens > 0:
+		out.MaxTokens = *req.MaxCompletionTokens
+	case req.MaxTokens != nil && *req.MaxTokens > 0:
+		out.MaxTokens = *req.MaxTokens
+	case defaultMaxTokens > 0:
+		out.MaxTokens = defaultMaxTokens
+	default:
+		out.MaxTokens = DefaultMaxTokens
+	}
+
+	stops, err := parseStop(req.Stop)
+	if err != nil {
+		return nil, err
+	}
+	out.StopSequences = stops
+
+	if req.User != "" {
+		out.Metadata = &anthropicMetadata{UserID: req.User}
+	}
+
+	// 工具定义
+	for _, tool := range req.Tools {
+		if tool.Type != "" && tool.Type != "function" {
+			return nil, fmt.Errorf("unsupported tool type: %s", tool.Type)
+		}
+		schema := tool.Function.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out.Tools = append(out.Tools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: schema,
+		})
+	}
+
+	toolChoice, err := convertToolChoice(req.ToolChoice)
+	if err != nil {
+		return nil, err
+	}
+	out.ToolChoice = toolChoice
+
+	// 消息转换：system/developer 合并为 system，其余按角色合并相邻消息
+	var systemParts []string
+	for i, msg := range req.Messages {
+		switch msg.Role {
+		case "system", "developer":
+			text, err := contentText(msg.Content)
+			if err != nil {
+				return nil, fmt.Errorf("messages[%d]: %w", i, err)
+			}
+			if text != "" {
+				systemParts = append(systemParts, text)
+			}
+
+		case "user":
+			blocks, err := userBlocks(msg.Content)
+			if err != nil {
+				return nil, fmt.Errorf("messages[%d]: %w", i, err)
+			}
+			out.Messages = appendBlocks(out.Messages, "user", blocks)
+
+		case "assistant":
+			blocks, err := assistantBlocks(msg)
+			if err != nil {
+				return nil, fmt.Errorf("messages[%d]: %w", i, err)
+			}
+			out.Messages = appendBlocks(out.Messages, "assistant", blocks)
+
+		case "tool":
+			text, err := contentText(msg.Content)
+			if err != nil {
+				return nil, fmt.Errorf("messages[%d]: %w", i, err)
+			}
+			block := contentBlock{Type: "tool_result", ToolUseID: msg.ToolCallID, Content: text}
+			out.Messages = appendBlocks(out.Messages, "user", []contentBlock{block})
+
+		default:
+			return nil, fmt.Errorf("messages[%d]: unsupported role: %s", i, msg.Role)
+		}
+	}
+	out.System = strings.Join(systemParts, "\n\n")
+
+	if len(out.Messages) == 0 {
+		return nil, fmt.Errorf("at least one user message is required")
+	}
+
+	converted, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode anthropic request: %w", err)
+	}
+
+	return &ConvertedRequest{
+		Body:         converted,
+		Model:        req.Model,
+		Stream:       req.Stream,
+		IncludeUsage: req.Stream && req.StreamOptions != nil && req.StreamOptions.IncludeUsage,
+	}, nil
+}
+
+// appendBlocks 追加内容块，与上一条同角色消息合并（Anthropic 要求角色交替）
+func appendBlocks(messages []anthropicMessage, role string, blocks []contentBlock) []anthropicMessage {
+	if len(blocks) == 0 {
+		return messages
+	}
+	if n := len(messages); n > 0 && messages[n-1].Role == role {
+		messages[n-1].Content = append(messages[n-1].Content, blocks...)
+		return messages
+	}
+	return append(messages, anthropicMessage{Role: role, Content: blocks})
+}
+
+// parseStop 解析 stop 参数（string 或 []string）
+func parseStop(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		if single == "" {
+			return nil, nil
+		}
+		return []string{single}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("stop must be a string or an array of strings")
+	}
+	return list, nil
+}
+
+// convertToolChoice 转换 tool_choice
+func convertToolChoice(raw json.RawMessage) (*anthropicToolUsage, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var mode string
+	if err := json.Unmarshal(raw, &mode); err == nil {
+		switch mode {
+		case "auto":
+			return &anthropicToolUsage{Type: "auto"}, nil
+		case "none":
+			return &anthropicToolUsage{Type: "none"}, nil
+		case "required":
+			return &anthropicToolUsage{Type: "any"}, nil
+		default:
+			return nil, fmt.Errorf("unsupported tool_choice: %s", mode)
+		}
+	}
+	var named struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &named); err != nil || named.Function.Name == "" {
+		return nil, fmt.Errorf("invalid tool_choice")
+	}
+	return &anthropicToolUsage{Type: "tool", Name: named.Function.Name}, nil
+}
+
+// parseContent 解析消息内容（string 或 []ContentPart）
+func parseContent(raw json.RawMessage) ([]ContentPart, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return []ContentPart{{Type: "text", Text: text}}, nil
+	}
+	var parts []ContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, fmt.Errorf("content must be a string or an array of content parts")
+	}
+	return parts, nil
+}
+
+// contentText 提取消息中的纯文本内容（system/tool 消息仅支持文本）
+func contentText(raw json.RawMessage) (string, error) {
+	parts, err := parseContent(raw)
+	if err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// userBlocks 转换用户消息内容
+func userBlocks(raw json.RawMessage) ([]contentBlock, error) {
+	parts, err := parseContent(raw)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []contentBlock
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			if part.Text != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: part.Text})
+			}
+		case "image_url":
+			if part.ImageURL == nil || part.ImageURL.URL == "" {
+				return nil, fmt.Errorf("image_url.url is required")
+			}
+			source, err := convertImage(part.ImageURL.URL)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, contentBlock{Type: "image", Source: source})
+		default:
+			return nil, fmt.Errorf("unsupported content part type: %s", part.Type)
+		}
+	}
+	return blocks, nil
+}
+
+// assistantBlocks 转换助手消息（文本 + 工具调用）
+func assistantBlocks(msg ChatMessage) ([]contentBlock, error) {
+	text, err := contentText(msg.Content)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []contentBlock
+	if text != "" {
+		blocks = append(blocks, contentBlock{Type: "text", Text: text})
+	}
+	for _, call := range msg.ToolCalls {
+		input := json.RawMessage(call.Function.Arguments)
+		if strings.TrimSpace(call.Function.Arguments) == "" {
+			input = json.RawMessage(`{}`)
+		} else if !json.Valid(input) {
+			return nil, fmt.Errorf("tool call %s has invalid JSON arguments", call.ID)
+		}
+		blocks = append(blocks, contentBlock{
+			Type:  "tool_use",
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Input: input,
+		})
+	}
+	return blocks, nil
+}
+
+// convertImage 转换图片地址：data URL 转为 base64 来源，其余作为 URL 来源
+func convertImage(rawURL string) (*imageSource, error) {
+	if !strings.HasPrefix(rawURL, "data:") {
+		return &imageSource{Type: "url", URL: rawURL}, nil
+	}
+	// data:image/png;base64,xxxx
+	meta, data, ok := strings.Cut(strings.TrimPrefix(rawURL, "data:"), ",")
+	if !ok || !strings.HasSuffix(meta, ";base64") {
+		return nil, fmt.Errorf("only base64 data URLs are supported for images")
+	}
+	return &imageSource{
+		Type:      "base64",
+		MediaType: strings.TrimSuffix(meta, ";base64"),
+		Data:      data,
+	}, nil
+}
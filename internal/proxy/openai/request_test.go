@@ -0,0 +1,134 @@
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertRequest(t *testing.T) {
+	body := `{
+		"model": "claude-sonnet-4",
+		"max_tokens": 256,
+		"stream": true,
+		"stream_options": {"include_usage": true},
+		"stop": "END",
+		"user": "u-1",
+		"messages": [
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": [
+				{"type": "text", "text": "what is this?"},
+				{"type": "image_url", "image_url": {"url": "data:image/png;base64,AAAA"}}
+			]},
+			{"role": "assistant", "content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{\"q\":\"x\"}"}}
+			]},
+			{"role": "tool", "tool_call_id": "call_1", "content": "found"},
+			{"role": "user", "content": "thanks"}
+		],
+		"tools": [{"type": "function", "function": {"name": "lookup", "parameters": {"type": "object"}}}],
+		"tool_choice": "required"
+	}`
+
+	converted, err := ConvertRequest([]byte(body), 0)
+	if err != nil {
+		t.Fatalf("ConvertRequest failed: %v", err)
+	}
+	if !converted.Stream || !converted.IncludeUsage || converted.Model != "claude-sonnet-4" {
+		t.Errorf("unexpected options: %+v", converted)
+	}
+
+	var req messagesRequest
+	if err := json.Unmarshal(converted.Body, &req); err != nil {
+		t.Fatalf("invalid converted body: %v", err)
+	}
+	if req.MaxTokens != 256 {
+		t.Errorf("Expected max_tokens=256, got %d", req.MaxTokens)
+	}
+	if req.System != "be brief" {
+		t.Errorf("Expected system prompt, got %q", req.System)
+	}
+	if len(req.StopSequences) != 1 || req.StopSequences[0] != "END" {
+		t.Errorf("unexpected stop_sequences: %v", req.StopSequences)
+	}
+	if req.Metadata == nil || req.Metadata.UserID != "u-1" {
+		t.Errorf("Expected metadata.user_id=u-1, got %+v", req.Metadata)
+	}
+	if req.ToolChoice == nil || req.ToolChoice.Type != "any" {
+		t.Errorf("Expected tool_choice any, got %+v", req.ToolChoice)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Name != "lookup" {
+		t.Errorf("unexpected tools: %+v", req.Tools)
+	}
+
+	// user / assistant / user(tool_result + text)
+	if len(req.Messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(req.Messages))
+	}
+	user := req.Messages[0]
+	if user.Role != "user" || len(user.Content) != 2 || user.Content[1].Source == nil ||
+		user.Content[1].Source.Type != "base64" || user.Content[1].Source.MediaType != "image/png" {
+		t.Errorf("unexpected user message: %+v", user)
+	}
+	assistant := req.Messages[1]
+	if assistant.Role != "assistant" || len(assistant.Content) != 1 ||
+		assistant.Content[0].Type != "tool_use" || string(assistant.Content[0].Input) != `{"q":"x"}` {
+		t.Errorf("unexpected assistant message: %+v", assistant)
+	}
+	last := req.Messages[2]
+	if last.Role != "user" || len(last.Content) != 2 ||
+		last.Content[0].Type != "tool_result" || last.Content[0].ToolUseID != "call_1" ||
+		last.Content[1].Text != "thanks" {
+		t.Errorf("unexpected merged user message: %+v", last)
+	}
+}
+
+func TestConvertRequestMaxTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		fallback int
+		expected int
+	}{
+		{"default", `{"model":"m","messages":[{"role":"user","content":"hi"}]}`, 0, DefaultMaxTokens},
+		{"configured default", `{"model":"m","messages":[{"role":"user","content":"hi"}]}`, 1024, 1024},
+		{"max_tokens", `{"model":"m","max_tokens":10,"messages":[{"role":"user","content":"hi"}]}`, 1024, 10},
+		{"max_completion_tokens wins", `{"model":"m","max_tokens":10,"max_completion_tokens":20,"messages":[{"role":"user","content":"hi"}]}`, 1024, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := ConvertRequest([]byte(tt.body), tt.fallback)
+			if err != nil {
+				t.Fatalf("ConvertRequest failed: %v", err)
+			}
+			var req messagesRequest
+			json.Unmarshal(converted.Body, &req)
+			if req.MaxTokens != tt.expected {
+				t.Errorf("Expected max_tokens=%d, got %d", tt.expected, req.MaxTokens)
+			}
+		})
+	}
+}
+
+func TestConvertRequestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", `{`},
+		{"missing model", `{"messages":[{"role":"user","content":"hi"}]}`},
+		{"empty messages", `{"model":"m","messages":[]}`},
+		{"system only", `{"model":"m","messages":[{"role":"system","content":"x"}]}`},
+		{"unknown role", `{"model":"m","messages":[{"role":"robot","content":"x"}]}`},
+		{"bad tool_choice", `{"model":"m","tool_choice":"sometimes","messages":[{"role":"user","content":"x"}]}`},
+		{"bad tool arguments", `{"model":"m","messages":[{"role":"user","content":"x"},{"role":"assistant","tool_calls":[{"id":"c","type":"function","function":{"name":"f","arguments":"{"}}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ConvertRequest([]byte(tt.body), 0); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
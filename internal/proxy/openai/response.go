@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ConvertResponse 将 Anthropic Messages 非流式响应转换为 OpenAI Chat Completion 响应
+func ConvertResponse(body []byte, created int64) ([]byte, error) {
+	var resp messagesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid anthropic response: %w", err)
+	}
+	if resp.Type != "" && resp.Type != "message" {
+		return nil, fmt.Errorf("unexpected anthropic response type: %s", resp.Type)
+	}
+
+	message := ResponseMessage{Role: "assistant"}
+	var texts []string
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			texts = append(texts, block.Text)
+		case "tool_use":
+			args := string(block.Input)
+			if args == "" {
+				args = "{}"
+			}
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:       block.ID,
+				Type:     "function",
+				Function: FunctionCall{Name: block.Name, Arguments: args},
+			})
+		}
+	}
+	if len(texts) > 0 || len(message.ToolCalls) == 0 {
+		content := strings.Join(texts, "")
+		message.Content = &content
+	}
+
+	finishReason := FinishReason(resp.StopReason)
+	completion := ChatCompletion{
+		ID:      completionID(resp.ID),
+		Object:  "chat.completion",
+		Created: created,
+		Model:   resp.Model,
+		Choices: []Choice{{Index: 0, Message: message, FinishReason: &finishReason}},
+		Usage:   convertUsage(resp.Usage),
+	}
+	return json.Marshal(completion)
+}
+
+// FinishReason 将 Anthropic stop_reason 映射为 OpenAI finish_reason
+func FinishReason(stopReason string) string {
+	switch stopReason {
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	default: // end_turn, stop_sequence, pause_turn
+		return "stop"
+	}
+}
+
+// ConvertError 将代理或上游返回的错误体转换为 OpenAI 错误格式
+// 支持 Anthropic JSON 错误和纯文本错误（http.Error）
+func ConvertError(statusCode int, body []byte) []byte {
+	detail := ErrorDetail{Type: errorTypeForStatus(statusCode)}
+
+	var upstream anthropicError
+	if err := json.Unmarshal(body, &upstream); err == nil && upstream.Error.Message != "" {
+		detail.Message = upstream.Error.Message
+		if upstream.Error.Type != "" {
+			detail.Type = upstream.Error.Type
+		}
+	} else {
+		detail.Message = strings.TrimSpace(string(body))
+	}
+	if detail.Message == "" {
+		detail.Message = http.StatusText(statusCode)
+	}
+
+	data, _ := json.Marshal(ErrorResponse{Error: detail})
+	return data
+}
+
+// WriteError 直接向客户端写入 OpenAI 格式错误
+func WriteError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(ConvertError(statusCode, []byte(message)))
+}
+
+// errorTypeForStatus 根据状态码推断错误类型
+func errorTypeForStatus(statusCode int) string {
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return "invalid_request_error"
+	case statusCode == http.StatusUnauthorized:
+		return "authentication_error"
+	case statusCode == http.StatusForbidden:
+		return "permission_error"
+	case statusCode == http.StatusNotFound:
+		return "not_found_error"
+	case statusCode == http.StatusTooManyRequests:
+		return "rate_limit_error"
+	default:
+		return "api_error"
+	}
+}
+
+// convertUsage 转换 Token 用量
+// OpenAI 的 prompt_tokens 包含缓存部分，因此累加 Anthropic 的缓存创建和读取 Token
+func convertUsage(u anthropicUsage) *Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	usage := &Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      prompt + u.OutputTokens,
+	}
+	if u.CacheReadInputTokens > 0 {
+		usage.PromptTokensDetails = &PromptTokensDetails{CachedTokens: u.CacheReadInputTokens}
+	}
+	return usage
+}
+
+// completionID 根据 Anthropic 消息 ID 生成 OpenAI 风格的 ID
+func completionID(messageID string) string {
+	if messageID == "" {
+		return "chatcmpl-unknown"
+	}
+	return "chatcmpl-" + strings.TrimPrefix(messageID, "msg_")
+}
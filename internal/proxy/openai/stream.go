@@ -0,0 +1,230 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// streamEvent Anthropic SSE 事件（按需解析的字段）
+type streamEvent struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message *struct {
+		ID    string         `json:"id"`
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	ContentBlock *contentBlock `json:"content_block"`
+	Delta        *struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StreamTranslator 将 Anthropic SSE 事件逐行转换为 OpenAI chunk
+// 非线程安全，每个请求使用独立实例
+type StreamTranslator struct {
+	id           string
+	model        string
+	created      int64
+	includeUsage bool
+
+	usage        anthropicUsage
+	toolIndex    map[int]int // Anthropic 内容块索引 -> OpenAI tool_calls 索引
+	nextTool     int
+	finishReason string
+	started      bool
+	done         bool
+}
+
+// NewStreamTranslator 创建流式转换器
+// model 为请求中的模型名称，上游 message_start 中的模型名称优先
+func NewStreamTranslator(model string, created int64, includeUsage bool) *StreamTranslator {
+	return &StreamTranslator{
+		id:           completionID(""),
+		model:        model,
+		created:      created,
+		includeUsage: includeUsage,
+		toolIndex:    make(map[int]int),
+	}
+}
+
+// Done 是否已输出结束标记 [DONE]
+func (t *StreamTranslator) Done() bool {
+	return t.done
+}
+
+// TranslateLine 转换一行 SSE 文本，返回需要写给客户端的 SSE 帧（可能为空）
+// event: 行和空行被忽略（事件类型从 data 的 type 字段获取）；
+// 代理自身输出的非 JSON 状态行（retry:/suspend: 等）转换为 SSE 注释，error: 行转换为 OpenAI 错误
+func (t *StreamTranslator) TranslateLine(line string) []string {
+	line = strings.TrimRight(line, "\r")
+	if t.done || !strings.HasPrefix(line, "data:") {
+		return nil
+	}
+	payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+	if payload == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(payload, "{") {
+		if msg, ok := strings.CutPrefix(payload, "error:"); ok {
+			return t.errorFrames("api_error", strings.TrimSpace(msg))
+		}
+		return []string{": " + payload + "\n\n"}
+	}
+
+	var ev streamEvent
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		return nil
+	}
+
+	switch ev.Type {
+	case "message_start":
+		if ev.Message != nil {
+			if ev.Message.ID != "" {
+				t.id = completionID(ev.Message.ID)
+			}
+			if ev.Message.Model != "" {
+				t.model = ev.Message.Model
+			}
+			t.usage = ev.Message.Usage
+		}
+		return t.start()
+
+	case "content_block_start":
+		frames := t.start()
+		if ev.ContentBlock != nil && ev.ContentBlock.Type == "tool_use" {
+			index := t.nextTool
+			t.toolIndex[ev.Index] = index
+			t.nextTool++
+			frames = append(frames, t.chunk(Delta{ToolCalls: []ToolCallDelta{{
+				Index:    index,
+				ID:       ev.ContentBlock.ID,
+				Type:     "function",
+				Function: FunctionCallDelta{Name: ev.ContentBlock.Name},
+			}}}, nil))
+		}
+		return frames
+
+	case "content_block_delta":
+		if ev.Delta == nil {
+			return nil
+		}
+		frames := t.start()
+		switch ev.Delta.Type {
+		case "text_delta":
+			text := ev.Delta.Text
+			frames = append(frames, t.chunk(Delta{Content: &text}, nil))
+		case "input_json_delta":
+			index, ok := t.toolIndex[ev.Index]
+			if !ok || ev.Delta.PartialJSON == "" {
+				return frames
+			}
+			frames = append(frames, t.chunk(Delta{ToolCalls: []ToolCallDelta{{
+				Index:    index,
+				Function: FunctionCallDelta{Arguments: ev.Delta.PartialJSON},
+			}}}, nil))
+		}
+		return frames
+
+	case "message_delta":
+		if ev.Usage != nil {
+			t.usage.OutputTokens = ev.Usage.OutputTokens
+			if ev.Usage.InputTokens > 0 {
+				t.usage.InputTokens = ev.Usage.InputTokens
+			}
+		}
+		if ev.Delta != nil && ev.Delta.StopReason != "" {
+			t.finishReason = FinishReason(ev.Delta.StopReason)
+		}
+		return nil
+
+	case "message_stop":
+		return t.Finish()
+
+	case "error":
+		errType, message := "api_error", "upstream stream error"
+		if ev.Error != nil {
+			if ev.Error.Type != "" {
+				errType = ev.Error.Type
+			}
+			if ev.Error.Message != "" {
+				message = ev.Error.Message
+			}
+		}
+		return t.errorFrames(errType, message)
+	}
+
+	// ping、content_block_stop、thinking 等事件无需转发
+	return nil
+}
+
+// Finish 输出结束块、可选的 usage 块和 [DONE]（重复调用无副作用）
+func (t *StreamTranslator) Finish() []string {
+	if t.done {
+		return nil
+	}
+	frames := t.start()
+
+	finishReason := t.finishReason
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+	frames = append(frames, t.chunk(Delta{}, &finishReason))
+
+	if t.includeUsage {
+		frames = append(frames, t.frame(ChatCompletionChunk{
+			ID:      t.id,
+			Object:  "chat.completion.chunk",
+			Created: t.created,
+			Model:   t.model,
+			Choices: []ChunkChoice{},
+			Usage:   convertUsage(t.usage),
+		}))
+	}
+
+	t.done = true
+	return append(frames, "data: [DONE]\n\n")
+}
+
+// start 首个输出块携带 role
+func (t *StreamTranslator) start() []string {
+	if t.started {
+		return nil
+	}
+	t.started = true
+	empty := ""
+	return []string{t.chunk(Delta{Role: "assistant", Content: &empty}, nil)}
+}
+
+// errorFrames 输出 OpenAI 错误对象并结束流
+func (t *StreamTranslator) errorFrames(errType, message string) []string {
+	data, _ := json.Marshal(ErrorResponse{Error: ErrorDetail{Message: message, Type: errType}})
+	t.done = true
+	return []string{"data: " + string(data) + "\n\n", "data: [DONE]\n\n"}
+}
+
+// chunk 构造单个 chat.completion.chunk 帧
+func (t *StreamTranslator) chunk(delta Delta, finishReason *string) string {
+	return t.frame(ChatCompletionChunk{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []ChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+	})
+}
+
+// frame 序列化为 SSE data 帧
+func (t *StreamTranslator) frame(v any) string {
+	data, _ := json.Marshal(v)
+	return "data: " + string(data) + "\n\n"
+}
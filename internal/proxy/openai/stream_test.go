@@ -0,0 +1,174 @@
+package openai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// collectChunks 解析 SSE 帧中的 chunk，返回 chunk 列表和是否收到 [DONE]
+func collectChunks(t *testing.T, output string) ([]ChatCompletionChunk, bool) {
+	t.Helper()
+	var chunks []ChatCompletionChunk
+	done := false
+	for _, line := range strings.Split(output, "\n") {
+		payload, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if payload == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			t.Fatalf("invalid chunk %q: %v", payload, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, done
+}
+
+func TestStreamTranslator(t *testing.T) {
+	lines := []string{
+		"event: message_start",
+		`data: {"type":"message_start","message":{"id":"msg_123","model":"claude-sonnet-4","usage":{"input_tokens":10,"cache_read_input_tokens":5,"output_tokens":1}}}`,
+		"",
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`,
+		`data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"lookup","input":{}}}`,
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"q\":"}}`,
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"1}"}}`,
+		`data: {"type":"ping"}`,
+		`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":7}}`,
+		`data: {"type":"message_stop"}`,
+	}
+
+	translator := NewStreamTranslator("requested-model", 1700000000, true)
+	var output strings.Builder
+	for _, line := range lines {
+		for _, frame := range translator.TranslateLine(line) {
+			output.WriteString(frame)
+		}
+	}
+	if !translator.Done() {
+		t.Fatal("Expected translator to be done after message_stop")
+	}
+	if extra := translator.Finish(); extra != nil {
+		t.Errorf("Expected Finish to be idempotent, got %v", extra)
+	}
+
+	chunks, done := collectChunks(t, output.String())
+	if !done {
+		t.Error("Expected [DONE] marker")
+	}
+
+	var text, args string
+	var finish string
+	var usage *Usage
+	for _, chunk := range chunks {
+		if chunk.ID != "chatcmpl-123" || chunk.Model != "claude-sonnet-4" {
+			t.Errorf("unexpected chunk identity: %s %s", chunk.ID, chunk.Model)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != nil {
+				text += *choice.Delta.Content
+			}
+			for _, call := range choice.Delta.ToolCalls {
+				if call.ID != "" && (call.ID != "toolu_1" || call.Function.Name != "lookup" || call.Index != 0) {
+					t.Errorf("unexpected tool call header: %+v", call)
+				}
+				args += call.Function.Arguments
+			}
+			if choice.FinishReason != nil {
+				finish = *choice.FinishReason
+			}
+		}
+	}
+
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Error("Expected first chunk to carry assistant role")
+	}
+	if text != "Hello" {
+		t.Errorf("Expected text Hello, got %q", text)
+	}
+	if args != `{"q":1}` {
+		t.Errorf("Expected tool arguments, got %q", args)
+	}
+	if finish != "tool_calls" {
+		t.Errorf("Expected finish_reason tool_calls, got %q", finish)
+	}
+	if usage == nil || usage.PromptTokens != 15 || usage.CompletionTokens != 7 || usage.TotalTokens != 22 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestStreamTranslatorProxyStatusLines(t *testing.T) {
+	translator := NewStreamTranslator("m", 0, false)
+
+	frames := translator.TranslateLine("data: retry: 正在重试端点")
+	if len(frames) != 1 || !strings.HasPrefix(frames[0], ": ") {
+		t.Errorf("Expected status line as SSE comment, got %v", frames)
+	}
+
+	frames = translator.TranslateLine("data: error: all endpoints failed")
+	output := strings.Join(frames, "")
+	if !strings.Contains(output, `"message":"all endpoints failed"`) || !strings.HasSuffix(output, "data: [DONE]\n\n") {
+		t.Errorf("unexpected error frames: %q", output)
+	}
+	if !translator.Done() {
+		t.Error("Expected translator to be done after error")
+	}
+}
+
+func TestResponseWriterNonStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := NewResponseWriter(rec, &ConvertedRequest{Model: "m"})
+	rw.Header().Set("Content-Length", "999")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write([]byte(`{"id":"msg_1","type":"message","model":"claude","content":[{"type":"text","text":"hi"}],"stop_reason":"max_tokens","usage":{"input_tokens":3,"output_tokens":2}}`))
+	rw.Close()
+
+	var completion ChatCompletion
+	if err := json.Unmarshal(rec.Body.Bytes(), &completion); err != nil {
+		t.Fatalf("invalid response: %v (%s)", err, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Length") != "" {
+		t.Error("Expected stale Content-Length to be removed")
+	}
+	if completion.ID != "chatcmpl-1" || completion.Object != "chat.completion" {
+		t.Errorf("unexpected completion: %+v", completion)
+	}
+	choice := completion.Choices[0]
+	if choice.Message.Content == nil || *choice.Message.Content != "hi" || *choice.FinishReason != "length" {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if completion.Usage.TotalTokens != 5 {
+		t.Errorf("Expected total_tokens=5, got %d", completion.Usage.TotalTokens)
+	}
+}
+
+func TestResponseWriterError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := NewResponseWriter(rec, &ConvertedRequest{Model: "m", Stream: true})
+	rw.WriteHeader(http.StatusTooManyRequests)
+	rw.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+	rw.Close()
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", rec.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid error response: %v", err)
+	}
+	if resp.Error.Message != "slow down" || resp.Error.Type != "rate_limit_error" {
+		t.Errorf("unexpected error: %+v", resp.Error)
+	}
+}
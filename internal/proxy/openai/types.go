@@ -0,0 +1,267 @@
+// Package openai 提供 OpenAI Chat Completions 与 Anthropic Messages 之间的协议转换
+// 请求转换为 Anthropic 格式后交由现有代理流程处理（端点选择、重试、Token 统计），
+// 响应（含 SSE 流）再转换回 OpenAI 格式返回给客户端
+package openai
+
+import "encoding/json"
+
+const (
+	// ChatCompletionsPath OpenAI Chat Completions 接口路径
+	ChatCompletionsPath = "/v1/chat/completions"
+	// MessagesPath 转换后转发的 Anthropic Messages 接口路径
+	MessagesPath = "/v1/messages"
+	// AnthropicVersion 客户端未指定时使用的 anthropic-version 头
+	AnthropicVersion = "2023-06-01"
+	// DefaultMaxTokens 客户端未指定 max_tokens 时的默认值（Anthropic 要求必填）
+	DefaultMaxTokens = 4096
+)
+
+// ============================================================
+// OpenAI 请求/响应结构
+// ============================================================
+
+// ChatCompletionRequest OpenAI Chat Completions 请求
+type ChatCompletionRequest struct {
+	Model               string          `json:"model"`
+	Messages            []ChatMessage   `json:"messages"`
+	MaxTokens           *int            `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int            `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64        `json:"temperature,omitempty"`
+	TopP                *float64        `json:"top_p,omitempty"`
+	Stop                json.RawMessage `json:"stop,omitempty"` // string 或 []string
+	Stream              bool            `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions  `json:"stream_options,omitempty"`
+	Tools               []Tool          `json:"tools,omitempty"`
+	ToolChoice          json.RawMessage `json:"tool_choice,omitempty"` // "auto" | "none" | "required" | {type, function}
+	User                string          `json:"user,omitempty"`
+}
+
+// StreamOptions 流式选项
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// ChatMessage OpenAI 消息
+type ChatMessage struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content,omitempty"` // string 或 []ContentPart
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+// ContentPart OpenAI 多模态内容片段
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL 图片地址（http(s) URL 或 data URL）
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// Tool OpenAI 工具定义
+type Tool struct {
+	Type     string      `json:"type"`
+	Function FunctionDef `json:"function"`
+}
+
+// FunctionDef 函数定义
+type FunctionDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall 工具调用
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall 函数调用（arguments 为 JSON 字符串）
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletion OpenAI 非流式响应
+type ChatCompletion struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+// Choice 非流式响应选项
+type Choice struct {
+	Index        int             `json:"index"`
+	Message      ResponseMessage `json:"message"`
+	FinishReason *string         `json:"finish_reason"`
+}
+
+// ResponseMessage 非流式响应消息
+type ResponseMessage struct {
+	Role      string     `json:"role"`
+	Content   *string    `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChatCompletionChunk OpenAI 流式响应块
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Object  string        `json:"object"`
+	Created int64         `json:"created"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *Usage        `json:"usage,omitempty"`
+}
+
+// ChunkChoice 流式响应选项
+type ChunkChoice struct {
+	Index        int     `json:"index"`
+	Delta        Delta   `json:"delta"`
+	FinishReason *string `json:"finish_reason"`
+}
+
+// Delta 流式增量内容
+type Delta struct {
+	Role      string          `json:"role,omitempty"`
+	Content   *string         `json:"content,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta 流式工具调用增量
+type ToolCallDelta struct {
+	Index    int               `json:"index"`
+	ID       string            `json:"id,omitempty"`
+	Type     string            `json:"type,omitempty"`
+	Function FunctionCallDelta `json:"function"`
+}
+
+// FunctionCallDelta 流式函数调用增量
+type FunctionCallDelta struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// Usage OpenAI Token 用量
+type Usage struct {
+	PromptTokens        int                  `json:"prompt_tokens"`
+	CompletionTokens    int                  `json:"completion_tokens"`
+	TotalTokens         int                  `json:"total_tokens"`
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails 输入 Token 明细
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// ErrorResponse OpenAI 错误响应
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail OpenAI 错误详情
+type ErrorDetail struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code"`
+}
+
+// ============================================================
+// Anthropic Messages 结构
+// ============================================================
+
+// messagesRequest Anthropic Messages 请求
+type messagesRequest struct {
+	Model         string              `json:"model"`
+	MaxTokens     int                 `json:"max_tokens"`
+	System        string              `json:"system,omitempty"`
+	Messages      []anthropicMessage  `json:"messages"`
+	Temperature   *float64            `json:"temperature,omitempty"`
+	TopP          *float64            `json:"top_p,omitempty"`
+	StopSequences []string            `json:"stop_sequences,omitempty"`
+	Stream        bool                `json:"stream,omitempty"`
+	Tools         []anthropicTool     `json:"tools,omitempty"`
+	ToolChoice    *anthropicToolUsage `json:"tool_choice,omitempty"`
+	Metadata      *anthropicMetadata  `json:"metadata,omitempty"`
+}
+
+// anthropicMessage Anthropic 消息
+type anthropicMessage struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+// contentBlock Anthropic 内容块（text / image / tool_use / tool_result）
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	Source    *imageSource    `json:"source,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+// imageSource Anthropic 图片来源
+type imageSource struct {
+	Type      string `json:"type"` // "base64" | "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// anthropicTool Anthropic 工具定义
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// anthropicToolUsage Anthropic tool_choice
+type anthropicToolUsage struct {
+	Type string `json:"type"` // "auto" | "any" | "tool" | "none"
+	Name string `json:"name,omitempty"`
+}
+
+// anthropicMetadata Anthropic 请求元数据
+type anthropicMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// messagesResponse Anthropic 非流式响应
+type messagesResponse struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      anthropicUsage `json:"usage"`
+}
+
+// anthropicUsage Anthropic Token 用量
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// anthropicError Anthropic 错误响应
+type anthropicError struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
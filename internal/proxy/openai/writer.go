@@ -0,0 +1,167 @@
+package openai
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ResponseWriter 包装客户端 ResponseWriter，将代理输出的 Anthropic 响应转换为 OpenAI 格式
+//   - 流式成功响应：逐行转换 SSE 并立即刷新
+//   - 非流式响应：缓冲完整响应体，在 Close 时转换
+//   - 错误响应（状态码 >= 400）：缓冲后转换为 OpenAI 错误 JSON
+//
+// 处理完成后必须调用 Close
+type ResponseWriter struct {
+	w          http.ResponseWriter
+	flusher    http.Flusher
+	stream     bool
+	translator *StreamTranslator
+
+	statusCode  int
+	wroteHeader bool
+	buffering   bool         // 是否缓冲响应体（非流式或错误响应）
+	body        bytes.Buffer // 缓冲的响应体
+	pending     []byte       // 流式模式下未完成的行
+	created     int64
+}
+
+// NewResponseWriter 创建转换 ResponseWriter
+func NewResponseWriter(w http.ResponseWriter, req *ConvertedRequest) *ResponseWriter {
+	created := time.Now().Unix()
+	rw := &ResponseWriter{
+		w:         w,
+		stream:    req.Stream,
+		buffering: !req.Stream,
+		created:   created,
+	}
+	if f, ok := w.(http.Flusher); ok {
+		rw.flusher = f
+	}
+	if req.Stream {
+		rw.translator = NewStreamTranslator(req.Model, created, req.IncludeUsage)
+	}
+	return rw
+}
+
+// Header 实现 http.ResponseWriter
+func (rw *ResponseWriter) Header() http.Header {
+	return rw.w.Header()
+}
+
+// WriteHeader 实现 http.ResponseWriter
+// 错误状态码切换为缓冲模式，以便在 Close 时输出 OpenAI 错误 JSON
+func (rw *ResponseWriter) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.statusCode = statusCode
+
+	if statusCode >= http.StatusBadRequest {
+		rw.buffering = true
+		return
+	}
+	if !rw.buffering {
+		rw.w.Header().Del("Content-Length")
+		rw.w.WriteHeader(statusCode)
+	}
+}
+
+// Write 实现 http.ResponseWriter
+func (rw *ResponseWriter) Write(p []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.buffering {
+		return rw.body.Write(p)
+	}
+
+	// 流式：按行转换，保留不完整的行等待后续数据
+	rw.pending = append(rw.pending, p...)
+	for {
+		idx := bytes.IndexByte(rw.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(rw.pending[:idx])
+		rw.pending = rw.pending[idx+1:]
+		if err := rw.writeFrames(rw.translator.TranslateLine(line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush 实现 http.Flusher（缓冲模式下不刷新）
+func (rw *ResponseWriter) Flush() {
+	if !rw.buffering && rw.flusher != nil {
+		rw.flusher.Flush()
+	}
+}
+
+// Close 完成响应转换并写出剩余数据
+func (rw *ResponseWriter) Close() {
+	if !rw.wroteHeader {
+		// 处理流程未输出任何内容
+		rw.WriteHeader(http.StatusBadGateway)
+	}
+
+	if !rw.buffering {
+		if len(rw.pending) > 0 {
+			rw.writeFrames(rw.translator.TranslateLine(string(rw.pending)))
+			rw.pending = nil
+		}
+		// 上游未正常结束（无 message_stop）时补齐结束标记
+		rw.writeFrames(rw.translator.Finish())
+		rw.Flush()
+		return
+	}
+
+	header := rw.w.Header()
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	header.Del("Transfer-Encoding")
+	header.Del("X-Content-Type-Options")
+	header.Set("Content-Type", "application/json")
+
+	if rw.statusCode >= http.StatusBadRequest {
+		rw.w.WriteHeader(rw.statusCode)
+		rw.w.Write(ConvertError(rw.statusCode, streamErrorBody(rw.body.Bytes())))
+		return
+	}
+
+	converted, err := ConvertResponse(rw.body.Bytes(), rw.created)
+	if err != nil {
+		rw.w.WriteHeader(http.StatusBadGateway)
+		rw.w.Write(ConvertError(http.StatusBadGateway, []byte(err.Error())))
+		return
+	}
+	rw.w.WriteHeader(rw.statusCode)
+	rw.w.Write(converted)
+}
+
+// writeFrames 写出转换后的 SSE 帧
+func (rw *ResponseWriter) writeFrames(frames []string) error {
+	for _, frame := range frames {
+		if _, err := rw.w.Write([]byte(frame)); err != nil {
+			return err
+		}
+	}
+	if len(frames) > 0 {
+		rw.Flush()
+	}
+	return nil
+}
+
+// streamErrorBody 从流式错误输出（"data: error: ..."）中提取错误消息，其余内容原样返回
+func streamErrorBody(body []byte) []byte {
+	text := strings.TrimSpace(string(body))
+	if msg, ok := strings.CutPrefix(text, "data:"); ok {
+		msg = strings.TrimSpace(msg)
+		msg = strings.TrimSpace(strings.TrimPrefix(msg, "error:"))
+		return []byte(msg)
+	}
+	return body
+}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"cc-forwarder/internal/proxy/openai"
+)
+
+// serveChatCompletions 处理 OpenAI Chat Completions 请求
+// 请求体转换为 Anthropic Messages 格式并改写路径后复用标准转发流程（端点选择、重试、Token 统计），
+// 响应经 openai.ResponseWriter 转换回 OpenAI 格式
+func (h *Handler) serveChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		openai.WriteError(w, http.StatusMethodNotAllowed, "only POST is supported")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		openai.WriteError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	converted, err := openai.ConvertRequest(body, h.config.OpenAICompat.DefaultMaxTokens)
+	if err != nil {
+		openai.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Debug("🔌 [OpenAI兼容] 请求已转换为 Messages 格式",
+		"model", converted.Model,
+		"stream", converted.Stream)
+
+	inner := r.Clone(r.Context())
+	inner.URL.Path = openai.MessagesPath
+	inner.URL.RawPath = ""
+	inner.RequestURI = openai.MessagesPath
+	inner.Body = io.NopCloser(bytes.NewReader(converted.Body))
+	inner.ContentLength = int64(len(converted.Body))
+
+	// 清除会影响流式检测的客户端头，由转换结果决定是否为流式请求
+	inner.Header.Del("Accept")
+	inner.Header.Del("Cache-Control")
+	inner.Header.Del("stream")
+	inner.Header.Set("Content-Length", strconv.Itoa(len(converted.Body)))
+	if converted.Stream {
+		inner.Header.Set("Accept", "text/event-stream")
+	}
+	if inner.Header.Get("anthropic-version") == "" {
+		inner.Header.Set("anthropic-version", openai.AnthropicVersion)
+	}
+
+	tw := openai.NewResponseWriter(w, converted)
+	h.ServeHTTP(tw, inner)
+	tw.Close()
+}
@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"cc-forwarder/internal/capture"
+	"cc-forwarder/internal/tracking"
+)
+
+// StreamReplayResult 流式响应重放结果
+type StreamReplayResult struct {
+	Model      string                 // 解析到的模型名称
+	TokenUsage *tracking.TokenUsage   // 解析到的 Token 使用统计
+	Complete   bool                   // 流是否完整（收到 message_start/message_delta/message_stop）
+	Err        error                  // 流处理返回的错误（含流不完整的 StreamIncompleteError）
+	Output     []byte                 // 转发给客户端的数据
+	Duration   time.Duration          // 处理耗时
+	Stats      map[string]interface{} // 流处理统计
+}
+
+// ReplayStream 将 SSE 响应交给 StreamProcessor/TokenParser 处理（不记录用量）
+// 用于重放抓取的流式响应，复现 Token 解析和流完整性判断的问题
+func ReplayStream(ctx context.Context, resp *http.Response, requestID, endpoint string) *StreamReplayResult {
+	writer := &replayWriter{header: make(http.Header)}
+	parser := NewTokenParserWithRequestID(requestID)
+	processor := NewStreamProcessor(parser, nil, writer, writer, requestID, endpoint)
+
+	start := time.Now()
+	tokenUsage, model, err := processor.ProcessStreamWithRetry(ctx, resp)
+
+	return &StreamReplayResult{
+		Model:      model,
+		TokenUsage: tokenUsage,
+		Complete:   err == nil && parser.IsStreamComplete(),
+		Err:        err,
+		Output:     writer.buffer.Bytes(),
+		Duration:   time.Since(start),
+		Stats:      processor.GetProcessingStats(),
+	}
+}
+
+// ReplayCapturedStream 离线重放抓取的 SSE 响应（不访问端点）
+// realtime 为 true 时按记录的到达时间间隔输出数据块
+func ReplayCapturedStream(ctx context.Context, requestID string, attempt *capture.Attempt, realtime bool) (*StreamReplayResult, error) {
+	resp, err := capture.NewResponse(ctx, attempt, realtime)
+	if err != nil {
+		return nil, err
+	}
+	return ReplayStream(ctx, resp, requestID, attempt.Endpoint), nil
+}
+
+// replayWriter 收集重放输出的 http.ResponseWriter
+type replayWriter struct {
+	header http.Header
+	buffer bytes.Buffer
+	status int
+}
+
+func (w *replayWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *replayWriter) Write(data []byte) (int, error) {
+	return w.buffer.Write(data)
+}
+
+func (w *replayWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+}
+
+func (w *replayWriter) Flush() {}
@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"cc-forwarder/internal/capture"
+)
+
+// TestReplayCapturedStream_Truncated 离线重放连接中断的抓取记录，应判定为流不完整
+func TestReplayCapturedStream_Truncated(t *testing.T) {
+	c, err := capture.LoadFile(filepath.Join("testdata", "captures", "truncated_stream.json"))
+	if err != nil {
+		t.Fatalf("加载抓取文件失败: %v", err)
+	}
+	attempt, err := c.Attempt(-1)
+	if err != nil {
+		t.Fatalf("获取尝试失败: %v", err)
+	}
+
+	result, err := ReplayCapturedStream(context.Background(), c.RequestID, attempt, false)
+	if err != nil {
+		t.Fatalf("重放失败: %v", err)
+	}
+
+	if result.Complete || result.Err == nil {
+		t.Fatal("连接中断的流应判定为不完整")
+	}
+	if result.Model != "claude-sonnet-4-20250514" {
+		t.Errorf("模型不匹配: %s", result.Model)
+	}
+	if string(result.Output) != string(attempt.Response.Body()) {
+		t.Errorf("转发给客户端的数据应与抓取的原始数据一致")
+	}
+}
+
+// TestReplayCapturedStream_Complete 离线重放完整的抓取记录，应解析出最终 Token 用量
+func TestReplayCapturedStream_Complete(t *testing.T) {
+	attempt := &capture.Attempt{
+		Endpoint: "vendor-a",
+		Response: &capture.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"text/event-stream"}},
+			Chunks: []capture.Chunk{
+				{Data: capture.Payload("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"model\":\"claude-sonnet-4-20250514\",\"usage\":{\"input_tokens\":10,\"output_tokens\":1}}}\n\n")},
+				// message_delta 跨数据块
+				{Data: capture.Payload("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},")},
+				{Data: capture.Payload("\"usage\":{\"input_tokens\":10,\"output_tokens\":42}}\n\n")},
+				{Data: capture.Payload("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")},
+			},
+			Complete: true,
+		},
+	}
+
+	result, err := ReplayCapturedStream(context.Background(), "req-complete", attempt, false)
+	if err != nil {
+		t.Fatalf("重放失败: %v", err)
+	}
+	if !result.Complete || result.Err != nil {
+		t.Fatalf("期望流完整: %v", result.Err)
+	}
+	if result.TokenUsage == nil || result.TokenUsage.OutputTokens != 42 {
+		t.Errorf("Token 用量不匹配: %+v", result.TokenUsage)
+	}
+}
@@ -105,12 +105,7 @@ func (rh *RetryHandler) ExecuteWithContext(ctx context.Context, operation Operat
 	
 	for {
 		// Get healthy endpoints from currently active groups only (no auto group switching)
-		var endpoints []*endpoint.Endpoint
-		if rh.endpointManager.GetConfig().Strategy.Type == "fastest" && rh.endpointManager.GetConfig().Strategy.FastTestEnabled {
-			endpoints = rh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-		} else {
-			endpoints = rh.endpointManager.GetHealthyEndpoints()
-		}
+		endpoints := rh.endpointManager.GetEndpointsForRequest(ctx)
 		
 		// If no endpoints available from active groups, check if we should suspend request
 		if len(endpoints) == 0 {
@@ -294,7 +289,7 @@ func (rh *RetryHandler) ExecuteWithContext(ctx context.Context, operation Operat
 		for groupName := range groupsFailedThisIteration {
 			if !groupsSetToCooldownThisRequest[groupName] {
 				slog.WarnContext(ctx, fmt.Sprintf("❄️ [组失败] 组 %s 中所有端点均已失败，将组设置为冷却状态", groupName))
-				rh.endpointManager.GetGroupManager().SetGroupCooldown(groupName)
+				rh.endpointManager.CooldownGroup(groupName, "组内所有端点均已失败")
 				groupsSetToCooldownThisRequest[groupName] = true
 			}
 		}
@@ -303,12 +298,7 @@ func (rh *RetryHandler) ExecuteWithContext(ctx context.Context, operation Operat
 		if rh.endpointManager.GetConfig().Group.AutoSwitchBetweenGroups {
 			// Auto mode: Check if there are still active groups available after cooldown
 			// Get fresh endpoint list to see if any new groups became active
-			var newEndpoints []*endpoint.Endpoint
-			if rh.endpointManager.GetConfig().Strategy.Type == "fastest" && rh.endpointManager.GetConfig().Strategy.FastTestEnabled {
-				newEndpoints = rh.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-			} else {
-				newEndpoints = rh.endpointManager.GetHealthyEndpoints()
-			}
+			newEndpoints := rh.endpointManager.GetEndpointsForRequest(ctx)
 			
 			// If we have new endpoints available (from different groups), continue the retry loop
 			if len(newEndpoints) > 0 && len(groupsFailedThisIteration) > 0 {
@@ -415,6 +405,17 @@ func (rh *RetryHandler) calculateDelay(attempt int) time.Duration {
 // 请使用 RetryController.ShouldRetry 替代
 // 迁移指南: docs/migration/retry_v3.3.md
 func (rh *RetryHandler) shouldRetryStatusCode(statusCode int) *RetryableError {
+	// 全局重试策略中配置了匹配的状态码规则时优先使用
+	if statusCode >= 400 {
+		if rule, ok := matchStatusRule(rh.config.Retry.Policy, statusCode); ok {
+			return &RetryableError{
+				StatusCode:  statusCode,
+				IsRetryable: rule.Action != config.RetryActionFail,
+				Reason:      fmt.Sprintf("重试策略 %s: %s", rule.Match, rule.Action),
+			}
+		}
+	}
+
 	switch {
 	case statusCode >= 200 && statusCode < 400:
 		// 2xx Success and 3xx Redirects - don't retry
@@ -0,0 +1,288 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/proxy/handlers"
+)
+
+// retryErrorTypeNames 错误类型在重试策略 match 中使用的名称
+var retryErrorTypeNames = map[handlers.ErrorType]string{
+	handlers.ErrorTypeUnknown:            "unknown",
+	handlers.ErrorTypeNetwork:            "network",
+	handlers.ErrorTypeEOF:                "eof",
+	handlers.ErrorTypeConnectionTimeout:  "connection_timeout",
+	handlers.ErrorTypeResponseTimeout:    "response_timeout",
+	handlers.ErrorTypeTimeout:            "timeout",
+	handlers.ErrorTypeHTTP:               "http",
+	handlers.ErrorTypeServerError:        "server_error",
+	handlers.ErrorTypeStream:             "stream",
+	handlers.ErrorTypeAuth:               "auth",
+	handlers.ErrorTypeRateLimit:          "rate_limit",
+	handlers.ErrorTypeParsing:            "parsing",
+	handlers.ErrorTypeClientCancel:       "client_cancel",
+	handlers.ErrorTypeNoHealthyEndpoints: "no_healthy_endpoints",
+}
+
+// retryErrorTypeLabels 错误类型的中文描述（用于决策原因）
+var retryErrorTypeLabels = map[string]string{
+	"unknown":              "未知错误",
+	"network":              "网络错误",
+	"eof":                  "EOF连接中断",
+	"connection_timeout":   "连接超时",
+	"response_timeout":     "响应超时",
+	"timeout":              "超时错误",
+	"http":                 "HTTP错误",
+	"server_error":         "服务器错误",
+	"stream":               "流式处理错误",
+	"auth":                 "认证/权限错误",
+	"rate_limit":           "限流错误",
+	"parsing":              "解析错误",
+	"no_healthy_endpoints": "健康检查限制",
+}
+
+// httpStatusPattern 匹配转发层构造的状态码错误（"HTTP 503: ..." 或 "endpoint returned error: 503"）
+var httpStatusPattern = regexp.MustCompile(`(?i)^(?:http|endpoint returned error:) (\d{3})\b`)
+
+// parseHTTPStatusCode 从错误信息中提取上游响应状态码，非状态码错误返回 0
+func parseHTTPStatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	matches := httpStatusPattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(matches[1])
+	return code
+}
+
+// defaultRetryPolicy 内置默认重试策略，与引入可配置策略前的硬编码行为一致
+//   - 网络错误、连接超时、服务器错误：同端点指数退避重试，达到上限后切换端点
+//   - 限流错误：更保守的退避（基础延迟 ×3、倍数 2.5、最大延迟 ×2），延迟超过 30s 时尝试挂起
+//   - 认证/权限错误、健康检查限制：直接切换端点
+//   - 解析错误：按指数退避等待后切换端点
+//   - EOF、响应超时、4xx、流式错误、未知错误：不重试（避免重复计费）
+func defaultRetryPolicy(retry config.RetryConfig) []config.RetryRule {
+	return []config.RetryRule{
+		{Match: "network", Action: config.RetryActionRetry},
+		{Match: "connection_timeout", Action: config.RetryActionRetry},
+		{Match: "server_error", Action: config.RetryActionRetry},
+		{
+			Match:        "rate_limit",
+			Action:       config.RetryActionRetry,
+			BaseDelay:    retry.BaseDelay * 3,
+			MaxDelay:     retry.MaxDelay * 2,
+			Multiplier:   2.5,
+			SuspendAfter: 30 * time.Second,
+		},
+		{Match: "auth", Action: config.RetryActionSwitch},
+		{Match: "parsing", Action: config.RetryActionSwitch, BaseDelay: retry.BaseDelay},
+		{Match: "no_healthy_endpoints", Action: config.RetryActionSwitch},
+		{Match: "eof", Action: config.RetryActionFail},
+		{Match: "response_timeout", Action: config.RetryActionFail},
+		{Match: "timeout", Action: config.RetryActionFail},
+		{Match: "http", Action: config.RetryActionFail},
+		{Match: "stream", Action: config.RetryActionFail},
+		{Match: "unknown", Action: config.RetryActionFail},
+	}
+}
+
+// matchRetryRule 在一组规则中查找匹配项，优先级：状态码 > 状态码类别 > 错误类型
+func matchRetryRule(rules []config.RetryRule, statusCode int, errorType string) (config.RetryRule, bool) {
+	if rule, ok := matchStatusRule(rules, statusCode); ok {
+		return rule, true
+	}
+	for _, rule := range rules {
+		if strings.EqualFold(strings.TrimSpace(rule.Match), errorType) {
+			return rule, true
+		}
+	}
+	return config.RetryRule{}, false
+}
+
+// matchStatusRule 查找匹配状态码的规则，精确状态码优先于状态码类别
+func matchStatusRule(rules []config.RetryRule, statusCode int) (config.RetryRule, bool) {
+	if statusCode <= 0 {
+		return config.RetryRule{}, false
+	}
+	code := strconv.Itoa(statusCode)
+	class := code[:1] + "xx"
+	for _, rule := range rules {
+		if strings.TrimSpace(rule.Match) == code {
+			return rule, true
+		}
+	}
+	for _, rule := range rules {
+		if strings.EqualFold(strings.TrimSpace(rule.Match), class) {
+			return rule, true
+		}
+	}
+	return config.RetryRule{}, false
+}
+
+// resolveRetryRule 按 端点 retry_policy > 全局 retry.policy > 默认策略 的顺序查找适用规则
+func (rm *RetryManager) resolveRetryRule(errorCtx *handlers.ErrorContext) config.RetryRule {
+	errorType := retryErrorTypeNames[errorCtx.ErrorType]
+
+	if rm.endpointMgr != nil && errorCtx.EndpointName != "" {
+		if ep := rm.endpointMgr.GetEndpointByNameAny(errorCtx.EndpointName); ep != nil {
+			if rule, ok := matchRetryRule(ep.Config.RetryPolicy, errorCtx.StatusCode, errorType); ok {
+				return rule
+			}
+		}
+	}
+	if rule, ok := matchRetryRule(rm.config.Retry.Policy, errorCtx.StatusCode, errorType); ok {
+		return rule
+	}
+	if rule, ok := matchRetryRule(defaultRetryPolicy(rm.config.Retry), 0, errorType); ok {
+		return rule
+	}
+	// 未知的错误类型按保守策略处理
+	return config.RetryRule{Match: "unknown", Action: config.RetryActionFail}
+}
+
+// decideByRule 根据规则生成重试决策
+// 上游通过 Retry-After 等响应头给出了等待时间时，按上游要求等待；超过规则的最大延迟时不在原地等待，直接执行 then 动作
+func (rm *RetryManager) decideByRule(rule config.RetryRule, errorCtx *handlers.ErrorContext, localAttempt int) handlers.RetryDecision {
+	errorType := retryErrorTypeNames[errorCtx.ErrorType]
+	label := retryErrorTypeLabels[errorType]
+	if label == "" {
+		label = retryErrorTypeLabels["unknown"]
+	}
+	if errorCtx.StatusCode > 0 && !strings.EqualFold(strings.TrimSpace(rule.Match), errorType) {
+		label = fmt.Sprintf("HTTP %d", errorCtx.StatusCode)
+	}
+
+	if rule.Action == config.RetryActionRetry {
+		maxAttempts := rule.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = rm.config.Retry.MaxAttempts
+		}
+		then := rule.Then
+		if then == "" {
+			then = config.RetryActionSwitch
+		}
+		if localAttempt < maxAttempts {
+			delay := rm.ruleBackoff(rule, localAttempt)
+			reason := label + "，在同一端点重试"
+			if upstream := errorCtx.UpstreamRetryAfter; upstream > 0 {
+				if upstream > rm.ruleMaxDelay(rule) {
+					return terminalDecision(then, errorType, fmt.Sprintf("%s，上游要求等待 %v", label, upstream))
+				}
+				delay = upstream
+				reason = fmt.Sprintf("%s，按上游要求等待 %v 后在同一端点重试", label, upstream)
+			}
+			return handlers.RetryDecision{
+				RetrySameEndpoint: true,
+				SuspendRequest:    rule.SuspendAfter > 0 && delay > rule.SuspendAfter, // 延迟太长时考虑挂起
+				Delay:             delay,
+				Reason:            reason,
+			}
+		}
+		return terminalDecision(then, errorType, label+"重试达到上限")
+	}
+
+	decision := terminalDecision(rule.Action, errorType, label)
+	// switch 规则设置了 base_delay 时，切换端点前按退避延迟等待
+	if rule.Action == config.RetryActionSwitch && rule.BaseDelay > 0 {
+		decision.Delay = rm.ruleBackoff(rule, localAttempt)
+	}
+	return decision
+}
+
+// terminalDecision 生成不在同一端点重试的决策（切换端点、挂起或终止）
+func terminalDecision(action, errorType, label string) handlers.RetryDecision {
+	switch action {
+	case config.RetryActionSwitch:
+		return handlers.RetryDecision{
+			SwitchEndpoint: true,
+			Reason:         label + "，切换端点",
+		}
+	case config.RetryActionSuspend:
+		// 挂起不可用（未启用或已达上限）时退化为切换端点
+		return handlers.RetryDecision{
+			SwitchEndpoint: true,
+			SuspendRequest: true,
+			Reason:         label + "，挂起请求等待端点恢复",
+		}
+	default:
+		return handlers.RetryDecision{
+			FinalStatus: retryFinalStatus(errorType),
+			Reason:      label + "，不重试",
+		}
+	}
+}
+
+// retryFinalStatus 终止请求时记录的最终状态
+func retryFinalStatus(errorType string) string {
+	switch errorType {
+	case "eof":
+		return "eof_interrupted"
+	case "response_timeout", "timeout":
+		return "timeout"
+	case "stream":
+		return "stream_error"
+	default:
+		return "error"
+	}
+}
+
+// ruleBackoff 按规则计算指数退避延迟，规则未设置的参数使用全局重试配置
+func (rm *RetryManager) ruleBackoff(rule config.RetryRule, attempt int) time.Duration {
+	baseDelay := rule.BaseDelay
+	if baseDelay == 0 {
+		baseDelay = rm.config.Retry.BaseDelay
+	}
+	if attempt <= 0 {
+		return baseDelay
+	}
+	maxDelay := rm.ruleMaxDelay(rule)
+	multiplier := rule.Multiplier
+	if multiplier == 0 {
+		multiplier = rm.config.Retry.Multiplier
+	}
+
+	// 指数退避: baseDelay * (multiplier ^ (attempt-1))
+	delay := time.Duration(float64(baseDelay) * math.Pow(multiplier, float64(attempt-1)))
+
+	// 限制最大延迟
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// ruleMaxDelay 规则的最大退避延迟，未设置时使用全局重试配置
+func (rm *RetryManager) ruleMaxDelay(rule config.RetryRule) time.Duration {
+	if rule.MaxDelay > 0 {
+		return rule.MaxDelay
+	}
+	return rm.config.Retry.MaxDelay
+}
+
+// maxPolicyAttempts 返回所有策略规则中最大的同端点尝试次数（不小于 retry.max_attempts）
+func (rm *RetryManager) maxPolicyAttempts() int {
+	maxAttempts := rm.config.Retry.MaxAttempts
+	collect := func(rules []config.RetryRule) {
+		for _, rule := range rules {
+			if rule.Action == config.RetryActionRetry && rule.MaxAttempts > maxAttempts {
+				maxAttempts = rule.MaxAttempts
+			}
+		}
+	}
+	collect(rm.config.Retry.Policy)
+	if rm.endpointMgr != nil {
+		for _, ep := range rm.endpointMgr.GetAllEndpoints() {
+			collect(ep.Config.RetryPolicy)
+		}
+	}
+	return maxAttempts
+}
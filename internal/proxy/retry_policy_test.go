@@ -0,0 +1,239 @@
+package proxy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"cc-forwarder/config"
+	"cc-forwarder/internal/proxy/handlers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_DefaultMatchesLegacyDecisions(t *testing.T) {
+	rm := createTestRetryManager()
+
+	errorTypes := []handlers.ErrorType{
+		handlers.ErrorTypeUnknown,
+		handlers.ErrorTypeNetwork,
+		handlers.ErrorTypeEOF,
+		handlers.ErrorTypeConnectionTimeout,
+		handlers.ErrorTypeResponseTimeout,
+		handlers.ErrorTypeTimeout,
+		handlers.ErrorTypeHTTP,
+		handlers.ErrorTypeServerError,
+		handlers.ErrorTypeStream,
+		handlers.ErrorTypeAuth,
+		handlers.ErrorTypeRateLimit,
+		handlers.ErrorTypeParsing,
+		handlers.ErrorTypeClientCancel,
+		handlers.ErrorTypeNoHealthyEndpoints,
+	}
+
+	for _, errorType := range errorTypes {
+		for attempt := 0; attempt <= 4; attempt++ {
+			name := fmt.Sprintf("%s/attempt-%d", retryErrorTypeNames[errorType], attempt)
+			t.Run(name, func(t *testing.T) {
+				errorCtx := &handlers.ErrorContext{
+					EndpointName: "test-endpoint-1",
+					ErrorType:    errorType,
+				}
+
+				want := legacyRetryDecision(rm, errorCtx, attempt)
+				got := rm.ShouldRetryWithDecision(errorCtx, attempt, attempt, false)
+				assert.Equal(t, want.RetrySameEndpoint, got.RetrySameEndpoint, "RetrySameEndpoint")
+				assert.Equal(t, want.SwitchEndpoint, got.SwitchEndpoint, "SwitchEndpoint")
+				assert.Equal(t, want.SuspendRequest, got.SuspendRequest, "SuspendRequest")
+				assert.Equal(t, want.Delay, got.Delay, "Delay")
+				assert.Equal(t, want.FinalStatus, got.FinalStatus, "FinalStatus")
+
+				wantRetry, wantDelay := legacyShouldRetry(rm, errorCtx, attempt)
+				gotRetry, gotDelay := rm.ShouldRetry(errorCtx, attempt)
+				assert.Equal(t, wantRetry, gotRetry, "ShouldRetry")
+				assert.Equal(t, wantDelay, gotDelay, "ShouldRetry delay")
+			})
+		}
+	}
+
+	// 限流错误使用更保守的退避：BaseDelay * 3 * 2.5^(n-1)
+	decision := rm.ShouldRetryWithDecision(&handlers.ErrorContext{ErrorType: handlers.ErrorTypeRateLimit}, 2, 2, false)
+	assert.Equal(t, 750*time.Millisecond, decision.Delay)
+	decision = rm.ShouldRetryWithDecision(&handlers.ErrorContext{ErrorType: handlers.ErrorTypeServerError}, 2, 2, false)
+	assert.Equal(t, 200*time.Millisecond, decision.Delay)
+}
+
+// legacyRetryDecision 引入可配置重试策略前 ShouldRetryWithDecision 的硬编码决策（不含 Reason）
+func legacyRetryDecision(rm *RetryManager, errorCtx *handlers.ErrorContext, localAttempt int) handlers.RetryDecision {
+	maxAttempts := rm.config.Retry.MaxAttempts
+	switch errorCtx.ErrorType {
+	case handlers.ErrorTypeClientCancel:
+		return handlers.RetryDecision{FinalStatus: "cancelled"}
+	case handlers.ErrorTypeEOF:
+		return handlers.RetryDecision{FinalStatus: "eof_interrupted"}
+	case handlers.ErrorTypeResponseTimeout, handlers.ErrorTypeTimeout:
+		return handlers.RetryDecision{FinalStatus: "timeout"}
+	case handlers.ErrorTypeConnectionTimeout, handlers.ErrorTypeNetwork, handlers.ErrorTypeServerError:
+		if localAttempt < maxAttempts {
+			return handlers.RetryDecision{RetrySameEndpoint: true, Delay: rm.calculateBackoff(localAttempt)}
+		}
+		return handlers.RetryDecision{SwitchEndpoint: true}
+	case handlers.ErrorTypeHTTP:
+		return handlers.RetryDecision{FinalStatus: "error"}
+	case handlers.ErrorTypeStream:
+		return handlers.RetryDecision{FinalStatus: "stream_error"}
+	case handlers.ErrorTypeAuth:
+		return handlers.RetryDecision{SwitchEndpoint: true}
+	case handlers.ErrorTypeRateLimit:
+		if localAttempt < maxAttempts {
+			delay := rm.calculateRateLimitBackoff(localAttempt)
+			return handlers.RetryDecision{RetrySameEndpoint: true, SuspendRequest: delay > 30*time.Second, Delay: delay}
+		}
+		return handlers.RetryDecision{SwitchEndpoint: true}
+	case handlers.ErrorTypeParsing:
+		return handlers.RetryDecision{SwitchEndpoint: true, Delay: rm.calculateBackoff(localAttempt)}
+	case handlers.ErrorTypeNoHealthyEndpoints:
+		return handlers.RetryDecision{SwitchEndpoint: true}
+	default:
+		return handlers.RetryDecision{FinalStatus: "error"}
+	}
+}
+
+// legacyShouldRetry 引入可配置重试策略前 ShouldRetry 的硬编码决策
+func legacyShouldRetry(rm *RetryManager, errorCtx *handlers.ErrorContext, attempt int) (bool, time.Duration) {
+	if attempt >= rm.config.Retry.MaxAttempts {
+		return false, 0
+	}
+	switch errorCtx.ErrorType {
+	case handlers.ErrorTypeNetwork, handlers.ErrorTypeConnectionTimeout, handlers.ErrorTypeServerError, handlers.ErrorTypeParsing:
+		return true, rm.calculateBackoff(attempt)
+	case handlers.ErrorTypeRateLimit:
+		return true, rm.calculateRateLimitBackoff(attempt)
+	case handlers.ErrorTypeNoHealthyEndpoints:
+		return attempt < 1, 0
+	default:
+		return false, 0
+	}
+}
+
+func TestRetryPolicy_RateLimitSuspendsOnLongDelay(t *testing.T) {
+	rm := createTestRetryManager()
+	rm.config.Retry.BaseDelay = 11 * time.Second
+	rm.config.Retry.MaxDelay = time.Minute
+
+	decision := rm.ShouldRetryWithDecision(&handlers.ErrorContext{ErrorType: handlers.ErrorTypeRateLimit}, 1, 1, false)
+	assert.True(t, decision.RetrySameEndpoint)
+	assert.True(t, decision.SuspendRequest, "限流退避超过 30s 时应尝试挂起")
+	assert.Equal(t, 33*time.Second, decision.Delay)
+}
+
+func TestRetryPolicy_StatusRulesOverrideErrorType(t *testing.T) {
+	rm := createTestRetryManager()
+	rm.config.Retry.Policy = []config.RetryRule{
+		{Match: "4xx", Action: config.RetryActionSwitch},
+		{Match: "404", Action: config.RetryActionRetry, MaxAttempts: 2, BaseDelay: time.Second},
+	}
+
+	notFound := &handlers.ErrorContext{ErrorType: handlers.ErrorTypeHTTP, StatusCode: 404}
+	decision := rm.ShouldRetryWithDecision(notFound, 1, 1, false)
+	assert.True(t, decision.RetrySameEndpoint, "精确状态码规则优先于状态码类别")
+	assert.Equal(t, time.Second, decision.Delay)
+	assert.Contains(t, decision.Reason, "HTTP 404")
+
+	decision = rm.ShouldRetryWithDecision(notFound, 2, 2, false)
+	assert.False(t, decision.RetrySameEndpoint)
+	assert.True(t, decision.SwitchEndpoint, "重试达到上限后默认切换端点")
+
+	decision = rm.ShouldRetryWithDecision(&handlers.ErrorContext{ErrorType: handlers.ErrorTypeHTTP, StatusCode: 400}, 1, 1, false)
+	assert.True(t, decision.SwitchEndpoint, "400 应匹配 4xx 规则")
+	assert.Empty(t, decision.FinalStatus)
+
+	// 未被规则覆盖的错误仍使用默认策略
+	decision = rm.ShouldRetryWithDecision(&handlers.ErrorContext{ErrorType: handlers.ErrorTypeServerError, StatusCode: 503}, 1, 1, false)
+	assert.True(t, decision.RetrySameEndpoint)
+}
+
+func TestRetryPolicy_EndpointOverridesGlobal(t *testing.T) {
+	rm := createTestRetryManager()
+	rm.config.Retry.Policy = []config.RetryRule{
+		{Match: "server_error", Action: config.RetryActionSwitch},
+	}
+	ep := rm.endpointMgr.GetEndpointByNameAny("test-endpoint-1")
+	ep.Config.RetryPolicy = []config.RetryRule{
+		{Match: "server_error", Action: config.RetryActionFail},
+	}
+
+	decision := rm.ShouldRetryWithDecision(&handlers.ErrorContext{
+		EndpointName: "test-endpoint-1",
+		ErrorType:    handlers.ErrorTypeServerError,
+		StatusCode:   502,
+	}, 1, 1, false)
+	assert.False(t, decision.RetrySameEndpoint)
+	assert.False(t, decision.SwitchEndpoint)
+	assert.Equal(t, "error", decision.FinalStatus, "端点规则优先于全局规则")
+
+	decision = rm.ShouldRetryWithDecision(&handlers.ErrorContext{
+		EndpointName: "test-endpoint-2",
+		ErrorType:    handlers.ErrorTypeServerError,
+		StatusCode:   502,
+	}, 1, 1, false)
+	assert.True(t, decision.SwitchEndpoint, "其他端点使用全局规则")
+}
+
+func TestRetryPolicy_MaxAttemptsAndThen(t *testing.T) {
+	rm := createTestRetryManager()
+	ep := rm.endpointMgr.GetEndpointByNameAny("test-endpoint-2")
+	ep.Config.RetryPolicy = []config.RetryRule{
+		{Match: "eof", Action: config.RetryActionRetry, MaxAttempts: 5, Then: config.RetryActionSuspend},
+	}
+
+	assert.Equal(t, 5, rm.GetMaxAttempts(), "最大尝试次数应覆盖策略规则的 max_attempts")
+
+	errorCtx := &handlers.ErrorContext{EndpointName: "test-endpoint-2", ErrorType: handlers.ErrorTypeEOF}
+	decision := rm.ShouldRetryWithDecision(errorCtx, 4, 4, true)
+	assert.True(t, decision.RetrySameEndpoint)
+
+	decision = rm.ShouldRetryWithDecision(errorCtx, 5, 5, true)
+	assert.False(t, decision.RetrySameEndpoint)
+	assert.True(t, decision.SuspendRequest)
+	assert.True(t, decision.SwitchEndpoint, "无法挂起时退化为切换端点")
+
+	shouldRetry, _ := rm.ShouldRetry(errorCtx, 1)
+	assert.True(t, shouldRetry, "ShouldRetry 与 ShouldRetryWithDecision 使用同一策略")
+}
+
+func TestParseHTTPStatusCode(t *testing.T) {
+	assert.Equal(t, 503, parseHTTPStatusCode(fmt.Errorf("HTTP 503: Service Unavailable")))
+	assert.Equal(t, 429, parseHTTPStatusCode(fmt.Errorf("endpoint returned error: 429")))
+	assert.Equal(t, 0, parseHTTPStatusCode(fmt.Errorf("dial tcp: connection refused on port 5030")))
+	assert.Equal(t, 0, parseHTTPStatusCode(nil))
+}
+
+func TestRetryPolicy_UpstreamRetryAfter(t *testing.T) {
+	rm := createTestRetryManager()
+	rm.config.Retry.MaxDelay = 10 * time.Second
+
+	errorCtx := &handlers.ErrorContext{
+		ErrorType:          handlers.ErrorTypeRateLimit,
+		StatusCode:         429,
+		UpstreamRetryAfter: 7 * time.Second,
+	}
+	decision := rm.ShouldRetryWithDecision(errorCtx, 1, 1, false)
+	assert.True(t, decision.RetrySameEndpoint)
+	assert.Equal(t, 7*time.Second, decision.Delay, "应按上游要求的时间等待")
+	assert.Contains(t, decision.Reason, "上游要求")
+
+	// 超过规则最大延迟（默认限流规则为 max_delay×2）时不在原地等待
+	errorCtx.UpstreamRetryAfter = 25 * time.Second
+	decision = rm.ShouldRetryWithDecision(errorCtx, 1, 1, false)
+	assert.False(t, decision.RetrySameEndpoint)
+	assert.True(t, decision.SwitchEndpoint)
+
+	// 不重试的规则不受影响
+	decision = rm.ShouldRetryWithDecision(&handlers.ErrorContext{
+		ErrorType:          handlers.ErrorTypeHTTP,
+		StatusCode:         400,
+		UpstreamRetryAfter: time.Second,
+	}, 1, 1, false)
+	assert.Equal(t, "error", decision.FinalStatus)
+}
@@ -42,12 +42,7 @@ func (h *Handler) handleSSERequest(w http.ResponseWriter, r *http.Request, bodyB
 
 	// Get healthy endpoints with fast testing if enabled
 	ctx := r.Context()
-	var endpoints []*endpoint.Endpoint
-	if h.endpointManager.GetConfig().Strategy.Type == "fastest" && h.endpointManager.GetConfig().Strategy.FastTestEnabled {
-		endpoints = h.endpointManager.GetFastestEndpointsWithRealTimeTest(ctx)
-	} else {
-		endpoints = h.endpointManager.GetHealthyEndpoints()
-	}
+	endpoints := h.endpointManager.GetEndpointsForRequest(ctx)
 	
 	if len(endpoints) == 0 {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -107,33 +102,12 @@ func (h *Handler) streamFromEndpoint(ctx context.Context, w http.ResponseWriter,
 	// Copy headers
 	h.forwarder.CopyHeaders(r, req, ep)
 
-	// Create HTTP client optimized for real-time streaming with proxy support
-	httpTransport, err := transport.CreateTransport(h.config)
+	// Reuse the endpoint's pooled streaming transport (keeps connections alive)
+	httpTransport, err := h.forwarder.RoundTripper(ep, transport.ProfileStreaming)
 	if err != nil {
 		return fmt.Errorf("failed to create transport: %w", err)
 	}
-	
-	// Optimize transport for streaming
-	httpTransport.DisableKeepAlives = false
-	httpTransport.MaxIdleConns = 10
-	httpTransport.MaxIdleConnsPerHost = 2
-	httpTransport.IdleConnTimeout = 0 // No idle timeout
-	httpTransport.TLSHandshakeTimeout = 10 * time.Second
-	httpTransport.ExpectContinueTimeout = 1 * time.Second
-
-	// 从配置中读取响应头超时时间，默认60秒
-	responseHeaderTimeout := h.config.Streaming.ResponseHeaderTimeout
-	if responseHeaderTimeout == 0 {
-		responseHeaderTimeout = 60 * time.Second
-	}
-	httpTransport.ResponseHeaderTimeout = responseHeaderTimeout
 
-	// Critical: Disable compression to prevent buffering delays
-	httpTransport.DisableCompression = true
-	// Set smaller buffer sizes for lower latency
-	httpTransport.WriteBufferSize = 4096 // Smaller write buffer
-	httpTransport.ReadBufferSize = 4096  // Smaller read buffer
-	
 	client := &http.Client{
 		Timeout:   0, // No timeout for streaming
 		Transport: httpTransport,
@@ -45,10 +45,13 @@ type StreamProcessor struct {
 	bytesProcessed int64     // 已处理字节数
 	lineBuffer     []byte    // SSE行缓冲区
 	partialData    []byte    // 部分数据缓冲区，用于错误恢复
+	firstByteAt    time.Time // 首个数据块转发给客户端的时间
+	firstContentAt time.Time // 首个内容增量（content_block_delta）到达的时间，受 parseMutex 保护
 
 	// 并发控制
 	parseWg    sync.WaitGroup // 等待组，确保后台解析完成
 	parseMutex sync.Mutex     // 解析互斥锁，保护共享状态
+	parseTail  chan struct{}  // 上一个后台解析任务的完成信号，保证数据块按到达顺序解析
 
 	// 错误处理
 	parseErrors    []error // 解析过程中的错误集合
@@ -129,6 +132,7 @@ func (sp *StreamProcessor) ProcessStream(ctx context.Context, resp *http.Respons
 
 			// 保存部分数据用于错误恢复
 			sp.savePartialData(chunk)
+			receivedAt := time.Now()
 
 			// 2. 立即转发到客户端 - 这是关键！不等待完整响应
 			if writeErr := sp.forwardToClient(chunk); writeErr != nil {
@@ -138,9 +142,12 @@ func (sp *StreamProcessor) ProcessStream(ctx context.Context, resp *http.Respons
 				slog.Error(fmt.Sprintf("❌ [流式错误] [%s] 转发到客户端失败: %v", sp.requestID, writeErr))
 				return nil, fmt.Errorf("failed to forward to client: %w", writeErr)
 			}
+			if sp.firstByteAt.IsZero() {
+				sp.firstByteAt = time.Now()
+			}
 
 			// 3. 并行解析Token信息 - 不影响转发性能
-			sp.parseTokensInBackground(chunk)
+			sp.parseTokensInBackground(chunk, receivedAt)
 
 			// 4. 更新处理状态
 			sp.bytesProcessed += int64(n)
@@ -181,16 +188,26 @@ func (sp *StreamProcessor) forwardToClient(data []byte) error {
 
 // parseTokensInBackground 并发Token解析，不阻塞主流
 // 这个方法在后台goroutine中解析SSE事件，提取模型信息和Token使用统计
-func (sp *StreamProcessor) parseTokensInBackground(data []byte) {
+// receivedAt 为数据块从端点读到的时间，用于统计首个内容增量的到达时间
+func (sp *StreamProcessor) parseTokensInBackground(data []byte, receivedAt time.Time) {
 	// 为每个数据块启动一个后台goroutine
+	// 数据块连续到达时多个goroutine可能同时就绪，需等待上一个数据块解析完成，
+	// 否则跨数据块的SSE行会被乱序拼接
 	sp.parseWg.Add(1)
+	prev := sp.parseTail
+	done := make(chan struct{})
+	sp.parseTail = done
+
+	// 创建后台处理缓冲区（在启动goroutine前复制，data 在下一次读取时会被覆盖）
+	parseBuffer := make([]byte, len(data))
+	copy(parseBuffer, data)
 
 	go func() {
 		defer sp.parseWg.Done()
-
-		// 创建后台处理缓冲区
-		parseBuffer := make([]byte, len(data))
-		copy(parseBuffer, data)
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
 
 		// 逐字节处理，构建SSE行
 		sp.parseMutex.Lock()
@@ -203,6 +220,7 @@ func (sp *StreamProcessor) parseTokensInBackground(data []byte) {
 			// 检测换行符，处理完整的SSE行
 			if b == '\n' {
 				line := strings.TrimSpace(string(sp.lineBuffer))
+				sp.markFirstContent(line, receivedAt)
 
 				// ✅ 修复：处理所有行，包括空行（空行触发SSE事件解析）
 				sp.processSSELine(line)
@@ -214,6 +232,24 @@ func (sp *StreamProcessor) parseTokensInBackground(data []byte) {
 	}()
 }
 
+// markFirstContent 记录首个内容增量的到达时间（调用方需持有 parseMutex）
+// 后台解析可能乱序执行，因此取所有内容增量中最早的到达时间
+func (sp *StreamProcessor) markFirstContent(line string, receivedAt time.Time) {
+	if !strings.HasPrefix(line, "data:") || !strings.Contains(line, `"content_block_delta"`) {
+		return
+	}
+	if sp.firstContentAt.IsZero() || receivedAt.Before(sp.firstContentAt) {
+		sp.firstContentAt = receivedAt
+	}
+}
+
+// GetTimings 获取首字节和首个内容增量的时间，未出现时为零值
+func (sp *StreamProcessor) GetTimings() (firstByte, firstContent time.Time) {
+	sp.parseMutex.Lock()
+	defer sp.parseMutex.Unlock()
+	return sp.firstByteAt, sp.firstContentAt
+}
+
 // processSSELine 处理单个SSE行
 // 修改版本：仅进行 Token 解析，不再直接记录到 usageTracker
 func (sp *StreamProcessor) processSSELine(line string) {
@@ -515,8 +551,11 @@ func (sp *StreamProcessor) Reset() {
 	sp.startTime = time.Now()
 	sp.bytesProcessed = 0
 	sp.lineBuffer = sp.lineBuffer[:0]
+	sp.parseTail = nil
 	sp.partialData = sp.partialData[:0] // 重置部分数据缓冲区
 	sp.parseErrors = sp.parseErrors[:0]
+	sp.firstByteAt = time.Time{}
+	sp.firstContentAt = time.Time{}
 
 	// 重置TokenParser状态
 	if sp.tokenParser != nil {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 // mockResponseWriter 实现 http.ResponseWriter 和 http.Flusher
@@ -106,6 +107,41 @@ func TestStreamProcessor_ProcessStream_SimpleData(t *testing.T) {
 	}
 }
 
+func TestStreamProcessor_Timings(t *testing.T) {
+	pr, pw := io.Pipe()
+	resp := &http.Response{StatusCode: 200, Header: make(http.Header), Body: pr}
+
+	go func() {
+		io.WriteString(pw, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"model\":\"claude-sonnet-4-5\"}}\n\n")
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(pw, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n")
+		pw.Close()
+	}()
+
+	writer := &mockResponseWriter{}
+	processor := NewStreamProcessor(NewTokenParser(), nil, writer, writer, "test-timings", "endpoint")
+	if _, err := processor.ProcessStream(context.Background(), resp); err != nil {
+		t.Fatalf("ProcessStream failed: %v", err)
+	}
+
+	firstByte, firstContent := processor.GetTimings()
+	if firstByte.IsZero() || firstContent.IsZero() {
+		t.Fatalf("首字节和首个内容增量时间都应被记录: %v / %v", firstByte, firstContent)
+	}
+	if gap := firstContent.Sub(firstByte); gap < 40*time.Millisecond {
+		t.Errorf("首个内容增量应晚于首字节约 50ms，实际间隔 %v", gap)
+	}
+
+	// 没有内容增量的流只记录首字节
+	processor = NewStreamProcessor(NewTokenParser(), nil, writer, writer, "test-no-content", "endpoint")
+	if _, err := processor.ProcessStream(context.Background(), mockResponse("event: ping\ndata: {\"type\":\"ping\"}\n\n", 200)); err != nil {
+		t.Fatalf("ProcessStream failed: %v", err)
+	}
+	if firstByte, firstContent := processor.GetTimings(); firstByte.IsZero() || !firstContent.IsZero() {
+		t.Errorf("无内容增量时应只有首字节时间: %v / %v", firstByte, firstContent)
+	}
+}
+
 func TestStreamProcessor_GetProcessingStats(t *testing.T) {
 	// 创建处理器
 	tokenParser := NewTokenParser()
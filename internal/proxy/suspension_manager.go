@@ -8,9 +8,14 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"cc-forwarder/config"
 	"cc-forwarder/internal/endpoint"
+	"cc-forwarder/internal/events"
 	"cc-forwarder/internal/proxy/handlers" // 🎯 [挂起取消区分] 新增handlers包导入
+	"cc-forwarder/internal/tracing"
 )
 
 // SuspensionManager 管理请求挂起逻辑
@@ -20,6 +25,7 @@ type SuspensionManager struct {
 	endpointManager *endpoint.Manager
 	groupManager    *endpoint.GroupManager
 	recoverySignalManager *EndpointRecoverySignalManager // 端点恢复信号管理器
+	eventBus              events.EventBus                // 发布挂起超时事件
 
 	// 挂起请求计数相关字段
 	suspendedRequestsMutex sync.RWMutex
@@ -45,6 +51,31 @@ func NewSuspensionManagerWithRecoverySignal(cfg *config.Config, endpointManager
 	}
 }
 
+// SetEventBus 设置事件总线，挂起等待超时时发布 request_suspend_timeout 事件
+func (sm *SuspensionManager) SetEventBus(eventBus events.EventBus) {
+	sm.eventBus = eventBus
+}
+
+// publishSuspendTimeout 发布挂起请求等待超时事件
+func (sm *SuspensionManager) publishSuspendTimeout(connID, reason, failedEndpoint string, timeout time.Duration) {
+	if sm.eventBus == nil {
+		return
+	}
+
+	sm.eventBus.Publish(events.Event{
+		Type:     events.EventRequestSuspendTimeout,
+		Source:   "suspension_manager",
+		Priority: events.PriorityHigh,
+		Data: map[string]interface{}{
+			"request_id":      connID,
+			"reason":          reason,
+			"endpoint":        failedEndpoint,
+			"timeout_seconds": int64(timeout.Seconds()),
+			"change_type":     "suspend_timeout",
+		},
+	})
+}
+
 // ShouldSuspend 判断是否应该挂起请求
 // 迁移自 RetryHandler.shouldSuspendRequest，但专注于挂起逻辑判断
 // 条件：手动模式 + 有备用组 + 功能启用 + 未达到最大挂起数
@@ -201,6 +232,7 @@ func (sm *SuspensionManager) WaitForGroupSwitch(ctx context.Context, connID stri
 		// 挂起超时
 		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
 			slog.WarnContext(ctx, fmt.Sprintf("⏰ [挂起超时] 连接 %s 挂起等待超时 (%v)，停止等待", connID, timeout))
+			sm.publishSuspendTimeout(connID, "group_switch", "", timeout)
 		} else {
 			slog.InfoContext(ctx, fmt.Sprintf("🔄 [上下文取消] 连接 %s 挂起期间上下文被取消", connID))
 		}
@@ -333,6 +365,7 @@ func (sm *SuspensionManager) WaitForEndpointRecovery(ctx context.Context, connID
 			// ⏰ [优先级3] 挂起超时
 			if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
 				slog.WarnContext(ctx, fmt.Sprintf("⏰ [挂起超时] 连接 %s 挂起等待超时 (%v)，停止等待", connID, timeout))
+				sm.publishSuspendTimeout(connID, "endpoint_recovery", failedEndpoint, timeout)
 			} else {
 				slog.InfoContext(ctx, fmt.Sprintf("🔄 [上下文取消] 连接 %s 挂起期间上下文被取消", connID))
 			}
@@ -356,7 +389,7 @@ func (sm *SuspensionManager) WaitForEndpointRecovery(ctx context.Context, connID
 // WaitForEndpointRecoveryWithResult 🎯 [挂起取消区分] 带结果的端点恢复等待方法
 // 功能与WaitForEndpointRecovery相同，但返回详细的结果类型以区分成功、超时、取消
 // 这是对现有方法的增强版本，保持向后兼容性
-func (sm *SuspensionManager) WaitForEndpointRecoveryWithResult(ctx context.Context, connID, failedEndpoint string) handlers.SuspensionResult {
+func (sm *SuspensionManager) WaitForEndpointRecoveryWithResult(ctx context.Context, connID, failedEndpoint string) (result handlers.SuspensionResult) {
 	// 检查配置和管理器是否存在
 	if sm.config == nil {
 		slog.InfoContext(ctx, "🔍 [端点恢复等待] 配置为空，无法挂起请求")
@@ -389,6 +422,17 @@ func (sm *SuspensionManager) WaitForEndpointRecoveryWithResult(ctx context.Conte
 	slog.InfoContext(ctx, fmt.Sprintf("⏸️ [端点恢复挂起] 连接 %s 请求已挂起，等待端点 %s 恢复或组切换 (当前挂起数: %d)",
 		connID, failedEndpoint, currentCount))
 
+	// 链路追踪：挂起等待作为请求的子 span，记录等待结果
+	ctx, span := tracing.Tracer().Start(ctx, "proxy.suspend",
+		trace.WithAttributes(
+			attribute.String("endpoint", failedEndpoint),
+			attribute.Int("suspended_requests", currentCount),
+		))
+	defer func() {
+		span.SetAttributes(attribute.String("suspension.result", result.String()))
+		span.End()
+	}()
+
 	// 创建超时context
 	timeout := sm.config.RequestSuspend.Timeout
 	if timeout <= 0 {
@@ -450,6 +494,7 @@ func (sm *SuspensionManager) WaitForEndpointRecoveryWithResult(ctx context.Conte
 			// ⏰ [优先级3] 挂起超时
 			if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
 				slog.WarnContext(ctx, fmt.Sprintf("⏰ [挂起超时] 连接 %s 挂起等待超时 (%v)，停止等待", connID, timeout))
+				sm.publishSuspendTimeout(connID, "endpoint_recovery", failedEndpoint, timeout)
 			} else {
 				slog.InfoContext(ctx, fmt.Sprintf("🔄 [上下文取消] 连接 %s 挂起期间超时上下文被取消", connID))
 			}
@@ -471,3 +516,90 @@ func (sm *SuspensionManager) WaitForEndpointRecoveryWithResult(ctx context.Conte
 		}
 	}
 }
+
+// WaitForEndpointCapacity 所有可用端点都达到并发或 RPM/TPM 上限时挂起请求，等待容量释放
+// 不依赖 request_suspend.enabled（端点容量上限本身就是排队的开关），但共用挂起数上限和超时时间
+// 在途请求结束时立即重新检查，RPM/TPM 窗口滑动则按秒轮询
+func (sm *SuspensionManager) WaitForEndpointCapacity(ctx context.Context, connID string) (result handlers.SuspensionResult) {
+	if sm.config == nil || sm.endpointManager == nil {
+		slog.InfoContext(ctx, "🔍 [容量等待] 配置或端点管理器为空，无法挂起请求")
+		return handlers.SuspensionTimeout
+	}
+
+	// 增加挂起请求计数（与其他挂起共用最大挂起数）
+	sm.suspendedRequestsMutex.Lock()
+	if limit := sm.config.RequestSuspend.MaxSuspendedRequests; limit > 0 && sm.suspendedRequestsCount >= limit {
+		sm.suspendedRequestsMutex.Unlock()
+		slog.WarnContext(ctx, fmt.Sprintf("🚫 [挂起限制] 当前挂起请求数已达到最大限制 %d，连接 %s 不再排队等待端点容量", limit, connID))
+		return handlers.SuspensionTimeout
+	}
+	sm.suspendedRequestsCount++
+	currentCount := sm.suspendedRequestsCount
+	sm.suspendedRequestsMutex.Unlock()
+
+	defer func() {
+		sm.suspendedRequestsMutex.Lock()
+		sm.suspendedRequestsCount--
+		newCount := sm.suspendedRequestsCount
+		sm.suspendedRequestsMutex.Unlock()
+		slog.InfoContext(ctx, fmt.Sprintf("⬇️ [挂起结束] 连接 %s 请求挂起结束，当前挂起数: %d", connID, newCount))
+	}()
+
+	slog.InfoContext(ctx, fmt.Sprintf("⏸️ [容量等待] 连接 %s 所有可用端点已达到并发或 RPM/TPM 上限，排队等待 (当前挂起数: %d)",
+		connID, currentCount))
+
+	// 链路追踪：排队等待作为请求的子 span，记录等待结果
+	ctx, span := tracing.Tracer().Start(ctx, "proxy.suspend",
+		trace.WithAttributes(
+			attribute.String("suspension.reason", "endpoint_capacity"),
+			attribute.Int("suspended_requests", currentCount),
+		))
+	defer func() {
+		span.SetAttributes(attribute.String("suspension.result", result.String()))
+		span.End()
+	}()
+
+	timeout := sm.config.RequestSuspend.Timeout
+	if timeout <= 0 {
+		timeout = 300 * time.Second // 默认5分钟
+	}
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
+	defer timeoutCancel()
+
+	capacityCh := sm.endpointManager.SubscribeCapacity()
+	defer sm.endpointManager.UnsubscribeCapacity(capacityCh)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		woken := false
+		select {
+		case <-capacityCh:
+			woken = true
+		case <-ticker.C:
+		case <-timeoutCtx.Done():
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, fmt.Sprintf("⏰ [容量等待] 连接 %s 等待端点容量超时 (%v)", connID, timeout))
+				sm.publishSuspendTimeout(connID, "endpoint_capacity", "", timeout)
+				return handlers.SuspensionTimeout
+			}
+			if errors.Is(ctx.Err(), context.Canceled) {
+				slog.InfoContext(ctx, fmt.Sprintf("❌ [请求取消] 连接 %s 原始请求被客户端取消，结束排队", connID))
+				return handlers.SuspensionCancelled
+			}
+			slog.InfoContext(ctx, fmt.Sprintf("⏰ [请求超时] 连接 %s 原始请求上下文超时，结束排队", connID))
+			return handlers.SuspensionTimeout
+		}
+
+		if endpoints := sm.endpointManager.GetEndpointsForRequest(ctx); len(endpoints) > 0 {
+			slog.InfoContext(ctx, fmt.Sprintf("✅ [容量等待] 连接 %s 端点 %s 已有空闲容量，恢复请求处理",
+				connID, endpoints[0].Config.Name))
+			return handlers.SuspensionSuccess
+		}
+		// 释放的名额对本请求不可用（如端点不匹配模型），转交给下一个排队的请求
+		if woken {
+			sm.endpointManager.PassCapacity(capacityCh)
+		}
+	}
+}
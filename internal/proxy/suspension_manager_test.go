@@ -7,6 +7,7 @@ import (
 
 	"cc-forwarder/config"
 	"cc-forwarder/internal/endpoint"
+	"cc-forwarder/internal/proxy/handlers"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -422,6 +423,57 @@ func TestSuspensionManager_EdgeCases(t *testing.T) {
 	})
 }
 
+// TestSuspensionManager_WaitForEndpointCapacity 测试端点容量已满时的排队等待
+func TestSuspensionManager_WaitForEndpointCapacity(t *testing.T) {
+	cfg := &config.Config{
+		RequestSuspend: config.RequestSuspendConfig{
+			Timeout:              200 * time.Millisecond,
+			MaxSuspendedRequests: 100,
+		},
+		Endpoints: []config.EndpointConfig{
+			{Name: "limited", URL: "http://limited.example.com", Priority: 1, MaxConcurrency: 1},
+		},
+	}
+	endpointMgr := endpoint.NewManager(cfg)
+	ep := endpointMgr.GetEndpointByNameAny("limited")
+	ep.Status.Healthy = true
+	endpointMgr.GetGroupManager().UpdateGroups(endpointMgr.GetAllEndpoints())
+	require.NoError(t, endpointMgr.GetGroupManager().ManualActivateGroup("limited"))
+	sm := NewSuspensionManager(cfg, endpointMgr, endpointMgr.GetGroupManager())
+
+	require.True(t, endpointMgr.TryAcquireCapacity(ep))
+	require.True(t, endpointMgr.HasSaturatedEndpoints(context.Background()))
+
+	t.Run("容量释放后恢复", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			endpointMgr.ReleaseCapacity(ep)
+		}()
+		start := time.Now()
+		result := sm.WaitForEndpointCapacity(context.Background(), "capacity-success")
+		assert.Equal(t, handlers.SuspensionSuccess, result)
+		assert.Less(t, time.Since(start), 150*time.Millisecond, "归还名额时应立即恢复，不等待轮询")
+		assert.Equal(t, 0, sm.GetSuspendedRequestsCount())
+	})
+
+	require.True(t, endpointMgr.TryAcquireCapacity(ep))
+
+	t.Run("等待超时", func(t *testing.T) {
+		result := sm.WaitForEndpointCapacity(context.Background(), "capacity-timeout")
+		assert.Equal(t, handlers.SuspensionTimeout, result)
+	})
+
+	t.Run("客户端取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+		result := sm.WaitForEndpointCapacity(ctx, "capacity-cancel")
+		assert.Equal(t, handlers.SuspensionCancelled, result)
+	})
+}
+
 // BenchmarkSuspensionManager_ShouldSuspend 性能测试
 func BenchmarkSuspensionManager_ShouldSuspend(b *testing.B) {
 	sm := createTestSuspensionManager(nil)
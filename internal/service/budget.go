@@ -0,0 +1,591 @@
+// Package service 提供业务逻辑层实现
+// 预算服务 - 按周期统计消费，超过软限额发送事件，超过硬限额拒绝请求
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/events"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/tracking"
+)
+
+// 预算状态
+const (
+	BudgetStateOK           = "ok"
+	BudgetStateSoftExceeded = "soft_exceeded"
+	BudgetStateHardExceeded = "hard_exceeded"
+)
+
+// defaultBudgetRefreshInterval 默认消费刷新间隔
+const defaultBudgetRefreshInterval = 10 * time.Second
+
+// SpendQuerier 消费查询接口（由 tracking.UsageTracker 实现）
+type SpendQuerier interface {
+	QuerySpend(ctx context.Context, opts *tracking.QueryOptions) (*tracking.SpendSummary, error)
+}
+
+// BudgetStatus 预算在当前周期的消费状态
+type BudgetStatus struct {
+	Name   string `json:"name"`
+	Scope  string `json:"scope"`
+	Target string `json:"target"`
+	Period string `json:"period"`
+
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+
+	SpentUSD     float64 `json:"spent_usd"`
+	SpentTokens  int64   `json:"spent_tokens"`
+	RequestCount int64   `json:"request_count"`
+
+	SoftLimitUSD    float64 `json:"soft_limit_usd"`
+	SoftLimitTokens int64   `json:"soft_limit_tokens"`
+	HardLimitUSD    float64 `json:"hard_limit_usd"`
+	HardLimitTokens int64   `json:"hard_limit_tokens"`
+
+	State     string    `json:"state"` // ok/soft_exceeded/hard_exceeded
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Message 返回超出限额的说明（用于拒绝请求时的错误信息）
+func (st *BudgetStatus) Message() string {
+	scope := st.Scope
+	if st.Target != "" {
+		scope += " " + st.Target
+	}
+	if st.HardLimitUSD > 0 && st.SpentUSD >= st.HardLimitUSD {
+		return fmt.Sprintf("Budget '%s' (%s) exceeded: $%.4f of $%.4f spent this %s period, resets at %s",
+			st.Name, scope, st.SpentUSD, st.HardLimitUSD, st.Period, st.PeriodEnd.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Budget '%s' (%s) exceeded: %d of %d tokens used this %s period, resets at %s",
+		st.Name, scope, st.SpentTokens, st.HardLimitTokens, st.Period, st.PeriodEnd.Format(time.RFC3339))
+}
+
+// BudgetService 预算管理与执行服务
+// 后台定期汇总各预算当前周期的消费（数据库 + 热池），请求路径只读取缓存状态
+type BudgetService struct {
+	store    store.BudgetStore
+	spend    SpendQuerier
+	eventBus events.EventBus
+	location *time.Location
+
+	// 预算缓存与当前状态
+	budgets  []*store.BudgetRecord
+	statuses map[string]*BudgetStatus
+	mu       sync.RWMutex
+
+	// 串行化刷新：定时刷新与手动刷新并发时，避免较早的计算结果覆盖较新的结果
+	refreshMu sync.Mutex
+
+	// 运行配置
+	enabled         bool
+	refreshInterval time.Duration
+	configMu        sync.RWMutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewBudgetService 创建预算服务实例
+func NewBudgetService(st store.BudgetStore, spend SpendQuerier) *BudgetService {
+	return &BudgetService{
+		store:           st,
+		spend:           spend,
+		location:        time.Local,
+		statuses:        make(map[string]*BudgetStatus),
+		enabled:         true,
+		refreshInterval: defaultBudgetRefreshInterval,
+	}
+}
+
+// SetEventBus 设置事件总线（用于发布超限事件）
+func (s *BudgetService) SetEventBus(eventBus events.EventBus) {
+	s.eventBus = eventBus
+}
+
+// SetLocation 设置计算周期边界使用的时区
+func (s *BudgetService) SetLocation(loc *time.Location) {
+	if loc != nil {
+		s.location = loc
+	}
+}
+
+// Configure 更新运行配置（启用状态和刷新间隔）
+func (s *BudgetService) Configure(enabled bool, refreshInterval time.Duration) {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultBudgetRefreshInterval
+	}
+
+	s.configMu.Lock()
+	s.enabled = enabled
+	s.refreshInterval = refreshInterval
+	s.configMu.Unlock()
+}
+
+// IsEnabled 预算执行是否启用
+func (s *BudgetService) IsEnabled() bool {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.enabled
+}
+
+// Start 启动后台刷新
+func (s *BudgetService) Start() {
+	if s.stopCh != nil {
+		return
+	}
+	s.stopCh = make(chan struct{})
+
+	s.wg.Add(1)
+	go s.refreshLoop(s.stopCh)
+}
+
+// Stop 停止后台刷新
+func (s *BudgetService) Stop() {
+	if s.stopCh == nil {
+		return
+	}
+	close(s.stopCh)
+	s.wg.Wait()
+	s.stopCh = nil
+}
+
+// refreshLoop 按配置间隔刷新消费状态
+func (s *BudgetService) refreshLoop(stopCh chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		s.configMu.RLock()
+		interval := s.refreshInterval
+		s.configMu.RUnlock()
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(interval):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		s.Refresh(ctx)
+		cancel()
+	}
+}
+
+// ============================================================
+// 请求执行检查
+// ============================================================
+
+// CheckRequest 检查全局和客户端 Key 预算，超过硬限额时返回对应状态
+func (s *BudgetService) CheckRequest(clientKey string) *BudgetStatus {
+	if !s.IsEnabled() {
+		return nil
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, st := range s.statuses {
+		if !st.blocking(now) {
+			continue
+		}
+		switch st.Scope {
+		case store.BudgetScopeGlobal:
+			return st.clone()
+		case store.BudgetScopeClientKey:
+			if clientKey != "" && st.Target == clientKey {
+				return st.clone()
+			}
+		}
+	}
+	return nil
+}
+
+// RequestRejectReason 返回全局/客户端 Key 预算的拒绝原因，未超限时返回空字符串（实现 proxy.BudgetGuard）
+func (s *BudgetService) RequestRejectReason(clientKey string) string {
+	if st := s.CheckRequest(clientKey); st != nil {
+		return st.Message()
+	}
+	return ""
+}
+
+// EndpointRejectReason 返回任一超过硬限额的端点/渠道预算说明（无可用端点时作为拒绝原因）
+func (s *BudgetService) EndpointRejectReason() string {
+	if !s.IsEnabled() {
+		return ""
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, st := range s.statuses {
+		if st.blocking(now) && (st.Scope == store.BudgetScopeEndpoint || st.Scope == store.BudgetScopeChannel) {
+			return st.Message()
+		}
+	}
+	return ""
+}
+
+// AllowEndpoint 检查端点和渠道预算，超过硬限额的端点在选择时跳过（实现 endpoint.EndpointGate）
+func (s *BudgetService) AllowEndpoint(name, channel string) bool {
+	if !s.IsEnabled() {
+		return true
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, st := range s.statuses {
+		if !st.blocking(now) {
+			continue
+		}
+		if (st.Scope == store.BudgetScopeEndpoint && st.Target == name) ||
+			(st.Scope == store.BudgetScopeChannel && channel != "" && st.Target == channel) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStatuses 获取所有预算的当前状态
+func (s *BudgetService) GetStatuses() []*BudgetStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*BudgetStatus, 0, len(s.budgets))
+	for _, b := range s.budgets {
+		if st, ok := s.statuses[b.Name]; ok {
+			result = append(result, st.clone())
+		}
+	}
+	return result
+}
+
+// GetStatus 获取单个预算的当前状态
+func (s *BudgetService) GetStatus(name string) *BudgetStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if st, ok := s.statuses[name]; ok {
+		return st.clone()
+	}
+	return nil
+}
+
+// ============================================================
+// 消费刷新
+// ============================================================
+
+// Refresh 重新汇总所有启用预算在当前周期的消费
+// 状态升级（进入软/硬超限）时发布事件，每个周期每种状态只发布一次
+// 并发调用时串行执行，计算和替换状态在同一把锁内完成
+func (s *BudgetService) Refresh(ctx context.Context) {
+	if s.spend == nil {
+		return
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	s.mu.RLock()
+	budgets := s.budgets
+	previous := s.statuses
+	s.mu.RUnlock()
+
+	now := time.Now().In(s.location)
+	statuses := make(map[string]*BudgetStatus, len(budgets))
+
+	for _, b := range budgets {
+		if !b.Enabled {
+			continue
+		}
+
+		start, end := budgetPeriodBounds(b.Period, now)
+		opts := &tracking.QueryOptions{StartDate: &start}
+		switch b.Scope {
+		case store.BudgetScopeEndpoint:
+			opts.EndpointName = b.Target
+		case store.BudgetScopeChannel:
+			opts.Channel = b.Target
+		case store.BudgetScopeClientKey:
+			opts.ClientKeyName = b.Target
+		}
+
+		spend, err := s.spend.QuerySpend(ctx, opts)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("⚠️ [预算] 统计消费失败: %s - %v", b.Name, err))
+			// 保留上一次的状态，避免查询失败时误放行
+			if prev, ok := previous[b.Name]; ok {
+				statuses[b.Name] = prev
+			}
+			continue
+		}
+
+		st := &BudgetStatus{
+			Name:            b.Name,
+			Scope:           b.Scope,
+			Target:          b.Target,
+			Period:          b.Period,
+			PeriodStart:     start,
+			PeriodEnd:       end,
+			SpentUSD:        spend.TotalCostUSD,
+			SpentTokens:     spend.TotalTokens,
+			RequestCount:    spend.RequestCount,
+			SoftLimitUSD:    b.SoftLimitUSD,
+			SoftLimitTokens: b.SoftLimitTokens,
+			HardLimitUSD:    b.HardLimitUSD,
+			HardLimitTokens: b.HardLimitTokens,
+			CheckedAt:       now,
+		}
+		st.State = evaluateBudgetState(b, spend)
+		statuses[b.Name] = st
+
+		// 同一周期内状态升级时发布事件
+		prevState := BudgetStateOK
+		if prev, ok := previous[b.Name]; ok && prev.PeriodStart.Equal(start) {
+			prevState = prev.State
+		}
+		if budgetStateRank(st.State) > budgetStateRank(prevState) {
+			s.publishExceeded(st)
+		}
+	}
+
+	s.mu.Lock()
+	s.statuses = statuses
+	s.mu.Unlock()
+}
+
+// publishExceeded 发布预算超限事件
+func (s *BudgetService) publishExceeded(st *BudgetStatus) {
+	eventType := events.EventBudgetSoftLimitExceeded
+	priority := events.PriorityHigh
+	if st.State == BudgetStateHardExceeded {
+		eventType = events.EventBudgetHardLimitExceeded
+		priority = events.PriorityCritical
+		slog.Warn(fmt.Sprintf("🚫 [预算] 超过硬限额，将拒绝新请求: %s (%s %s) $%.4f / %d tokens",
+			st.Name, st.Scope, st.Target, st.SpentUSD, st.SpentTokens))
+	} else {
+		slog.Warn(fmt.Sprintf("⚠️ [预算] 超过软限额: %s (%s %s) $%.4f / %d tokens",
+			st.Name, st.Scope, st.Target, st.SpentUSD, st.SpentTokens))
+	}
+
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(events.Event{
+		Type:     eventType,
+		Source:   "budget_service",
+		Priority: priority,
+		Data: map[string]interface{}{
+			"name":              st.Name,
+			"scope":             st.Scope,
+			"target":            st.Target,
+			"period":            st.Period,
+			"period_start":      st.PeriodStart.Format(time.RFC3339),
+			"period_end":        st.PeriodEnd.Format(time.RFC3339),
+			"spent_usd":         st.SpentUSD,
+			"spent_tokens":      st.SpentTokens,
+			"soft_limit_usd":    st.SoftLimitUSD,
+			"soft_limit_tokens": st.SoftLimitTokens,
+			"hard_limit_usd":    st.HardLimitUSD,
+			"hard_limit_tokens": st.HardLimitTokens,
+			"state":             st.State,
+		},
+	})
+}
+
+// ============================================================
+// 预算管理
+// ============================================================
+
+// LoadCache 从数据库加载预算到缓存
+func (s *BudgetService) LoadCache(ctx context.Context) error {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("加载预算失败: %w", err)
+	}
+
+	s.mu.Lock()
+	s.budgets = records
+	s.mu.Unlock()
+
+	slog.Info(fmt.Sprintf("✅ [BudgetService] 加载 %d 个预算到缓存", len(records)))
+	return nil
+}
+
+// CreateBudget 创建预算
+func (s *BudgetService) CreateBudget(ctx context.Context, record *store.BudgetRecord) (*store.BudgetRecord, error) {
+	if err := s.validateRecord(record); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.Get(ctx, record.Name)
+	if err != nil {
+		return nil, fmt.Errorf("检查预算是否存在失败: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("预算 '%s' %w", record.Name, store.ErrAlreadyExists)
+	}
+
+	created, err := s.store.Create(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [BudgetService] 创建预算: %s", created.Name))
+	return created, nil
+}
+
+// GetBudget 获取预算
+func (s *BudgetService) GetBudget(ctx context.Context, name string) (*store.BudgetRecord, error) {
+	record, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取预算失败: %w", err)
+	}
+	return record, nil
+}
+
+// ListBudgets 列出所有预算
+func (s *BudgetService) ListBudgets(ctx context.Context) ([]*store.BudgetRecord, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("列出预算失败: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateBudget 更新预算
+func (s *BudgetService) UpdateBudget(ctx context.Context, record *store.BudgetRecord) error {
+	if err := s.validateRecord(record); err != nil {
+		return err
+	}
+
+	if err := s.store.Update(ctx, record); err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [BudgetService] 更新预算: %s", record.Name))
+	return nil
+}
+
+// DeleteBudget 删除预算
+func (s *BudgetService) DeleteBudget(ctx context.Context, name string) error {
+	if err := s.store.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [BudgetService] 删除预算: %s", name))
+	return nil
+}
+
+// reload 重新加载缓存并立即刷新状态，使修改后的限额即时生效
+func (s *BudgetService) reload(ctx context.Context) {
+	if err := s.LoadCache(ctx); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [BudgetService] 重新加载预算失败: %v", err))
+		return
+	}
+	s.Refresh(ctx)
+}
+
+// validateRecord 验证预算记录
+func (s *BudgetService) validateRecord(record *store.BudgetRecord) error {
+	if record.Name == "" {
+		return fmt.Errorf("预算名称不能为空")
+	}
+
+	switch record.Scope {
+	case store.BudgetScopeGlobal:
+		record.Target = ""
+	case store.BudgetScopeEndpoint, store.BudgetScopeChannel, store.BudgetScopeClientKey:
+		if record.Target == "" {
+			return fmt.Errorf("作用范围为 %s 时必须指定目标名称", record.Scope)
+		}
+	default:
+		return fmt.Errorf("无效的作用范围: %s (可选: global, endpoint, channel, client_key)", record.Scope)
+	}
+
+	switch record.Period {
+	case store.BudgetPeriodDaily, store.BudgetPeriodWeekly, store.BudgetPeriodMonthly:
+	default:
+		return fmt.Errorf("无效的预算周期: %s (可选: daily, weekly, monthly)", record.Period)
+	}
+
+	if record.SoftLimitUSD < 0 || record.HardLimitUSD < 0 || record.SoftLimitTokens < 0 || record.HardLimitTokens < 0 {
+		return fmt.Errorf("限额不能为负数")
+	}
+	if record.SoftLimitUSD == 0 && record.HardLimitUSD == 0 && record.SoftLimitTokens == 0 && record.HardLimitTokens == 0 {
+		return fmt.Errorf("至少需要设置一个限额")
+	}
+
+	return nil
+}
+
+// ============================================================
+// 辅助函数
+// ============================================================
+
+// budgetPeriodBounds 计算 now 所在周期的起止时间（周从周一开始）
+func budgetPeriodBounds(period string, now time.Time) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case store.BudgetPeriodWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为 0
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case store.BudgetPeriodMonthly:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
+
+// evaluateBudgetState 根据消费和限额判断预算状态
+func evaluateBudgetState(b *store.BudgetRecord, spend *tracking.SpendSummary) string {
+	if (b.HardLimitUSD > 0 && spend.TotalCostUSD >= b.HardLimitUSD) ||
+		(b.HardLimitTokens > 0 && spend.TotalTokens >= b.HardLimitTokens) {
+		return BudgetStateHardExceeded
+	}
+	if (b.SoftLimitUSD > 0 && spend.TotalCostUSD >= b.SoftLimitUSD) ||
+		(b.SoftLimitTokens > 0 && spend.TotalTokens >= b.SoftLimitTokens) {
+		return BudgetStateSoftExceeded
+	}
+	return BudgetStateOK
+}
+
+// budgetStateRank 状态严重程度排序
+func budgetStateRank(state string) int {
+	switch state {
+	case BudgetStateHardExceeded:
+		return 2
+	case BudgetStateSoftExceeded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// blocking 当前是否应拦截请求（周期已结束的状态等待下一次刷新，不再拦截）
+func (st *BudgetStatus) blocking(now time.Time) bool {
+	return st.State == BudgetStateHardExceeded && now.Before(st.PeriodEnd)
+}
+
+// clone 复制状态，避免调用方修改缓存
+func (st *BudgetStatus) clone() *BudgetStatus {
+	c := *st
+	return &c
+}
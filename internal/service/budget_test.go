@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/events"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/tracking"
+)
+
+// memoryBudgetStore 测试用内存预算存储
+type memoryBudgetStore struct {
+	records []*store.BudgetRecord
+}
+
+func (m *memoryBudgetStore) Create(ctx context.Context, record *store.BudgetRecord) (*store.BudgetRecord, error) {
+	m.records = append(m.records, record)
+	return record, nil
+}
+
+func (m *memoryBudgetStore) Get(ctx context.Context, name string) (*store.BudgetRecord, error) {
+	for _, r := range m.records {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryBudgetStore) List(ctx context.Context) ([]*store.BudgetRecord, error) {
+	return m.records, nil
+}
+
+func (m *memoryBudgetStore) Update(ctx context.Context, record *store.BudgetRecord) error {
+	return nil
+}
+
+func (m *memoryBudgetStore) Delete(ctx context.Context, name string) error {
+	return nil
+}
+
+// fakeSpendQuerier 测试用消费查询：按作用范围目标返回固定消费
+type fakeSpendQuerier struct {
+	mu    sync.Mutex
+	spend map[string]*tracking.SpendSummary // key: endpoint/channel/client_key 目标名，空字符串为全局
+}
+
+func (f *fakeSpendQuerier) set(target string, usd float64, tokens int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.spend[target] = &tracking.SpendSummary{TotalCostUSD: usd, TotalTokens: tokens}
+}
+
+func (f *fakeSpendQuerier) QuerySpend(ctx context.Context, opts *tracking.QueryOptions) (*tracking.SpendSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target := opts.EndpointName + opts.Channel + opts.ClientKeyName
+	if s, ok := f.spend[target]; ok {
+		return s, nil
+	}
+	return &tracking.SpendSummary{}, nil
+}
+
+// recordingEventBus 测试用事件总线，记录发布的事件
+type recordingEventBus struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (b *recordingEventBus) Publish(event events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+}
+
+func (b *recordingEventBus) SetSSEBroadcaster(broadcaster events.SSEBroadcaster) {}
+func (b *recordingEventBus) Start() error                                        { return nil }
+func (b *recordingEventBus) Stop() error                                         { return nil }
+func (b *recordingEventBus) GetStats() events.BusStats                           { return events.BusStats{} }
+func (b *recordingEventBus) Subscribe(filter events.SubscriptionFilter) (<-chan events.Event, func()) {
+	return make(chan events.Event), func() {}
+}
+
+func (b *recordingEventBus) types() []events.EventType {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := make([]events.EventType, 0, len(b.events))
+	for _, e := range b.events {
+		result = append(result, e.Type)
+	}
+	return result
+}
+
+// newTestBudgetService 创建带内存存储的预算服务
+func newTestBudgetService(t *testing.T, records ...*store.BudgetRecord) (*BudgetService, *fakeSpendQuerier, *recordingEventBus) {
+	t.Helper()
+
+	spend := &fakeSpendQuerier{spend: make(map[string]*tracking.SpendSummary)}
+	bus := &recordingEventBus{}
+
+	svc := NewBudgetService(&memoryBudgetStore{records: records}, spend)
+	svc.SetEventBus(bus)
+	if err := svc.LoadCache(context.Background()); err != nil {
+		t.Fatalf("加载预算失败: %v", err)
+	}
+
+	return svc, spend, bus
+}
+
+// TestBudgetService_StateTransitions 测试软/硬限额状态变化和事件只发布一次
+func TestBudgetService_StateTransitions(t *testing.T) {
+	svc, spend, bus := newTestBudgetService(t, &store.BudgetRecord{
+		Name: "global", Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodMonthly,
+		SoftLimitUSD: 8, HardLimitUSD: 10, Enabled: true,
+	})
+	ctx := context.Background()
+
+	spend.set("", 5, 0)
+	svc.Refresh(ctx)
+	if st := svc.GetStatus("global"); st == nil || st.State != BudgetStateOK {
+		t.Fatalf("消费未超限时状态应为 ok: %+v", st)
+	}
+
+	spend.set("", 9, 0)
+	svc.Refresh(ctx)
+	svc.Refresh(ctx)
+	if st := svc.GetStatus("global"); st.State != BudgetStateSoftExceeded {
+		t.Errorf("状态 = %s, want %s", st.State, BudgetStateSoftExceeded)
+	}
+	if reason := svc.RequestRejectReason(""); reason != "" {
+		t.Errorf("软超限不应拒绝请求: %s", reason)
+	}
+
+	spend.set("", 12, 0)
+	svc.Refresh(ctx)
+	if reason := svc.RequestRejectReason("alice"); reason == "" {
+		t.Error("全局预算硬超限应拒绝所有请求")
+	}
+
+	want := []events.EventType{events.EventBudgetSoftLimitExceeded, events.EventBudgetHardLimitExceeded}
+	got := bus.types()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("发布事件 = %v, want %v", got, want)
+	}
+
+	// 关闭执行后放行
+	svc.Configure(false, 0)
+	if reason := svc.RequestRejectReason(""); reason != "" {
+		t.Errorf("关闭预算执行后不应拒绝请求: %s", reason)
+	}
+}
+
+// TestBudgetService_Scopes 测试客户端 Key、端点和渠道预算的作用范围
+func TestBudgetService_Scopes(t *testing.T) {
+	svc, spend, _ := newTestBudgetService(t,
+		&store.BudgetRecord{Name: "alice", Scope: store.BudgetScopeClientKey, Target: "alice", Period: store.BudgetPeriodDaily, HardLimitTokens: 1000, Enabled: true},
+		&store.BudgetRecord{Name: "ep-a", Scope: store.BudgetScopeEndpoint, Target: "ep-a", Period: store.BudgetPeriodWeekly, HardLimitUSD: 1, Enabled: true},
+		&store.BudgetRecord{Name: "ch-b", Scope: store.BudgetScopeChannel, Target: "ch-b", Period: store.BudgetPeriodMonthly, HardLimitUSD: 1, Enabled: true},
+		&store.BudgetRecord{Name: "disabled", Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodDaily, HardLimitUSD: 0.01, Enabled: false},
+	)
+
+	spend.set("", 100, 0)
+	spend.set("alice", 0, 1500)
+	spend.set("ep-a", 2, 0)
+	spend.set("ch-b", 2, 0)
+	svc.Refresh(context.Background())
+
+	if svc.RequestRejectReason("alice") == "" {
+		t.Error("alice 超过 Token 硬限额应被拒绝")
+	}
+	if reason := svc.RequestRejectReason("bob"); reason != "" {
+		t.Errorf("bob 不受 alice 预算限制: %s", reason)
+	}
+	if svc.GetStatus("disabled") != nil {
+		t.Error("禁用的预算不应统计")
+	}
+
+	tests := []struct {
+		name, channel string
+		want          bool
+	}{
+		{"ep-a", "ch-a", false},
+		{"ep-b", "ch-b", false},
+		{"ep-c", "ch-c", true},
+		{"ep-c", "", true},
+	}
+	for _, tt := range tests {
+		if got := svc.AllowEndpoint(tt.name, tt.channel); got != tt.want {
+			t.Errorf("AllowEndpoint(%s, %s) = %v, want %v", tt.name, tt.channel, got, tt.want)
+		}
+	}
+	if svc.EndpointRejectReason() == "" {
+		t.Error("存在超限的端点预算时应返回说明")
+	}
+}
+
+// TestBudgetService_Validate 测试预算验证
+func TestBudgetService_Validate(t *testing.T) {
+	svc, _, _ := newTestBudgetService(t)
+
+	tests := []struct {
+		name    string
+		record  store.BudgetRecord
+		wantErr bool
+	}{
+		{"全局预算", store.BudgetRecord{Name: "a", Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodDaily, HardLimitUSD: 1}, false},
+		{"缺少名称", store.BudgetRecord{Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodDaily, HardLimitUSD: 1}, true},
+		{"缺少目标", store.BudgetRecord{Name: "a", Scope: store.BudgetScopeEndpoint, Period: store.BudgetPeriodDaily, HardLimitUSD: 1}, true},
+		{"无效作用范围", store.BudgetRecord{Name: "a", Scope: "team", Period: store.BudgetPeriodDaily, HardLimitUSD: 1}, true},
+		{"无效周期", store.BudgetRecord{Name: "a", Scope: store.BudgetScopeGlobal, Period: "yearly", HardLimitUSD: 1}, true},
+		{"未设置限额", store.BudgetRecord{Name: "a", Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodDaily}, true},
+		{"负数限额", store.BudgetRecord{Name: "a", Scope: store.BudgetScopeGlobal, Period: store.BudgetPeriodDaily, HardLimitUSD: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			if err := svc.validateRecord(&record); (err != nil) != tt.wantErr {
+				t.Errorf("validateRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBudgetPeriodBounds 测试周期边界计算
+func TestBudgetPeriodBounds(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2025, 3, 13, 15, 30, 0, 0, loc) // 周四
+
+	tests := []struct {
+		period    string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{store.BudgetPeriodDaily, time.Date(2025, 3, 13, 0, 0, 0, 0, loc), time.Date(2025, 3, 14, 0, 0, 0, 0, loc)},
+		{store.BudgetPeriodWeekly, time.Date(2025, 3, 10, 0, 0, 0, 0, loc), time.Date(2025, 3, 17, 0, 0, 0, 0, loc)},
+		{store.BudgetPeriodMonthly, time.Date(2025, 3, 1, 0, 0, 0, 0, loc), time.Date(2025, 4, 1, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			start, end := budgetPeriodBounds(tt.period, now)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("budgetPeriodBounds() = %v - %v, want %v - %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	// 周日属于前一个周一开始的周
+	sunday := time.Date(2025, 3, 16, 23, 0, 0, 0, loc)
+	if start, _ := budgetPeriodBounds(store.BudgetPeriodWeekly, sunday); !start.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, loc)) {
+		t.Errorf("周日的周起始 = %v, want 2025-03-10", start)
+	}
+}
@@ -0,0 +1,257 @@
+// Package service 提供业务逻辑层实现
+// 客户端 API Key 服务 - 多客户端鉴权与按 Key 统计
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// clientKeyPrefix 自动生成的客户端 Key 前缀
+const clientKeyPrefix = "ccf-"
+
+// ClientKeyService 客户端 Key 管理业务服务
+// 实现 middleware.ClientKeyResolver，鉴权时只查内存缓存
+type ClientKeyService struct {
+	store store.ClientKeyStore
+
+	// 内存缓存：Key 值 -> 记录
+	cache   map[string]*store.ClientKeyRecord
+	cacheMu sync.RWMutex
+}
+
+// NewClientKeyService 创建客户端 Key 服务实例
+func NewClientKeyService(st store.ClientKeyStore) *ClientKeyService {
+	return &ClientKeyService{
+		store: st,
+		cache: make(map[string]*store.ClientKeyRecord),
+	}
+}
+
+// ResolveClientKey 校验客户端 Key，返回 Key 名称（未启用或已过期视为无效）
+func (s *ClientKeyService) ResolveClientKey(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	s.cacheMu.RLock()
+	record, ok := s.cache[token]
+	s.cacheMu.RUnlock()
+
+	if !ok || !record.Enabled || record.IsExpired(time.Now()) {
+		return "", false
+	}
+	return record.Name, true
+}
+
+// CreateKey 创建客户端 Key，未指定 Key 值时自动生成
+func (s *ClientKeyService) CreateKey(ctx context.Context, record *store.ClientKeyRecord) (*store.ClientKeyRecord, error) {
+	record.Name = strings.TrimSpace(record.Name)
+	record.Key = strings.TrimSpace(record.Key)
+	if record.Key == "" {
+		key, err := generateClientKey()
+		if err != nil {
+			return nil, err
+		}
+		record.Key = key
+	}
+
+	if err := s.validateRecord(record); err != nil {
+		return nil, err
+	}
+
+	// 检查名称和 Key 是否已存在
+	existing, err := s.store.Get(ctx, record.Name)
+	if err != nil {
+		return nil, fmt.Errorf("检查客户端 Key 是否存在失败: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("客户端 Key '%s' %w", record.Name, store.ErrAlreadyExists)
+	}
+	if err := s.checkKeyUnique(ctx, record.Key, record.Name); err != nil {
+		return nil, err
+	}
+
+	created, err := s.store.Create(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端 Key 失败: %w", err)
+	}
+
+	s.updateCache(nil, created)
+
+	slog.Info(fmt.Sprintf("✅ [ClientKeyService] 创建客户端 Key: %s", created.Name))
+	return created, nil
+}
+
+// GetKey 获取客户端 Key
+func (s *ClientKeyService) GetKey(ctx context.Context, name string) (*store.ClientKeyRecord, error) {
+	record, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取客户端 Key 失败: %w", err)
+	}
+	return record, nil
+}
+
+// ListKeys 列出所有客户端 Key
+func (s *ClientKeyService) ListKeys(ctx context.Context) ([]*store.ClientKeyRecord, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("列出客户端 Key 失败: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateKey 更新客户端 Key（Key 值为空时保留原值）
+func (s *ClientKeyService) UpdateKey(ctx context.Context, record *store.ClientKeyRecord) error {
+	existing, err := s.store.Get(ctx, record.Name)
+	if err != nil {
+		return fmt.Errorf("获取客户端 Key 失败: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("客户端 Key '%s' %w", record.Name, store.ErrNotFound)
+	}
+
+	record.Key = strings.TrimSpace(record.Key)
+	if record.Key == "" {
+		record.Key = existing.Key
+	}
+	if err := s.validateRecord(record); err != nil {
+		return err
+	}
+	if record.Key != existing.Key {
+		if err := s.checkKeyUnique(ctx, record.Key, record.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := s.store.Update(ctx, record); err != nil {
+		return fmt.Errorf("更新客户端 Key 失败: %w", err)
+	}
+
+	record.ID = existing.ID
+	record.CreatedAt = existing.CreatedAt
+	s.updateCache(existing, record)
+
+	slog.Info(fmt.Sprintf("✅ [ClientKeyService] 更新客户端 Key: %s", record.Name))
+	return nil
+}
+
+// ToggleKey 启用/禁用客户端 Key
+func (s *ClientKeyService) ToggleKey(ctx context.Context, name string, enabled bool) error {
+	existing, err := s.store.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("获取客户端 Key 失败: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("客户端 Key '%s' %w", name, store.ErrNotFound)
+	}
+
+	updated := *existing
+	updated.Enabled = enabled
+	if err := s.store.Update(ctx, &updated); err != nil {
+		return fmt.Errorf("更新客户端 Key 失败: %w", err)
+	}
+	s.updateCache(existing, &updated)
+
+	action := "禁用"
+	if enabled {
+		action = "启用"
+	}
+	slog.Info(fmt.Sprintf("✅ [ClientKeyService] %s客户端 Key: %s", action, name))
+	return nil
+}
+
+// DeleteKey 删除客户端 Key
+func (s *ClientKeyService) DeleteKey(ctx context.Context, name string) error {
+	existing, err := s.store.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("获取客户端 Key 失败: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("客户端 Key '%s' %w", name, store.ErrNotFound)
+	}
+
+	if err := s.store.Delete(ctx, name); err != nil {
+		return fmt.Errorf("删除客户端 Key 失败: %w", err)
+	}
+	s.updateCache(existing, nil)
+
+	slog.Info(fmt.Sprintf("✅ [ClientKeyService] 删除客户端 Key: %s", name))
+	return nil
+}
+
+// LoadCache 从数据库加载客户端 Key 到缓存
+func (s *ClientKeyService) LoadCache(ctx context.Context) error {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("加载客户端 Key 失败: %w", err)
+	}
+
+	cache := make(map[string]*store.ClientKeyRecord, len(records))
+	for _, record := range records {
+		cache[record.Key] = record
+	}
+
+	s.cacheMu.Lock()
+	s.cache = cache
+	s.cacheMu.Unlock()
+
+	slog.Info(fmt.Sprintf("✅ [ClientKeyService] 加载 %d 个客户端 Key 到缓存", len(records)))
+	return nil
+}
+
+// validateRecord 验证客户端 Key 记录
+func (s *ClientKeyService) validateRecord(record *store.ClientKeyRecord) error {
+	if record.Name == "" {
+		return fmt.Errorf("客户端 Key 名称不能为空")
+	}
+	if len(record.Key) < 8 {
+		return fmt.Errorf("客户端 Key 长度不能少于 8 个字符")
+	}
+	if strings.ContainsAny(record.Key, " \t\r\n") {
+		return fmt.Errorf("客户端 Key 不能包含空白字符")
+	}
+	return nil
+}
+
+// checkKeyUnique 检查 Key 值未被其他客户端使用
+func (s *ClientKeyService) checkKeyUnique(ctx context.Context, key, name string) error {
+	other, err := s.store.GetByKey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("检查客户端 Key 是否重复失败: %w", err)
+	}
+	if other != nil && other.Name != name {
+		return fmt.Errorf("该 Key 已被客户端 '%s' 使用", other.Name)
+	}
+	return nil
+}
+
+// updateCache 用新记录替换缓存中的旧记录（old 或 updated 可为 nil）
+func (s *ClientKeyService) updateCache(old, updated *store.ClientKeyRecord) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	if old != nil {
+		delete(s.cache, old.Key)
+	}
+	if updated != nil {
+		s.cache[updated.Key] = updated
+	}
+}
+
+// generateClientKey 生成随机客户端 Key
+func generateClientKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成客户端 Key 失败: %w", err)
+	}
+	return clientKeyPrefix + hex.EncodeToString(buf), nil
+}
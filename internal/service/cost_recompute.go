@@ -0,0 +1,185 @@
+// Package service 提供业务逻辑层实现
+// 成本重算 - 修正定价或端点倍率后，在后台按时间范围重算请求成本并报告进度
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/tracking"
+)
+
+// 成本重算任务状态
+const (
+	CostRecomputeRunning   = "running"
+	CostRecomputeCompleted = "completed"
+	CostRecomputeFailed    = "failed"
+	CostRecomputeCancelled = "cancelled"
+)
+
+// CostRecomputer 按时间范围重算请求成本（由 tracking.UsageTracker 实现）
+type CostRecomputer interface {
+	RecomputeCosts(ctx context.Context, opts tracking.CostRecomputeOptions, onProgress func(tracking.CostRecomputeProgress)) (tracking.CostRecomputeProgress, error)
+}
+
+// CostRecomputeJob 成本重算任务快照
+type CostRecomputeJob struct {
+	ID           int64                          `json:"id"`
+	Status       string                         `json:"status"`
+	StartTime    time.Time                      `json:"start_time"`
+	EndTime      time.Time                      `json:"end_time"`
+	ModelName    string                         `json:"model_name,omitempty"`
+	EndpointName string                         `json:"endpoint_name,omitempty"`
+	Progress     tracking.CostRecomputeProgress `json:"progress"`
+	Error        string                         `json:"error,omitempty"`
+	StartedAt    time.Time                      `json:"started_at"`
+	FinishedAt   *time.Time                     `json:"finished_at,omitempty"`
+}
+
+// CostRecomputeService 成本重算任务管理，同一时间只运行一个任务
+type CostRecomputeService struct {
+	recomputer CostRecomputer
+
+	mu      sync.Mutex
+	job     *CostRecomputeJob // 当前或最近一次任务
+	nextID  int64
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	onEvent func(CostRecomputeJob) // 进度和结束时回调（推送到前端）
+}
+
+// NewCostRecomputeService 创建成本重算服务
+func NewCostRecomputeService(recomputer CostRecomputer) *CostRecomputeService {
+	return &CostRecomputeService{recomputer: recomputer}
+}
+
+// SetOnProgress 设置进度回调，每批完成和任务结束时调用
+func (s *CostRecomputeService) SetOnProgress(fn func(CostRecomputeJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onEvent = fn
+}
+
+// Start 启动后台重算任务，已有任务运行时返回错误
+func (s *CostRecomputeService) Start(opts tracking.CostRecomputeOptions) (CostRecomputeJob, error) {
+	if opts.StartTime.IsZero() {
+		return CostRecomputeJob{}, fmt.Errorf("开始时间不能为空")
+	}
+	if opts.EndTime.IsZero() {
+		opts.EndTime = time.Now()
+	}
+	if !opts.EndTime.After(opts.StartTime) {
+		return CostRecomputeJob{}, fmt.Errorf("结束时间必须晚于开始时间")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.job != nil && s.job.Status == CostRecomputeRunning {
+		return CostRecomputeJob{}, fmt.Errorf("已有成本重算任务在运行 (#%d)", s.job.ID)
+	}
+
+	s.nextID++
+	job := &CostRecomputeJob{
+		ID:           s.nextID,
+		Status:       CostRecomputeRunning,
+		StartTime:    opts.StartTime,
+		EndTime:      opts.EndTime,
+		ModelName:    opts.ModelName,
+		EndpointName: opts.EndpointName,
+		StartedAt:    time.Now(),
+	}
+	s.job = job
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.run(ctx, job, opts)
+
+	slog.Info(fmt.Sprintf("🔄 [CostRecompute] 开始重算成本 #%d: %s ~ %s",
+		job.ID, opts.StartTime.Format(time.RFC3339), opts.EndTime.Format(time.RFC3339)))
+	return *job, nil
+}
+
+// Status 返回当前或最近一次任务的快照，没有任务时返回 nil
+func (s *CostRecomputeService) Status() *CostRecomputeJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.job == nil {
+		return nil
+	}
+	snapshot := *s.job
+	return &snapshot
+}
+
+// Cancel 取消正在运行的任务（当前批次结束后停止，已更新的记录不回滚）
+func (s *CostRecomputeService) Cancel() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.job == nil || s.job.Status != CostRecomputeRunning {
+		return fmt.Errorf("没有正在运行的成本重算任务")
+	}
+	s.cancel()
+	return nil
+}
+
+// Stop 取消运行中的任务并等待其退出（应用关闭时调用）
+func (s *CostRecomputeService) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// run 执行重算并更新任务状态
+func (s *CostRecomputeService) run(ctx context.Context, job *CostRecomputeJob, opts tracking.CostRecomputeOptions) {
+	defer s.wg.Done()
+
+	progress, err := s.recomputer.RecomputeCosts(ctx, opts, func(p tracking.CostRecomputeProgress) {
+		s.update(job, func() { job.Progress = p })
+	})
+
+	s.update(job, func() {
+		job.Progress = progress
+		now := time.Now()
+		job.FinishedAt = &now
+		switch {
+		case err == nil:
+			job.Status = CostRecomputeCompleted
+		case ctx.Err() != nil:
+			job.Status = CostRecomputeCancelled
+		default:
+			job.Status = CostRecomputeFailed
+			job.Error = err.Error()
+		}
+	})
+
+	if err != nil && ctx.Err() == nil {
+		slog.Error(fmt.Sprintf("❌ [CostRecompute] 成本重算 #%d 失败: %v", job.ID, err))
+		return
+	}
+	slog.Info(fmt.Sprintf("✅ [CostRecompute] 成本重算 #%d %s: 处理 %d/%d 条, 更新 %d 条, 成本 $%.6f → $%.6f",
+		job.ID, job.Status, progress.Processed, progress.Total, progress.Updated, progress.CostBefore, progress.CostAfter))
+}
+
+// update 在锁内修改任务并回调快照
+func (s *CostRecomputeService) update(job *CostRecomputeJob, fn func()) {
+	s.mu.Lock()
+	fn()
+	snapshot := *job
+	onEvent := s.onEvent
+	s.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(snapshot)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/tracking"
+)
+
+// fakeCostRecomputer 测试用重算器：分 batches 批报告进度，release 关闭前阻塞在最后一批之前
+type fakeCostRecomputer struct {
+	batches int
+	release chan struct{}
+	err     error
+}
+
+func (f *fakeCostRecomputer) RecomputeCosts(ctx context.Context, opts tracking.CostRecomputeOptions, onProgress func(tracking.CostRecomputeProgress)) (tracking.CostRecomputeProgress, error) {
+	progress := tracking.CostRecomputeProgress{Total: int64(f.batches * 10)}
+	onProgress(progress)
+	for i := 0; i < f.batches; i++ {
+		if i == f.batches-1 && f.release != nil {
+			select {
+			case <-f.release:
+			case <-ctx.Done():
+				return progress, ctx.Err()
+			}
+		}
+		progress.Processed += 10
+		progress.Updated += 5
+		onProgress(progress)
+	}
+	return progress, f.err
+}
+
+// waitCostRecompute 等待任务结束并返回最终快照
+func waitCostRecompute(t *testing.T, s *CostRecomputeService) *CostRecomputeJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if job := s.Status(); job != nil && job.Status != CostRecomputeRunning {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("成本重算任务未在预期时间内结束")
+	return nil
+}
+
+func TestCostRecomputeService(t *testing.T) {
+	fake := &fakeCostRecomputer{batches: 3, release: make(chan struct{})}
+	s := NewCostRecomputeService(fake)
+	defer s.Stop()
+
+	var mu sync.Mutex
+	var events []CostRecomputeJob
+	s.SetOnProgress(func(job CostRecomputeJob) {
+		mu.Lock()
+		events = append(events, job)
+		mu.Unlock()
+	})
+
+	if s.Status() != nil {
+		t.Fatal("没有任务时状态应为 nil")
+	}
+	start := time.Now().Add(-24 * time.Hour)
+	if _, err := s.Start(tracking.CostRecomputeOptions{}); err == nil {
+		t.Error("缺少开始时间时应返回错误")
+	}
+	if _, err := s.Start(tracking.CostRecomputeOptions{StartTime: start, EndTime: start}); err == nil {
+		t.Error("结束时间不晚于开始时间时应返回错误")
+	}
+
+	job, err := s.Start(tracking.CostRecomputeOptions{StartTime: start, ModelName: "claude-a"})
+	if err != nil || job.ID != 1 || job.Status != CostRecomputeRunning || job.EndTime.IsZero() {
+		t.Fatalf("启动任务失败: %v %+v", err, job)
+	}
+	if _, err := s.Start(tracking.CostRecomputeOptions{StartTime: start}); err == nil {
+		t.Error("已有任务运行时应拒绝启动")
+	}
+
+	close(fake.release)
+	done := waitCostRecompute(t, s)
+	if done.Status != CostRecomputeCompleted || done.Progress.Processed != 30 || done.Progress.Updated != 15 || done.FinishedAt == nil {
+		t.Errorf("任务结果不符: %+v", done)
+	}
+
+	mu.Lock()
+	last := events[len(events)-1]
+	if len(events) != 5 || last.Status != CostRecomputeCompleted {
+		t.Errorf("进度回调不符: %d 次, 最后 %+v", len(events), last)
+	}
+	mu.Unlock()
+
+	if err := s.Cancel(); err == nil {
+		t.Error("没有运行中的任务时取消应返回错误")
+	}
+}
+
+func TestCostRecomputeService_CancelAndFail(t *testing.T) {
+	fake := &fakeCostRecomputer{batches: 2, release: make(chan struct{})}
+	s := NewCostRecomputeService(fake)
+	defer s.Stop()
+
+	start := time.Now().Add(-time.Hour)
+	if _, err := s.Start(tracking.CostRecomputeOptions{StartTime: start}); err != nil {
+		t.Fatalf("启动任务失败: %v", err)
+	}
+	if err := s.Cancel(); err != nil {
+		t.Fatalf("取消任务失败: %v", err)
+	}
+	if job := waitCostRecompute(t, s); job.Status != CostRecomputeCancelled || job.Progress.Processed != 10 {
+		t.Errorf("取消后的任务状态不符: %+v", job)
+	}
+
+	// 上一个任务结束后可以再次启动
+	s.recomputer = &fakeCostRecomputer{batches: 1, err: errors.New("database locked")}
+	job, err := s.Start(tracking.CostRecomputeOptions{StartTime: start})
+	if err != nil || job.ID != 2 {
+		t.Fatalf("再次启动任务失败: %v %+v", err, job)
+	}
+	if job := waitCostRecompute(t, s); job.Status != CostRecomputeFailed || job.Error != "database locked" {
+		t.Errorf("失败任务状态不符: %+v", job)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"cc-forwarder/config"
@@ -45,7 +46,7 @@ func (s *EndpointService) CreateEndpoint(ctx context.Context, record *store.Endp
 
 	// 检查名称唯一性（运行时）
 	if existing := s.manager.GetEndpointByNameAny(record.Name); existing != nil {
-		return nil, fmt.Errorf("端点 '%s' 已存在", record.Name)
+		return nil, fmt.Errorf("端点 '%s' %w", record.Name, store.ErrAlreadyExists)
 	}
 
 	// 保存到数据库
@@ -103,7 +104,7 @@ func (s *EndpointService) UpdateEndpoint(ctx context.Context, record *store.Endp
 		return fmt.Errorf("查询端点失败: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("端点 '%s' 不存在", record.Name)
+		return fmt.Errorf("端点 '%s' %w", record.Name, store.ErrNotFound)
 	}
 
 	// 验证必填字段
@@ -137,7 +138,7 @@ func (s *EndpointService) DeleteEndpoint(ctx context.Context, name string) error
 		return fmt.Errorf("查询端点失败: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("端点 '%s' 不存在", name)
+		return fmt.Errorf("端点 '%s' %w", name, store.ErrNotFound)
 	}
 
 	// 先从运行时管理器移除
@@ -162,7 +163,7 @@ func (s *EndpointService) ToggleEndpoint(ctx context.Context, name string, enabl
 		return fmt.Errorf("获取端点失败: %w", err)
 	}
 	if record == nil {
-		return fmt.Errorf("端点 '%s' 不存在", name)
+		return fmt.Errorf("端点 '%s' %w", name, store.ErrNotFound)
 	}
 
 	record.Enabled = enabled
@@ -278,7 +279,7 @@ func (s *EndpointService) GetEndpointWithHealth(ctx context.Context, name string
 		return nil, fmt.Errorf("获取端点失败: %w", err)
 	}
 	if record == nil {
-		return nil, fmt.Errorf("端点 '%s' 不存在", name)
+		return nil, fmt.Errorf("端点 '%s' %w", name, store.ErrNotFound)
 	}
 
 	// 获取健康状态
@@ -317,6 +318,22 @@ func (s *EndpointService) validateRecord(record *store.EndpointRecord) error {
 	if record.Channel == "" {
 		return fmt.Errorf("端点渠道不能为空")
 	}
+	if proxy := EndpointProxyToConfig(record.Proxy); proxy != nil {
+		if err := config.ValidateProxy(*proxy); err != nil {
+			return fmt.Errorf("端点代理配置无效: %w", err)
+		}
+	}
+	for from, to := range record.ModelMapping {
+		if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return fmt.Errorf("模型映射的模型名称不能为空")
+		}
+	}
+	if _, err := config.ParseRetryPolicy(record.RetryPolicy); err != nil {
+		return fmt.Errorf("端点重试策略无效: %w", err)
+	}
+	if record.MaxConcurrency < 0 || record.RPM < 0 || record.TPM < 0 {
+		return fmt.Errorf("端点并发和 RPM/TPM 上限不能为负数")
+	}
 	return nil
 }
 
@@ -327,11 +344,18 @@ func (s *EndpointService) recordToConfig(record *store.EndpointRecord) config.En
 		URL:                 record.URL,
 		Channel:             record.Channel,
 		Priority:            record.Priority,
+		Weight:              record.Weight,
 		Token:               record.Token,
 		ApiKey:              record.ApiKey,
 		Headers:             record.Headers,
 		Timeout:             time.Duration(record.TimeoutSeconds) * time.Second,
+		MaxConcurrency:      record.MaxConcurrency,
+		RPM:                 record.RPM,
+		TPM:                 record.TPM,
 		SupportsCountTokens: record.SupportsCountTokens,
+		ModelMapping:        record.ModelMapping,
+		RetryPolicy:         EndpointRetryPolicyToConfig(record.RetryPolicy),
+		Proxy:               EndpointProxyToConfig(record.Proxy),
 	}
 
 	// v5.0: 设置 Enabled（是否作为代理端点）
@@ -361,11 +385,18 @@ func (s *EndpointService) configToRecord(cfg config.EndpointConfig) *store.Endpo
 		ApiKey:              cfg.ApiKey,
 		Headers:             cfg.Headers,
 		Priority:            cfg.Priority,
+		Weight:              cfg.Weight,
 		FailoverEnabled:     true, // 默认参与故障转移
 		TimeoutSeconds:      int(cfg.Timeout.Seconds()),
+		MaxConcurrency:      cfg.MaxConcurrency,
+		RPM:                 cfg.RPM,
+		TPM:                 cfg.TPM,
 		SupportsCountTokens: cfg.SupportsCountTokens,
+		ModelMapping:        cfg.ModelMapping,
+		RetryPolicy:         config.FormatRetryPolicy(cfg.RetryPolicy),
 		CostMultiplier:      1.0,
 		Enabled:             true,
+		Proxy:               EndpointProxyFromConfig(cfg.Proxy),
 	}
 
 	if cfg.FailoverEnabled != nil {
@@ -384,6 +415,48 @@ func (s *EndpointService) configToRecord(cfg config.EndpointConfig) *store.Endpo
 	return record
 }
 
+// EndpointRetryPolicyToConfig 将数据库中的端点重试策略文本转换为规则（保存时已校验，解析失败时视为未配置）
+func EndpointRetryPolicyToConfig(text string) []config.RetryRule {
+	rules, err := config.ParseRetryPolicy(text)
+	if err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [EndpointService] 端点重试策略无效，已忽略: %v", err))
+		return nil
+	}
+	return rules
+}
+
+// EndpointProxyToConfig 将数据库中的端点代理配置转换为配置对象（nil 表示使用全局代理）
+func EndpointProxyToConfig(p *store.EndpointProxy) *config.ProxyConfig {
+	if p == nil {
+		return nil
+	}
+	return &config.ProxyConfig{
+		Enabled:  p.Enabled,
+		Type:     p.Type,
+		URL:      p.URL,
+		Host:     p.Host,
+		Port:     p.Port,
+		Username: p.Username,
+		Password: p.Password,
+	}
+}
+
+// EndpointProxyFromConfig 将配置对象中的端点代理转换为数据库记录格式
+func EndpointProxyFromConfig(p *config.ProxyConfig) *store.EndpointProxy {
+	if p == nil {
+		return nil
+	}
+	return &store.EndpointProxy{
+		Enabled:  p.Enabled,
+		Type:     p.Type,
+		URL:      p.URL,
+		Host:     p.Host,
+		Port:     p.Port,
+		Username: p.Username,
+		Password: p.Password,
+	}
+}
+
 // maskToken 脱敏 Token
 func maskToken(token string) string {
 	if len(token) <= 8 {
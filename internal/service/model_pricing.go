@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"cc-forwarder/internal/store"
 	"cc-forwarder/internal/tracking"
@@ -45,20 +46,31 @@ func (s *ModelPricingService) CreatePricing(ctx context.Context, record *store.M
 		return nil, fmt.Errorf("检查定价是否存在失败: %w", err)
 	}
 	if existing != nil {
-		return nil, fmt.Errorf("模型定价 '%s' 已存在", record.ModelName)
+		return nil, fmt.Errorf("模型定价 '%s' %w", record.ModelName, store.ErrAlreadyExists)
 	}
 
-	// 如果设置为默认，先清除其他默认标记
-	if record.IsDefault {
-		if err := s.clearDefaultFlag(ctx); err != nil {
-			return nil, err
+	// 定价和定价版本在同一事务中写入，任一失败时整体回滚
+	var created *store.ModelPricingRecord
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		// 如果设置为默认，先清除其他默认标记
+		if record.IsDefault {
+			if err := s.clearDefaultFlag(ctx, st); err != nil {
+				return err
+			}
+		}
+
+		// 创建记录
+		var err error
+		created, err = st.Create(ctx, record)
+		if err != nil {
+			return fmt.Errorf("创建模型定价失败: %w", err)
 		}
-	}
 
-	// 创建记录
-	created, err := s.store.Create(ctx, record)
+		// 记录定价版本（从创建时起生效）
+		return s.recordVersion(ctx, st, created, time.Now(), PricingVersionCreate)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("创建模型定价失败: %w", err)
+		return nil, err
 	}
 
 	// 更新缓存
@@ -156,19 +168,31 @@ func (s *ModelPricingService) UpdatePricing(ctx context.Context, record *store.M
 		return fmt.Errorf("查询模型定价失败: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("模型定价 '%s' 不存在", record.ModelName)
+		return fmt.Errorf("模型定价 '%s' %w", record.ModelName, store.ErrNotFound)
 	}
 
-	// 如果设置为默认，先清除其他默认标记
-	if record.IsDefault && !existing.IsDefault {
-		if err := s.clearDefaultFlag(ctx); err != nil {
-			return err
+	// 定价和定价版本在同一事务中写入，任一失败时整体回滚
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		// 如果设置为默认，先清除其他默认标记
+		if record.IsDefault && !existing.IsDefault {
+			if err := s.clearDefaultFlag(ctx, st); err != nil {
+				return err
+			}
 		}
-	}
 
-	// 更新数据库
-	if err := s.store.Update(ctx, record); err != nil {
-		return fmt.Errorf("更新模型定价失败: %w", err)
+		// 更新数据库
+		if err := st.Update(ctx, record); err != nil {
+			return fmt.Errorf("更新模型定价失败: %w", err)
+		}
+
+		// 价格变化时记录新版本（从修改时起生效，之前的请求仍按旧版本计算）
+		if pricingDiffers(existing, record) {
+			return s.recordVersion(ctx, st, record, time.Now(), PricingVersionUpdate)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// 更新缓存
@@ -186,7 +210,7 @@ func (s *ModelPricingService) DeletePricing(ctx context.Context, modelName strin
 		return fmt.Errorf("查询模型定价失败: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("模型定价 '%s' 不存在", modelName)
+		return fmt.Errorf("模型定价 '%s' %w", modelName, store.ErrNotFound)
 	}
 
 	// 不允许删除默认定价
@@ -194,9 +218,18 @@ func (s *ModelPricingService) DeletePricing(ctx context.Context, modelName strin
 		return fmt.Errorf("不能删除默认定价，请先设置其他模型为默认")
 	}
 
-	// 删除数据库记录
-	if err := s.store.Delete(ctx, modelName); err != nil {
-		return fmt.Errorf("删除模型定价失败: %w", err)
+	// 定价和定价历史在同一事务中删除（之后该模型的请求回退到默认定价）
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		if err := st.Delete(ctx, modelName); err != nil {
+			return fmt.Errorf("删除模型定价失败: %w", err)
+		}
+		if err := st.DeleteVersions(ctx, modelName); err != nil {
+			return fmt.Errorf("删除模型定价历史失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// 从缓存移除
@@ -297,10 +330,11 @@ func (s *ModelPricingService) GetPricingCount(ctx context.Context) (int, error)
 // 注意：CacheCreation 使用 5m 价格，因为大多数请求使用默认 5 分钟缓存
 func (s *ModelPricingService) ToTrackingPricing(record *store.ModelPricingRecord) tracking.ModelPricing {
 	return tracking.ModelPricing{
-		Input:         record.InputPrice,
-		Output:        record.OutputPrice,
-		CacheCreation: record.CacheCreationPrice5m, // 使用 5m 价格作为默认
-		CacheRead:     record.CacheReadPrice,
+		Input:           record.InputPrice,
+		Output:          record.OutputPrice,
+		CacheCreation:   record.CacheCreationPrice5m, // 使用 5m 价格作为默认
+		CacheCreation1h: record.CacheCreationPrice1h,
+		CacheRead:       record.CacheReadPrice,
 	}
 }
 
@@ -319,8 +353,8 @@ func (s *ModelPricingService) validateRecord(record *store.ModelPricingRecord) e
 }
 
 // clearDefaultFlag 清除所有默认标记
-func (s *ModelPricingService) clearDefaultFlag(ctx context.Context) error {
-	records, err := s.store.List(ctx)
+func (s *ModelPricingService) clearDefaultFlag(ctx context.Context, st store.ModelPricingStore) error {
+	records, err := st.List(ctx)
 	if err != nil {
 		return fmt.Errorf("获取定价列表失败: %w", err)
 	}
@@ -328,7 +362,7 @@ func (s *ModelPricingService) clearDefaultFlag(ctx context.Context) error {
 	for _, record := range records {
 		if record.IsDefault {
 			record.IsDefault = false
-			if err := s.store.Update(ctx, record); err != nil {
+			if err := st.Update(ctx, record); err != nil {
 				return fmt.Errorf("清除默认标记失败: %w", err)
 			}
 		}
@@ -0,0 +1,251 @@
+// Package service 提供业务逻辑层实现
+// 模型定价历史 - 维护带生效时间的定价版本，供成本计算按请求时间选择定价
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/tracking"
+)
+
+// 定价版本来源
+const (
+	PricingVersionBaseline = "baseline" // 启用定价历史前已有的定价
+	PricingVersionCreate   = "create"   // 创建模型定价
+	PricingVersionUpdate   = "update"   // 修改模型定价
+	PricingVersionImport   = "import"   // 从价格目录导入
+	PricingVersionManual   = "manual"   // 手动添加或修正的版本
+)
+
+// EnsureBaselineVersions 为没有定价历史的模型补充基线版本
+// 基线版本从 Unix 纪元起生效，使启用定价历史前的请求仍按当前定价计算
+func (s *ModelPricingService) EnsureBaselineVersions(ctx context.Context) (int, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取定价列表失败: %w", err)
+	}
+	versions, err := s.store.ListVersions(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("获取定价历史失败: %w", err)
+	}
+
+	versioned := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		versioned[v.ModelName] = true
+	}
+
+	added := 0
+	for _, record := range records {
+		if versioned[record.ModelName] {
+			continue
+		}
+		if err := s.recordVersion(ctx, s.store, record, time.Unix(0, 0), PricingVersionBaseline); err != nil {
+			return added, err
+		}
+		added++
+	}
+
+	if added > 0 {
+		slog.Info(fmt.Sprintf("✅ [ModelPricingService] 为 %d 个模型补充基线定价版本", added))
+	}
+	return added, nil
+}
+
+// ListPricingVersions 列出模型的定价版本（按生效时间升序），modelName 为空时列出全部
+func (s *ModelPricingService) ListPricingVersions(ctx context.Context, modelName string) ([]*store.ModelPricingVersionRecord, error) {
+	versions, err := s.store.ListVersions(ctx, modelName)
+	if err != nil {
+		return nil, fmt.Errorf("获取定价历史失败: %w", err)
+	}
+	return versions, nil
+}
+
+// AddPricingVersion 添加或修正定价版本
+// 生效时间不能晚于当前时间；与已有版本生效时间相同时覆盖其价格。
+// 新版本是该模型最新的版本时，同步更新模型当前定价
+func (s *ModelPricingService) AddPricingVersion(ctx context.Context, version *store.ModelPricingVersionRecord) error {
+	existing, err := s.store.Get(ctx, version.ModelName)
+	if err != nil {
+		return fmt.Errorf("查询模型定价失败: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("模型定价 '%s' %w", version.ModelName, store.ErrNotFound)
+	}
+	if version.InputPrice < 0 || version.OutputPrice < 0 || version.CacheCreationPrice5m < 0 ||
+		version.CacheCreationPrice1h < 0 || version.CacheReadPrice < 0 {
+		return fmt.Errorf("价格不能为负数")
+	}
+	if version.EffectiveFrom.IsZero() {
+		return fmt.Errorf("生效时间不能为空")
+	}
+	if version.EffectiveFrom.After(time.Now()) {
+		return fmt.Errorf("生效时间不能晚于当前时间")
+	}
+
+	version.EffectiveFrom = version.EffectiveFrom.Truncate(time.Second)
+	if version.CacheCreationPrice5m == 0 {
+		version.CacheCreationPrice5m = version.InputPrice * 1.25
+	}
+	if version.CacheCreationPrice1h == 0 {
+		version.CacheCreationPrice1h = version.InputPrice * 2.0
+	}
+	if version.CacheReadPrice == 0 {
+		version.CacheReadPrice = version.InputPrice * 0.1
+	}
+	if version.Source == "" {
+		version.Source = PricingVersionManual
+	}
+
+	// 定价版本和模型当前定价在同一事务中写入，任一失败时整体回滚
+	var synced *store.ModelPricingRecord
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		if err := st.AddVersion(ctx, version); err != nil {
+			return fmt.Errorf("添加定价版本失败: %w", err)
+		}
+		synced, err = s.syncCurrentPricing(ctx, st, existing)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if synced != nil {
+		s.updateCache(synced)
+	}
+
+	slog.Info(fmt.Sprintf("✅ [ModelPricingService] 添加定价版本: %s @ %s",
+		version.ModelName, version.EffectiveFrom.Format(time.RFC3339)))
+	return nil
+}
+
+// DeletePricingVersion 删除定价版本（模型至少保留一个版本）
+// 删除的是最新版本时，模型当前定价回退到上一个版本
+func (s *ModelPricingService) DeletePricingVersion(ctx context.Context, id int64) error {
+	version, err := s.store.GetVersion(ctx, id)
+	if err != nil {
+		return fmt.Errorf("查询定价版本失败: %w", err)
+	}
+	if version == nil {
+		return fmt.Errorf("定价版本%w: %d", store.ErrNotFound, id)
+	}
+
+	versions, err := s.store.ListVersions(ctx, version.ModelName)
+	if err != nil {
+		return fmt.Errorf("获取定价历史失败: %w", err)
+	}
+	if len(versions) <= 1 {
+		return fmt.Errorf("模型 '%s' 至少需要保留一个定价版本", version.ModelName)
+	}
+
+	// 删除版本和回退模型当前定价在同一事务中完成，任一失败时整体回滚
+	var synced *store.ModelPricingRecord
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		if err := st.DeleteVersion(ctx, id); err != nil {
+			return fmt.Errorf("删除定价版本失败: %w", err)
+		}
+		existing, err := st.Get(ctx, version.ModelName)
+		if err != nil {
+			return fmt.Errorf("查询模型定价失败: %w", err)
+		}
+		if existing == nil {
+			return nil
+		}
+		synced, err = s.syncCurrentPricing(ctx, st, existing)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if synced != nil {
+		s.updateCache(synced)
+	}
+
+	slog.Info(fmt.Sprintf("✅ [ModelPricingService] 删除定价版本: %s @ %s",
+		version.ModelName, version.EffectiveFrom.Format(time.RFC3339)))
+	return nil
+}
+
+// PricingHistory 返回全部定价版本（tracking 格式），用于同步到 UsageTracker
+func (s *ModelPricingService) PricingHistory(ctx context.Context) (tracking.PricingHistory, error) {
+	versions, err := s.store.ListVersions(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取定价历史失败: %w", err)
+	}
+
+	grouped := make(map[string][]tracking.PricingVersion)
+	for _, v := range versions {
+		grouped[v.ModelName] = append(grouped[v.ModelName], tracking.PricingVersion{
+			EffectiveFrom: v.EffectiveFrom,
+			Pricing: tracking.ModelPricing{
+				Input:           v.InputPrice,
+				Output:          v.OutputPrice,
+				CacheCreation:   v.CacheCreationPrice5m,
+				CacheCreation1h: v.CacheCreationPrice1h,
+				CacheRead:       v.CacheReadPrice,
+			},
+		})
+	}
+	return tracking.NewPricingHistory(grouped), nil
+}
+
+// recordVersion 通过 st 将模型定价记录为从 effectiveFrom 起生效的版本（st 可以是事务中的存储）
+func (s *ModelPricingService) recordVersion(ctx context.Context, st store.ModelPricingStore, record *store.ModelPricingRecord, effectiveFrom time.Time, source string) error {
+	version := &store.ModelPricingVersionRecord{
+		ModelName:            record.ModelName,
+		InputPrice:           record.InputPrice,
+		OutputPrice:          record.OutputPrice,
+		CacheCreationPrice5m: record.CacheCreationPrice5m,
+		CacheCreationPrice1h: record.CacheCreationPrice1h,
+		CacheReadPrice:       record.CacheReadPrice,
+		EffectiveFrom:        effectiveFrom.Truncate(time.Second),
+		Source:               source,
+	}
+	if err := st.AddVersion(ctx, version); err != nil {
+		return fmt.Errorf("记录定价版本失败: %w", err)
+	}
+	return nil
+}
+
+// syncCurrentPricing 通过 st 使模型当前定价与最新版本一致（保留显示名称等元信息）
+// 返回更新后的记录，无需更新时返回 nil；调用方在事务提交后再更新缓存
+func (s *ModelPricingService) syncCurrentPricing(ctx context.Context, st store.ModelPricingStore, current *store.ModelPricingRecord) (*store.ModelPricingRecord, error) {
+	versions, err := st.ListVersions(ctx, current.ModelName)
+	if err != nil {
+		return nil, fmt.Errorf("获取定价历史失败: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	latest := versions[len(versions)-1]
+	if !pricingDiffers(current, &store.ModelPricingRecord{
+		InputPrice:           latest.InputPrice,
+		OutputPrice:          latest.OutputPrice,
+		CacheCreationPrice5m: latest.CacheCreationPrice5m,
+		CacheCreationPrice1h: latest.CacheCreationPrice1h,
+		CacheReadPrice:       latest.CacheReadPrice,
+	}) {
+		return nil, nil
+	}
+
+	updated := *current
+	updated.InputPrice = latest.InputPrice
+	updated.OutputPrice = latest.OutputPrice
+	updated.CacheCreationPrice5m = latest.CacheCreationPrice5m
+	updated.CacheCreationPrice1h = latest.CacheCreationPrice1h
+	updated.CacheReadPrice = latest.CacheReadPrice
+	if err := st.Update(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("更新模型当前定价失败: %w", err)
+	}
+	return &updated, nil
+}
+
+// pricingDiffers 判断两条定价记录的价格是否不同
+func pricingDiffers(a, b *store.ModelPricingRecord) bool {
+	return a.InputPrice != b.InputPrice || a.OutputPrice != b.OutputPrice ||
+		a.CacheCreationPrice5m != b.CacheCreationPrice5m || a.CacheCreationPrice1h != b.CacheCreationPrice1h ||
+		a.CacheReadPrice != b.CacheReadPrice
+}
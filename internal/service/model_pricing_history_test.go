@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+func (m *memoryModelPricingStore) Get(ctx context.Context, modelName string) (*store.ModelPricingRecord, error) {
+	r, ok := m.records[modelName]
+	if !ok {
+		return nil, nil
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *memoryModelPricingStore) Create(ctx context.Context, record *store.ModelPricingRecord) (*store.ModelPricingRecord, error) {
+	copied := *record
+	m.records[record.ModelName] = &copied
+	return record, nil
+}
+
+func (m *memoryModelPricingStore) Update(ctx context.Context, record *store.ModelPricingRecord) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	if _, ok := m.records[record.ModelName]; !ok {
+		return fmt.Errorf("模型定价不存在: %s", record.ModelName)
+	}
+	copied := *record
+	m.records[record.ModelName] = &copied
+	return nil
+}
+
+func (m *memoryModelPricingStore) Delete(ctx context.Context, modelName string) error {
+	delete(m.records, modelName)
+	return nil
+}
+
+func (m *memoryModelPricingStore) AddVersion(ctx context.Context, record *store.ModelPricingVersionRecord) error {
+	if m.versionErr != nil {
+		return m.versionErr
+	}
+	for _, v := range m.versions {
+		if v.ModelName == record.ModelName && v.EffectiveFrom.Equal(record.EffectiveFrom) {
+			record.ID = v.ID
+			*v = *record
+			return nil
+		}
+	}
+	m.nextVersionID++
+	record.ID = m.nextVersionID
+	copied := *record
+	m.versions = append(m.versions, &copied)
+	return nil
+}
+
+func (m *memoryModelPricingStore) GetVersion(ctx context.Context, id int64) (*store.ModelPricingVersionRecord, error) {
+	for _, v := range m.versions {
+		if v.ID == id {
+			copied := *v
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryModelPricingStore) ListVersions(ctx context.Context, modelName string) ([]*store.ModelPricingVersionRecord, error) {
+	var list []*store.ModelPricingVersionRecord
+	for _, v := range m.versions {
+		if modelName == "" || v.ModelName == modelName {
+			copied := *v
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ModelName != list[j].ModelName {
+			return list[i].ModelName < list[j].ModelName
+		}
+		return list[i].EffectiveFrom.Before(list[j].EffectiveFrom)
+	})
+	return list, nil
+}
+
+func (m *memoryModelPricingStore) DeleteVersion(ctx context.Context, id int64) error {
+	for i, v := range m.versions {
+		if v.ID == id {
+			m.versions = append(m.versions[:i], m.versions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("定价版本不存在: %d", id)
+}
+
+func (m *memoryModelPricingStore) DeleteVersions(ctx context.Context, modelName string) error {
+	kept := m.versions[:0]
+	for _, v := range m.versions {
+		if v.ModelName != modelName {
+			kept = append(kept, v)
+		}
+	}
+	m.versions = kept
+	return nil
+}
+
+func TestModelPricingService_Versions(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{
+		ModelName: "claude-a", DisplayName: "Claude A",
+		InputPrice: 3, OutputPrice: 15, CacheCreationPrice5m: 3.75, CacheCreationPrice1h: 6, CacheReadPrice: 0.3,
+	})
+	s := NewModelPricingService(st)
+	ctx := context.Background()
+	now := time.Now()
+
+	if added, err := s.EnsureBaselineVersions(ctx); err != nil || added != 1 {
+		t.Fatalf("补充基线版本失败: %v %d", err, added)
+	}
+	if added, _ := s.EnsureBaselineVersions(ctx); added != 0 {
+		t.Errorf("已有历史的模型不应重复补充: %d", added)
+	}
+
+	// 最新版本同步到当前定价，元信息保留
+	if err := s.AddPricingVersion(ctx, &store.ModelPricingVersionRecord{ModelName: "claude-a", InputPrice: 4, OutputPrice: 20, EffectiveFrom: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("添加定价版本失败: %v", err)
+	}
+	if current := st.records["claude-a"]; current.InputPrice != 4 || current.CacheReadPrice != 0.4 || current.DisplayName != "Claude A" {
+		t.Errorf("当前定价应同步为最新版本: %+v", current)
+	}
+
+	// 回溯修正不影响当前定价
+	if err := s.AddPricingVersion(ctx, &store.ModelPricingVersionRecord{ModelName: "claude-a", InputPrice: 3.5, OutputPrice: 17.5, EffectiveFrom: now.Add(-48 * time.Hour)}); err != nil {
+		t.Fatalf("添加回溯版本失败: %v", err)
+	}
+	if current := st.records["claude-a"]; current.InputPrice != 4 {
+		t.Errorf("回溯版本不应修改当前定价: %+v", current)
+	}
+
+	updated := *st.records["claude-a"]
+	updated.InputPrice = 5
+	if err := s.UpdatePricing(ctx, &updated); err != nil {
+		t.Fatalf("更新定价失败: %v", err)
+	}
+
+	history, err := s.PricingHistory(ctx)
+	if err != nil || len(history["claude-a"]) != 4 {
+		t.Fatalf("定价历史不符: %v %+v", err, history)
+	}
+	for at, want := range map[time.Duration]float64{-72 * time.Hour: 3, -30 * time.Hour: 3.5, -30 * time.Minute: 4, time.Minute: 5} {
+		if pricing, _, ok := history.At("claude-a", now.Add(at)); !ok || pricing.Input != want {
+			t.Errorf("%v 时的定价应为 %v, 实际 %+v", at, want, pricing)
+		}
+	}
+
+	// 删除最新版本后当前定价回退到上一个版本
+	versions, _ := s.ListPricingVersions(ctx, "claude-a")
+	latest := versions[len(versions)-1]
+	if latest.Source != PricingVersionUpdate {
+		t.Errorf("最新版本来源应为 update: %+v", latest)
+	}
+	if err := s.DeletePricingVersion(ctx, latest.ID); err != nil {
+		t.Fatalf("删除定价版本失败: %v", err)
+	}
+	if current := st.records["claude-a"]; current.InputPrice != 4 {
+		t.Errorf("当前定价应回退到上一个版本: %+v", current)
+	}
+
+	invalid := []*store.ModelPricingVersionRecord{
+		{ModelName: "claude-a", InputPrice: 1, EffectiveFrom: now.Add(time.Hour)},
+		{ModelName: "claude-a", InputPrice: -1, EffectiveFrom: now},
+		{ModelName: "claude-a", InputPrice: 1},
+		{ModelName: "unknown", InputPrice: 1, EffectiveFrom: now},
+	}
+	for _, v := range invalid {
+		if err := s.AddPricingVersion(ctx, v); err == nil {
+			t.Errorf("应拒绝定价版本: %+v", v)
+		}
+	}
+
+	if err := s.DeletePricing(ctx, "claude-a"); err != nil {
+		t.Fatalf("删除模型定价失败: %v", err)
+	}
+	if left, _ := s.ListPricingVersions(ctx, ""); len(left) != 0 {
+		t.Errorf("删除模型后定价历史应清空: %d 条", len(left))
+	}
+}
+
+func TestModelPricingService_DeleteOnlyVersion(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15})
+	s := NewModelPricingService(st)
+	ctx := context.Background()
+
+	if _, err := s.EnsureBaselineVersions(ctx); err != nil {
+		t.Fatalf("补充基线版本失败: %v", err)
+	}
+	versions, _ := s.ListPricingVersions(ctx, "claude-a")
+	if err := s.DeletePricingVersion(ctx, versions[0].ID); err == nil {
+		t.Error("不应删除模型唯一的定价版本")
+	}
+}
+
+func TestModelPricingService_UpdatePricingRollsBack(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15})
+	st.versionErr = fmt.Errorf("disk full")
+	s := NewModelPricingService(st)
+
+	if err := s.UpdatePricing(context.Background(), &store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 4, OutputPrice: 20}); err == nil {
+		t.Fatal("记录定价版本失败时更新应失败")
+	}
+	if current := st.records["claude-a"]; current.InputPrice != 3 {
+		t.Errorf("定价版本写入失败时定价应回滚: %+v", current)
+	}
+	if _, err := s.CreatePricing(context.Background(), &store.ModelPricingRecord{ModelName: "claude-b", InputPrice: 1, OutputPrice: 5}); err == nil {
+		t.Fatal("记录定价版本失败时创建应失败")
+	}
+	if st.records["claude-b"] != nil {
+		t.Error("定价版本写入失败时不应创建定价")
+	}
+}
+
+func TestModelPricingService_AddPricingVersionRollsBack(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15})
+	s := NewModelPricingService(st)
+	ctx := context.Background()
+
+	if _, err := s.EnsureBaselineVersions(ctx); err != nil {
+		t.Fatalf("补充基线版本失败: %v", err)
+	}
+	st.updateErr = fmt.Errorf("disk full")
+	version := &store.ModelPricingVersionRecord{ModelName: "claude-a", InputPrice: 4, OutputPrice: 20, EffectiveFrom: time.Now().Add(-time.Hour)}
+	if err := s.AddPricingVersion(ctx, version); err == nil {
+		t.Fatal("同步当前定价失败时添加版本应失败")
+	}
+	if versions, _ := s.ListPricingVersions(ctx, "claude-a"); len(versions) != 1 {
+		t.Errorf("同步当前定价失败时新版本应回滚: %d 条", len(versions))
+	}
+	if pricing, err := s.GetPricing(ctx, "claude-a"); err != nil || pricing.InputPrice != 3 {
+		t.Errorf("回滚后缓存不应使用新版本价格: %+v %v", pricing, err)
+	}
+}
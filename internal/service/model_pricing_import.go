@@ -0,0 +1,488 @@
+// Package service 提供业务逻辑层实现
+// 模型定价导入 - 从 LiteLLM 价格目录或 CSV 导入，应用前预览新增和变化的模型
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// 价格目录格式
+const (
+	PricingFormatLiteLLM = "litellm" // LiteLLM model_prices_and_context_window.json
+	PricingFormatCSV     = "csv"
+)
+
+// 导入差异类型
+const (
+	PricingChangeNew       = "new"
+	PricingChangeChanged   = "changed"
+	PricingChangeUnchanged = "unchanged"
+)
+
+// defaultLiteLLMProviders LiteLLM 目录未指定 provider 时只导入 Anthropic 模型
+var defaultLiteLLMProviders = []string{"anthropic"}
+
+// pricingCSVColumns CSV 支持的列（价格单位为 USD / 1M tokens）
+var pricingCSVColumns = []string{
+	"model_name", "input_price", "output_price",
+	"cache_creation_price_5m", "cache_creation_price_1h", "cache_read_price",
+	"display_name", "description",
+}
+
+// PricingImportOptions 定价导入选项
+type PricingImportOptions struct {
+	Format       string   // litellm / csv，为空时按内容识别（以 { 开头为 LiteLLM）
+	Providers    []string // LiteLLM 目录只导入这些 litellm_provider，为空时为 anthropic
+	OnlyExisting bool     // 只更新已有模型，不新增
+	Source       string   // 来源说明，写入审计记录
+}
+
+// PricingFieldChange 单个字段的变化
+type PricingFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// PricingChange 单个模型的导入差异
+type PricingChange struct {
+	ModelName string               `json:"model_name"`
+	Action    string               `json:"action"` // new / changed / unchanged
+	Fields    []PricingFieldChange `json:"fields,omitempty"`
+
+	record *store.ModelPricingRecord // 导入后的完整记录
+	old    *store.ModelPricingRecord // 导入前的记录，新增模型为 nil
+}
+
+// PricingImportDiff 导入预览结果
+type PricingImportDiff struct {
+	Format    string          `json:"format"`
+	Total     int             `json:"total"`   // 目录中符合条件的模型数
+	Skipped   int             `json:"skipped"` // 被 provider、缺少价格或 OnlyExisting 过滤的条目数
+	New       int             `json:"new"`
+	Changed   int             `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+	Changes   []PricingChange `json:"changes"` // 依次为新增、变化、未变化，同类按模型名称排序
+
+	// 新增和变化内容的摘要，应用时校验预览后定价没有被修改
+	Fingerprint string `json:"fingerprint"`
+}
+
+// ParsePricingCatalog 解析价格目录，返回模型定价（USD / 1M tokens）、识别出的格式和被过滤的条目数
+func ParsePricingCatalog(data []byte, opts PricingImportOptions) ([]*store.ModelPricingRecord, string, int, error) {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		format = PricingFormatCSV
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = PricingFormatLiteLLM
+		}
+	}
+
+	switch format {
+	case PricingFormatLiteLLM:
+		providers := opts.Providers
+		if len(providers) == 0 {
+			providers = defaultLiteLLMProviders
+		}
+		records, skipped, err := parseLiteLLMCatalog(data, providers)
+		return records, format, skipped, err
+	case PricingFormatCSV:
+		records, err := parsePricingCSV(data)
+		return records, format, 0, err
+	default:
+		return nil, format, 0, fmt.Errorf("不支持的价格目录格式: %s（可选 litellm / csv）", opts.Format)
+	}
+}
+
+// litellmEntry LiteLLM 价格目录条目（价格单位为 USD / token）
+type litellmEntry struct {
+	Provider        string   `json:"litellm_provider"`
+	InputCost       *float64 `json:"input_cost_per_token"`
+	OutputCost      *float64 `json:"output_cost_per_token"`
+	CacheCreation   *float64 `json:"cache_creation_input_token_cost"`
+	CacheCreation1h *float64 `json:"cache_creation_input_token_cost_above_1hr"`
+	CacheRead       *float64 `json:"cache_read_input_token_cost"`
+}
+
+// parseLiteLLMCatalog 解析 LiteLLM model_prices_and_context_window.json
+func parseLiteLLMCatalog(data []byte, providers []string) ([]*store.ModelPricingRecord, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("解析 LiteLLM 价格目录失败: %w", err)
+	}
+
+	records := make([]*store.ModelPricingRecord, 0)
+	skipped := 0
+	for name, value := range raw {
+		if name == "sample_spec" {
+			continue
+		}
+
+		var entry litellmEntry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			skipped++
+			continue
+		}
+		if !containsFold(providers, entry.Provider) || entry.InputCost == nil || entry.OutputCost == nil {
+			skipped++
+			continue
+		}
+
+		record := &store.ModelPricingRecord{
+			ModelName:   name,
+			InputPrice:  perMillion(entry.InputCost),
+			OutputPrice: perMillion(entry.OutputCost),
+		}
+		record.CacheCreationPrice5m = perMillion(entry.CacheCreation)
+		record.CacheCreationPrice1h = perMillion(entry.CacheCreation1h)
+		record.CacheReadPrice = perMillion(entry.CacheRead)
+		records = append(records, record)
+	}
+
+	return records, skipped, nil
+}
+
+// parsePricingCSV 解析 CSV 价格表（首行为列名，# 开头的行为注释）
+func parsePricingCSV(data []byte) ([]*store.ModelPricingRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !containsFold(pricingCSVColumns, name) {
+			return nil, fmt.Errorf("CSV 包含未知列: %s（支持 %s）", name, strings.Join(pricingCSVColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"model_name", "input_price", "output_price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV 缺少必需列: %s", required)
+		}
+	}
+
+	var records []*store.ModelPricingRecord
+	seen := make(map[string]bool)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 CSV 失败: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		record := &store.ModelPricingRecord{
+			ModelName:   cell("model_name"),
+			DisplayName: cell("display_name"),
+			Description: cell("description"),
+		}
+		if record.ModelName == "" {
+			return nil, fmt.Errorf("第 %d 行: 模型名称不能为空", line)
+		}
+		if seen[record.ModelName] {
+			return nil, fmt.Errorf("第 %d 行: 模型 %s 重复", line, record.ModelName)
+		}
+		seen[record.ModelName] = true
+
+		prices := []struct {
+			column   string
+			target   *float64
+			required bool
+		}{
+			{"input_price", &record.InputPrice, true},
+			{"output_price", &record.OutputPrice, true},
+			{"cache_creation_price_5m", &record.CacheCreationPrice5m, false},
+			{"cache_creation_price_1h", &record.CacheCreationPrice1h, false},
+			{"cache_read_price", &record.CacheReadPrice, false},
+		}
+		for _, p := range prices {
+			value := cell(p.column)
+			if value == "" {
+				if p.required {
+					return nil, fmt.Errorf("第 %d 行: %s 不能为空", line, p.column)
+				}
+				continue
+			}
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price < 0 || math.IsInf(price, 0) || math.IsNaN(price) {
+				return nil, fmt.Errorf("第 %d 行: 无效的 %s: %s", line, p.column, value)
+			}
+			*p.target = roundPrice(price)
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV 中没有定价数据")
+	}
+	return records, nil
+}
+
+// PreviewImport 解析价格目录并与现有定价比较，不写入数据库
+func (s *ModelPricingService) PreviewImport(ctx context.Context, data []byte, opts PricingImportOptions) (*PricingImportDiff, error) {
+	imported, format, skipped, err := ParsePricingCatalog(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取现有定价失败: %w", err)
+	}
+	current := make(map[string]*store.ModelPricingRecord, len(existing))
+	for _, r := range existing {
+		current[r.ModelName] = r
+	}
+
+	diff := &PricingImportDiff{Format: format, Skipped: skipped, Changes: make([]PricingChange, 0, len(imported))}
+	for _, record := range imported {
+		old := current[record.ModelName]
+		if old == nil && opts.OnlyExisting {
+			diff.Skipped++
+			continue
+		}
+		// 不导入零价格：存储层会把零价格当作未设置并填入默认值
+		if record.InputPrice <= 0 || record.OutputPrice <= 0 {
+			diff.Skipped++
+			continue
+		}
+		diff.Changes = append(diff.Changes, diffPricing(old, record))
+	}
+
+	order := map[string]int{PricingChangeNew: 0, PricingChangeChanged: 1, PricingChangeUnchanged: 2}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if order[a.Action] != order[b.Action] {
+			return order[a.Action] < order[b.Action]
+		}
+		return a.ModelName < b.ModelName
+	})
+
+	hash := sha256.New()
+	for _, change := range diff.Changes {
+		switch change.Action {
+		case PricingChangeNew:
+			diff.New++
+		case PricingChangeChanged:
+			diff.Changed++
+		default:
+			diff.Unchanged++
+			continue
+		}
+		fmt.Fprintf(hash, "%s|%s", change.ModelName, change.Action)
+		for _, f := range change.Fields {
+			fmt.Fprintf(hash, "|%s=%v>%v", f.Field, f.Old, f.New)
+		}
+		hash.Write([]byte{'\n'})
+	}
+	diff.Total = len(diff.Changes)
+	diff.Fingerprint = hex.EncodeToString(hash.Sum(nil))[:16]
+
+	return diff, nil
+}
+
+// ApplyImport 导入价格目录：写入新增和变化的模型定价并记录审计
+// fingerprint 不为空时必须与当前预览结果一致，防止预览后定价被修改
+func (s *ModelPricingService) ApplyImport(ctx context.Context, data []byte, opts PricingImportOptions, fingerprint string) (*PricingImportDiff, *store.ModelPricingAuditRecord, error) {
+	diff, err := s.PreviewImport(ctx, data, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fingerprint != "" && fingerprint != diff.Fingerprint {
+		return nil, nil, fmt.Errorf("定价在预览后已变化，请重新预览")
+	}
+	if diff.New == 0 && diff.Changed == 0 {
+		return diff, nil, nil
+	}
+
+	var records, versioned []*store.ModelPricingRecord
+	var applied []PricingChange
+	for _, change := range diff.Changes {
+		if change.Action != PricingChangeUnchanged {
+			records = append(records, change.record)
+			applied = append(applied, change)
+		}
+		// 只修改显示名称或描述时价格不变，不产生新的定价版本
+		if change.Action == PricingChangeNew || (change.Action == PricingChangeChanged && pricingDiffers(change.old, change.record)) {
+			versioned = append(versioned, change.record)
+		}
+	}
+
+	changes, err := json.Marshal(applied)
+	if err != nil {
+		return nil, nil, fmt.Errorf("序列化导入明细失败: %w", err)
+	}
+	audit := &store.ModelPricingAuditRecord{
+		Source:    opts.Source,
+		Format:    diff.Format,
+		Added:     diff.New,
+		Changed:   diff.Changed,
+		Unchanged: diff.Unchanged,
+		Changes:   string(changes),
+	}
+
+	// 定价、定价版本和审计在同一事务中写入，任一失败时整体回滚
+	err = s.store.RunInTx(ctx, func(st store.ModelPricingStore) error {
+		if err := st.BatchUpsert(ctx, records); err != nil {
+			return fmt.Errorf("导入模型定价失败: %w", err)
+		}
+
+		// 新增和价格变化的模型从导入时起按新价格计算
+		now := time.Now()
+		for _, record := range versioned {
+			if err := s.recordVersion(ctx, st, record, now, PricingVersionImport); err != nil {
+				return err
+			}
+		}
+
+		if err := st.RecordAudit(ctx, audit); err != nil {
+			return fmt.Errorf("记录定价导入审计失败: %w", err)
+		}
+		return nil
+	})
+	s.clearCache()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.Info(fmt.Sprintf("✅ [ModelPricingService] 从 %s 目录导入模型定价: 新增 %d, 变化 %d, 未变化 %d",
+		diff.Format, diff.New, diff.Changed, diff.Unchanged))
+	return diff, audit, nil
+}
+
+// ListImportAudits 按时间倒序列出定价导入审计记录
+func (s *ModelPricingService) ListImportAudits(ctx context.Context, limit int) ([]*store.ModelPricingAuditRecord, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	records, err := s.store.ListAudits(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取定价导入审计失败: %w", err)
+	}
+	return records, nil
+}
+
+// diffPricing 比较现有定价和导入定价
+// 导入记录未提供的缓存价格保留原值（新模型或原值为空时按输入价格推算），只比较目录实际提供的价格
+// 显示名称和描述为空时保留原值，默认定价标记不变
+func diffPricing(old, imported *store.ModelPricingRecord) PricingChange {
+	merged := &store.ModelPricingRecord{ModelName: imported.ModelName}
+	if old != nil {
+		copied := *old
+		merged = &copied
+	}
+
+	merged.InputPrice = imported.InputPrice
+	merged.OutputPrice = imported.OutputPrice
+	merged.CacheCreationPrice5m = mergeCachePrice(imported.CacheCreationPrice5m, merged.CacheCreationPrice5m, imported.InputPrice, 1.25)
+	merged.CacheCreationPrice1h = mergeCachePrice(imported.CacheCreationPrice1h, merged.CacheCreationPrice1h, imported.InputPrice, 2.0)
+	merged.CacheReadPrice = mergeCachePrice(imported.CacheReadPrice, merged.CacheReadPrice, imported.InputPrice, 0.1)
+	if imported.DisplayName != "" {
+		merged.DisplayName = imported.DisplayName
+	}
+	if imported.Description != "" {
+		merged.Description = imported.Description
+	}
+
+	change := PricingChange{ModelName: merged.ModelName, record: merged, old: old}
+	if old == nil {
+		change.Action = PricingChangeNew
+		old = &store.ModelPricingRecord{}
+	}
+
+	prices := []struct {
+		field    string
+		old, new float64
+	}{
+		{"input_price", old.InputPrice, merged.InputPrice},
+		{"output_price", old.OutputPrice, merged.OutputPrice},
+		{"cache_creation_price_5m", old.CacheCreationPrice5m, merged.CacheCreationPrice5m},
+		{"cache_creation_price_1h", old.CacheCreationPrice1h, merged.CacheCreationPrice1h},
+		{"cache_read_price", old.CacheReadPrice, merged.CacheReadPrice},
+	}
+	for _, p := range prices {
+		if math.Abs(p.old-p.new) > 1e-9 {
+			change.Fields = append(change.Fields, PricingFieldChange{Field: p.field, Old: p.old, New: p.new})
+		}
+	}
+	if old.DisplayName != merged.DisplayName {
+		change.Fields = append(change.Fields, PricingFieldChange{Field: "display_name", Old: old.DisplayName, New: merged.DisplayName})
+	}
+	if old.Description != merged.Description {
+		change.Fields = append(change.Fields, PricingFieldChange{Field: "description", Old: old.Description, New: merged.Description})
+	}
+
+	if change.Action == "" {
+		change.Action = PricingChangeUnchanged
+		if len(change.Fields) > 0 {
+			change.Action = PricingChangeChanged
+		}
+	}
+	return change
+}
+
+// mergeCachePrice 目录提供了缓存价格时使用目录价格，否则保留现有价格，都没有时按输入价格的倍率推算
+func mergeCachePrice(price, existing, input, multiplier float64) float64 {
+	if price > 0 {
+		return price
+	}
+	if existing > 0 {
+		return existing
+	}
+	return roundPrice(input * multiplier)
+}
+
+// perMillion 将 USD / token 转换为 USD / 1M tokens
+func perMillion(cost *float64) float64 {
+	if cost == nil {
+		return 0
+	}
+	return roundPrice(*cost * 1_000_000)
+}
+
+// roundPrice 价格保留 6 位小数，消除单位换算带来的浮点误差
+func roundPrice(price float64) float64 {
+	return math.Round(price*1e6) / 1e6
+}
+
+// containsFold 忽略大小写判断是否包含
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"cc-forwarder/internal/store"
+)
+
+// memoryModelPricingStore 测试用内存模型定价存储（只实现导入用到的方法）
+type memoryModelPricingStore struct {
+	store.ModelPricingStore
+	records  map[string]*store.ModelPricingRecord
+	audits   []*store.ModelPricingAuditRecord
+	auditErr error // 不为空时 RecordAudit 返回该错误
+
+	versions      []*store.ModelPricingVersionRecord
+	nextVersionID int64
+	versionErr    error // 不为空时 AddVersion 返回该错误
+	updateErr     error // 不为空时 Update 返回该错误
+}
+
+func newMemoryModelPricingStore(records ...*store.ModelPricingRecord) *memoryModelPricingStore {
+	m := &memoryModelPricingStore{records: make(map[string]*store.ModelPricingRecord)}
+	for _, r := range records {
+		m.records[r.ModelName] = r
+	}
+	return m
+}
+
+func (m *memoryModelPricingStore) List(ctx context.Context) ([]*store.ModelPricingRecord, error) {
+	records := make([]*store.ModelPricingRecord, 0, len(m.records))
+	for _, r := range m.records {
+		copied := *r
+		records = append(records, &copied)
+	}
+	return records, nil
+}
+
+func (m *memoryModelPricingStore) BatchUpsert(ctx context.Context, records []*store.ModelPricingRecord) error {
+	for _, r := range records {
+		m.records[r.ModelName] = r
+	}
+	return nil
+}
+
+func (m *memoryModelPricingStore) RecordAudit(ctx context.Context, record *store.ModelPricingAuditRecord) error {
+	if m.auditErr != nil {
+		return m.auditErr
+	}
+	record.ID = int64(len(m.audits) + 1)
+	m.audits = append(m.audits, record)
+	return nil
+}
+
+// RunInTx fn 返回错误时恢复执行前的数据，模拟事务回滚
+func (m *memoryModelPricingStore) RunInTx(ctx context.Context, fn func(st store.ModelPricingStore) error) error {
+	records := maps.Clone(m.records)
+	audits := append([]*store.ModelPricingAuditRecord(nil), m.audits...)
+	versions := append([]*store.ModelPricingVersionRecord(nil), m.versions...)
+	if err := fn(m); err != nil {
+		m.records, m.audits, m.versions = records, audits, versions
+		return err
+	}
+	return nil
+}
+
+const testLiteLLMCatalog = `{
+	"sample_spec": {"input_cost_per_token": 0},
+	"claude-sonnet-4-20250514": {
+		"litellm_provider": "anthropic",
+		"input_cost_per_token": 3e-06,
+		"output_cost_per_token": 1.5e-05,
+		"cache_creation_input_token_cost": 3.75e-06,
+		"cache_read_input_token_cost": 3e-07
+	},
+	"claude-opus-4-1-20250805": {
+		"litellm_provider": "anthropic",
+		"input_cost_per_token": 1.5e-05,
+		"output_cost_per_token": 7.5e-05,
+		"cache_creation_input_token_cost": 1.875e-05,
+		"cache_creation_input_token_cost_above_1hr": 3e-05,
+		"cache_read_input_token_cost": 1.5e-06
+	},
+	"claude-haiku-new": {
+		"litellm_provider": "anthropic",
+		"input_cost_per_token": 1e-06,
+		"output_cost_per_token": 5e-06
+	},
+	"gpt-4o": {"litellm_provider": "openai", "input_cost_per_token": 2.5e-06, "output_cost_per_token": 1e-05},
+	"claude-embedding": {"litellm_provider": "anthropic", "mode": "embedding"}
+}`
+
+func TestParsePricingCatalog(t *testing.T) {
+	records, format, skipped, err := ParsePricingCatalog([]byte(testLiteLLMCatalog), PricingImportOptions{})
+	if err != nil || format != PricingFormatLiteLLM {
+		t.Fatalf("解析 LiteLLM 目录失败: %v %s", err, format)
+	}
+	// 默认只导入 anthropic，缺少价格的条目被跳过
+	if len(records) != 3 || skipped != 2 {
+		t.Fatalf("应导入 3 个模型、跳过 2 个: %d %d", len(records), skipped)
+	}
+	for _, r := range records {
+		if r.ModelName == "claude-opus-4-1-20250805" && (r.InputPrice != 15 || r.CacheCreationPrice1h != 30 || r.CacheReadPrice != 1.5) {
+			t.Errorf("单位换算不符: %+v", r)
+		}
+	}
+
+	if records, _, _, _ := ParsePricingCatalog([]byte(testLiteLLMCatalog), PricingImportOptions{Providers: []string{"OpenAI"}}); len(records) != 1 {
+		t.Errorf("按 provider 过滤不符: %d", len(records))
+	}
+
+	csvData := "\ufeffmodel_name,input_price,output_price,cache_read_price,display_name\n" +
+		"# 注释行\n" +
+		"claude-a, 3, 15, , Claude A\n" +
+		"claude-b,0.8,4,0.08,\n"
+	records, format, _, err = ParsePricingCatalog([]byte(csvData), PricingImportOptions{})
+	if err != nil || format != PricingFormatCSV || len(records) != 2 {
+		t.Fatalf("解析 CSV 失败: %v %s %d", err, format, len(records))
+	}
+	if records[0].DisplayName != "Claude A" || records[0].CacheReadPrice != 0 || records[1].CacheReadPrice != 0.08 {
+		t.Errorf("CSV 字段不符: %+v %+v", records[0], records[1])
+	}
+
+	invalid := []string{
+		"model_name,input_price\nclaude-a,3\n",
+		"model_name,input_price,output_price,price\nclaude-a,3,15,1\n",
+		"model_name,input_price,output_price\nclaude-a,abc,15\n",
+		"model_name,input_price,output_price\nclaude-a,3,15\nclaude-a,3,15\n",
+		"model_name,input_price,output_price\n",
+	}
+	for _, data := range invalid {
+		if _, _, _, err := ParsePricingCatalog([]byte(data), PricingImportOptions{Format: "csv"}); err == nil {
+			t.Errorf("应解析失败: %q", data)
+		}
+	}
+	if _, _, _, err := ParsePricingCatalog([]byte("{}"), PricingImportOptions{Format: "xml"}); err == nil {
+		t.Error("未知格式应返回错误")
+	}
+}
+
+func TestModelPricingService_PreviewAndApplyImport(t *testing.T) {
+	st := newMemoryModelPricingStore(
+		&store.ModelPricingRecord{
+			ModelName: "claude-sonnet-4-20250514", DisplayName: "Claude Sonnet 4",
+			InputPrice: 3, OutputPrice: 15, CacheCreationPrice5m: 3.75, CacheCreationPrice1h: 6, CacheReadPrice: 0.3,
+		},
+		&store.ModelPricingRecord{
+			ModelName: "claude-opus-4-1-20250805", DisplayName: "Claude Opus 4.1", IsDefault: true,
+			InputPrice: 15, OutputPrice: 60, CacheCreationPrice5m: 18.75, CacheCreationPrice1h: 30, CacheReadPrice: 1.5,
+		},
+	)
+	s := NewModelPricingService(st)
+	ctx := context.Background()
+	data := []byte(testLiteLLMCatalog)
+
+	diff, err := s.PreviewImport(ctx, data, PricingImportOptions{})
+	if err != nil {
+		t.Fatalf("预览失败: %v", err)
+	}
+	if diff.New != 1 || diff.Changed != 1 || diff.Unchanged != 1 || diff.Total != 3 {
+		t.Fatalf("预览统计不符: %+v", diff)
+	}
+	if diff.Changes[0].ModelName != "claude-haiku-new" || diff.Changes[1].Action != PricingChangeChanged {
+		t.Errorf("差异排序不符: %+v", diff.Changes)
+	}
+	opus := diff.Changes[1]
+	if len(opus.Fields) != 1 || opus.Fields[0].Field != "output_price" || opus.Fields[0].Old != 60.0 || opus.Fields[0].New != 75.0 {
+		t.Errorf("字段变化不符: %+v", opus.Fields)
+	}
+	if len(st.audits) != 0 || st.records["claude-haiku-new"] != nil {
+		t.Fatal("预览不应写入数据库")
+	}
+
+	if _, _, err := s.ApplyImport(ctx, data, PricingImportOptions{}, "stale"); err == nil {
+		t.Error("预览摘要不一致时应拒绝导入")
+	}
+
+	diff, audit, err := s.ApplyImport(ctx, data, PricingImportOptions{Source: "litellm.json"}, diff.Fingerprint)
+	if err != nil || audit == nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	// 未提供的缓存价格按输入价格推算，原有显示名称和默认标记保留
+	haiku := st.records["claude-haiku-new"]
+	if haiku == nil || haiku.CacheCreationPrice5m != 1.25 || haiku.CacheReadPrice != 0.1 {
+		t.Errorf("新增模型不符: %+v", haiku)
+	}
+	if updated := st.records["claude-opus-4-1-20250805"]; updated.OutputPrice != 75 || updated.DisplayName != "Claude Opus 4.1" || !updated.IsDefault {
+		t.Errorf("更新后的模型不符: %+v", updated)
+	}
+
+	var changes []PricingChange
+	if err := json.Unmarshal([]byte(audit.Changes), &changes); err != nil || len(changes) != 2 {
+		t.Fatalf("审计明细不符: %v %s", err, audit.Changes)
+	}
+	if audit.Source != "litellm.json" || audit.Added != 1 || audit.Changed != 1 || audit.Unchanged != 1 {
+		t.Errorf("审计记录不符: %+v", audit)
+	}
+
+	// 再次导入没有变化，不写审计
+	diff, audit, err = s.ApplyImport(ctx, data, PricingImportOptions{}, "")
+	if err != nil || audit != nil || diff.Unchanged != 3 || len(st.audits) != 1 {
+		t.Errorf("重复导入应无变化: %v %+v", err, diff)
+	}
+}
+
+func TestModelPricingService_ImportOnlyExisting(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15})
+	s := NewModelPricingService(st)
+
+	csvData := "model_name,input_price,output_price\nclaude-a,3,15\nclaude-b,1,5\nclaude-free,0,0\n"
+	diff, err := s.PreviewImport(context.Background(), []byte(csvData), PricingImportOptions{OnlyExisting: true})
+	if err != nil {
+		t.Fatalf("预览失败: %v", err)
+	}
+	// claude-a 缺少的缓存价格按倍率推算后与原值不同，记为变化
+	if diff.New != 0 || diff.Changed != 1 || diff.Skipped != 2 {
+		t.Errorf("只更新已有模型时统计不符: %+v", diff)
+	}
+	if !strings.Contains(diff.Changes[0].Fields[0].Field, "cache") {
+		t.Errorf("变化字段不符: %+v", diff.Changes[0].Fields)
+	}
+}
+
+func TestModelPricingService_ImportKeepsUnprovidedCachePrices(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{
+		ModelName: "claude-a", InputPrice: 3, OutputPrice: 15,
+		CacheCreationPrice5m: 3.75, CacheCreationPrice1h: 7, CacheReadPrice: 0.3,
+	})
+	s := NewModelPricingService(st)
+
+	// 目录未提供 1h 缓存价格，现有价格与推算值 (input×2) 不同也不计为变化
+	csvData := "model_name,input_price,output_price,cache_creation_price_5m,cache_read_price\nclaude-a,3,15,3.75,0.3\n"
+	diff, err := s.PreviewImport(context.Background(), []byte(csvData), PricingImportOptions{})
+	if err != nil {
+		t.Fatalf("预览失败: %v", err)
+	}
+	if diff.Unchanged != 1 || diff.Changed != 0 {
+		t.Errorf("未提供的缓存价格不应计为变化: %+v", diff.Changes)
+	}
+}
+
+func TestModelPricingService_ImportDescriptionOnlyKeepsVersions(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{
+		ModelName: "claude-a", Description: "旧描述", InputPrice: 3, OutputPrice: 15,
+		CacheCreationPrice5m: 3.75, CacheCreationPrice1h: 6, CacheReadPrice: 0.3,
+	})
+	s := NewModelPricingService(st)
+
+	csvData := "model_name,description,input_price,output_price\nclaude-a,新描述,3,15\n"
+	diff, _, err := s.ApplyImport(context.Background(), []byte(csvData), PricingImportOptions{}, "")
+	if err != nil || diff.Changed != 1 {
+		t.Fatalf("导入失败: %v %+v", err, diff)
+	}
+	if st.records["claude-a"].Description != "新描述" {
+		t.Errorf("描述应更新: %+v", st.records["claude-a"])
+	}
+	if len(st.versions) != 0 {
+		t.Errorf("价格未变化时不应记录定价版本: %+v", st.versions)
+	}
+}
+
+func TestModelPricingService_ApplyImportRollsBack(t *testing.T) {
+	st := newMemoryModelPricingStore(&store.ModelPricingRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15})
+	st.auditErr = errors.New("disk full")
+	s := NewModelPricingService(st)
+
+	csvData := "model_name,input_price,output_price\nclaude-a,4,20\nclaude-b,1,5\n"
+	if _, _, err := s.ApplyImport(context.Background(), []byte(csvData), PricingImportOptions{}, ""); err == nil {
+		t.Fatal("审计写入失败时导入应失败")
+	}
+	if len(st.records) != 1 || st.records["claude-a"].InputPrice != 3 || len(st.versions) != 0 {
+		t.Errorf("审计失败时定价和版本应回滚: %+v %d", st.records["claude-a"], len(st.versions))
+	}
+}
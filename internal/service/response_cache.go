@@ -0,0 +1,265 @@
+// Package service 提供业务逻辑层实现
+// 响应缓存服务 - 缓存确定性非流式请求的成功响应
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// 可缓存的请求路径
+const (
+	cacheableMessagesPath    = "/v1/messages"
+	cacheableCountTokensPath = "/v1/messages/count_tokens"
+)
+
+// uncachedResponseHeaders 不写入缓存的响应头（逐跳头部和与单次响应相关的头部）
+var uncachedResponseHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Content-Encoding":  true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Date":              true,
+	"Set-Cookie":        true,
+}
+
+// ResponseCacheStats 响应缓存状态
+type ResponseCacheStats struct {
+	Enabled    bool   `json:"enabled"`
+	TTL        string `json:"ttl"`
+	MaxEntries int    `json:"max_entries"`
+	Entries    int    `json:"entries"`
+	TotalHits  int64  `json:"total_hits"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// ResponseCacheService 响应缓存服务
+// 实现 handlers.ResponseCache，在端点选择前查找缓存，成功响应后写入缓存
+type ResponseCacheService struct {
+	store store.ResponseCacheStore
+
+	mu         sync.RWMutex
+	enabled    bool
+	ttl        time.Duration
+	maxEntries int
+}
+
+// NewResponseCacheService 创建响应缓存服务实例
+func NewResponseCacheService(st store.ResponseCacheStore) *ResponseCacheService {
+	return &ResponseCacheService{store: st}
+}
+
+// Configure 更新缓存开关、有效期和条目上限（支持热更新）
+func (s *ResponseCacheService) Configure(enabled bool, ttl time.Duration, maxEntries int) {
+	s.mu.Lock()
+	s.enabled = enabled
+	s.ttl = ttl
+	s.maxEntries = maxEntries
+	s.mu.Unlock()
+}
+
+// settings 读取当前配置
+func (s *ResponseCacheService) settings() (bool, time.Duration, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.enabled, s.ttl, s.maxEntries
+}
+
+// ResponseCacheKey 计算请求的缓存键和模型，请求不可缓存时返回 false
+// 只缓存 count_tokens 和显式指定 temperature 为 0 的非流式 /v1/messages 请求；
+// 请求体按 JSON 规范化（键排序，去掉 metadata 和 stream），与字段顺序和会话标识无关
+func ResponseCacheKey(path string, body []byte) (key, model string, ok bool) {
+	if path != cacheableMessagesPath && path != cacheableCountTokensPath {
+		return "", "", false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var request map[string]any
+	if err := decoder.Decode(&request); err != nil || request == nil {
+		return "", "", false
+	}
+
+	model, _ = request["model"].(string)
+	if path == cacheableMessagesPath {
+		if stream, _ := request["stream"].(bool); stream {
+			return "", "", false
+		}
+		temperature, isNumber := request["temperature"].(json.Number)
+		if !isNumber {
+			return "", "", false
+		}
+		if value, err := temperature.Float64(); err != nil || value != 0 {
+			return "", "", false
+		}
+		request["temperature"] = 0 // 0 与 0.0 视为相同请求
+	}
+
+	delete(request, "metadata")
+	delete(request, "stream")
+	canonical, err := json.Marshal(request)
+	if err != nil {
+		return "", "", false
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(path + "\n" + model + "\n"))
+	hash.Write(canonical)
+	return hex.EncodeToString(hash.Sum(nil)), model, true
+}
+
+// Lookup 查找请求对应的未过期缓存响应
+func (s *ResponseCacheService) Lookup(ctx context.Context, path string, body []byte) (http.Header, []byte, bool) {
+	enabled, _, _ := s.settings()
+	if !enabled {
+		return nil, nil, false
+	}
+
+	key, _, ok := ResponseCacheKey(path, body)
+	if !ok {
+		return nil, nil, false
+	}
+
+	record, err := s.store.Get(ctx, key)
+	if err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [响应缓存] 查询缓存失败: %v", err))
+		return nil, nil, false
+	}
+	if record == nil {
+		return nil, nil, false
+	}
+
+	now := time.Now()
+	if !record.ExpiresAt.After(now) {
+		if err := s.store.Delete(ctx, key); err != nil {
+			slog.Debug(fmt.Sprintf("🗑️ [响应缓存] 删除过期条目失败: %v", err))
+		}
+		return nil, nil, false
+	}
+
+	if err := s.store.RecordHit(ctx, key, now); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [响应缓存] 更新命中次数失败: %v", err))
+	}
+
+	return http.Header(record.Headers), record.Body, true
+}
+
+// Store 保存成功响应，超出条目上限时淘汰最久未使用的条目
+func (s *ResponseCacheService) Store(ctx context.Context, path string, body []byte, header http.Header, responseBody []byte) {
+	enabled, ttl, maxEntries := s.settings()
+	if !enabled || ttl <= 0 {
+		return
+	}
+
+	key, model, ok := ResponseCacheKey(path, body)
+	if !ok {
+		return
+	}
+
+	headers := make(map[string][]string, len(header))
+	for name, values := range header {
+		if !uncachedResponseHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = values
+		}
+	}
+
+	now := time.Now()
+	record := &store.ResponseCacheRecord{
+		CacheKey:  key,
+		Model:     model,
+		Path:      path,
+		Headers:   headers,
+		Body:      responseBody,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := s.store.Put(ctx, record); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [响应缓存] 写入缓存失败: %v", err))
+		return
+	}
+
+	if _, err := s.store.DeleteExpired(ctx, now); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [响应缓存] 清理过期条目失败: %v", err))
+	}
+	if maxEntries > 0 {
+		if evicted, err := s.store.Trim(ctx, maxEntries); err != nil {
+			slog.Warn(fmt.Sprintf("⚠️ [响应缓存] 淘汰缓存条目失败: %v", err))
+		} else if evicted > 0 {
+			slog.Debug(fmt.Sprintf("🗑️ [响应缓存] 超出条目上限，淘汰 %d 个条目", evicted))
+		}
+	}
+
+	slog.Info(fmt.Sprintf("💾 [响应缓存] 已缓存响应: %s, 模型: %s, 大小: %d字节", path, model, len(responseBody)))
+}
+
+// ListEntries 获取所有缓存条目（不含响应体）
+func (s *ResponseCacheService) ListEntries(ctx context.Context) ([]*store.ResponseCacheRecord, error) {
+	return s.store.List(ctx)
+}
+
+// GetEntry 获取缓存条目（含响应体）
+func (s *ResponseCacheService) GetEntry(ctx context.Context, key string) (*store.ResponseCacheRecord, error) {
+	record, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("缓存条目 '%s' %w", key, store.ErrNotFound)
+	}
+	return record, nil
+}
+
+// DeleteEntry 删除缓存条目
+func (s *ResponseCacheService) DeleteEntry(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+// Purge 清空缓存，expiredOnly 为 true 时只删除已过期的条目
+func (s *ResponseCacheService) Purge(ctx context.Context, expiredOnly bool) (int64, error) {
+	var (
+		purged int64
+		err    error
+	)
+	if expiredOnly {
+		purged, err = s.store.DeleteExpired(ctx, time.Now())
+	} else {
+		purged, err = s.store.Purge(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Info(fmt.Sprintf("🗑️ [响应缓存] 已清除 %d 个缓存条目", purged))
+	return purged, nil
+}
+
+// GetStats 获取缓存配置和条目统计
+func (s *ResponseCacheService) GetStats(ctx context.Context) (*ResponseCacheStats, error) {
+	entries, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, ttl, maxEntries := s.settings()
+	stats := &ResponseCacheStats{
+		Enabled:    enabled,
+		TTL:        ttl.String(),
+		MaxEntries: maxEntries,
+		Entries:    len(entries),
+	}
+	for _, entry := range entries {
+		stats.TotalHits += entry.HitCount
+		stats.TotalBytes += entry.BodySize
+	}
+	return stats, nil
+}
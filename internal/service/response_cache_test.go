@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/store"
+)
+
+// memoryResponseCacheStore 测试用内存响应缓存存储
+type memoryResponseCacheStore struct {
+	records map[string]*store.ResponseCacheRecord
+}
+
+func newMemoryResponseCacheStore() *memoryResponseCacheStore {
+	return &memoryResponseCacheStore{records: make(map[string]*store.ResponseCacheRecord)}
+}
+
+func (m *memoryResponseCacheStore) Get(ctx context.Context, key string) (*store.ResponseCacheRecord, error) {
+	return m.records[key], nil
+}
+
+func (m *memoryResponseCacheStore) Put(ctx context.Context, record *store.ResponseCacheRecord) error {
+	m.records[record.CacheKey] = record
+	return nil
+}
+
+func (m *memoryResponseCacheStore) List(ctx context.Context) ([]*store.ResponseCacheRecord, error) {
+	records := make([]*store.ResponseCacheRecord, 0, len(m.records))
+	for _, r := range m.records {
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (m *memoryResponseCacheStore) RecordHit(ctx context.Context, key string, at time.Time) error {
+	if r := m.records[key]; r != nil {
+		r.HitCount++
+		r.LastHitAt = &at
+	}
+	return nil
+}
+
+func (m *memoryResponseCacheStore) Delete(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func (m *memoryResponseCacheStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for key, r := range m.records {
+		if !r.ExpiresAt.After(now) {
+			delete(m.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *memoryResponseCacheStore) Trim(ctx context.Context, maxEntries int) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryResponseCacheStore) Purge(ctx context.Context) (int64, error) {
+	purged := int64(len(m.records))
+	m.records = make(map[string]*store.ResponseCacheRecord)
+	return purged, nil
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	base := `{"model":"claude-sonnet-4","temperature":0,"max_tokens":10,"messages":[{"role":"user","content":"hi"}]}`
+	baseKey, model, ok := ResponseCacheKey("/v1/messages", []byte(base))
+	if !ok {
+		t.Fatal("temperature 为 0 的非流式请求应可缓存")
+	}
+	if model != "claude-sonnet-4" {
+		t.Errorf("模型不匹配: %s", model)
+	}
+
+	// 字段顺序、metadata 和 stream:false 不影响缓存键
+	equivalent := `{"messages":[{"role":"user","content":"hi"}],"max_tokens":10,"stream":false,"metadata":{"user_id":"u1"},"temperature":0.0,"model":"claude-sonnet-4"}`
+	if key, _, ok := ResponseCacheKey("/v1/messages", []byte(equivalent)); !ok || key != baseKey {
+		t.Errorf("等价请求应得到相同缓存键: %v", ok)
+	}
+
+	different := `{"model":"claude-sonnet-4","temperature":0,"max_tokens":10,"messages":[{"role":"user","content":"hello"}]}`
+	if key, _, _ := ResponseCacheKey("/v1/messages", []byte(different)); key == baseKey {
+		t.Error("不同请求不应得到相同缓存键")
+	}
+
+	uncacheable := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"未指定 temperature", "/v1/messages", `{"model":"m","messages":[]}`},
+		{"temperature 非 0", "/v1/messages", `{"model":"m","temperature":0.7,"messages":[]}`},
+		{"流式请求", "/v1/messages", `{"model":"m","temperature":0,"stream":true,"messages":[]}`},
+		{"不支持的路径", "/v1/complete", `{"model":"m","temperature":0}`},
+		{"无效 JSON", "/v1/messages", `{`},
+	}
+	for _, tc := range uncacheable {
+		if _, _, ok := ResponseCacheKey(tc.path, []byte(tc.body)); ok {
+			t.Errorf("%s: 不应可缓存", tc.name)
+		}
+	}
+
+	// count_tokens 不要求 temperature
+	if _, _, ok := ResponseCacheKey("/v1/messages/count_tokens", []byte(`{"model":"m","messages":[]}`)); !ok {
+		t.Error("count_tokens 请求应可缓存")
+	}
+}
+
+func TestResponseCacheLookupStore(t *testing.T) {
+	st := newMemoryResponseCacheStore()
+	svc := NewResponseCacheService(st)
+	ctx := context.Background()
+	path := "/v1/messages"
+	body := []byte(`{"model":"m","temperature":0,"messages":[]}`)
+	header := http.Header{
+		"Content-Type":   {"application/json"},
+		"Content-Length": {"12"},
+		"Request-Id":     {"req_1"},
+	}
+
+	// 未启用时不缓存
+	svc.Store(ctx, path, body, header, []byte(`{"id":"m1"}`))
+	if len(st.records) != 0 {
+		t.Fatal("未启用时不应写入缓存")
+	}
+
+	svc.Configure(true, time.Hour, 10)
+	if _, _, ok := svc.Lookup(ctx, path, body); ok {
+		t.Fatal("空缓存不应命中")
+	}
+
+	svc.Store(ctx, path, body, header, []byte(`{"id":"m1"}`))
+	gotHeader, gotBody, ok := svc.Lookup(ctx, path, body)
+	if !ok {
+		t.Fatal("期望命中缓存")
+	}
+	if string(gotBody) != `{"id":"m1"}` {
+		t.Errorf("响应体不匹配: %s", gotBody)
+	}
+	if gotHeader.Get("Content-Type") != "application/json" || gotHeader.Get("Content-Length") != "" {
+		t.Errorf("响应头不匹配: %v", gotHeader)
+	}
+
+	stats, err := svc.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("获取统计失败: %v", err)
+	}
+	if stats.Entries != 1 || stats.TotalHits != 1 {
+		t.Errorf("统计不匹配: %+v", stats)
+	}
+
+	// 过期条目不命中并被删除
+	for _, r := range st.records {
+		r.ExpiresAt = time.Now().Add(-time.Second)
+	}
+	if _, _, ok := svc.Lookup(ctx, path, body); ok {
+		t.Error("过期条目不应命中")
+	}
+	if len(st.records) != 0 {
+		t.Error("过期条目应被删除")
+	}
+
+	svc.Store(ctx, path, body, header, []byte(`{"id":"m2"}`))
+	purged, err := svc.Purge(ctx, false)
+	if err != nil || purged != 1 {
+		t.Errorf("清空缓存失败: %d, %v", purged, err)
+	}
+}
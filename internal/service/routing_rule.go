@@ -0,0 +1,220 @@
+// Package service 提供业务逻辑层实现
+// 模型路由规则服务 - 按请求模型限制可用端点/渠道
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"cc-forwarder/internal/endpoint"
+	"cc-forwarder/internal/store"
+)
+
+// RoutingRuleService 模型路由规则管理服务
+// 实现 endpoint.ModelRouter，端点选择时只查内存缓存
+type RoutingRuleService struct {
+	store store.RoutingRuleStore
+
+	// 启用的规则缓存（按匹配顺序）
+	rules   []*store.RoutingRuleRecord
+	rulesMu sync.RWMutex
+}
+
+// NewRoutingRuleService 创建模型路由规则服务实例
+func NewRoutingRuleService(st store.RoutingRuleStore) *RoutingRuleService {
+	return &RoutingRuleService{store: st}
+}
+
+// HasRoutes 是否存在启用的路由规则
+func (s *RoutingRuleService) HasRoutes() bool {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+	return len(s.rules) > 0
+}
+
+// MatchModel 返回模型命中的第一条启用的路由规则
+func (s *RoutingRuleService) MatchModel(model string) (*endpoint.ModelRoute, bool) {
+	if model == "" {
+		return nil, false
+	}
+
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	for _, rule := range s.rules {
+		if MatchModelPattern(rule.ModelPattern, model) {
+			return &endpoint.ModelRoute{
+				Rule:      rule.Name,
+				Endpoints: rule.Endpoints,
+				Channels:  rule.Channels,
+				Fallback:  rule.Fallback,
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// MatchModelPattern 判断模型名称是否匹配通配符（* 匹配任意字符，? 匹配单个字符，不区分大小写）
+func MatchModelPattern(pattern, model string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	model = strings.ToLower(model)
+	if pattern == "*" {
+		return true
+	}
+
+	// 模型名称可能包含 "/"（如 anthropic/claude-sonnet-4），path.Match 的 * 不跨越 "/"，先替换掉
+	pattern = strings.ReplaceAll(pattern, "/", "\x00")
+	model = strings.ReplaceAll(model, "/", "\x00")
+
+	matched, err := path.Match(pattern, model)
+	return err == nil && matched
+}
+
+// CreateRule 创建路由规则
+func (s *RoutingRuleService) CreateRule(ctx context.Context, record *store.RoutingRuleRecord) (*store.RoutingRuleRecord, error) {
+	normalizeRoutingRule(record)
+	if err := s.validateRecord(record); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.Get(ctx, record.Name)
+	if err != nil {
+		return nil, fmt.Errorf("检查路由规则是否存在失败: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("路由规则 '%s' %w", record.Name, store.ErrAlreadyExists)
+	}
+
+	created, err := s.store.Create(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("创建路由规则失败: %w", err)
+	}
+
+	if err := s.LoadCache(ctx); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [RoutingRuleService] 刷新路由规则缓存失败: %v", err))
+	}
+
+	slog.Info(fmt.Sprintf("✅ [RoutingRuleService] 创建路由规则: %s (%s)", created.Name, created.ModelPattern))
+	return created, nil
+}
+
+// GetRule 获取路由规则
+func (s *RoutingRuleService) GetRule(ctx context.Context, name string) (*store.RoutingRuleRecord, error) {
+	record, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取路由规则失败: %w", err)
+	}
+	return record, nil
+}
+
+// ListRules 列出所有路由规则（按匹配顺序）
+func (s *RoutingRuleService) ListRules(ctx context.Context) ([]*store.RoutingRuleRecord, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("列出路由规则失败: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateRule 更新路由规则
+func (s *RoutingRuleService) UpdateRule(ctx context.Context, record *store.RoutingRuleRecord) error {
+	normalizeRoutingRule(record)
+	if err := s.validateRecord(record); err != nil {
+		return err
+	}
+
+	if err := s.store.Update(ctx, record); err != nil {
+		return fmt.Errorf("更新路由规则失败: %w", err)
+	}
+
+	if err := s.LoadCache(ctx); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [RoutingRuleService] 刷新路由规则缓存失败: %v", err))
+	}
+
+	slog.Info(fmt.Sprintf("✅ [RoutingRuleService] 更新路由规则: %s", record.Name))
+	return nil
+}
+
+// DeleteRule 删除路由规则
+func (s *RoutingRuleService) DeleteRule(ctx context.Context, name string) error {
+	if err := s.store.Delete(ctx, name); err != nil {
+		return fmt.Errorf("删除路由规则失败: %w", err)
+	}
+
+	if err := s.LoadCache(ctx); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [RoutingRuleService] 刷新路由规则缓存失败: %v", err))
+	}
+
+	slog.Info(fmt.Sprintf("✅ [RoutingRuleService] 删除路由规则: %s", name))
+	return nil
+}
+
+// LoadCache 从数据库加载启用的路由规则到缓存
+func (s *RoutingRuleService) LoadCache(ctx context.Context) error {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("加载路由规则失败: %w", err)
+	}
+
+	rules := make([]*store.RoutingRuleRecord, 0, len(records))
+	for _, record := range records {
+		if record.Enabled {
+			rules = append(rules, record)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+
+	s.rulesMu.Lock()
+	s.rules = rules
+	s.rulesMu.Unlock()
+
+	slog.Info(fmt.Sprintf("✅ [RoutingRuleService] 加载 %d 条启用的路由规则到缓存", len(rules)))
+	return nil
+}
+
+// validateRecord 验证路由规则记录
+func (s *RoutingRuleService) validateRecord(record *store.RoutingRuleRecord) error {
+	if record.Name == "" {
+		return fmt.Errorf("路由规则名称不能为空")
+	}
+	if record.ModelPattern == "" {
+		return fmt.Errorf("模型匹配规则不能为空")
+	}
+	if _, err := path.Match(strings.ToLower(record.ModelPattern), ""); err != nil {
+		return fmt.Errorf("模型匹配规则无效: %s", record.ModelPattern)
+	}
+	if len(record.Endpoints) == 0 && len(record.Channels) == 0 {
+		return fmt.Errorf("至少需要指定一个允许的端点或渠道")
+	}
+	return nil
+}
+
+// normalizeRoutingRule 去除名称、通配符和目标列表中的空白项
+func normalizeRoutingRule(record *store.RoutingRuleRecord) {
+	record.Name = strings.TrimSpace(record.Name)
+	record.ModelPattern = strings.TrimSpace(record.ModelPattern)
+	record.Endpoints = compactNames(record.Endpoints)
+	record.Channels = compactNames(record.Channels)
+}
+
+// compactNames 去除空白和重复的名称
+func compactNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cc-forwarder/internal/store"
+)
+
+// memoryRoutingRuleStore 测试用内存路由规则存储
+type memoryRoutingRuleStore struct {
+	records []*store.RoutingRuleRecord
+}
+
+func (m *memoryRoutingRuleStore) Create(ctx context.Context, record *store.RoutingRuleRecord) (*store.RoutingRuleRecord, error) {
+	m.records = append(m.records, record)
+	return record, nil
+}
+
+func (m *memoryRoutingRuleStore) Get(ctx context.Context, name string) (*store.RoutingRuleRecord, error) {
+	for _, r := range m.records {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRoutingRuleStore) List(ctx context.Context) ([]*store.RoutingRuleRecord, error) {
+	return m.records, nil
+}
+
+func (m *memoryRoutingRuleStore) Update(ctx context.Context, record *store.RoutingRuleRecord) error {
+	for i, r := range m.records {
+		if r.Name == record.Name {
+			m.records[i] = record
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryRoutingRuleStore) Delete(ctx context.Context, name string) error {
+	for i, r := range m.records {
+		if r.Name == name {
+			m.records = append(m.records[:i], m.records[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestMatchModelPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		model   string
+		want    bool
+	}{
+		{"*", "claude-sonnet-4-20250514", true},
+		{"claude-opus-*", "claude-opus-4-1-20250805", true},
+		{"claude-opus-*", "claude-sonnet-4-20250514", false},
+		{"*sonnet*", "claude-3-7-sonnet-20250219", true},
+		{"*SONNET*", "claude-sonnet-4", true},
+		{"claude-3-?-sonnet*", "claude-3-5-sonnet-20241022", true},
+		{"claude-haiku-4-5", "claude-haiku-4-5", true},
+		{"claude-haiku-4-5", "claude-haiku-4-5-20251001", false},
+		{"*sonnet*", "anthropic/claude-sonnet-4", true},
+		{"[", "claude", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchModelPattern(tt.pattern, tt.model); got != tt.want {
+			t.Errorf("MatchModelPattern(%q, %q) = %v, want %v", tt.pattern, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestRoutingRuleService_MatchModel(t *testing.T) {
+	st := &memoryRoutingRuleStore{}
+	svc := NewRoutingRuleService(st)
+	ctx := context.Background()
+
+	if svc.HasRoutes() {
+		t.Error("没有规则时 HasRoutes 应为 false")
+	}
+
+	rules := []*store.RoutingRuleRecord{
+		{Name: "opus", ModelPattern: "claude-opus-*", Endpoints: []string{"opus-relay"}, Priority: 2, Enabled: true},
+		{Name: "sonnet", ModelPattern: "*sonnet*", Channels: []string{" 官方 ", ""}, Priority: 1, Fallback: true, Enabled: true},
+		{Name: "all", ModelPattern: "*", Endpoints: []string{"default"}, Priority: 9, Enabled: false},
+	}
+	for _, r := range rules {
+		if _, err := svc.CreateRule(ctx, r); err != nil {
+			t.Fatalf("创建规则失败: %v", err)
+		}
+	}
+
+	if !svc.HasRoutes() {
+		t.Fatal("存在启用的规则时 HasRoutes 应为 true")
+	}
+
+	route, ok := svc.MatchModel("claude-opus-4-1-20250805")
+	if !ok || route.Rule != "opus" || route.Endpoints[0] != "opus-relay" || route.Fallback {
+		t.Errorf("opus 模型匹配结果不正确: %+v", route)
+	}
+
+	route, ok = svc.MatchModel("claude-sonnet-4-20250514")
+	if !ok || route.Rule != "sonnet" || len(route.Channels) != 1 || route.Channels[0] != "官方" || !route.Fallback {
+		t.Errorf("sonnet 模型匹配结果不正确: %+v", route)
+	}
+
+	// 禁用的规则不参与匹配
+	if route, ok := svc.MatchModel("claude-haiku-4-5"); ok {
+		t.Errorf("不应命中禁用的规则: %+v", route)
+	}
+
+	// 删除规则后缓存同步更新
+	if err := svc.DeleteRule(ctx, "opus"); err != nil {
+		t.Fatalf("删除规则失败: %v", err)
+	}
+	if _, ok := svc.MatchModel("claude-opus-4-1-20250805"); ok {
+		t.Error("删除后不应再命中规则")
+	}
+}
+
+func TestRoutingRuleService_Validate(t *testing.T) {
+	svc := NewRoutingRuleService(&memoryRoutingRuleStore{})
+	ctx := context.Background()
+
+	invalid := []*store.RoutingRuleRecord{
+		{Name: "", ModelPattern: "*", Endpoints: []string{"a"}},
+		{Name: "no-pattern", ModelPattern: " ", Endpoints: []string{"a"}},
+		{Name: "bad-pattern", ModelPattern: "claude-[", Endpoints: []string{"a"}},
+		{Name: "no-target", ModelPattern: "*", Endpoints: []string{" "}},
+	}
+	for _, r := range invalid {
+		if _, err := svc.CreateRule(ctx, r); err == nil {
+			t.Errorf("规则 %q 应校验失败", r.Name)
+		}
+	}
+
+	if _, err := svc.CreateRule(ctx, &store.RoutingRuleRecord{Name: "dup", ModelPattern: "*", Endpoints: []string{"a"}}); err != nil {
+		t.Fatalf("创建规则失败: %v", err)
+	}
+	if _, err := svc.CreateRule(ctx, &store.RoutingRuleRecord{Name: "dup", ModelPattern: "*", Endpoints: []string{"a"}}); err == nil {
+		t.Error("重复名称应创建失败")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"cc-forwarder/config"
 	"cc-forwarder/internal/store"
 )
 
@@ -22,6 +23,11 @@ const (
 	CategoryStreaming     = "streaming"
 	CategoryAuth          = "auth"
 	CategoryTokenCounting = "token_counting"
+	CategoryOpenAICompat  = "openai_compat"
+	CategoryBudget        = "budget"
+	CategoryKeyRotation   = "key_rotation"
+	CategoryResponseCache = "response_cache"
+	CategoryCircuit       = "circuit_breaker"
 	CategoryRetention     = "retention"
 	CategoryHotPool       = "hot_pool"
 	CategoryServer        = "server"
@@ -35,8 +41,20 @@ const (
 	ValueTypeBool     = "bool"
 	ValueTypeDuration = "duration"
 	ValueTypeJSON     = "json"
+	ValueTypeText     = "text" // 多行文本（如 YAML/JSON 格式的规则）
 )
 
+// secretSettings 敏感设置（category.key），管理 API 响应中不返回其值
+var secretSettings = map[string]bool{
+	CategoryAuth + ".token":       true,
+	CategoryAuth + ".admin_token": true,
+}
+
+// IsSecretSetting 判断设置是否为敏感设置
+func IsSecretSetting(category, key string) bool {
+	return secretSettings[category+"."+key]
+}
+
 // CategoryInfo 分类信息
 type CategoryInfo struct {
 	Name        string `json:"name"`
@@ -109,6 +127,41 @@ func NewSettingsService(store store.SettingsStore) *SettingsService {
 				Icon:        "🔢",
 				Order:       7,
 			},
+			CategoryOpenAICompat: {
+				Name:        CategoryOpenAICompat,
+				Label:       "OpenAI 兼容",
+				Description: "配置 /v1/chat/completions 兼容接口",
+				Icon:        "🔌",
+				Order:       9,
+			},
+			CategoryBudget: {
+				Name:        CategoryBudget,
+				Label:       "预算",
+				Description: "配置消费预算的执行和刷新",
+				Icon:        "💰",
+				Order:       10,
+			},
+			CategoryKeyRotation: {
+				Name:        CategoryKeyRotation,
+				Label:       "Key 轮换",
+				Description: "配置多 Token/API Key 端点的自动轮换",
+				Icon:        "🔑",
+				Order:       11,
+			},
+			CategoryResponseCache: {
+				Name:        CategoryResponseCache,
+				Label:       "响应缓存",
+				Description: "配置确定性非流式请求的响应缓存",
+				Icon:        "🗄️",
+				Order:       12,
+			},
+			CategoryCircuit: {
+				Name:        CategoryCircuit,
+				Label:       "熔断器",
+				Description: "按真实请求的错误率和延迟熔断端点",
+				Icon:        "🧯",
+				Order:       13,
+			},
 			CategoryRetention: {
 				Name:        CategoryRetention,
 				Label:       "数据保留",
@@ -230,8 +283,31 @@ func (s *SettingsService) GetAll(ctx context.Context) ([]*store.SettingRecord, e
 	return s.store.GetAll(ctx)
 }
 
+// validateValue 校验需要解析的设置值，避免保存后应用时才发现无效
+func validateValue(category, key, value string) error {
+	if category == CategoryRetry && key == "policy" {
+		if _, err := config.ParseRetryPolicy(value); err != nil {
+			return fmt.Errorf("重试策略无效: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRecords 批量校验设置值
+func validateRecords(records []*store.SettingRecord) error {
+	for _, record := range records {
+		if err := validateValue(record.Category, record.Key, record.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Set 设置单个值
 func (s *SettingsService) Set(ctx context.Context, category, key, value string) error {
+	if err := validateValue(category, key, value); err != nil {
+		return err
+	}
 	if err := s.store.Set(ctx, category, key, value); err != nil {
 		return err
 	}
@@ -241,12 +317,18 @@ func (s *SettingsService) Set(ctx context.Context, category, key, value string)
 
 // BatchSet 批量设置（不触发回调，需手动触发）
 func (s *SettingsService) BatchSet(ctx context.Context, records []*store.SettingRecord) error {
+	if err := validateRecords(records); err != nil {
+		return err
+	}
 	return s.store.BatchSet(ctx, records)
 }
 
 // UpdateAndApply 批量更新并应用（触发热更新）
 // 只更新 value，保留 label、description 等元数据
 func (s *SettingsService) UpdateAndApply(ctx context.Context, records []*store.SettingRecord) error {
+	if err := validateRecords(records); err != nil {
+		return err
+	}
 	if err := s.store.BatchUpdateValues(ctx, records); err != nil {
 		return fmt.Errorf("保存设置失败: %w", err)
 	}
@@ -342,6 +424,21 @@ func (s *SettingsService) GetAllDefaults() []*store.SettingRecord {
 	// TokenCounting 设置
 	defaults = append(defaults, s.getDefaultsForCategory(CategoryTokenCounting)...)
 
+	// OpenAI 兼容设置
+	defaults = append(defaults, s.getDefaultsForCategory(CategoryOpenAICompat)...)
+
+	// 预算设置
+	defaults = append(defaults, s.getDefaultsForCategory(CategoryBudget)...)
+
+	// Key 轮换设置
+	defaults = append(defaults, s.getDefaultsForCategory(CategoryKeyRotation)...)
+
+	// 响应缓存设置
+	defaults = append(defaults, s.getDefaultsForCategory(CategoryResponseCache)...)
+
+	// 熔断器设置
+	defaults = append(defaults, s.getDefaultsForCategory(CategoryCircuit)...)
+
 	// Retention 设置
 	defaults = append(defaults, s.getDefaultsForCategory(CategoryRetention)...)
 
@@ -358,11 +455,12 @@ func (s *SettingsService) getDefaultsForCategory(category string) []*store.Setti
 
 	case CategoryStrategy:
 		return []*store.SettingRecord{
-			{Category: CategoryStrategy, Key: "type", Value: "priority", ValueType: ValueTypeString, Label: "策略类型", Description: "路由策略: priority (优先级) 或 fastest (最快响应)", DisplayOrder: 1},
+			{Category: CategoryStrategy, Key: "type", Value: "priority", ValueType: ValueTypeString, Label: "策略类型", Description: "路由策略: priority (优先级)、fastest (最快响应)、weighted (同优先级按权重)、least_outstanding (最少在途请求) 或 ewma (真实请求延迟)", DisplayOrder: 1},
 			{Category: CategoryStrategy, Key: "fast_test_enabled", Value: "true", ValueType: ValueTypeBool, Label: "启用快速测试", Description: "仅在 fastest 策略下生效", DisplayOrder: 2},
 			{Category: CategoryStrategy, Key: "fast_test_cache_ttl", Value: "3s", ValueType: ValueTypeDuration, Label: "缓存时间", Description: "快速测试结果缓存时间", DisplayOrder: 3},
 			{Category: CategoryStrategy, Key: "fast_test_timeout", Value: "1s", ValueType: ValueTypeDuration, Label: "测试超时", Description: "快速测试超时时间", DisplayOrder: 4},
 			{Category: CategoryStrategy, Key: "fast_test_path", Value: "/v1/models", ValueType: ValueTypeString, Label: "测试路径", Description: "快速测试请求路径", DisplayOrder: 5},
+			{Category: CategoryStrategy, Key: "rate_limit_reserve", Value: "0.1", ValueType: ValueTypeFloat, Label: "限流余量", Description: "上游返回的剩余请求/Token 额度低于上限的该比例时，端点排到选择顺序末尾 (0~1)", DisplayOrder: 6},
 		}
 
 	case CategoryRetry:
@@ -371,6 +469,7 @@ func (s *SettingsService) getDefaultsForCategory(category string) []*store.Setti
 			{Category: CategoryRetry, Key: "base_delay", Value: "1s", ValueType: ValueTypeDuration, Label: "基础延迟", Description: "首次重试前的等待时间", DisplayOrder: 2},
 			{Category: CategoryRetry, Key: "max_delay", Value: "30s", ValueType: ValueTypeDuration, Label: "最大延迟", Description: "重试延迟的上限", DisplayOrder: 3},
 			{Category: CategoryRetry, Key: "multiplier", Value: "2.0", ValueType: ValueTypeFloat, Label: "延迟倍数", Description: "每次重试延迟的倍增系数", DisplayOrder: 4},
+			{Category: CategoryRetry, Key: "policy", Value: "", ValueType: ValueTypeText, Label: "重试策略", Description: "按状态码或错误类型配置重试动作的规则数组（YAML/JSON），为空时使用内置默认策略", DisplayOrder: 5},
 		}
 
 	case CategoryHealth:
@@ -407,6 +506,7 @@ func (s *SettingsService) getDefaultsForCategory(category string) []*store.Setti
 		return []*store.SettingRecord{
 			{Category: CategoryAuth, Key: "enabled", Value: "false", ValueType: ValueTypeBool, Label: "启用鉴权", Description: "是否启用 API 访问鉴权", DisplayOrder: 1},
 			{Category: CategoryAuth, Key: "token", Value: "", ValueType: ValueTypeString, Label: "鉴权 Token", Description: "Bearer Token 值", DisplayOrder: 2},
+			{Category: CategoryAuth, Key: "admin_token", Value: "", ValueType: ValueTypeString, Label: "管理 API Token", Description: "/admin/api/v1 管理接口的 Bearer Token，为空时关闭管理 API", DisplayOrder: 3},
 		}
 
 	case CategoryTokenCounting:
@@ -415,6 +515,44 @@ func (s *SettingsService) getDefaultsForCategory(category string) []*store.Setti
 			{Category: CategoryTokenCounting, Key: "estimation_ratio", Value: "4.0", ValueType: ValueTypeFloat, Label: "估算比例", Description: "Token 估算比例 (1 token ≈ N 字符)", DisplayOrder: 2},
 		}
 
+	case CategoryOpenAICompat:
+		return []*store.SettingRecord{
+			{Category: CategoryOpenAICompat, Key: "enabled", Value: "true", ValueType: ValueTypeBool, Label: "启用 OpenAI 兼容接口", Description: "将 /v1/chat/completions 请求转换为 Anthropic Messages 格式转发", DisplayOrder: 1},
+			{Category: CategoryOpenAICompat, Key: "default_max_tokens", Value: "4096", ValueType: ValueTypeInt, Label: "默认 max_tokens", Description: "请求未指定 max_tokens 时使用的值", DisplayOrder: 2},
+		}
+
+	case CategoryBudget:
+		return []*store.SettingRecord{
+			{Category: CategoryBudget, Key: "enabled", Value: "true", ValueType: ValueTypeBool, Label: "启用预算执行", Description: "超过硬限额时拒绝新请求，超过软限额时发送提醒事件", DisplayOrder: 1},
+			{Category: CategoryBudget, Key: "refresh_interval", Value: "10s", ValueType: ValueTypeDuration, Label: "刷新间隔", Description: "统计各预算当前周期消费的间隔", DisplayOrder: 2},
+		}
+
+	case CategoryKeyRotation:
+		return []*store.SettingRecord{
+			{Category: CategoryKeyRotation, Key: "enabled", Value: "true", ValueType: ValueTypeBool, Label: "启用自动轮换", Description: "上游返回 429/额度耗尽时冷却当前 Key，返回 401/403 时标记 Key 无效，并自动切换到下一个可用 Key", DisplayOrder: 1},
+			{Category: CategoryKeyRotation, Key: "strategy", Value: "failover", ValueType: ValueTypeString, Label: "分配策略", Description: "failover (故障时切换)、round_robin (轮询) 或 least_used (最少使用)", DisplayOrder: 2},
+			{Category: CategoryKeyRotation, Key: "cooldown", Value: "60s", ValueType: ValueTypeDuration, Label: "冷却时间", Description: "上游未返回 Retry-After 时 Key 的冷却时间", DisplayOrder: 3},
+		}
+
+	case CategoryResponseCache:
+		return []*store.SettingRecord{
+			{Category: CategoryResponseCache, Key: "enabled", Value: "false", ValueType: ValueTypeBool, Label: "启用响应缓存", Description: "缓存 temperature 为 0 的非流式 /v1/messages 请求和 count_tokens 请求的成功响应，命中时不转发、不计成本", DisplayOrder: 1},
+			{Category: CategoryResponseCache, Key: "ttl", Value: "1h", ValueType: ValueTypeDuration, Label: "缓存有效期", Description: "缓存条目的存活时间", DisplayOrder: 2},
+			{Category: CategoryResponseCache, Key: "max_entries", Value: "1000", ValueType: ValueTypeInt, Label: "最大条目数", Description: "超出后淘汰最久未命中的条目", DisplayOrder: 3},
+		}
+
+	case CategoryCircuit:
+		return []*store.SettingRecord{
+			{Category: CategoryCircuit, Key: "enabled", Value: "true", ValueType: ValueTypeBool, Label: "启用熔断器", Description: "统计窗口内错误率或慢请求比例超过阈值时打开熔断，端点暂停参与选择", DisplayOrder: 1},
+			{Category: CategoryCircuit, Key: "window", Value: "60s", ValueType: ValueTypeDuration, Label: "统计窗口", Description: "错误率和慢请求比例的滚动统计窗口", DisplayOrder: 2},
+			{Category: CategoryCircuit, Key: "min_requests", Value: "10", ValueType: ValueTypeInt, Label: "最少请求数", Description: "窗口内请求数达到该值才判定是否熔断", DisplayOrder: 3},
+			{Category: CategoryCircuit, Key: "error_rate", Value: "0.5", ValueType: ValueTypeFloat, Label: "错误率阈值", Description: "5xx、429 和连接错误占比达到该值时打开熔断 (0~1)", DisplayOrder: 4},
+			{Category: CategoryCircuit, Key: "slow_call_duration", Value: "60s", ValueType: ValueTypeDuration, Label: "慢请求阈值", Description: "响应头耗时超过该值计为慢请求", DisplayOrder: 5},
+			{Category: CategoryCircuit, Key: "slow_call_rate", Value: "0.8", ValueType: ValueTypeFloat, Label: "慢请求比例阈值", Description: "慢请求占比达到该值时打开熔断 (0~1)", DisplayOrder: 6},
+			{Category: CategoryCircuit, Key: "open_duration", Value: "30s", ValueType: ValueTypeDuration, Label: "熔断时长", Description: "打开后经过该时间进入半开状态", DisplayOrder: 7},
+			{Category: CategoryCircuit, Key: "half_open_requests", Value: "3", ValueType: ValueTypeInt, Label: "试探请求数", Description: "半开状态放行的试探请求数，全部成功后关闭，任一失败重新打开", DisplayOrder: 8},
+		}
+
 	case CategoryRetention:
 		return []*store.SettingRecord{
 			{Category: CategoryRetention, Key: "retention_days", Value: "0", ValueType: ValueTypeInt, Label: "数据保留天数", Description: "请求日志保留天数，0 表示永久保留", DisplayOrder: 1},
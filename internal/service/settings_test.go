@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cc-forwarder/internal/store"
+)
+
+// recordingSettingsStore 测试用设置存储：只记录批量更新的值
+type recordingSettingsStore struct {
+	store.SettingsStore
+	updated []*store.SettingRecord
+}
+
+func (m *recordingSettingsStore) BatchUpdateValues(ctx context.Context, records []*store.SettingRecord) error {
+	m.updated = append(m.updated, records...)
+	return nil
+}
+
+func TestSettingsService_RejectsInvalidRetryPolicy(t *testing.T) {
+	st := &recordingSettingsStore{}
+	s := NewSettingsService(st)
+	ctx := context.Background()
+
+	invalid := []*store.SettingRecord{{Category: CategoryRetry, Key: "policy", Value: "- match: \"404\"\n  action: explode"}}
+	if err := s.UpdateAndApply(ctx, invalid); err == nil {
+		t.Fatal("无效的重试策略应返回错误")
+	}
+	if err := s.Set(ctx, CategoryRetry, "policy", "not: [valid"); err == nil {
+		t.Fatal("无法解析的重试策略应返回错误")
+	}
+	if len(st.updated) != 0 {
+		t.Fatalf("无效的重试策略不应保存: %+v", st.updated)
+	}
+
+	valid := []*store.SettingRecord{{Category: CategoryRetry, Key: "policy", Value: "- match: \"404\"\n  action: switch"}}
+	if err := s.UpdateAndApply(ctx, valid); err != nil || len(st.updated) != 1 {
+		t.Errorf("有效的重试策略应保存: %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+// Package service 提供业务逻辑层实现
+// Webhook 服务 - 订阅 EventBus 运维事件并推送到外部 Webhook
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cc-forwarder/internal/events"
+	"cc-forwarder/internal/store"
+	"cc-forwarder/internal/webhook"
+)
+
+// Webhook 投递参数
+const (
+	// 同时进行的投递数上限
+	maxConcurrentWebhookDeliveries = 4
+	// 投递记录保留条数
+	maxWebhookDeliveries = 1000
+	// 单条 Webhook 允许的最大重试次数
+	maxWebhookRetries = 10
+	// 默认重试次数
+	DefaultWebhookMaxRetries = 3
+)
+
+// WebhookService Webhook 管理与投递服务
+type WebhookService struct {
+	store  store.WebhookStore
+	sender *webhook.Sender
+
+	// 启用的 Webhook 缓存
+	hooks   []*store.WebhookRecord
+	hooksMu sync.RWMutex
+
+	// 端点最近一次的健康状态，用于只在状态变化时通知（健康检查每次都会发布事件）
+	endpointHealth   map[string]bool
+	endpointHealthMu sync.Mutex
+
+	sem         chan struct{}
+	unsubscribe func()
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewWebhookService 创建 Webhook 服务实例
+func NewWebhookService(st store.WebhookStore) *WebhookService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookService{
+		store:          st,
+		sender:         webhook.NewSender(0),
+		endpointHealth: make(map[string]bool),
+		sem:            make(chan struct{}, maxConcurrentWebhookDeliveries),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Start 订阅 EventBus 事件并开始投递
+func (s *WebhookService) Start(eventBus events.EventBus) {
+	if eventBus == nil || s.unsubscribe != nil {
+		return
+	}
+
+	ch, unsubscribe := eventBus.Subscribe(events.SubscriptionFilter{
+		Name:  "webhook_service",
+		Types: webhook.SupportedEventTypes(),
+	})
+	s.unsubscribe = unsubscribe
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for event := range ch {
+			s.dispatch(event)
+		}
+	}()
+}
+
+// Stop 取消订阅并等待进行中的投递结束（未完成的重试被取消）
+func (s *WebhookService) Stop() {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// dispatch 将事件异步投递到订阅了该事件类型的 Webhook
+func (s *WebhookService) dispatch(event events.Event) {
+	if !s.shouldNotify(event) {
+		return
+	}
+
+	s.hooksMu.RLock()
+	var targets []*store.WebhookRecord
+	for _, hook := range s.hooks {
+		if subscribesTo(hook, event.Type) {
+			targets = append(targets, hook)
+		}
+	}
+	s.hooksMu.RUnlock()
+
+	for _, hook := range targets {
+		s.wg.Add(1)
+		go func(hook *store.WebhookRecord) {
+			defer s.wg.Done()
+
+			select {
+			case s.sem <- struct{}{}:
+				defer func() { <-s.sem }()
+			case <-s.ctx.Done():
+				return
+			}
+			s.deliver(s.ctx, hook, event)
+		}(hook)
+	}
+}
+
+// shouldNotify 过滤重复的端点健康事件：只在健康状态变化时通知，首次检查为健康时不通知
+func (s *WebhookService) shouldNotify(event events.Event) bool {
+	if event.Type != events.EventEndpointHealthy && event.Type != events.EventEndpointUnhealthy {
+		return true
+	}
+	name, _ := event.Data["endpoint"].(string)
+	healthy := event.Type == events.EventEndpointHealthy
+
+	s.endpointHealthMu.Lock()
+	defer s.endpointHealthMu.Unlock()
+
+	previous, known := s.endpointHealth[name]
+	s.endpointHealth[name] = healthy
+	if !known {
+		return !healthy
+	}
+	return previous != healthy
+}
+
+// subscribesTo Webhook 是否订阅了事件类型（未指定时订阅默认告警事件）
+func subscribesTo(hook *store.WebhookRecord, eventType events.EventType) bool {
+	if len(hook.EventTypes) == 0 {
+		for _, t := range webhook.DefaultEventTypes {
+			if t == eventType {
+				return true
+			}
+		}
+		return false
+	}
+	for _, t := range hook.EventTypes {
+		if t == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver 发送一条事件通知并记录投递结果
+func (s *WebhookService) deliver(ctx context.Context, hook *store.WebhookRecord, event events.Event) *store.WebhookDeliveryRecord {
+	target := webhook.Target{
+		Name:       hook.Name,
+		URL:        hook.URL,
+		Format:     hook.Format,
+		Secret:     hook.Secret,
+		MaxRetries: hook.MaxRetries,
+	}
+	result := s.sender.Send(ctx, target, webhook.NewMessage(event, hook.Template))
+
+	record := &store.WebhookDeliveryRecord{
+		Webhook:    hook.Name,
+		EventType:  string(event.Type),
+		Success:    result.Success,
+		Attempts:   result.Attempts,
+		StatusCode: result.StatusCode,
+		Error:      result.Error,
+		Payload:    result.Payload,
+		DurationMs: result.Duration.Milliseconds(),
+		CreatedAt:  time.Now(),
+	}
+
+	if result.Success {
+		slog.Debug(fmt.Sprintf("📤 [Webhook] 投递成功: %s (%s), 尝试 %d 次", hook.Name, event.Type, result.Attempts))
+	} else {
+		slog.Warn(fmt.Sprintf("⚠️ [Webhook] 投递失败: %s (%s), 尝试 %d 次: %s", hook.Name, event.Type, result.Attempts, result.Error))
+	}
+
+	// 服务停止时 ctx 已取消，投递记录使用独立的超时上下文
+	recordCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.store.RecordDelivery(recordCtx, record); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [Webhook] 记录投递结果失败: %v", err))
+		return record
+	}
+	if _, err := s.store.TrimDeliveries(recordCtx, maxWebhookDeliveries); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [Webhook] 清理投递记录失败: %v", err))
+	}
+	return record
+}
+
+// SendTest 向指定 Webhook 同步发送一条测试通知（禁用的 Webhook 也可测试）
+func (s *WebhookService) SendTest(ctx context.Context, name string) (*store.WebhookDeliveryRecord, error) {
+	hook, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取 Webhook 失败: %w", err)
+	}
+	if hook == nil {
+		return nil, fmt.Errorf("Webhook '%s' %w", name, store.ErrNotFound)
+	}
+
+	event := events.Event{
+		Type:      webhook.EventTest,
+		Source:    "webhook_service",
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"webhook": hook.Name},
+	}
+	return s.deliver(ctx, hook, event), nil
+}
+
+// ListDeliveries 列出投递记录（webhook 为空时列出全部）
+func (s *WebhookService) ListDeliveries(ctx context.Context, name string, limit int) ([]*store.WebhookDeliveryRecord, error) {
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = 100
+	}
+	records, err := s.store.ListDeliveries(ctx, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("列出投递记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// ============================================================
+// Webhook 管理
+// ============================================================
+
+// CreateWebhook 创建 Webhook
+func (s *WebhookService) CreateWebhook(ctx context.Context, record *store.WebhookRecord) (*store.WebhookRecord, error) {
+	normalizeWebhook(record)
+	if err := s.validateRecord(record); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.Get(ctx, record.Name)
+	if err != nil {
+		return nil, fmt.Errorf("检查 Webhook 是否存在失败: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("Webhook '%s' %w", record.Name, store.ErrAlreadyExists)
+	}
+
+	created, err := s.store.Create(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Webhook 失败: %w", err)
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [WebhookService] 创建 Webhook: %s (%s)", created.Name, created.Format))
+	return created, nil
+}
+
+// GetWebhook 获取 Webhook
+func (s *WebhookService) GetWebhook(ctx context.Context, name string) (*store.WebhookRecord, error) {
+	record, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取 Webhook 失败: %w", err)
+	}
+	return record, nil
+}
+
+// ListWebhooks 列出所有 Webhook
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*store.WebhookRecord, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("列出 Webhook 失败: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateWebhook 更新 Webhook
+func (s *WebhookService) UpdateWebhook(ctx context.Context, record *store.WebhookRecord) error {
+	normalizeWebhook(record)
+	if err := s.validateRecord(record); err != nil {
+		return err
+	}
+
+	if err := s.store.Update(ctx, record); err != nil {
+		return fmt.Errorf("更新 Webhook 失败: %w", err)
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [WebhookService] 更新 Webhook: %s", record.Name))
+	return nil
+}
+
+// DeleteWebhook 删除 Webhook
+func (s *WebhookService) DeleteWebhook(ctx context.Context, name string) error {
+	if err := s.store.Delete(ctx, name); err != nil {
+		return fmt.Errorf("删除 Webhook 失败: %w", err)
+	}
+
+	s.reload(ctx)
+
+	slog.Info(fmt.Sprintf("✅ [WebhookService] 删除 Webhook: %s", name))
+	return nil
+}
+
+// LoadCache 从数据库加载启用的 Webhook 到缓存
+func (s *WebhookService) LoadCache(ctx context.Context) error {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("加载 Webhook 失败: %w", err)
+	}
+
+	hooks := make([]*store.WebhookRecord, 0, len(records))
+	for _, record := range records {
+		if record.Enabled {
+			hooks = append(hooks, record)
+		}
+	}
+
+	s.hooksMu.Lock()
+	s.hooks = hooks
+	s.hooksMu.Unlock()
+
+	slog.Info(fmt.Sprintf("✅ [WebhookService] 加载 %d 个启用的 Webhook 到缓存", len(hooks)))
+	return nil
+}
+
+// reload 重新加载缓存
+func (s *WebhookService) reload(ctx context.Context) {
+	if err := s.LoadCache(ctx); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️ [WebhookService] 刷新 Webhook 缓存失败: %v", err))
+	}
+}
+
+// validateRecord 验证 Webhook 记录
+func (s *WebhookService) validateRecord(record *store.WebhookRecord) error {
+	if record.Name == "" {
+		return fmt.Errorf("Webhook 名称不能为空")
+	}
+	u, err := url.Parse(record.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Webhook URL 无效: %s", record.URL)
+	}
+	if !webhook.IsSupportedFormat(record.Format) {
+		return fmt.Errorf("不支持的负载格式: %s（可选 json / slack / feishu / dingtalk）", record.Format)
+	}
+	for _, eventType := range record.EventTypes {
+		if !webhook.IsSupportedEventType(eventType) {
+			return fmt.Errorf("不支持的事件类型: %s", eventType)
+		}
+	}
+	if err := webhook.ValidateTemplate(record.Template); err != nil {
+		return err
+	}
+	if record.MaxRetries < 0 || record.MaxRetries > maxWebhookRetries {
+		return fmt.Errorf("重试次数必须在 0-%d 之间", maxWebhookRetries)
+	}
+	return nil
+}
+
+// normalizeWebhook 去除名称和 URL 的空白，格式默认为通用 JSON
+func normalizeWebhook(record *store.WebhookRecord) {
+	record.Name = strings.TrimSpace(record.Name)
+	record.URL = strings.TrimSpace(record.URL)
+	record.Format = strings.ToLower(strings.TrimSpace(record.Format))
+	if record.Format == "" {
+		record.Format = webhook.FormatJSON
+	}
+	record.EventTypes = compactNames(record.EventTypes)
+}
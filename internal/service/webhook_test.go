@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/events"
+	"cc-forwarder/internal/store"
+)
+
+// memoryWebhookStore 测试用内存 Webhook 存储
+type memoryWebhookStore struct {
+	mu         sync.Mutex
+	records    []*store.WebhookRecord
+	deliveries []*store.WebhookDeliveryRecord
+}
+
+func (m *memoryWebhookStore) Create(ctx context.Context, record *store.WebhookRecord) (*store.WebhookRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+	return record, nil
+}
+
+func (m *memoryWebhookStore) Get(ctx context.Context, name string) (*store.WebhookRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.records {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryWebhookStore) List(ctx context.Context) ([]*store.WebhookRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*store.WebhookRecord(nil), m.records...), nil
+}
+
+func (m *memoryWebhookStore) Update(ctx context.Context, record *store.WebhookRecord) error {
+	return nil
+}
+
+func (m *memoryWebhookStore) Delete(ctx context.Context, name string) error {
+	return nil
+}
+
+func (m *memoryWebhookStore) RecordDelivery(ctx context.Context, record *store.WebhookDeliveryRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, record)
+	return nil
+}
+
+func (m *memoryWebhookStore) ListDeliveries(ctx context.Context, webhook string, limit int) ([]*store.WebhookDeliveryRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []*store.WebhookDeliveryRecord
+	for i := len(m.deliveries) - 1; i >= 0 && len(records) < limit; i-- {
+		if webhook == "" || m.deliveries[i].Webhook == webhook {
+			records = append(records, m.deliveries[i])
+		}
+	}
+	return records, nil
+}
+
+func (m *memoryWebhookStore) TrimDeliveries(ctx context.Context, keep int) (int64, error) {
+	return 0, nil
+}
+
+// webhookReceiver 本地 Webhook 接收端，记录收到的事件类型
+func webhookReceiver(t *testing.T) (*httptest.Server, chan string) {
+	t.Helper()
+	received := make(chan string, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload struct {
+			Event string `json:"event"`
+		}
+		json.Unmarshal(body, &payload)
+		received <- payload.Event
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestWebhookService_Validation(t *testing.T) {
+	s := NewWebhookService(&memoryWebhookStore{})
+	ctx := context.Background()
+
+	invalid := []*store.WebhookRecord{
+		{Name: "", URL: "http://localhost"},
+		{Name: "a", URL: "ftp://localhost"},
+		{Name: "a", URL: "http://localhost", Format: "teams"},
+		{Name: "a", URL: "http://localhost", EventTypes: []string{"request_started"}},
+		{Name: "a", URL: "http://localhost", Template: "{{.Title"},
+		{Name: "a", URL: "http://localhost", MaxRetries: 11},
+	}
+	for _, record := range invalid {
+		if _, err := s.CreateWebhook(ctx, record); err == nil {
+			t.Errorf("应校验失败: %+v", record)
+		}
+	}
+
+	created, err := s.CreateWebhook(ctx, &store.WebhookRecord{Name: " ops ", URL: " https://example.com/hook ", Format: "Slack"})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if created.Name != "ops" || created.Format != "slack" || created.URL != "https://example.com/hook" {
+		t.Errorf("规范化不符: %+v", created)
+	}
+	if _, err := s.CreateWebhook(ctx, &store.WebhookRecord{Name: "ops", URL: "http://localhost"}); err == nil {
+		t.Error("重复名称应创建失败")
+	}
+}
+
+func TestWebhookService_DispatchesSubscribedEvents(t *testing.T) {
+	server, received := webhookReceiver(t)
+	st := &memoryWebhookStore{}
+	s := NewWebhookService(st)
+	s.sender.SetBackoff(time.Millisecond, time.Millisecond)
+	ctx := context.Background()
+
+	// 默认订阅告警事件；禁用的 Webhook 不投递
+	if _, err := s.CreateWebhook(ctx, &store.WebhookRecord{Name: "ops", URL: server.URL, Enabled: true}); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if _, err := s.CreateWebhook(ctx, &store.WebhookRecord{Name: "off", URL: server.URL, Enabled: false}); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	bus := events.NewEventBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	bus.Start()
+	defer bus.Stop()
+	s.Start(bus)
+
+	unhealthy := events.Event{Type: events.EventEndpointUnhealthy, Data: map[string]interface{}{"endpoint": "primary"}}
+	bus.Publish(events.Event{Type: events.EventEndpointHealthy, Data: map[string]interface{}{"endpoint": "primary"}})
+	bus.Publish(unhealthy)
+	bus.Publish(unhealthy) // 持续不健康不重复通知
+	bus.Publish(events.Event{Type: events.EventRequestCompleted})
+	bus.Publish(events.Event{Type: events.EventGroupCooldown, Data: map[string]interface{}{"group": "main"}})
+
+	got := map[string]int{}
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-received:
+			got[event]++
+		case <-time.After(2 * time.Second):
+			t.Fatalf("等待投递超时: %v", got)
+		}
+	}
+
+	// 等待投递结果写入记录后再停止，避免取消进行中的请求
+	var deliveries []*store.WebhookDeliveryRecord
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if deliveries, _ = s.ListDeliveries(ctx, "ops", 10); len(deliveries) == 2 {
+			break
+		}
+	}
+	s.Stop()
+
+	if got["endpoint_unhealthy"] != 1 || got["group_cooldown"] != 1 || len(received) != 0 {
+		t.Errorf("投递的事件不符: %v, 多余 %d 条", got, len(received))
+	}
+	if len(deliveries) != 2 || !deliveries[0].Success || !deliveries[1].Success || deliveries[0].Attempts != 1 {
+		t.Errorf("投递记录不符: %d 条", len(deliveries))
+	}
+}
+
+func TestWebhookService_SendTest(t *testing.T) {
+	server, received := webhookReceiver(t)
+	s := NewWebhookService(&memoryWebhookStore{})
+	ctx := context.Background()
+
+	if _, err := s.CreateWebhook(ctx, &store.WebhookRecord{Name: "ops", URL: server.URL, Enabled: false}); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	delivery, err := s.SendTest(ctx, "ops")
+	if err != nil || !delivery.Success || delivery.EventType != "webhook_test" {
+		t.Fatalf("测试发送失败: %v %+v", err, delivery)
+	}
+	if event := <-received; event != "webhook_test" {
+		t.Errorf("收到的事件不符: %s", event)
+	}
+
+	if _, err := s.SendTest(ctx, "nobody"); err == nil {
+		t.Error("不存在的 Webhook 应返回错误")
+	}
+}
@@ -0,0 +1,229 @@
+// Package store 提供数据存储层实现
+// 预算存储 - 按周期限制消费金额和 Token 用量
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 预算作用范围
+const (
+	BudgetScopeGlobal    = "global"     // 全部请求
+	BudgetScopeEndpoint  = "endpoint"   // 指定端点
+	BudgetScopeChannel   = "channel"    // 指定渠道
+	BudgetScopeClientKey = "client_key" // 指定客户端 Key
+)
+
+// 预算周期
+const (
+	BudgetPeriodDaily   = "daily"
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+)
+
+// BudgetRecord 表示数据库中的预算记录
+// 限额为 0 表示不限制该项
+type BudgetRecord struct {
+	ID int64 `json:"id"`
+
+	// 基本信息
+	Name        string `json:"name"`                  // 唯一名称
+	Scope       string `json:"scope"`                 // global/endpoint/channel/client_key
+	Target      string `json:"target"`                // 端点名/渠道名/客户端 Key 名称（global 为空）
+	Period      string `json:"period"`                // daily/weekly/monthly
+	Description string `json:"description,omitempty"` // 备注
+
+	// 软限额：超过时发送事件提醒
+	SoftLimitUSD    float64 `json:"soft_limit_usd"`
+	SoftLimitTokens int64   `json:"soft_limit_tokens"`
+
+	// 硬限额：超过时拒绝新请求
+	HardLimitUSD    float64 `json:"hard_limit_usd"`
+	HardLimitTokens int64   `json:"hard_limit_tokens"`
+
+	Enabled bool `json:"enabled"`
+
+	// 审计字段
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BudgetStore 定义预算存储接口
+type BudgetStore interface {
+	Create(ctx context.Context, record *BudgetRecord) (*BudgetRecord, error)
+	Get(ctx context.Context, name string) (*BudgetRecord, error)
+	List(ctx context.Context) ([]*BudgetRecord, error)
+	Update(ctx context.Context, record *BudgetRecord) error
+	Delete(ctx context.Context, name string) error
+}
+
+// SQLiteBudgetStore 实现 BudgetStore 接口
+type SQLiteBudgetStore struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+// NewSQLiteBudgetStore 创建新的 SQLite 预算存储
+func NewSQLiteBudgetStore(db *sql.DB) *SQLiteBudgetStore {
+	return &SQLiteBudgetStore{db: db}
+}
+
+// budgetColumns 预算查询列
+const budgetColumns = `id, name, scope, COALESCE(target, ''), period, COALESCE(description, ''),
+	soft_limit_usd, soft_limit_tokens, hard_limit_usd, hard_limit_tokens,
+	enabled, created_at, updated_at`
+
+// Create 创建预算
+func (s *SQLiteBudgetStore) Create(ctx context.Context, record *BudgetRecord) (*BudgetRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		INSERT INTO budgets (
+			name, scope, target, period, description,
+			soft_limit_usd, soft_limit_tokens, hard_limit_usd, hard_limit_tokens, enabled
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Name, record.Scope, record.Target, record.Period, record.Description,
+		record.SoftLimitUSD, record.SoftLimitTokens, record.HardLimitUSD, record.HardLimitTokens,
+		boolToInt(record.Enabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建预算失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = time.Now()
+
+	return record, nil
+}
+
+// Get 根据名称获取预算
+func (s *SQLiteBudgetStore) Get(ctx context.Context, name string) (*BudgetRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + budgetColumns + " FROM budgets WHERE name = ?"
+	return scanBudget(s.db.QueryRowContext(ctx, query, name))
+}
+
+// List 获取所有预算
+func (s *SQLiteBudgetStore) List(ctx context.Context) ([]*BudgetRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + budgetColumns + " FROM budgets ORDER BY scope ASC, target ASC, name ASC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询预算失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*BudgetRecord
+	for rows.Next() {
+		record, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历预算记录失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// Update 更新预算（按名称匹配）
+func (s *SQLiteBudgetStore) Update(ctx context.Context, record *BudgetRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		UPDATE budgets SET
+			scope = ?, target = ?, period = ?, description = ?,
+			soft_limit_usd = ?, soft_limit_tokens = ?, hard_limit_usd = ?, hard_limit_tokens = ?,
+			enabled = ?
+		WHERE name = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Scope, record.Target, record.Period, record.Description,
+		record.SoftLimitUSD, record.SoftLimitTokens, record.HardLimitUSD, record.HardLimitTokens,
+		boolToInt(record.Enabled), record.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("更新预算失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("预算 '%s' %w", record.Name, ErrNotFound)
+	}
+
+	record.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 删除预算
+func (s *SQLiteBudgetStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM budgets WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("删除预算失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("预算 '%s' %w", name, ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanBudget 扫描单条预算记录（sql.Row 或 sql.Rows）
+func scanBudget(row interface{ Scan(dest ...any) error }) (*BudgetRecord, error) {
+	var record BudgetRecord
+	var enabled int
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&record.ID, &record.Name, &record.Scope, &record.Target, &record.Period, &record.Description,
+		&record.SoftLimitUSD, &record.SoftLimitTokens, &record.HardLimitUSD, &record.HardLimitTokens,
+		&enabled, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("扫描预算记录失败: %w", err)
+	}
+
+	record.Enabled = enabled == 1
+	record.CreatedAt, _ = parseStoreTime(createdAt)
+	record.UpdatedAt, _ = parseStoreTime(updatedAt)
+
+	return &record, nil
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// createBudgetTestDB 创建预算测试数据库
+func createBudgetTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "budget_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS budgets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			scope TEXT NOT NULL DEFAULT 'global',
+			target TEXT DEFAULT '',
+			period TEXT NOT NULL DEFAULT 'monthly',
+			description TEXT,
+			soft_limit_usd REAL DEFAULT 0,
+			soft_limit_tokens INTEGER DEFAULT 0,
+			hard_limit_usd REAL DEFAULT 0,
+			hard_limit_tokens INTEGER DEFAULT 0,
+			enabled INTEGER DEFAULT 1,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestBudgetCRUD 测试预算增删改查
+func TestBudgetCRUD(t *testing.T) {
+	db, cleanup := createBudgetTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteBudgetStore(db)
+	ctx := context.Background()
+
+	_, err := s.Create(ctx, &BudgetRecord{
+		Name:         "team-monthly",
+		Scope:        BudgetScopeGlobal,
+		Period:       BudgetPeriodMonthly,
+		Description:  "团队月度预算",
+		SoftLimitUSD: 80,
+		HardLimitUSD: 100,
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	if _, err := s.Create(ctx, &BudgetRecord{
+		Name:            "alice-daily",
+		Scope:           BudgetScopeClientKey,
+		Target:          "alice",
+		Period:          BudgetPeriodDaily,
+		HardLimitTokens: 1000000,
+		Enabled:         true,
+	}); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	// 重复名称应失败
+	if _, err := s.Create(ctx, &BudgetRecord{Name: "alice-daily", Scope: BudgetScopeGlobal, Period: BudgetPeriodDaily}); err == nil {
+		t.Error("重复名称应创建失败")
+	}
+
+	got, err := s.Get(ctx, "team-monthly")
+	if err != nil || got == nil {
+		t.Fatalf("获取失败: %v", err)
+	}
+	if got.Scope != BudgetScopeGlobal || got.Target != "" || got.Period != BudgetPeriodMonthly ||
+		got.SoftLimitUSD != 80 || got.HardLimitUSD != 100 || !got.Enabled || got.Description != "团队月度预算" {
+		t.Errorf("记录字段不匹配: %+v", got)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("创建时间未解析")
+	}
+
+	missing, err := s.Get(ctx, "nobody")
+	if err != nil || missing != nil {
+		t.Errorf("不存在的记录应返回 nil, nil: %v, %+v", err, missing)
+	}
+
+	// 更新：调整限额并禁用
+	got.HardLimitUSD = 150
+	got.Enabled = false
+	if err := s.Update(ctx, got); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	updated, _ := s.Get(ctx, "team-monthly")
+	if updated.HardLimitUSD != 150 || updated.Enabled {
+		t.Errorf("更新未生效: %+v", updated)
+	}
+
+	if err := s.Update(ctx, &BudgetRecord{Name: "nobody"}); err == nil {
+		t.Error("更新不存在的记录应返回错误")
+	}
+
+	// 列表按 scope、target、name 排序
+	list, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "alice-daily" || list[1].Name != "team-monthly" {
+		t.Errorf("列表不匹配: %d 条", len(list))
+	}
+
+	if err := s.Delete(ctx, "alice-daily"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if err := s.Delete(ctx, "alice-daily"); err == nil {
+		t.Error("删除不存在的记录应返回错误")
+	}
+}
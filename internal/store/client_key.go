@@ -0,0 +1,267 @@
+// Package store 提供数据存储层实现
+// 客户端 API Key 存储 - 多客户端鉴权与按 Key 统计
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// storeTimeLayout 时间字段的存储格式（与各表审计字段的默认值一致）
+const storeTimeLayout = "2006-01-02 15:04:05.999999-07:00"
+
+// ClientKeyRecord 表示数据库中的客户端 API Key 记录
+type ClientKeyRecord struct {
+	ID int64 `json:"id"`
+
+	// 基本信息
+	Name        string `json:"name"`                  // 唯一名称（记录到 request_logs.client_key_name）
+	Key         string `json:"key"`                   // Bearer Token
+	Description string `json:"description,omitempty"` // 备注
+
+	// 状态
+	Enabled   bool       `json:"enabled"`              // 是否启用
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 过期时间，nil 表示永不过期
+
+	// 审计字段
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsExpired 判断 Key 在指定时间是否已过期
+func (r *ClientKeyRecord) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// ClientKeyStore 定义客户端 Key 存储接口
+type ClientKeyStore interface {
+	// CRUD 操作
+	Create(ctx context.Context, record *ClientKeyRecord) (*ClientKeyRecord, error)
+	Get(ctx context.Context, name string) (*ClientKeyRecord, error)
+	GetByKey(ctx context.Context, key string) (*ClientKeyRecord, error)
+	List(ctx context.Context) ([]*ClientKeyRecord, error)
+	Update(ctx context.Context, record *ClientKeyRecord) error
+	Delete(ctx context.Context, name string) error
+
+	// 统计
+	Count(ctx context.Context) (int, error)
+}
+
+// SQLiteClientKeyStore 实现 ClientKeyStore 接口
+type SQLiteClientKeyStore struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+// NewSQLiteClientKeyStore 创建新的 SQLite 客户端 Key 存储
+func NewSQLiteClientKeyStore(db *sql.DB) *SQLiteClientKeyStore {
+	return &SQLiteClientKeyStore{db: db}
+}
+
+// Create 创建客户端 Key
+func (s *SQLiteClientKeyStore) Create(ctx context.Context, record *ClientKeyRecord) (*ClientKeyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		INSERT INTO client_keys (name, key, description, enabled, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Name, record.Key, record.Description,
+		boolToInt(record.Enabled), formatNullableTime(record.ExpiresAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端 Key 失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = time.Now()
+
+	return record, nil
+}
+
+// Get 根据名称获取客户端 Key
+func (s *SQLiteClientKeyStore) Get(ctx context.Context, name string) (*ClientKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT id, name, key, COALESCE(description, ''), enabled, expires_at, created_at, updated_at
+		FROM client_keys WHERE name = ?
+	`
+
+	return scanClientKey(s.db.QueryRowContext(ctx, query, name))
+}
+
+// GetByKey 根据 Key 值获取客户端 Key
+func (s *SQLiteClientKeyStore) GetByKey(ctx context.Context, key string) (*ClientKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT id, name, key, COALESCE(description, ''), enabled, expires_at, created_at, updated_at
+		FROM client_keys WHERE key = ?
+	`
+
+	return scanClientKey(s.db.QueryRowContext(ctx, query, key))
+}
+
+// List 获取所有客户端 Key
+func (s *SQLiteClientKeyStore) List(ctx context.Context) ([]*ClientKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT id, name, key, COALESCE(description, ''), enabled, expires_at, created_at, updated_at
+		FROM client_keys
+		ORDER BY name ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询客户端 Key 失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ClientKeyRecord
+	for rows.Next() {
+		record, err := scanClientKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历客户端 Key 记录失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// Update 更新客户端 Key（按名称匹配）
+func (s *SQLiteClientKeyStore) Update(ctx context.Context, record *ClientKeyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		UPDATE client_keys SET
+			key = ?, description = ?, enabled = ?, expires_at = ?
+		WHERE name = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Key, record.Description, boolToInt(record.Enabled),
+		formatNullableTime(record.ExpiresAt), record.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("更新客户端 Key 失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("客户端 Key '%s' %w", record.Name, ErrNotFound)
+	}
+
+	record.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 删除客户端 Key
+func (s *SQLiteClientKeyStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM client_keys WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("删除客户端 Key 失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("客户端 Key '%s' %w", name, ErrNotFound)
+	}
+
+	return nil
+}
+
+// Count 获取客户端 Key 总数
+func (s *SQLiteClientKeyStore) Count(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM client_keys").Scan(&count); err != nil {
+		return 0, fmt.Errorf("获取客户端 Key 数量失败: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanClientKey 扫描单条客户端 Key 记录（sql.Row 或 sql.Rows）
+func scanClientKey(row interface{ Scan(dest ...any) error }) (*ClientKeyRecord, error) {
+	var record ClientKeyRecord
+	var enabled int
+	var expiresAt sql.NullString
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&record.ID, &record.Name, &record.Key, &record.Description,
+		&enabled, &expiresAt, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("扫描客户端 Key 记录失败: %w", err)
+	}
+
+	record.Enabled = enabled == 1
+	if expiresAt.Valid && expiresAt.String != "" {
+		if t, ok := parseStoreTime(expiresAt.String); ok {
+			record.ExpiresAt = &t
+		}
+	}
+
+	// 解析时间
+	record.CreatedAt, _ = parseStoreTime(createdAt)
+	record.UpdatedAt, _ = parseStoreTime(updatedAt)
+
+	return &record, nil
+}
+
+// parseStoreTime 解析时间字段
+// SQLite 驱动会把 DATETIME 列转换为 time.Time 再以 RFC3339 格式扫描到字符串，两种格式都需支持
+func parseStoreTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, storeTimeLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// formatNullableTime 格式化可空时间，nil 存储为 NULL
+func formatNullableTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(storeTimeLayout), Valid: true}
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// createClientKeyTestDB 创建客户端 Key 测试数据库
+func createClientKeyTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "client_key_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS client_keys (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			key TEXT UNIQUE NOT NULL,
+			description TEXT,
+			enabled INTEGER DEFAULT 1,
+			expires_at DATETIME,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestClientKeyCRUD 测试客户端 Key 增删改查
+func TestClientKeyCRUD(t *testing.T) {
+	db, cleanup := createClientKeyTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteClientKeyStore(db)
+	ctx := context.Background()
+
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	_, err := s.Create(ctx, &ClientKeyRecord{
+		Name:        "alice",
+		Key:         "ccf-alice-key",
+		Description: "Alice 的笔记本",
+		Enabled:     true,
+		ExpiresAt:   &expires,
+	})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	if _, err := s.Create(ctx, &ClientKeyRecord{Name: "bob", Key: "ccf-bob-key", Enabled: true}); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	// 重复 Key 值应失败
+	if _, err := s.Create(ctx, &ClientKeyRecord{Name: "carol", Key: "ccf-bob-key", Enabled: true}); err == nil {
+		t.Error("重复 Key 值应创建失败")
+	}
+
+	got, err := s.Get(ctx, "alice")
+	if err != nil || got == nil {
+		t.Fatalf("获取失败: %v", err)
+	}
+	if got.Key != "ccf-alice-key" || got.Description != "Alice 的笔记本" || !got.Enabled {
+		t.Errorf("记录字段不匹配: %+v", got)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("过期时间不匹配: got %v, want %v", got.ExpiresAt, expires)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("创建时间未解析")
+	}
+
+	byKey, err := s.GetByKey(ctx, "ccf-bob-key")
+	if err != nil || byKey == nil || byKey.Name != "bob" {
+		t.Fatalf("按 Key 值获取失败: %v, %+v", err, byKey)
+	}
+	if byKey.ExpiresAt != nil {
+		t.Error("未设置过期时间时应为 nil")
+	}
+
+	missing, err := s.Get(ctx, "nobody")
+	if err != nil || missing != nil {
+		t.Errorf("不存在的记录应返回 nil, nil: %v, %+v", err, missing)
+	}
+
+	// 更新：禁用并清除过期时间
+	got.Enabled = false
+	got.ExpiresAt = nil
+	if err := s.Update(ctx, got); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	updated, _ := s.Get(ctx, "alice")
+	if updated.Enabled || updated.ExpiresAt != nil {
+		t.Errorf("更新未生效: %+v", updated)
+	}
+
+	list, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "alice" || list[1].Name != "bob" {
+		t.Errorf("列表不匹配: %d 条", len(list))
+	}
+
+	if err := s.Delete(ctx, "bob"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if err := s.Delete(ctx, "bob"); err == nil {
+		t.Error("删除不存在的记录应返回错误")
+	}
+
+	count, err := s.Count(ctx)
+	if err != nil || count != 1 {
+		t.Errorf("数量不匹配: %d, %v", count, err)
+	}
+}
+
+// TestClientKeyIsExpired 测试过期判断
+func TestClientKeyIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{"永不过期", nil, false},
+		{"已过期", &past, true},
+		{"未过期", &future, false},
+		{"恰好到期", &now, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ClientKeyRecord{ExpiresAt: tt.expiresAt}
+			if got := r.IsExpired(now); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -28,12 +28,20 @@ type EndpointRecord struct {
 
 	// 路由配置
 	Priority        int  `json:"priority"`         // 优先级（数字越小越高）
+	Weight          int  `json:"weight"`           // 权重（weighted 策略下同优先级内按权重分配流量）
 	FailoverEnabled bool `json:"failover_enabled"` // 是否参与故障转移
 	CooldownSeconds *int `json:"cooldown_seconds"` // 冷却时间（秒，nil=使用全局配置）
 	TimeoutSeconds  int  `json:"timeout_seconds"`  // 请求超时（秒）
 
+	// 容量限制（0=不限制）
+	MaxConcurrency int `json:"max_concurrency"` // 最大并发请求数
+	RPM            int `json:"rpm"`             // 每分钟请求数上限
+	TPM            int `json:"tpm"`             // 每分钟 Token 数上限
+
 	// 功能支持
-	SupportsCountTokens bool `json:"supports_count_tokens"` // 是否支持 count_tokens
+	SupportsCountTokens bool              `json:"supports_count_tokens"`   // 是否支持 count_tokens
+	ModelMapping        map[string]string `json:"model_mapping,omitempty"` // 模型名称映射（客户端模型 -> 上游模型）
+	RetryPolicy         string            `json:"retry_policy,omitempty"`  // 端点级重试策略规则（YAML/JSON 文本）
 
 	// 成本倍率
 	CostMultiplier                float64 `json:"cost_multiplier"`
@@ -46,11 +54,25 @@ type EndpointRecord struct {
 	// 状态
 	Enabled bool `json:"enabled"`
 
+	// 网络配置
+	Proxy *EndpointProxy `json:"proxy,omitempty"` // 端点级代理（nil=使用全局代理，Enabled=false 表示直连）
+
 	// 审计字段
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// EndpointProxy 端点级上游代理配置（以 JSON 存储在 proxy_config 列）
+type EndpointProxy struct {
+	Enabled  bool   `json:"enabled"`
+	Type     string `json:"type"` // "http", "https", "socks5"
+	URL      string `json:"url,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
 // EndpointStore 定义端点存储接口
 type EndpointStore interface {
 	// CRUD 操作
@@ -110,6 +132,14 @@ func (s *SQLiteEndpointStore) Create(ctx context.Context, record *EndpointRecord
 	if err != nil {
 		return nil, fmt.Errorf("序列化 headers 失败: %w", err)
 	}
+	proxyJSON, err := marshalEndpointProxy(record.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	modelMappingJSON, err := marshalModelMapping(record.ModelMapping)
+	if err != nil {
+		return nil, err
+	}
 
 	// 设置默认值
 	if record.CostMultiplier == 0 {
@@ -133,25 +163,30 @@ func (s *SQLiteEndpointStore) Create(ctx context.Context, record *EndpointRecord
 	if record.TimeoutSeconds == 0 {
 		record.TimeoutSeconds = 300
 	}
+	if record.Weight <= 0 {
+		record.Weight = 1
+	}
 
 	query := `
 		INSERT INTO endpoints (
 			channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			enabled, proxy_config
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := s.getQuerier().ExecContext(ctx, query,
 		record.Channel, record.Name, record.URL, record.Token, record.ApiKey, string(headersJSON),
-		record.Priority, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
-		boolToInt(record.SupportsCountTokens),
+		record.Priority, record.Weight, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
+		boolToInt(record.SupportsCountTokens), modelMappingJSON, nullableText(record.RetryPolicy),
+		record.MaxConcurrency, record.RPM, record.TPM,
 		record.CostMultiplier, record.InputCostMultiplier, record.OutputCostMultiplier,
 		record.CacheCreationCostMultiplier, record.CacheCreationCostMultiplier1h, record.CacheReadCostMultiplier,
-		boolToInt(record.Enabled),
+		boolToInt(record.Enabled), proxyJSON,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("创建端点失败: %w", err)
@@ -176,11 +211,12 @@ func (s *SQLiteEndpointStore) Get(ctx context.Context, name string) (*EndpointRe
 
 	query := `
 		SELECT id, channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled, created_at, updated_at
+			enabled, proxy_config, created_at, updated_at
 		FROM endpoints WHERE name = ?
 	`
 
@@ -194,11 +230,12 @@ func (s *SQLiteEndpointStore) GetByID(ctx context.Context, id int64) (*EndpointR
 
 	query := `
 		SELECT id, channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled, created_at, updated_at
+			enabled, proxy_config, created_at, updated_at
 		FROM endpoints WHERE id = ?
 	`
 
@@ -212,11 +249,12 @@ func (s *SQLiteEndpointStore) List(ctx context.Context) ([]*EndpointRecord, erro
 
 	query := `
 		SELECT id, channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled, created_at, updated_at
+			enabled, proxy_config, created_at, updated_at
 		FROM endpoints
 		ORDER BY priority ASC, channel ASC, name ASC
 	`
@@ -234,25 +272,38 @@ func (s *SQLiteEndpointStore) Update(ctx context.Context, record *EndpointRecord
 	if err != nil {
 		return fmt.Errorf("序列化 headers 失败: %w", err)
 	}
+	proxyJSON, err := marshalEndpointProxy(record.Proxy)
+	if err != nil {
+		return err
+	}
+	modelMappingJSON, err := marshalModelMapping(record.ModelMapping)
+	if err != nil {
+		return err
+	}
+	if record.Weight <= 0 {
+		record.Weight = 1
+	}
 
 	query := `
 		UPDATE endpoints SET
 			channel = ?, url = ?, token = ?, api_key = ?, headers = ?,
-			priority = ?, failover_enabled = ?, cooldown_seconds = ?, timeout_seconds = ?,
-			supports_count_tokens = ?,
+			priority = ?, weight = ?, failover_enabled = ?, cooldown_seconds = ?, timeout_seconds = ?,
+			supports_count_tokens = ?, model_mapping = ?, retry_policy = ?,
+			max_concurrency = ?, rpm = ?, tpm = ?,
 			cost_multiplier = ?, input_cost_multiplier = ?, output_cost_multiplier = ?,
 			cache_creation_cost_multiplier = ?, cache_creation_cost_multiplier_1h = ?, cache_read_cost_multiplier = ?,
-			enabled = ?
+			enabled = ?, proxy_config = ?
 		WHERE name = ?
 	`
 
 	result, err := s.getQuerier().ExecContext(ctx, query,
 		record.Channel, record.URL, record.Token, record.ApiKey, string(headersJSON),
-		record.Priority, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
-		boolToInt(record.SupportsCountTokens),
+		record.Priority, record.Weight, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
+		boolToInt(record.SupportsCountTokens), modelMappingJSON, nullableText(record.RetryPolicy),
+		record.MaxConcurrency, record.RPM, record.TPM,
 		record.CostMultiplier, record.InputCostMultiplier, record.OutputCostMultiplier,
 		record.CacheCreationCostMultiplier, record.CacheCreationCostMultiplier1h, record.CacheReadCostMultiplier,
-		boolToInt(record.Enabled),
+		boolToInt(record.Enabled), proxyJSON,
 		record.Name,
 	)
 	if err != nil {
@@ -265,7 +316,7 @@ func (s *SQLiteEndpointStore) Update(ctx context.Context, record *EndpointRecord
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("端点不存在: %s", record.Name)
+		return fmt.Errorf("端点%w: %s", ErrNotFound, record.Name)
 	}
 
 	return nil
@@ -289,7 +340,7 @@ func (s *SQLiteEndpointStore) Delete(ctx context.Context, name string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("端点不存在: %s", name)
+		return fmt.Errorf("端点%w: %s", ErrNotFound, name)
 	}
 
 	return nil
@@ -314,12 +365,13 @@ func (s *SQLiteEndpointStore) BatchCreate(ctx context.Context, records []*Endpoi
 	query := `
 		INSERT INTO endpoints (
 			channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			enabled, proxy_config
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, err := tx.PrepareContext(ctx, query)
@@ -351,19 +403,31 @@ func (s *SQLiteEndpointStore) BatchCreate(ctx context.Context, records []*Endpoi
 		if record.TimeoutSeconds == 0 {
 			record.TimeoutSeconds = 300
 		}
+		if record.Weight <= 0 {
+			record.Weight = 1
+		}
 
 		headersJSON, err := json.Marshal(record.Headers)
 		if err != nil {
 			return fmt.Errorf("序列化 headers 失败: %w", err)
 		}
+		proxyJSON, err := marshalEndpointProxy(record.Proxy)
+		if err != nil {
+			return err
+		}
+		modelMappingJSON, err := marshalModelMapping(record.ModelMapping)
+		if err != nil {
+			return err
+		}
 
 		_, err = stmt.ExecContext(ctx,
 			record.Channel, record.Name, record.URL, record.Token, record.ApiKey, string(headersJSON),
-			record.Priority, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
-			boolToInt(record.SupportsCountTokens),
+			record.Priority, record.Weight, boolToInt(record.FailoverEnabled), record.CooldownSeconds, record.TimeoutSeconds,
+			boolToInt(record.SupportsCountTokens), modelMappingJSON, nullableText(record.RetryPolicy),
+			record.MaxConcurrency, record.RPM, record.TPM,
 			record.CostMultiplier, record.InputCostMultiplier, record.OutputCostMultiplier,
 			record.CacheCreationCostMultiplier, record.CacheCreationCostMultiplier1h, record.CacheReadCostMultiplier,
-			boolToInt(record.Enabled),
+			boolToInt(record.Enabled), proxyJSON,
 		)
 		if err != nil {
 			return fmt.Errorf("插入端点 %s 失败: %w", record.Name, err)
@@ -410,11 +474,12 @@ func (s *SQLiteEndpointStore) ListByChannel(ctx context.Context, channel string)
 
 	query := `
 		SELECT id, channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled, created_at, updated_at
+			enabled, proxy_config, created_at, updated_at
 		FROM endpoints
 		WHERE channel = ?
 		ORDER BY priority ASC, name ASC
@@ -430,11 +495,12 @@ func (s *SQLiteEndpointStore) ListEnabled(ctx context.Context) ([]*EndpointRecor
 
 	query := `
 		SELECT id, channel, name, url, token, api_key, headers,
-			priority, failover_enabled, cooldown_seconds, timeout_seconds,
-			supports_count_tokens,
+			priority, weight, failover_enabled, cooldown_seconds, timeout_seconds,
+			supports_count_tokens, model_mapping, retry_policy,
+			max_concurrency, rpm, tpm,
 			cost_multiplier, input_cost_multiplier, output_cost_multiplier,
 			cache_creation_cost_multiplier, cache_creation_cost_multiplier_1h, cache_read_cost_multiplier,
-			enabled, created_at, updated_at
+			enabled, proxy_config, created_at, updated_at
 		FROM endpoints
 		WHERE enabled = 1
 		ORDER BY priority ASC, channel ASC, name ASC
@@ -469,6 +535,7 @@ func (s *SQLiteEndpointStore) WithTx(tx *sql.Tx) EndpointStore {
 func (s *SQLiteEndpointStore) scanEndpoint(row *sql.Row) (*EndpointRecord, error) {
 	var record EndpointRecord
 	var headersJSON string
+	var proxyJSON, modelMappingJSON, retryPolicy sql.NullString
 	var cooldownSeconds sql.NullInt64
 	var failoverEnabled, supportsCountTokens, enabled int
 	var createdAt, updatedAt string
@@ -476,11 +543,12 @@ func (s *SQLiteEndpointStore) scanEndpoint(row *sql.Row) (*EndpointRecord, error
 	err := row.Scan(
 		&record.ID, &record.Channel, &record.Name, &record.URL,
 		&record.Token, &record.ApiKey, &headersJSON,
-		&record.Priority, &failoverEnabled, &cooldownSeconds, &record.TimeoutSeconds,
-		&supportsCountTokens,
+		&record.Priority, &record.Weight, &failoverEnabled, &cooldownSeconds, &record.TimeoutSeconds,
+		&supportsCountTokens, &modelMappingJSON, &retryPolicy,
+		&record.MaxConcurrency, &record.RPM, &record.TPM,
 		&record.CostMultiplier, &record.InputCostMultiplier, &record.OutputCostMultiplier,
 		&record.CacheCreationCostMultiplier, &record.CacheCreationCostMultiplier1h, &record.CacheReadCostMultiplier,
-		&enabled, &createdAt, &updatedAt,
+		&enabled, &proxyJSON, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -496,6 +564,11 @@ func (s *SQLiteEndpointStore) scanEndpoint(row *sql.Row) (*EndpointRecord, error
 		}
 	}
 
+	// 解析代理配置和模型映射
+	record.Proxy = parseEndpointProxy(proxyJSON)
+	record.ModelMapping = parseModelMapping(modelMappingJSON)
+	record.RetryPolicy = retryPolicy.String
+
 	// 解析可空字段
 	if cooldownSeconds.Valid {
 		cd := int(cooldownSeconds.Int64)
@@ -514,6 +587,62 @@ func (s *SQLiteEndpointStore) scanEndpoint(row *sql.Row) (*EndpointRecord, error
 	return &record, nil
 }
 
+// marshalEndpointProxy 序列化端点代理配置，nil 存储为 NULL（继承全局代理）
+func marshalEndpointProxy(p *EndpointProxy) (sql.NullString, error) {
+	if p == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("序列化 proxy 失败: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// parseEndpointProxy 解析端点代理配置，解析失败时视为未配置
+func parseEndpointProxy(data sql.NullString) *EndpointProxy {
+	if !data.Valid || data.String == "" || data.String == "null" {
+		return nil
+	}
+	var p EndpointProxy
+	if err := json.Unmarshal([]byte(data.String), &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+// marshalModelMapping 序列化模型名称映射，未配置时存储为 NULL
+func marshalModelMapping(m map[string]string) (sql.NullString, error) {
+	if len(m) == 0 {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("序列化 model_mapping 失败: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// nullableText 空文本存储为 NULL
+func nullableText(text string) sql.NullString {
+	if strings.TrimSpace(text) == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: text, Valid: true}
+}
+
+// parseModelMapping 解析模型名称映射，解析失败时视为未配置
+func parseModelMapping(data sql.NullString) map[string]string {
+	if !data.Valid || data.String == "" || data.String == "null" {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(data.String), &m); err != nil || len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
 // scanEndpoints 扫描多个端点记录
 func (s *SQLiteEndpointStore) scanEndpoints(ctx context.Context, query string) ([]*EndpointRecord, error) {
 	return s.scanEndpointsWithArgs(ctx, query)
@@ -531,6 +660,7 @@ func (s *SQLiteEndpointStore) scanEndpointsWithArgs(ctx context.Context, query s
 	for rows.Next() {
 		var record EndpointRecord
 		var headersJSON string
+		var proxyJSON, modelMappingJSON, retryPolicy sql.NullString
 		var cooldownSeconds sql.NullInt64
 		var failoverEnabled, supportsCountTokens, enabled int
 		var createdAt, updatedAt string
@@ -538,11 +668,12 @@ func (s *SQLiteEndpointStore) scanEndpointsWithArgs(ctx context.Context, query s
 		err := rows.Scan(
 			&record.ID, &record.Channel, &record.Name, &record.URL,
 			&record.Token, &record.ApiKey, &headersJSON,
-			&record.Priority, &failoverEnabled, &cooldownSeconds, &record.TimeoutSeconds,
-			&supportsCountTokens,
+			&record.Priority, &record.Weight, &failoverEnabled, &cooldownSeconds, &record.TimeoutSeconds,
+			&supportsCountTokens, &modelMappingJSON, &retryPolicy,
+			&record.MaxConcurrency, &record.RPM, &record.TPM,
 			&record.CostMultiplier, &record.InputCostMultiplier, &record.OutputCostMultiplier,
 			&record.CacheCreationCostMultiplier, &record.CacheCreationCostMultiplier1h, &record.CacheReadCostMultiplier,
-			&enabled, &createdAt, &updatedAt,
+			&enabled, &proxyJSON, &createdAt, &updatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("扫描端点记录失败: %w", err)
@@ -555,6 +686,11 @@ func (s *SQLiteEndpointStore) scanEndpointsWithArgs(ctx context.Context, query s
 			}
 		}
 
+		// 解析代理配置和模型映射
+		record.Proxy = parseEndpointProxy(proxyJSON)
+		record.ModelMapping = parseModelMapping(modelMappingJSON)
+		record.RetryPolicy = retryPolicy.String
+
 		// 解析可空字段
 		if cooldownSeconds.Valid {
 			cd := int(cooldownSeconds.Int64)
@@ -39,16 +39,24 @@ func createTestDB(t *testing.T) (*sql.DB, func()) {
 			api_key TEXT,
 			headers TEXT,
 			priority INTEGER DEFAULT 1,
+			weight INTEGER DEFAULT 1,
 			failover_enabled INTEGER DEFAULT 1,
 			cooldown_seconds INTEGER,
 			timeout_seconds INTEGER DEFAULT 300,
+			max_concurrency INTEGER DEFAULT 0,
+			rpm INTEGER DEFAULT 0,
+			tpm INTEGER DEFAULT 0,
 			supports_count_tokens INTEGER DEFAULT 0,
+			model_mapping TEXT,
+			retry_policy TEXT,
 			cost_multiplier REAL DEFAULT 1.0,
 			input_cost_multiplier REAL DEFAULT 1.0,
 			output_cost_multiplier REAL DEFAULT 1.0,
 			cache_creation_cost_multiplier REAL DEFAULT 1.0,
+			cache_creation_cost_multiplier_1h REAL DEFAULT 1.0,
 			cache_read_cost_multiplier REAL DEFAULT 1.0,
 			enabled INTEGER DEFAULT 1,
+			proxy_config TEXT,
 			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
 			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
 		);
@@ -109,6 +117,149 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+// TestEndpointProxy 测试端点级代理配置的存取
+func TestEndpointProxy(t *testing.T) {
+	db, cleanup := createTestDB(t)
+	defer cleanup()
+
+	store := NewSQLiteEndpointStore(db)
+	ctx := context.Background()
+
+	// 未配置代理：读回为 nil（继承全局代理）
+	if _, err := store.Create(ctx, &EndpointRecord{Channel: "c", Name: "inherit", URL: "https://a.example.com", Enabled: true}); err != nil {
+		t.Fatalf("创建端点失败: %v", err)
+	}
+	got, err := store.Get(ctx, "inherit")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if got.Proxy != nil {
+		t.Errorf("未配置代理时 Proxy 应为 nil, got %+v", got.Proxy)
+	}
+
+	// 配置 SOCKS5 代理
+	proxy := &EndpointProxy{Enabled: true, Type: "socks5", Host: "10.0.0.1", Port: 1080, Username: "u", Password: "p"}
+	if _, err := store.Create(ctx, &EndpointRecord{Channel: "c", Name: "socks", URL: "https://b.example.com", Enabled: true, Proxy: proxy}); err != nil {
+		t.Fatalf("创建端点失败: %v", err)
+	}
+	got, err = store.Get(ctx, "socks")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if got.Proxy == nil || *got.Proxy != *proxy {
+		t.Errorf("Proxy 不匹配: got %+v, want %+v", got.Proxy, proxy)
+	}
+
+	// 更新为直连（显式禁用代理）
+	got.Proxy = &EndpointProxy{Enabled: false}
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("更新端点失败: %v", err)
+	}
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("列出端点失败: %v", err)
+	}
+	for _, r := range list {
+		if r.Name == "socks" && (r.Proxy == nil || r.Proxy.Enabled) {
+			t.Errorf("更新后应为直连配置, got %+v", r.Proxy)
+		}
+	}
+}
+
+// TestEndpointWeight 测试端点权重（未设置时默认为 1）
+func TestEndpointWeight(t *testing.T) {
+	db, cleanup := createTestDB(t)
+	defer cleanup()
+
+	store := NewSQLiteEndpointStore(db)
+	ctx := context.Background()
+
+	if _, err := store.Create(ctx, &EndpointRecord{Channel: "c", Name: "default", URL: "https://a.example.com"}); err != nil {
+		t.Fatalf("创建端点失败: %v", err)
+	}
+	if _, err := store.Create(ctx, &EndpointRecord{Channel: "c", Name: "heavy", URL: "https://b.example.com", Weight: 5}); err != nil {
+		t.Fatalf("创建端点失败: %v", err)
+	}
+
+	got, err := store.Get(ctx, "default")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if got.Weight != 1 {
+		t.Errorf("默认权重应为 1, got %d", got.Weight)
+	}
+
+	got, err = store.Get(ctx, "heavy")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if got.Weight != 5 {
+		t.Errorf("权重不匹配: got %d, want 5", got.Weight)
+	}
+
+	got.Weight = 3
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("更新端点失败: %v", err)
+	}
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("列出端点失败: %v", err)
+	}
+	for _, r := range list {
+		if r.Name == "heavy" && r.Weight != 3 {
+			t.Errorf("更新后权重应为 3, got %d", r.Weight)
+		}
+	}
+}
+
+// TestEndpointModelMapping 测试端点模型名称映射（未配置时为 nil）
+func TestEndpointModelMapping(t *testing.T) {
+	db, cleanup := createTestDB(t)
+	defer cleanup()
+
+	store := NewSQLiteEndpointStore(db)
+	ctx := context.Background()
+
+	mapping := map[string]string{"claude-sonnet-4-5": "vendor/claude-sonnet"}
+	if err := store.BatchCreate(ctx, []*EndpointRecord{
+		{Channel: "c", Name: "plain", URL: "https://a.example.com"},
+		{Channel: "c", Name: "vendor", URL: "https://b.example.com", ModelMapping: mapping},
+	}); err != nil {
+		t.Fatalf("批量创建端点失败: %v", err)
+	}
+
+	got, err := store.Get(ctx, "plain")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if got.ModelMapping != nil {
+		t.Errorf("未配置映射时 ModelMapping 应为 nil, got %v", got.ModelMapping)
+	}
+
+	got, err = store.Get(ctx, "vendor")
+	if err != nil {
+		t.Fatalf("获取端点失败: %v", err)
+	}
+	if len(got.ModelMapping) != 1 || got.ModelMapping["claude-sonnet-4-5"] != "vendor/claude-sonnet" {
+		t.Errorf("ModelMapping 不匹配: got %v, want %v", got.ModelMapping, mapping)
+	}
+
+	// 清空映射
+	got.ModelMapping = map[string]string{}
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("更新端点失败: %v", err)
+	}
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("列出端点失败: %v", err)
+	}
+	for _, r := range list {
+		if r.ModelMapping != nil {
+			t.Errorf("端点 %s 的 ModelMapping 应为 nil, got %v", r.Name, r.ModelMapping)
+		}
+	}
+}
+
 // TestGet 测试获取端点
 func TestGet(t *testing.T) {
 	db, cleanup := createTestDB(t)
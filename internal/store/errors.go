@@ -0,0 +1,30 @@
+// Package store 提供数据存储层实现
+// 错误分类 - 供上层区分记录不存在、重复和存储故障（管理 API 据此返回 404 / 409 / 500）
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"modernc.org/sqlite"
+)
+
+var (
+	// ErrNotFound 记录不存在，使用方式: fmt.Errorf("端点 '%s' %w", name, ErrNotFound)
+	ErrNotFound = errors.New("不存在")
+
+	// ErrAlreadyExists 记录已存在，使用方式: fmt.Errorf("端点 '%s' %w", name, ErrAlreadyExists)
+	ErrAlreadyExists = errors.New("已存在")
+)
+
+// IsStorageError 判断错误是否由数据库本身引起（锁、约束、连接或超时），而不是调用方的输入
+func IsStorageError(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, sql.ErrTxDone) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,146 @@
+// Package store 提供数据存储层实现
+// 端点 Key 状态存储 - 持久化多 Key 自动轮换的冷却/失效状态
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KeyStateRecord 表示数据库中的端点 Key 状态记录
+type KeyStateRecord struct {
+	EndpointName   string     `json:"endpoint_name"`
+	KeyType        string     `json:"key_type"` // token/api_key
+	KeyHash        string     `json:"key_hash"` // Key 值指纹
+	IsActive       bool       `json:"is_active"`
+	UsageCount     int64      `json:"usage_count"`
+	CooldownUntil  *time.Time `json:"cooldown_until,omitempty"`
+	Invalid        bool       `json:"invalid"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// KeyStateStore 定义端点 Key 状态存储接口
+type KeyStateStore interface {
+	List(ctx context.Context) ([]*KeyStateRecord, error)
+	// ReplaceAll 用当前完整状态替换已保存的状态（已删除的端点/Key 一并清除）
+	ReplaceAll(ctx context.Context, records []*KeyStateRecord) error
+}
+
+// SQLiteKeyStateStore 实现 KeyStateStore 接口
+type SQLiteKeyStateStore struct {
+	db *sql.DB
+	mu sync.Mutex
+}
+
+// NewSQLiteKeyStateStore 创建新的 SQLite 端点 Key 状态存储
+func NewSQLiteKeyStateStore(db *sql.DB) *SQLiteKeyStateStore {
+	return &SQLiteKeyStateStore{db: db}
+}
+
+// List 获取所有 Key 状态
+func (s *SQLiteKeyStateStore) List(ctx context.Context) ([]*KeyStateRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		SELECT endpoint_name, key_type, key_hash, is_active, usage_count,
+			cooldown_until, invalid, last_status_code, COALESCE(last_error, ''), last_used_at, updated_at
+		FROM endpoint_key_states
+		ORDER BY endpoint_name ASC, key_type ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询 Key 状态失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*KeyStateRecord
+	for rows.Next() {
+		var record KeyStateRecord
+		var isActive, invalid int
+		var cooldownUntil, lastUsedAt sql.NullString
+		var updatedAt string
+
+		if err := rows.Scan(
+			&record.EndpointName, &record.KeyType, &record.KeyHash, &isActive, &record.UsageCount,
+			&cooldownUntil, &invalid, &record.LastStatusCode, &record.LastError, &lastUsedAt, &updatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描 Key 状态失败: %w", err)
+		}
+
+		record.IsActive = isActive == 1
+		record.Invalid = invalid == 1
+		record.CooldownUntil = parseNullableStoreTime(cooldownUntil)
+		record.LastUsedAt = parseNullableStoreTime(lastUsedAt)
+		record.UpdatedAt, _ = parseStoreTime(updatedAt)
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历 Key 状态失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// ReplaceAll 在事务中清空并写入全部 Key 状态
+func (s *SQLiteKeyStateStore) ReplaceAll(ctx context.Context, records []*KeyStateRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM endpoint_key_states"); err != nil {
+		return fmt.Errorf("清除 Key 状态失败: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO endpoint_key_states (
+			endpoint_name, key_type, key_hash, is_active, usage_count,
+			cooldown_until, invalid, last_status_code, last_error, last_used_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("准备插入语句失败: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		if _, err := stmt.ExecContext(ctx,
+			r.EndpointName, r.KeyType, r.KeyHash, boolToInt(r.IsActive), r.UsageCount,
+			formatNullableTime(r.CooldownUntil), boolToInt(r.Invalid), r.LastStatusCode, r.LastError,
+			formatNullableTime(r.LastUsedAt),
+		); err != nil {
+			return fmt.Errorf("保存 Key 状态失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// parseNullableStoreTime 解析可空时间字段，NULL 或无法解析时返回 nil
+func parseNullableStoreTime(value sql.NullString) *time.Time {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	t, ok := parseStoreTime(value.String)
+	if !ok {
+		return nil
+	}
+	return &t
+}
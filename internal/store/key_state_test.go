@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// createKeyStateTestDB 创建 Key 状态测试数据库
+func createKeyStateTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "key_state_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS endpoint_key_states (
+			endpoint_name TEXT NOT NULL,
+			key_type TEXT NOT NULL,
+			key_hash TEXT NOT NULL,
+			is_active INTEGER DEFAULT 0,
+			usage_count INTEGER DEFAULT 0,
+			cooldown_until DATETIME,
+			invalid INTEGER DEFAULT 0,
+			last_status_code INTEGER DEFAULT 0,
+			last_error TEXT,
+			last_used_at DATETIME,
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			PRIMARY KEY (endpoint_name, key_type, key_hash)
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestKeyStateReplaceAll 测试 Key 状态保存与读取
+func TestKeyStateReplaceAll(t *testing.T) {
+	db, cleanup := createKeyStateTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteKeyStateStore(db)
+	ctx := context.Background()
+
+	cooldown := time.Now().Add(time.Minute).Truncate(time.Second)
+	err := s.ReplaceAll(ctx, []*KeyStateRecord{
+		{EndpointName: "ep", KeyType: "token", KeyHash: "aaa", IsActive: false, UsageCount: 10, CooldownUntil: &cooldown, LastStatusCode: 429, LastError: "HTTP 429"},
+		{EndpointName: "ep", KeyType: "token", KeyHash: "bbb", IsActive: true, UsageCount: 3},
+		{EndpointName: "ep", KeyType: "token", KeyHash: "ccc", Invalid: true, LastStatusCode: 401},
+	})
+	if err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	records, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("记录数 = %d, want 3", len(records))
+	}
+
+	byHash := make(map[string]*KeyStateRecord)
+	for _, r := range records {
+		byHash[r.KeyHash] = r
+	}
+	if r := byHash["aaa"]; r.CooldownUntil == nil || !r.CooldownUntil.Equal(cooldown) || r.UsageCount != 10 || r.LastError != "HTTP 429" {
+		t.Errorf("冷却状态不匹配: %+v", r)
+	}
+	if r := byHash["bbb"]; !r.IsActive || r.CooldownUntil != nil || r.LastUsedAt != nil {
+		t.Errorf("激活状态不匹配: %+v", r)
+	}
+	if r := byHash["ccc"]; !r.Invalid || r.LastStatusCode != 401 {
+		t.Errorf("无效状态不匹配: %+v", r)
+	}
+
+	// 再次保存会替换全部记录
+	if err := s.ReplaceAll(ctx, []*KeyStateRecord{{EndpointName: "ep", KeyType: "api_key", KeyHash: "ddd"}}); err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+	records, _ = s.List(ctx)
+	if len(records) != 1 || records[0].KeyHash != "ddd" {
+		t.Errorf("替换后记录不匹配: %d 条", len(records))
+	}
+}
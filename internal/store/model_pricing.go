@@ -55,8 +55,20 @@ type ModelPricingStore interface {
 	// 统计
 	Count(ctx context.Context) (int, error)
 
+	// 导入审计
+	RecordAudit(ctx context.Context, record *ModelPricingAuditRecord) error
+	ListAudits(ctx context.Context, limit int) ([]*ModelPricingAuditRecord, error)
+
+	// 定价历史
+	AddVersion(ctx context.Context, record *ModelPricingVersionRecord) error
+	GetVersion(ctx context.Context, id int64) (*ModelPricingVersionRecord, error)
+	ListVersions(ctx context.Context, modelName string) ([]*ModelPricingVersionRecord, error)
+	DeleteVersion(ctx context.Context, id int64) error
+	DeleteVersions(ctx context.Context, modelName string) error
+
 	// 事务支持
 	WithTx(tx *sql.Tx) ModelPricingStore
+	RunInTx(ctx context.Context, fn func(st ModelPricingStore) error) error
 }
 
 // SQLiteModelPricingStore 实现 ModelPricingStore 接口
@@ -212,7 +224,7 @@ func (s *SQLiteModelPricingStore) Update(ctx context.Context, record *ModelPrici
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("模型定价不存在: %s", record.ModelName)
+		return fmt.Errorf("模型定价%w: %s", ErrNotFound, record.ModelName)
 	}
 
 	return nil
@@ -236,7 +248,7 @@ func (s *SQLiteModelPricingStore) Delete(ctx context.Context, modelName string)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("模型定价不存在: %s", modelName)
+		return fmt.Errorf("模型定价%w: %s", ErrNotFound, modelName)
 	}
 
 	return nil
@@ -316,12 +328,16 @@ func (s *SQLiteModelPricingStore) BatchUpsert(ctx context.Context, records []*Mo
 		return nil
 	}
 
-	// 使用事务
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("开始事务失败: %w", err)
+	// 使用事务（已在外部事务中时直接复用，由外部提交）
+	tx := s.tx
+	if tx == nil {
+		var err error
+		tx, err = s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("开始事务失败: %w", err)
+		}
+		defer tx.Rollback()
 	}
-	defer tx.Rollback()
 
 	query := `
 		INSERT INTO model_pricing (
@@ -374,6 +390,9 @@ func (s *SQLiteModelPricingStore) BatchUpsert(ctx context.Context, records []*Mo
 		}
 	}
 
+	if s.tx != nil {
+		return nil
+	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("提交事务失败: %w", err)
 	}
@@ -427,7 +446,7 @@ func (s *SQLiteModelPricingStore) SetDefault(ctx context.Context, modelName stri
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("模型定价不存在: %s", modelName)
+		return fmt.Errorf("模型定价%w: %s", ErrNotFound, modelName)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -459,6 +478,28 @@ func (s *SQLiteModelPricingStore) WithTx(tx *sql.Tx) ModelPricingStore {
 	}
 }
 
+// RunInTx 在同一事务中执行 fn，fn 返回错误时回滚
+// fn 必须通过传入的存储实例读写，已在事务中时直接复用当前事务
+func (s *SQLiteModelPricingStore) RunInTx(ctx context.Context, fn func(st ModelPricingStore) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
 // scanModelPricing 从单行扫描模型定价记录
 func (s *SQLiteModelPricingStore) scanModelPricing(row *sql.Row) (*ModelPricingRecord, error) {
 	var record ModelPricingRecord
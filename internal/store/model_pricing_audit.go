@@ -0,0 +1,90 @@
+// Package store 提供数据存储层实现
+// 模型定价导入审计 - 记录每次从价格目录导入时新增和变化的模型
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ModelPricingAuditRecord 表示一次定价导入的审计记录
+type ModelPricingAuditRecord struct {
+	ID        int64     `json:"id"`
+	Source    string    `json:"source,omitempty"` // 来源说明（文件名或 URL）
+	Format    string    `json:"format"`           // 目录格式：litellm / csv
+	Added     int       `json:"added"`            // 新增模型数
+	Changed   int       `json:"changed"`          // 价格变化的模型数
+	Unchanged int       `json:"unchanged"`        // 未变化的模型数
+	Changes   string    `json:"changes"`          // 新增和变化明细（JSON 数组）
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecordAudit 写入一条导入审计记录
+func (s *SQLiteModelPricingStore) RecordAudit(ctx context.Context, record *ModelPricingAuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record.Changes == "" {
+		record.Changes = "[]"
+	}
+
+	query := `
+		INSERT INTO model_pricing_audit (source, format, added, changed, unchanged, changes)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.getQuerier().ExecContext(ctx, query,
+		record.Source, record.Format, record.Added, record.Changed, record.Unchanged, record.Changes,
+	)
+	if err != nil {
+		return fmt.Errorf("记录定价导入审计失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	return nil
+}
+
+// ListAudits 按时间倒序列出导入审计记录
+func (s *SQLiteModelPricingStore) ListAudits(ctx context.Context, limit int) ([]*ModelPricingAuditRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT id, COALESCE(source, ''), format, added, changed, unchanged, COALESCE(changes, '[]'), created_at
+		FROM model_pricing_audit
+		ORDER BY id DESC LIMIT ?
+	`
+
+	rows, err := s.getQuerier().QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询定价导入审计失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ModelPricingAuditRecord
+	for rows.Next() {
+		var record ModelPricingAuditRecord
+		var createdAt string
+		if err := rows.Scan(
+			&record.ID, &record.Source, &record.Format,
+			&record.Added, &record.Changed, &record.Unchanged, &record.Changes, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描定价导入审计失败: %w", err)
+		}
+		record.CreatedAt, _ = parseStoreTime(createdAt)
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历定价导入审计失败: %w", err)
+	}
+
+	return records, nil
+}
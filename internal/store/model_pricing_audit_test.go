@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// TestModelPricingAudits 测试定价导入审计的写入和查询
+func TestModelPricingAudits(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS model_pricing_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source TEXT,
+			format TEXT NOT NULL,
+			added INTEGER DEFAULT 0,
+			changed INTEGER DEFAULT 0,
+			unchanged INTEGER DEFAULT 0,
+			changes TEXT DEFAULT '[]',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	s := NewSQLiteModelPricingStore(db)
+	ctx := context.Background()
+
+	first := &ModelPricingAuditRecord{Format: "csv", Added: 2}
+	if err := s.RecordAudit(ctx, first); err != nil || first.ID == 0 || first.Changes != "[]" {
+		t.Fatalf("写入审计失败: %v %+v", err, first)
+	}
+	second := &ModelPricingAuditRecord{
+		Source: "model_prices_and_context_window.json", Format: "litellm",
+		Changed: 1, Unchanged: 5, Changes: `[{"model_name":"claude-a","action":"changed"}]`,
+	}
+	if err := s.RecordAudit(ctx, second); err != nil {
+		t.Fatalf("写入审计失败: %v", err)
+	}
+
+	audits, err := s.ListAudits(ctx, 10)
+	if err != nil || len(audits) != 2 {
+		t.Fatalf("查询审计失败: %v, %d 条", err, len(audits))
+	}
+	latest := audits[0]
+	if latest.Format != "litellm" || latest.Source != second.Source || latest.Changed != 1 ||
+		latest.Unchanged != 5 || latest.Changes != second.Changes || latest.CreatedAt.IsZero() {
+		t.Errorf("应按时间倒序返回: %+v", latest)
+	}
+	if audits[1].Source != "" || audits[1].Added != 2 {
+		t.Errorf("审计字段不符: %+v", audits[1])
+	}
+
+	if limited, _ := s.ListAudits(ctx, 1); len(limited) != 1 {
+		t.Errorf("limit 未生效: %d 条", len(limited))
+	}
+}
@@ -0,0 +1,157 @@
+// Package store 提供数据存储层实现
+// 模型定价历史 - 记录每个模型的定价版本及生效时间，用于按请求时间计算成本
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ModelPricingVersionRecord 表示一个模型定价版本
+type ModelPricingVersionRecord struct {
+	ID        int64  `json:"id"`
+	ModelName string `json:"model_name"`
+
+	// 定价信息 (USD per 1M tokens)
+	InputPrice           float64 `json:"input_price"`
+	OutputPrice          float64 `json:"output_price"`
+	CacheCreationPrice5m float64 `json:"cache_creation_price_5m"`
+	CacheCreationPrice1h float64 `json:"cache_creation_price_1h"`
+	CacheReadPrice       float64 `json:"cache_read_price"`
+
+	EffectiveFrom time.Time `json:"effective_from"` // 生效时间，直到同一模型的下一个版本
+	Source        string    `json:"source"`         // 版本来源：baseline / create / update / import / manual
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AddVersion 写入定价版本，同一模型同一生效时间的版本会被覆盖（用于修正价格）
+func (s *SQLiteModelPricingStore) AddVersion(ctx context.Context, record *ModelPricingVersionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		INSERT INTO model_pricing_history (
+			model_name, input_price, output_price,
+			cache_creation_price_5m, cache_creation_price_1h, cache_read_price,
+			effective_from, source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(model_name, effective_from) DO UPDATE SET
+			input_price = excluded.input_price,
+			output_price = excluded.output_price,
+			cache_creation_price_5m = excluded.cache_creation_price_5m,
+			cache_creation_price_1h = excluded.cache_creation_price_1h,
+			cache_read_price = excluded.cache_read_price,
+			source = excluded.source
+		RETURNING id
+	`
+
+	err := s.getQuerier().QueryRowContext(ctx, query,
+		record.ModelName, record.InputPrice, record.OutputPrice,
+		record.CacheCreationPrice5m, record.CacheCreationPrice1h, record.CacheReadPrice,
+		record.EffectiveFrom.UTC().Format(storeTimeLayout), record.Source,
+	).Scan(&record.ID)
+	if err != nil {
+		return fmt.Errorf("写入定价版本失败: %w", err)
+	}
+
+	record.CreatedAt = time.Now()
+	return nil
+}
+
+// GetVersion 根据 ID 获取定价版本，不存在时返回 nil
+func (s *SQLiteModelPricingStore) GetVersion(ctx context.Context, id int64) (*ModelPricingVersionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records, err := s.queryVersions(ctx, "WHERE id = ?", id)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// ListVersions 列出定价版本（按模型和生效时间升序），modelName 为空时列出全部
+func (s *SQLiteModelPricingStore) ListVersions(ctx context.Context, modelName string) ([]*ModelPricingVersionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if modelName == "" {
+		return s.queryVersions(ctx, "")
+	}
+	return s.queryVersions(ctx, "WHERE model_name = ?", modelName)
+}
+
+// DeleteVersion 删除定价版本
+func (s *SQLiteModelPricingStore) DeleteVersion(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.getQuerier().ExecContext(ctx, "DELETE FROM model_pricing_history WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("删除定价版本失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("定价版本%w: %d", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// DeleteVersions 删除模型的全部定价版本
+func (s *SQLiteModelPricingStore) DeleteVersions(ctx context.Context, modelName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.getQuerier().ExecContext(ctx, "DELETE FROM model_pricing_history WHERE model_name = ?", modelName); err != nil {
+		return fmt.Errorf("删除模型定价历史失败: %w", err)
+	}
+	return nil
+}
+
+// queryVersions 按条件查询定价版本
+func (s *SQLiteModelPricingStore) queryVersions(ctx context.Context, where string, args ...interface{}) ([]*ModelPricingVersionRecord, error) {
+	query := `
+		SELECT id, model_name, input_price, output_price,
+			cache_creation_price_5m, cache_creation_price_1h, cache_read_price,
+			effective_from, COALESCE(source, ''), created_at
+		FROM model_pricing_history ` + where + `
+		ORDER BY model_name ASC, effective_from ASC
+	`
+
+	rows, err := s.getQuerier().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询定价版本失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ModelPricingVersionRecord
+	for rows.Next() {
+		var record ModelPricingVersionRecord
+		var effectiveFrom, createdAt sql.NullString
+		if err := rows.Scan(
+			&record.ID, &record.ModelName,
+			&record.InputPrice, &record.OutputPrice,
+			&record.CacheCreationPrice5m, &record.CacheCreationPrice1h, &record.CacheReadPrice,
+			&effectiveFrom, &record.Source, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描定价版本失败: %w", err)
+		}
+		record.EffectiveFrom, _ = parseStoreTime(effectiveFrom.String)
+		record.CreatedAt, _ = parseStoreTime(createdAt.String)
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历定价版本失败: %w", err)
+	}
+
+	return records, nil
+}
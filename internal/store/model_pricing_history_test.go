@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// TestModelPricingVersions 测试定价版本的写入、覆盖、查询和删除
+func TestModelPricingVersions(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS model_pricing_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			model_name TEXT NOT NULL,
+			input_price REAL NOT NULL DEFAULT 0,
+			output_price REAL NOT NULL DEFAULT 0,
+			cache_creation_price_5m REAL DEFAULT 0,
+			cache_creation_price_1h REAL DEFAULT 0,
+			cache_read_price REAL DEFAULT 0,
+			effective_from DATETIME NOT NULL,
+			source TEXT DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			UNIQUE(model_name, effective_from)
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	s := NewSQLiteModelPricingStore(db)
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	t0 := time.Date(2025, 6, 1, 8, 0, 0, 0, loc)
+
+	versions := []*ModelPricingVersionRecord{
+		{ModelName: "claude-b", InputPrice: 1, OutputPrice: 5, EffectiveFrom: t0, Source: "create"},
+		{ModelName: "claude-a", InputPrice: 4, OutputPrice: 20, EffectiveFrom: t0.AddDate(0, 1, 0), Source: "update"},
+		{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15, EffectiveFrom: time.Unix(0, 0), Source: "baseline"},
+	}
+	for _, v := range versions {
+		if err := s.AddVersion(ctx, v); err != nil || v.ID == 0 {
+			t.Fatalf("写入定价版本失败: %v", err)
+		}
+	}
+
+	// 同一生效时间再次写入覆盖价格
+	fix := &ModelPricingVersionRecord{ModelName: "claude-a", InputPrice: 5, OutputPrice: 25, EffectiveFrom: t0.AddDate(0, 1, 0).UTC(), Source: "manual"}
+	if err := s.AddVersion(ctx, fix); err != nil || fix.ID != versions[1].ID {
+		t.Fatalf("覆盖定价版本失败: %v, id %d != %d", err, fix.ID, versions[1].ID)
+	}
+
+	list, err := s.ListVersions(ctx, "claude-a")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("查询定价版本失败: %v, %d 条", err, len(list))
+	}
+	if !list[0].EffectiveFrom.Equal(time.Unix(0, 0)) || list[1].InputPrice != 5 || list[1].Source != "manual" ||
+		!list[1].EffectiveFrom.Equal(t0.AddDate(0, 1, 0)) {
+		t.Errorf("定价版本不符: %+v %+v", list[0], list[1])
+	}
+
+	if all, _ := s.ListVersions(ctx, ""); len(all) != 3 || all[0].ModelName != "claude-a" {
+		t.Errorf("全部定价版本不符: %d 条", len(all))
+	}
+
+	got, err := s.GetVersion(ctx, versions[0].ID)
+	if err != nil || got == nil || got.ModelName != "claude-b" {
+		t.Errorf("按 ID 获取定价版本失败: %v %+v", err, got)
+	}
+	if missing, err := s.GetVersion(ctx, 999); err != nil || missing != nil {
+		t.Errorf("不存在的版本应返回 nil: %v %+v", err, missing)
+	}
+
+	if err := s.DeleteVersion(ctx, versions[0].ID); err != nil {
+		t.Fatalf("删除定价版本失败: %v", err)
+	}
+	if err := s.DeleteVersion(ctx, versions[0].ID); err == nil {
+		t.Error("重复删除应返回错误")
+	}
+	if err := s.DeleteVersions(ctx, "claude-a"); err != nil {
+		t.Fatalf("删除模型定价历史失败: %v", err)
+	}
+	if all, _ := s.ListVersions(ctx, ""); len(all) != 0 {
+		t.Errorf("定价历史应已清空: %d 条", len(all))
+	}
+}
+
+// TestModelPricingRunInTx 测试定价和定价版本在同一事务中写入和回滚
+func TestModelPricingRunInTx(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS model_pricing (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			model_name TEXT UNIQUE NOT NULL,
+			input_price REAL NOT NULL DEFAULT 3.0,
+			output_price REAL NOT NULL DEFAULT 15.0,
+			cache_creation_price_5m REAL DEFAULT 3.75,
+			cache_creation_price_1h REAL DEFAULT 6.0,
+			cache_read_price REAL DEFAULT 0.30,
+			display_name TEXT,
+			description TEXT,
+			is_default INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+		CREATE TABLE IF NOT EXISTS model_pricing_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			model_name TEXT NOT NULL,
+			input_price REAL NOT NULL DEFAULT 0,
+			output_price REAL NOT NULL DEFAULT 0,
+			cache_creation_price_5m REAL DEFAULT 0,
+			cache_creation_price_1h REAL DEFAULT 0,
+			cache_read_price REAL DEFAULT 0,
+			effective_from DATETIME NOT NULL,
+			source TEXT DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			UNIQUE(model_name, effective_from)
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	s := NewSQLiteModelPricingStore(db)
+	ctx := context.Background()
+	write := func(st ModelPricingStore) error {
+		if err := st.BatchUpsert(ctx, []*ModelPricingRecord{{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15}}); err != nil {
+			return err
+		}
+		return st.AddVersion(ctx, &ModelPricingVersionRecord{ModelName: "claude-a", InputPrice: 3, OutputPrice: 15, EffectiveFrom: time.Now(), Source: "import"})
+	}
+
+	if err := s.RunInTx(ctx, func(st ModelPricingStore) error {
+		if err := write(st); err != nil {
+			return err
+		}
+		return sql.ErrConnDone
+	}); err != sql.ErrConnDone {
+		t.Fatalf("应返回 fn 的错误: %v", err)
+	}
+	if count, _ := s.Count(ctx); count != 0 {
+		t.Errorf("回滚后不应写入定价: %d", count)
+	}
+	if versions, _ := s.ListVersions(ctx, ""); len(versions) != 0 {
+		t.Errorf("回滚后不应写入定价版本: %d", len(versions))
+	}
+
+	if err := s.RunInTx(ctx, write); err != nil {
+		t.Fatalf("事务写入失败: %v", err)
+	}
+	if count, _ := s.Count(ctx); count != 1 {
+		t.Errorf("提交后定价数不符: %d", count)
+	}
+	if versions, _ := s.ListVersions(ctx, "claude-a"); len(versions) != 1 {
+		t.Errorf("提交后定价版本数不符: %d", len(versions))
+	}
+}
@@ -0,0 +1,256 @@
+// Package store 提供数据存储层实现
+// 响应缓存存储 - 保存确定性非流式请求的成功响应
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ResponseCacheRecord 表示数据库中的响应缓存条目
+type ResponseCacheRecord struct {
+	CacheKey string              `json:"cache_key"` // 请求路径、模型和规范化请求体的 SHA-256
+	Model    string              `json:"model"`
+	Path     string              `json:"path"`
+	Headers  map[string][]string `json:"headers,omitempty"`
+	Body     []byte              `json:"-"` // 列表查询时不加载
+	BodySize int64               `json:"body_size"`
+
+	HitCount  int64      `json:"hit_count"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ResponseCacheStore 定义响应缓存存储接口
+type ResponseCacheStore interface {
+	// Get 获取缓存条目（含响应体），不存在时返回 nil
+	Get(ctx context.Context, key string) (*ResponseCacheRecord, error)
+	// Put 写入缓存条目，已存在时覆盖
+	Put(ctx context.Context, record *ResponseCacheRecord) error
+	// List 获取所有缓存条目（不含响应体）
+	List(ctx context.Context) ([]*ResponseCacheRecord, error)
+	// RecordHit 累加命中次数并更新最近命中时间
+	RecordHit(ctx context.Context, key string, at time.Time) error
+	Delete(ctx context.Context, key string) error
+	// DeleteExpired 删除已过期的条目，返回删除数量
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	// Trim 条目数超过 maxEntries 时删除最久未使用的条目，返回删除数量
+	Trim(ctx context.Context, maxEntries int) (int64, error)
+	// Purge 删除所有条目，返回删除数量
+	Purge(ctx context.Context) (int64, error)
+}
+
+// SQLiteResponseCacheStore 实现 ResponseCacheStore 接口
+type SQLiteResponseCacheStore struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+// NewSQLiteResponseCacheStore 创建新的 SQLite 响应缓存存储
+func NewSQLiteResponseCacheStore(db *sql.DB) *SQLiteResponseCacheStore {
+	return &SQLiteResponseCacheStore{db: db}
+}
+
+// Get 根据缓存键获取条目
+func (s *SQLiteResponseCacheStore) Get(ctx context.Context, key string) (*ResponseCacheRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT cache_key, COALESCE(model, ''), path, COALESCE(headers, '{}'), body,
+			hit_count, expires_at, last_hit_at, created_at
+		FROM response_cache WHERE cache_key = ?
+	`
+
+	var record ResponseCacheRecord
+	var headersJSON, expiresAt, createdAt string
+	var lastHitAt sql.NullString
+
+	err := s.db.QueryRowContext(ctx, query, key).Scan(
+		&record.CacheKey, &record.Model, &record.Path, &headersJSON, &record.Body,
+		&record.HitCount, &expiresAt, &lastHitAt, &createdAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询响应缓存失败: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(headersJSON), &record.Headers); err != nil {
+		return nil, fmt.Errorf("解析响应头失败: %w", err)
+	}
+
+	record.BodySize = int64(len(record.Body))
+	record.ExpiresAt, _ = parseStoreTime(expiresAt)
+	record.LastHitAt = parseNullableStoreTime(lastHitAt)
+	record.CreatedAt, _ = parseStoreTime(createdAt)
+
+	return &record, nil
+}
+
+// Put 写入缓存条目（按缓存键覆盖，命中统计重新计数）
+func (s *SQLiteResponseCacheStore) Put(ctx context.Context, record *ResponseCacheRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	headers := record.Headers
+	if headers == nil {
+		headers = map[string][]string{}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("序列化响应头失败: %w", err)
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT OR REPLACE INTO response_cache (
+			cache_key, model, path, headers, body, hit_count, expires_at, last_hit_at, created_at
+		) VALUES (?, ?, ?, ?, ?, 0, ?, NULL, ?)
+	`
+
+	_, err = s.db.ExecContext(ctx, query,
+		record.CacheKey, record.Model, record.Path, string(headersJSON), record.Body,
+		record.ExpiresAt.Format(storeTimeLayout), record.CreatedAt.Format(storeTimeLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("写入响应缓存失败: %w", err)
+	}
+
+	record.HitCount = 0
+	record.LastHitAt = nil
+	record.BodySize = int64(len(record.Body))
+	return nil
+}
+
+// List 获取所有缓存条目（按创建时间倒序）
+func (s *SQLiteResponseCacheStore) List(ctx context.Context) ([]*ResponseCacheRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := `
+		SELECT cache_key, COALESCE(model, ''), path, LENGTH(body),
+			hit_count, expires_at, last_hit_at, created_at
+		FROM response_cache
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询响应缓存失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ResponseCacheRecord
+	for rows.Next() {
+		var record ResponseCacheRecord
+		var expiresAt, createdAt string
+		var lastHitAt sql.NullString
+
+		if err := rows.Scan(
+			&record.CacheKey, &record.Model, &record.Path, &record.BodySize,
+			&record.HitCount, &expiresAt, &lastHitAt, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描响应缓存失败: %w", err)
+		}
+
+		record.ExpiresAt, _ = parseStoreTime(expiresAt)
+		record.LastHitAt = parseNullableStoreTime(lastHitAt)
+		record.CreatedAt, _ = parseStoreTime(createdAt)
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历响应缓存失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// RecordHit 记录一次命中
+func (s *SQLiteResponseCacheStore) RecordHit(ctx context.Context, key string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE response_cache SET hit_count = hit_count + 1, last_hit_at = ? WHERE cache_key = ?",
+		at.Format(storeTimeLayout), key)
+	if err != nil {
+		return fmt.Errorf("更新缓存命中次数失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除缓存条目
+func (s *SQLiteResponseCacheStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM response_cache WHERE cache_key = ?", key)
+	if err != nil {
+		return fmt.Errorf("删除响应缓存失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("缓存条目 '%s' %w", key, ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpired 删除已过期的条目
+func (s *SQLiteResponseCacheStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM response_cache WHERE expires_at <= ?", now.Format(storeTimeLayout))
+	if err != nil {
+		return 0, fmt.Errorf("删除过期响应缓存失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Trim 按最近使用时间保留 maxEntries 个条目
+func (s *SQLiteResponseCacheStore) Trim(ctx context.Context, maxEntries int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		DELETE FROM response_cache WHERE cache_key IN (
+			SELECT cache_key FROM response_cache
+			ORDER BY COALESCE(last_hit_at, created_at) DESC
+			LIMIT -1 OFFSET ?
+		)
+	`
+
+	result, err := s.db.ExecContext(ctx, query, maxEntries)
+	if err != nil {
+		return 0, fmt.Errorf("淘汰响应缓存失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Purge 清空响应缓存
+func (s *SQLiteResponseCacheStore) Purge(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM response_cache")
+	if err != nil {
+		return 0, fmt.Errorf("清空响应缓存失败: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// createResponseCacheTestDB 创建响应缓存测试数据库
+func createResponseCacheTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "response_cache_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS response_cache (
+			cache_key TEXT PRIMARY KEY,
+			model TEXT,
+			path TEXT NOT NULL,
+			headers TEXT DEFAULT '{}',
+			body BLOB NOT NULL,
+			hit_count INTEGER DEFAULT 0,
+			expires_at DATETIME NOT NULL,
+			last_hit_at DATETIME,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestResponseCachePutGet 测试缓存条目写入、读取和命中统计
+func TestResponseCachePutGet(t *testing.T) {
+	db, cleanup := createResponseCacheTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteResponseCacheStore(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	err := s.Put(ctx, &ResponseCacheRecord{
+		CacheKey:  "k1",
+		Model:     "claude-sonnet-4",
+		Path:      "/v1/messages",
+		Headers:   map[string][]string{"Content-Type": {"application/json"}},
+		Body:      []byte(`{"id":"msg_1"}`),
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	record, err := s.Get(ctx, "k1")
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if record == nil {
+		t.Fatal("期望找到缓存条目")
+	}
+	if string(record.Body) != `{"id":"msg_1"}` || record.BodySize != 14 {
+		t.Errorf("响应体不匹配: %q (%d)", record.Body, record.BodySize)
+	}
+	if got := record.Headers["Content-Type"]; len(got) != 1 || got[0] != "application/json" {
+		t.Errorf("响应头不匹配: %v", record.Headers)
+	}
+	if record.HitCount != 0 || record.LastHitAt != nil {
+		t.Errorf("新条目不应有命中记录: %d, %v", record.HitCount, record.LastHitAt)
+	}
+
+	if err := s.RecordHit(ctx, "k1", now); err != nil {
+		t.Fatalf("记录命中失败: %v", err)
+	}
+	if err := s.RecordHit(ctx, "k1", now); err != nil {
+		t.Fatalf("记录命中失败: %v", err)
+	}
+
+	entries, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("列表查询失败: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("期望 1 个条目, 实际 %d", len(entries))
+	}
+	if entries[0].HitCount != 2 || entries[0].LastHitAt == nil {
+		t.Errorf("命中统计不匹配: %d, %v", entries[0].HitCount, entries[0].LastHitAt)
+	}
+	if entries[0].Body != nil || entries[0].BodySize != 14 {
+		t.Errorf("列表不应加载响应体: %q (%d)", entries[0].Body, entries[0].BodySize)
+	}
+
+	// 覆盖写入重置命中统计
+	if err := s.Put(ctx, &ResponseCacheRecord{CacheKey: "k1", Path: "/v1/messages", Body: []byte("{}"), ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("覆盖写入失败: %v", err)
+	}
+	record, _ = s.Get(ctx, "k1")
+	if record.HitCount != 0 || string(record.Body) != "{}" {
+		t.Errorf("覆盖写入后条目不匹配: %d, %q", record.HitCount, record.Body)
+	}
+
+	if record, err := s.Get(ctx, "missing"); err != nil || record != nil {
+		t.Errorf("不存在的条目应返回 nil: %v, %v", record, err)
+	}
+	if err := s.Delete(ctx, "missing"); err == nil {
+		t.Error("删除不存在的条目应返回错误")
+	}
+	if err := s.Delete(ctx, "k1"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+}
+
+// TestResponseCacheEviction 测试过期清理、条目上限淘汰和清空
+func TestResponseCacheEviction(t *testing.T) {
+	db, cleanup := createResponseCacheTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteResponseCacheStore(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	put := func(key string, createdAt, expiresAt time.Time) {
+		t.Helper()
+		err := s.Put(ctx, &ResponseCacheRecord{
+			CacheKey:  key,
+			Path:      "/v1/messages",
+			Body:      []byte("{}"),
+			ExpiresAt: expiresAt,
+			CreatedAt: createdAt,
+		})
+		if err != nil {
+			t.Fatalf("写入 %s 失败: %v", key, err)
+		}
+	}
+
+	put("expired", now.Add(-2*time.Hour), now.Add(-time.Hour))
+	put("old", now.Add(-3*time.Minute), now.Add(time.Hour))
+	put("hit", now.Add(-2*time.Minute), now.Add(time.Hour))
+	put("new", now.Add(-time.Minute), now.Add(time.Hour))
+
+	deleted, err := s.DeleteExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("清理过期条目失败: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("期望清理 1 个过期条目, 实际 %d", deleted)
+	}
+
+	// 最近命中的条目优先保留
+	if err := s.RecordHit(ctx, "hit", now); err != nil {
+		t.Fatalf("记录命中失败: %v", err)
+	}
+	evicted, err := s.Trim(ctx, 2)
+	if err != nil {
+		t.Fatalf("淘汰失败: %v", err)
+	}
+	if evicted != 1 {
+		t.Errorf("期望淘汰 1 个条目, 实际 %d", evicted)
+	}
+	if record, _ := s.Get(ctx, "old"); record != nil {
+		t.Error("最久未使用的条目应被淘汰")
+	}
+	if record, _ := s.Get(ctx, "hit"); record == nil {
+		t.Error("最近命中的条目应保留")
+	}
+
+	purged, err := s.Purge(ctx)
+	if err != nil {
+		t.Fatalf("清空失败: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("期望清空 2 个条目, 实际 %d", purged)
+	}
+}
@@ -0,0 +1,251 @@
+// Package store 提供数据存储层实现
+// 模型路由规则存储 - 按模型名称限制可用端点/渠道
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RoutingRuleRecord 表示数据库中的模型路由规则
+// 请求模型匹配 ModelPattern 时，只允许转发到 Endpoints 中的端点或 Channels 中的渠道
+type RoutingRuleRecord struct {
+	ID int64 `json:"id"`
+
+	// 基本信息
+	Name         string `json:"name"`                  // 唯一名称
+	ModelPattern string `json:"model_pattern"`         // 模型名称通配符，如 claude-opus-*、*sonnet*
+	Description  string `json:"description,omitempty"` // 备注
+
+	// 允许的目标（至少配置一项）
+	Endpoints []string `json:"endpoints"` // 端点名称
+	Channels  []string `json:"channels"`  // 渠道名称
+
+	// 匹配配置
+	Priority int  `json:"priority"` // 匹配顺序（数字越小越先匹配）
+	Fallback bool `json:"fallback"` // 允许的端点均不可用时，回退到常规端点选择
+
+	Enabled bool `json:"enabled"`
+
+	// 审计字段
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoutingRuleStore 定义模型路由规则存储接口
+type RoutingRuleStore interface {
+	Create(ctx context.Context, record *RoutingRuleRecord) (*RoutingRuleRecord, error)
+	Get(ctx context.Context, name string) (*RoutingRuleRecord, error)
+	List(ctx context.Context) ([]*RoutingRuleRecord, error)
+	Update(ctx context.Context, record *RoutingRuleRecord) error
+	Delete(ctx context.Context, name string) error
+}
+
+// SQLiteRoutingRuleStore 实现 RoutingRuleStore 接口
+type SQLiteRoutingRuleStore struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+// NewSQLiteRoutingRuleStore 创建新的 SQLite 模型路由规则存储
+func NewSQLiteRoutingRuleStore(db *sql.DB) *SQLiteRoutingRuleStore {
+	return &SQLiteRoutingRuleStore{db: db}
+}
+
+// routingRuleColumns 路由规则查询列
+const routingRuleColumns = `id, name, model_pattern, COALESCE(description, ''),
+	COALESCE(endpoints, '[]'), COALESCE(channels, '[]'),
+	priority, fallback, enabled, created_at, updated_at`
+
+// Create 创建路由规则
+func (s *SQLiteRoutingRuleStore) Create(ctx context.Context, record *RoutingRuleRecord) (*RoutingRuleRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpointsJSON, channelsJSON, err := marshalRoutingTargets(record)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO model_routing_rules (
+			name, model_pattern, description, endpoints, channels, priority, fallback, enabled
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Name, record.ModelPattern, record.Description, endpointsJSON, channelsJSON,
+		record.Priority, boolToInt(record.Fallback), boolToInt(record.Enabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建路由规则失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = time.Now()
+
+	return record, nil
+}
+
+// Get 根据名称获取路由规则
+func (s *SQLiteRoutingRuleStore) Get(ctx context.Context, name string) (*RoutingRuleRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + routingRuleColumns + " FROM model_routing_rules WHERE name = ?"
+	return scanRoutingRule(s.db.QueryRowContext(ctx, query, name))
+}
+
+// List 获取所有路由规则（按匹配顺序）
+func (s *SQLiteRoutingRuleStore) List(ctx context.Context) ([]*RoutingRuleRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + routingRuleColumns + " FROM model_routing_rules ORDER BY priority ASC, name ASC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询路由规则失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*RoutingRuleRecord
+	for rows.Next() {
+		record, err := scanRoutingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历路由规则失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// Update 更新路由规则（按名称匹配）
+func (s *SQLiteRoutingRuleStore) Update(ctx context.Context, record *RoutingRuleRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpointsJSON, channelsJSON, err := marshalRoutingTargets(record)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE model_routing_rules SET
+			model_pattern = ?, description = ?, endpoints = ?, channels = ?,
+			priority = ?, fallback = ?, enabled = ?
+		WHERE name = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.ModelPattern, record.Description, endpointsJSON, channelsJSON,
+		record.Priority, boolToInt(record.Fallback), boolToInt(record.Enabled), record.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("更新路由规则失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("路由规则 '%s' %w", record.Name, ErrNotFound)
+	}
+
+	record.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 删除路由规则
+func (s *SQLiteRoutingRuleStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM model_routing_rules WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("删除路由规则失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("路由规则 '%s' %w", name, ErrNotFound)
+	}
+
+	return nil
+}
+
+// marshalRoutingTargets 序列化允许的端点和渠道列表
+func marshalRoutingTargets(record *RoutingRuleRecord) (string, string, error) {
+	endpoints := record.Endpoints
+	if endpoints == nil {
+		endpoints = []string{}
+	}
+	channels := record.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+
+	endpointsJSON, err := json.Marshal(endpoints)
+	if err != nil {
+		return "", "", fmt.Errorf("序列化端点列表失败: %w", err)
+	}
+	channelsJSON, err := json.Marshal(channels)
+	if err != nil {
+		return "", "", fmt.Errorf("序列化渠道列表失败: %w", err)
+	}
+
+	return string(endpointsJSON), string(channelsJSON), nil
+}
+
+// scanRoutingRule 扫描单条路由规则（sql.Row 或 sql.Rows）
+func scanRoutingRule(row interface{ Scan(dest ...any) error }) (*RoutingRuleRecord, error) {
+	var record RoutingRuleRecord
+	var endpointsJSON, channelsJSON string
+	var fallback, enabled int
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&record.ID, &record.Name, &record.ModelPattern, &record.Description,
+		&endpointsJSON, &channelsJSON,
+		&record.Priority, &fallback, &enabled, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("扫描路由规则失败: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(endpointsJSON), &record.Endpoints); err != nil {
+		return nil, fmt.Errorf("解析端点列表失败: %w", err)
+	}
+	if err := json.Unmarshal([]byte(channelsJSON), &record.Channels); err != nil {
+		return nil, fmt.Errorf("解析渠道列表失败: %w", err)
+	}
+
+	record.Fallback = fallback == 1
+	record.Enabled = enabled == 1
+	record.CreatedAt, _ = parseStoreTime(createdAt)
+	record.UpdatedAt, _ = parseStoreTime(updatedAt)
+
+	return &record, nil
+}
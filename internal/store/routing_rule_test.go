@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// createRoutingRuleTestDB 创建路由规则测试数据库
+func createRoutingRuleTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "routing_rule_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS model_routing_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			model_pattern TEXT NOT NULL,
+			description TEXT,
+			endpoints TEXT DEFAULT '[]',
+			channels TEXT DEFAULT '[]',
+			priority INTEGER DEFAULT 0,
+			fallback INTEGER DEFAULT 0,
+			enabled INTEGER DEFAULT 1,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestRoutingRuleCRUD 测试路由规则增删改查
+func TestRoutingRuleCRUD(t *testing.T) {
+	db, cleanup := createRoutingRuleTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteRoutingRuleStore(db)
+	ctx := context.Background()
+
+	_, err := s.Create(ctx, &RoutingRuleRecord{
+		Name:         "opus",
+		ModelPattern: "claude-opus-*",
+		Endpoints:    []string{"relay-a", "relay-b"},
+		Priority:     2,
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	_, err = s.Create(ctx, &RoutingRuleRecord{
+		Name:         "sonnet",
+		ModelPattern: "*sonnet*",
+		Channels:     []string{"官方"},
+		Priority:     1,
+		Fallback:     true,
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	// 重复名称应失败
+	if _, err := s.Create(ctx, &RoutingRuleRecord{Name: "opus", ModelPattern: "*"}); err == nil {
+		t.Error("重复名称应创建失败")
+	}
+
+	got, err := s.Get(ctx, "opus")
+	if err != nil || got == nil {
+		t.Fatalf("获取失败: %v", err)
+	}
+	if got.ModelPattern != "claude-opus-*" || len(got.Endpoints) != 2 || got.Endpoints[1] != "relay-b" {
+		t.Errorf("记录字段不匹配: %+v", got)
+	}
+	if got.Channels == nil || len(got.Channels) != 0 {
+		t.Errorf("未配置渠道时应为空列表: %#v", got.Channels)
+	}
+	if got.Fallback || !got.Enabled || got.CreatedAt.IsZero() {
+		t.Errorf("记录字段不匹配: %+v", got)
+	}
+
+	missing, err := s.Get(ctx, "nobody")
+	if err != nil || missing != nil {
+		t.Errorf("不存在的记录应返回 nil, nil: %v, %+v", err, missing)
+	}
+
+	// 列表按匹配顺序返回
+	list, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "sonnet" || list[1].Name != "opus" {
+		t.Errorf("列表顺序不匹配: %d 条", len(list))
+	}
+	if list[0].Channels[0] != "官方" || !list[0].Fallback {
+		t.Errorf("记录字段不匹配: %+v", list[0])
+	}
+
+	// 更新
+	got.Endpoints = []string{"relay-c"}
+	got.Fallback = true
+	got.Enabled = false
+	if err := s.Update(ctx, got); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	updated, _ := s.Get(ctx, "opus")
+	if len(updated.Endpoints) != 1 || updated.Endpoints[0] != "relay-c" || !updated.Fallback || updated.Enabled {
+		t.Errorf("更新未生效: %+v", updated)
+	}
+
+	if err := s.Update(ctx, &RoutingRuleRecord{Name: "nobody", ModelPattern: "*"}); err == nil {
+		t.Error("更新不存在的记录应返回错误")
+	}
+
+	if err := s.Delete(ctx, "opus"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if err := s.Delete(ctx, "opus"); err == nil {
+		t.Error("删除不存在的记录应返回错误")
+	}
+}
@@ -20,7 +20,7 @@ type SettingRecord struct {
 
 	// 设置值
 	Value     string `json:"value"`      // 值 (支持 JSON)
-	ValueType string `json:"value_type"` // 类型: string, int, float, bool, duration, json
+	ValueType string `json:"value_type"` // 类型: string, int, float, bool, duration, json, text
 
 	// 显示信息
 	Label        string `json:"label"`         // 显示名称
@@ -183,7 +183,7 @@ func (s *SQLiteSettingsStore) Delete(ctx context.Context, category, key string)
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("设置不存在: %s.%s", category, key)
+		return fmt.Errorf("设置%w: %s.%s", ErrNotFound, category, key)
 	}
 
 	return nil
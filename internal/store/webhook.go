@@ -0,0 +1,351 @@
+// Package store 提供数据存储层实现
+// Webhook 存储 - 运维事件外发通知的目标配置和投递记录
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WebhookRecord 表示数据库中的 Webhook 配置
+type WebhookRecord struct {
+	ID int64 `json:"id"`
+
+	// 基本信息
+	Name        string `json:"name"`                  // 唯一名称
+	URL         string `json:"url"`                   // 投递地址
+	Format      string `json:"format"`                // 负载格式：json / slack / feishu / dingtalk
+	Description string `json:"description,omitempty"` // 备注
+
+	// 投递配置
+	EventTypes []string `json:"event_types"`        // 订阅的事件类型（为空时使用默认告警事件）
+	Template   string   `json:"template,omitempty"` // 自定义消息模板（Go text/template）
+	Secret     string   `json:"-"`                  // 签名密钥
+	MaxRetries int      `json:"max_retries"`        // 失败后的最大重试次数
+
+	Enabled bool `json:"enabled"`
+
+	// 审计字段
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryRecord 表示一次 Webhook 投递记录（含重试）
+type WebhookDeliveryRecord struct {
+	ID         int64     `json:"id"`
+	Webhook    string    `json:"webhook"`     // Webhook 名称
+	EventType  string    `json:"event_type"`  // 事件类型
+	Success    bool      `json:"success"`     // 是否投递成功
+	Attempts   int       `json:"attempts"`    // 请求次数
+	StatusCode int       `json:"status_code"` // 最后一次响应状态码（网络错误为 0）
+	Error      string    `json:"error,omitempty"`
+	Payload    string    `json:"payload,omitempty"` // 请求体
+	DurationMs int64     `json:"duration_ms"`       // 总耗时（含重试等待）
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookStore 定义 Webhook 存储接口
+type WebhookStore interface {
+	Create(ctx context.Context, record *WebhookRecord) (*WebhookRecord, error)
+	Get(ctx context.Context, name string) (*WebhookRecord, error)
+	List(ctx context.Context) ([]*WebhookRecord, error)
+	Update(ctx context.Context, record *WebhookRecord) error
+	Delete(ctx context.Context, name string) error
+
+	// RecordDelivery 记录一次投递
+	RecordDelivery(ctx context.Context, record *WebhookDeliveryRecord) error
+	// ListDeliveries 按时间倒序列出投递记录，webhook 为空时列出全部
+	ListDeliveries(ctx context.Context, webhook string, limit int) ([]*WebhookDeliveryRecord, error)
+	// TrimDeliveries 只保留最近 keep 条投递记录，返回删除数量
+	TrimDeliveries(ctx context.Context, keep int) (int64, error)
+}
+
+// SQLiteWebhookStore 实现 WebhookStore 接口
+type SQLiteWebhookStore struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+// NewSQLiteWebhookStore 创建新的 SQLite Webhook 存储
+func NewSQLiteWebhookStore(db *sql.DB) *SQLiteWebhookStore {
+	return &SQLiteWebhookStore{db: db}
+}
+
+// webhookColumns Webhook 查询列
+const webhookColumns = `id, name, url, format, COALESCE(description, ''),
+	COALESCE(event_types, '[]'), COALESCE(template, ''), COALESCE(secret, ''),
+	max_retries, enabled, created_at, updated_at`
+
+// webhookDeliveryColumns 投递记录查询列
+const webhookDeliveryColumns = `id, webhook, event_type, success, attempts, status_code,
+	COALESCE(error, ''), COALESCE(payload, ''), duration_ms, created_at`
+
+// Create 创建 Webhook
+func (s *SQLiteWebhookStore) Create(ctx context.Context, record *WebhookRecord) (*WebhookRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	eventTypesJSON, err := marshalEventTypes(record.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO webhooks (
+			name, url, format, description, event_types, template, secret, max_retries, enabled
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Name, record.URL, record.Format, record.Description, eventTypesJSON,
+		record.Template, record.Secret, record.MaxRetries, boolToInt(record.Enabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Webhook 失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = time.Now()
+
+	return record, nil
+}
+
+// Get 根据名称获取 Webhook
+func (s *SQLiteWebhookStore) Get(ctx context.Context, name string) (*WebhookRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE name = ?"
+	return scanWebhook(s.db.QueryRowContext(ctx, query, name))
+}
+
+// List 获取所有 Webhook
+func (s *SQLiteWebhookStore) List(ctx context.Context) ([]*WebhookRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + webhookColumns + " FROM webhooks ORDER BY name ASC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询 Webhook 失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*WebhookRecord
+	for rows.Next() {
+		record, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历 Webhook 失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// Update 更新 Webhook（按名称匹配）
+func (s *SQLiteWebhookStore) Update(ctx context.Context, record *WebhookRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	eventTypesJSON, err := marshalEventTypes(record.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhooks SET
+			url = ?, format = ?, description = ?, event_types = ?,
+			template = ?, secret = ?, max_retries = ?, enabled = ?
+		WHERE name = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.URL, record.Format, record.Description, eventTypesJSON,
+		record.Template, record.Secret, record.MaxRetries, boolToInt(record.Enabled), record.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("更新 Webhook 失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("Webhook '%s' %w", record.Name, ErrNotFound)
+	}
+
+	record.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 删除 Webhook（投递记录保留）
+func (s *SQLiteWebhookStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhooks WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("删除 Webhook 失败: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取影响行数失败: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("Webhook '%s' %w", name, ErrNotFound)
+	}
+
+	return nil
+}
+
+// RecordDelivery 记录一次投递
+func (s *SQLiteWebhookStore) RecordDelivery(ctx context.Context, record *WebhookDeliveryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		INSERT INTO webhook_deliveries (
+			webhook, event_type, success, attempts, status_code, error, payload, duration_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		record.Webhook, record.EventType, boolToInt(record.Success), record.Attempts,
+		record.StatusCode, record.Error, record.Payload, record.DurationMs,
+	)
+	if err != nil {
+		return fmt.Errorf("记录 Webhook 投递失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取插入 ID 失败: %w", err)
+	}
+
+	record.ID = id
+	record.CreatedAt = time.Now()
+	return nil
+}
+
+// ListDeliveries 按时间倒序列出投递记录
+func (s *SQLiteWebhookStore) ListDeliveries(ctx context.Context, webhook string, limit int) ([]*WebhookDeliveryRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries"
+	var args []interface{}
+	if webhook != "" {
+		query += " WHERE webhook = ?"
+		args = append(args, webhook)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 Webhook 投递记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*WebhookDeliveryRecord
+	for rows.Next() {
+		var record WebhookDeliveryRecord
+		var success int
+		var createdAt string
+		if err := rows.Scan(
+			&record.ID, &record.Webhook, &record.EventType, &success, &record.Attempts, &record.StatusCode,
+			&record.Error, &record.Payload, &record.DurationMs, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描 Webhook 投递记录失败: %w", err)
+		}
+		record.Success = success == 1
+		record.CreatedAt, _ = parseStoreTime(createdAt)
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历 Webhook 投递记录失败: %w", err)
+	}
+
+	return records, nil
+}
+
+// TrimDeliveries 只保留最近 keep 条投递记录
+func (s *SQLiteWebhookStore) TrimDeliveries(ctx context.Context, keep int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		DELETE FROM webhook_deliveries WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			ORDER BY id DESC
+			LIMIT -1 OFFSET ?
+		)
+	`
+
+	result, err := s.db.ExecContext(ctx, query, keep)
+	if err != nil {
+		return 0, fmt.Errorf("清理 Webhook 投递记录失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// marshalEventTypes 序列化订阅的事件类型
+func marshalEventTypes(eventTypes []string) (string, error) {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	data, err := json.Marshal(eventTypes)
+	if err != nil {
+		return "", fmt.Errorf("序列化事件类型失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// scanWebhook 扫描单条 Webhook（sql.Row 或 sql.Rows）
+func scanWebhook(row interface{ Scan(dest ...any) error }) (*WebhookRecord, error) {
+	var record WebhookRecord
+	var eventTypesJSON string
+	var enabled int
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&record.ID, &record.Name, &record.URL, &record.Format, &record.Description,
+		&eventTypesJSON, &record.Template, &record.Secret,
+		&record.MaxRetries, &enabled, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("扫描 Webhook 失败: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(eventTypesJSON), &record.EventTypes); err != nil {
+		return nil, fmt.Errorf("解析事件类型失败: %w", err)
+	}
+
+	record.Enabled = enabled == 1
+	record.CreatedAt, _ = parseStoreTime(createdAt)
+	record.UpdatedAt, _ = parseStoreTime(updatedAt)
+
+	return &record, nil
+}
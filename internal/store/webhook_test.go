@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// createWebhookTestDB 创建 Webhook 测试数据库
+func createWebhookTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "webhook_store_test_*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			url TEXT NOT NULL,
+			format TEXT NOT NULL DEFAULT 'json',
+			description TEXT,
+			event_types TEXT DEFAULT '[]',
+			template TEXT,
+			secret TEXT,
+			max_retries INTEGER DEFAULT 3,
+			enabled INTEGER DEFAULT 1,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			webhook TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			success INTEGER DEFAULT 0,
+			attempts INTEGER DEFAULT 0,
+			status_code INTEGER DEFAULT 0,
+			error TEXT,
+			payload TEXT,
+			duration_ms INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+// TestWebhookCRUD 测试 Webhook 增删改查
+func TestWebhookCRUD(t *testing.T) {
+	db, cleanup := createWebhookTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteWebhookStore(db)
+	ctx := context.Background()
+
+	_, err := s.Create(ctx, &WebhookRecord{
+		Name:       "ops-feishu",
+		URL:        "https://open.feishu.cn/open-apis/bot/v2/hook/x",
+		Format:     "feishu",
+		EventTypes: []string{"endpoint_unhealthy", "group_cooldown"},
+		Secret:     "s3cret",
+		MaxRetries: 2,
+		Enabled:    true,
+	})
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	if _, err := s.Create(ctx, &WebhookRecord{Name: "ops-feishu", URL: "http://x", Format: "json"}); err == nil {
+		t.Error("重复名称应创建失败")
+	}
+
+	got, err := s.Get(ctx, "ops-feishu")
+	if err != nil || got == nil {
+		t.Fatalf("获取失败: %v", err)
+	}
+	if got.Format != "feishu" || len(got.EventTypes) != 2 || got.EventTypes[1] != "group_cooldown" ||
+		got.Secret != "s3cret" || got.MaxRetries != 2 || !got.Enabled || got.CreatedAt.IsZero() {
+		t.Errorf("记录字段不匹配: %+v", got)
+	}
+
+	missing, err := s.Get(ctx, "nobody")
+	if err != nil || missing != nil {
+		t.Errorf("不存在的记录应返回 nil, nil: %v, %+v", err, missing)
+	}
+
+	got.EventTypes = nil
+	got.Template = "{{.Title}}"
+	got.Enabled = false
+	if err := s.Update(ctx, got); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	updated, _ := s.Get(ctx, "ops-feishu")
+	if updated.EventTypes == nil || len(updated.EventTypes) != 0 || updated.Template != "{{.Title}}" || updated.Enabled {
+		t.Errorf("更新未生效: %+v", updated)
+	}
+
+	if err := s.Update(ctx, &WebhookRecord{Name: "nobody"}); err == nil {
+		t.Error("更新不存在的记录应返回错误")
+	}
+
+	list, err := s.List(ctx)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("列出失败: %v, %d 条", err, len(list))
+	}
+
+	if err := s.Delete(ctx, "ops-feishu"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if err := s.Delete(ctx, "ops-feishu"); err == nil {
+		t.Error("删除不存在的记录应返回错误")
+	}
+}
+
+// TestWebhookDeliveries 测试投递记录的写入、查询和清理
+func TestWebhookDeliveries(t *testing.T) {
+	db, cleanup := createWebhookTestDB(t)
+	defer cleanup()
+
+	s := NewSQLiteWebhookStore(db)
+	ctx := context.Background()
+
+	for i, name := range []string{"a", "b", "a", "a"} {
+		record := &WebhookDeliveryRecord{
+			Webhook:    name,
+			EventType:  "endpoint_unhealthy",
+			Success:    i%2 == 0,
+			Attempts:   i + 1,
+			StatusCode: 200,
+			DurationMs: int64(i * 10),
+		}
+		if err := s.RecordDelivery(ctx, record); err != nil || record.ID == 0 {
+			t.Fatalf("记录投递失败: %v", err)
+		}
+	}
+
+	all, err := s.ListDeliveries(ctx, "", 10)
+	if err != nil || len(all) != 4 {
+		t.Fatalf("列出投递记录失败: %v, %d 条", err, len(all))
+	}
+	if all[0].Attempts != 4 || all[0].Success || !all[1].Success || all[0].CreatedAt.IsZero() {
+		t.Errorf("应按时间倒序返回: %+v", all[0])
+	}
+
+	onlyA, _ := s.ListDeliveries(ctx, "a", 2)
+	if len(onlyA) != 2 || onlyA[0].Webhook != "a" || onlyA[1].Attempts != 3 {
+		t.Errorf("按名称过滤不符: %+v", onlyA)
+	}
+
+	deleted, err := s.TrimDeliveries(ctx, 2)
+	if err != nil || deleted != 2 {
+		t.Fatalf("清理投递记录失败: %v, %d", err, deleted)
+	}
+	remaining, _ := s.ListDeliveries(ctx, "", 10)
+	if len(remaining) != 2 || remaining[1].Attempts != 3 {
+		t.Errorf("应保留最近的投递记录: %+v", remaining)
+	}
+}
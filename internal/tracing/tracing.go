@@ -0,0 +1,109 @@
+// Package tracing 提供 OpenTelemetry 链路追踪：OTLP/HTTP 导出和 W3C traceparent 传播
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"cc-forwarder/config"
+)
+
+// instrumentationName 本程序创建的 span 所属的 instrumentation scope
+const instrumentationName = "cc-forwarder"
+
+// defaultTracesPath OTLP/HTTP 的默认 traces 路径
+const defaultTracesPath = "/v1/traces"
+
+// Setup 按配置初始化全局 TracerProvider 和传播器
+// 未启用时不做任何修改（全局 Tracer 为 no-op），返回的 shutdown 函数负责刷新并关闭导出器
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpointURL, err := tracesURL(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpointURL)}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(cfg.Timeout))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 OTLP 导出器失败: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("创建追踪资源失败: %w", err)
+	}
+
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Warn(fmt.Sprintf("⚠️ [链路追踪] %v", err))
+	}))
+
+	slog.Info(fmt.Sprintf("🔭 [链路追踪] 已启用，导出地址: %s, 服务名: %s, 采样比例: %g",
+		endpointURL, cfg.ServiceName, ratio))
+
+	return provider.Shutdown, nil
+}
+
+// tracesURL 补全采集器地址的 traces 路径（仅填写了主机地址时使用 /v1/traces）
+func tracesURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("无效的追踪采集器地址: %s", endpoint)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = defaultTracesPath
+	}
+	return u.String(), nil
+}
+
+// Tracer 返回本程序使用的 Tracer（未启用追踪时为 no-op）
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Extract 从请求头中提取上游调用方的 traceparent
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject 将当前 span 的 traceparent 写入转发给端点的请求头
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
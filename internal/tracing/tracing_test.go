@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"cc-forwarder/config"
+)
+
+func TestTracesURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:4318":              "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":             "http://localhost:4318/v1/traces",
+		"https://otel.example.com/custom/v1": "https://otel.example.com/custom/v1",
+	}
+	for in, want := range tests {
+		got, err := tracesURL(in)
+		if err != nil {
+			t.Fatalf("%s: 解析失败: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("tracesURL(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+
+	if _, err := tracesURL("localhost:4318"); err == nil {
+		t.Error("缺少协议的地址应返回错误")
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Enabled: false})
+	if err != nil {
+		t.Fatalf("未启用时不应返回错误: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown 返回错误: %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "noop")
+	defer span.End()
+	if span.SpanContext().IsValid() {
+		t.Error("未启用追踪时 span 不应有效")
+	}
+}
+
+func TestSetup_ExportAndPropagate(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		paths   []string
+		headers []string
+		bodies  int
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		headers = append(headers, r.Header.Get("X-Collector-Token"))
+		bodies += len(body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}()
+
+	ratio := 1.0
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Enabled:     true,
+		Endpoint:    collector.URL,
+		ServiceName: "cc-forwarder-test",
+		SampleRatio: &ratio,
+		Headers:     map[string]string{"X-Collector-Token": "secret"},
+		Timeout:     5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	// 沿用客户端 traceparent：新 span 与调用方属于同一条链路
+	incoming := http.Header{}
+	incoming.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, span := Tracer().Start(Extract(context.Background(), incoming), "proxy.request")
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+	span.End()
+
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %s，期望沿用客户端的 TraceID", got)
+	}
+	if tp := outgoing.Get("traceparent"); tp != "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01" {
+		t.Errorf("转发的 traceparent = %q", tp)
+	}
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown 失败: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) == 0 || bodies == 0 {
+		t.Fatal("采集器未收到任何 span")
+	}
+	if paths[0] != "/v1/traces" {
+		t.Errorf("导出路径 = %s，期望 /v1/traces", paths[0])
+	}
+	if headers[0] != "secret" {
+		t.Errorf("导出请求缺少配置的头部")
+	}
+}
@@ -40,6 +40,7 @@ type ArchiveManager struct {
 	adapter     DatabaseAdapter
 	config      ArchiveManagerConfig
 	pricing     map[string]ModelPricing       // 模型定价缓存
+	pricingHist PricingHistory                // 模型定价历史
 	endpointMu  map[string]EndpointMultiplier // 端点倍率缓存
 	location    *time.Location
 
@@ -123,6 +124,11 @@ func (am *ArchiveManager) UpdatePricing(pricing map[string]ModelPricing) {
 	am.pricing = pricing
 }
 
+// UpdatePricingHistory 更新模型定价历史（运行时动态更新）
+func (am *ArchiveManager) UpdatePricingHistory(history PricingHistory) {
+	am.pricingHist = history
+}
+
 // Archive 发送请求到归档通道
 func (am *ArchiveManager) Archive(req *ActiveRequest) error {
 	event := &ArchiveEvent{
@@ -296,18 +302,18 @@ func (am *ArchiveManager) batchInsert(events []*ArchiveEvent) error {
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO request_logs (
 			request_id, client_ip, user_agent, method, path,
-			start_time, end_time, duration_ms,
+			start_time, end_time, duration_ms, ttft_ms, output_tokens_per_sec,
 			channel, endpoint_name, group_name, model_name,
 			status, http_status_code, retry_count,
 			failure_reason, cancel_reason,
-			is_streaming,
+			is_streaming, client_key_name,
 			input_tokens, output_tokens,
 			cache_creation_tokens, cache_creation_5m_tokens, cache_creation_1h_tokens,
 			cache_read_tokens,
 			input_cost_usd, output_cost_usd,
 			cache_creation_cost_usd, cache_creation_5m_cost_usd, cache_creation_1h_cost_usd,
 			cache_read_cost_usd, total_cost_usd
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -338,6 +344,8 @@ func (am *ArchiveManager) batchInsert(events []*ArchiveEvent) error {
 			startTime,
 			endTime,
 			req.DurationMs,
+			nullInt64(req.TTFTMs),
+			nullFloat64(req.OutputTokensPerSec),
 			req.Channel,
 			req.EndpointName,
 			req.GroupName,
@@ -348,6 +356,7 @@ func (am *ArchiveManager) batchInsert(events []*ArchiveEvent) error {
 			nullString(req.FailureReason),
 			nullString(req.CancelReason),
 			req.IsStreaming,
+			req.ClientKey,
 			req.InputTokens,
 			req.OutputTokens,
 			req.CacheCreationTokens,
@@ -376,17 +385,15 @@ func (am *ArchiveManager) batchInsert(events []*ArchiveEvent) error {
 
 // calculateCostV2 计算请求成本（v5.0.1+: 支持分开的 5m/1h 缓存）
 func (am *ArchiveManager) calculateCostV2(req *ActiveRequest) CostBreakdown {
-	if am.pricing == nil {
+	// 响应缓存命中未消耗上游 Token，不计成本
+	if (am.pricing == nil && am.pricingHist == nil) || req.Status == "cache_hit" {
 		return CostBreakdown{}
 	}
 
-	// 查找模型定价，不存在则回退到 _default 定价
-	pricing, exists := am.pricing[req.ModelName]
+	// 查找请求开始时生效的模型定价，不存在则回退到 _default 定价
+	pricing, exists := resolvePricing(am.pricing, am.pricingHist, req.ModelName, req.StartTime)
 	if !exists {
-		pricing, exists = am.pricing["_default"]
-		if !exists {
-			return CostBreakdown{}
-		}
+		return CostBreakdown{}
 	}
 
 	// 获取端点倍率
@@ -428,6 +435,22 @@ func nullString(s string) interface{} {
 	return s
 }
 
+// nullInt64 处理 0 为SQL NULL（未采集）
+func nullInt64(v int64) interface{} {
+	if v == 0 {
+		return sql.NullInt64{}
+	}
+	return v
+}
+
+// nullFloat64 处理 0 为SQL NULL（未采集）
+func nullFloat64(v float64) interface{} {
+	if v == 0 {
+		return sql.NullFloat64{}
+	}
+	return v
+}
+
 // GetStats 获取统计信息（返回快照，不含锁）
 func (am *ArchiveManager) GetStats() ArchiveStats {
 	am.stats.mu.RLock()
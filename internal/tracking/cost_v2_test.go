@@ -391,3 +391,27 @@ func TestCalculateCost_BackwardCompatible(t *testing.T) {
 		t.Errorf("CalculateCost(use1hCache=true): Expected CacheCreation5mCost $0, got $%f", result1h.CacheCreation5mCost)
 	}
 }
+
+// TestArchiveCost_CacheHit 测试响应缓存命中的请求归档时不计成本
+func TestArchiveCost_CacheHit(t *testing.T) {
+	am := &ArchiveManager{
+		pricing: map[string]ModelPricing{
+			"claude-sonnet-4": {Input: 3.0, Output: 15.0},
+		},
+	}
+
+	req := &ActiveRequest{
+		ModelName:    "claude-sonnet-4",
+		Status:       "completed",
+		InputTokens:  1000000,
+		OutputTokens: 100000,
+	}
+	if cost := am.calculateCostV2(req); math.Abs(cost.TotalCost-4.5) > 0.001 {
+		t.Errorf("completed: Expected TotalCost $4.50, got $%f", cost.TotalCost)
+	}
+
+	req.Status = "cache_hit"
+	if cost := am.calculateCostV2(req); cost.TotalCost != 0 || cost.InputCost != 0 || cost.OutputCost != 0 {
+		t.Errorf("cache_hit: Expected zero cost, got %+v", cost)
+	}
+}
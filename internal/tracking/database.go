@@ -190,6 +190,8 @@ func (ut *UsageTracker) buildWriteQuery(event RequestEvent) (string, []interface
 		return ut.buildFlexibleUpdateQuery(event)
 	case "success": // 新增：成功完成，替代"complete"
 		return ut.buildSuccessQuery(event)
+	case "cache_hit": // 响应缓存命中，不计成本
+		return ut.buildCacheHitQuery(event)
 	case "final_failure": // 新增：失败/取消完成
 		return ut.buildFinalFailureQuery(event)
 	case "complete":
@@ -207,7 +209,8 @@ func (ut *UsageTracker) buildWriteQuery(event RequestEvent) (string, []interface
 			CacheReadTokens:     data.CacheReadTokens,
 		}
 
-		inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens)
+		startTime := ut.requestStartTime(event.RequestID, event.Timestamp.Add(-data.Duration))
+		inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens, startTime)
 
 		query := fmt.Sprintf(`UPDATE request_logs SET
 			end_time = ?,
@@ -260,7 +263,7 @@ func (ut *UsageTracker) buildWriteQuery(event RequestEvent) (string, []interface
 			CacheReadTokens:       data.CacheReadTokens,
 		}
 
-		pricing := ut.GetPricing(data.ModelName)
+		pricing := ut.GetPricingAt(data.ModelName, ut.requestStartTime(event.RequestID, event.Timestamp.Add(-data.Duration)))
 		costBreakdown := CalculateCostV2(tokens, &pricing, nil)
 
 		// 只更新Token相关字段和成本，不更新状态
@@ -320,7 +323,7 @@ func (ut *UsageTracker) buildWriteQuery(event RequestEvent) (string, []interface
 			CacheReadTokens:       data.CacheReadTokens,
 		}
 
-		pricing := ut.GetPricing(data.ModelName)
+		pricing := ut.GetPricingAt(data.ModelName, ut.requestStartTime(event.RequestID, event.Timestamp.Add(-data.Duration)))
 		costBreakdown := CalculateCostV2(tokens, &pricing, nil)
 
 		// 🔧 专用于恢复场景：更新任何状态的请求的Token字段，因为这是恢复不完整的数据
@@ -374,8 +377,8 @@ func (ut *UsageTracker) buildStartQuery(event RequestEvent) (string, []interface
 	}
 
 	// 使用适配器构建INSERT OR REPLACE查询
-	columns := []string{"request_id", "client_ip", "user_agent", "method", "path", "start_time", "status", "is_streaming", "updated_at"}
-	placeholders := []string{"?", "?", "?", "?", "?", "?", "'pending'", "?", ut.adapter.BuildDateTimeNow()}
+	columns := []string{"request_id", "client_ip", "user_agent", "method", "path", "start_time", "status", "is_streaming", "client_key_name", "updated_at"}
+	placeholders := []string{"?", "?", "?", "?", "?", "?", "'pending'", "?", "?", ut.adapter.BuildDateTimeNow()}
 
 	query := ut.adapter.BuildInsertOrReplaceQuery("request_logs", columns, placeholders)
 
@@ -387,6 +390,7 @@ func (ut *UsageTracker) buildStartQuery(event RequestEvent) (string, []interface
 		data.Path,
 		event.Timestamp,
 		data.IsStreaming,
+		data.ClientKey,
 	}
 
 	return query, args, nil
@@ -490,6 +494,14 @@ func (ut *UsageTracker) buildFlexibleUpdateQuery(event RequestEvent) (string, []
 		setParts = append(setParts, "failure_reason = ?")
 		args = append(args, *opts.FailureReason)
 	}
+	if opts.TTFTMs != nil {
+		setParts = append(setParts, "ttft_ms = ?")
+		args = append(args, *opts.TTFTMs)
+	}
+	if opts.OutputTokensPerSec != nil {
+		setParts = append(setParts, "output_tokens_per_sec = ?")
+		args = append(args, *opts.OutputTokensPerSec)
+	}
 
 	// 如果没有字段需要更新，返回错误
 	if len(setParts) == 0 {
@@ -526,7 +538,8 @@ func (ut *UsageTracker) buildSuccessQuery(event RequestEvent) (string, []interfa
 		CacheReadTokens:     data.CacheReadTokens,
 	}
 
-	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens)
+	startTime := ut.requestStartTime(event.RequestID, event.Timestamp.Add(-data.Duration))
+	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens, startTime)
 
 	// 🔧 [方案A补充] 支持 failure_reason 写入（用于数据质量标记，如 stream_truncated）
 	query := fmt.Sprintf(`UPDATE request_logs SET
@@ -568,6 +581,47 @@ func (ut *UsageTracker) buildSuccessQuery(event RequestEvent) (string, []interfa
 	return query, args, nil
 }
 
+// buildCacheHitQuery 构建响应缓存命中的查询
+// Token 仅用于展示，成本字段全部为 0
+func (ut *UsageTracker) buildCacheHitQuery(event RequestEvent) (string, []interface{}, error) {
+	data, ok := event.Data.(RequestCompleteData)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid cache_hit event data type")
+	}
+
+	query := fmt.Sprintf(`UPDATE request_logs SET
+		end_time = ?,
+		duration_ms = ?,
+		model_name = ?,
+		input_tokens = ?,
+		output_tokens = ?,
+		cache_creation_tokens = ?,
+		cache_read_tokens = ?,
+		input_cost_usd = 0,
+		output_cost_usd = 0,
+		cache_creation_cost_usd = 0,
+		cache_read_cost_usd = 0,
+		total_cost_usd = 0,
+		failure_reason = NULL,
+		http_status_code = 200,
+		status = 'cache_hit',
+		updated_at = %s
+	WHERE request_id = ?`, ut.adapter.BuildDateTimeNow())
+
+	args := []interface{}{
+		event.Timestamp,
+		data.Duration.Milliseconds(),
+		data.ModelName,
+		data.InputTokens,
+		data.OutputTokens,
+		data.CacheCreationTokens,
+		data.CacheReadTokens,
+		event.RequestID,
+	}
+
+	return query, args, nil
+}
+
 // buildFinalFailureQuery 构建失败/取消完成的查询
 // 一次性更新所有失败/取消相关字段：status, end_time, duration_ms, failure_reason/cancel_reason, 可选Token
 // 🔧 [修复] 2025-12-11: 添加 model_name 和 5m/1h 缓存字段支持
@@ -698,7 +752,7 @@ func (ut *UsageTracker) buildCompleteQueryWithTx(ctx context.Context, tx *sql.Tx
 		CacheReadTokens:     data.CacheReadTokens,
 	}
 
-	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens)
+	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens, startTime)
 
 	// 使用计算出的准确持续时间
 	query := fmt.Sprintf(`UPDATE request_logs SET
@@ -745,8 +799,8 @@ func (ut *UsageTracker) insertRequestStart(ctx context.Context, tx *sql.Tx, even
 	}
 
 	// 使用适配器构建INSERT OR REPLACE查询
-	columns := []string{"request_id", "client_ip", "user_agent", "method", "path", "start_time", "status", "is_streaming", "updated_at"}
-	placeholders := []string{"?", "?", "?", "?", "?", "?", "'pending'", "?", ut.adapter.BuildDateTimeNow()}
+	columns := []string{"request_id", "client_ip", "user_agent", "method", "path", "start_time", "status", "is_streaming", "client_key_name", "updated_at"}
+	placeholders := []string{"?", "?", "?", "?", "?", "?", "'pending'", "?", "?", ut.adapter.BuildDateTimeNow()}
 	query := ut.adapter.BuildInsertOrReplaceQuery("request_logs", columns, placeholders)
 
 	_, err := tx.ExecContext(ctx, query,
@@ -756,7 +810,8 @@ func (ut *UsageTracker) insertRequestStart(ctx context.Context, tx *sql.Tx, even
 		data.Method,
 		data.Path,
 		event.Timestamp,
-		data.IsStreaming)
+		data.IsStreaming,
+		data.ClientKey)
 
 	return err
 }
@@ -893,7 +948,7 @@ func (ut *UsageTracker) completeRequest(ctx context.Context, tx *sql.Tx, event R
 		CacheReadTokens:     data.CacheReadTokens,
 	}
 
-	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens)
+	inputCost, outputCost, cacheCost, readCost, totalCost := ut.calculateCost(data.ModelName, tokens, startTime)
 
 	query := fmt.Sprintf(`UPDATE request_logs SET
 		end_time = ?,
@@ -967,9 +1022,9 @@ func (ut *UsageTracker) completeRequest(ctx context.Context, tx *sql.Tx, event R
 	return err
 }
 
-// calculateCost 计算请求成本
-func (ut *UsageTracker) calculateCost(modelName string, tokens *TokenUsage) (inputCost, outputCost, cacheCost, readCost, totalCost float64) {
-	pricing := ut.GetPricing(modelName)
+// calculateCost 按请求开始时生效的定价计算请求成本
+func (ut *UsageTracker) calculateCost(modelName string, tokens *TokenUsage, startTime time.Time) (inputCost, outputCost, cacheCost, readCost, totalCost float64) {
+	pricing := ut.GetPricingAt(modelName, startTime)
 
 	inputCost = float64(tokens.InputTokens) * pricing.Input / 1000000
 	outputCost = float64(tokens.OutputTokens) * pricing.Output / 1000000
@@ -980,6 +1035,24 @@ func (ut *UsageTracker) calculateCost(modelName string, tokens *TokenUsage) (inp
 	return
 }
 
+// requestStartTime 查询请求记录的开始时间，用于选择请求开始时生效的定价
+// 写操作按队列顺序执行，start 事件已先于完成事件写入；记录不存在或查询失败时返回 fallback
+func (ut *UsageTracker) requestStartTime(requestID string, fallback time.Time) time.Time {
+	if ut.readDB == nil {
+		return fallback
+	}
+	// start_time 转为文本读取，避免驱动按 UTC 解析无时区的时间
+	var value string
+	err := ut.readDB.QueryRowContext(ut.ctx, `SELECT CAST(start_time AS TEXT) FROM request_logs WHERE request_id = ?`, requestID).Scan(&value)
+	if err != nil {
+		return fallback
+	}
+	if startTime := ut.parseStoredTime(value); !startTime.IsZero() {
+		return startTime
+	}
+	return fallback
+}
+
 // periodicCleanup 定期清理历史数据
 func (ut *UsageTracker) periodicCleanup() {
 	defer ut.wg.Done()
@@ -1108,32 +1181,48 @@ func (ut *UsageTracker) updateUsageSummary() {
 	endDate := time.Now()
 	startDate := endDate.AddDate(0, 0, -7)
 
-	// 使用适配器构建INSERT OR REPLACE查询
-	columns := []string{
-		"date", "model_name", "endpoint_name", "group_name",
-		"request_count", "success_count", "error_count",
-		"total_input_tokens", "total_output_tokens",
-		"total_cache_creation_tokens", "total_cache_read_tokens",
-		"total_cost_usd", "avg_duration_ms",
-		"created_at", "updated_at",
+	summaryWriteReq := WriteRequest{
+		Query:     ut.usageSummaryQuery(),
+		Args:      []interface{}{startDate, endDate.AddDate(0, 0, 1)},
+		Response:  make(chan error, 1),
+		Context:   context.Background(),
+		EventType: "update_summary",
 	}
 
-	placeholders := make([]string, len(columns))
-	for i := range placeholders {
-		placeholders[i] = "?"
+	select {
+	case ut.writeQueue <- summaryWriteReq:
+		err := <-summaryWriteReq.Response
+		if err != nil {
+			slog.Error("Failed to update usage summary", "error", err)
+		} else {
+			slog.Info("Usage summary updated successfully")
+		}
+	case <-ut.ctx.Done():
+		slog.Debug("Usage summary update cancelled due to context cancellation")
 	}
+}
 
-	baseQuery := ut.adapter.BuildInsertOrReplaceQuery("usage_summary", columns, placeholders)
-
-	// 构建SELECT子查询
-	selectQuery := fmt.Sprintf(`
+// usageSummaryQuery 返回按 [start, end) 范围重新汇总 usage_summary 的语句
+func (ut *UsageTracker) usageSummaryQuery() string {
+	// 汇总表以 (date, model_name, endpoint_name, group_name, client_key_name) 唯一约束，
+	// 直接使用 INSERT OR REPLACE ... SELECT 重算（request_logs 的 ON CONFLICT(request_id) 不适用于汇总表）
+	return fmt.Sprintf(`
+	INSERT OR REPLACE INTO usage_summary (
+		date, model_name, endpoint_name, group_name, client_key_name,
+		request_count, success_count, error_count,
+		total_input_tokens, total_output_tokens,
+		total_cache_creation_tokens, total_cache_read_tokens,
+		total_cost_usd, avg_duration_ms,
+		created_at, updated_at
+	)
 	SELECT
 		DATE(start_time) as date,
 		COALESCE(model_name, '') as model_name,
 		COALESCE(endpoint_name, '') as endpoint_name,
 		COALESCE(group_name, '') as group_name,
+		COALESCE(client_key_name, '') as client_key_name,
 		COUNT(*) as request_count,
-		SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) as success_count,
+		SUM(CASE WHEN status IN ('completed', 'cache_hit') THEN 1 ELSE 0 END) as success_count,
 		SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END) as error_count,
 		SUM(input_tokens) as total_input_tokens,
 		SUM(output_tokens) as total_output_tokens,
@@ -1146,31 +1235,8 @@ func (ut *UsageTracker) updateUsageSummary() {
 	FROM request_logs
 	WHERE start_time >= ? AND start_time < ?
 		AND (model_name IS NOT NULL OR endpoint_name IS NOT NULL)
-	GROUP BY DATE(start_time), model_name, endpoint_name, group_name
+	GROUP BY DATE(start_time), COALESCE(model_name, ''), COALESCE(endpoint_name, ''), COALESCE(group_name, ''), COALESCE(client_key_name, '')
 	`, ut.adapter.BuildDateTimeNow(), ut.adapter.BuildDateTimeNow())
-
-	// 拼接完整查询
-	query := strings.Replace(baseQuery, "VALUES ("+strings.Join(placeholders, ", ")+")", "("+selectQuery+")", 1)
-
-	summaryWriteReq := WriteRequest{
-		Query:     query,
-		Args:      []interface{}{startDate, endDate.AddDate(0, 0, 1)},
-		Response:  make(chan error, 1),
-		Context:   context.Background(),
-		EventType: "update_summary",
-	}
-
-	select {
-	case ut.writeQueue <- summaryWriteReq:
-		err := <-summaryWriteReq.Response
-		if err != nil {
-			slog.Error("Failed to update usage summary", "error", err)
-		} else {
-			slog.Info("Usage summary updated successfully")
-		}
-	case <-ut.ctx.Done():
-		slog.Debug("Usage summary update cancelled due to context cancellation")
-	}
 }
 
 // GetDatabaseStats 获取数据库统计信息（使用读连接）
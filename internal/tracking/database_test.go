@@ -163,7 +163,7 @@ func TestCostCalculation(t *testing.T) {
 		CacheReadTokens:     5000,    // 0.005M tokens
 	}
 	
-	inputCost, outputCost, cacheCost, readCost, totalCost := tracker.calculateCost("claude-3-5-haiku-20241022", tokens)
+	inputCost, outputCost, cacheCost, readCost, totalCost := tracker.calculateCost("claude-3-5-haiku-20241022", tokens, time.Now())
 	
 	// Expected costs:
 	// Input: 0.1M * $1 = $0.10
@@ -226,7 +226,7 @@ func TestDefaultPricing(t *testing.T) {
 		OutputTokens: 50000,   // 0.05M tokens
 	}
 	
-	inputCost, outputCost, _, _, totalCost := tracker.calculateCost("unknown-model", tokens)
+	inputCost, outputCost, _, _, totalCost := tracker.calculateCost("unknown-model", tokens, time.Now())
 	
 	// Expected costs with default pricing:
 	// Input: 0.1M * $2 = $0.20
@@ -18,9 +18,10 @@ type ActiveRequest struct {
 	Method      string    `json:"method"`
 	Path        string    `json:"path"`
 	IsStreaming bool      `json:"is_streaming"`
+	ClientKey   string    `json:"client_key_name"` // 鉴权使用的客户端 Key 名称
 
 	// 可变状态（频繁更新）
-	Status        string `json:"status"`         // pending/forwarding/processing/completed/failed/cancelled
+	Status        string `json:"status"`         // pending/forwarding/processing/completed/cache_hit/failed/cancelled
 	Channel       string `json:"channel"`        // 渠道标签（来自端点配置）
 	EndpointName  string `json:"endpoint_name"`  // 当前使用的端点
 	GroupName     string `json:"group_name"`     // 当前使用的组
@@ -43,6 +44,10 @@ type ActiveRequest struct {
 	DurationMs   int64      `json:"duration_ms"`
 	TotalCostUSD float64    `json:"total_cost_usd"`
 
+	// 响应速度（完成前由生命周期管理器写入，0 表示未知）
+	TTFTMs             int64   `json:"ttft_ms"`               // 首 Token 耗时
+	OutputTokensPerSec float64 `json:"output_tokens_per_sec"` // 输出 Token 速率
+
 	// 内部字段
 	mu sync.RWMutex `json:"-"` // 保护单个请求的并发访问
 }
@@ -320,6 +325,23 @@ func (hp *HotPool) GetActiveCount() int {
 	return len(hp.requests) + len(hp.archiving)
 }
 
+// CountByEndpoint 按端点统计在途请求数（不包括已完成、归档中的请求）
+func (hp *HotPool) CountByEndpoint() map[string]int {
+	hp.mu.RLock()
+	defer hp.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, req := range hp.requests {
+		req.mu.RLock()
+		endpointName := req.EndpointName
+		req.mu.RUnlock()
+		if endpointName != "" {
+			counts[endpointName]++
+		}
+	}
+	return counts
+}
+
 // ConfirmArchived 确认请求已成功写入数据库，从归档缓存中移除
 // 由 ArchiveManager 在批量写入成功后调用
 func (hp *HotPool) ConfirmArchived(requestIDs []string) {
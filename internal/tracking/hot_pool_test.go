@@ -164,6 +164,37 @@ func TestHotPoolCompleteAndArchive(t *testing.T) {
 	}
 }
 
+func TestHotPoolCountByEndpoint(t *testing.T) {
+	config := DefaultHotPoolConfig()
+	pool := NewHotPool(config)
+	defer pool.Close()
+
+	assign := map[string]string{
+		"req-a1": "alpha",
+		"req-a2": "alpha",
+		"req-b1": "beta",
+		"req-p1": "", // 尚未选择端点
+	}
+	for id, endpointName := range assign {
+		pool.Add(NewActiveRequest(id, "127.0.0.1", "test-agent", "POST", "/v1/messages", false))
+		name := endpointName
+		pool.Update(id, func(r *ActiveRequest) {
+			r.EndpointName = name
+		})
+	}
+
+	// 已完成（归档中）的请求不计入在途数
+	pool.CompleteAndArchive("req-a2", nil)
+
+	counts := pool.CountByEndpoint()
+	if counts["alpha"] != 1 || counts["beta"] != 1 {
+		t.Errorf("Unexpected in-flight counts: %v", counts)
+	}
+	if _, ok := counts[""]; ok {
+		t.Error("Requests without endpoint should not be counted")
+	}
+}
+
 func TestHotPoolConcurrentAccess(t *testing.T) {
 	config := DefaultHotPoolConfig()
 	config.MaxSize = 1000
@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"sort"
+	"time"
+)
+
+// PricingVersion 定价版本：从 EffectiveFrom 起生效，直到同一模型的下一个版本
+type PricingVersion struct {
+	EffectiveFrom time.Time
+	Pricing       ModelPricing
+}
+
+// PricingHistory 按模型分组的定价版本，每个模型的版本按生效时间升序排列
+type PricingHistory map[string][]PricingVersion
+
+// NewPricingHistory 复制版本列表并按生效时间排序
+func NewPricingHistory(versions map[string][]PricingVersion) PricingHistory {
+	history := make(PricingHistory, len(versions))
+	for modelName, list := range versions {
+		if len(list) == 0 {
+			continue
+		}
+		sorted := make([]PricingVersion, len(list))
+		copy(sorted, list)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].EffectiveFrom.Before(sorted[j].EffectiveFrom)
+		})
+		history[modelName] = sorted
+	}
+	return history
+}
+
+// At 返回模型在指定时间生效的定价
+// 第二个返回值表示模型是否有定价历史，第三个返回值表示该时间是否有生效的版本（早于首个版本时为 false）
+func (h PricingHistory) At(modelName string, at time.Time) (ModelPricing, bool, bool) {
+	versions, ok := h[modelName]
+	if !ok || len(versions) == 0 {
+		return ModelPricing{}, false, false
+	}
+
+	// 找到第一个晚于 at 的版本，它前面的版本即为生效版本
+	idx := sort.Search(len(versions), func(i int) bool {
+		return versions[i].EffectiveFrom.After(at)
+	})
+	if idx == 0 {
+		return ModelPricing{}, true, false
+	}
+	return versions[idx-1].Pricing, true, true
+}
+
+// resolvePricing 查找请求开始时生效的模型定价
+// 模型有定价历史时以历史为准（早于首个版本视为当时未定价），没有历史时使用当前定价；
+// 模型未定价时回退到 _default 定价，均不存在时返回 false
+func resolvePricing(current map[string]ModelPricing, history PricingHistory, modelName string, at time.Time) (ModelPricing, bool) {
+	for _, name := range []string{modelName, "_default"} {
+		pricing, hasHistory, effective := history.At(name, at)
+		if effective {
+			return pricing, true
+		}
+		if hasHistory {
+			continue
+		}
+		if pricing, ok := current[name]; ok {
+			return pricing, true
+		}
+	}
+	return ModelPricing{}, false
+}
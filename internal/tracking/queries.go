@@ -5,39 +5,42 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 )
 
 // QueryOptions represents options for querying usage data
 type QueryOptions struct {
-	StartDate    *time.Time
-	EndDate      *time.Time
-	ModelName    string
-	Channel      string
-	EndpointName string
-	GroupName    string
-	Status       string
-	Limit        int
-	Offset       int
+	StartDate     *time.Time
+	EndDate       *time.Time
+	ModelName     string
+	Channel       string
+	EndpointName  string
+	GroupName     string
+	ClientKeyName string
+	Status        string
+	Limit         int
+	Offset        int
 }
 
 // UsageSummary represents a summary of usage data
 type UsageSummary struct {
-	Date         string    `json:"date"`
-	ModelName    string    `json:"model_name"`
-	EndpointName string    `json:"endpoint_name"`
-	GroupName    string    `json:"group_name"`
-	RequestCount int       `json:"request_count"`
-	SuccessCount int       `json:"success_count"`
-	ErrorCount   int       `json:"error_count"`
-	
+	Date          string `json:"date"`
+	ModelName     string `json:"model_name"`
+	EndpointName  string `json:"endpoint_name"`
+	GroupName     string `json:"group_name"`
+	ClientKeyName string `json:"client_key_name"`
+	RequestCount  int    `json:"request_count"`
+	SuccessCount  int    `json:"success_count"`
+	ErrorCount    int    `json:"error_count"`
+
 	TotalInputTokens         int64   `json:"total_input_tokens"`
 	TotalOutputTokens        int64   `json:"total_output_tokens"`
 	TotalCacheCreationTokens int64   `json:"total_cache_creation_tokens"`
 	TotalCacheReadTokens     int64   `json:"total_cache_read_tokens"`
-	TotalCostUSD            float64 `json:"total_cost_usd"`
-	
-	AvgDurationMs float64 `json:"avg_duration_ms"`
+	TotalCostUSD             float64 `json:"total_cost_usd"`
+
+	AvgDurationMs float64   `json:"avg_duration_ms"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -55,11 +58,15 @@ type RequestDetail struct {
 	EndTime     *time.Time `json:"end_time"`
 	DurationMs  *int64     `json:"duration_ms"`
 
-	Channel      string    `json:"channel"`      // 渠道标签
-	EndpointName string    `json:"endpoint_name"`
-	GroupName    string    `json:"group_name"`
-	ModelName    string    `json:"model_name"`
-	IsStreaming  bool      `json:"is_streaming"` // 是否为流式请求
+	TTFTMs             *int64   `json:"ttft_ms"`               // 首 Token 耗时
+	OutputTokensPerSec *float64 `json:"output_tokens_per_sec"` // 输出 Token 速率
+
+	Channel       string `json:"channel"` // 渠道标签
+	EndpointName  string `json:"endpoint_name"`
+	GroupName     string `json:"group_name"`
+	ModelName     string `json:"model_name"`
+	IsStreaming   bool   `json:"is_streaming"`    // 是否为流式请求
+	ClientKeyName string `json:"client_key_name"` // 鉴权使用的客户端 Key 名称
 
 	Status         string `json:"status"`
 	HTTPStatusCode *int   `json:"http_status_code"`
@@ -116,6 +123,13 @@ type EndpointCostSummary struct {
 	CacheReadCostUSD     float64 `json:"cache_read_cost_usd"`
 }
 
+// SpendSummary 消费汇总（预算检查用）
+type SpendSummary struct {
+	RequestCount int64   `json:"request_count"`
+	TotalTokens  int64   `json:"total_tokens"` // 输入 + 输出 + 缓存创建 + 缓存读取
+	TotalCostUSD float64 `json:"total_cost_usd"`
+}
+
 // GetDB returns the read database connection for external queries (读写分离：返回读连接)
 func (ut *UsageTracker) GetDB() *sql.DB {
 	return ut.readDB
@@ -139,6 +153,7 @@ func (ut *UsageTracker) QueryUsageSummary(ctx context.Context, opts *QueryOption
 
 	query := `SELECT date, model_name, endpoint_name, 
 		COALESCE(group_name, '') as group_name,
+		COALESCE(client_key_name, '') as client_key_name,
 		request_count, success_count, error_count,
 		total_input_tokens, total_output_tokens, 
 		total_cache_creation_tokens, total_cache_read_tokens,
@@ -167,6 +182,10 @@ func (ut *UsageTracker) QueryUsageSummary(ctx context.Context, opts *QueryOption
 		query += " AND group_name = ?"
 		args = append(args, opts.GroupName)
 	}
+	if opts.ClientKeyName != "" {
+		query += " AND client_key_name = ?"
+		args = append(args, opts.ClientKeyName)
+	}
 	
 	query += " ORDER BY date DESC, total_cost_usd DESC"
 	
@@ -189,7 +208,7 @@ func (ut *UsageTracker) QueryUsageSummary(ctx context.Context, opts *QueryOption
 	for rows.Next() {
 		var summary UsageSummary
 		err := rows.Scan(
-			&summary.Date, &summary.ModelName, &summary.EndpointName, &summary.GroupName,
+			&summary.Date, &summary.ModelName, &summary.EndpointName, &summary.GroupName, &summary.ClientKeyName,
 			&summary.RequestCount, &summary.SuccessCount, &summary.ErrorCount,
 			&summary.TotalInputTokens, &summary.TotalOutputTokens,
 			&summary.TotalCacheCreationTokens, &summary.TotalCacheReadTokens,
@@ -219,11 +238,13 @@ func (ut *UsageTracker) QueryRequestDetails(ctx context.Context, opts *QueryOpti
 		COALESCE(client_ip, '') as client_ip,
 		COALESCE(user_agent, '') as user_agent,
 		method, path, start_time, end_time, duration_ms,
+		ttft_ms, output_tokens_per_sec,
 		COALESCE(channel, '') as channel,
 		COALESCE(endpoint_name, '') as endpoint_name,
 		COALESCE(group_name, '') as group_name,
 		COALESCE(model_name, '') as model_name,
 		COALESCE(is_streaming, false) as is_streaming,
+		COALESCE(client_key_name, '') as client_key_name,
 		status, http_status_code, retry_count,
 		COALESCE(failure_reason, '') as failure_reason,
 		COALESCE(last_failure_reason, '') as last_failure_reason,
@@ -262,6 +283,10 @@ func (ut *UsageTracker) QueryRequestDetails(ctx context.Context, opts *QueryOpti
 		query += " AND group_name = ?"
 		args = append(args, opts.GroupName)
 	}
+	if opts.ClientKeyName != "" {
+		query += " AND client_key_name = ?"
+		args = append(args, opts.ClientKeyName)
+	}
 	if opts.Status != "" {
 		// v3.5.0状态机重构 - 状态与错误分离的兼容查询
 		switch opts.Status {
@@ -327,7 +352,8 @@ func (ut *UsageTracker) QueryRequestDetails(ctx context.Context, opts *QueryOpti
 			&detail.ID, &detail.RequestID,
 			&detail.ClientIP, &detail.UserAgent, &detail.Method, &detail.Path,
 			&detail.StartTime, &detail.EndTime, &detail.DurationMs,
-			&detail.Channel, &detail.EndpointName, &detail.GroupName, &detail.ModelName, &detail.IsStreaming,
+			&detail.TTFTMs, &detail.OutputTokensPerSec,
+			&detail.Channel, &detail.EndpointName, &detail.GroupName, &detail.ModelName, &detail.IsStreaming, &detail.ClientKeyName,
 			&detail.Status, &detail.HTTPStatusCode, &detail.RetryCount,
 			&detail.FailureReason, &detail.LastFailureReason, &detail.CancelReason,
 			&detail.InputTokens, &detail.OutputTokens,
@@ -374,7 +400,7 @@ func (ut *UsageTracker) QueryUsageStats(ctx context.Context, period string) (*Us
 	
 	query := `SELECT 
 		COUNT(*) as total_requests,
-		CAST(SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) AS FLOAT) / COUNT(*) * 100 as success_rate,
+		CAST(SUM(CASE WHEN status IN ('completed', 'cache_hit') THEN 1 ELSE 0 END) AS FLOAT) / COUNT(*) * 100 as success_rate,
 		AVG(CASE WHEN duration_ms IS NOT NULL THEN duration_ms ELSE 0 END) as avg_duration,
 		SUM(total_cost_usd) as total_cost
 		FROM request_logs 
@@ -437,6 +463,10 @@ func (ut *UsageTracker) CountRequestDetails(ctx context.Context, opts *QueryOpti
 		query += " AND group_name = ?"
 		args = append(args, opts.GroupName)
 	}
+	if opts.ClientKeyName != "" {
+		query += " AND client_key_name = ?"
+		args = append(args, opts.ClientKeyName)
+	}
 	if opts.Status != "" {
 		query += " AND status = ?"
 		args = append(args, opts.Status)
@@ -451,6 +481,84 @@ func (ut *UsageTracker) CountRequestDetails(ctx context.Context, opts *QueryOpti
 	return count, nil
 }
 
+// QuerySpend 汇总消费（数据库 + 热池中尚未归档的请求）
+// 支持 StartDate、EndDate、Channel、EndpointName、ClientKeyName 筛选，其余字段忽略
+func (ut *UsageTracker) QuerySpend(ctx context.Context, opts *QueryOptions) (*SpendSummary, error) {
+	if ut.readDB == nil {
+		return nil, fmt.Errorf("read database not initialized")
+	}
+
+	spend := &SpendSummary{}
+	spendOpts := &QueryOptions{
+		StartDate:     opts.StartDate,
+		EndDate:       opts.EndDate,
+		Channel:       opts.Channel,
+		EndpointName:  opts.EndpointName,
+		ClientKeyName: opts.ClientKeyName,
+	}
+
+	// 热池：进行中的请求按当前 Token 估算成本，归档中的请求可能已写入数据库，需从数据库统计中排除
+	hotRequests := ut.getFilteredHotPoolRequests(spendOpts)
+	hotIDs := make([]interface{}, 0, len(hotRequests))
+	for _, req := range hotRequests {
+		spend.RequestCount++
+		spend.TotalTokens += req.InputTokens + req.OutputTokens + req.CacheCreationTokens + req.CacheReadTokens
+		spend.TotalCostUSD += req.TotalCostUSD
+		hotIDs = append(hotIDs, req.RequestID)
+	}
+
+	query := `SELECT COUNT(*),
+		COALESCE(SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens), 0),
+		COALESCE(SUM(total_cost_usd), 0)
+		FROM request_logs WHERE 1=1`
+	var args []interface{}
+
+	if opts.StartDate != nil {
+		query += " AND start_time >= ?"
+		args = append(args, ut.formatQueryTime(*opts.StartDate))
+	}
+	if opts.EndDate != nil {
+		query += " AND start_time <= ?"
+		args = append(args, ut.formatQueryTime(*opts.EndDate))
+	}
+	if opts.Channel != "" {
+		query += " AND channel = ?"
+		args = append(args, opts.Channel)
+	}
+	if opts.EndpointName != "" {
+		query += " AND endpoint_name = ?"
+		args = append(args, opts.EndpointName)
+	}
+	if opts.ClientKeyName != "" {
+		query += " AND client_key_name = ?"
+		args = append(args, opts.ClientKeyName)
+	}
+	if len(hotIDs) > 0 {
+		query += " AND request_id NOT IN (?" + strings.Repeat(",?", len(hotIDs)-1) + ")"
+		args = append(args, hotIDs...)
+	}
+
+	var count, tokens int64
+	var cost float64
+	if err := ut.readDB.QueryRowContext(ctx, query, args...).Scan(&count, &tokens, &cost); err != nil {
+		return nil, fmt.Errorf("failed to query spend: %w", err)
+	}
+
+	spend.RequestCount += count
+	spend.TotalTokens += tokens
+	spend.TotalCostUSD += cost
+
+	return spend, nil
+}
+
+// formatQueryTime 按数据库存储格式（配置时区、无时区后缀）格式化时间
+func (ut *UsageTracker) formatQueryTime(t time.Time) string {
+	if ut.location != nil {
+		t = t.In(ut.location)
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
 // GetEndpointCostsForDate queries endpoint cost summary data for a specific date
 func (ut *UsageTracker) GetEndpointCostsForDate(ctx context.Context, date string) ([]EndpointCostSummary, error) {
 	if ut.readDB == nil {
@@ -482,7 +590,7 @@ func (ut *UsageTracker) GetEndpointCostsForDate(ctx context.Context, date string
 		COALESCE(SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens), 0) as total_tokens,
 		COALESCE(SUM(total_cost_usd), 0.0) as total_cost_usd,
 		COUNT(*) as request_count,
-		SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) as success_count,
+		SUM(CASE WHEN status IN ('completed', 'cache_hit') THEN 1 ELSE 0 END) as success_count,
 		COALESCE(SUM(input_tokens), 0) as input_tokens,
 		COALESCE(SUM(output_tokens), 0) as output_tokens,
 		COALESCE(SUM(cache_creation_tokens), 0) as cache_creation_tokens,
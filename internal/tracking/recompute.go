@@ -0,0 +1,286 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// CostRecomputeOptions 成本重算范围
+type CostRecomputeOptions struct {
+	StartTime    time.Time // 请求开始时间下界（包含）
+	EndTime      time.Time // 请求开始时间上界（不包含），为零时为当前时间
+	ModelName    string    // 只重算该模型，为空时不过滤
+	EndpointName string    // 只重算该端点，为空时不过滤
+	BatchSize    int       // 每批读取和更新的记录数（默认 500）
+}
+
+// CostRecomputeProgress 成本重算进度
+type CostRecomputeProgress struct {
+	Total      int64   `json:"total"`       // 范围内的记录数
+	Processed  int64   `json:"processed"`   // 已处理记录数
+	Updated    int64   `json:"updated"`     // 成本发生变化并已更新的记录数
+	CostBefore float64 `json:"cost_before"` // 已处理记录重算前的总成本
+	CostAfter  float64 `json:"cost_after"`  // 已处理记录重算后的总成本
+}
+
+// recomputeRow 待重算的请求记录
+type recomputeRow struct {
+	id           int64
+	startTime    time.Time
+	modelName    string
+	endpointName string
+	status       string
+	usage        TokenUsage
+	costs        [7]float64
+}
+
+// costColumns 返回成本分项，顺序与 request_logs 中的 *_cost_usd 列一致
+func (c CostBreakdown) costColumns() [7]float64 {
+	return [7]float64{
+		c.InputCost, c.OutputCost,
+		c.CacheCreationCost, c.CacheCreation5mCost, c.CacheCreation1hCost,
+		c.CacheReadCost, c.TotalCost,
+	}
+}
+
+// RecomputeCosts 按当前定价历史和端点倍率重算时间范围内请求的 *_cost_usd 列
+// 每条记录使用其 start_time 时生效的定价版本，按 id 分批读取和更新，每批完成后回调进度；
+// ctx 取消时在当前批次结束后停止，已更新的批次不会回滚，其覆盖的日期仍会重新汇总
+func (ut *UsageTracker) RecomputeCosts(ctx context.Context, opts CostRecomputeOptions, onProgress func(CostRecomputeProgress)) (CostRecomputeProgress, error) {
+	var progress CostRecomputeProgress
+
+	if ut.readDB == nil || ut.writeDB == nil {
+		return progress, fmt.Errorf("database not initialized")
+	}
+	if opts.EndTime.IsZero() {
+		opts.EndTime = time.Now()
+	}
+	if !opts.EndTime.After(opts.StartTime) {
+		return progress, fmt.Errorf("end time must be after start time")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+
+	// 快照定价和倍率，重算过程中的定价变更不影响本次结果
+	ut.mu.RLock()
+	pricing := ut.pricing
+	history := ut.pricingHist
+	multipliers := ut.endpointMu
+	ut.mu.RUnlock()
+
+	filter := " WHERE start_time >= ? AND start_time < ?"
+	filterArgs := []interface{}{ut.formatQueryTime(opts.StartTime), ut.formatQueryTime(opts.EndTime)}
+	if opts.ModelName != "" {
+		filter += " AND model_name = ?"
+		filterArgs = append(filterArgs, opts.ModelName)
+	}
+	if opts.EndpointName != "" {
+		filter += " AND endpoint_name = ?"
+		filterArgs = append(filterArgs, opts.EndpointName)
+	}
+
+	// 汇总表按天聚合成本，重算涉及的日期需要重新汇总；
+	// 取消或写入失败提前返回时也要汇总已更新的批次，ctx 已取消时仍需执行
+	defer func() {
+		if progress.Updated == 0 {
+			return
+		}
+		if err := ut.refreshUsageSummaryRange(context.WithoutCancel(ctx), opts.StartTime, opts.EndTime); err != nil {
+			slog.Warn("Failed to refresh usage summary after cost recompute", "error", err)
+		}
+	}()
+
+	if err := ut.readDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM request_logs"+filter, filterArgs...).Scan(&progress.Total); err != nil {
+		return progress, fmt.Errorf("failed to count requests: %w", err)
+	}
+	if onProgress != nil {
+		onProgress(progress)
+	}
+
+	// start_time 转为文本读取，避免驱动按 UTC 解析无时区的时间
+	selectQuery := `SELECT id, CAST(start_time AS TEXT), COALESCE(model_name, ''), COALESCE(endpoint_name, ''), status,
+		COALESCE(input_tokens, 0), COALESCE(output_tokens, 0),
+		COALESCE(cache_creation_tokens, 0), COALESCE(cache_creation_5m_tokens, 0), COALESCE(cache_creation_1h_tokens, 0),
+		COALESCE(cache_read_tokens, 0),
+		COALESCE(input_cost_usd, 0), COALESCE(output_cost_usd, 0),
+		COALESCE(cache_creation_cost_usd, 0), COALESCE(cache_creation_5m_cost_usd, 0), COALESCE(cache_creation_1h_cost_usd, 0),
+		COALESCE(cache_read_cost_usd, 0), COALESCE(total_cost_usd, 0)
+		FROM request_logs` + filter + " AND id > ? ORDER BY id LIMIT ?"
+
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		args := append(append([]interface{}{}, filterArgs...), lastID, opts.BatchSize)
+		rows, err := ut.queryRecomputeBatch(ctx, selectQuery, args)
+		if err != nil {
+			return progress, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		lastID = rows[len(rows)-1].id
+
+		updates := make(map[int64][7]float64)
+		for _, row := range rows {
+			var cost CostBreakdown
+			// 响应缓存命中未消耗上游 Token，不计成本（与归档时一致）
+			if row.status != "cache_hit" {
+				if p, ok := resolvePricing(pricing, history, row.modelName, row.startTime); ok {
+					var multiplier *EndpointMultiplier
+					if m, ok := multipliers[row.endpointName]; ok {
+						multiplier = &m
+					}
+					usage := row.usage
+					cost = CalculateCostV2(&usage, &p, multiplier)
+				}
+			}
+
+			newCosts := cost.costColumns()
+			progress.Processed++
+			progress.CostBefore += row.costs[6]
+			progress.CostAfter += newCosts[6]
+			if costsDiffer(row.costs, newCosts) {
+				updates[row.id] = newCosts
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := ut.writeRecomputedCosts(ctx, updates); err != nil {
+				return progress, err
+			}
+			progress.Updated += int64(len(updates))
+		}
+
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		if len(rows) < opts.BatchSize {
+			break
+		}
+	}
+
+	return progress, nil
+}
+
+// queryRecomputeBatch 读取一批待重算的请求记录
+func (ut *UsageTracker) queryRecomputeBatch(ctx context.Context, query string, args []interface{}) ([]recomputeRow, error) {
+	rows, err := ut.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requests for recompute: %w", err)
+	}
+	defer rows.Close()
+
+	var batch []recomputeRow
+	for rows.Next() {
+		var row recomputeRow
+		var startTime string
+		if err := rows.Scan(
+			&row.id, &startTime, &row.modelName, &row.endpointName, &row.status,
+			&row.usage.InputTokens, &row.usage.OutputTokens,
+			&row.usage.CacheCreationTokens, &row.usage.CacheCreation5mTokens, &row.usage.CacheCreation1hTokens,
+			&row.usage.CacheReadTokens,
+			&row.costs[0], &row.costs[1], &row.costs[2], &row.costs[3], &row.costs[4], &row.costs[5], &row.costs[6],
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan request for recompute: %w", err)
+		}
+		row.startTime = ut.parseStoredTime(startTime)
+		batch = append(batch, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating requests for recompute: %w", err)
+	}
+	return batch, nil
+}
+
+// writeRecomputedCosts 在一个事务中写入一批重算后的成本
+func (ut *UsageTracker) writeRecomputedCosts(ctx context.Context, updates map[int64][7]float64) error {
+	ut.writeMu.Lock()
+	defer ut.writeMu.Unlock()
+
+	tx, err := ut.writeDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE request_logs SET
+		input_cost_usd = ?, output_cost_usd = ?,
+		cache_creation_cost_usd = ?, cache_creation_5m_cost_usd = ?, cache_creation_1h_cost_usd = ?,
+		cache_read_cost_usd = ?, total_cost_usd = ?
+		WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for id, c := range updates {
+		if _, err := stmt.ExecContext(ctx, c[0], c[1], c[2], c[3], c[4], c[5], c[6], id); err != nil {
+			return fmt.Errorf("failed to update costs of request %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// refreshUsageSummaryRange 重新汇总时间范围覆盖的整天
+func (ut *UsageTracker) refreshUsageSummaryRange(ctx context.Context, start, end time.Time) error {
+	loc := ut.location
+	if loc == nil {
+		loc = time.Local
+	}
+	start = start.In(loc)
+	end = end.In(loc)
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	dayEnd := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	ut.writeMu.Lock()
+	defer ut.writeMu.Unlock()
+
+	_, err := ut.writeDB.ExecContext(ctx, ut.usageSummaryQuery(), ut.formatQueryTime(dayStart), ut.formatQueryTime(dayEnd))
+	if err != nil {
+		return fmt.Errorf("failed to refresh usage summary: %w", err)
+	}
+	return nil
+}
+
+// parseStoredTime 解析数据库中的时间文本（无时区后缀的按配置时区解析）
+func (ut *UsageTracker) parseStoredTime(value string) time.Time {
+	loc := ut.location
+	if loc == nil {
+		loc = time.Local
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, loc); err == nil {
+		return t
+	}
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+		time.RFC3339Nano,
+	} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// costsDiffer 判断成本分项是否变化（忽略浮点误差）
+func costsDiffer(a, b [7]float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-12 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPricingHistoryResolve(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewPricingHistory(map[string][]PricingVersion{
+		"claude-a": {
+			{EffectiveFrom: t0.AddDate(0, 1, 0), Pricing: ModelPricing{Input: 5}},
+			{EffectiveFrom: t0, Pricing: ModelPricing{Input: 3}},
+		},
+		"_default": {{EffectiveFrom: time.Unix(0, 0), Pricing: ModelPricing{Input: 1}}},
+	})
+	current := map[string]ModelPricing{"claude-a": {Input: 5}, "claude-b": {Input: 8}, "_default": {Input: 1}}
+
+	cases := []struct {
+		model string
+		at    time.Time
+		want  float64
+	}{
+		{"claude-a", t0.AddDate(0, 0, 10), 3},
+		{"claude-a", t0.AddDate(0, 1, 0), 5},  // 生效时间当刻使用新版本
+		{"claude-a", t0.AddDate(0, 0, -1), 1}, // 早于首个版本时回退到 _default
+		{"claude-b", t0, 8},                   // 没有历史时使用当前定价
+		{"unknown", t0, 1},
+	}
+	for _, c := range cases {
+		pricing, ok := resolvePricing(current, history, c.model, c.at)
+		if !ok || pricing.Input != c.want {
+			t.Errorf("%s @ %s: 期望 %v, 实际 %v (%v)", c.model, c.at, c.want, pricing.Input, ok)
+		}
+	}
+}
+
+func TestRecomputeCosts(t *testing.T) {
+	tracker, err := NewUsageTracker(&Config{
+		Enabled:         true,
+		DatabasePath:    filepath.Join(t.TempDir(), "usage.db"),
+		BufferSize:      50,
+		BatchSize:       5,
+		FlushInterval:   50 * time.Millisecond,
+		MaxRetry:        3,
+		CleanupInterval: 24 * time.Hour,
+		RetentionDays:   30,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create usage tracker: %v", err)
+	}
+	defer tracker.Close()
+
+	ctx := context.Background()
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	rows := []struct {
+		id       string
+		start    time.Time
+		model    string
+		endpoint string
+		status   string
+	}{
+		{"req-old-price", base, "claude-a", "ep-1", "completed"},
+		{"req-new-price", base.Add(2 * time.Hour), "claude-a", "ep-1", "completed"},
+		{"req-multiplier", base.Add(3 * time.Hour), "claude-a", "ep-2", "completed"},
+		{"req-cache-hit", base.Add(4 * time.Hour), "claude-a", "ep-1", "cache_hit"},
+		{"req-other-model", base.Add(5 * time.Hour), "claude-b", "ep-1", "completed"},
+		{"req-out-of-range", base.Add(-time.Hour), "claude-a", "ep-1", "completed"},
+	}
+	for _, r := range rows {
+		_, err := tracker.writeDB.ExecContext(ctx, `INSERT INTO request_logs
+			(request_id, start_time, model_name, endpoint_name, status, input_tokens, output_tokens, total_cost_usd)
+			VALUES (?, ?, ?, ?, ?, 1000000, 0, 99)`,
+			r.id, tracker.formatQueryTime(r.start), r.model, r.endpoint, r.status)
+		if err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+	}
+
+	tracker.UpdatePricing(map[string]ModelPricing{"claude-a": {Input: 4}, "claude-b": {Input: 2}})
+	tracker.UpdatePricingHistory(NewPricingHistory(map[string][]PricingVersion{
+		"claude-a": {
+			{EffectiveFrom: time.Unix(0, 0), Pricing: ModelPricing{Input: 3}},
+			{EffectiveFrom: base.Add(time.Hour), Pricing: ModelPricing{Input: 4}},
+		},
+	}))
+	tracker.UpdateEndpointMultipliers(map[string]EndpointMultiplier{"ep-2": {CostMultiplier: 0.5}})
+
+	var reports []CostRecomputeProgress
+	progress, err := tracker.RecomputeCosts(ctx, CostRecomputeOptions{
+		StartTime: base,
+		EndTime:   base.Add(6 * time.Hour),
+		BatchSize: 2,
+	}, func(p CostRecomputeProgress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatalf("重算失败: %v", err)
+	}
+	if progress.Total != 5 || progress.Processed != 5 || progress.Updated != 5 {
+		t.Errorf("进度不符: %+v", progress)
+	}
+	// 初始计数 + 3 个批次
+	if len(reports) != 4 || reports[0].Processed != 0 || reports[0].Total != 5 {
+		t.Errorf("进度回调不符: %+v", reports)
+	}
+
+	want := map[string]float64{
+		"req-old-price":    3,
+		"req-new-price":    4,
+		"req-multiplier":   2,
+		"req-cache-hit":    0,
+		"req-other-model":  2,
+		"req-out-of-range": 99,
+	}
+	for id, cost := range want {
+		var got float64
+		if err := tracker.readDB.QueryRowContext(ctx, "SELECT total_cost_usd FROM request_logs WHERE request_id = ?", id).Scan(&got); err != nil {
+			t.Fatalf("查询 %s 失败: %v", id, err)
+		}
+		if math.Abs(got-cost) > 1e-9 {
+			t.Errorf("%s: 期望成本 %v, 实际 %v", id, cost, got)
+		}
+	}
+	if math.Abs(progress.CostBefore-5*99) > 1e-9 || math.Abs(progress.CostAfter-11) > 1e-9 {
+		t.Errorf("成本汇总不符: %+v", progress)
+	}
+
+	// 再次重算没有变化
+	progress, err = tracker.RecomputeCosts(ctx, CostRecomputeOptions{StartTime: base, EndTime: base.Add(6 * time.Hour), ModelName: "claude-a"}, nil)
+	if err != nil || progress.Total != 4 || progress.Updated != 0 {
+		t.Errorf("重复重算应无更新: %v %+v", err, progress)
+	}
+
+	if _, err := tracker.RecomputeCosts(ctx, CostRecomputeOptions{StartTime: base, EndTime: base}, nil); err == nil {
+		t.Error("结束时间不晚于开始时间时应返回错误")
+	}
+}
+
+func TestCompletionCostUsesStartTimePricing(t *testing.T) {
+	tracker, err := NewUsageTracker(&Config{
+		Enabled:         true,
+		DatabasePath:    filepath.Join(t.TempDir(), "usage.db"),
+		BufferSize:      50,
+		BatchSize:       5,
+		FlushInterval:   50 * time.Millisecond,
+		MaxRetry:        3,
+		CleanupInterval: 24 * time.Hour,
+		RetentionDays:   30,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create usage tracker: %v", err)
+	}
+	defer tracker.Close()
+
+	ctx := context.Background()
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	tracker.UpdatePricing(map[string]ModelPricing{"claude-a": {Input: 4}})
+	tracker.UpdatePricingHistory(NewPricingHistory(map[string][]PricingVersion{
+		"claude-a": {
+			{EffectiveFrom: time.Unix(0, 0), Pricing: ModelPricing{Input: 3}},
+			{EffectiveFrom: start.Add(time.Hour), Pricing: ModelPricing{Input: 4}},
+		},
+	}))
+
+	// 请求开始后定价发生变化，各完成路径都应按开始时的定价计算
+	for _, eventType := range []string{"success", "complete", "failed_request_tokens", "token_recovery"} {
+		requestID := "req-" + eventType
+		_, err := tracker.writeDB.ExecContext(ctx, `INSERT INTO request_logs (request_id, start_time, status) VALUES (?, ?, 'forwarding')`,
+			requestID, tracker.formatQueryTime(start))
+		if err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+
+		query, args, err := tracker.buildWriteQuery(RequestEvent{
+			Type:      eventType,
+			RequestID: requestID,
+			Timestamp: time.Now(),
+			Data:      RequestCompleteData{ModelName: "claude-a", InputTokens: 1000000, Duration: time.Minute},
+		})
+		if err != nil {
+			t.Fatalf("%s: 构建查询失败: %v", eventType, err)
+		}
+		if _, err := tracker.writeDB.ExecContext(ctx, query, args...); err != nil {
+			t.Fatalf("%s: 写入失败: %v", eventType, err)
+		}
+
+		var cost float64
+		if err := tracker.readDB.QueryRowContext(ctx, "SELECT total_cost_usd FROM request_logs WHERE request_id = ?", requestID).Scan(&cost); err != nil {
+			t.Fatalf("%s: 查询失败: %v", eventType, err)
+		}
+		if math.Abs(cost-3) > 1e-9 {
+			t.Errorf("%s: 应按请求开始时的定价计算, 期望 3, 实际 %v", eventType, cost)
+		}
+	}
+}
+
+func TestRecomputeCostsCancelRefreshesSummary(t *testing.T) {
+	tracker, err := NewUsageTracker(&Config{
+		Enabled:         true,
+		DatabasePath:    filepath.Join(t.TempDir(), "usage.db"),
+		BufferSize:      50,
+		BatchSize:       5,
+		FlushInterval:   50 * time.Millisecond,
+		MaxRetry:        3,
+		CleanupInterval: 24 * time.Hour,
+		RetentionDays:   30,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create usage tracker: %v", err)
+	}
+	defer tracker.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	for i := 0; i < 4; i++ {
+		_, err := tracker.writeDB.ExecContext(ctx, `INSERT INTO request_logs
+			(request_id, start_time, model_name, endpoint_name, status, input_tokens, output_tokens, total_cost_usd)
+			VALUES (?, ?, 'claude-a', 'ep-1', 'completed', 1000000, 0, 99)`,
+			fmt.Sprintf("req-%d", i), tracker.formatQueryTime(base.Add(time.Duration(i)*time.Minute)))
+		if err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+	}
+	tracker.UpdatePricing(map[string]ModelPricing{"claude-a": {Input: 3}})
+
+	// 第一批完成后取消：只更新前两条记录
+	progress, err := tracker.RecomputeCosts(ctx, CostRecomputeOptions{
+		StartTime: base,
+		EndTime:   base.Add(time.Hour),
+		BatchSize: 2,
+	}, func(p CostRecomputeProgress) {
+		if p.Processed > 0 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) || progress.Updated != 2 {
+		t.Fatalf("取消后进度不符: %v %+v", err, progress)
+	}
+
+	var total float64
+	if err := tracker.readDB.QueryRowContext(context.Background(),
+		"SELECT COALESCE(SUM(total_cost_usd), 0) FROM usage_summary").Scan(&total); err != nil {
+		t.Fatalf("查询汇总失败: %v", err)
+	}
+	if math.Abs(total-(2*3+2*99)) > 1e-9 {
+		t.Errorf("取消后汇总应包含已更新批次的成本, 期望 %v, 实际 %v", 2*3+2*99, total)
+	}
+}
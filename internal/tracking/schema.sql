@@ -17,6 +17,8 @@ CREATE TABLE IF NOT EXISTS request_logs (
     start_time DATETIME NOT NULL,           -- 请求开始时间
     end_time DATETIME,                      -- 请求完成时间
     duration_ms INTEGER,                    -- 总耗时(毫秒)
+    ttft_ms INTEGER,                        -- 首 Token 耗时(毫秒)，流式请求为首个内容增量到达时间
+    output_tokens_per_sec REAL,             -- 输出 Token 速率(tok/s)
     
     -- 转发信息
     channel TEXT DEFAULT '',                -- 渠道标签（来自端点配置）
@@ -24,9 +26,10 @@ CREATE TABLE IF NOT EXISTS request_logs (
     group_name TEXT,                        -- 所属组名
     model_name TEXT,                        -- Claude模型名称
     is_streaming BOOLEAN DEFAULT FALSE,     -- 是否为流式请求
+    client_key_name TEXT DEFAULT '',        -- 鉴权使用的客户端 Key 名称（多客户端 Key）
     
     -- 状态信息 (v3.5.0更新: 生命周期状态与错误原因分离 - 2025-09-28)
-    status TEXT NOT NULL DEFAULT 'pending', -- 生命周期状态: pending/forwarding/processing/retry/suspended/completed/failed/cancelled
+    status TEXT NOT NULL DEFAULT 'pending', -- 生命周期状态: pending/forwarding/processing/retry/suspended/completed/failed/cancelled/cache_hit
     http_status_code INTEGER,               -- HTTP状态码
     retry_count INTEGER DEFAULT 0,          -- 重试次数
 
@@ -76,6 +79,7 @@ CREATE TABLE IF NOT EXISTS usage_summary (
     model_name TEXT NOT NULL,
     endpoint_name TEXT NOT NULL,
     group_name TEXT,
+    client_key_name TEXT DEFAULT '',       -- 客户端 Key 名称
     
     request_count INTEGER DEFAULT 0,       -- 请求总数
     success_count INTEGER DEFAULT 0,       -- 成功请求数
@@ -92,7 +96,7 @@ CREATE TABLE IF NOT EXISTS usage_summary (
     created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
     updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
     
-    UNIQUE(date, model_name, endpoint_name, group_name)
+    UNIQUE(date, model_name, endpoint_name, group_name, client_key_name)
 );
 
 -- 汇总表索引
@@ -137,12 +141,18 @@ CREATE TABLE IF NOT EXISTS endpoints (
 
     -- ========== 路由配置 ==========
     priority INTEGER DEFAULT 1,                     -- 优先级（数字越小越高）
+    weight INTEGER DEFAULT 1,                       -- 权重（weighted 策略下同优先级内按权重分配）
     failover_enabled INTEGER DEFAULT 1,             -- 是否参与故障转移 (1=是, 0=否)
     cooldown_seconds INTEGER,                       -- 冷却时间（秒，NULL=使用全局配置）
     timeout_seconds INTEGER DEFAULT 300,            -- 请求超时（秒）
+    max_concurrency INTEGER DEFAULT 0,              -- 最大并发请求数（0=不限制）
+    rpm INTEGER DEFAULT 0,                          -- 每分钟请求数上限（0=不限制）
+    tpm INTEGER DEFAULT 0,                          -- 每分钟 Token 数上限（0=不限制）
 
     -- ========== 功能支持 ==========
     supports_count_tokens INTEGER DEFAULT 0,        -- 是否支持 count_tokens 端点
+    model_mapping TEXT,                             -- 模型名称映射 (JSON格式，客户端模型 -> 上游模型)
+    retry_policy TEXT,                              -- 端点级重试策略规则 (YAML/JSON格式)
 
     -- ========== 成本倍率 ==========
     cost_multiplier REAL DEFAULT 1.0,               -- 总成本倍率
@@ -155,6 +165,9 @@ CREATE TABLE IF NOT EXISTS endpoints (
     -- ========== 状态 ==========
     enabled INTEGER DEFAULT 1,                      -- 是否启用 (1=启用, 0=禁用)
 
+    -- ========== 网络配置 ==========
+    proxy_config TEXT,                              -- 端点级代理 (JSON格式，NULL=使用全局代理)
+
     -- ========== 审计字段 ==========
     created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
     updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
@@ -215,6 +228,41 @@ BEGIN
     UPDATE model_pricing SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
 END;
 
+-- ============================================================================
+-- 模型定价导入审计表
+-- 每次从价格目录（LiteLLM / CSV）导入一条记录，保存新增和变化的模型明细
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS model_pricing_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT,                                    -- 来源说明（文件名或 URL）
+    format TEXT NOT NULL,                           -- 目录格式：litellm / csv
+    added INTEGER DEFAULT 0,                        -- 新增模型数
+    changed INTEGER DEFAULT 0,                      -- 价格变化的模型数
+    unchanged INTEGER DEFAULT 0,                    -- 未变化的模型数
+    changes TEXT DEFAULT '[]',                      -- 新增和变化明细（JSON 数组）
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+-- ============================================================================
+-- 模型定价历史表
+-- 每个版本从 effective_from 起生效，直到同一模型的下一个版本；成本计算按请求开始时间选择版本
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS model_pricing_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    model_name TEXT NOT NULL,                       -- 模型名称
+    input_price REAL NOT NULL DEFAULT 0,            -- 输入价格 (USD per 1M tokens)
+    output_price REAL NOT NULL DEFAULT 0,           -- 输出价格
+    cache_creation_price_5m REAL DEFAULT 0,         -- 5分钟缓存创建价格
+    cache_creation_price_1h REAL DEFAULT 0,         -- 1小时缓存创建价格
+    cache_read_price REAL DEFAULT 0,                -- 缓存读取价格
+    effective_from DATETIME NOT NULL,               -- 生效时间（UTC）
+    source TEXT DEFAULT '',                         -- 版本来源：baseline / create / update / import / manual
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    UNIQUE(model_name, effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_model_pricing_history_model ON model_pricing_history(model_name, effective_from);
+
 -- ============================================================================
 -- 系统设置表 (v5.1.0 新增 - 2025-12-08)
 -- 将运行时可调配置从 config.yaml 迁移到 SQLite，支持动态管理和热更新
@@ -256,4 +304,198 @@ CREATE TRIGGER IF NOT EXISTS update_settings_timestamp
     WHEN NEW.updated_at = OLD.updated_at
 BEGIN
     UPDATE settings SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
-END;
\ No newline at end of file
+END;
+
+-- ============================================================================
+-- 客户端 API Key 表
+-- 多客户端鉴权：每个 Key 有独立名称、启用状态和过期时间，请求按 Key 名称统计
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS client_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    -- ========== 基本信息 ==========
+    name TEXT UNIQUE NOT NULL,                      -- 唯一名称（记录到 request_logs.client_key_name）
+    key TEXT UNIQUE NOT NULL,                       -- Bearer Token
+    description TEXT,                               -- 备注
+
+    -- ========== 状态 ==========
+    enabled INTEGER DEFAULT 1,                      -- 是否启用 (1=启用)
+    expires_at DATETIME,                            -- 过期时间，NULL 表示永不过期
+
+    -- ========== 审计字段 ==========
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+-- 客户端 Key 表触发器：自动更新 updated_at
+CREATE TRIGGER IF NOT EXISTS update_client_keys_timestamp
+    AFTER UPDATE ON client_keys
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE client_keys SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
+END;
+
+-- ============================================================================
+-- 预算表
+-- 按日/周/月限制消费金额和 Token 用量，作用范围：全局/端点/渠道/客户端 Key
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS budgets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    -- ========== 基本信息 ==========
+    name TEXT UNIQUE NOT NULL,                      -- 唯一名称
+    scope TEXT NOT NULL DEFAULT 'global',           -- global/endpoint/channel/client_key
+    target TEXT DEFAULT '',                         -- 端点名/渠道名/客户端 Key 名称（global 为空）
+    period TEXT NOT NULL DEFAULT 'monthly',         -- daily/weekly/monthly
+    description TEXT,                               -- 备注
+
+    -- ========== 限额（0 表示不限制） ==========
+    soft_limit_usd REAL DEFAULT 0,                  -- 软限额：超过时发送事件提醒
+    soft_limit_tokens INTEGER DEFAULT 0,
+    hard_limit_usd REAL DEFAULT 0,                  -- 硬限额：超过时拒绝新请求
+    hard_limit_tokens INTEGER DEFAULT 0,
+
+    -- ========== 状态 ==========
+    enabled INTEGER DEFAULT 1,                      -- 是否启用 (1=启用)
+
+    -- ========== 审计字段 ==========
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+-- 预算表触发器：自动更新 updated_at
+CREATE TRIGGER IF NOT EXISTS update_budgets_timestamp
+    AFTER UPDATE ON budgets
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE budgets SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
+END;
+
+-- ============================================================================
+-- 端点 Key 状态表
+-- 多 Key 自动轮换的运行状态（冷却/失效/使用次数），重启后恢复
+-- Key 以值的指纹标识，不保存明文
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS endpoint_key_states (
+    endpoint_name TEXT NOT NULL,                    -- 端点名称
+    key_type TEXT NOT NULL,                         -- token/api_key
+    key_hash TEXT NOT NULL,                         -- Key 值指纹
+    is_active INTEGER DEFAULT 0,                    -- 是否为当前激活的 Key
+    usage_count INTEGER DEFAULT 0,                  -- 累计分配的请求数
+    cooldown_until DATETIME,                        -- 冷却截止时间（NULL 表示未冷却）
+    invalid INTEGER DEFAULT 0,                      -- 认证失败后标记为无效 (1=无效)
+    last_status_code INTEGER DEFAULT 0,             -- 最近一次错误的状态码
+    last_error TEXT,                                -- 最近一次错误说明
+    last_used_at DATETIME,                          -- 最近一次使用时间
+    updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    PRIMARY KEY (endpoint_name, key_type, key_hash)
+);
+
+-- ============================================================================
+-- 模型路由规则表
+-- 请求模型匹配通配符时，只允许转发到指定端点/渠道
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS model_routing_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    -- ========== 基本信息 ==========
+    name TEXT UNIQUE NOT NULL,                      -- 唯一名称
+    model_pattern TEXT NOT NULL,                    -- 模型名称通配符，如 claude-opus-*、*sonnet*
+    description TEXT,                               -- 备注
+
+    -- ========== 允许的目标（JSON 数组） ==========
+    endpoints TEXT DEFAULT '[]',                    -- 端点名称
+    channels TEXT DEFAULT '[]',                     -- 渠道名称
+
+    -- ========== 匹配配置 ==========
+    priority INTEGER DEFAULT 0,                     -- 匹配顺序（数字越小越先匹配）
+    fallback INTEGER DEFAULT 0,                     -- 允许的端点均不可用时回退到常规选择 (1=回退)
+    enabled INTEGER DEFAULT 1,                      -- 是否启用 (1=启用)
+
+    -- ========== 审计字段 ==========
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+-- 模型路由规则表触发器：自动更新 updated_at
+CREATE TRIGGER IF NOT EXISTS update_model_routing_rules_timestamp
+    AFTER UPDATE ON model_routing_rules
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE model_routing_rules SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
+END;
+
+-- ============================================================================
+-- 响应缓存表
+-- 确定性非流式请求（temperature 为 0 的 /v1/messages 和 count_tokens）的成功响应
+-- 以规范化请求体和模型的哈希为键，过期或超出条目上限时删除
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS response_cache (
+    cache_key TEXT PRIMARY KEY,                     -- 请求路径、模型和规范化请求体的 SHA-256
+    model TEXT,                                     -- 请求模型
+    path TEXT NOT NULL,                             -- 请求路径
+    headers TEXT DEFAULT '{}',                      -- 响应头（JSON）
+    body BLOB NOT NULL,                             -- 响应体（已解压）
+    hit_count INTEGER DEFAULT 0,                    -- 命中次数
+    expires_at DATETIME NOT NULL,                   -- 过期时间
+    last_hit_at DATETIME,                           -- 最近一次命中时间
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+CREATE INDEX IF NOT EXISTS idx_response_cache_expires_at ON response_cache(expires_at);
+
+-- ============================================================================
+-- Webhook 表
+-- 运维事件（端点不健康、组冷却、挂起超时、预算超限等）外发通知的目标配置
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    -- ========== 基本信息 ==========
+    name TEXT UNIQUE NOT NULL,                      -- 唯一名称
+    url TEXT NOT NULL,                              -- 投递地址
+    format TEXT NOT NULL DEFAULT 'json',            -- 负载格式：json / slack / feishu / dingtalk
+    description TEXT,                               -- 备注
+
+    -- ========== 投递配置 ==========
+    event_types TEXT DEFAULT '[]',                  -- 订阅的事件类型（JSON 数组，为空时使用默认告警事件）
+    template TEXT,                                  -- 自定义消息模板（Go text/template）
+    secret TEXT,                                    -- 签名密钥
+    max_retries INTEGER DEFAULT 3,                  -- 失败后的最大重试次数
+    enabled INTEGER DEFAULT 1,                      -- 是否启用 (1=启用)
+
+    -- ========== 审计字段 ==========
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+    updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+-- Webhook 表触发器：自动更新 updated_at
+CREATE TRIGGER IF NOT EXISTS update_webhooks_timestamp
+    AFTER UPDATE ON webhooks
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE webhooks SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
+END;
+
+-- ============================================================================
+-- Webhook 投递记录表
+-- 每次投递（含重试）一条记录，只保留最近的记录
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook TEXT NOT NULL,                          -- Webhook 名称
+    event_type TEXT NOT NULL,                       -- 事件类型
+    success INTEGER DEFAULT 0,                      -- 是否投递成功 (1=成功)
+    attempts INTEGER DEFAULT 0,                     -- 请求次数
+    status_code INTEGER DEFAULT 0,                  -- 最后一次响应状态码（网络错误为 0）
+    error TEXT,                                     -- 错误信息
+    payload TEXT,                                   -- 请求体
+    duration_ms INTEGER DEFAULT 0,                  -- 总耗时（含重试等待）
+    created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00')
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook, id);
@@ -179,37 +179,102 @@ func (s *SQLiteAdapter) InitSchema() error {
 	return nil
 }
 
-// migrateSchema 执行数据库迁移（v5.0.1+: 添加 5m/1h 缓存字段）
+// migrateSchema 执行数据库迁移（v5.0.1+: 添加 5m/1h 缓存字段、端点级代理字段、客户端 Key 字段）
 func (s *SQLiteAdapter) migrateSchema(ctx context.Context) error {
 	migrations := []struct {
+		table       string
 		checkColumn string
 		alterSQL    string
 		description string
 	}{
 		{
+			table:       "request_logs",
 			checkColumn: "cache_creation_5m_tokens",
 			alterSQL:    "ALTER TABLE request_logs ADD COLUMN cache_creation_5m_tokens INTEGER DEFAULT 0",
 			description: "5分钟缓存创建tokens字段",
 		},
 		{
+			table:       "request_logs",
 			checkColumn: "cache_creation_1h_tokens",
 			alterSQL:    "ALTER TABLE request_logs ADD COLUMN cache_creation_1h_tokens INTEGER DEFAULT 0",
 			description: "1小时缓存创建tokens字段",
 		},
 		{
+			table:       "request_logs",
 			checkColumn: "cache_creation_5m_cost_usd",
 			alterSQL:    "ALTER TABLE request_logs ADD COLUMN cache_creation_5m_cost_usd REAL DEFAULT 0",
 			description: "5分钟缓存创建成本字段",
 		},
 		{
+			table:       "request_logs",
 			checkColumn: "cache_creation_1h_cost_usd",
 			alterSQL:    "ALTER TABLE request_logs ADD COLUMN cache_creation_1h_cost_usd REAL DEFAULT 0",
 			description: "1小时缓存创建成本字段",
 		},
+		{
+			table:       "endpoints",
+			checkColumn: "proxy_config",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN proxy_config TEXT",
+			description: "端点级代理配置字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "weight",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN weight INTEGER DEFAULT 1",
+			description: "端点权重字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "model_mapping",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN model_mapping TEXT",
+			description: "端点模型名称映射字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "retry_policy",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN retry_policy TEXT",
+			description: "端点重试策略字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "max_concurrency",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN max_concurrency INTEGER DEFAULT 0",
+			description: "端点最大并发字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "rpm",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN rpm INTEGER DEFAULT 0",
+			description: "端点每分钟请求数上限字段",
+		},
+		{
+			table:       "endpoints",
+			checkColumn: "tpm",
+			alterSQL:    "ALTER TABLE endpoints ADD COLUMN tpm INTEGER DEFAULT 0",
+			description: "端点每分钟 Token 数上限字段",
+		},
+		{
+			table:       "request_logs",
+			checkColumn: "client_key_name",
+			alterSQL:    "ALTER TABLE request_logs ADD COLUMN client_key_name TEXT DEFAULT ''",
+			description: "客户端 Key 名称字段",
+		},
+		{
+			table:       "request_logs",
+			checkColumn: "ttft_ms",
+			alterSQL:    "ALTER TABLE request_logs ADD COLUMN ttft_ms INTEGER",
+			description: "首 Token 耗时字段",
+		},
+		{
+			table:       "request_logs",
+			checkColumn: "output_tokens_per_sec",
+			alterSQL:    "ALTER TABLE request_logs ADD COLUMN output_tokens_per_sec REAL",
+			description: "输出 Token 速率字段",
+		},
 	}
 
 	for _, m := range migrations {
-		exists, err := s.columnExists(ctx, "request_logs", m.checkColumn)
+		exists, err := s.columnExists(ctx, m.table, m.checkColumn)
 		if err != nil {
 			return fmt.Errorf("failed to check column %s: %w", m.checkColumn, err)
 		}
@@ -223,6 +288,93 @@ func (s *SQLiteAdapter) migrateSchema(ctx context.Context) error {
 		}
 	}
 
+	// 新增列的索引需在列迁移之后创建（旧库执行 schema.sql 时列尚不存在）
+	if _, err := s.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_request_logs_client_key ON request_logs(client_key_name)"); err != nil {
+		return fmt.Errorf("failed to create client key index: %w", err)
+	}
+
+	return s.migrateUsageSummary(ctx)
+}
+
+// migrateUsageSummary 为 usage_summary 添加 client_key_name 维度
+// 唯一约束需要包含新列，SQLite 不支持修改约束，因此重建表并保留已有汇总数据
+func (s *SQLiteAdapter) migrateUsageSummary(ctx context.Context) error {
+	exists, err := s.columnExists(ctx, "usage_summary", "client_key_name")
+	if err != nil {
+		return fmt.Errorf("failed to check column client_key_name: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	s.logger.Info("🔧 [数据库迁移] 重建 usage_summary 表（添加客户端 Key 维度）")
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage_summary migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"ALTER TABLE usage_summary RENAME TO usage_summary_old",
+		`CREATE TABLE usage_summary (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date TEXT NOT NULL,
+			model_name TEXT NOT NULL,
+			endpoint_name TEXT NOT NULL,
+			group_name TEXT,
+			client_key_name TEXT DEFAULT '',
+			request_count INTEGER DEFAULT 0,
+			success_count INTEGER DEFAULT 0,
+			error_count INTEGER DEFAULT 0,
+			total_input_tokens INTEGER DEFAULT 0,
+			total_output_tokens INTEGER DEFAULT 0,
+			total_cache_creation_tokens INTEGER DEFAULT 0,
+			total_cache_read_tokens INTEGER DEFAULT 0,
+			total_cost_usd REAL DEFAULT 0,
+			avg_duration_ms REAL DEFAULT 0,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			updated_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00'),
+			UNIQUE(date, model_name, endpoint_name, group_name, client_key_name)
+		)`,
+		`INSERT INTO usage_summary (
+			date, model_name, endpoint_name, group_name, client_key_name,
+			request_count, success_count, error_count,
+			total_input_tokens, total_output_tokens,
+			total_cache_creation_tokens, total_cache_read_tokens,
+			total_cost_usd, avg_duration_ms, created_at, updated_at
+		) SELECT
+			date, model_name, endpoint_name, group_name, '',
+			request_count, success_count, error_count,
+			total_input_tokens, total_output_tokens,
+			total_cache_creation_tokens, total_cache_read_tokens,
+			total_cost_usd, avg_duration_ms, created_at, updated_at
+		FROM usage_summary_old`,
+		"DROP TABLE usage_summary_old",
+		"CREATE INDEX IF NOT EXISTS idx_usage_summary_date ON usage_summary(date)",
+		"CREATE INDEX IF NOT EXISTS idx_usage_summary_model ON usage_summary(model_name)",
+		"CREATE INDEX IF NOT EXISTS idx_usage_summary_endpoint ON usage_summary(endpoint_name)",
+		"CREATE INDEX IF NOT EXISTS idx_usage_summary_group ON usage_summary(group_name)",
+		`CREATE TRIGGER IF NOT EXISTS update_usage_summary_timestamp
+			AFTER UPDATE ON usage_summary
+			FOR EACH ROW
+			WHEN NEW.updated_at = OLD.updated_at
+		BEGIN
+			UPDATE usage_summary SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now', 'localtime') || '+08:00' WHERE id = NEW.id;
+		END`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate usage_summary: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit usage_summary migration: %w", err)
+	}
+
+	s.logger.Info("✅ [数据库迁移] usage_summary 表重建完成")
 	return nil
 }
 
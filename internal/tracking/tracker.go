@@ -31,6 +31,7 @@ type UsageStatsDetailed struct {
 	ModelStats       map[string]ModelStat       `json:"model_stats"`
 	EndpointStats    map[string]EndpointStat    `json:"endpoint_stats"`
 	GroupStats       map[string]GroupStat       `json:"group_stats"`
+	ClientKeyStats   map[string]ClientKeyStat   `json:"client_key_stats"`
 }
 
 // ModelStat 模型统计
@@ -51,9 +52,16 @@ type GroupStat struct {
 	TotalCost    float64 `json:"total_cost"`
 }
 
+// ClientKeyStat 客户端 Key 统计（按使用者区分用量和成本）
+type ClientKeyStat struct {
+	RequestCount int64   `json:"request_count"`
+	TotalTokens  int64   `json:"total_tokens"`
+	TotalCost    float64 `json:"total_cost"`
+}
+
 // RequestEvent 表示请求事件
 type RequestEvent struct {
-	Type      string      `json:"type"`      // "start", "flexible_update", "success", "final_failure", "complete", "failed_request_tokens", "token_recovery"
+	Type      string      `json:"type"`      // "start", "flexible_update", "success", "cache_hit", "final_failure", "complete", "failed_request_tokens", "token_recovery"
 	RequestID string      `json:"request_id"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"` // 根据Type不同而变化
@@ -66,6 +74,7 @@ type RequestStartData struct {
 	Method      string `json:"method"`
 	Path        string `json:"path"`
 	IsStreaming bool   `json:"is_streaming"` // 是否为流式请求
+	ClientKey   string `json:"client_key_name,omitempty"` // 鉴权使用的客户端 Key 名称
 }
 
 // RequestUpdateData 请求更新事件数据
@@ -301,16 +310,18 @@ type WriteRequest struct {
 // UpdateOptions 统一的请求更新选项
 // 支持可选字段更新，只更新非nil的字段
 type UpdateOptions struct {
-	EndpointName  *string        // 端点名称
-	Channel       *string        // 渠道标签（v5.0）
-	GroupName     *string        // 组名称
-	Status        *string        // 状态
-	RetryCount    *int           // 重试次数
-	HttpStatus    *int           // HTTP状态码
-	ModelName     *string        // 模型名称
-	EndTime       *time.Time     // 结束时间
-	Duration      *time.Duration // 持续时间
-	FailureReason *string        // 失败原因（用于中间过程记录）
+	EndpointName       *string        // 端点名称
+	Channel            *string        // 渠道标签（v5.0）
+	GroupName          *string        // 组名称
+	Status             *string        // 状态
+	RetryCount         *int           // 重试次数
+	HttpStatus         *int           // HTTP状态码
+	ModelName          *string        // 模型名称
+	EndTime            *time.Time     // 结束时间
+	Duration           *time.Duration // 持续时间
+	FailureReason      *string        // 失败原因（用于中间过程记录）
+	TTFTMs             *int64         // 首 Token 耗时（毫秒）
+	OutputTokensPerSec *float64       // 输出 Token 速率（tok/s）
 }
 
 // UsageTracker 使用跟踪器
@@ -320,6 +331,7 @@ type UsageTracker struct {
 	eventChan    chan RequestEvent
 	config       *Config
 	pricing      map[string]ModelPricing      // 模型定价缓存
+	pricingHist  PricingHistory               // 模型定价历史（按请求开始时间选择生效版本）
 	endpointMu   map[string]EndpointMultiplier // 端点倍率缓存
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -704,6 +716,11 @@ func (ut *UsageTracker) Close() error {
 
 // RecordRequestStart 记录请求开始
 func (ut *UsageTracker) RecordRequestStart(requestID, clientIP, userAgent, method, path string, isStreaming bool) {
+	ut.RecordRequestStartWithClientKey(requestID, clientIP, userAgent, method, path, "", isStreaming)
+}
+
+// RecordRequestStartWithClientKey 记录请求开始（附带鉴权使用的客户端 Key 名称）
+func (ut *UsageTracker) RecordRequestStartWithClientKey(requestID, clientIP, userAgent, method, path, clientKey string, isStreaming bool) {
 	if ut.config == nil || !ut.config.Enabled {
 		return
 	}
@@ -711,22 +728,23 @@ func (ut *UsageTracker) RecordRequestStart(requestID, clientIP, userAgent, metho
 	// 🔥 v4.1 热池模式：直接添加到内存热池
 	if ut.hotPoolEnabled && ut.hotPool != nil {
 		req := NewActiveRequest(requestID, clientIP, userAgent, method, path, isStreaming)
+		req.ClientKey = clientKey
 		if err := ut.hotPool.Add(req); err != nil {
 			slog.Warn("🔥 热池添加请求失败，降级到事件队列模式",
 				"request_id", requestID,
 				"error", err)
 			// 降级到传统模式
-			ut.recordRequestStartLegacy(requestID, clientIP, userAgent, method, path, isStreaming)
+			ut.recordRequestStartLegacy(requestID, clientIP, userAgent, method, path, clientKey, isStreaming)
 		}
 		return
 	}
 
 	// 传统模式：发送事件到队列
-	ut.recordRequestStartLegacy(requestID, clientIP, userAgent, method, path, isStreaming)
+	ut.recordRequestStartLegacy(requestID, clientIP, userAgent, method, path, clientKey, isStreaming)
 }
 
 // recordRequestStartLegacy 传统模式记录请求开始
-func (ut *UsageTracker) recordRequestStartLegacy(requestID, clientIP, userAgent, method, path string, isStreaming bool) {
+func (ut *UsageTracker) recordRequestStartLegacy(requestID, clientIP, userAgent, method, path, clientKey string, isStreaming bool) {
 	event := RequestEvent{
 		Type:      "start",
 		RequestID: requestID,
@@ -737,6 +755,7 @@ func (ut *UsageTracker) recordRequestStartLegacy(requestID, clientIP, userAgent,
 			Method:      method,
 			Path:        path,
 			IsStreaming: isStreaming,
+			ClientKey:   clientKey,
 		},
 	}
 
@@ -785,6 +804,12 @@ func (ut *UsageTracker) RecordRequestUpdate(requestID string, opts UpdateOptions
 			if opts.FailureReason != nil {
 				req.FailureReason = *opts.FailureReason
 			}
+			if opts.TTFTMs != nil {
+				req.TTFTMs = *opts.TTFTMs
+			}
+			if opts.OutputTokensPerSec != nil {
+				req.OutputTokensPerSec = *opts.OutputTokensPerSec
+			}
 		})
 		if err != nil {
 			// 请求可能不在热池中（已归档或从未记录），降级到传统模式
@@ -914,6 +939,75 @@ func (ut *UsageTracker) recordRequestSuccessLegacy(requestID, modelName string,
 	}
 }
 
+// RecordRequestCacheHit 记录请求由响应缓存直接返回
+// 一次性更新：status='cache_hit', end_time, duration_ms, Token（仅展示），成本固定为 0
+func (ut *UsageTracker) RecordRequestCacheHit(requestID, modelName string, tokens *TokenUsage, duration time.Duration) {
+	if ut.config == nil || !ut.config.Enabled {
+		return
+	}
+
+	var inputTokens, outputTokens, cacheCreationTokens, cacheReadTokens int64
+	var cacheCreation5mTokens, cacheCreation1hTokens int64
+	if tokens != nil {
+		inputTokens = tokens.InputTokens
+		outputTokens = tokens.OutputTokens
+		cacheCreationTokens = tokens.CacheCreationTokens
+		cacheCreation5mTokens = tokens.CacheCreation5mTokens
+		cacheCreation1hTokens = tokens.CacheCreation1hTokens
+		cacheReadTokens = tokens.CacheReadTokens
+
+		if cacheCreation5mTokens == 0 && cacheCreation1hTokens == 0 && cacheCreationTokens > 0 {
+			cacheCreation5mTokens = cacheCreationTokens // 默认按 5m 缓存处理
+		}
+	}
+
+	if ut.hotPoolEnabled && ut.hotPool != nil {
+		now := ut.now()
+		err := ut.hotPool.CompleteAndArchive(requestID, func(req *ActiveRequest) {
+			req.Status = "cache_hit"
+			req.ModelName = modelName
+			req.HTTPStatus = 200
+			req.InputTokens = inputTokens
+			req.OutputTokens = outputTokens
+			req.CacheCreationTokens = cacheCreationTokens
+			req.CacheCreation5mTokens = cacheCreation5mTokens
+			req.CacheCreation1hTokens = cacheCreation1hTokens
+			req.CacheReadTokens = cacheReadTokens
+			req.EndTime = &now
+			req.DurationMs = duration.Milliseconds()
+			req.FailureReason = ""
+			// 归档时 cache_hit 状态不计算成本
+		})
+		if err == nil {
+			return
+		}
+		slog.Debug("🔥 热池完成请求失败，降级到事件队列模式",
+			"request_id", requestID,
+			"error", err)
+	}
+
+	event := RequestEvent{
+		Type:      "cache_hit",
+		RequestID: requestID,
+		Timestamp: ut.now(),
+		Data: RequestCompleteData{
+			ModelName:           modelName,
+			InputTokens:         inputTokens,
+			OutputTokens:        outputTokens,
+			CacheCreationTokens: cacheCreationTokens,
+			CacheReadTokens:     cacheReadTokens,
+			Duration:            duration,
+		},
+	}
+
+	select {
+	case ut.eventChan <- event:
+	default:
+		slog.Warn("Usage tracking event buffer full, dropping cache hit event",
+			"request_id", requestID)
+	}
+}
+
 // RecordRequestFinalFailure 记录请求最终失败或取消
 // 一次性更新所有失败/取消相关字段：status, end_time, duration_ms, failure_reason/cancel_reason, http_status_code, 可选Token
 // 🔧 [修复] 2025-12-11: 添加 modelName 参数，确保取消/失败请求能正确计算成本
@@ -1160,6 +1254,22 @@ func (ut *UsageTracker) UpdatePricing(pricing map[string]ModelPricing) {
 	slog.Info("Model pricing updated", "model_count", len(pricing))
 }
 
+// UpdatePricingHistory 更新模型定价历史（运行时动态更新）
+// 归档和成本重算按请求开始时间选择当时生效的定价版本
+func (ut *UsageTracker) UpdatePricingHistory(history PricingHistory) {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+
+	ut.pricingHist = history
+
+	// 同步到 ArchiveManager
+	if ut.archiveManager != nil {
+		ut.archiveManager.UpdatePricingHistory(history)
+	}
+
+	slog.Info("Model pricing history updated", "model_count", len(history))
+}
+
 // UpdateEndpointMultipliers 更新端点成本倍率
 // 成本计算公式：模型基础定价 * 端点倍率
 func (ut *UsageTracker) UpdateEndpointMultipliers(multipliers map[string]EndpointMultiplier) {
@@ -1313,6 +1423,42 @@ func (ut *UsageTracker) GetPricing(modelName string) ModelPricing {
 	return ut.config.DefaultPricing
 }
 
+// GetPricingAt 获取指定时间生效的模型定价（优先使用定价历史，模型未定价时回退到 _default）
+// 均不存在时返回配置中的默认定价
+func (ut *UsageTracker) GetPricingAt(modelName string, at time.Time) ModelPricing {
+	ut.mu.RLock()
+	defer ut.mu.RUnlock()
+
+	if pricing, ok := resolvePricing(ut.pricing, ut.pricingHist, modelName, at); ok {
+		return pricing
+	}
+	return ut.config.DefaultPricing
+}
+
+// EstimateCost 按当前定价和端点倍率估算请求成本（与归档时的计算方式一致）
+// 模型无定价时回退到 _default 定价，均不存在时返回 0
+func (ut *UsageTracker) EstimateCost(modelName, endpointName string, tokens *TokenUsage) float64 {
+	if tokens == nil {
+		return 0
+	}
+
+	ut.mu.RLock()
+	pricing, exists := ut.pricing[modelName]
+	if !exists {
+		pricing, exists = ut.pricing["_default"]
+	}
+	var multiplier *EndpointMultiplier
+	if m, ok := ut.endpointMu[endpointName]; ok {
+		multiplier = &m
+	}
+	ut.mu.RUnlock()
+
+	if !exists {
+		return 0
+	}
+	return CalculateCostV2(tokens, &pricing, multiplier).TotalCost
+}
+
 // GetConfiguredModels 获取配置中的所有模型列表
 func (ut *UsageTracker) GetConfiguredModels() []string {
 	ut.mu.RLock()
@@ -1358,7 +1504,7 @@ func (ut *UsageTracker) GetUsageStats(ctx context.Context, startTime, endTime ti
 
 	query := `SELECT 
 		COUNT(*) as total_requests,
-		SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) as success_requests,
+		SUM(CASE WHEN status IN ('completed', 'cache_hit') THEN 1 ELSE 0 END) as success_requests,
 		SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END) as error_requests,
 		SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens) as total_tokens,
 		SUM(total_cost_usd) as total_cost
@@ -1454,6 +1600,34 @@ func (ut *UsageTracker) GetUsageStats(ctx context.Context, startTime, endTime ti
 			TotalCost:    cost,
 		}
 	}
+
+	// 获取客户端 Key 统计（使用读连接）
+	clientKeyQuery := `SELECT client_key_name, COUNT(*),
+		SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens), SUM(total_cost_usd)
+		FROM request_logs
+		WHERE start_time >= ? AND start_time <= ? AND client_key_name IS NOT NULL AND client_key_name != ''
+		GROUP BY client_key_name`
+
+	rows4, err := ut.readDB.QueryContext(ctx, clientKeyQuery, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client key stats: %w", err)
+	}
+	defer rows4.Close()
+
+	stats.ClientKeyStats = make(map[string]ClientKeyStat)
+	for rows4.Next() {
+		var clientKey string
+		var requests, tokens int64
+		var cost float64
+		if err := rows4.Scan(&clientKey, &requests, &tokens, &cost); err != nil {
+			continue
+		}
+		stats.ClientKeyStats[clientKey] = ClientKeyStat{
+			RequestCount: requests,
+			TotalTokens:  tokens,
+			TotalCost:    cost,
+		}
+	}
 	
 	return &stats, nil
 }
@@ -1650,6 +1824,14 @@ func (ut *UsageTracker) GetActiveRequests() []*ActiveRequest {
 	return ut.hotPool.GetActive()
 }
 
+// GetInFlightByEndpoint 按端点统计热池中的在途请求数（least_outstanding 策略使用）
+func (ut *UsageTracker) GetInFlightByEndpoint() map[string]int {
+	if ut.hotPool == nil {
+		return map[string]int{}
+	}
+	return ut.hotPool.CountByEndpoint()
+}
+
 // GetActiveRequestCount 获取活跃请求数量
 func (ut *UsageTracker) GetActiveRequestCount() int {
 	if ut.hotPool == nil {
@@ -1756,6 +1938,8 @@ func (ut *UsageTracker) GetHotPoolAndArchiveOverview() map[string]interface{} {
 func (ut *UsageTracker) ActiveRequestToDetail(req *ActiveRequest) RequestDetail {
 	var endTime *time.Time
 	var durationMs *int64
+	var ttftMs *int64
+	var tokensPerSec *float64
 	var httpStatus *int
 
 	if req.EndTime != nil {
@@ -1764,6 +1948,12 @@ func (ut *UsageTracker) ActiveRequestToDetail(req *ActiveRequest) RequestDetail
 	if req.DurationMs > 0 {
 		durationMs = &req.DurationMs
 	}
+	if req.TTFTMs > 0 {
+		ttftMs = &req.TTFTMs
+	}
+	if req.OutputTokensPerSec > 0 {
+		tokensPerSec = &req.OutputTokensPerSec
+	}
 	if req.HTTPStatus > 0 {
 		httpStatus = &req.HTTPStatus
 	}
@@ -1803,11 +1993,14 @@ func (ut *UsageTracker) ActiveRequestToDetail(req *ActiveRequest) RequestDetail
 		StartTime:             req.StartTime,
 		EndTime:               endTime,
 		DurationMs:            durationMs,
+		TTFTMs:                ttftMs,
+		OutputTokensPerSec:    tokensPerSec,
 		EndpointName:          req.EndpointName,
 		Channel:               req.Channel, // v5.0: 渠道标签
 		GroupName:             req.GroupName,
 		ModelName:             req.ModelName,
 		IsStreaming:           req.IsStreaming,
+		ClientKeyName:         req.ClientKey,
 		Status:                req.Status,
 		HTTPStatusCode:        httpStatus,
 		RetryCount:            req.RetryCount,
@@ -1957,6 +2150,10 @@ func (ut *UsageTracker) getFilteredHotPoolRequests(opts *QueryOptions) []Request
 			if opts.GroupName != "" && req.GroupName != opts.GroupName {
 				continue
 			}
+			// 客户端 Key 过滤
+			if opts.ClientKeyName != "" && req.ClientKey != opts.ClientKeyName {
+				continue
+			}
 			// 时间范围过滤
 			if opts.StartDate != nil && req.StartTime.Before(*opts.StartDate) {
 				continue
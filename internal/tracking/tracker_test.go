@@ -2,6 +2,7 @@ package tracking
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -429,4 +430,71 @@ func TestPricingUpdate(t *testing.T) {
 	if stats.TotalRequests != 10 {
 		t.Errorf("Expected 10 requests with pricing updates, got %d", stats.TotalRequests)
 	}
-}
\ No newline at end of file
+}
+
+func TestResponseSpeedPersistence(t *testing.T) {
+	for _, hotPool := range []bool{true, false} {
+		hotPool := hotPool
+		name := "HotPool"
+		if !hotPool {
+			name = "Legacy"
+		}
+		t.Run(name, func(t *testing.T) {
+			config := &Config{
+				Enabled:         true,
+				DatabasePath:    filepath.Join(t.TempDir(), "usage.db"),
+				BufferSize:      50,
+				BatchSize:       5,
+				FlushInterval:   50 * time.Millisecond,
+				MaxRetry:        3,
+				CleanupInterval: 24 * time.Hour,
+				RetentionDays:   30,
+				HotPool:         &HotPoolSettings{Enabled: hotPool, MaxAge: time.Minute, MaxSize: 100, CleanupInterval: time.Minute},
+			}
+			tracker, err := NewUsageTracker(config)
+			if err != nil {
+				t.Fatalf("Failed to create usage tracker: %v", err)
+			}
+			defer tracker.Close()
+
+			ttftMs, rate := int64(320), 48.5
+			tracker.RecordRequestStart("req-speed", "127.0.0.1", "test", "POST", "/v1/messages", true)
+			tracker.RecordRequestStart("req-no-speed", "127.0.0.1", "test", "POST", "/v1/messages", false)
+			time.Sleep(100 * time.Millisecond)
+			tracker.RecordRequestUpdate("req-speed", UpdateOptions{TTFTMs: &ttftMs, OutputTokensPerSec: &rate})
+			tracker.RecordRequestSuccess("req-speed", "claude-sonnet-4-5", &TokenUsage{OutputTokens: 97}, 2320*time.Millisecond)
+			tracker.RecordRequestSuccess("req-no-speed", "claude-sonnet-4-5", nil, 100*time.Millisecond)
+
+			byID := map[string]RequestDetail{}
+			deadline := time.Now().Add(3 * time.Second)
+			for len(byID) < 2 && time.Now().Before(deadline) {
+				time.Sleep(100 * time.Millisecond)
+				details, err := tracker.QueryRequestDetails(context.Background(), &QueryOptions{Status: "completed"})
+				if err != nil {
+					t.Fatalf("Failed to query request details: %v", err)
+				}
+				for _, d := range details {
+					byID[d.RequestID] = d
+				}
+			}
+
+			got, ok := byID["req-speed"]
+			if !ok {
+				t.Fatal("req-speed was not persisted")
+			}
+			if got.TTFTMs == nil || *got.TTFTMs != ttftMs {
+				t.Errorf("Expected ttft_ms %d, got %v", ttftMs, got.TTFTMs)
+			}
+			if got.OutputTokensPerSec == nil || *got.OutputTokensPerSec != rate {
+				t.Errorf("Expected output_tokens_per_sec %v, got %v", rate, got.OutputTokensPerSec)
+			}
+
+			// 未采集时保持 NULL
+			if other, ok := byID["req-no-speed"]; !ok {
+				t.Error("req-no-speed was not persisted")
+			} else if other.TTFTMs != nil || other.OutputTokensPerSec != nil {
+				t.Errorf("Expected NULL speed columns, got %v / %v", other.TTFTMs, other.OutputTokensPerSec)
+			}
+		})
+	}
+}
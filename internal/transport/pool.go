@@ -0,0 +1,368 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cc-forwarder/config"
+)
+
+// Profile 传输档位，不同类型的请求使用不同的连接参数
+type Profile string
+
+const (
+	// ProfileRegular 常规（非流式）请求
+	ProfileRegular Profile = "regular"
+	// ProfileStreaming 流式请求：禁用压缩、较小缓冲区、可配置的响应头超时
+	ProfileStreaming Profile = "streaming"
+	// ProfileProbe 健康检查与快速测试等探测请求
+	ProfileProbe Profile = "probe"
+)
+
+// PoolStats 单个端点/档位的连接池统计
+type PoolStats struct {
+	Endpoint       string `json:"endpoint"`
+	Profile        string `json:"profile"`
+	OpenConns      int64  `json:"open_conns"`      // 当前打开的 TCP 连接数
+	ActiveRequests int64  `json:"active_requests"` // 正在进行的请求数（直到响应体关闭）
+	IdleConns      int64  `json:"idle_conns"`      // 估算的空闲连接数（open - active，HTTP/2 多路复用时为近似值）
+	Dials          int64  `json:"dials"`           // 累计新建连接数
+	TLSHandshakes  int64  `json:"tls_handshakes"`  // 累计 TLS 握手次数
+	ReusedConns    int64  `json:"reused_conns"`    // 累计复用连接的请求数
+	Requests       int64  `json:"requests"`        // 累计请求数
+	Rebuilds       int64  `json:"rebuilds"`        // 因配置变更重建 Transport 的次数
+}
+
+// poolKey 连接池键：端点名称 + 档位
+type poolKey struct {
+	endpoint string
+	profile  Profile
+}
+
+// poolCounters 连接统计计数器（按键累计，Transport 重建后保留）
+type poolCounters struct {
+	openConns      atomic.Int64
+	activeRequests atomic.Int64
+	dials          atomic.Int64
+	tlsHandshakes  atomic.Int64
+	reusedConns    atomic.Int64
+	requests       atomic.Int64
+	rebuilds       atomic.Int64
+}
+
+// pooledTransport 缓存的 Transport 及其构建指纹
+type pooledTransport struct {
+	base        *http.Transport
+	rt          *instrumentedTransport
+	endpointURL string
+	proxy       *config.ProxyConfig // 端点级代理覆盖（nil 表示使用全局代理）
+	fingerprint string
+}
+
+// Pool 按端点缓存 HTTP Transport，保持长连接复用
+// 只有当端点地址、（全局或端点级）代理配置或档位参数变化时才会重建 Transport
+type Pool struct {
+	mu       sync.Mutex
+	cfg      *config.Config
+	entries  map[poolKey]*pooledTransport
+	counters map[poolKey]*poolCounters
+}
+
+// NewPool 创建 Transport 连接池
+func NewPool(cfg *config.Config) *Pool {
+	return &Pool{
+		cfg:      cfg,
+		entries:  make(map[poolKey]*pooledTransport),
+		counters: make(map[poolKey]*poolCounters),
+	}
+}
+
+// Get 获取端点对应档位的 RoundTripper
+// 首次调用或配置指纹变化时创建新的 Transport，旧 Transport 的空闲连接会被关闭
+func (p *Pool) Get(ep *config.EndpointConfig, profile Profile) (http.RoundTripper, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := poolKey{endpoint: ep.Name, profile: profile}
+	fingerprint := transportFingerprint(p.cfg, ep, profile)
+
+	if entry, ok := p.entries[key]; ok {
+		if entry.fingerprint == fingerprint {
+			return entry.rt, nil
+		}
+		entry.base.CloseIdleConnections()
+		p.counterFor(key).rebuilds.Add(1)
+	}
+
+	base, err := NewTransport(p.cfg, ep, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := p.counterFor(key)
+	instrumentDial(base, counters)
+
+	entry := &pooledTransport{
+		base:        base,
+		rt:          &instrumentedTransport{base: base, counters: counters},
+		endpointURL: ep.URL,
+		proxy:       copyProxy(ep.Proxy),
+		fingerprint: fingerprint,
+	}
+	p.entries[key] = entry
+	return entry.rt, nil
+}
+
+// UpdateConfig 更新全局配置
+// 指纹发生变化的 Transport（例如代理配置变更）会立即关闭空闲连接并在下次使用时重建
+func (p *Pool) UpdateConfig(cfg *config.Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cfg = cfg
+	for key, entry := range p.entries {
+		ep := &config.EndpointConfig{Name: key.endpoint, URL: entry.endpointURL, Proxy: entry.proxy}
+		if entry.fingerprint != transportFingerprint(cfg, ep, key.profile) {
+			entry.base.CloseIdleConnections()
+			delete(p.entries, key)
+			p.counterFor(key).rebuilds.Add(1)
+		}
+	}
+}
+
+// Invalidate 移除端点的所有 Transport 和连接统计（端点被删除时调用）
+func (p *Pool) Invalidate(endpointName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, entry := range p.entries {
+		if key.endpoint == endpointName {
+			entry.base.CloseIdleConnections()
+			delete(p.entries, key)
+		}
+	}
+	for key := range p.counters {
+		if key.endpoint == endpointName {
+			delete(p.counters, key)
+		}
+	}
+}
+
+// Retain 只保留指定端点的 Transport，其余全部关闭（用于端点整体同步）
+func (p *Pool) Retain(endpointNames []string) {
+	keep := make(map[string]bool, len(endpointNames))
+	for _, name := range endpointNames {
+		keep[name] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, entry := range p.entries {
+		if !keep[key.endpoint] {
+			entry.base.CloseIdleConnections()
+			delete(p.entries, key)
+			delete(p.counters, key)
+		}
+	}
+}
+
+// Close 关闭所有空闲连接并清空连接统计
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, entry := range p.entries {
+		entry.base.CloseIdleConnections()
+		delete(p.entries, key)
+	}
+	clear(p.counters)
+}
+
+// Stats 返回所有端点/档位的连接统计（按端点名称、档位排序）
+func (p *Pool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(p.counters))
+	for key, c := range p.counters {
+		open := c.openConns.Load()
+		active := c.activeRequests.Load()
+		idle := open - active
+		if idle < 0 {
+			idle = 0
+		}
+		stats = append(stats, PoolStats{
+			Endpoint:       key.endpoint,
+			Profile:        string(key.profile),
+			OpenConns:      open,
+			ActiveRequests: active,
+			IdleConns:      idle,
+			Dials:          c.dials.Load(),
+			TLSHandshakes:  c.tlsHandshakes.Load(),
+			ReusedConns:    c.reusedConns.Load(),
+			Requests:       c.requests.Load(),
+			Rebuilds:       c.rebuilds.Load(),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Endpoint != stats[j].Endpoint {
+			return stats[i].Endpoint < stats[j].Endpoint
+		}
+		return stats[i].Profile < stats[j].Profile
+	})
+	return stats
+}
+
+// counterFor 获取（或创建）键对应的计数器，调用方需持有锁
+func (p *Pool) counterFor(key poolKey) *poolCounters {
+	c, ok := p.counters[key]
+	if !ok {
+		c = &poolCounters{}
+		p.counters[key] = c
+	}
+	return c
+}
+
+// NewTransport 创建端点指定档位的 Transport（不经过连接池），遵循端点级代理覆盖
+func NewTransport(cfg *config.Config, ep *config.EndpointConfig, profile Profile) (*http.Transport, error) {
+	httpTransport, err := CreateEndpointTransport(cfg, ep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
+
+	httpTransport.MaxIdleConnsPerHost = 10
+	if profile == ProfileStreaming {
+		httpTransport.DisableKeepAlives = false
+		httpTransport.ResponseHeaderTimeout = streamingResponseHeaderTimeout(cfg)
+		httpTransport.DisableCompression = true // 禁用压缩以防缓冲延迟
+		httpTransport.WriteBufferSize = 4096    // 较小的写缓冲区
+		httpTransport.ReadBufferSize = 4096     // 较小的读缓冲区
+	}
+
+	return httpTransport, nil
+}
+
+// streamingResponseHeaderTimeout 流式请求的响应头超时，默认60秒
+func streamingResponseHeaderTimeout(cfg *config.Config) time.Duration {
+	if cfg.Streaming.ResponseHeaderTimeout > 0 {
+		return cfg.Streaming.ResponseHeaderTimeout
+	}
+	return 60 * time.Second
+}
+
+// transportFingerprint 计算 Transport 构建参数指纹，指纹变化即需要重建
+func transportFingerprint(cfg *config.Config, ep *config.EndpointConfig, profile Profile) string {
+	fingerprint := fmt.Sprintf("%s|%s|%+v", profile, endpointOrigin(ep.URL), EffectiveProxy(cfg, ep))
+	if profile == ProfileStreaming {
+		fingerprint += "|" + streamingResponseHeaderTimeout(cfg).String()
+	}
+	return fingerprint
+}
+
+// copyProxy 复制端点级代理配置，避免外部修改影响已缓存的指纹比较
+func copyProxy(p *config.ProxyConfig) *config.ProxyConfig {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	return &cp
+}
+
+// endpointOrigin 提取端点的 scheme://host 部分（路径变化不影响连接）
+func endpointOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// instrumentDial 包装 DialContext 以统计新建和存活的连接
+func instrumentDial(t *http.Transport, counters *poolCounters) {
+	baseDial := t.DialContext
+	if baseDial == nil {
+		baseDial = (&net.Dialer{}).DialContext
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := baseDial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		counters.dials.Add(1)
+		counters.openConns.Add(1)
+		return &countedConn{Conn: conn, counters: counters}, nil
+	}
+}
+
+// countedConn 关闭时减少存活连接计数
+type countedConn struct {
+	net.Conn
+	counters  *poolCounters
+	closeOnce sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.counters.openConns.Add(-1)
+	})
+	return c.Conn.Close()
+}
+
+// instrumentedTransport 统计请求数、连接复用和 TLS 握手
+type instrumentedTransport struct {
+	base     *http.Transport
+	counters *poolCounters
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.counters.requests.Add(1)
+	t.counters.activeRequests.Add(1)
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.counters.reusedConns.Add(1)
+			}
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.counters.tlsHandshakes.Add(1)
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.counters.activeRequests.Add(-1)
+		return nil, err
+	}
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, counters: t.counters}
+	return resp, nil
+}
+
+// trackedBody 响应体关闭时结束活跃请求计数
+type trackedBody struct {
+	io.ReadCloser
+	counters  *poolCounters
+	closeOnce sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.closeOnce.Do(func() {
+		b.counters.activeRequests.Add(-1)
+	})
+	return b.ReadCloser.Close()
+}
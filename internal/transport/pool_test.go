@@ -0,0 +1,218 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"cc-forwarder/config"
+)
+
+func doGet(t *testing.T, rt http.RoundTripper, url string) {
+	t.Helper()
+	client := &http.Client{Transport: rt}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func findStats(stats []PoolStats, endpoint string, profile Profile) *PoolStats {
+	for i := range stats {
+		if stats[i].Endpoint == endpoint && stats[i].Profile == string(profile) {
+			return &stats[i]
+		}
+	}
+	return nil
+}
+
+func TestPoolReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	pool := NewPool(&config.Config{})
+	defer pool.Close()
+	ep := &config.EndpointConfig{Name: "ep1", URL: server.URL}
+
+	for i := 0; i < 5; i++ {
+		rt, err := pool.Get(ep, ProfileRegular)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		doGet(t, rt, server.URL)
+	}
+
+	s := findStats(pool.Stats(), "ep1", ProfileRegular)
+	if s == nil {
+		t.Fatal("expected stats for ep1/regular")
+	}
+	if s.Requests != 5 {
+		t.Errorf("expected 5 requests, got %d", s.Requests)
+	}
+	if s.Dials != 1 {
+		t.Errorf("expected a single dial, got %d", s.Dials)
+	}
+	if s.ReusedConns != 4 {
+		t.Errorf("expected 4 reused connections, got %d", s.ReusedConns)
+	}
+	if s.ActiveRequests != 0 {
+		t.Errorf("expected no active requests, got %d", s.ActiveRequests)
+	}
+	if s.OpenConns != 1 || s.IdleConns != 1 {
+		t.Errorf("expected 1 open idle connection, got open=%d idle=%d", s.OpenConns, s.IdleConns)
+	}
+}
+
+func TestPoolCountsTLSHandshakes(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	pool := NewPool(&config.Config{})
+	defer pool.Close()
+	ep := &config.EndpointConfig{Name: "tls", URL: server.URL}
+
+	rt, err := pool.Get(ep, ProfileStreaming)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	rt.(*instrumentedTransport).base.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	for i := 0; i < 3; i++ {
+		doGet(t, rt, server.URL)
+	}
+
+	s := findStats(pool.Stats(), "tls", ProfileStreaming)
+	if s == nil {
+		t.Fatal("expected stats for tls/streaming")
+	}
+	if s.TLSHandshakes != 1 {
+		t.Errorf("expected 1 TLS handshake, got %d", s.TLSHandshakes)
+	}
+}
+
+func TestPoolRebuildsOnChange(t *testing.T) {
+	cfg := &config.Config{}
+	pool := NewPool(cfg)
+	defer pool.Close()
+	ep := &config.EndpointConfig{Name: "ep1", URL: "http://a.example.com/v1"}
+
+	first, _ := pool.Get(ep, ProfileRegular)
+	same, _ := pool.Get(&config.EndpointConfig{Name: "ep1", URL: "http://a.example.com/other"}, ProfileRegular)
+	if first != same {
+		t.Error("path-only change should reuse the transport")
+	}
+
+	moved, _ := pool.Get(&config.EndpointConfig{Name: "ep1", URL: "http://b.example.com"}, ProfileRegular)
+	if moved == first {
+		t.Error("host change should rebuild the transport")
+	}
+
+	newCfg := &config.Config{Proxy: config.ProxyConfig{Enabled: true, Type: "http", Host: "127.0.0.1", Port: 3128}}
+	pool.UpdateConfig(newCfg)
+	proxied, _ := pool.Get(&config.EndpointConfig{Name: "ep1", URL: "http://b.example.com"}, ProfileRegular)
+	if proxied == moved {
+		t.Error("proxy change should rebuild the transport")
+	}
+
+	s := findStats(pool.Stats(), "ep1", ProfileRegular)
+	if s == nil || s.Rebuilds != 2 {
+		t.Errorf("expected 2 rebuilds, got %+v", s)
+	}
+
+	pool.Get(&config.EndpointConfig{Name: "ep2", URL: "http://c.example.com"}, ProfileRegular)
+	pool.Invalidate("ep1")
+	if s := findStats(pool.Stats(), "ep1", ProfileRegular); s != nil {
+		t.Errorf("invalidate should drop the endpoint stats, got %+v", s)
+	}
+	if findStats(pool.Stats(), "ep2", ProfileRegular) == nil {
+		t.Error("invalidate should keep other endpoints' stats")
+	}
+	fresh, _ := pool.Get(&config.EndpointConfig{Name: "ep1", URL: "http://b.example.com"}, ProfileRegular)
+	if fresh == proxied {
+		t.Error("invalidate should drop the cached transport")
+	}
+
+	pool.Retain([]string{"ep1"})
+	if len(pool.Stats()) != 1 {
+		t.Errorf("retain should drop stats of removed endpoints, got %+v", pool.Stats())
+	}
+
+	pool.Close()
+	if len(pool.Stats()) != 0 {
+		t.Error("close should drop all stats")
+	}
+}
+
+func TestPoolHonoursEndpointProxy(t *testing.T) {
+	var proxied atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	// 简易 HTTP 正向代理：记录经过的请求后转发到上游
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	defer proxyServer.Close()
+
+	// 全局代理指向不可用地址，直连覆盖的端点不应受影响
+	cfg := &config.Config{Proxy: config.ProxyConfig{Enabled: true, Type: "http", URL: "http://127.0.0.1:1"}}
+	pool := NewPool(cfg)
+	defer pool.Close()
+
+	direct := &config.EndpointConfig{Name: "direct", URL: upstream.URL, Proxy: &config.ProxyConfig{Enabled: false}}
+	rt, err := pool.Get(direct, ProfileRegular)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	doGet(t, rt, upstream.URL)
+	if proxied.Load() != 0 {
+		t.Errorf("direct endpoint should bypass proxies, proxied=%d", proxied.Load())
+	}
+
+	custom := &config.EndpointConfig{Name: "custom", URL: upstream.URL, Proxy: &config.ProxyConfig{Enabled: true, Type: "http", URL: proxyServer.URL}}
+	rt, err = pool.Get(custom, ProfileRegular)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	doGet(t, rt, upstream.URL)
+	if proxied.Load() != 1 {
+		t.Errorf("custom endpoint should use its own proxy, proxied=%d", proxied.Load())
+	}
+
+	// 端点代理配置变化时重建 Transport
+	custom.Proxy = &config.ProxyConfig{Enabled: false}
+	rebuilt, _ := pool.Get(custom, ProfileRegular)
+	if rebuilt == rt {
+		t.Error("endpoint proxy change should rebuild the transport")
+	}
+
+	// 全局代理变化不影响已覆盖代理的端点
+	before, _ := pool.Get(direct, ProfileRegular)
+	pool.UpdateConfig(&config.Config{})
+	after, _ := pool.Get(direct, ProfileRegular)
+	if before != after {
+		t.Error("global proxy change should not rebuild endpoints with an override")
+	}
+
+	if _, err := pool.Get(&config.EndpointConfig{Name: "bad", URL: upstream.URL, Proxy: &config.ProxyConfig{Enabled: true, Type: "ftp", Host: "h", Port: 1}}, ProfileRegular); err == nil {
+		t.Error("invalid endpoint proxy should fail")
+	}
+}
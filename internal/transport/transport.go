@@ -14,6 +14,26 @@ import (
 
 // CreateTransport creates an HTTP transport with optional proxy support
 func CreateTransport(cfg *config.Config) (*http.Transport, error) {
+	return CreateTransportWithProxy(cfg.Proxy)
+}
+
+// CreateEndpointTransport creates an HTTP transport honouring the endpoint's proxy override
+func CreateEndpointTransport(cfg *config.Config, ep *config.EndpointConfig) (*http.Transport, error) {
+	return CreateTransportWithProxy(EffectiveProxy(cfg, ep))
+}
+
+// EffectiveProxy returns the proxy settings that apply to an endpoint.
+// An endpoint without its own proxy settings inherits the global proxy;
+// an endpoint override with enabled=false forces a direct connection.
+func EffectiveProxy(cfg *config.Config, ep *config.EndpointConfig) config.ProxyConfig {
+	if ep != nil && ep.Proxy != nil {
+		return *ep.Proxy
+	}
+	return cfg.Proxy
+}
+
+// CreateTransportWithProxy creates an HTTP transport using the given proxy settings
+func CreateTransportWithProxy(proxyCfg config.ProxyConfig) (*http.Transport, error) {
 	transport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
@@ -27,45 +47,39 @@ func CreateTransport(cfg *config.Config) (*http.Transport, error) {
 	}
 
 	// If proxy is not enabled, return default transport
-	if !cfg.Proxy.Enabled {
+	if !proxyCfg.Enabled {
 		return transport, nil
 	}
 
 	// Validate proxy configuration
-	if cfg.Proxy.Type == "" {
-		return nil, fmt.Errorf("proxy type is required when proxy is enabled")
-	}
-	if cfg.Proxy.Type != "http" && cfg.Proxy.Type != "https" && cfg.Proxy.Type != "socks5" {
-		return nil, fmt.Errorf("unsupported proxy type: %s", cfg.Proxy.Type)
-	}
-	if cfg.Proxy.URL == "" && (cfg.Proxy.Host == "" || cfg.Proxy.Port == 0) {
-		return nil, fmt.Errorf("proxy URL or host:port must be specified when proxy is enabled")
+	if err := config.ValidateProxy(proxyCfg); err != nil {
+		return nil, err
 	}
 
-	switch cfg.Proxy.Type {
+	switch proxyCfg.Type {
 	case "http", "https":
-		return createHTTPProxyTransport(cfg, transport)
+		return createHTTPProxyTransport(proxyCfg, transport)
 	case "socks5":
-		return createSOCKS5ProxyTransport(cfg, transport)
+		return createSOCKS5ProxyTransport(proxyCfg, transport)
 	default:
-		return nil, fmt.Errorf("unsupported proxy type: %s", cfg.Proxy.Type)
+		return nil, fmt.Errorf("unsupported proxy type: %s", proxyCfg.Type)
 	}
 }
 
 // createHTTPProxyTransport creates transport with HTTP/HTTPS proxy
-func createHTTPProxyTransport(cfg *config.Config, transport *http.Transport) (*http.Transport, error) {
+func createHTTPProxyTransport(proxyCfg config.ProxyConfig, transport *http.Transport) (*http.Transport, error) {
 	var proxyURL *url.URL
 	var err error
 
-	if cfg.Proxy.URL != "" {
+	if proxyCfg.URL != "" {
 		// Use complete proxy URL
-		proxyURL, err = url.Parse(cfg.Proxy.URL)
+		proxyURL, err = url.Parse(proxyCfg.URL)
 		if err != nil {
 			return nil, fmt.Errorf("invalid proxy URL: %w", err)
 		}
 	} else {
 		// Construct proxy URL from host:port
-		proxyURLStr := fmt.Sprintf("%s://%s:%d", cfg.Proxy.Type, cfg.Proxy.Host, cfg.Proxy.Port)
+		proxyURLStr := fmt.Sprintf("%s://%s:%d", proxyCfg.Type, proxyCfg.Host, proxyCfg.Port)
 		proxyURL, err = url.Parse(proxyURLStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct proxy URL: %w", err)
@@ -73,11 +87,11 @@ func createHTTPProxyTransport(cfg *config.Config, transport *http.Transport) (*h
 	}
 
 	// Add authentication if provided
-	if cfg.Proxy.Username != "" {
-		if cfg.Proxy.Password != "" {
-			proxyURL.User = url.UserPassword(cfg.Proxy.Username, cfg.Proxy.Password)
+	if proxyCfg.Username != "" {
+		if proxyCfg.Password != "" {
+			proxyURL.User = url.UserPassword(proxyCfg.Username, proxyCfg.Password)
 		} else {
-			proxyURL.User = url.User(cfg.Proxy.Username)
+			proxyURL.User = url.User(proxyCfg.Username)
 		}
 	}
 
@@ -86,29 +100,29 @@ func createHTTPProxyTransport(cfg *config.Config, transport *http.Transport) (*h
 }
 
 // createSOCKS5ProxyTransport creates transport with SOCKS5 proxy
-func createSOCKS5ProxyTransport(cfg *config.Config, transport *http.Transport) (*http.Transport, error) {
+func createSOCKS5ProxyTransport(proxyCfg config.ProxyConfig, transport *http.Transport) (*http.Transport, error) {
 	var proxyAddr string
-	if cfg.Proxy.URL != "" {
+	if proxyCfg.URL != "" {
 		// Parse SOCKS5 URL
-		proxyURL, err := url.Parse(cfg.Proxy.URL)
+		proxyURL, err := url.Parse(proxyCfg.URL)
 		if err != nil {
 			return nil, fmt.Errorf("invalid SOCKS5 proxy URL: %w", err)
 		}
 		proxyAddr = proxyURL.Host
 	} else {
 		// Construct address from host:port
-		proxyAddr = fmt.Sprintf("%s:%d", cfg.Proxy.Host, cfg.Proxy.Port)
+		proxyAddr = fmt.Sprintf("%s:%d", proxyCfg.Host, proxyCfg.Port)
 	}
 
 	// Create SOCKS5 dialer
 	var dialer proxy.Dialer
 	var err error
 
-	if cfg.Proxy.Username != "" && cfg.Proxy.Password != "" {
+	if proxyCfg.Username != "" && proxyCfg.Password != "" {
 		// SOCKS5 with authentication
 		auth := &proxy.Auth{
-			User:     cfg.Proxy.Username,
-			Password: cfg.Proxy.Password,
+			User:     proxyCfg.Username,
+			Password: proxyCfg.Password,
 		}
 		dialer, err = proxy.SOCKS5("tcp", proxyAddr, auth, proxy.Direct)
 	} else {
@@ -129,21 +143,26 @@ func createSOCKS5ProxyTransport(cfg *config.Config, transport *http.Transport) (
 
 // GetProxyInfo returns human-readable proxy information
 func GetProxyInfo(cfg *config.Config) string {
-	if !cfg.Proxy.Enabled {
+	return ProxyInfo(cfg.Proxy)
+}
+
+// ProxyInfo returns human-readable information for the given proxy settings
+func ProxyInfo(proxyCfg config.ProxyConfig) string {
+	if !proxyCfg.Enabled {
 		return "No proxy"
 	}
 
 	var addr string
-	if cfg.Proxy.URL != "" {
-		addr = cfg.Proxy.URL
+	if proxyCfg.URL != "" {
+		addr = proxyCfg.URL
 	} else {
-		addr = fmt.Sprintf("%s:%d", cfg.Proxy.Host, cfg.Proxy.Port)
+		addr = fmt.Sprintf("%s:%d", proxyCfg.Host, proxyCfg.Port)
 	}
 
 	authInfo := ""
-	if cfg.Proxy.Username != "" {
+	if proxyCfg.Username != "" {
 		authInfo = " (with auth)"
 	}
 
-	return fmt.Sprintf("%s proxy: %s%s", cfg.Proxy.Type, addr, authInfo)
-}
\ No newline at end of file
+	return fmt.Sprintf("%s proxy: %s%s", proxyCfg.Type, addr, authInfo)
+}
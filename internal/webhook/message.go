@@ -0,0 +1,202 @@
+// Package webhook 将运维事件（端点不健康、组冷却、挂起超时、预算超限等）推送到外部 Webhook
+// 支持通用 JSON、Slack 兼容、飞书和钉钉机器人格式
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"cc-forwarder/internal/events"
+)
+
+// 负载格式
+const (
+	FormatJSON     = "json"     // 通用 JSON
+	FormatSlack    = "slack"    // Slack Incoming Webhook 兼容
+	FormatFeishu   = "feishu"   // 飞书自定义机器人
+	FormatDingTalk = "dingtalk" // 钉钉自定义机器人
+)
+
+// EventTest 测试发送使用的事件类型
+const EventTest events.EventType = "webhook_test"
+
+// eventTemplate 事件的默认标题和消息模板
+type eventTemplate struct {
+	title string
+	text  string
+}
+
+// eventTemplates 可订阅的事件类型及默认消息
+var eventTemplates = map[events.EventType]eventTemplate{
+	events.EventEndpointUnhealthy: {
+		title: "端点不健康",
+		text:  "端点 {{.Data.endpoint}} 健康检查失败（连续失败 {{.Data.consecutive_fails}} 次，最近检查 {{.Data.last_check}}）",
+	},
+	events.EventEndpointHealthy: {
+		title: "端点已恢复",
+		text:  "端点 {{.Data.endpoint}} 已恢复健康（响应时间 {{.Data.response_time}}）",
+	},
+	events.EventEndpointCircuitChanged: {
+		title: "端点熔断状态变化",
+		text:  "端点 {{.Data.endpoint}} 熔断器 {{.Data.from}} → {{.Data.to}}，原因：{{.Data.reason}}",
+	},
+	events.EventGroupCooldown: {
+		title: "组进入冷却",
+		text:  "组 {{.Data.group}} 进入冷却 {{.Data.cooldown_seconds}} 秒，预计 {{.Data.cooldown_until}} 恢复。原因：{{.Data.reason}}",
+	},
+	events.EventGroupStatusChanged: {
+		title: "组状态变化",
+		text:  "组 {{.Data.group}}：{{.Data.event}}",
+	},
+	events.EventRequestSuspendTimeout: {
+		title: "挂起请求超时",
+		text:  "请求 {{.Data.request_id}} 挂起等待 {{.Data.timeout_seconds}} 秒后超时（{{.Data.reason}}）",
+	},
+	events.EventBudgetSoftLimitExceeded: {
+		title: "预算超过软限额",
+		text:  `预算 {{.Data.name}}（{{.Data.scope}} {{.Data.target}}）本期已花费 ${{printf "%.4f" .Data.spent_usd}} / {{.Data.spent_tokens}} tokens`,
+	},
+	events.EventBudgetHardLimitExceeded: {
+		title: "预算超过硬限额",
+		text:  `预算 {{.Data.name}}（{{.Data.scope}} {{.Data.target}}）本期已花费 ${{printf "%.4f" .Data.spent_usd}} / {{.Data.spent_tokens}} tokens，新请求将被拒绝`,
+	},
+	EventTest: {
+		title: "Webhook 测试",
+		text:  "这是一条来自 CC-Forwarder 的测试通知（Webhook：{{.Data.webhook}}）",
+	},
+}
+
+// DefaultEventTypes 未指定事件类型时订阅的运维告警事件
+var DefaultEventTypes = []events.EventType{
+	events.EventEndpointUnhealthy,
+	events.EventGroupCooldown,
+	events.EventRequestSuspendTimeout,
+	events.EventBudgetSoftLimitExceeded,
+	events.EventBudgetHardLimitExceeded,
+}
+
+// SupportedEventTypes 可订阅的事件类型（不含测试事件）
+func SupportedEventTypes() []events.EventType {
+	return []events.EventType{
+		events.EventEndpointUnhealthy,
+		events.EventEndpointHealthy,
+		events.EventEndpointCircuitChanged,
+		events.EventGroupCooldown,
+		events.EventGroupStatusChanged,
+		events.EventRequestSuspendTimeout,
+		events.EventBudgetSoftLimitExceeded,
+		events.EventBudgetHardLimitExceeded,
+	}
+}
+
+// IsSupportedEventType 事件类型是否可订阅
+func IsSupportedEventType(eventType string) bool {
+	for _, t := range SupportedEventTypes() {
+		if string(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSupportedFormat 负载格式是否支持
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatJSON, FormatSlack, FormatFeishu, FormatDingTalk:
+		return true
+	}
+	return false
+}
+
+// Message 渲染后的通知消息
+type Message struct {
+	Event     string                 `json:"event"`
+	Title     string                 `json:"title"`
+	Text      string                 `json:"text"`
+	Source    string                 `json:"source"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// TemplateData 消息模板可用的字段
+// 自定义模板中 {{.Title}} 和 {{.Text}} 为事件的默认标题和消息，{{.Data.xxx}} 为事件数据
+type TemplateData struct {
+	Event  string
+	Title  string
+	Text   string
+	Source string
+	Time   string
+	Data   map[string]interface{}
+}
+
+// ValidateTemplate 校验自定义消息模板
+func ValidateTemplate(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if _, err := template.New("webhook").Parse(text); err != nil {
+		return fmt.Errorf("消息模板无效: %w", err)
+	}
+	return nil
+}
+
+// NewMessage 根据事件渲染通知消息
+// customTemplate 非空时替换默认消息，渲染失败时回退到默认消息
+func NewMessage(event events.Event, customTemplate string) Message {
+	tpl, ok := eventTemplates[event.Type]
+	if !ok {
+		tpl = eventTemplate{title: string(event.Type)}
+	}
+
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	data := TemplateData{
+		Event:  string(event.Type),
+		Title:  tpl.title,
+		Source: event.Source,
+		Time:   timestamp.Format("2006-01-02 15:04:05"),
+		Data:   event.Data,
+	}
+
+	text, err := render(tpl.text, data)
+	if err != nil {
+		text = fmt.Sprintf("%s：%v", tpl.title, event.Data)
+	}
+	data.Text = text
+
+	if strings.TrimSpace(customTemplate) != "" {
+		if custom, err := render(customTemplate, data); err == nil {
+			text = custom
+		}
+	}
+
+	return Message{
+		Event:     string(event.Type),
+		Title:     tpl.title,
+		Text:      text,
+		Source:    event.Source,
+		Timestamp: timestamp,
+		Data:      event.Data,
+	}
+}
+
+// render 渲染模板
+func render(text string, data TemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("webhook").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 默认投递参数
+const (
+	defaultTimeout   = 10 * time.Second
+	defaultBaseDelay = time.Second
+	defaultMaxDelay  = 30 * time.Second
+
+	// 响应体最多读取的字节数（用于解析飞书/钉钉的错误码和记录错误信息）
+	maxResponseBody = 4 << 10
+)
+
+// Target 投递目标
+type Target struct {
+	Name       string
+	URL        string
+	Format     string
+	Secret     string // 签名密钥：JSON 格式为 HMAC-SHA256 请求头，飞书/钉钉为机器人加签
+	MaxRetries int    // 失败后的最大重试次数
+}
+
+// Result 一次投递（含重试）的结果
+type Result struct {
+	Success    bool
+	Attempts   int
+	StatusCode int
+	Error      string
+	Payload    string
+	Duration   time.Duration
+}
+
+// Sender 发送 Webhook 请求，失败时按指数退避重试
+type Sender struct {
+	client    *http.Client
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	now       func() time.Time
+}
+
+// NewSender 创建 Webhook 发送器，timeout 为单次请求超时（<= 0 时使用默认值）
+func NewSender(timeout time.Duration) *Sender {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Sender{
+		client:    &http.Client{Timeout: timeout},
+		baseDelay: defaultBaseDelay,
+		maxDelay:  defaultMaxDelay,
+		now:       time.Now,
+	}
+}
+
+// SetBackoff 设置重试退避的基础延迟和最大延迟
+func (s *Sender) SetBackoff(baseDelay, maxDelay time.Duration) {
+	s.baseDelay = baseDelay
+	s.maxDelay = maxDelay
+}
+
+// Send 发送消息，网络错误、5xx 和 429 按指数退避重试，其他失败立即返回
+func (s *Sender) Send(ctx context.Context, target Target, msg Message) Result {
+	start := s.now()
+	result := Result{}
+
+	for attempt := 0; attempt <= target.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := s.backoff(attempt)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				result.Error = fmt.Sprintf("等待重试时取消: %v", ctx.Err())
+				result.Duration = s.now().Sub(start)
+				return result
+			}
+		}
+
+		result.Attempts++
+		statusCode, payload, retryable, err := s.sendOnce(ctx, target, msg)
+		result.StatusCode = statusCode
+		result.Payload = payload
+		if err == nil {
+			result.Success = true
+			result.Error = ""
+			break
+		}
+		result.Error = err.Error()
+		if !retryable || ctx.Err() != nil {
+			break
+		}
+	}
+
+	result.Duration = s.now().Sub(start)
+	return result
+}
+
+// backoff 第 attempt 次重试前的等待时间
+func (s *Sender) backoff(attempt int) time.Duration {
+	delay := s.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+	return delay
+}
+
+// sendOnce 发送一次请求，返回状态码、请求体、是否可重试和错误
+func (s *Sender) sendOnce(ctx context.Context, target Target, msg Message) (int, string, bool, error) {
+	body, err := BuildPayload(target, msg, s.now())
+	if err != nil {
+		return 0, "", false, err
+	}
+
+	requestURL := target.URL
+	if target.Format == FormatDingTalk && target.Secret != "" {
+		requestURL, err = signDingTalkURL(target.URL, target.Secret, s.now())
+		if err != nil {
+			return 0, string(body), false, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, string(body), false, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "cc-forwarder-webhook")
+	if target.Format == FormatJSON {
+		req.Header.Set("X-CCF-Event", msg.Event)
+		if target.Secret != "" {
+			mac := hmac.New(sha256.New, []byte(target.Secret))
+			mac.Write(body)
+			req.Header.Set("X-CCF-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, string(body), true, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return resp.StatusCode, string(body), retryable, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+	}
+
+	// 飞书和钉钉在 HTTP 200 响应体中返回业务错误码
+	if err := checkBotResponse(target.Format, respBody); err != nil {
+		return resp.StatusCode, string(body), false, err
+	}
+	return resp.StatusCode, string(body), false, nil
+}
+
+// BuildPayload 按目标格式构造请求体
+func BuildPayload(target Target, msg Message, now time.Time) ([]byte, error) {
+	var payload interface{}
+	switch target.Format {
+	case FormatSlack:
+		payload = map[string]interface{}{
+			"text": fmt.Sprintf("*%s*\n%s", msg.Title, msg.Text),
+		}
+	case FormatFeishu:
+		body := map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": msg.Title + "\n" + msg.Text},
+		}
+		if target.Secret != "" {
+			timestamp := strconv.FormatInt(now.Unix(), 10)
+			sign, err := feishuSign(timestamp, target.Secret)
+			if err != nil {
+				return nil, err
+			}
+			body["timestamp"] = timestamp
+			body["sign"] = sign
+		}
+		payload = body
+	case FormatDingTalk:
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]string{"content": msg.Title + "\n" + msg.Text},
+		}
+	case FormatJSON, "":
+		payload = struct {
+			Webhook string `json:"webhook"`
+			Message
+		}{Webhook: target.Name, Message: msg}
+	default:
+		return nil, fmt.Errorf("不支持的负载格式: %s", target.Format)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化负载失败: %w", err)
+	}
+	return body, nil
+}
+
+// feishuSign 飞书机器人签名：以 timestamp + "\n" + secret 为密钥对空串做 HMAC-SHA256
+func feishuSign(timestamp, secret string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	if _, err := mac.Write(nil); err != nil {
+		return "", fmt.Errorf("计算飞书签名失败: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signDingTalkURL 钉钉机器人加签：在 URL 上追加毫秒时间戳和 HMAC-SHA256 签名
+func signDingTalkURL(rawURL, secret string, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("解析 Webhook URL 失败: %w", err)
+	}
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// checkBotResponse 检查飞书/钉钉响应体中的错误码
+func checkBotResponse(format string, body []byte) error {
+	if format != FormatFeishu && format != FormatDingTalk {
+		return nil
+	}
+
+	var resp struct {
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+	if resp.Code != nil && *resp.Code != 0 {
+		return fmt.Errorf("机器人返回错误 %d: %s", *resp.Code, resp.Msg)
+	}
+	if resp.ErrCode != nil && *resp.ErrCode != 0 {
+		return fmt.Errorf("机器人返回错误 %d: %s", *resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
+
+// truncate 截断过长的字符串
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cc-forwarder/internal/events"
+)
+
+func unhealthyEvent() events.Event {
+	return events.Event{
+		Type:      events.EventEndpointUnhealthy,
+		Source:    "endpoint_manager",
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local),
+		Data: map[string]interface{}{
+			"endpoint":          "primary",
+			"consecutive_fails": 3,
+			"last_check":        "2025-01-01 12:00:00",
+		},
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := NewMessage(unhealthyEvent(), "")
+	if msg.Title != "端点不健康" || !strings.Contains(msg.Text, "端点 primary 健康检查失败（连续失败 3 次") {
+		t.Fatalf("默认消息不符: %+v", msg)
+	}
+
+	msg = NewMessage(unhealthyEvent(), "[生产] {{.Title}} {{.Data.endpoint}} @ {{.Time}}")
+	if msg.Text != "[生产] 端点不健康 primary @ 2025-01-01 12:00:00" {
+		t.Errorf("自定义模板渲染不符: %s", msg.Text)
+	}
+
+	// 自定义模板执行失败时回退到默认消息
+	msg = NewMessage(unhealthyEvent(), "{{.Missing}}")
+	if !strings.HasPrefix(msg.Text, "端点 primary") {
+		t.Errorf("模板失败时应回退到默认消息: %s", msg.Text)
+	}
+
+	budget := NewMessage(events.Event{
+		Type: events.EventBudgetHardLimitExceeded,
+		Data: map[string]interface{}{"name": "monthly", "scope": "global", "target": "", "spent_usd": 12.5, "spent_tokens": int64(1000)},
+	}, "")
+	if !strings.Contains(budget.Text, "$12.5000 / 1000 tokens，新请求将被拒绝") {
+		t.Errorf("预算消息不符: %s", budget.Text)
+	}
+
+	if err := ValidateTemplate("{{.Title"); err == nil {
+		t.Error("无效模板应校验失败")
+	}
+}
+
+func TestBuildPayload(t *testing.T) {
+	msg := NewMessage(unhealthyEvent(), "")
+	now := time.Unix(1700000000, 0)
+
+	body, err := BuildPayload(Target{Name: "ops", Format: FormatSlack}, msg, now)
+	if err != nil || !strings.Contains(string(body), `"text":"*端点不健康*\n端点 primary`) {
+		t.Errorf("Slack 负载不符: %s %v", body, err)
+	}
+
+	body, _ = BuildPayload(Target{Name: "ops", Format: FormatFeishu, Secret: "s"}, msg, now)
+	var feishu map[string]interface{}
+	json.Unmarshal(body, &feishu)
+	if feishu["msg_type"] != "text" || feishu["timestamp"] != "1700000000" || feishu["sign"] == "" {
+		t.Errorf("飞书负载不符: %s", body)
+	}
+
+	body, _ = BuildPayload(Target{Name: "ops", Format: FormatDingTalk}, msg, now)
+	if !strings.Contains(string(body), `"msgtype":"text"`) {
+		t.Errorf("钉钉负载不符: %s", body)
+	}
+
+	body, _ = BuildPayload(Target{Name: "ops", Format: FormatJSON}, msg, now)
+	var generic map[string]interface{}
+	json.Unmarshal(body, &generic)
+	if generic["webhook"] != "ops" || generic["event"] != "endpoint_unhealthy" || generic["data"] == nil {
+		t.Errorf("JSON 负载不符: %s", body)
+	}
+
+	if _, err := BuildPayload(Target{Format: "teams"}, msg, now); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
+
+func TestSender_RetriesAndSigns(t *testing.T) {
+	var calls atomic.Int32
+	var signature, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		signature = r.Header.Get("X-CCF-Signature")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	sender.SetBackoff(time.Millisecond, 5*time.Millisecond)
+	target := Target{Name: "ops", URL: server.URL, Format: FormatJSON, Secret: "secret", MaxRetries: 3}
+
+	result := sender.Send(context.Background(), target, NewMessage(unhealthyEvent(), ""))
+	if !result.Success || result.Attempts != 3 || result.StatusCode != http.StatusNoContent {
+		t.Fatalf("重试后应投递成功: %+v", result)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	if signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("签名不符: %s", signature)
+	}
+	if result.Payload != body {
+		t.Error("结果应记录请求体")
+	}
+}
+
+func TestSender_NonRetryableFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.Contains(r.URL.RawQuery, "sign=") {
+			w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	sender.SetBackoff(time.Millisecond, time.Millisecond)
+	msg := NewMessage(unhealthyEvent(), "")
+
+	result := sender.Send(context.Background(), Target{URL: server.URL, Format: FormatSlack, MaxRetries: 3}, msg)
+	if result.Success || result.Attempts != 1 || result.StatusCode != http.StatusBadRequest {
+		t.Errorf("4xx 不应重试: %+v", result)
+	}
+
+	result = sender.Send(context.Background(), Target{URL: server.URL + "?access_token=x", Format: FormatDingTalk, Secret: "s", MaxRetries: 3}, msg)
+	if result.Success || result.Attempts != 1 || !strings.Contains(result.Error, "310000") {
+		t.Errorf("钉钉业务错误应视为失败且不重试: %+v", result)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("请求次数不符: %d", calls.Load())
+	}
+}
@@ -36,6 +36,7 @@ var (
 var (
 	configPath  = flag.String("config", "config/config.yaml", "配置文件路径")
 	showVersion = flag.Bool("version", false, "显示版本信息")
+	headless    = flag.Bool("headless", false, "无界面服务器模式（不启动窗口，适用于无显示环境）")
 )
 
 // 嵌入前端资源
@@ -60,6 +61,11 @@ var (
 )
 
 func main() {
+	// 子命令：重放抓取的请求/响应（不启动应用）
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+
 	flag.Parse()
 
 	// 处理版本标志
@@ -75,6 +81,11 @@ func main() {
 	app := NewApp()
 	app.configPath = *configPath
 
+	// 无界面模式：直接启动代理服务，不进入 Wails 事件循环
+	if *headless {
+		os.Exit(runHeadless(app))
+	}
+
 	// 运行 Wails 应用
 	err := wails.Run(&options.App{
 		Title:     "",
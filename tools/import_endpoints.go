@@ -10,6 +10,7 @@ import (
 	"log"
 
 	"cc-forwarder/config"
+	"cc-forwarder/internal/service"
 	"cc-forwarder/internal/store"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -87,15 +88,22 @@ func main() {
 			ApiKey:              apiKey,
 			Headers:             ep.Headers,
 			Priority:            priority,
+			Weight:              ep.Weight,
 			FailoverEnabled:     failoverEnabled,
 			CooldownSeconds:     cooldownSeconds,
 			TimeoutSeconds:      timeoutSeconds,
+			MaxConcurrency:      ep.MaxConcurrency,
+			RPM:                 ep.RPM,
+			TPM:                 ep.TPM,
 			SupportsCountTokens: ep.SupportsCountTokens,
+			ModelMapping:        ep.ModelMapping,
+			RetryPolicy:         config.FormatRetryPolicy(ep.RetryPolicy),
 			CostMultiplier:      1.0,
 			InputCostMultiplier: 1.0,
 			OutputCostMultiplier: 1.0,
 			CacheCreationCostMultiplier: 1.0,
 			CacheReadCostMultiplier: 1.0,
+			Proxy:               service.EndpointProxyFromConfig(ep.Proxy),
 			Enabled:             false, // 默认不激活
 		}
 